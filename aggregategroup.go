@@ -0,0 +1,145 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/edamame"
+)
+
+// aggregateGroupAlias names the aggregate column of a grouped aggregate.
+const aggregateGroupAlias = "grub_aggregate"
+
+// AggregateGroupSpec describes the aggregate ExecAggregateGroup computes
+// for each distinct combination of the GroupBy columns.
+type AggregateGroupSpec struct {
+	GroupBy []string                // columns to group by; at least one
+	Func    edamame.AggregateFunc   // AggCount, AggSum, AggAvg, AggMin, or AggMax
+	Field   string                  // column; empty with AggCount counts rows
+	Where   []edamame.ConditionSpec // filters rows before grouping
+	Having  []edamame.HavingAggSpec // filters groups by an aggregate, such as count > :min
+	OrderBy []edamame.OrderBySpec   // orders groups by GroupBy columns
+
+	// ValueOrder orders groups by the aggregate, "asc" or "desc", ahead
+	// of OrderBy. Empty leaves the order to OrderBy.
+	ValueOrder string
+}
+
+// AggregateRow is one group of an ExecAggregateGroup result.
+type AggregateRow struct {
+	Group map[string]any // the GroupBy columns' values, as the driver returns them
+	Value float64        // the aggregate over the group's rows
+}
+
+// ExecAggregateGroup computes spec's aggregate per group in a single GROUP
+// BY statement, returning a row per group that passes Having, in the order
+// ValueOrder and OrderBy give. As with ExecAggregate, values are float64
+// and a NULL aggregate is 0. Text group values read as []byte by the
+// driver are returned as strings. params are validated like ExecQuery.
+//
+//	rows, err := db.ExecAggregateGroup(ctx, grub.AggregateGroupSpec{
+//	    GroupBy:    []string{"status"},
+//	    Func:       edamame.AggCount,
+//	    ValueOrder: "desc",
+//	}, nil)
+func (d *Database[T]) ExecAggregateGroup(ctx context.Context, spec AggregateGroupSpec, params map[string]any) ([]AggregateRow, error) {
+	rows, err := read(ctx, d, func(c conn[T]) ([]AggregateRow, error) {
+		return d.execAggregateGroup(ctx, c.db, spec, params)
+	})
+	if err != nil {
+		return nil, d.wrapErr("exec_aggregate_group", "", err)
+	}
+	return rows, nil
+}
+
+// ExecAggregateGroupTx is ExecAggregateGroup within a transaction.
+func (d *Database[T]) ExecAggregateGroupTx(ctx context.Context, tx *sqlx.Tx, spec AggregateGroupSpec, params map[string]any) ([]AggregateRow, error) {
+	if tx == nil {
+		return nil, d.wrapErr("exec_aggregate_group_tx", "", ErrNilTransaction)
+	}
+	rows, err := d.execAggregateGroup(ctx, tx, spec, params)
+	if err != nil {
+		return nil, d.wrapErr("exec_aggregate_group_tx", "", err)
+	}
+	return rows, nil
+}
+
+func (d *Database[T]) execAggregateGroup(ctx context.Context, execer sqlx.ExtContext, spec AggregateGroupSpec, params map[string]any) ([]AggregateRow, error) {
+	if len(spec.GroupBy) == 0 {
+		return nil, errors.New("no group by columns given")
+	}
+	exprs, err := aggregateExprs([]AggregateSpec{{Alias: aggregateGroupAlias, Func: spec.Func, Field: spec.Field}})
+	if err != nil {
+		return nil, err
+	}
+	direction := strings.ToUpper(spec.ValueOrder)
+	if direction != "" && direction != "ASC" && direction != "DESC" {
+		return nil, fmt.Errorf("invalid value order %q: want asc or desc", spec.ValueOrder)
+	}
+	stmt := edamame.NewQueryStatement("aggregate_group", "", edamame.QuerySpec{
+		Fields:      spec.GroupBy,
+		SelectExprs: exprs,
+		Where:       spec.Where,
+		GroupBy:     spec.GroupBy,
+		HavingAgg:   spec.Having,
+		OrderBy:     spec.OrderBy,
+	})
+	if err := checkParams(stmt, params, func() (string, error) { return d.executor.RenderQuery(stmt) }); err != nil {
+		return nil, err
+	}
+	query, err := d.executor.RenderQuery(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if direction != "" {
+		query = orderByValue(query, quoteIdent(d.dialect, aggregateGroupAlias)+" "+direction)
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	query, params, _ = expandIn(query, params)
+	if params == nil {
+		params = map[string]any{}
+	}
+	rows, err := sqlx.NamedQueryContext(callCtx, execer, query, params)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var result []AggregateRow
+	for rows.Next() {
+		groups := make([]any, len(spec.GroupBy))
+		var value *float64
+		dest := make([]any, 0, len(groups)+1)
+		for i := range groups {
+			dest = append(dest, &groups[i])
+		}
+		if err := rows.Scan(append(dest, &value)...); err != nil {
+			return nil, err
+		}
+		row := AggregateRow{Group: make(map[string]any, len(groups))}
+		for i, col := range spec.GroupBy {
+			if b, ok := groups[i].([]byte); ok {
+				groups[i] = string(b)
+			}
+			row.Group[col] = groups[i]
+		}
+		if value != nil {
+			row.Value = *value
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// orderByValue puts order first in query's ORDER BY clause, adding the
+// clause when query has none. The grouped query ends at its ORDER BY, so
+// the clause is the last in query.
+func orderByValue(query, order string) string {
+	if i := strings.LastIndex(query, " ORDER BY "); i >= 0 {
+		return query[:i] + " ORDER BY " + order + ", " + query[i+len(" ORDER BY "):]
+	}
+	return query + " ORDER BY " + order
+}
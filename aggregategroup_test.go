@@ -0,0 +1,132 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	astqlmariadb "github.com/zoobzio/astql/mariadb"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestDatabase_ExecAggregateGroup(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture, cfg := mockdb.NewWithConfig()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	perAge := AggregateGroupSpec{
+		GroupBy:    []string{"age"},
+		Func:       edamame.AggCount,
+		Where:      []edamame.ConditionSpec{{Field: "name", Operator: "!=", Param: "skip"}},
+		Having:     []edamame.HavingAggSpec{{Func: "count", Operator: ">=", Param: "min_count"}},
+		OrderBy:    []edamame.OrderBySpec{{Field: "age", Direction: "asc"}},
+		ValueOrder: "desc",
+	}
+	params := map[string]any{"skip": "nobody", "min_count": 2}
+
+	t.Run("sql", func(t *testing.T) {
+		capture.Reset()
+		if _, err := db.ExecAggregateGroup(ctx, perAge, params); err != nil {
+			t.Fatalf("ExecAggregateGroup failed: %v", err)
+		}
+		if len(capture.Queries) != 1 {
+			t.Fatalf("expected one query, got %d", len(capture.Queries))
+		}
+		want := `SELECT "age", COUNT(*) AS "grub_aggregate" FROM "test_users" WHERE "name" != ? ` +
+			`GROUP BY "age" HAVING COUNT(*) >= ? ORDER BY "grub_aggregate" DESC, "age" ASC`
+		if got := capture.Queries[0].Query; got != want {
+			t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+		}
+	})
+
+	t.Run("value order alone", func(t *testing.T) {
+		capture.Reset()
+		spec := AggregateGroupSpec{GroupBy: []string{"name", "age"}, Func: edamame.AggSum, Field: "age", ValueOrder: "asc"}
+		if _, err := db.ExecAggregateGroup(ctx, spec, nil); err != nil {
+			t.Fatalf("ExecAggregateGroup failed: %v", err)
+		}
+		query := capture.Queries[0].Query
+		for _, want := range []string{`SUM("age") AS "grub_aggregate"`, `GROUP BY "name", "age" ORDER BY "grub_aggregate" ASC`} {
+			if !strings.Contains(query, want) {
+				t.Errorf("expected %q in query, got: %s", want, query)
+			}
+		}
+	})
+
+	t.Run("rows", func(t *testing.T) {
+		cfg.SetRows([]string{"age", "grub_aggregate"},
+			[]driver.Value{int64(30), int64(3)},
+			[]driver.Value{[]byte("20"), []byte("2")},
+			[]driver.Value{nil, nil})
+		got, err := db.ExecAggregateGroup(ctx, perAge, params)
+		if err != nil {
+			t.Fatalf("ExecAggregateGroup failed: %v", err)
+		}
+		want := []AggregateRow{
+			{Group: map[string]any{"age": int64(30)}, Value: 3},
+			{Group: map[string]any{"age": "20"}, Value: 2},
+			{Group: map[string]any{"age": nil}, Value: 0},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("tx", func(t *testing.T) {
+		cfg.SetRows([]string{"age", "grub_aggregate"}, []driver.Value{int64(30), float64(2.5)})
+		tx, err := mockDB.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTxx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		got, err := db.ExecAggregateGroupTx(ctx, tx, perAge, params)
+		if err != nil || len(got) != 1 || got[0].Value != 2.5 {
+			t.Fatalf("expected one group of 2.5, got %v, %v", got, err)
+		}
+		if _, err := db.ExecAggregateGroupTx(ctx, nil, perAge, params); !errors.Is(err, ErrNilTransaction) {
+			t.Errorf("expected ErrNilTransaction, got %v", err)
+		}
+	})
+
+	t.Run("invalid specs", func(t *testing.T) {
+		cases := map[string]AggregateGroupSpec{
+			"no group by":   {Func: edamame.AggCount},
+			"missing field": {GroupBy: []string{"age"}, Func: edamame.AggSum},
+			"unknown func":  {GroupBy: []string{"age"}, Func: "MEDIAN", Field: "age"},
+			"unknown group": {GroupBy: []string{"aeg"}, Func: edamame.AggCount},
+			"value order":   {GroupBy: []string{"age"}, Func: edamame.AggCount, ValueOrder: "up"},
+		}
+		for name, spec := range cases {
+			capture.Reset()
+			_, err := db.ExecAggregateGroup(ctx, spec, nil)
+			var gErr *Error
+			if !errors.As(err, &gErr) || gErr.Op != "exec_aggregate_group" {
+				t.Errorf("%s: expected exec_aggregate_group error, got %v", name, err)
+			}
+			if len(capture.Queries) != 0 {
+				t.Errorf("%s: expected no query", name)
+			}
+		}
+		if _, err := db.ExecAggregateGroup(ctx, perAge, map[string]any{"skip": "x", "minCount": 2}); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("expected ErrInvalidParams, got %v", err)
+		}
+	})
+
+	t.Run("dialect quoting", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlmariadb.New())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		_, _ = db.ExecAggregateGroup(ctx, AggregateGroupSpec{GroupBy: []string{"age"}, Func: edamame.AggCount, ValueOrder: "desc"}, nil)
+		if want := "ORDER BY `grub_aggregate` DESC"; len(capture.Queries) != 1 || !strings.Contains(capture.Queries[0].Query, want) {
+			t.Errorf("expected %q, got %v", want, capture.Queries)
+		}
+	})
+}
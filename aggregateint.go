@@ -0,0 +1,154 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/edamame"
+)
+
+// CountAllInt counts all records in the table as an int64.
+func (d *Database[T]) CountAllInt(ctx context.Context) (int64, error) {
+	return d.execAggregateInt(ctx, "count_all_int", CountAll, nil)
+}
+
+// ExecAggregateInt is ExecAggregate returning an int64, for COUNT and for
+// SUM, MIN, or MAX over integer columns, whose results a float64 cannot
+// hold exactly past 2^53. The value is read as the driver returns it, so
+// integers, decimal strings such as MySQL's SUM, and floats with no
+// fractional part are all accepted; anything else, such as AVG of 2.5,
+// fails with an error naming the value and its driver type. An aggregate
+// over no rows returns 0. Cached like ExecQuery when WithQueryCache is set.
+func (d *Database[T]) ExecAggregateInt(ctx context.Context, stmt edamame.AggregateStatement, params map[string]any) (int64, error) {
+	return d.execAggregateInt(ctx, "exec_aggregate_int", stmt, params)
+}
+
+func (d *Database[T]) execAggregateInt(ctx context.Context, op string, stmt edamame.AggregateStatement, params map[string]any) (int64, error) {
+	render := func() (string, error) { return d.executor.RenderAggregate(stmt) }
+	if err := checkParams(stmt, params, render); err != nil {
+		return 0, d.wrapErr(op, "", err)
+	}
+	key, data, hit := d.cache.lookup(ctx, "aggregate_int", stmt.Name(), render, params)
+	if hit {
+		var cached int64
+		if json.Unmarshal(data, &cached) == nil {
+			return cached, nil
+		}
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	result, err := read(callCtx, d, func(c conn[T]) (int64, error) {
+		return d.runAggregateInt(callCtx, c.execer(nil), stmt, params)
+	})
+	if err != nil {
+		return 0, d.wrapErr(op, "", err)
+	}
+	d.cache.put(ctx, key, result)
+	return result, nil
+}
+
+// ExecAggregateIntTx is ExecAggregateInt within a transaction.
+func (d *Database[T]) ExecAggregateIntTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.AggregateStatement, params map[string]any) (int64, error) {
+	if tx == nil {
+		return 0, d.wrapErr("exec_aggregate_int_tx", "", ErrNilTransaction)
+	}
+	if err := checkParams(stmt, params, func() (string, error) { return d.executor.RenderAggregate(stmt) }); err != nil {
+		return 0, d.wrapErr("exec_aggregate_int_tx", "", err)
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	result, err := d.runAggregateInt(callCtx, tx, stmt, params)
+	if err != nil {
+		return 0, d.wrapErr("exec_aggregate_int_tx", "", err)
+	}
+	return result, nil
+}
+
+// runAggregateInt runs stmt on execer and converts its single value with
+// aggregateInt.
+func (d *Database[T]) runAggregateInt(ctx context.Context, execer sqlx.ExtContext, stmt edamame.AggregateStatement, params map[string]any) (int64, error) {
+	query, err := d.executor.RenderAggregate(stmt)
+	if err != nil {
+		return 0, err
+	}
+	query, args, _ := expandIn(query, params)
+	if args == nil {
+		args = map[string]any{}
+	}
+	rows, err := sqlx.NamedQueryContext(ctx, execer, query, args)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, errors.New("aggregate query returned no rows")
+	}
+	var value any
+	if err := rows.Scan(&value); err != nil {
+		return 0, err
+	}
+	return aggregateInt(value)
+}
+
+// aggregateInt converts an aggregate value as a driver returns it to an
+// int64. NULL is 0.
+func aggregateInt(value any) (int64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, fmt.Errorf("aggregate value %v (float64) is not an int64", v)
+		}
+		return int64(v), nil
+	case []byte:
+		n, err := parseAggregateInt(string(v))
+		if err != nil {
+			return 0, fmt.Errorf("aggregate value %q ([]byte) is not an int64", v)
+		}
+		return n, nil
+	case string:
+		n, err := parseAggregateInt(v)
+		if err != nil {
+			return 0, fmt.Errorf("aggregate value %q (string) is not an int64", v)
+		}
+		return n, nil
+	case driver.Valuer:
+		inner, err := v.Value()
+		if err != nil {
+			return 0, err
+		}
+		return aggregateInt(inner)
+	}
+	return 0, fmt.Errorf("aggregate value %v (%T) is not an int64", value, value)
+}
+
+// parseAggregateInt parses a decimal string such as "42" or "42.000",
+// rejecting any nonzero fractional part.
+func parseAggregateInt(s string) (int64, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	whole, frac, ok := strings.Cut(s, ".")
+	if !ok {
+		return 0, strconv.ErrSyntax
+	}
+	for _, c := range frac {
+		if c != '0' {
+			return 0, strconv.ErrSyntax
+		}
+	}
+	return strconv.ParseInt(whole, 10, 64)
+}
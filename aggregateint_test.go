@@ -0,0 +1,114 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestDatabase_ExecAggregateInt(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture, cfg := mockdb.NewWithConfig()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	sumAge := edamame.NewAggregateStatement("sum-age", "", edamame.AggSum, edamame.AggregateSpec{Field: "age"})
+
+	// Each value is one way a driver returns an aggregate.
+	values := map[string]struct {
+		value driver.Value
+		want  int64
+	}{
+		"int64":            {int64(3), 3},
+		"float64":          {float64(60), 60},
+		"bytes":            {[]byte("9007199254740993"), 9007199254740993},
+		"decimal bytes":    {[]byte("60.000"), 60},
+		"string":           {"-7", -7},
+		"null":             {nil, 0},
+		"past float53 sum": {int64(1<<53 + 1), 1<<53 + 1},
+	}
+	for name, tt := range values {
+		t.Run(name, func(t *testing.T) {
+			cfg.SetRows([]string{"sum"}, []driver.Value{tt.value})
+			got, err := db.ExecAggregateInt(ctx, sumAge, nil)
+			if err != nil {
+				t.Fatalf("ExecAggregateInt failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("not an integer", func(t *testing.T) {
+		for _, value := range []driver.Value{2.5, []byte("2.5"), "abc", true} {
+			cfg.SetRows([]string{"avg"}, []driver.Value{value})
+			_, err := db.ExecAggregateInt(ctx, sumAge, nil)
+			var gErr *Error
+			if !errors.As(err, &gErr) || gErr.Op != "exec_aggregate_int" {
+				t.Fatalf("%v: expected exec_aggregate_int error, got %v", value, err)
+			}
+			if !strings.Contains(err.Error(), "is not an int64") {
+				t.Errorf("%v: expected the error to name the value, got %v", value, err)
+			}
+		}
+	})
+
+	t.Run("count all", func(t *testing.T) {
+		capture.Reset()
+		cfg.SetRows([]string{"count"}, []driver.Value{[]byte("3")})
+		got, err := db.CountAllInt(ctx)
+		if err != nil || got != 3 {
+			t.Fatalf("expected 3, got %d, %v", got, err)
+		}
+		if len(capture.Queries) != 1 || !strings.Contains(capture.Queries[0].Query, "COUNT(*)") {
+			t.Errorf("expected one COUNT query, got %v", capture.Queries)
+		}
+	})
+
+	t.Run("tx", func(t *testing.T) {
+		cfg.SetRows([]string{"sum"}, []driver.Value{float64(12)})
+		tx, err := mockDB.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTxx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		got, err := db.ExecAggregateIntTx(ctx, tx, sumAge, nil)
+		if err != nil || got != 12 {
+			t.Fatalf("expected 12, got %d, %v", got, err)
+		}
+		if _, err := db.ExecAggregateIntTx(ctx, nil, sumAge, nil); !errors.Is(err, ErrNilTransaction) {
+			t.Errorf("expected ErrNilTransaction, got %v", err)
+		}
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		byAge := edamame.NewAggregateStatement("count-by-age", "", edamame.AggCount, edamame.AggregateSpec{
+			Where: []edamame.ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		})
+		capture.Reset()
+		if _, err := db.ExecAggregateInt(ctx, byAge, nil); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("expected ErrInvalidParams, got %v", err)
+		}
+		if len(capture.Queries) != 0 {
+			t.Errorf("expected no query, got %d", len(capture.Queries))
+		}
+	})
+}
+
+func TestAggregateInt_Valuer(t *testing.T) {
+	got, err := aggregateInt(sql.NullInt64{Int64: 4, Valid: true})
+	if err != nil || got != 4 {
+		t.Errorf("expected 4, got %d, %v", got, err)
+	}
+	if got, err := aggregateInt(sql.NullFloat64{}); err != nil || got != 0 {
+		t.Errorf("expected a NULL to be 0, got %d, %v", got, err)
+	}
+}
@@ -5,6 +5,7 @@ package grub
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,10 +21,20 @@ var (
 	ErrDuplicate            = shared.ErrDuplicate
 	ErrConflict             = shared.ErrConflict
 	ErrConstraint           = shared.ErrConstraint
+	ErrUniqueViolation      = shared.ErrUniqueViolation
+	ErrForeignKeyViolation  = shared.ErrForeignKeyViolation
+	ErrCheckViolation       = shared.ErrCheckViolation
+	ErrNotNullViolation     = shared.ErrNotNullViolation
+	ErrSerializationFailure = shared.ErrSerializationFailure
 	ErrInvalidKey           = shared.ErrInvalidKey
 	ErrReadOnly             = shared.ErrReadOnly
 	ErrTableExists          = shared.ErrTableExists
 	ErrTableNotFound        = shared.ErrTableNotFound
+	ErrStatementNotFound    = shared.ErrStatementNotFound
+	ErrInvalidParams        = shared.ErrInvalidParams
+	ErrKeyNotGenerated      = shared.ErrKeyNotGenerated
+	ErrNilTransaction       = shared.ErrNilTransaction
+	ErrRedacted             = shared.ErrRedacted
 	ErrTTLNotSupported      = shared.ErrTTLNotSupported
 	ErrDimensionMismatch    = shared.ErrDimensionMismatch
 	ErrInvalidVector        = shared.ErrInvalidVector
@@ -31,10 +42,44 @@ var (
 	ErrInvalidQuery         = shared.ErrInvalidQuery
 	ErrOperatorNotSupported = shared.ErrOperatorNotSupported
 	ErrFilterNotSupported   = shared.ErrFilterNotSupported
+	ErrScanLimitExceeded    = shared.ErrScanLimitExceeded
+	ErrSchemaMismatch       = shared.ErrSchemaMismatch
+	ErrNoMetadata           = shared.ErrNoMetadata
+	ErrUnsupported          = shared.ErrUnsupported
+	ErrDialectMismatch      = shared.ErrDialectMismatch
+	ErrCodecMismatch        = shared.ErrCodecMismatch
 	ErrNoPrimaryKey         = shared.ErrNoPrimaryKey
 	ErrMultiplePrimaryKeys  = shared.ErrMultiplePrimaryKeys
 )
 
+// Error is re-exported from internal/shared for the public API.
+// Use errors.As to extract the operation context from a returned error.
+type Error = shared.Error
+
+// ConstraintError is re-exported from internal/shared for the public API.
+// Use errors.As to extract the violated constraint or column name.
+type ConstraintError = shared.ConstraintError
+
+// KeyError is re-exported from internal/shared for the public API.
+// Use errors.As to extract the rejected key and the rule it broke.
+type KeyError = shared.KeyError
+
+// BatchQueryError is re-exported from internal/shared for the public API.
+// Use errors.As to find which query in an Index.SearchBatch call failed.
+type BatchQueryError = shared.BatchQueryError
+
+// BatchChunkError is re-exported from internal/shared for the public API.
+// Use errors.As to find which chunk of a split batch write failed.
+type BatchChunkError = shared.BatchChunkError
+
+// Error kinds identifying the facade that produced an Error.
+const (
+	KindDatabase = shared.KindDatabase
+	KindStore    = shared.KindStore
+	KindBucket   = shared.KindBucket
+	KindIndex    = shared.KindIndex
+)
+
 // StoreProvider defines raw key-value storage operations.
 // Implementations (redis, badger, bolt) satisfy this interface.
 type StoreProvider interface {
@@ -66,6 +111,54 @@ type StoreProvider interface {
 	SetBatch(ctx context.Context, items map[string][]byte, ttl time.Duration) error
 }
 
+// StoreMatcher is optionally implemented by a StoreProvider that can filter
+// keys by glob pattern on the server. Store.ListMatch uses it when available
+// and otherwise filters List results client-side.
+type StoreMatcher interface {
+	// ListMatch returns up to limit keys matching the Redis-style glob
+	// pattern, resuming from cursor ("" to start), and the cursor for the
+	// next page ("" when done). Limit of 0 means no limit.
+	ListMatch(ctx context.Context, pattern, cursor string, limit int) ([]string, string, error)
+}
+
+// StorePrefixDeleter is optionally implemented by a StoreProvider that can
+// delete every key under a prefix natively. Store.DeletePrefix uses it when
+// available and otherwise lists and deletes keys a page at a time.
+type StorePrefixDeleter interface {
+	// DeletePrefix removes every key under prefix and returns how many were
+	// removed. On error the count covers the keys removed before it, and
+	// calling again resumes with the keys that remain.
+	DeletePrefix(ctx context.Context, prefix string) (int64, error)
+}
+
+// BatchLimiter is optionally implemented by a StoreProvider or
+// VectorProvider whose backend caps how many items one batch call may carry.
+// Store.GetBatch and Store.SetBatch, and Index.UpsertBatch and
+// Index.DeleteBatch, split larger batches into sequential calls of at most
+// that many items.
+type BatchLimiter interface {
+	// MaxBatchSize returns the most items one batch call may carry, or 0
+	// for no limit.
+	MaxBatchSize() int
+}
+
+// StoreSwapper is optionally implemented by a StoreProvider that can replace
+// a value atomically only if it is unchanged. Store.Update requires it.
+type StoreSwapper interface {
+	// CompareAndSwap stores value at key with optional TTL only if the value
+	// currently stored is byte-for-byte equal to old, and reports whether it
+	// did. A missing key never matches. TTL of 0 means no expiration.
+	CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (bool, error)
+}
+
+// StoreTTLReader is optionally implemented by a StoreProvider that can
+// report how long a key has left to live. Store.TTL requires it.
+type StoreTTLReader interface {
+	// TTL returns the time left before key expires, or 0 if it does not
+	// expire. Returns ErrNotFound if the key does not exist.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+}
+
 // AtomicStore defines atom-based key-value storage operations.
 // atomic.Store[T] satisfies this interface, enabling type-agnostic access
 // for framework internals (field-level encryption, pipelines, etc.).
@@ -118,6 +211,10 @@ type AtomicDatabase interface {
 
 	// ExecSelect executes a select statement and returns an atom.
 	ExecSelect(ctx context.Context, stmt edamame.SelectStatement, params map[string]any) (*atom.Atom, error)
+
+	// ExecRaw runs arbitrary SQL with positional args and returns atoms.
+	// An escape hatch: the query is neither rendered nor validated.
+	ExecRaw(ctx context.Context, query string, args ...any) ([]*atom.Atom, error)
 }
 
 // BucketProvider defines raw blob storage operations.
@@ -142,6 +239,100 @@ type BucketProvider interface {
 	List(ctx context.Context, prefix string, limit int) ([]ObjectInfo, error)
 }
 
+// BucketInfoPutter is optionally implemented by a BucketProvider whose put
+// response carries object metadata. Bucket.PutInfo uses it when available
+// and otherwise reports only what the client already knows.
+type BucketInfoPutter interface {
+	// PutInfo stores data at key like Put and returns the stored object's
+	// info as reported by the backend (ETag, version, last-modified).
+	PutInfo(ctx context.Context, key string, data []byte, info *ObjectInfo) (*ObjectInfo, error)
+}
+
+// BucketExpirer is optionally implemented by a BucketProvider whose backend
+// can delete an object at a set time. Its Put and PutInfo honour
+// ObjectInfo.ExpiresAt. Bucket.Put returns ErrUnsupported for an object with
+// ExpiresAt on a provider that does not implement it, so the caller knows
+// to sweep expired objects itself.
+type BucketExpirer interface {
+	// ExpiryPrecision returns how long after ExpiresAt the backend may take
+	// to delete an object. Objects are never deleted early.
+	ExpiryPrecision() time.Duration
+}
+
+// BucketHeader is optionally implemented by a BucketProvider that can fetch
+// an object's info without its body. Bucket.FilterByMetadata uses it when
+// available and falls back to Get otherwise.
+type BucketHeader interface {
+	// Head returns the info for key, including its user metadata.
+	// Returns ErrNotFound if the key does not exist.
+	Head(ctx context.Context, key string) (*ObjectInfo, error)
+}
+
+// BucketMetadataLister is optionally implemented by a BucketProvider whose
+// listing can return each object's user metadata. Bucket.FilterByMetadata
+// uses it to avoid a round-trip per object.
+type BucketMetadataLister interface {
+	// ListMetadata behaves like List, with Metadata populated on every result.
+	ListMetadata(ctx context.Context, prefix string, limit int) ([]ObjectInfo, error)
+}
+
+// BucketPager is optionally implemented by a BucketProvider that can resume
+// a listing from a backend cursor. Bucket.ListPage uses it when available and
+// otherwise lists the whole prefix and pages it by key.
+type BucketPager interface {
+	// ListPage returns up to limit objects under prefix, starting after
+	// cursor, and the cursor of the next page. An empty cursor starts the
+	// listing; an empty next cursor means there are no more pages.
+	ListPage(ctx context.Context, prefix string, limit int, cursor string) ([]ObjectInfo, string, error)
+}
+
+// BucketPrefixDeleter is optionally implemented by a BucketProvider that can
+// delete objects in bulk. Bucket.DeletePrefix uses it when available and
+// otherwise lists and deletes objects a page at a time.
+type BucketPrefixDeleter interface {
+	// DeletePrefix removes every object under prefix and returns how many
+	// were removed. On error the count covers the objects removed before
+	// it, and calling again resumes with the objects that remain.
+	DeletePrefix(ctx context.Context, prefix string) (int64, error)
+}
+
+// Versioner is optionally implemented by a BucketProvider backed by a
+// versioned store. Bucket.ListVersions and Bucket.GetVersion return
+// ErrUnsupported for providers that do not implement it.
+type Versioner interface {
+	// ListVersions returns every stored version of key, newest first.
+	// Returns ErrNotFound if the key has no versions.
+	ListVersions(ctx context.Context, key string) ([]ObjectVersion, error)
+
+	// GetVersion retrieves a specific version of the blob at key.
+	// Returns ErrNotFound if the version does not exist.
+	GetVersion(ctx context.Context, key, versionID string) ([]byte, *ObjectInfo, error)
+}
+
+// ObjectVersion describes one stored version of a blob.
+type ObjectVersion = shared.ObjectVersion
+
+// MultipartUploader is optionally implemented by a BucketProvider that can
+// assemble an object from separately uploaded parts. Bucket.NewMultipartUpload
+// returns ErrUnsupported for providers that do not implement it.
+type MultipartUploader interface {
+	// CreateMultipartUpload starts an upload to key and returns its ID.
+	CreateMultipartUpload(ctx context.Context, key string, info *ObjectInfo) (string, error)
+
+	// UploadPart stores part number n (starting at 1) and returns its ETag.
+	// Parts may be uploaded concurrently and in any order.
+	UploadPart(ctx context.Context, key, uploadID string, n int, r io.Reader) (string, error)
+
+	// CompleteMultipartUpload assembles parts, sorted by number, into the object.
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) (*ObjectInfo, error)
+
+	// AbortMultipartUpload discards the upload and any stored parts.
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+}
+
+// CompletedPart identifies an uploaded part of a multipart upload.
+type CompletedPart = shared.CompletedPart
+
 // AtomicObject holds blob metadata with an atomized payload.
 // Used by AtomicBucket for type-agnostic access to blob data.
 type AtomicObject = shared.AtomicObject
@@ -187,8 +378,21 @@ const (
 	DistanceInnerProduct = shared.DistanceInnerProduct
 )
 
+// ScoreKind is re-exported from internal/shared for the public API.
+type ScoreKind = shared.ScoreKind
+
+// ScoreMeasure is re-exported from internal/shared for the public API.
+type ScoreMeasure = shared.ScoreMeasure
+
+// Score measure constants.
+const (
+	ScoreDistance        = shared.ScoreDistance
+	ScoreSquaredDistance = shared.ScoreSquaredDistance
+	ScoreSimilarity      = shared.ScoreSimilarity
+)
+
 // VectorProvider defines raw vector storage operations.
-// Implementations (pinecone, weaviate, milvus, qdrant) satisfy this interface.
+// Implementations (pinecone, weaviate, milvus, qdrant, redisvector, sqlitevector) satisfy this interface.
 type VectorProvider interface {
 	// Upsert stores or updates a vector with associated metadata.
 	// If the ID exists, the vector and metadata are replaced.
@@ -232,6 +436,89 @@ type VectorProvider interface {
 	Exists(ctx context.Context, id uuid.UUID) (bool, error)
 }
 
+// VectorBatchExister is optionally implemented by a VectorProvider that can
+// check many IDs in a single round-trip. Index.ExistsBatch uses it when
+// available and falls back to concurrent Exists calls otherwise.
+type VectorBatchExister interface {
+	// ExistsBatch reports which of ids exist.
+	// IDs missing from the result are treated as not existing.
+	ExistsBatch(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
+// VectorPager is optionally implemented by a VectorProvider that can skip
+// results server-side. Index.SearchPage and Index.QueryPage use it when
+// available and otherwise fetch offset+k results and drop the first offset.
+type VectorPager interface {
+	// SearchPage performs similarity search and returns up to k results
+	// after skipping the offset nearest neighbors.
+	SearchPage(ctx context.Context, vector []float32, k, offset int, filter map[string]any) ([]VectorResult, error)
+
+	// QueryPage is SearchPage with vecna filter support.
+	QueryPage(ctx context.Context, vector []float32, k, offset int, filter *vecna.Filter) ([]VectorResult, error)
+}
+
+// VectorRecommender is optionally implemented by a VectorProvider that can
+// search by stored vector IDs without the caller re-sending the vectors.
+// Index.Recommend and Index.RecommendMulti use it when available and
+// otherwise fetch the seed vectors and call Query.
+type VectorRecommender interface {
+	// Recommend returns up to k vectors closest to the seeds at ids,
+	// excluding the seeds. Returns ErrNotFound if a seed does not exist and
+	// ErrUnsupported if the provider cannot handle this many seeds, in which
+	// case the Index falls back to Get and Query.
+	Recommend(ctx context.Context, ids []uuid.UUID, k int, filter *vecna.Filter) ([]VectorResult, error)
+}
+
+// VectorBatchSearcher is optionally implemented by a VectorProvider that can
+// run several similarity searches in one round-trip. Index.SearchBatch falls
+// back to concurrent Query calls otherwise.
+type VectorBatchSearcher interface {
+	// SearchBatch returns the k nearest neighbors of each query, aligned
+	// positionally with queries. A query that fails should be reported as a
+	// *BatchQueryError carrying its position.
+	SearchBatch(ctx context.Context, queries [][]float32, k int, filter *vecna.Filter) ([][]VectorResult, error)
+}
+
+// VectorHybridSearcher is optionally implemented by a VectorProvider that can
+// blend dense vector similarity with sparse keyword (BM25) relevance in one
+// query. Index.HybridSearch returns ErrUnsupported without it.
+type VectorHybridSearcher interface {
+	// HybridSearch returns the k best matches for text and vector, highest
+	// score first. alpha weights the blend: 1 is pure vector search, 0 pure
+	// keyword search.
+	HybridSearch(ctx context.Context, text string, vector []float32, k int, alpha float32, filter *vecna.Filter) ([]VectorResult, error)
+}
+
+// VectorScorer is optionally implemented by a VectorProvider that reports
+// how the Score of its search results is measured. WithNormalizedScores
+// requires it.
+type VectorScorer interface {
+	// ScoreKind reports the metric and measure of search scores. The zero
+	// ScoreKind means unknown.
+	ScoreKind() ScoreKind
+}
+
+// VectorProjector is optionally implemented by a VectorProvider that can
+// return a subset of metadata properties from searches. An Index built with
+// WithSearchFields searches through the projected view, transferring only
+// those properties.
+type VectorProjector interface {
+	// Project returns a view of the provider whose searches and Filter return
+	// only the named top-level metadata properties. Get and writes behave as
+	// on the original provider.
+	Project(fields []string) VectorProvider
+}
+
+// VectorIDOrderer is optionally implemented by a VectorProvider that can
+// scan in ID order. An Index built with WithIDOrder lists and filters
+// through the ordered view.
+type VectorIDOrderer interface {
+	// OrderByID returns a view of the provider whose List and Filter return
+	// vectors in ascending ID order. Other methods behave as on the original
+	// provider.
+	OrderByID() VectorProvider
+}
+
 // AtomicVector holds vector data with an atomized metadata payload.
 // Used by AtomicIndex for type-agnostic access to vector data.
 type AtomicVector = shared.AtomicVector
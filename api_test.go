@@ -18,6 +18,10 @@ func TestErrorsReexported(t *testing.T) {
 		{"ErrDuplicate", ErrDuplicate, shared.ErrDuplicate},
 		{"ErrConflict", ErrConflict, shared.ErrConflict},
 		{"ErrConstraint", ErrConstraint, shared.ErrConstraint},
+		{"ErrUniqueViolation", ErrUniqueViolation, shared.ErrUniqueViolation},
+		{"ErrForeignKeyViolation", ErrForeignKeyViolation, shared.ErrForeignKeyViolation},
+		{"ErrCheckViolation", ErrCheckViolation, shared.ErrCheckViolation},
+		{"ErrNotNullViolation", ErrNotNullViolation, shared.ErrNotNullViolation},
 		{"ErrInvalidKey", ErrInvalidKey, shared.ErrInvalidKey},
 		{"ErrReadOnly", ErrReadOnly, shared.ErrReadOnly},
 		{"ErrTableExists", ErrTableExists, shared.ErrTableExists},
@@ -58,3 +62,82 @@ func TestErrorsNotNil(t *testing.T) {
 		}
 	}
 }
+
+func TestError_Is(t *testing.T) {
+	err := shared.WrapError(KindStore, "get", "", "user:1", ErrNotFound)
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("errors.Is(err, ErrNotFound) failed through Error wrapper")
+	}
+	if errors.Is(err, ErrDuplicate) {
+		t.Error("errors.Is(err, ErrDuplicate) unexpectedly matched")
+	}
+}
+
+func TestError_As(t *testing.T) {
+	err := shared.WrapError(KindDatabase, "delete", "users", "42", ErrNotFound)
+
+	var gerr *Error
+	if !errors.As(err, &gerr) {
+		t.Fatal("errors.As failed to extract *Error")
+	}
+	if gerr.Op != "delete" {
+		t.Errorf("Op: expected 'delete', got %q", gerr.Op)
+	}
+	if gerr.Kind != KindDatabase {
+		t.Errorf("Kind: expected %q, got %q", KindDatabase, gerr.Kind)
+	}
+	if gerr.Table != "users" {
+		t.Errorf("Table: expected 'users', got %q", gerr.Table)
+	}
+	if gerr.Key != "42" {
+		t.Errorf("Key: expected '42', got %q", gerr.Key)
+	}
+}
+
+func TestError_Message(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "with table and key",
+			err:      shared.WrapError(KindDatabase, "get", "users", "42", ErrNotFound),
+			expected: `grub: database get users key="42": grub: record not found`,
+		},
+		{
+			name:     "key only",
+			err:      shared.WrapError(KindStore, "set", "", "session:abc", errors.New("connection refused")),
+			expected: `grub: store set key="session:abc": connection refused`,
+		},
+		{
+			name:     "op only",
+			err:      shared.WrapError(KindIndex, "upsert_batch", "", "", errors.New("timeout")),
+			expected: `grub: index upsert_batch: timeout`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWrapError_Nil(t *testing.T) {
+	if err := shared.WrapError(KindStore, "get", "", "k", nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
+
+func TestWrapError_NoDoubleWrap(t *testing.T) {
+	inner := shared.WrapError(KindStore, "get", "", "k", ErrNotFound)
+	outer := shared.WrapError(KindDatabase, "set", "t", "other", inner)
+
+	if outer != inner {
+		t.Error("expected already-wrapped error to be returned unchanged")
+	}
+}
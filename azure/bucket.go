@@ -4,12 +4,15 @@ package azure
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/zoobzio/grub"
 )
@@ -69,7 +72,15 @@ func (p *Provider) Get(ctx context.Context, key string) ([]byte, *grub.ObjectInf
 
 // Put stores data at key with associated metadata.
 func (p *Provider) Put(ctx context.Context, key string, data []byte, info *grub.ObjectInfo) error {
+	_, err := p.PutInfo(ctx, key, data, info)
+	return err
+}
+
+// PutInfo stores data at key and returns the ETag, version ID, and
+// last-modified time from the upload response.
+func (p *Provider) PutInfo(ctx context.Context, key string, data []byte, info *grub.ObjectInfo) (*grub.ObjectInfo, error) {
 	opts := &azblob.UploadBufferOptions{}
+	result := &grub.ObjectInfo{Key: key, Size: int64(len(data))}
 	if info != nil {
 		if info.ContentType != "" {
 			opts.HTTPHeaders = &blob.HTTPHeaders{
@@ -79,9 +90,47 @@ func (p *Provider) Put(ctx context.Context, key string, data []byte, info *grub.
 		if len(info.Metadata) > 0 {
 			opts.Metadata = mapToPtrMap(info.Metadata)
 		}
+		result.ContentType = info.ContentType
+		result.Metadata = info.Metadata
 	}
-	_, err := p.client.UploadBuffer(ctx, p.containerName, key, data, opts)
-	return err
+	resp, err := p.client.UploadBuffer(ctx, p.containerName, key, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	if info != nil && !info.ExpiresAt.IsZero() {
+		if err := p.setExpiry(ctx, key, info.ExpiresAt); err != nil {
+			return nil, err
+		}
+		result.ExpiresAt = info.ExpiresAt
+	}
+	if resp.ETag != nil {
+		result.ETag = string(*resp.ETag)
+	}
+	if resp.VersionID != nil {
+		result.VersionID = *resp.VersionID
+	}
+	if resp.LastModified != nil {
+		result.LastModified = *resp.LastModified
+	}
+	return result, nil
+}
+
+// ExpiryPrecision implements grub.BucketExpirer. The service deletes a
+// blob once its expiry time passes.
+func (p *Provider) ExpiryPrecision() time.Duration {
+	return 0
+}
+
+// setExpiry has the service delete the blob at key at time at. Blob expiry
+// needs a hierarchical namespace account; if it fails the blob is deleted
+// rather than left to live forever.
+func (p *Provider) setExpiry(ctx context.Context, key string, at time.Time) error {
+	blobClient := p.client.ServiceClient().NewContainerClient(p.containerName).NewBlockBlobClient(key)
+	if _, err := blobClient.SetExpiry(ctx, blockblob.ExpiryTypeAbsolute(at), nil); err != nil {
+		_, _ = p.client.DeleteBlob(ctx, p.containerName, key, nil)
+		return fmt.Errorf("azure: set blob expiry: %w", err)
+	}
+	return nil
 }
 
 // Delete removes the blob at key.
@@ -113,39 +162,69 @@ func (p *Provider) Exists(ctx context.Context, key string) (bool, error) {
 	return true, nil
 }
 
+// Head returns the info for key, including its user metadata, without
+// downloading the body.
+func (p *Provider) Head(ctx context.Context, key string) (*grub.ObjectInfo, error) {
+	blobClient := p.client.ServiceClient().NewContainerClient(p.containerName).NewBlobClient(key)
+	props, err := blobClient.GetProperties(ctx, nil)
+	if err != nil {
+		var respErr *azcore.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == 404 {
+			return nil, grub.ErrNotFound
+		}
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return nil, grub.ErrNotFound
+		}
+		return nil, err
+	}
+	info := &grub.ObjectInfo{Key: key}
+	if props.ContentType != nil {
+		info.ContentType = *props.ContentType
+	}
+	if props.ContentLength != nil {
+		info.Size = *props.ContentLength
+	}
+	if props.ETag != nil {
+		info.ETag = string(*props.ETag)
+	}
+	if props.VersionID != nil {
+		info.VersionID = *props.VersionID
+	}
+	if props.LastModified != nil {
+		info.LastModified = *props.LastModified
+	}
+	if len(props.Metadata) > 0 {
+		info.Metadata = ptrMapToMap(props.Metadata)
+	}
+	return info, nil
+}
+
+// ListMetadata returns the same results as List, which requests user
+// metadata with each listing page.
+func (p *Provider) ListMetadata(ctx context.Context, prefix string, limit int) ([]grub.ObjectInfo, error) {
+	return p.List(ctx, prefix, limit)
+}
+
 // List returns object info for keys matching the given prefix.
 func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.ObjectInfo, error) {
 	var results []grub.ObjectInfo
 
 	pager := p.client.NewListBlobsFlatPager(p.containerName, &container.ListBlobsFlatOptions{
-		Prefix: &prefix,
+		Prefix:  &prefix,
+		Include: container.ListBlobsInclude{Metadata: true},
 	})
 
 	for pager.More() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		page, err := pager.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, b := range page.Segment.BlobItems {
-			info := grub.ObjectInfo{
-				Key: *b.Name,
-			}
-			if b.Properties != nil {
-				if b.Properties.ContentType != nil {
-					info.ContentType = *b.Properties.ContentType
-				}
-				if b.Properties.ContentLength != nil {
-					info.Size = *b.Properties.ContentLength
-				}
-				if b.Properties.ETag != nil {
-					info.ETag = string(*b.Properties.ETag)
-				}
-			}
-			if b.Metadata != nil {
-				info.Metadata = ptrMapToMap(b.Metadata)
-			}
-			results = append(results, info)
+			results = append(results, objectInfo(b))
 
 			if limit > 0 && len(results) >= limit {
 				return results, nil
@@ -156,6 +235,62 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.O
 	return results, nil
 }
 
+// ListPage returns up to limit objects under prefix, resuming from an Azure
+// continuation marker. Listed blobs carry all ObjectInfo fields. A limit of
+// 0 or less means 1000.
+func (p *Provider) ListPage(ctx context.Context, prefix string, limit int, cursor string) ([]grub.ObjectInfo, string, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	maxResults := int32(min(limit, 5000)) //nolint:gosec // bounded by the service maximum
+	opts := &container.ListBlobsFlatOptions{
+		Prefix:     &prefix,
+		Include:    container.ListBlobsInclude{Metadata: true},
+		MaxResults: &maxResults,
+	}
+	if cursor != "" {
+		opts.Marker = &cursor
+	}
+	page, err := p.client.NewListBlobsFlatPager(p.containerName, opts).NextPage(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	results := make([]grub.ObjectInfo, 0, len(page.Segment.BlobItems))
+	for _, b := range page.Segment.BlobItems {
+		results = append(results, objectInfo(b))
+	}
+	var next string
+	if page.NextMarker != nil {
+		next = *page.NextMarker
+	}
+	return results, next, nil
+}
+
+// objectInfo converts a listed blob.
+func objectInfo(b *container.BlobItem) grub.ObjectInfo {
+	info := grub.ObjectInfo{
+		Key: *b.Name,
+	}
+	if b.Properties != nil {
+		if b.Properties.ContentType != nil {
+			info.ContentType = *b.Properties.ContentType
+		}
+		if b.Properties.ContentLength != nil {
+			info.Size = *b.Properties.ContentLength
+		}
+		if b.Properties.ETag != nil {
+			info.ETag = string(*b.Properties.ETag)
+		}
+		if b.Properties.LastModified != nil {
+			info.LastModified = *b.Properties.LastModified
+		}
+	}
+	if b.Metadata != nil {
+		info.Metadata = ptrMapToMap(b.Metadata)
+	}
+	return info
+}
+
 // ptrMapToMap converts map[string]*string to map[string]string.
 func ptrMapToMap(m map[string]*string) map[string]string {
 	if m == nil {
@@ -178,6 +178,29 @@ func TestProvider_Put(t *testing.T) {
 	})
 }
 
+func TestProvider_PutInfo(t *testing.T) {
+	clearContainer(t)
+	ctx := context.Background()
+
+	data := []byte("receipt content")
+	info, err := testProvider.PutInfo(ctx, "put-info", data, &grub.ObjectInfo{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("PutInfo failed: %v", err)
+	}
+	if info.Key != "put-info" {
+		t.Errorf("unexpected key: %q", info.Key)
+	}
+	if info.Size != int64(len(data)) {
+		t.Errorf("expected size %d, got %d", len(data), info.Size)
+	}
+	if info.ETag == "" {
+		t.Error("expected ETag to be reported")
+	}
+	if info.LastModified.IsZero() {
+		t.Error("expected LastModified to be reported")
+	}
+}
+
 func TestProvider_Delete(t *testing.T) {
 	clearContainer(t)
 	ctx := context.Background()
@@ -2,6 +2,7 @@
 package badger
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"time"
@@ -65,6 +66,107 @@ func (p *Provider) Delete(_ context.Context, key string) error {
 	})
 }
 
+// deleteBatchSize bounds the keys DeletePrefix removes per transaction,
+// keeping well under Badger's transaction size limit.
+const deleteBatchSize = 1000
+
+// DeletePrefix removes every key under prefix, in transactions of up to
+// 1000 deletes. Cancellation is checked between transactions.
+func (p *Provider) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var deleted int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		var n int
+		err := p.db.Update(func(txn *badger.Txn) error {
+			opts := badger.DefaultIteratorOptions
+			opts.PrefetchValues = false
+			opts.Prefix = []byte(prefix)
+			it := txn.NewIterator(opts)
+			var keys [][]byte
+			for it.Rewind(); it.Valid() && len(keys) < deleteBatchSize; it.Next() {
+				keys = append(keys, it.Item().KeyCopy(nil))
+			}
+			it.Close()
+			for _, key := range keys {
+				if err := txn.Delete(key); err != nil {
+					return err
+				}
+			}
+			n = len(keys)
+			return nil
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += int64(n)
+		if n < deleteBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// CompareAndSwap stores value at key only if the stored value equals old,
+// in one transaction. A concurrent transaction that writes key first makes
+// the commit fail with a conflict, which is reported as no swap.
+func (p *Provider) CompareAndSwap(_ context.Context, key string, old, value []byte, ttl time.Duration) (bool, error) {
+	var swapped bool
+	err := p.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		current, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(current, old) {
+			return nil
+		}
+		entry := badger.NewEntry([]byte(key), value)
+		if ttl > 0 {
+			entry = entry.WithTTL(ttl)
+		}
+		swapped = true
+		return txn.SetEntry(entry)
+	})
+	if errors.Is(err, badger.ErrConflict) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return swapped, nil
+}
+
+// TTL returns the time left before key expires, or 0 if it does not
+// expire. Badger tracks expiry to the second, so a key about to expire
+// reports a millisecond rather than 0, which would read as no expiry.
+func (p *Provider) TTL(_ context.Context, key string) (time.Duration, error) {
+	var ttl time.Duration
+	err := p.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return grub.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if expires := item.ExpiresAt(); expires > 0 {
+			ttl = max(time.Until(time.Unix(int64(expires), 0)), time.Millisecond)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return ttl, nil
+}
+
 // Exists checks whether a key exists.
 func (p *Provider) Exists(_ context.Context, key string) (bool, error) {
 	var exists bool
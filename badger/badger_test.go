@@ -3,6 +3,7 @@ package badger
 import (
 	"context"
 	"errors"
+	"strconv"
 	"testing"
 	"time"
 
@@ -249,6 +250,77 @@ func TestProvider_List_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestProvider_DeletePrefix(t *testing.T) {
+	db := setupTestDB(t)
+	provider := New(db)
+	ctx := context.Background()
+
+	items := make(map[string][]byte, 2501)
+	for i := 0; i < 2500; i++ {
+		items["tmp/"+strconv.Itoa(i)] = []byte("v")
+	}
+	items["tmq/keep"] = []byte("v")
+	if err := provider.SetBatch(ctx, items, 0); err != nil {
+		t.Fatalf("SetBatch failed: %v", err)
+	}
+
+	n, err := provider.DeletePrefix(ctx, "tmp/")
+	if err != nil {
+		t.Fatalf("DeletePrefix failed: %v", err)
+	}
+	if n != 2500 {
+		t.Errorf("expected 2500 deleted, got %d", n)
+	}
+	keys, _ := provider.List(ctx, "", 0)
+	if len(keys) != 1 || keys[0] != "tmq/keep" {
+		t.Errorf("expected only tmq/keep left, got %v", keys)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := provider.DeletePrefix(cancelled, "tmq/"); err == nil {
+		t.Error("expected context cancellation error")
+	}
+}
+
+func TestProvider_CompareAndSwap(t *testing.T) {
+	db := setupTestDB(t)
+	provider := New(db)
+	ctx := context.Background()
+	_ = provider.Set(ctx, "key", []byte("v1"), 0)
+
+	if ok, err := provider.CompareAndSwap(ctx, "key", []byte("stale"), []byte("v2"), 0); err != nil || ok {
+		t.Errorf("expected no swap for a stale value, got %v, %v", ok, err)
+	}
+	if ok, err := provider.CompareAndSwap(ctx, "key", []byte("v1"), []byte("v2"), time.Hour); err != nil || !ok {
+		t.Errorf("expected a swap, got %v, %v", ok, err)
+	}
+	if data, _ := provider.Get(ctx, "key"); string(data) != "v2" {
+		t.Errorf("expected v2, got %q", data)
+	}
+	if ok, err := provider.CompareAndSwap(ctx, "missing", nil, []byte("v"), 0); err != nil || ok {
+		t.Errorf("expected no swap for a missing key, got %v, %v", ok, err)
+	}
+}
+
+func TestProvider_TTL(t *testing.T) {
+	db := setupTestDB(t)
+	provider := New(db)
+	ctx := context.Background()
+	_ = provider.Set(ctx, "forever", []byte("v"), 0)
+	_ = provider.Set(ctx, "expiring", []byte("v"), time.Hour)
+
+	if ttl, err := provider.TTL(ctx, "forever"); err != nil || ttl != 0 {
+		t.Errorf("expected no expiry, got %v, %v", ttl, err)
+	}
+	if ttl, err := provider.TTL(ctx, "expiring"); err != nil || ttl <= 0 || ttl > time.Hour {
+		t.Errorf("expected up to an hour, got %v, %v", ttl, err)
+	}
+	if _, err := provider.TTL(ctx, "missing"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
 func TestProvider_GetBatch(t *testing.T) {
 	db := setupTestDB(t)
 	provider := New(db)
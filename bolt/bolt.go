@@ -2,6 +2,7 @@
 package bolt
 
 import (
+	"bytes"
 	"context"
 	"time"
 
@@ -75,6 +76,89 @@ func (p *Provider) Delete(_ context.Context, key string) error {
 	})
 }
 
+// deleteBatchSize bounds the keys DeletePrefix removes per transaction, so
+// a large prefix does not hold the write lock for the whole deletion.
+const deleteBatchSize = 1000
+
+// DeletePrefix removes every key under prefix, in transactions of up to
+// 1000 deletes. Cancellation is checked between transactions.
+func (p *Provider) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var deleted int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		var n int
+		err := p.db.Update(func(tx *bbolt.Tx) error {
+			b := tx.Bucket(p.bucket)
+			if b == nil {
+				return nil
+			}
+			prefixBytes := []byte(prefix)
+			var keys [][]byte
+			c := b.Cursor()
+			for k, _ := c.Seek(prefixBytes); k != nil && hasPrefix(k, prefixBytes) && len(keys) < deleteBatchSize; k, _ = c.Next() {
+				keys = append(keys, append([]byte(nil), k...))
+			}
+			// Deleting through the cursor while iterating can skip keys.
+			for _, key := range keys {
+				if err := b.Delete(key); err != nil {
+					return err
+				}
+			}
+			n = len(keys)
+			return nil
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += int64(n)
+		if n < deleteBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// CompareAndSwap stores value at key only if the stored value equals old.
+// Bolt serialises write transactions, so the compare and the put cannot
+// interleave with another writer.
+// Returns ErrTTLNotSupported if TTL > 0, as BoltDB does not support expiration.
+func (p *Provider) CompareAndSwap(_ context.Context, key string, old, value []byte, ttl time.Duration) (bool, error) {
+	if ttl > 0 {
+		return false, grub.ErrTTLNotSupported
+	}
+	var swapped bool
+	err := p.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(p.bucket)
+		if b == nil {
+			return nil
+		}
+		current := b.Get([]byte(key))
+		if current == nil || !bytes.Equal(current, old) {
+			return nil
+		}
+		swapped = true
+		return b.Put([]byte(key), value)
+	})
+	if err != nil {
+		return false, err
+	}
+	return swapped, nil
+}
+
+// TTL returns 0 for any key that exists, as BoltDB does not support
+// expiration.
+func (p *Provider) TTL(ctx context.Context, key string) (time.Duration, error) {
+	exists, err := p.Exists(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, grub.ErrNotFound
+	}
+	return 0, nil
+}
+
 // Exists checks whether a key exists.
 func (p *Provider) Exists(_ context.Context, key string) (bool, error) {
 	var exists bool
@@ -2,10 +2,18 @@ package grub
 
 import (
 	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/zoobzio/atom"
 	atomic "github.com/zoobzio/grub/internal/atomic"
+	"github.com/zoobzio/grub/internal/shared"
 )
 
 // Bucket provides type-safe blob storage operations for T.
@@ -13,39 +21,85 @@ import (
 type Bucket[T any] struct {
 	provider   BucketProvider
 	codec      Codec
+	timeout    time.Duration
+	stamps     *timestamps
+	redact     *redaction
+	sniff      bool
+	keys       *KeyPolicy
 	atomic     *atomic.Bucket[T]
-	atomicOnce sync.Once
+	atomicOnce *sync.Once
+	readOnly   bool
 }
 
 // NewBucket creates a Bucket for type T backed by the given provider.
 // Uses JSON codec by default.
-func NewBucket[T any](provider BucketProvider) *Bucket[T] {
-	return &Bucket[T]{
-		provider: provider,
-		codec:    JSONCodec{},
-	}
+func NewBucket[T any](provider BucketProvider, opts ...Option) *Bucket[T] {
+	return NewBucketWithCodec[T](provider, JSONCodec{}, opts...)
 }
 
 // NewBucketWithCodec creates a Bucket for type T with a custom codec.
-func NewBucketWithCodec[T any](provider BucketProvider, codec Codec) *Bucket[T] {
+func NewBucketWithCodec[T any](provider BucketProvider, codec Codec, opts ...Option) *Bucket[T] {
+	o := applyOptions(opts)
 	return &Bucket[T]{
-		provider: provider,
-		codec:    codec,
+		provider:   provider,
+		codec:      codec,
+		timeout:    o.timeout,
+		stamps:     newTimestamps[T](o),
+		redact:     newRedaction[T](o),
+		sniff:      o.sniffContentType,
+		keys:       o.keyPolicy,
+		atomicOnce: new(sync.Once),
+	}
+}
+
+// NewBucketChecked creates a Bucket like NewBucket, but validates that T can
+// be atomized before returning. Unsupported field types are reported here,
+// naming the field, rather than as a panic from Atomic().
+func NewBucketChecked[T any](provider BucketProvider, opts ...Option) (*Bucket[T], error) {
+	return NewBucketCheckedWithCodec[T](provider, JSONCodec{}, opts...)
+}
+
+// NewBucketCheckedWithCodec creates a Bucket like NewBucketWithCodec,
+// validating T at construction time as NewBucketChecked does.
+func NewBucketCheckedWithCodec[T any](provider BucketProvider, codec Codec, opts ...Option) (*Bucket[T], error) {
+	atomizer, err := useAtomizer[T]()
+	if err != nil {
+		return nil, err
 	}
+	b := NewBucketWithCodec[T](provider, codec, opts...)
+	b.atomicOnce.Do(func() {
+		b.atomic = atomic.NewBucket[T](provider, codec, atomizer.Spec()).Redact(b.redact.fields())
+	})
+	return b, nil
 }
 
 // Get retrieves the object at key.
 func (b *Bucket[T]) Get(ctx context.Context, key string) (*Object[T], error) {
-	data, info, err := b.provider.Get(ctx, key)
+	key, err := b.keys.apply(key)
 	if err != nil {
-		return nil, err
+		return nil, shared.WrapError(KindBucket, "get", "", key, err)
 	}
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	data, info, err := b.provider.Get(callCtx, key)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "get", "", key, err)
+	}
+	return b.decodeObject(ctx, data, info)
+}
+
+// decodeObject decodes a provider payload into an Object[T], redacts it, and
+// runs AfterLoad, unless ctx asks for the payload as stored.
+func (b *Bucket[T]) decodeObject(ctx context.Context, data []byte, info *ObjectInfo) (*Object[T], error) {
 	var payload T
 	if err := b.codec.Decode(data, &payload); err != nil {
 		return nil, err
 	}
-	if err := callAfterLoad(ctx, &payload); err != nil {
-		return nil, err
+	if !isRawLoad(ctx) {
+		b.redact.apply(&payload)
+		if err := callAfterLoad(ctx, &payload); err != nil {
+			return nil, err
+		}
 	}
 	return &Object[T]{
 		Key:         info.Key,
@@ -53,51 +107,379 @@ func (b *Bucket[T]) Get(ctx context.Context, key string) (*Object[T], error) {
 		Size:        info.Size,
 		ETag:        info.ETag,
 		Metadata:    info.Metadata,
+		ExpiresAt:   info.ExpiresAt,
 		Data:        payload,
 	}, nil
 }
 
+// ListVersions returns every stored version of key, newest first, including
+// delete markers. Returns ErrUnsupported if the provider does not implement
+// Versioner.
+func (b *Bucket[T]) ListVersions(ctx context.Context, key string) ([]ObjectVersion, error) {
+	key, err := b.keys.apply(key)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "list_versions", "", key, err)
+	}
+	v, ok := b.provider.(Versioner)
+	if !ok {
+		return nil, shared.WrapError(KindBucket, "list_versions", "", key, ErrUnsupported)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	versions, err := v.ListVersions(callCtx, key)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "list_versions", "", key, err)
+	}
+	return versions, nil
+}
+
+// GetVersion retrieves a specific version of the object at key.
+// Returns ErrUnsupported if the provider does not implement Versioner.
+func (b *Bucket[T]) GetVersion(ctx context.Context, key, versionID string) (*Object[T], error) {
+	key, err := b.keys.apply(key)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "get_version", "", key, err)
+	}
+	v, ok := b.provider.(Versioner)
+	if !ok {
+		return nil, shared.WrapError(KindBucket, "get_version", "", key, ErrUnsupported)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	data, info, err := v.GetVersion(callCtx, key, versionID)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "get_version", "", key, err)
+	}
+	return b.decodeObject(ctx, data, info)
+}
+
 // Put stores an object at key.
 func (b *Bucket[T]) Put(ctx context.Context, obj *Object[T]) error {
+	_, err := b.PutInfo(ctx, obj)
+	return err
+}
+
+// PutInfo stores an object at key and returns its info without a follow-up
+// read. Providers implementing BucketInfoPutter report the backend's ETag,
+// version, and last-modified time; others return the key, content type,
+// metadata, and encoded size.
+func (b *Bucket[T]) PutInfo(ctx context.Context, obj *Object[T]) (*ObjectInfo, error) {
+	if b.readOnly {
+		return nil, shared.WrapError(KindBucket, "put", "", obj.Key, ErrReadOnly)
+	}
+	key, err := b.keys.apply(obj.Key)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "put", "", key, err)
+	}
+	if _, ok := b.provider.(BucketExpirer); !ok && !obj.ExpiresAt.IsZero() {
+		return nil, shared.WrapError(KindBucket, "put", "", key, ErrUnsupported)
+	}
+	b.stamps.stamp(&obj.Data)
 	if err := callBeforeSave(ctx, &obj.Data); err != nil {
-		return err
+		return nil, err
+	}
+	if err := b.redact.check(&obj.Data); err != nil {
+		return nil, shared.WrapError(KindBucket, "put", "", key, err)
 	}
 	data, err := b.codec.Encode(obj.Data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	info := &ObjectInfo{
-		Key:         obj.Key,
-		ContentType: obj.ContentType,
+		Key:         key,
+		ContentType: b.contentType(key, obj.ContentType, data),
 		Size:        int64(len(data)),
 		Metadata:    obj.Metadata,
+		ExpiresAt:   obj.ExpiresAt,
 	}
-	if err := b.provider.Put(ctx, obj.Key, data, info); err != nil {
-		return err
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	result := info
+	if p, ok := b.provider.(BucketInfoPutter); ok {
+		result, err = p.PutInfo(callCtx, key, data, info)
+	} else {
+		err = b.provider.Put(callCtx, key, data, info)
 	}
-	return callAfterSave(ctx, &obj.Data)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "put", "", key, err)
+	}
+	if result == nil {
+		result = info
+	}
+	if err := callAfterSave(ctx, &obj.Data); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // Delete removes the object at key.
 func (b *Bucket[T]) Delete(ctx context.Context, key string) error {
+	if b.readOnly {
+		return shared.WrapError(KindBucket, "delete", "", key, ErrReadOnly)
+	}
+	key, err := b.keys.apply(key)
+	if err != nil {
+		return shared.WrapError(KindBucket, "delete", "", key, err)
+	}
 	if err := callBeforeDelete[T](ctx); err != nil {
 		return err
 	}
-	if err := b.provider.Delete(ctx, key); err != nil {
-		return err
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	if err := b.provider.Delete(callCtx, key); err != nil {
+		return shared.WrapError(KindBucket, "delete", "", key, err)
 	}
 	return callAfterDelete[T](ctx)
 }
 
+// DeletePrefix removes every object under prefix and returns how many were
+// removed. A provider implementing BucketPrefixDeleter deletes in bulk (S3
+// and MinIO in batches of 1000); otherwise objects are listed and deleted a
+// page at a time. BeforeDelete and AfterDelete hooks do not run: they take
+// no key, so there is nothing to call them with per object. The empty
+// prefix is rejected with ErrInvalidKey rather than emptying the bucket.
+//
+// On error the count covers the objects removed so far; calling again
+// resumes with the objects that remain. The default timeout applies to each
+// provider call rather than the whole deletion.
+func (b *Bucket[T]) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	if b.readOnly {
+		return 0, shared.WrapError(KindBucket, "delete_prefix", "", prefix, ErrReadOnly)
+	}
+	if err := checkDeletePrefix(prefix); err != nil {
+		return 0, shared.WrapError(KindBucket, "delete_prefix", "", prefix, err)
+	}
+	prefix, err := b.keys.applyPrefix(prefix)
+	if err != nil {
+		return 0, shared.WrapError(KindBucket, "delete_prefix", "", prefix, err)
+	}
+	if deleter, ok := b.provider.(BucketPrefixDeleter); ok {
+		n, err := deleter.DeletePrefix(ctx, prefix)
+		if err != nil {
+			return n, shared.WrapError(KindBucket, "delete_prefix", "", prefix, err)
+		}
+		return n, nil
+	}
+	list := func(ctx context.Context, prefix string, limit int) ([]string, error) {
+		infos, err := b.provider.List(ctx, prefix, limit)
+		keys := make([]string, len(infos))
+		for n, info := range infos {
+			keys[n] = info.Key
+		}
+		return keys, err
+	}
+	return deletePaged(ctx, KindBucket, prefix, b.timeout, list, b.provider.Delete)
+}
+
 // Exists checks whether a key exists.
 func (b *Bucket[T]) Exists(ctx context.Context, key string) (bool, error) {
-	return b.provider.Exists(ctx, key)
+	key, err := b.keys.apply(key)
+	if err != nil {
+		return false, shared.WrapError(KindBucket, "exists", "", key, err)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	exists, err := b.provider.Exists(callCtx, key)
+	if err != nil {
+		return false, shared.WrapError(KindBucket, "exists", "", key, err)
+	}
+	return exists, nil
 }
 
 // List returns object info for keys matching the given prefix.
 // Limit of 0 means no limit.
 func (b *Bucket[T]) List(ctx context.Context, prefix string, limit int) ([]ObjectInfo, error) {
-	return b.provider.List(ctx, prefix, limit)
+	prefix, err := b.keys.applyPrefix(prefix)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "list", "", prefix, err)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	infos, err := b.provider.List(callCtx, prefix, limit)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "list", "", prefix, err)
+	}
+	return infos, nil
+}
+
+// defaultPageSize is the ListPage limit used when limit is not positive.
+const defaultPageSize = 1000
+
+// ListPage returns up to limit objects under prefix, starting after cursor,
+// and the cursor of the next page. Pass an empty cursor for the first page;
+// an empty next cursor means the listing is done. A limit of 0 or less means
+// 1000.
+//
+// Cursors are opaque and only valid for the same provider and prefix. A
+// provider implementing BucketPager resumes from a backend cursor;
+// otherwise each page lists the whole prefix and returns the keys sorted
+// after cursor, which costs a full listing per page.
+func (b *Bucket[T]) ListPage(ctx context.Context, prefix string, limit int, cursor string) ([]ObjectInfo, string, error) {
+	prefix, err := b.keys.applyPrefix(prefix)
+	if err != nil {
+		return nil, "", shared.WrapError(KindBucket, "list_page", "", prefix, err)
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	if pager, ok := b.provider.(BucketPager); ok {
+		infos, next, err := pager.ListPage(callCtx, prefix, limit, cursor)
+		if err != nil {
+			return nil, "", shared.WrapError(KindBucket, "list_page", "", prefix, err)
+		}
+		return infos, next, nil
+	}
+	infos, err := b.provider.List(callCtx, prefix, 0)
+	if err != nil {
+		return nil, "", shared.WrapError(KindBucket, "list_page", "", prefix, err)
+	}
+	page, next := pageByKey(infos, limit, cursor)
+	return page, next, nil
+}
+
+// pageByKey sorts infos by key and returns up to limit of those whose keys
+// sort after cursor, with the last returned key as the next cursor when
+// more remain.
+func pageByKey(infos []ObjectInfo, limit int, cursor string) ([]ObjectInfo, string) {
+	slices.SortFunc(infos, func(a, b ObjectInfo) int { return strings.Compare(a.Key, b.Key) })
+	start, found := slices.BinarySearchFunc(infos, cursor, func(info ObjectInfo, key string) int {
+		return strings.Compare(info.Key, key)
+	})
+	if found {
+		start++
+	}
+	end := min(start+limit, len(infos))
+	if end == len(infos) {
+		return infos[start:end], ""
+	}
+	return infos[start:end], infos[end-1].Key
+}
+
+// FilterByMetadata returns info for objects under prefix whose metadata
+// contains every key/value pair in match, in List order. Metadata keys are
+// compared case-insensitively, since backends normalise their case; values
+// must match exactly. Limit of 0 means no limit.
+//
+// Object stores cannot filter by metadata server-side, so this lists every
+// key under prefix. A provider implementing BucketMetadataLister returns
+// metadata with the listing; otherwise each listed object costs one more
+// round-trip, a Head through BucketHeader when available or a full Get.
+// Keep prefixes narrow.
+func (b *Bucket[T]) FilterByMetadata(ctx context.Context, prefix string, match map[string]string, limit int) ([]ObjectInfo, error) {
+	prefix, err := b.keys.applyPrefix(prefix)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "filter_by_metadata", "", prefix, err)
+	}
+	if lister, ok := b.provider.(BucketMetadataLister); ok {
+		callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+		defer cancel()
+		infos, err := lister.ListMetadata(callCtx, prefix, 0)
+		if err != nil {
+			return nil, shared.WrapError(KindBucket, "filter_by_metadata", "", prefix, err)
+		}
+		results := make([]ObjectInfo, 0)
+		for _, info := range infos {
+			if limit > 0 && len(results) >= limit {
+				break
+			}
+			if metadataMatches(info.Metadata, match) {
+				results = append(results, info)
+			}
+		}
+		return results, nil
+	}
+
+	listCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	infos, err := b.provider.List(listCtx, prefix, 0)
+	cancel()
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "filter_by_metadata", "", prefix, err)
+	}
+	results := make([]ObjectInfo, 0)
+	for _, listed := range infos {
+		if limit > 0 && len(results) >= limit {
+			break
+		}
+		info, err := b.head(ctx, listed.Key)
+		if errors.Is(err, ErrNotFound) {
+			continue // deleted since listing
+		}
+		if err != nil {
+			return nil, shared.WrapError(KindBucket, "filter_by_metadata", "", listed.Key, err)
+		}
+		if metadataMatches(info.Metadata, match) {
+			results = append(results, *info)
+		}
+	}
+	return results, nil
+}
+
+// contentType returns ct unless it is empty and sniffing is enabled, in
+// which case the type is inferred from key's extension or detected from data.
+func (b *Bucket[T]) contentType(key, ct string, data []byte) string {
+	if ct != "" || !b.sniff {
+		return ct
+	}
+	if t := mime.TypeByExtension(path.Ext(key)); t != "" {
+		return t
+	}
+	if len(data) == 0 {
+		return ""
+	}
+	return http.DetectContentType(data)
+}
+
+// head fetches the info for key, through BucketHeader when the provider
+// implements it and a full Get otherwise.
+func (b *Bucket[T]) head(ctx context.Context, key string) (*ObjectInfo, error) {
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	if header, ok := b.provider.(BucketHeader); ok {
+		return header.Head(callCtx, key)
+	}
+	_, info, err := b.provider.Get(callCtx, key)
+	return info, err
+}
+
+// metadataMatches reports whether metadata holds every pair in match,
+// comparing keys case-insensitively.
+func metadataMatches(metadata, match map[string]string) bool {
+	for k, want := range match {
+		got, ok := metadata[k]
+		if !ok {
+			for mk, mv := range metadata {
+				if strings.EqualFold(mk, k) {
+					got, ok = mv, true
+					break
+				}
+			}
+		}
+		if !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Provider returns the BucketProvider passed to NewBucket, so callers can
+// type-assert to the concrete provider for features grub does not cover.
+func (b *Bucket[T]) Provider() BucketProvider {
+	return b.provider
+}
+
+// ReadOnly returns a view of this bucket whose writes (Put, PutInfo,
+// Delete, DeletePrefix, and starting or resuming a multipart upload) fail
+// with ErrReadOnly, naming the operation, before hooks run or the provider
+// is reached. Reads behave as on b and share its provider, codec, and
+// options. The view's Atomic rejects writes the same way.
+func (b *Bucket[T]) ReadOnly() *Bucket[T] {
+	view := *b
+	view.readOnly = true
+	view.atomic = nil
+	view.atomicOnce = new(sync.Once)
+	return &view
 }
 
 // Atomic returns an atom-based view of this bucket.
@@ -110,7 +492,17 @@ func (b *Bucket[T]) Atomic() *atomic.Bucket[T] {
 		if err != nil {
 			panic("grub: invalid type for atomization: " + err.Error())
 		}
-		b.atomic = atomic.NewBucket[T](b.provider, b.codec, atomizer.Spec())
+		b.atomic = atomic.NewBucket[T](b.provider, b.codec, atomizer.Spec()).Redact(b.redact.fields()).Keys(b.keys.apply)
+		if b.readOnly {
+			b.atomic = b.atomic.ReadOnly()
+		}
 	})
 	return b.atomic
 }
+
+// Spec returns the atom spec of T: its type name and each field's name,
+// Go type, kind, and struct tags, as carried by Atomic's atoms. Panics if
+// T is not atomizable, like Atomic.
+func (b *Bucket[T]) Spec() atom.Spec {
+	return b.Atomic().Spec()
+}
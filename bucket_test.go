@@ -3,7 +3,10 @@ package grub
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 // mockBucketProvider implements BucketProvider for testing.
@@ -138,6 +141,35 @@ func TestNewBucketWithCodec(t *testing.T) {
 	}
 }
 
+func TestNewBucketChecked(t *testing.T) {
+	t.Run("valid type", func(t *testing.T) {
+		bucket, err := NewBucketChecked[testPayload](newMockBucketProvider())
+		if err != nil {
+			t.Fatalf("NewBucketChecked failed: %v", err)
+		}
+		if bucket.atomic == nil || bucket.Atomic() != bucket.atomic {
+			t.Error("atomic view should be built eagerly")
+		}
+	})
+
+	t.Run("unsupported field type", func(t *testing.T) {
+		_, err := NewBucketChecked[unsupportedMetadata](newMockBucketProvider())
+		if err == nil || !strings.Contains(err.Error(), `"Callback"`) {
+			t.Fatalf("expected error naming the field, got %v", err)
+		}
+	})
+
+	t.Run("with codec", func(t *testing.T) {
+		bucket, err := NewBucketCheckedWithCodec[testPayload](newMockBucketProvider(), GobCodec{})
+		if err != nil {
+			t.Fatalf("NewBucketCheckedWithCodec failed: %v", err)
+		}
+		if _, ok := bucket.codec.(GobCodec); !ok {
+			t.Errorf("expected GobCodec, got %T", bucket.codec)
+		}
+	})
+}
+
 func TestBucket_Get(t *testing.T) {
 	provider := newMockBucketProvider()
 	bucket := NewBucket[testPayload](provider)
@@ -256,6 +288,170 @@ func TestBucket_Put(t *testing.T) {
 	})
 }
 
+func TestBucket_ContentTypeSniffing(t *testing.T) {
+	ctx := context.Background()
+	data := testPayload{Field1: "hello"}
+
+	for _, tc := range []struct {
+		name, key, contentType, want string
+		opts                         []Option
+	}{
+		{"disabled", "a.png", "", "", nil},
+		{"extension", "a.png", "", "image/png", []Option{WithContentTypeSniffing()}},
+		{"pdf extension", "docs/report.pdf", "", "application/pdf", []Option{WithContentTypeSniffing()}},
+		{"detected", "no-extension", "", "text/plain; charset=utf-8", []Option{WithContentTypeSniffing()}},
+		{"explicit wins", "a.png", "application/json", "application/json", []Option{WithContentTypeSniffing()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := newMockBucketProvider()
+			bucket := NewBucket[testPayload](provider, tc.opts...)
+			info, err := bucket.PutInfo(ctx, &Object[testPayload]{Key: tc.key, ContentType: tc.contentType, Data: data})
+			if err != nil {
+				t.Fatalf("PutInfo failed: %v", err)
+			}
+			if got := provider.info[tc.key].ContentType; got != tc.want {
+				t.Errorf("expected stored content type %q, got %q", tc.want, got)
+			}
+			if info.ContentType != tc.want {
+				t.Errorf("expected returned content type %q, got %q", tc.want, info.ContentType)
+			}
+		})
+	}
+}
+
+// infoPutterProvider reports backend metadata from PutInfo.
+type infoPutterProvider struct {
+	*mockBucketProvider
+	putInfoCalls int
+}
+
+func (p *infoPutterProvider) PutInfo(ctx context.Context, key string, data []byte, info *ObjectInfo) (*ObjectInfo, error) {
+	p.putInfoCalls++
+	if err := p.Put(ctx, key, data, info); err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{
+		Key:          key,
+		Size:         int64(len(data)),
+		ETag:         `"abc123"`,
+		VersionID:    "v1",
+		LastModified: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, nil
+}
+
+func TestBucket_PutInfo(t *testing.T) {
+	ctx := context.Background()
+	obj := &Object[testPayload]{
+		Key:         "receipt",
+		ContentType: "application/json",
+		Metadata:    map[string]string{"k": "v"},
+		Data:        testPayload{Field1: "hello", Field2: 1},
+	}
+
+	t.Run("provider reports info", func(t *testing.T) {
+		provider := &infoPutterProvider{mockBucketProvider: newMockBucketProvider()}
+		info, err := NewBucket[testPayload](provider).PutInfo(ctx, obj)
+		if err != nil {
+			t.Fatalf("PutInfo failed: %v", err)
+		}
+		if provider.putInfoCalls != 1 {
+			t.Errorf("expected provider PutInfo to be used, got %d calls", provider.putInfoCalls)
+		}
+		if info.ETag != `"abc123"` || info.VersionID != "v1" || info.LastModified.IsZero() {
+			t.Errorf("expected backend info, got %+v", info)
+		}
+	})
+
+	t.Run("fallback populates key and size", func(t *testing.T) {
+		provider := newMockBucketProvider()
+		info, err := NewBucket[testPayload](provider).PutInfo(ctx, obj)
+		if err != nil {
+			t.Fatalf("PutInfo failed: %v", err)
+		}
+		if info.Key != "receipt" || info.ContentType != "application/json" || info.Metadata["k"] != "v" {
+			t.Errorf("unexpected info %+v", info)
+		}
+		if info.Size != int64(len(provider.data["receipt"])) {
+			t.Errorf("expected size %d, got %d", len(provider.data["receipt"]), info.Size)
+		}
+		if info.ETag != "" || info.VersionID != "" {
+			t.Errorf("expected no backend fields, got %+v", info)
+		}
+	})
+
+	t.Run("Put uses PutInfo", func(t *testing.T) {
+		provider := &infoPutterProvider{mockBucketProvider: newMockBucketProvider()}
+		if err := NewBucket[testPayload](provider).Put(ctx, obj); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if provider.putInfoCalls != 1 {
+			t.Errorf("expected Put to route through PutInfo, got %d calls", provider.putInfoCalls)
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		provider := &infoPutterProvider{mockBucketProvider: newMockBucketProvider()}
+		provider.putErr = errors.New("put error")
+		info, err := NewBucket[testPayload](provider).PutInfo(ctx, obj)
+		if !errors.Is(err, provider.putErr) || info != nil {
+			t.Errorf("expected wrapped provider error and nil info, got %v, %v", info, err)
+		}
+	})
+}
+
+// expiringProvider is a mock provider that can expire objects.
+type expiringProvider struct {
+	*mockBucketProvider
+}
+
+func (*expiringProvider) ExpiryPrecision() time.Duration { return 24 * time.Hour }
+
+func TestBucket_PutExpiresAt(t *testing.T) {
+	ctx := context.Background()
+	expires := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	obj := &Object[testPayload]{Key: "render", ExpiresAt: expires, Data: testPayload{Field1: "tmp"}}
+
+	t.Run("passed to an expiring provider", func(t *testing.T) {
+		provider := &expiringProvider{mockBucketProvider: newMockBucketProvider()}
+		bucket := NewBucket[testPayload](provider)
+		if err := bucket.Put(ctx, obj); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if got := provider.info["render"].ExpiresAt; !got.Equal(expires) {
+			t.Errorf("expected ExpiresAt %v passed to the provider, got %v", expires, got)
+		}
+		got, err := bucket.Get(ctx, "render")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if !got.ExpiresAt.Equal(expires) {
+			t.Errorf("expected ExpiresAt %v on Get, got %v", expires, got.ExpiresAt)
+		}
+	})
+
+	t.Run("unsupported without expiry", func(t *testing.T) {
+		provider := newMockBucketProvider()
+		err := NewBucket[testPayload](provider).Put(ctx, obj)
+		if !errors.Is(err, ErrUnsupported) {
+			t.Fatalf("expected ErrUnsupported, got %v", err)
+		}
+		if len(provider.data) != 0 {
+			t.Error("expected nothing stored")
+		}
+		if err := NewBucket[testPayload](provider).Put(ctx, &Object[testPayload]{Key: "kept"}); err != nil {
+			t.Errorf("expected an object without ExpiresAt to store, got %v", err)
+		}
+	})
+
+	t.Run("atomic view", func(t *testing.T) {
+		provider := newMockBucketProvider()
+		err := NewBucket[testPayload](provider).Atomic().Put(ctx, "render", &AtomicObject{Key: "render", ExpiresAt: expires})
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+	})
+}
+
 func TestBucket_Delete(t *testing.T) {
 	provider := newMockBucketProvider()
 	bucket := NewBucket[testPayload](provider)
@@ -344,6 +540,294 @@ func TestBucket_List(t *testing.T) {
 	})
 }
 
+// pagerBucketProvider records the ListPage arguments it was called with.
+type pagerBucketProvider struct {
+	*mockBucketProvider
+	limit  int
+	cursor string
+}
+
+func (p *pagerBucketProvider) ListPage(_ context.Context, _ string, limit int, cursor string) ([]ObjectInfo, string, error) {
+	p.limit, p.cursor = limit, cursor
+	return []ObjectInfo{{Key: "from-pager"}}, "token-2", nil
+}
+
+func TestBucket_ListPage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sorted key fallback", func(t *testing.T) {
+		provider := newMockBucketProvider()
+		for _, key := range []string{"p/e", "p/b", "p/d", "p/a", "p/c", "q/a"} {
+			provider.data[key] = []byte(`{}`)
+			provider.info[key] = &ObjectInfo{Key: key}
+		}
+		bucket := NewBucket[testPayload](provider)
+
+		var keys []string
+		cursor := ""
+		for pages := 0; ; pages++ {
+			if pages == 3 {
+				t.Fatal("expected the listing to finish in 3 pages")
+			}
+			infos, next, err := bucket.ListPage(ctx, "p/", 2, cursor)
+			if err != nil {
+				t.Fatalf("ListPage failed: %v", err)
+			}
+			for _, info := range infos {
+				keys = append(keys, info.Key)
+			}
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		if got := strings.Join(keys, ","); got != "p/a,p/b,p/c,p/d,p/e" {
+			t.Errorf("expected every key once in order, got %s", got)
+		}
+	})
+
+	t.Run("exact final page", func(t *testing.T) {
+		provider := newMockBucketProvider()
+		for _, key := range []string{"a", "b"} {
+			provider.info[key] = &ObjectInfo{Key: key}
+		}
+		infos, next, err := NewBucket[testPayload](provider).ListPage(ctx, "", 2, "")
+		if err != nil || len(infos) != 2 || next != "" {
+			t.Errorf("expected 2 infos and no next cursor, got %d, %q, %v", len(infos), next, err)
+		}
+	})
+
+	t.Run("provider pager", func(t *testing.T) {
+		provider := &pagerBucketProvider{mockBucketProvider: newMockBucketProvider()}
+		infos, next, err := NewBucket[testPayload](provider).ListPage(ctx, "", 0, "token-1")
+		if err != nil {
+			t.Fatalf("ListPage failed: %v", err)
+		}
+		if len(infos) != 1 || infos[0].Key != "from-pager" || next != "token-2" {
+			t.Errorf("expected the provider page, got %+v, %q", infos, next)
+		}
+		if provider.limit != 1000 || provider.cursor != "token-1" {
+			t.Errorf("expected limit 1000 and cursor token-1, got %d, %q", provider.limit, provider.cursor)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		provider := newMockBucketProvider()
+		provider.listErr = errors.New("list failed")
+		if _, _, err := NewBucket[testPayload](provider).ListPage(ctx, "", 2, ""); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+// headerBucketProvider counts Head calls and strips List metadata, like S3.
+type headerBucketProvider struct {
+	*mockBucketProvider
+	heads int
+}
+
+func (p *headerBucketProvider) List(ctx context.Context, prefix string, limit int) ([]ObjectInfo, error) {
+	infos, err := p.mockBucketProvider.List(ctx, prefix, limit)
+	for n := range infos {
+		infos[n].Metadata = nil
+	}
+	return infos, err
+}
+
+func (p *headerBucketProvider) Head(_ context.Context, key string) (*ObjectInfo, error) {
+	p.heads++
+	info, ok := p.info[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return info, nil
+}
+
+// metadataListerProvider lists with metadata and fails on any Get.
+type metadataListerProvider struct {
+	*mockBucketProvider
+}
+
+func (p *metadataListerProvider) ListMetadata(ctx context.Context, prefix string, limit int) ([]ObjectInfo, error) {
+	return p.mockBucketProvider.List(ctx, prefix, limit)
+}
+
+func (p *metadataListerProvider) Get(context.Context, string) ([]byte, *ObjectInfo, error) {
+	return nil, nil, errors.New("unexpected get")
+}
+
+func TestBucket_FilterByMetadata(t *testing.T) {
+	ctx := context.Background()
+	seed := func() *mockBucketProvider {
+		provider := newMockBucketProvider()
+		for key, meta := range map[string]map[string]string{
+			"docs/a":  {"Owner": "ann", "kind": "report"},
+			"docs/b":  {"owner": "bob", "kind": "report"},
+			"docs/c":  {"owner": "ann", "kind": "memo"},
+			"other/d": {"owner": "ann", "kind": "report"},
+		} {
+			provider.data[key] = []byte(`{}`)
+			provider.info[key] = &ObjectInfo{Key: key, Metadata: meta}
+		}
+		return provider
+	}
+	want := map[string]string{"owner": "ann", "kind": "report"}
+
+	t.Run("get fallback", func(t *testing.T) {
+		provider := seed()
+		bucket := NewBucket[testPayload](provider)
+		infos, err := bucket.FilterByMetadata(ctx, "docs/", want, 0)
+		if err != nil {
+			t.Fatalf("FilterByMetadata failed: %v", err)
+		}
+		if len(infos) != 1 || infos[0].Key != "docs/a" {
+			t.Errorf("expected [docs/a], got %v", infos)
+		}
+	})
+
+	t.Run("header", func(t *testing.T) {
+		provider := &headerBucketProvider{mockBucketProvider: seed()}
+		bucket := NewBucket[testPayload](provider)
+		infos, err := bucket.FilterByMetadata(ctx, "docs/", map[string]string{"owner": "ann"}, 0)
+		if err != nil {
+			t.Fatalf("FilterByMetadata failed: %v", err)
+		}
+		if len(infos) != 2 {
+			t.Errorf("expected 2 infos, got %d", len(infos))
+		}
+		if provider.heads != 3 {
+			t.Errorf("expected 3 heads, got %d", provider.heads)
+		}
+	})
+
+	t.Run("lister", func(t *testing.T) {
+		provider := &metadataListerProvider{mockBucketProvider: seed()}
+		bucket := NewBucket[testPayload](provider)
+		infos, err := bucket.FilterByMetadata(ctx, "", want, 0)
+		if err != nil {
+			t.Fatalf("FilterByMetadata failed: %v", err)
+		}
+		if len(infos) != 2 {
+			t.Errorf("expected 2 infos, got %d", len(infos))
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		bucket := NewBucket[testPayload](seed())
+		infos, err := bucket.FilterByMetadata(ctx, "", want, 1)
+		if err != nil {
+			t.Fatalf("FilterByMetadata failed: %v", err)
+		}
+		if len(infos) != 1 {
+			t.Errorf("expected 1 info, got %d", len(infos))
+		}
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		provider := seed()
+		provider.listErr = errors.New("list failed")
+		bucket := NewBucket[testPayload](provider)
+		if _, err := bucket.FilterByMetadata(ctx, "", want, 0); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+// versionedBucketProvider keeps every put as a numbered version.
+type versionedBucketProvider struct {
+	*mockBucketProvider
+	versions map[string][][]byte
+}
+
+func newVersionedBucketProvider() *versionedBucketProvider {
+	return &versionedBucketProvider{
+		mockBucketProvider: newMockBucketProvider(),
+		versions:           make(map[string][][]byte),
+	}
+}
+
+func (p *versionedBucketProvider) Put(ctx context.Context, key string, data []byte, info *ObjectInfo) error {
+	if err := p.mockBucketProvider.Put(ctx, key, data, info); err != nil {
+		return err
+	}
+	p.versions[key] = append(p.versions[key], data)
+	return nil
+}
+
+func (p *versionedBucketProvider) ListVersions(_ context.Context, key string) ([]ObjectVersion, error) {
+	stored := p.versions[key]
+	if len(stored) == 0 {
+		return nil, ErrNotFound
+	}
+	out := make([]ObjectVersion, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		out = append(out, ObjectVersion{
+			Key:       key,
+			VersionID: strconv.Itoa(i + 1),
+			Size:      int64(len(stored[i])),
+			IsLatest:  i == len(stored)-1,
+		})
+	}
+	return out, nil
+}
+
+func (p *versionedBucketProvider) GetVersion(_ context.Context, key, versionID string) ([]byte, *ObjectInfo, error) {
+	n, err := strconv.Atoi(versionID)
+	if err != nil || n < 1 || n > len(p.versions[key]) {
+		return nil, nil, ErrNotFound
+	}
+	data := p.versions[key][n-1]
+	return data, &ObjectInfo{Key: key, Size: int64(len(data)), VersionID: versionID}, nil
+}
+
+func TestBucket_Versions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lists and reads prior versions", func(t *testing.T) {
+		bucket := NewBucket[testPayload](newVersionedBucketProvider())
+		for i := 1; i <= 3; i++ {
+			obj := &Object[testPayload]{Key: "doc", Data: testPayload{Field1: "rev", Field2: i}}
+			if err := bucket.Put(ctx, obj); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+		}
+
+		versions, err := bucket.ListVersions(ctx, "doc")
+		if err != nil {
+			t.Fatalf("ListVersions failed: %v", err)
+		}
+		if len(versions) != 3 || versions[0].VersionID != "3" || !versions[0].IsLatest {
+			t.Fatalf("expected 3 versions newest first, got %+v", versions)
+		}
+
+		obj, err := bucket.GetVersion(ctx, "doc", "1")
+		if err != nil {
+			t.Fatalf("GetVersion failed: %v", err)
+		}
+		if obj.Data.Field2 != 1 {
+			t.Errorf("expected first revision, got %+v", obj.Data)
+		}
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		bucket := NewBucket[testPayload](newVersionedBucketProvider())
+		_, err := bucket.GetVersion(ctx, "doc", "9")
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		bucket := NewBucket[testPayload](newMockBucketProvider())
+		if _, err := bucket.ListVersions(ctx, "doc"); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported from ListVersions, got %v", err)
+		}
+		if _, err := bucket.GetVersion(ctx, "doc", "1"); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported from GetVersion, got %v", err)
+		}
+	})
+}
+
 func TestBucket_RoundTrip(t *testing.T) {
 	provider := newMockBucketProvider()
 	bucket := NewBucket[testPayload](provider)
@@ -403,6 +887,22 @@ func TestBucket_WithGobCodec(t *testing.T) {
 	}
 }
 
+func TestBucket_Provider(t *testing.T) {
+	provider := newMockBucketProvider()
+	bucket := NewBucket[testPayload](provider)
+
+	if p, ok := bucket.Provider().(*mockBucketProvider); !ok || p != provider {
+		t.Errorf("expected the provider passed to NewBucket, got %T", bucket.Provider())
+	}
+}
+
+func TestBucket_Spec(t *testing.T) {
+	spec := NewBucket[testPayload](newMockBucketProvider()).Spec()
+	if spec.TypeName != "testPayload" || len(spec.Fields) != 2 || spec.Fields[1].Type != "int" {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+}
+
 func TestBucket_Atomic(t *testing.T) {
 	provider := newMockBucketProvider()
 	bucket := NewBucket[testPayload](provider)
@@ -441,3 +941,22 @@ func TestBucket_Atomic(t *testing.T) {
 		t.Errorf("unexpected Field2: %v", obj.Data.Ints["Field2"])
 	}
 }
+
+func TestBucket_ErrorContext(t *testing.T) {
+	provider := newMockBucketProvider()
+	bucket := NewBucket[testPayload](provider)
+	ctx := context.Background()
+
+	err := bucket.Delete(ctx, "missing.json")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	var gerr *Error
+	if !errors.As(err, &gerr) {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if gerr.Kind != KindBucket || gerr.Op != "delete" || gerr.Key != "missing.json" {
+		t.Errorf("unexpected context: kind=%q op=%q key=%q", gerr.Kind, gerr.Op, gerr.Key)
+	}
+}
@@ -0,0 +1,34 @@
+package grub
+
+// batchLimit returns the batch limit provider declares through
+// BatchLimiter, looking through grub's own provider wrappers, or 0 when it
+// declares none.
+func batchLimit(provider any) int {
+	switch p := provider.(type) {
+	case *namespacedStore:
+		return batchLimit(p.inner)
+	case *keyedProvider:
+		return batchLimit(p.VectorProvider)
+	}
+	if l, ok := provider.(BatchLimiter); ok {
+		return max(l.MaxBatchSize(), 0)
+	}
+	return 0
+}
+
+// inChunks calls write with consecutive [start, end) ranges covering n
+// items, each at most limit long, stopping at the first error. A limit of 0
+// makes one call for the whole range. When the batch was split, the error
+// is wrapped in a *BatchChunkError naming the failing chunk.
+func inChunks(n, limit int, write func(start, end int) error) error {
+	if limit <= 0 || n <= limit {
+		return write(0, n)
+	}
+	for chunk, start := 0, 0; start < n; chunk, start = chunk+1, start+limit {
+		end := min(start+limit, n)
+		if err := write(start, end); err != nil {
+			return &BatchChunkError{Chunk: chunk, Start: start, Size: end - start, Err: err}
+		}
+	}
+	return nil
+}
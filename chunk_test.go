@@ -0,0 +1,182 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// limitedVectorProvider declares a batch limit and records each batch size,
+// failing the batch numbered failAt (1-based) when set.
+type limitedVectorProvider struct {
+	*mockVectorProvider
+	limit  int
+	sizes  []int
+	failAt int
+}
+
+func (p *limitedVectorProvider) MaxBatchSize() int { return p.limit }
+
+func (p *limitedVectorProvider) record(n int) error {
+	p.sizes = append(p.sizes, n)
+	if len(p.sizes) == p.failAt {
+		return errors.New("too large")
+	}
+	return nil
+}
+
+func (p *limitedVectorProvider) UpsertBatch(ctx context.Context, vectors []VectorRecord) error {
+	if err := p.record(len(vectors)); err != nil {
+		return err
+	}
+	return p.mockVectorProvider.UpsertBatch(ctx, vectors)
+}
+
+func (p *limitedVectorProvider) DeleteBatch(ctx context.Context, ids []uuid.UUID) error {
+	if err := p.record(len(ids)); err != nil {
+		return err
+	}
+	return p.mockVectorProvider.DeleteBatch(ctx, ids)
+}
+
+// limitedStoreProvider declares a batch limit and records each batch.
+type limitedStoreProvider struct {
+	*mockStoreProvider
+	limit  int
+	sets   [][]string
+	gets   []int
+	failAt int
+}
+
+func (p *limitedStoreProvider) MaxBatchSize() int { return p.limit }
+
+func (p *limitedStoreProvider) GetBatch(ctx context.Context, keys []string) (map[string][]byte, error) {
+	p.gets = append(p.gets, len(keys))
+	return p.mockStoreProvider.GetBatch(ctx, keys)
+}
+
+func (p *limitedStoreProvider) SetBatch(ctx context.Context, items map[string][]byte, ttl time.Duration) error {
+	var keys []string
+	for k := range items {
+		keys = append(keys, k)
+	}
+	p.sets = append(p.sets, keys)
+	if len(p.sets) == p.failAt {
+		return errors.New("too large")
+	}
+	return p.mockStoreProvider.SetBatch(ctx, items, ttl)
+}
+
+func TestIndex_BatchChunking(t *testing.T) {
+	ctx := context.Background()
+	vectors := make([]Vector[testMetadata], 5)
+	ids := make([]uuid.UUID, 5)
+	for n := range vectors {
+		ids[n] = uuid.New()
+		vectors[n] = Vector[testMetadata]{ID: ids[n], Vector: []float32{1, 2}}
+	}
+
+	t.Run("splits to the limit", func(t *testing.T) {
+		provider := &limitedVectorProvider{mockVectorProvider: newMockVectorProvider(), limit: 2}
+		index := NewIndex[testMetadata](provider)
+		if err := index.UpsertBatch(ctx, vectors); err != nil {
+			t.Fatalf("UpsertBatch failed: %v", err)
+		}
+		if err := index.DeleteBatch(ctx, ids); err != nil {
+			t.Fatalf("DeleteBatch failed: %v", err)
+		}
+		if want := []int{2, 2, 1, 2, 2, 1}; !reflect.DeepEqual(provider.sizes, want) {
+			t.Errorf("expected batch sizes %v, got %v", want, provider.sizes)
+		}
+	})
+
+	t.Run("reports the failing chunk", func(t *testing.T) {
+		provider := &limitedVectorProvider{mockVectorProvider: newMockVectorProvider(), limit: 2, failAt: 2}
+		err := NewIndex[testMetadata](provider).UpsertBatch(ctx, vectors)
+		var chunkErr *BatchChunkError
+		if !errors.As(err, &chunkErr) {
+			t.Fatalf("expected a BatchChunkError, got %v", err)
+		}
+		if chunkErr.Chunk != 1 || chunkErr.Start != 2 || chunkErr.Size != 2 {
+			t.Errorf("expected chunk 1 at item 2 of size 2, got %+v", chunkErr)
+		}
+		if len(provider.vectors) != 2 || len(provider.sizes) != 2 {
+			t.Errorf("expected only the first chunk written and no later attempts, got %d written in %v", len(provider.vectors), provider.sizes)
+		}
+	})
+
+	t.Run("unsplit batches keep the plain error", func(t *testing.T) {
+		provider := &limitedVectorProvider{mockVectorProvider: newMockVectorProvider(), limit: 10, failAt: 1}
+		err := NewIndex[testMetadata](provider).UpsertBatch(ctx, vectors)
+		var chunkErr *BatchChunkError
+		if err == nil || errors.As(err, &chunkErr) {
+			t.Errorf("expected an error without chunk details, got %v", err)
+		}
+	})
+
+	t.Run("keyed index", func(t *testing.T) {
+		provider := &limitedVectorProvider{mockVectorProvider: newMockVectorProvider(), limit: 2}
+		if err := NewKeyedIndex[testMetadata](provider, uuid.New()).Index().DeleteBatch(ctx, ids); err != nil {
+			t.Fatalf("DeleteBatch failed: %v", err)
+		}
+		if len(provider.sizes) != 3 {
+			t.Errorf("expected the keyed index to honour the limit, got %v", provider.sizes)
+		}
+	})
+}
+
+func TestStore_BatchChunking(t *testing.T) {
+	ctx := context.Background()
+	items := make(map[string]*testRecord, 5)
+	keys := make([]string, 0, 5)
+	for n := 0; n < 5; n++ {
+		key := "k" + strconv.Itoa(n)
+		items[key] = &testRecord{ID: n}
+		keys = append(keys, key)
+	}
+
+	t.Run("splits to the limit", func(t *testing.T) {
+		provider := &limitedStoreProvider{mockStoreProvider: newMockStoreProvider(), limit: 2}
+		store := NewStore[testRecord](provider, WithKeyNamespace("ns:"))
+		if err := store.SetBatch(ctx, items, 0); err != nil {
+			t.Fatalf("SetBatch failed: %v", err)
+		}
+		if len(provider.sets) != 3 || len(provider.sets[2]) != 1 || provider.sets[2][0] != "ns:k4" {
+			t.Errorf("expected sorted chunks of 2, 2, and 1, got %v", provider.sets)
+		}
+		got, err := store.GetBatch(ctx, keys)
+		if err != nil || len(got) != 5 {
+			t.Fatalf("expected all 5 records, got %d, %v", len(got), err)
+		}
+		if want := []int{2, 2, 1}; !reflect.DeepEqual(provider.gets, want) {
+			t.Errorf("expected get batch sizes %v, got %v", want, provider.gets)
+		}
+	})
+
+	t.Run("reports the failing chunk", func(t *testing.T) {
+		provider := &limitedStoreProvider{mockStoreProvider: newMockStoreProvider(), limit: 2, failAt: 3}
+		err := NewStore[testRecord](provider).SetBatch(ctx, items, 0)
+		var chunkErr *BatchChunkError
+		if !errors.As(err, &chunkErr) || chunkErr.Chunk != 2 || chunkErr.Start != 4 {
+			t.Fatalf("expected chunk 2 at item 4 to fail, got %v", err)
+		}
+		if len(provider.data) != 4 {
+			t.Errorf("expected the first two chunks written, got %d keys", len(provider.data))
+		}
+	})
+}
+
+func TestBatchChunkError(t *testing.T) {
+	err := &BatchChunkError{Chunk: 1, Start: 100, Size: 100, Err: ErrConflict}
+	if got := err.Error(); got != "chunk 1 (items 100-199): "+ErrConflict.Error() {
+		t.Errorf("unexpected message %q", got)
+	}
+	if !errors.Is(err, ErrConflict) {
+		t.Error("expected the chunk error to unwrap")
+	}
+}
@@ -0,0 +1,126 @@
+package grub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/vecna"
+)
+
+// clientFilterCap resolves the scan cap for WithClientSideFilter. Zero
+// disables client-side filtering.
+func clientFilterCap(o options) int {
+	if !o.clientFilter {
+		return 0
+	}
+	if o.clientFilterCap > 0 {
+		return o.clientFilterCap
+	}
+	return defaultClientFilterCap
+}
+
+// queryClientSide answers a Query the provider rejected by fetching up to
+// scanCap nearest neighbors unfiltered and keeping the first k that match.
+func (i *Index[T]) queryClientSide(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]VectorResult, error) {
+	if err := filter.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	candidates, err := i.provider.Query(ctx, vector, i.scanCap+1, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) > i.scanCap {
+		return nil, fmt.Errorf("%w: more than %d vectors", ErrScanLimitExceeded, i.scanCap)
+	}
+	i.emitClientFilterUsed(ctx, "query", len(candidates))
+
+	results := make([]VectorResult, 0, k)
+	for _, r := range candidates {
+		ok, err := i.matchResult(filter, r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			results = append(results, r)
+			if len(results) == k {
+				break
+			}
+		}
+	}
+	return results, nil
+}
+
+// filterClientSide answers a Filter the provider rejected by listing and
+// fetching every vector, refusing collections larger than scanCap.
+func (i *Index[T]) filterClientSide(ctx context.Context, filter *vecna.Filter, limit int) ([]VectorResult, error) {
+	if err := filter.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	ids, err := i.lister.List(ctx, i.scanCap+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > i.scanCap {
+		return nil, fmt.Errorf("%w: more than %d vectors", ErrScanLimitExceeded, i.scanCap)
+	}
+	i.emitClientFilterUsed(ctx, "filter", len(ids))
+
+	var results []VectorResult
+	for _, id := range ids {
+		vector, info, err := i.provider.Get(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue // deleted since List
+		}
+		if err != nil {
+			return nil, err
+		}
+		r := VectorResult{ID: id, Vector: vector, Metadata: info.Metadata}
+		ok, err := i.matchResult(filter, r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			results = append(results, r)
+			if limit > 0 && len(results) == limit {
+				break
+			}
+		}
+	}
+	return results, nil
+}
+
+// matchResult evaluates filter against a result's metadata. Metadata is
+// decoded into T and re-encoded as JSON, so fields are matched by the JSON
+// names vecna uses whatever the codec; with a JSONTagCodec, by the names it
+// stores. Undecodable metadata is passed to the DecodeErrorHandler; a nil
+// return drops the result.
+func (i *Index[T]) matchResult(filter *vecna.Filter, r VectorResult) (bool, error) {
+	if filter == nil {
+		return true, nil
+	}
+	var metadata T
+	if err := i.decodeMetadata(r.Metadata, &metadata); err != nil {
+		return false, handleDecodeErr(i.onDecodeErr, r.ID.String(), r.Metadata, err)
+	}
+	data, err := JSONTagCodec{Tag: fieldTag(i.codec)}.Encode(&metadata)
+	if err != nil {
+		return false, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return false, err
+	}
+	return MatchFilter(filter, fields)
+}
+
+// emitClientFilterUsed emits IndexClientFilterUsed.
+func (i *Index[T]) emitClientFilterUsed(ctx context.Context, op string, scanned int) {
+	capitan.Warn(ctx, IndexClientFilterUsed,
+		CollectionKey.Field(i.name),
+		OperationKey.Field(op),
+		ScannedCountKey.Field(scanned),
+	)
+}
@@ -0,0 +1,147 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/vecna"
+)
+
+// filterlessVectorProvider rejects metadata filtering the way Pinecone
+// does: Filter always fails and Query fails for any non-nil filter.
+type filterlessVectorProvider struct {
+	*mockVectorProvider
+}
+
+func (p *filterlessVectorProvider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]VectorResult, error) {
+	if filter != nil {
+		return nil, ErrOperatorNotSupported
+	}
+	return p.mockVectorProvider.Query(ctx, vector, k, nil)
+}
+
+func (*filterlessVectorProvider) Filter(context.Context, *vecna.Filter, int) ([]VectorResult, error) {
+	return nil, ErrFilterNotSupported
+}
+
+func TestIndex_ClientSideFilter(t *testing.T) {
+	ctx := context.Background()
+	b, err := vecna.New[testMetadata]()
+	if err != nil {
+		t.Fatalf("vecna.New failed: %v", err)
+	}
+
+	provider := &filterlessVectorProvider{newMockVectorProvider()}
+	index := NewIndex[testMetadata](provider, WithClientSideFilter())
+	dataset := make(map[uuid.UUID]testMetadata)
+	for n := 0; n < 12; n++ {
+		id := uuid.New()
+		meta := testMetadata{Category: []string{"tech", "art", "food"}[n%3], Score: n}
+		dataset[id] = meta
+		if err := index.Upsert(ctx, id, []float32{float32(n), 0}, &meta); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+	}
+
+	filter := b.And(b.Where("category").In("tech", "food"), b.Where("score").Gte(4))
+	want := func(m testMetadata) bool {
+		return (m.Category == "tech" || m.Category == "food") && m.Score >= 4
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		plain := NewIndex[testMetadata](provider)
+		if _, err := plain.Filter(ctx, filter, 0); !errors.Is(err, ErrFilterNotSupported) {
+			t.Errorf("expected ErrFilterNotSupported, got %v", err)
+		}
+		if _, err := plain.Query(ctx, []float32{0, 0}, 3, filter); !errors.Is(err, ErrOperatorNotSupported) {
+			t.Errorf("expected ErrOperatorNotSupported, got %v", err)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		results, err := index.Filter(ctx, filter, 0)
+		if err != nil {
+			t.Fatalf("Filter failed: %v", err)
+		}
+		var expected []uuid.UUID
+		for id, m := range dataset {
+			if want(m) {
+				expected = append(expected, id)
+			}
+		}
+		if len(results) != len(expected) {
+			t.Fatalf("expected %d results, got %d", len(expected), len(results))
+		}
+		for _, r := range results {
+			if !want(dataset[r.ID]) {
+				t.Errorf("unexpected result %+v", r.Metadata)
+			}
+		}
+	})
+
+	t.Run("filter limit", func(t *testing.T) {
+		results, err := index.Filter(ctx, filter, 2)
+		if err != nil {
+			t.Fatalf("Filter failed: %v", err)
+		}
+		if len(results) != 2 {
+			t.Errorf("expected limit applied after filtering, got %d results", len(results))
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		results, err := index.Query(ctx, []float32{0, 0}, 3, filter)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		// nearest to the origin is lowest score
+		var scores []int
+		for _, m := range dataset {
+			if want(m) {
+				scores = append(scores, m.Score)
+			}
+		}
+		sort.Ints(scores)
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %d", len(results))
+		}
+		for n, r := range results {
+			if r.Metadata.Score != scores[n] {
+				t.Errorf("result %d: expected score %d, got %d", n, scores[n], r.Metadata.Score)
+			}
+		}
+	})
+
+	t.Run("scan cap", func(t *testing.T) {
+		capped := NewIndex[testMetadata](provider, WithClientSideFilter(), WithClientSideFilterCap(5))
+		if _, err := capped.Filter(ctx, filter, 1); !errors.Is(err, ErrScanLimitExceeded) {
+			t.Errorf("expected ErrScanLimitExceeded from Filter, got %v", err)
+		}
+		if _, err := capped.Query(ctx, []float32{0, 0}, 1, filter); !errors.Is(err, ErrScanLimitExceeded) {
+			t.Errorf("expected ErrScanLimitExceeded from Query, got %v", err)
+		}
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		events := make(chan *capitan.Event, 1)
+		listener := capitan.Hook(IndexClientFilterUsed, func(_ context.Context, e *capitan.Event) { events <- e })
+		defer listener.Close()
+
+		if _, err := index.Filter(ctx, filter, 0); err != nil {
+			t.Fatalf("Filter failed: %v", err)
+		}
+		select {
+		case e := <-events:
+			if got, _ := ScannedCountKey.From(e); got != len(dataset) {
+				t.Errorf("scanned_count: expected %d, got %d", len(dataset), got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for IndexClientFilterUsed")
+		}
+	})
+}
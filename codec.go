@@ -4,6 +4,10 @@ import (
 	"bytes"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 )
 
 // Codec defines encoding/decoding operations for Store values.
@@ -20,9 +24,10 @@ func (JSONCodec) Encode(v any) ([]byte, error) {
 	return json.Marshal(v)
 }
 
-// Decode unmarshals JSON data into v.
+// Decode unmarshals JSON data into v. Data that is not JSON, such as Gob
+// output, fails with ErrCodecMismatch.
 func (JSONCodec) Decode(data []byte, v any) error {
-	return json.Unmarshal(data, v)
+	return decodeJSON(data, v)
 }
 
 // GobCodec implements Codec using Gob encoding.
@@ -37,7 +42,236 @@ func (GobCodec) Encode(v any) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// Decode unmarshals Gob data into v.
+// Decode unmarshals Gob data into v. JSON data, such as a value stored
+// before a store switched codecs, fails with ErrCodecMismatch.
 func (GobCodec) Decode(data []byte, v any) error {
-	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+	if err != nil && json.Valid(data) {
+		return fmt.Errorf("%w: got JSON, want gob: %w", ErrCodecMismatch, err)
+	}
+	return err
+}
+
+// MultiCodec encodes with Codec and decodes with the first of Codec and
+// Fallbacks that recognises the data, so a store can move to a new codec
+// without downtime: values written before the switch still decode, and
+// each write stores its value in the new format.
+//
+//	store := grub.NewStoreWithCodec[Config](provider, grub.MultiCodec{
+//		Codec:     grub.GobCodec{},
+//		Fallbacks: []grub.Codec{grub.JSONCodec{}},
+//	})
+//
+// A codec passes data on to the next by failing with ErrCodecMismatch, as
+// JSONCodec, JSONTagCodec, and GobCodec do for each other's output. Any
+// other error is returned as is. When no codec recognises the data,
+// Codec's error is returned.
+type MultiCodec struct {
+	Codec     Codec
+	Fallbacks []Codec
+}
+
+// Encode marshals v with Codec.
+func (c MultiCodec) Encode(v any) ([]byte, error) {
+	return c.Codec.Encode(v)
+}
+
+// Decode unmarshals data into v with the first codec that recognises it.
+func (c MultiCodec) Decode(data []byte, v any) error {
+	err := c.Codec.Decode(data, v)
+	if !errors.Is(err, ErrCodecMismatch) {
+		return err
+	}
+	for _, fallback := range c.Fallbacks {
+		if ferr := fallback.Decode(data, v); !errors.Is(ferr, ErrCodecMismatch) {
+			return ferr
+		}
+	}
+	return err
+}
+
+// decodeJSON is json.Unmarshal, reporting data that is not JSON as
+// ErrCodecMismatch.
+func decodeJSON(data []byte, v any) error {
+	err := json.Unmarshal(data, v)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("%w: not JSON: %w", ErrCodecMismatch, err)
+	}
+	return err
+}
+
+// JSONTagCodec implements Codec using JSON encoding, keying struct fields by
+// their Tag struct tag instead of their json tag, so the stored form of a
+// struct shared with an API layer need not follow its API names:
+//
+//	type Doc struct {
+//		Title string `json:"title" db:"doc_title"`
+//	}
+//
+//	index := grub.NewIndexWithCodec[Doc](provider, grub.JSONTagCodec{Tag: "db"})
+//
+// Fields without a Tag keep encoding/json's name, and a Tag of "-" skips
+// the field. The omitempty option applies from either tag. Only the fields
+// of the value itself, including those promoted from embedded structs, are
+// renamed; nested structs, and values that are not structs, encode as plain
+// JSON. Index derives its Schema, WithSearchFields names, and client-side
+// filter names from Tag too.
+type JSONTagCodec struct {
+	Tag string
+}
+
+// Encode marshals v to a JSON object keyed by Tag.
+func (c JSONTagCodec) Encode(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || !c.renames(rv.Type()) {
+		return json.Marshal(v)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, f := range taggedFields(rv.Type(), c.Tag) {
+		fv, err := rv.FieldByIndexErr(f.index)
+		if err != nil || (f.omitEmpty && isEmptyJSON(fv)) {
+			continue
+		}
+		value, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Decode unmarshals a JSON object keyed by Tag into v. Keys that match no
+// field are ignored.
+func (c JSONTagCodec) Decode(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct || !c.renames(rv.Elem().Type()) {
+		return decodeJSON(data, v)
+	}
+	var object map[string]json.RawMessage
+	if err := decodeJSON(data, &object); err != nil {
+		return err
+	}
+	if object == nil {
+		return nil
+	}
+	rv = rv.Elem()
+	for _, f := range taggedFields(rv.Type(), c.Tag) {
+		raw, ok := object[f.name]
+		if !ok {
+			continue
+		}
+		fv, err := allocField(rv, f.index)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", f.name, err)
+		}
+		if err := json.Unmarshal(raw, fv.Addr().Interface()); err != nil {
+			return fmt.Errorf("field %q: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// fieldTag returns the struct tag codec names metadata fields by: Tag for a
+// JSONTagCodec, json otherwise.
+func fieldTag(codec Codec) string {
+	if c, ok := codec.(JSONTagCodec); ok && c.Tag != "" {
+		return c.Tag
+	}
+	return "json"
+}
+
+// renames reports whether c encodes t differently from encoding/json.
+func (c JSONTagCodec) renames(t reflect.Type) bool {
+	if c.Tag == "" || c.Tag == "json" {
+		return false
+	}
+	return !t.Implements(jsonMarshalerType) && !reflect.PointerTo(t).Implements(jsonMarshalerType)
+}
+
+// taggedField is a field of a struct as JSONTagCodec encodes it.
+type taggedField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// taggedFields returns the fields of struct type t JSONTagCodec encodes
+// under tag, in field order.
+func taggedFields(t reflect.Type, tag string) []taggedField {
+	var fields []taggedField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name, ok := taggedName(f, tag)
+		if !ok {
+			continue
+		}
+		fields = append(fields, taggedField{name: name, index: f.Index, omitEmpty: hasTagOption(f, "json", "omitempty") || hasTagOption(f, tag, "omitempty")})
+	}
+	return fields
+}
+
+// taggedName returns the key f is stored under when fields are named by
+// tag, falling back to encoding/json's name, or false if it is skipped.
+func taggedName(f reflect.StructField, tag string) (string, bool) {
+	if tag != "json" {
+		if name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name == "-" {
+			return "", false
+		} else if name != "" {
+			return name, true
+		}
+	}
+	return jsonName(f)
+}
+
+// hasTagOption reports whether f's tag lists option after its name.
+func hasTagOption(f reflect.StructField, tag, option string) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get(tag), ",")
+	return strings.Contains(","+opts+",", ","+option+",")
+}
+
+// allocField returns the field of v at index, allocating nil embedded
+// struct pointers on the way. Like encoding/json, it cannot allocate a
+// pointer to an unexported embedded struct.
+func allocField(v reflect.Value, index []int) (reflect.Value, error) {
+	for n, i := range index {
+		if n > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %v", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, nil
+}
+
+// isEmptyJSON reports whether encoding/json's omitempty would drop v.
+func isEmptyJSON(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
 }
@@ -1,7 +1,14 @@
 package grub
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/google/uuid"
 )
 
 type testStruct struct {
@@ -159,6 +166,16 @@ func TestGobCodec_Decode(t *testing.T) {
 		if err := codec.Decode(data, &v); err == nil {
 			t.Error("expected error for invalid Gob data")
 		}
+		if err := codec.Decode(data, &v); errors.Is(err, ErrCodecMismatch) {
+			t.Errorf("expected a plain gob error for data that is not JSON, got %v", err)
+		}
+	})
+
+	t.Run("json data", func(t *testing.T) {
+		var v testStruct
+		if err := codec.Decode([]byte(`{"name":"test","value":42}`), &v); !errors.Is(err, ErrCodecMismatch) {
+			t.Errorf("expected ErrCodecMismatch, got %v", err)
+		}
 	})
 }
 
@@ -216,3 +233,168 @@ func TestGobCodec_ComplexTypes(t *testing.T) {
 		t.Errorf("nested struct mismatch: %+v", decoded.Nested)
 	}
 }
+
+// taggedDoc names its fields differently for the API and for storage.
+type taggedDoc struct {
+	Title   string   `json:"title" db:"doc_title"`
+	Body    string   `json:"body,omitempty" db:"doc_body"`
+	Secret  string   `json:"-" db:"secret"`
+	Draft   bool     `json:"draft" db:"-"`
+	Tags    []string `json:"tags"`
+	Author  testStruct
+	private string
+	taggedEmbed
+}
+
+type taggedEmbed struct {
+	Rank int `json:"rank" db:"doc_rank"`
+}
+
+func TestJSONTagCodec(t *testing.T) {
+	codec := JSONTagCodec{Tag: "db"}
+	doc := &taggedDoc{
+		Title: "t", Secret: "s", Draft: true, Tags: []string{"a"},
+		Author: testStruct{Name: "n", Value: 1}, taggedEmbed: taggedEmbed{Rank: 3},
+	}
+
+	data, err := codec.Encode(doc)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	want := `{"doc_title":"t","secret":"s","tags":["a"],"Author":{"name":"n","value":1},"doc_rank":3}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+
+	var got taggedDoc
+	if err := codec.Decode(data, &got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	doc.Draft = false
+	if !reflect.DeepEqual(&got, doc) {
+		t.Errorf("expected %+v, got %+v", doc, got)
+	}
+
+	t.Run("non-structs are plain JSON", func(t *testing.T) {
+		for _, v := range []any{nil, map[string]int{"a": 1}, []int{1}, (*taggedDoc)(nil)} {
+			data, err := codec.Encode(v)
+			if err != nil {
+				t.Fatalf("Encode %T failed: %v", v, err)
+			}
+			plain, _ := JSONCodec{}.Encode(v)
+			if string(data) != string(plain) {
+				t.Errorf("%T: expected %s, got %s", v, plain, data)
+			}
+		}
+	})
+
+	t.Run("decode errors name the field", func(t *testing.T) {
+		var got taggedDoc
+		if err := codec.Decode([]byte(`{"doc_title":1}`), &got); err == nil || !strings.Contains(err.Error(), "doc_title") {
+			t.Errorf("expected an error naming doc_title, got %v", err)
+		}
+	})
+}
+
+func TestIndex_JSONTagCodec(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockVectorProvider()
+	index := NewIndexWithCodec[taggedDoc](provider, JSONTagCodec{Tag: "db"}, WithSearchFields("doc_title"))
+	names := make([]string, 0, len(index.Schema().Fields))
+	for _, f := range index.Schema().Fields {
+		names = append(names, f.Name)
+	}
+	if want := []string{"doc_title", "doc_body", "secret", "tags", "Author", "doc_rank"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected schema fields %v, got %v", want, names)
+	}
+
+	id := uuid.New()
+	if err := index.Upsert(ctx, id, []float32{1, 0}, &taggedDoc{Title: "t", Body: "b"}); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	results, err := index.Search(ctx, []float32{1, 0}, 1, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Metadata.Title != "t" || results[0].Metadata.Body != "" {
+		t.Errorf("expected only doc_title searched, got %+v", results)
+	}
+}
+
+func TestMultiCodec(t *testing.T) {
+	jsonData, _ := JSONCodec{}.Encode(testStruct{Name: "old", Value: 1})
+	gobData, _ := GobCodec{}.Encode(testStruct{Name: "new", Value: 2})
+
+	t.Run("json rejects gob", func(t *testing.T) {
+		var v testStruct
+		if err := (JSONCodec{}).Decode(gobData, &v); !errors.Is(err, ErrCodecMismatch) {
+			t.Errorf("expected ErrCodecMismatch, got %v", err)
+		}
+		if err := (JSONTagCodec{Tag: "db"}).Decode(gobData, &v); !errors.Is(err, ErrCodecMismatch) {
+			t.Errorf("expected ErrCodecMismatch from JSONTagCodec, got %v", err)
+		}
+	})
+
+	codec := MultiCodec{Codec: GobCodec{}, Fallbacks: []Codec{JSONCodec{}}}
+	data, err := codec.Encode(testStruct{Name: "n"})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if json.Valid(data) {
+		t.Errorf("expected Codec to encode, got JSON: %s", data)
+	}
+	for name, tt := range map[string]struct {
+		data []byte
+		want testStruct
+	}{
+		"codec":    {gobData, testStruct{Name: "new", Value: 2}},
+		"fallback": {jsonData, testStruct{Name: "old", Value: 1}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var v testStruct
+			if err := codec.Decode(tt.data, &v); err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if v != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, v)
+			}
+		})
+	}
+
+	t.Run("other errors are not passed on", func(t *testing.T) {
+		var v testStruct
+		err := MultiCodec{Codec: JSONCodec{}, Fallbacks: []Codec{GobCodec{}}}.Decode([]byte(`{"name":1}`), &v)
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Errorf("expected the JSON type error, got %v", err)
+		}
+	})
+
+	t.Run("unrecognised", func(t *testing.T) {
+		var v testStruct
+		if err := codec.Decode([]byte("\x00garbage"), &v); err == nil || errors.Is(err, ErrCodecMismatch) {
+			t.Errorf("expected Codec's own error, got %v", err)
+		}
+	})
+
+	t.Run("store migration", func(t *testing.T) {
+		ctx := context.Background()
+		provider := newMockStoreProvider()
+		if err := NewStore[testRecord](provider).Set(ctx, "old", &testRecord{ID: 1, Name: "old"}, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if _, err := NewStoreWithCodec[testRecord](provider, GobCodec{}).Get(ctx, "old"); !errors.Is(err, ErrCodecMismatch) {
+			t.Errorf("expected a Gob store to report ErrCodecMismatch, got %v", err)
+		}
+		store := NewStoreWithCodec[testRecord](provider, codec)
+		if got, err := store.Get(ctx, "old"); err != nil || got.Name != "old" {
+			t.Fatalf("expected the JSON record, got %+v, %v", got, err)
+		}
+		if err := store.Set(ctx, "old", &testRecord{ID: 1, Name: "migrated"}, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if got, err := NewStoreWithCodec[testRecord](provider, GobCodec{}).Get(ctx, "old"); err != nil || got.Name != "migrated" {
+			t.Errorf("expected the rewrite to be Gob, got %+v, %v", got, err)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package grub
+
+import "github.com/zoobzio/edamame"
+
+// IsNull returns a condition matching rows where field is NULL. It binds no
+// param; comparing against a nil param instead renders = NULL, which never
+// matches.
+//
+//	noAge := edamame.NewQueryStatement("no-age", "", edamame.QuerySpec{
+//	    Where: []edamame.ConditionSpec{grub.IsNull("age")},
+//	})
+func IsNull(field string) edamame.ConditionSpec {
+	return edamame.ConditionSpec{Field: field, Operator: "IS NULL", IsNull: true}
+}
+
+// IsNotNull returns a condition matching rows where field is not NULL.
+// Like IsNull, it binds no param.
+func IsNotNull(field string) edamame.ConditionSpec {
+	return edamame.ConditionSpec{Field: field, Operator: "IS NOT NULL", IsNull: true}
+}
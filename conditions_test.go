@@ -0,0 +1,49 @@
+package grub
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestNullConditions(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	stmt := edamame.NewQueryStatement("no-age-or-named", "", edamame.QuerySpec{
+		Where: []edamame.ConditionSpec{
+			IsNotNull("email"),
+			{Logic: "OR", Group: []edamame.ConditionSpec{
+				IsNull("age"),
+				{Field: "name", Operator: "=", Param: "name"},
+			}},
+		},
+	})
+	if params := stmt.Params(); len(params) != 1 || params[0].Name != "name" {
+		t.Errorf("expected only the name param, got %+v", params)
+	}
+
+	capture.Reset()
+	if _, err := db.ExecQuery(ctx, stmt, map[string]any{"name": "A"}); err != nil {
+		t.Fatalf("ExecQuery failed: %v", err)
+	}
+	query, ok := capture.Last()
+	if !ok {
+		t.Fatal("no query captured")
+	}
+	for _, want := range []string{`"email" IS NOT NULL`, `"age" IS NULL OR "name" = ?`} {
+		if !strings.Contains(query.Query, want) {
+			t.Errorf("expected %q in query, got: %s", want, query.Query)
+		}
+	}
+	if len(query.Args) != 1 {
+		t.Errorf("expected one arg, got %v", query.Args)
+	}
+}
@@ -0,0 +1,290 @@
+package grub
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// Driver error shapes probed by classifyConstraint. Probing by interface keeps
+// the core package free of driver dependencies.
+type (
+	// sqlStateError is satisfied by lib/pq and pgx errors.
+	sqlStateError interface{ SQLState() string }
+
+	// pgFieldError is satisfied by lib/pq errors (constraint 'n', column 'c').
+	pgFieldError interface{ Get(k byte) string }
+
+	// mssqlError is satisfied by go-mssqldb errors.
+	mssqlError interface{ SQLErrorNumber() int32 }
+
+	// sqliteError is satisfied by modernc.org/sqlite errors.
+	sqliteError interface{ Code() int }
+)
+
+// PostgreSQL SQLSTATE codes for integrity constraint violations.
+const (
+	pgNotNullViolation    = "23502"
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+)
+
+// SQLite extended result codes for constraint violations.
+const (
+	sqliteConstraintCheck      = 275
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintNotNull    = 1299
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// MySQL/MariaDB server error numbers for constraint violations.
+const (
+	mysqlDupEntry            = 1062
+	mysqlBadNull             = 1048
+	mysqlRowIsReferenced     = 1451
+	mysqlNoReferencedRow     = 1452
+	mysqlCheckConstraintFail = 3819
+)
+
+// SQL Server error numbers for constraint violations.
+const (
+	mssqlCannotInsertNull = 515
+	mssqlConstraintFailed = 547
+	mssqlDupKeyIndex      = 2601
+	mssqlDupKeyConstraint = 2627
+)
+
+var (
+	mysqlNumberPattern  = regexp.MustCompile(`\bError (\d+)(?: \([0-9A-Z]{5}\))?:`)
+	quotedNamePattern   = regexp.MustCompile(`(?i:constraint|key|index|column) ['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]`)
+	sqliteDetailPattern = regexp.MustCompile(`(?:UNIQUE|NOT NULL|CHECK) constraint failed: (.+?)(?: \(\d+\))?$`)
+)
+
+// Driver codes for transactions aborted by a serialization failure or deadlock.
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	mysqlLockDeadlock      = 1213
+	mssqlDeadlockVictim    = 1205
+	mssqlSnapshotConflict  = 3960
+)
+
+// classifyConstraint inspects a driver error and, if it represents a constraint
+// violation, wraps it in a *ConstraintError. Other errors are returned unchanged.
+// Classification never discards the original error.
+func classifyConstraint(err error) error {
+	if err == nil {
+		return nil
+	}
+	var ce *shared.ConstraintError
+	if errors.As(err, &ce) {
+		return err
+	}
+
+	if classified := classifyPostgres(err); classified != nil {
+		return classified
+	}
+	if classified := classifyMSSQL(err); classified != nil {
+		return classified
+	}
+	if classified := classifySQLite(err); classified != nil {
+		return classified
+	}
+	if classified := classifyMySQL(err); classified != nil {
+		return classified
+	}
+	return err
+}
+
+// classifyPostgres maps SQLSTATE class 23 codes.
+func classifyPostgres(err error) error {
+	var se sqlStateError
+	if !errors.As(err, &se) {
+		return nil
+	}
+	var violation error
+	switch se.SQLState() {
+	case pgUniqueViolation:
+		violation = ErrUniqueViolation
+	case pgForeignKeyViolation:
+		violation = ErrForeignKeyViolation
+	case pgCheckViolation:
+		violation = ErrCheckViolation
+	case pgNotNullViolation:
+		violation = ErrNotNullViolation
+	default:
+		return nil
+	}
+	ce := &shared.ConstraintError{Violation: violation, Err: err}
+	var fe pgFieldError
+	if errors.As(err, &fe) {
+		ce.Constraint = fe.Get('n')
+		ce.Column = fe.Get('c')
+	}
+	return ce
+}
+
+// classifyMSSQL maps SQL Server error numbers.
+func classifyMSSQL(err error) error {
+	var me mssqlError
+	if !errors.As(err, &me) {
+		return nil
+	}
+	msg := err.Error()
+	ce := &shared.ConstraintError{Err: err}
+	switch me.SQLErrorNumber() {
+	case mssqlDupKeyConstraint, mssqlDupKeyIndex:
+		ce.Violation = ErrUniqueViolation
+		ce.Constraint = quotedName(msg)
+	case mssqlConstraintFailed:
+		switch {
+		case strings.Contains(msg, "FOREIGN KEY"), strings.Contains(msg, "REFERENCE"):
+			ce.Violation = ErrForeignKeyViolation
+		case strings.Contains(msg, "CHECK"):
+			ce.Violation = ErrCheckViolation
+		default:
+			return nil
+		}
+		ce.Constraint = quotedName(msg)
+	case mssqlCannotInsertNull:
+		ce.Violation = ErrNotNullViolation
+		ce.Column = quotedName(msg)
+	default:
+		return nil
+	}
+	return ce
+}
+
+// classifySQLite maps extended result codes, falling back to the message text
+// when extended codes are not enabled.
+func classifySQLite(err error) error {
+	var violation error
+	var se sqliteError
+	if errors.As(err, &se) {
+		switch se.Code() {
+		case sqliteConstraintUnique, sqliteConstraintPrimaryKey:
+			violation = ErrUniqueViolation
+		case sqliteConstraintForeignKey:
+			violation = ErrForeignKeyViolation
+		case sqliteConstraintCheck:
+			violation = ErrCheckViolation
+		case sqliteConstraintNotNull:
+			violation = ErrNotNullViolation
+		}
+	}
+
+	msg := err.Error()
+	if violation == nil {
+		switch {
+		case strings.Contains(msg, "UNIQUE constraint failed"):
+			violation = ErrUniqueViolation
+		case strings.Contains(msg, "FOREIGN KEY constraint failed"):
+			violation = ErrForeignKeyViolation
+		case strings.Contains(msg, "CHECK constraint failed"):
+			violation = ErrCheckViolation
+		case strings.Contains(msg, "NOT NULL constraint failed"):
+			violation = ErrNotNullViolation
+		default:
+			return nil
+		}
+	}
+
+	ce := &shared.ConstraintError{Violation: violation, Err: err}
+	if m := sqliteDetailPattern.FindStringSubmatch(msg); m != nil {
+		detail := strings.TrimSpace(m[1])
+		if errors.Is(violation, ErrCheckViolation) {
+			ce.Constraint = detail
+		} else {
+			ce.Column = stripTablePrefixes(detail)
+		}
+	}
+	return ce
+}
+
+// classifyMySQL maps MySQL/MariaDB error numbers parsed from the driver message
+// ("Error 1062 (23000): Duplicate entry ...").
+func classifyMySQL(err error) error {
+	msg := err.Error()
+	m := mysqlNumberPattern.FindStringSubmatch(msg)
+	if m == nil {
+		return nil
+	}
+	number, convErr := strconv.Atoi(m[1])
+	if convErr != nil {
+		return nil
+	}
+	ce := &shared.ConstraintError{Err: err}
+	switch number {
+	case mysqlDupEntry:
+		ce.Violation = ErrUniqueViolation
+		ce.Constraint = quotedName(msg)
+	case mysqlRowIsReferenced, mysqlNoReferencedRow:
+		ce.Violation = ErrForeignKeyViolation
+		ce.Constraint = quotedName(msg)
+	case mysqlCheckConstraintFail:
+		ce.Violation = ErrCheckViolation
+		ce.Constraint = quotedName(msg)
+	case mysqlBadNull:
+		ce.Violation = ErrNotNullViolation
+		ce.Column = quotedName(msg)
+	default:
+		return nil
+	}
+	return ce
+}
+
+// classifySerialization inspects a driver error and, if the transaction was
+// aborted by a serialization failure or deadlock, wraps it so it matches
+// ErrSerializationFailure. Other errors are returned unchanged.
+func classifySerialization(err error) error {
+	if err == nil || errors.Is(err, ErrSerializationFailure) {
+		return err
+	}
+	var se sqlStateError
+	if errors.As(err, &se) {
+		switch se.SQLState() {
+		case pgSerializationFailure, pgDeadlockDetected:
+			return fmt.Errorf("%w: %w", ErrSerializationFailure, err)
+		}
+		return err
+	}
+	var me mssqlError
+	if errors.As(err, &me) {
+		switch me.SQLErrorNumber() {
+		case mssqlDeadlockVictim, mssqlSnapshotConflict:
+			return fmt.Errorf("%w: %w", ErrSerializationFailure, err)
+		}
+		return err
+	}
+	if m := mysqlNumberPattern.FindStringSubmatch(err.Error()); m != nil && m[1] == strconv.Itoa(mysqlLockDeadlock) {
+		return fmt.Errorf("%w: %w", ErrSerializationFailure, err)
+	}
+	return err
+}
+
+// quotedName extracts the first quoted constraint, key, index, or column name from msg.
+func quotedName(msg string) string {
+	if m := quotedNamePattern.FindStringSubmatch(msg); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// stripTablePrefixes turns "users.email, users.name" into "email, name".
+func stripTablePrefixes(detail string) string {
+	parts := strings.Split(detail, ",")
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		if idx := strings.LastIndexByte(p, '.'); idx >= 0 {
+			p = p[idx+1:]
+		}
+		parts[i] = p
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,320 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// fakePQError mimics lib/pq's *pq.Error.
+type fakePQError struct {
+	code       string
+	constraint string
+	column     string
+}
+
+func (e *fakePQError) Error() string    { return "pq: constraint violated" }
+func (e *fakePQError) SQLState() string { return e.code }
+func (e *fakePQError) Get(k byte) string {
+	switch k {
+	case 'n':
+		return e.constraint
+	case 'c':
+		return e.column
+	}
+	return ""
+}
+
+// fakeMSSQLError mimics go-mssqldb's mssql.Error.
+type fakeMSSQLError struct {
+	number int32
+	msg    string
+}
+
+func (e fakeMSSQLError) Error() string         { return "mssql: " + e.msg }
+func (e fakeMSSQLError) SQLErrorNumber() int32 { return e.number }
+
+// fakeSQLiteError mimics modernc.org/sqlite's *sqlite.Error.
+type fakeSQLiteError struct {
+	code int
+	msg  string
+}
+
+func (e *fakeSQLiteError) Error() string { return e.msg }
+func (e *fakeSQLiteError) Code() int     { return e.code }
+
+func TestClassifyConstraint(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		violation  error
+		constraint string
+		column     string
+	}{
+		{
+			name:       "postgres unique",
+			err:        &fakePQError{code: "23505", constraint: "users_email_key"},
+			violation:  ErrUniqueViolation,
+			constraint: "users_email_key",
+		},
+		{
+			name:       "postgres foreign key",
+			err:        &fakePQError{code: "23503", constraint: "orders_user_id_fkey"},
+			violation:  ErrForeignKeyViolation,
+			constraint: "orders_user_id_fkey",
+		},
+		{
+			name:      "postgres check",
+			err:       &fakePQError{code: "23514"},
+			violation: ErrCheckViolation,
+		},
+		{
+			name:      "postgres not null",
+			err:       &fakePQError{code: "23502", column: "name"},
+			violation: ErrNotNullViolation,
+			column:    "name",
+		},
+		{
+			name:      "sqlite unique extended code",
+			err:       &fakeSQLiteError{code: 2067, msg: "constraint failed: UNIQUE constraint failed: users.email (2067)"},
+			violation: ErrUniqueViolation,
+			column:    "email",
+		},
+		{
+			name:      "sqlite primary key extended code",
+			err:       &fakeSQLiteError{code: 1555, msg: "constraint failed: UNIQUE constraint failed: users.id (1555)"},
+			violation: ErrUniqueViolation,
+			column:    "id",
+		},
+		{
+			name:      "sqlite foreign key extended code",
+			err:       &fakeSQLiteError{code: 787, msg: "constraint failed: FOREIGN KEY constraint failed (787)"},
+			violation: ErrForeignKeyViolation,
+		},
+		{
+			name:       "sqlite check message",
+			err:        errors.New("CHECK constraint failed: age_positive"),
+			violation:  ErrCheckViolation,
+			constraint: "age_positive",
+		},
+		{
+			name:      "sqlite not null message",
+			err:       errors.New("NOT NULL constraint failed: users.name"),
+			violation: ErrNotNullViolation,
+			column:    "name",
+		},
+		{
+			name:      "sqlite composite unique message",
+			err:       errors.New("UNIQUE constraint failed: users.org_id, users.email"),
+			violation: ErrUniqueViolation,
+			column:    "org_id, email",
+		},
+		{
+			name:       "mysql duplicate entry",
+			err:        errors.New("Error 1062 (23000): Duplicate entry 'a@b.c' for key 'users.email'"),
+			violation:  ErrUniqueViolation,
+			constraint: "users.email",
+		},
+		{
+			name:       "mysql foreign key",
+			err:        errors.New("Error 1452 (23000): Cannot add or update a child row: a foreign key constraint fails (`db`.`orders`, CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))"),
+			violation:  ErrForeignKeyViolation,
+			constraint: "fk_user",
+		},
+		{
+			name:       "mysql check",
+			err:        errors.New("Error 3819 (HY000): Check constraint 'age_positive' is violated."),
+			violation:  ErrCheckViolation,
+			constraint: "age_positive",
+		},
+		{
+			name:      "mysql not null",
+			err:       errors.New("Error 1048 (23000): Column 'name' cannot be null"),
+			violation: ErrNotNullViolation,
+			column:    "name",
+		},
+		{
+			name:       "mssql unique constraint",
+			err:        fakeMSSQLError{number: 2627, msg: "Violation of UNIQUE KEY constraint 'UQ_users_email'. Cannot insert duplicate key in object 'dbo.users'."},
+			violation:  ErrUniqueViolation,
+			constraint: "UQ_users_email",
+		},
+		{
+			name:       "mssql unique index",
+			err:        fakeMSSQLError{number: 2601, msg: "Cannot insert duplicate key row in object 'dbo.users' with unique index 'IX_users_email'."},
+			violation:  ErrUniqueViolation,
+			constraint: "IX_users_email",
+		},
+		{
+			name:       "mssql foreign key",
+			err:        fakeMSSQLError{number: 547, msg: `The INSERT statement conflicted with the FOREIGN KEY constraint "FK_orders_users".`},
+			violation:  ErrForeignKeyViolation,
+			constraint: "FK_orders_users",
+		},
+		{
+			name:       "mssql check",
+			err:        fakeMSSQLError{number: 547, msg: `The INSERT statement conflicted with the CHECK constraint "CK_age".`},
+			violation:  ErrCheckViolation,
+			constraint: "CK_age",
+		},
+		{
+			name:      "mssql not null",
+			err:       fakeMSSQLError{number: 515, msg: "Cannot insert the value NULL into column 'name', table 'db.dbo.users'; column does not allow nulls."},
+			violation: ErrNotNullViolation,
+			column:    "name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyConstraint(fmt.Errorf("exec: %w", tt.err))
+
+			if !errors.Is(err, tt.violation) {
+				t.Fatalf("expected %v, got %v", tt.violation, err)
+			}
+			if !errors.Is(err, ErrConstraint) {
+				t.Error("expected ErrConstraint to match")
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("expected original driver error to remain reachable")
+			}
+
+			var ce *ConstraintError
+			if !errors.As(err, &ce) {
+				t.Fatalf("expected *ConstraintError, got %T", err)
+			}
+			if ce.Constraint != tt.constraint {
+				t.Errorf("Constraint: expected %q, got %q", tt.constraint, ce.Constraint)
+			}
+			if ce.Column != tt.column {
+				t.Errorf("Column: expected %q, got %q", tt.column, ce.Column)
+			}
+		})
+	}
+}
+
+func TestClassifyConstraint_Unclassified(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"nil", nil},
+		{"plain error", errors.New("connection refused")},
+		{"postgres other class", &fakePQError{code: "40001"}},
+		{"mssql other number", fakeMSSQLError{number: 1205, msg: "deadlock victim"}},
+		{"mysql other number", errors.New("Error 1213 (40001): Deadlock found")},
+		{"sqlite busy", &fakeSQLiteError{code: 5, msg: "database is locked (5)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyConstraint(tt.err)
+			if !errors.Is(got, tt.err) || errors.Is(got, ErrConstraint) {
+				t.Errorf("expected error to pass through unchanged, got %v", got)
+			}
+		})
+	}
+}
+
+func TestClassifySerialization(t *testing.T) {
+	retryable := []struct {
+		name string
+		err  error
+	}{
+		{"postgres serialization failure", &fakePQError{code: "40001"}},
+		{"postgres deadlock", &fakePQError{code: "40P01"}},
+		{"mssql deadlock victim", fakeMSSQLError{number: 1205, msg: "deadlock victim"}},
+		{"mssql snapshot conflict", fakeMSSQLError{number: 3960, msg: "snapshot isolation transaction aborted"}},
+		{"mysql deadlock", errors.New("Error 1213 (40001): Deadlock found when trying to get lock")},
+	}
+	for _, tt := range retryable {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifySerialization(tt.err)
+			if !errors.Is(got, ErrSerializationFailure) || !errors.Is(got, tt.err) {
+				t.Errorf("expected ErrSerializationFailure wrapping the driver error, got %v", got)
+			}
+		})
+	}
+
+	other := []struct {
+		name string
+		err  error
+	}{
+		{"nil", nil},
+		{"plain error", errors.New("connection refused")},
+		{"postgres unique", &fakePQError{code: "23505"}},
+		{"mssql other number", fakeMSSQLError{number: 2627, msg: "duplicate key"}},
+		{"mysql lock wait timeout", errors.New("Error 1205 (HY000): Lock wait timeout exceeded")},
+	}
+	for _, tt := range other {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifySerialization(tt.err)
+			if got != tt.err {
+				t.Errorf("expected error to pass through unchanged, got %v", got)
+			}
+		})
+	}
+}
+
+func TestDatabase_SerializationFailure(t *testing.T) {
+	mockDB, _, cfg := mockdb.NewWithConfig()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	driverErr := &fakePQError{code: "40001"}
+	cfg.SetQueryErr(driverErr)
+	cfg.SetExecErr(driverErr)
+
+	err = db.Set(context.Background(), "1", &TestDBUser{ID: 1, Email: "a@example.com", Name: "A"})
+	if !errors.Is(err, ErrSerializationFailure) {
+		t.Errorf("expected ErrSerializationFailure, got %v", err)
+	}
+	if errors.Is(err, ErrConstraint) {
+		t.Error("serialization failure should not match ErrConstraint")
+	}
+}
+
+func TestConstraintError_UniqueMatchesDuplicate(t *testing.T) {
+	unique := classifyConstraint(&fakePQError{code: "23505"})
+	if !errors.Is(unique, ErrDuplicate) {
+		t.Error("expected unique violation to match ErrDuplicate")
+	}
+
+	fk := classifyConstraint(&fakePQError{code: "23503"})
+	if errors.Is(fk, ErrDuplicate) {
+		t.Error("foreign key violation should not match ErrDuplicate")
+	}
+}
+
+func TestDatabase_Set_ConstraintViolation(t *testing.T) {
+	mockDB, _, cfg := mockdb.NewWithConfig()
+	ctx := context.Background()
+
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	driverErr := &fakePQError{code: "23505", constraint: "test_users_email_key"}
+	cfg.SetQueryErr(driverErr)
+	cfg.SetExecErr(driverErr)
+	defer cfg.Reset()
+
+	err = db.Set(ctx, "1", &TestDBUser{ID: 1, Email: "dup@example.com", Name: "Dup"})
+	if !errors.Is(err, ErrUniqueViolation) {
+		t.Fatalf("expected ErrUniqueViolation, got %v", err)
+	}
+
+	var gerr *Error
+	if !errors.As(err, &gerr) || gerr.Op != "set" {
+		t.Errorf("expected *Error with op 'set', got %v", err)
+	}
+	var ce *ConstraintError
+	if !errors.As(err, &ce) || ce.Constraint != "test_users_email_key" {
+		t.Errorf("expected constraint name, got %+v", ce)
+	}
+}
@@ -2,15 +2,20 @@ package grub
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/zoobzio/astql"
 	"github.com/zoobzio/atom"
 	"github.com/zoobzio/edamame"
 	atomic "github.com/zoobzio/grub/internal/atomic"
+	"github.com/zoobzio/grub/internal/shared"
 	"github.com/zoobzio/soy"
 )
 
@@ -26,11 +31,35 @@ var (
 // Database provides type-safe SQL storage operations for T.
 // Uses edamame internally for query building and execution.
 type Database[T any] struct {
+	db         *sqlx.DB
+	raw        *sqlx.DB // db as passed to NewDatabase, before wrapPool
 	executor   *edamame.Executor[T]
 	keyCol     string
+	keyType    reflect.Type
+	keyIndex   []int
+	keyGen     KeyGenerator // nil unless WithKeyGenerator is set
+	existsSQL  string
 	tableName  string
+	timeout    time.Duration
+	statements *statementRegistry
+	cache      *queryCache // nil unless WithQueryCache is set
+	redact     *redaction
+	replicas   *replicaSet[T]      // nil unless WithReadReplicas is set
+	stmts      *stmtCache          // nil unless WithStatementCache is set
 	atomic     *atomic.Database[T] // lazily created via Atomic()
-	atomicOnce sync.Once
+	atomicOnce *sync.Once
+	history    History // empty unless WithHistory is set
+	dialect    string
+	outbox     *outbox[T]  // nil unless WithOutbox is set
+	retry      *writeRetry // nil unless WithWriteRetry is set
+	batchRows  int         // SetBatch rows per statement; 0 uses the default
+	beforeSave func(ctx context.Context, record *T) error
+	stamps     *timestamps // nil unless T has timestamp fields and WithoutTimestamps is unset
+	flights    *dbFlights  // nil unless WithSingleflight is set
+	renderer   astql.Renderer
+	readOnly   bool
+	writes     *soy.Soy[T] // write builders; rejects every statement in a ReadOnly view
+	logQueries bool        // WithQueryLogger is set
 }
 
 // findPrimaryKey inspects the struct metadata and returns the db column name
@@ -66,11 +95,33 @@ func findPrimaryKey[T any](exec *edamame.Executor[T]) (string, error) {
 	return keyCol, nil
 }
 
+// wrapPool applies WithStatementCache and WithQueryLogger to db. Statements
+// are logged before reaching the cache, so the logger sees every call.
+func wrapPool(db *sqlx.DB, o options) (*sqlx.DB, *stmtCache) {
+	var stmts *stmtCache
+	if o.stmtCache {
+		db, stmts = preparedDB(db, o.stmtCacheSize)
+	}
+	if o.queryLogger != nil {
+		db = loggedDB(db, queryReporter{fn: o.queryLogger, params: o.logParams})
+	}
+	return db, stmts
+}
+
 // NewDatabase creates a Database for type T.
 // The primary key column is derived from the struct field tagged with constraints:"primarykey".
+// The renderer quotes identifiers for its dialect, so it must match the
+// driver: pairing astql/postgres with a MySQL connection, say, fails with
+// ErrDialectMismatch.
 // Use the *Tx method variants (GetTx, SetTx, etc.) for transaction support.
-func NewDatabase[T any](db *sqlx.DB, table string, renderer astql.Renderer) (*Database[T], error) {
-	exec, err := edamame.New[T](db, table, renderer)
+func NewDatabase[T any](db *sqlx.DB, table string, renderer astql.Renderer, opts ...Option) (*Database[T], error) {
+	if err := checkDialect(db, renderer); err != nil {
+		return nil, err
+	}
+	o := applyOptions(opts)
+	raw := db
+	db, stmts := wrapPool(db, o)
+	exec, err := edamame.New[T](db, table, inRenderer{renderer})
 	if err != nil {
 		return nil, err
 	}
@@ -79,37 +130,265 @@ func NewDatabase[T any](db *sqlx.DB, table string, renderer astql.Renderer) (*Da
 	if err != nil {
 		return nil, err
 	}
+	existsSQL, err := existsQuery(exec, keyCol, renderer)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Database[T]{
+		db:         db,
+		raw:        raw,
+		executor:   exec,
+		keyCol:     keyCol,
+		keyType:    keyFieldType(exec, keyCol),
+		keyIndex:   keyFieldIndex(exec, keyCol),
+		keyGen:     o.keyGen,
+		existsSQL:  existsSQL,
+		tableName:  table,
+		timeout:    o.timeout,
+		history:    o.history,
+		batchRows:  o.maxBatchSize,
+		retry:      newWriteRetry(o),
+		dialect:    rendererDialect(renderer),
+		redact:     newRedaction[T](o),
+		stmts:      stmts,
+		renderer:   inRenderer{renderer},
+		statements: &statementRegistry{},
+		logQueries: o.queryLogger != nil,
+		atomicOnce: new(sync.Once),
+	}
+	if o.singleflight {
+		d.flights = &dbFlights{}
+	}
+	if d.outbox, err = newOutbox[T](o, table, d.dialect); err != nil {
+		return nil, err
+	}
+	if d.keyGen != nil {
+		if err := checkKeyGenerator(d.keyType, keyCol); err != nil {
+			return nil, err
+		}
+	}
 
 	// Register lifecycle hook callbacks on the soy instance so hooks
 	// fire through both wrapper methods and direct builder paths.
+	// Timestamps are filled first so BeforeSave can still override them.
+	stamps := newTimestamps[T](o)
+	d.stamps = stamps
+	d.beforeSave = func(ctx context.Context, record *T) error {
+		stamps.stamp(record)
+		if err := callBeforeSave(ctx, record); err != nil {
+			return err
+		}
+		return d.redact.check(record)
+	}
 	s := exec.Soy()
-	s.OnScan(callAfterLoad)
-	s.OnRecord(callBeforeSave)
-
-	return &Database[T]{
-		executor:  exec,
-		keyCol:    keyCol,
-		tableName: table,
-	}, nil
+	s.OnScan(d.afterLoad)
+	s.OnRecord(d.beforeSave)
+	d.writes = s
+	if o.cacheStore != nil {
+		d.cache = newQueryCache(o.cacheStore, o.cacheTTL, table)
+	}
+	if len(o.readReplicas) > 0 {
+		if d.replicas, err = newReplicaSet[T](o.readReplicas, table, renderer, o, d.afterLoad); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.RegisterQuery(QueryAll); err != nil {
+		return nil, err
+	}
+	if err := d.RegisterAggregate(CountAll); err != nil {
+		return nil, err
+	}
+	return d, nil
 }
 
 // Get retrieves the record at key as T.
 // Returns ErrNotFound if the key does not exist.
+// With WithSingleflight, concurrent Gets for key share one query; each
+// caller decodes its own copy of the record's db-mapped fields, sharing no
+// maps, slices, or pointers with the others, and passes it through
+// AfterLoad with its own ctx.
 func (d *Database[T]) Get(ctx context.Context, key string) (*T, error) {
-	result, err := d.executor.Soy().Select().
-		Where(d.keyCol, "=", "key").
-		Exec(ctx, map[string]any{"key": key})
+	if !d.coalesce(ctx) {
+		return d.get(ctx, key)
+	}
+	data, err := d.flights.get.do(ctx, key, func(fetchCtx context.Context) ([]byte, error) {
+		record, err := d.get(context.WithValue(fetchCtx, sharedLoadKey{}, true), key)
+		if err != nil {
+			return nil, err
+		}
+		data, err := d.encodeRows([]*T{record})
+		if err != nil {
+			// A record gob cannot encode cannot be copied; each caller
+			// reads it for itself instead.
+			return nil, nil
+		}
+		return data, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			return nil, d.wrapErr("get", key, err)
+		}
+		return nil, err
+	}
+	if data == nil {
+		return d.get(ctx, key)
+	}
+	records, err := d.decodeRows(data)
+	if err != nil {
+		return nil, d.wrapErr("get", key, err)
+	}
+	if err := d.afterLoad(ctx, records[0]); err != nil {
+		return nil, d.wrapErr("get", key, err)
+	}
+	return records[0], nil
+}
+
+// get runs the Get query for key.
+func (d *Database[T]) get(ctx context.Context, key string) (*T, error) {
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	result, err := read(callCtx, d, func(c conn[T]) (*T, error) {
+		return c.executor.Soy().Select().
+			Where(d.keyCol, "=", "key").
+			Exec(callCtx, map[string]any{"key": key})
+	})
 	if err != nil {
 		if errors.Is(err, soy.ErrNotFound) {
-			return nil, ErrNotFound
+			err = ErrNotFound
 		}
+		return nil, d.wrapErr("get", key, err)
+	}
+	return result, nil
+}
+
+// batchChunkSize bounds the keys bound into a single GetBatch query, keeping
+// well under driver parameter limits (SQL Server allows 2100).
+const batchChunkSize = 1000
+
+// GetBatch retrieves the records at keys, issuing one SELECT per 1000
+// distinct keys. Missing keys are omitted from the result. Result entries are
+// keyed by the primary key value formatted with fmt.Sprint, which matches the
+// key strings accepted by Get for integer and string keys.
+func (d *Database[T]) GetBatch(ctx context.Context, keys []string) (map[string]*T, error) {
+	records, err := d.selectIn(ctx, "get_batch", d.keyCol, keys)
+	if err != nil {
 		return nil, err
 	}
+	result := make(map[string]*T, len(records))
+	for _, rec := range records {
+		result[d.columnValue(rec, d.keyCol)] = rec
+	}
 	return result, nil
 }
 
+// selectIn returns every row whose column matches one of values. Values are
+// deduplicated and matched with a single IN list, one placeholder per value;
+// large sets are split into chunks of batchChunkSize.
+func (d *Database[T]) selectIn(ctx context.Context, op, column string, values []string) ([]*T, error) {
+	seen := make(map[string]struct{}, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		unique = append(unique, v)
+	}
+
+	stmt := edamame.NewQueryStatement(op, "", edamame.QuerySpec{
+		Where: []edamame.ConditionSpec{{Field: column, Operator: "IN", Param: "in"}},
+	})
+	var records []*T
+	for start := 0; start < len(unique); start += batchChunkSize {
+		params := map[string]any{"in": unique[start:min(start+batchChunkSize, len(unique))]}
+		callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+		rows, err := read(callCtx, d, func(c conn[T]) ([]*T, error) {
+			return d.runQuery(callCtx, c, nil, stmt, params)
+		})
+		cancel()
+		if err != nil {
+			return nil, d.wrapErr(op, "", err)
+		}
+		records = append(records, rows...)
+	}
+	return records, nil
+}
+
+// columnValue returns the value of the field mapped to column in rec,
+// formatted with fmt.Sprint. Nil pointers format as the empty string.
+func (d *Database[T]) columnValue(rec *T, column string) string {
+	v := reflect.ValueOf(rec).Elem()
+	for _, field := range d.executor.Soy().Metadata().Fields {
+		if field.Tags["db"] != column {
+			continue
+		}
+		fv := v.FieldByIndex(field.Index)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				return ""
+			}
+			fv = fv.Elem()
+		}
+		return fmt.Sprint(fv.Interface())
+	}
+	return ""
+}
+
 // Set stores value at key (insert or update via upsert).
-func (d *Database[T]) Set(ctx context.Context, _ string, value *T) error {
+// With WithKeyGenerator, an empty key and a zero primary key field make Set
+// generate the key into value and insert it without the upsert, so a
+// colliding key fails with ErrDuplicate instead of overwriting a row.
+// Transient conflicts are retried under WithWriteRetry.
+func (d *Database[T]) Set(ctx context.Context, key string, value *T) error {
+	if d.readOnly {
+		return d.wrapErr("set", key, ErrReadOnly)
+	}
+	return d.retry.do(ctx, func() error {
+		return d.set(ctx, "set", nil, key, value)
+	})
+}
+
+// set implements Set and SetTx, running outside a transaction when tx is
+// nil unless WithOutbox needs one.
+func (d *Database[T]) set(ctx context.Context, op string, tx *sqlx.Tx, key string, value *T) error {
+	if tx == nil && d.outbox != nil {
+		return d.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			return d.set(ctx, op, tx, key, value)
+		}, nil)
+	}
+	if key == "" {
+		generated, err := d.generateKey(value)
+		if err != nil {
+			return d.wrapErr(op, key, err)
+		}
+		if generated {
+			_, _, err := d.create(ctx, op, tx, value)
+			return err
+		}
+	}
+	insert := d.upsert()
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	var err error
+	if tx != nil {
+		_, err = insert.ExecTx(callCtx, tx, value)
+	} else {
+		_, err = insert.Exec(callCtx, value)
+	}
+	d.invalidateCache(ctx, tx)
+	if err == nil {
+		err = d.outbox.record(callCtx, tx, op, d.columnValue(value, d.keyCol), value)
+	}
+	if err != nil {
+		return d.wrapErr(op, key, err)
+	}
+	return callAfterSave(ctx, value)
+}
+
+// upsert returns the INSERT ... ON CONFLICT statement Set runs, updating
+// every column but the key.
+func (d *Database[T]) upsert() *soy.Create[T] {
 	s := d.executor.Soy()
 	// Use InsertFull to include PK in the INSERT for proper ON CONFLICT matching
 	insert := s.InsertFull().OnConflict(d.keyCol).DoUpdate()
@@ -121,41 +400,219 @@ func (d *Database[T]) Set(ctx context.Context, _ string, value *T) error {
 		}
 		insert = insert.Set(col, col)
 	}
+	return insert.Build()
+}
+
+// removeKey returns the DELETE statement Delete runs for key and its params.
+func (d *Database[T]) removeKey(key string) (*soy.Delete[T], map[string]any) {
+	return d.executor.Soy().Remove().Where(d.keyCol, "=", "key"), map[string]any{"key": key}
+}
+
+// SetIfChanged stores record at key only if it differs from the current row.
+// Columns are compared field by field using the struct metadata against the
+// row as stored, without redaction or AfterLoad; a missing row is always
+// written. Returns true if a write occurred. BeforeSave and AfterSave
+// fire only when a write occurs. The read and write are separate statements;
+// use SetIfChangedTx when they must be atomic.
+func (d *Database[T]) SetIfChanged(ctx context.Context, key string, record *T) (bool, error) {
+	if d.readOnly {
+		return false, d.wrapErr("set_if_changed", key, ErrReadOnly)
+	}
+	current, err := d.Get(withRawLoad(WithPrimaryReads(ctx)), key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+	if err == nil && d.sameColumns(current, record) {
+		return false, nil
+	}
+	if err := d.Set(ctx, key, record); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// InsertReturning inserts record without its primary key and returns the
+// stored row, with the generated key and any column defaults populated
+// from the dialect's RETURNING (or OUTPUT) clause and AfterLoad applied.
+// Returns ErrKeyNotGenerated if the table did not generate a key.
+// WithOutbox records the insert as a "create" of the returned row.
+func (d *Database[T]) InsertReturning(ctx context.Context, record *T) (*T, error) {
+	if d.readOnly {
+		return nil, d.wrapErr("insert_returning", "", ErrReadOnly)
+	}
+	return d.insertReturning(ctx, "insert_returning", nil, record)
+}
+
+// insertReturning implements InsertReturning and InsertReturningTx, running
+// outside a transaction when tx is nil unless WithOutbox needs one.
+func (d *Database[T]) insertReturning(ctx context.Context, op string, tx *sqlx.Tx, record *T) (*T, error) {
+	if tx == nil && d.outbox != nil {
+		var inserted *T
+		err := d.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			var err error
+			inserted, err = d.insertReturning(ctx, op, tx, record)
+			return err
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		return inserted, nil
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	insert := d.executor.Soy().Insert()
+	var inserted *T
+	var err error
+	if tx != nil {
+		inserted, err = insert.ExecTx(callCtx, tx, record)
+	} else {
+		inserted, err = insert.Exec(callCtx, record)
+	}
+	d.invalidateCache(ctx, tx)
+	return d.checkInserted(ctx, op, tx, inserted, err)
+}
 
-	_, err := insert.Build().Exec(ctx, value)
+// checkInserted wraps an insert error and rejects a returned row whose key
+// is NULL or zero, which means the table has no default for its primary key.
+// An accepted row is recorded to the outbox as a "create".
+func (d *Database[T]) checkInserted(ctx context.Context, op string, tx *sqlx.Tx, inserted *T, err error) (*T, error) {
+	notGenerated := fmt.Errorf("%w: column %q is not auto-generated", ErrKeyNotGenerated, d.keyCol)
 	if err != nil {
-		return err
+		// A NULL key fails to scan into a non-pointer field before it can be checked.
+		if strings.Contains(err.Error(), fmt.Sprintf("name %q: converting NULL", d.keyCol)) {
+			err = notGenerated
+		}
+		return nil, d.wrapErr(op, "", err)
 	}
-	return callAfterSave(ctx, value)
+	key := d.columnValue(inserted, d.keyCol)
+	if key == "" || key == "0" {
+		return nil, d.wrapErr(op, "", notGenerated)
+	}
+	if err := d.outbox.record(ctx, tx, "create", key, inserted); err != nil {
+		return nil, d.wrapErr(op, key, err)
+	}
+	if err := callAfterSave(ctx, inserted); err != nil {
+		return nil, err
+	}
+	return inserted, nil
 }
 
-// Delete removes the record at key.
+// Delete removes the record at key. Transient conflicts are retried under
+// WithWriteRetry.
 func (d *Database[T]) Delete(ctx context.Context, key string) error {
+	if d.readOnly {
+		return d.wrapErr("delete", key, ErrReadOnly)
+	}
+	return d.retry.do(ctx, func() error {
+		return d.delete(ctx, "delete", nil, key)
+	})
+}
+
+// delete implements Delete and DeleteTx, running outside a transaction
+// when tx is nil unless WithOutbox needs one.
+func (d *Database[T]) delete(ctx context.Context, op string, tx *sqlx.Tx, key string) error {
+	if tx == nil && d.outbox != nil {
+		return d.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			return d.delete(ctx, op, tx, key)
+		}, nil)
+	}
 	if err := callBeforeDelete[T](ctx); err != nil {
 		return err
 	}
-	affected, err := d.executor.Soy().Remove().
-		Where(d.keyCol, "=", "key").
-		Exec(ctx, map[string]any{"key": key})
-	if err != nil {
-		return err
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	remove, params := d.removeKey(key)
+	var affected int64
+	var err error
+	if tx != nil {
+		affected, err = remove.ExecTx(callCtx, tx, params)
+	} else {
+		affected, err = remove.Exec(callCtx, params)
+	}
+	d.invalidateCache(ctx, tx)
+	if err == nil && affected == 0 {
+		err = ErrNotFound
 	}
-	if affected == 0 {
-		return ErrNotFound
+	if err == nil {
+		err = d.outbox.record(callCtx, tx, op, key, nil)
+	}
+	if err != nil {
+		return d.wrapErr(op, key, err)
 	}
 	return callAfterDelete[T](ctx)
 }
 
 // Exists checks whether a record exists at key.
+// With WithSingleflight, concurrent Exists calls for key share one query.
 func (d *Database[T]) Exists(ctx context.Context, key string) (bool, error) {
-	results, err := d.executor.Soy().Query().
-		Where(d.keyCol, "=", "key").
-		Limit(1).
-		Exec(ctx, map[string]any{"key": key})
+	if !d.coalesce(ctx) {
+		return d.existsKey(ctx, key)
+	}
+	exists, err := d.flights.exists.do(ctx, key, func(fetchCtx context.Context) (bool, error) {
+		return d.existsKey(fetchCtx, key)
+	})
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return false, d.wrapErr("exists", key, err)
+	}
+	return exists, err
+}
+
+// existsKey runs the Exists query for key.
+func (d *Database[T]) existsKey(ctx context.Context, key string) (bool, error) {
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	exists, err := read(callCtx, d, func(c conn[T]) (bool, error) {
+		return d.exists(callCtx, c.execer(nil), key)
+	})
+	if err != nil {
+		return false, d.wrapErr("exists", key, err)
+	}
+	return exists, nil
+}
+
+// existsQuery renders the statement Exists runs, selecting a single
+// boolean so no columns of the row come back and the planner can stop at
+// the first index match. SQL Server cannot select a bare EXISTS, so it and
+// custom renderers get the portable CASE form.
+func existsQuery[T any](exec *edamame.Executor[T], keyCol string, renderer astql.Renderer) (string, error) {
+	inner, err := exec.Soy().Query().Fields(keyCol).Where(keyCol, "=", "key").Render()
+	if err != nil {
+		return "", err
+	}
+	switch rendererDialect(renderer) {
+	case "postgres", "sqlite", "mariadb":
+		return "SELECT EXISTS (" + inner.SQL + ")", nil
+	}
+	return "SELECT CASE WHEN EXISTS (" + inner.SQL + ") THEN 1 ELSE 0 END", nil
+}
+
+// exists runs the EXISTS query for key on execer.
+func (d *Database[T]) exists(ctx context.Context, execer sqlx.ExtContext, key string) (bool, error) {
+	rows, err := sqlx.NamedQueryContext(ctx, execer, d.existsSQL, map[string]any{"key": key})
 	if err != nil {
 		return false, err
 	}
-	return len(results) > 0, nil
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	var exists bool
+	if err := rows.Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, rows.Err()
+}
+
+// Table returns the table name this database manages.
+func (d *Database[T]) Table() string {
+	return d.tableName
+}
+
+// DB returns the connection pool passed to NewDatabase, for driver features
+// grub does not cover. Statements run on it bypass WithQueryLogger,
+// WithStatementCache, hooks, and the outbox.
+func (d *Database[T]) DB() *sqlx.DB {
+	return d.raw
 }
 
 // Executor returns the underlying edamame Executor for advanced query operations.
@@ -175,144 +632,420 @@ func (d *Database[T]) Select() *soy.Select[T] {
 
 // Insert returns an insert builder (auto-generates PK).
 func (d *Database[T]) Insert() *soy.Create[T] {
-	return d.executor.Soy().Insert()
+	return d.writes.Insert()
 }
 
 // InsertFull returns an insert builder that includes the PK field.
 func (d *Database[T]) InsertFull() *soy.Create[T] {
-	return d.executor.Soy().InsertFull()
+	return d.writes.InsertFull()
 }
 
 // Modify returns an update builder.
 func (d *Database[T]) Modify() *soy.Update[T] {
-	return d.executor.Soy().Modify()
+	return d.writes.Modify()
 }
 
 // Remove returns a delete builder.
 func (d *Database[T]) Remove() *soy.Delete[T] {
-	return d.executor.Soy().Remove()
+	return d.writes.Remove()
 }
 
 // ExecQuery executes a query statement and returns multiple records.
+// With WithQueryCache, results are served from the cache when present.
+// Records are cached as scanned, before AfterLoad, and decoded afresh on
+// each hit, so AfterLoad runs exactly once per returned record.
 func (d *Database[T]) ExecQuery(ctx context.Context, stmt edamame.QueryStatement, params map[string]any) ([]*T, error) {
-	return d.executor.ExecQuery(ctx, stmt, params)
+	render := func() (string, error) { return d.executor.RenderQuery(stmt) }
+	if err := checkParams(stmt, params, render); err != nil {
+		return nil, d.wrapErr("exec_query", "", err)
+	}
+	key, data, hit := d.cache.lookup(ctx, "query", stmt.Name(), render, params)
+	if hit {
+		if cached, err := d.decodeRows(data); err == nil {
+			for _, rec := range cached {
+				d.redact.apply(rec)
+			}
+			if err := callAfterLoadSlice(ctx, cached); err != nil {
+				return nil, err
+			}
+			return cached, nil
+		}
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	loadCtx := d.cacheLoad(callCtx, key)
+	result, err := read(loadCtx, d, func(c conn[T]) ([]*T, error) {
+		return d.runQuery(loadCtx, c, nil, stmt, params)
+	})
+	if err != nil {
+		return nil, d.wrapErr("exec_query", "", err)
+	}
+	if key != "" {
+		d.cacheRows(ctx, key, result)
+		if err := callAfterLoadSlice(ctx, result); err != nil {
+			return nil, d.wrapErr("exec_query", "", err)
+		}
+	}
+	return result, nil
 }
 
 // ExecSelect executes a select statement and returns a single record.
+// Cached like ExecQuery when WithQueryCache is set; errors are not cached.
 func (d *Database[T]) ExecSelect(ctx context.Context, stmt edamame.SelectStatement, params map[string]any) (*T, error) {
-	return d.executor.ExecSelect(ctx, stmt, params)
+	render := func() (string, error) { return d.executor.RenderSelect(stmt) }
+	if err := checkParams(stmt, params, render); err != nil {
+		return nil, d.wrapErr("exec_select", "", err)
+	}
+	key, data, hit := d.cache.lookup(ctx, "select", stmt.Name(), render, params)
+	if hit {
+		if cached, err := d.decodeRows(data); err == nil && len(cached) == 1 {
+			if err := d.afterLoad(ctx, cached[0]); err != nil {
+				return nil, err
+			}
+			return cached[0], nil
+		}
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	loadCtx := d.cacheLoad(callCtx, key)
+	result, err := read(loadCtx, d, func(c conn[T]) (*T, error) {
+		return d.runSelect(loadCtx, c, nil, stmt, params)
+	})
+	if err != nil {
+		return nil, d.wrapErr("exec_select", "", err)
+	}
+	if key != "" {
+		d.cacheRows(ctx, key, []*T{result})
+		if err := callAfterLoad(ctx, result); err != nil {
+			return nil, d.wrapErr("exec_select", "", err)
+		}
+	}
+	return result, nil
 }
 
 // ExecUpdate executes an update statement.
 func (d *Database[T]) ExecUpdate(ctx context.Context, stmt edamame.UpdateStatement, params map[string]any) (*T, error) {
-	return d.executor.ExecUpdate(ctx, stmt, params)
+	if d.readOnly {
+		return nil, d.wrapErr("exec_update", "", ErrReadOnly)
+	}
+	if err := checkParams(stmt, params, func() (string, error) { return d.executor.RenderUpdate(stmt) }); err != nil {
+		return nil, d.wrapErr("exec_update", "", err)
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	result, err := d.executor.ExecUpdate(callCtx, stmt, params)
+	d.cache.invalidate(ctx)
+	if err != nil {
+		return nil, d.wrapErr("exec_update", "", err)
+	}
+	return result, nil
 }
 
-// ExecAggregate executes an aggregate statement.
+// ExecAggregate executes an aggregate statement (COUNT, SUM, AVG, MIN, or MAX).
+// The result is always a float64, so AVG over an integer column keeps its
+// fractional part; an aggregate over no rows returns 0.
+// Cached like ExecQuery when WithQueryCache is set.
 func (d *Database[T]) ExecAggregate(ctx context.Context, stmt edamame.AggregateStatement, params map[string]any) (float64, error) {
-	return d.executor.ExecAggregate(ctx, stmt, params)
+	render := func() (string, error) { return d.executor.RenderAggregate(stmt) }
+	if err := checkParams(stmt, params, render); err != nil {
+		return 0, d.wrapErr("exec_aggregate", "", err)
+	}
+	key, data, hit := d.cache.lookup(ctx, "aggregate", stmt.Name(), render, params)
+	if hit {
+		var cached float64
+		if json.Unmarshal(data, &cached) == nil {
+			return cached, nil
+		}
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	result, err := read(callCtx, d, func(c conn[T]) (float64, error) {
+		return d.runAggregate(callCtx, c, nil, stmt, params)
+	})
+	if err != nil {
+		return 0, d.wrapErr("exec_aggregate", "", err)
+	}
+	d.cache.put(ctx, key, result)
+	return result, nil
 }
 
 // GetTx retrieves the record at key as T within a transaction.
 // Returns ErrNotFound if the key does not exist.
 func (d *Database[T]) GetTx(ctx context.Context, tx *sqlx.Tx, key string) (*T, error) {
+	if tx == nil {
+		return nil, d.wrapErr("get_tx", key, ErrNilTransaction)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
 	result, err := d.executor.Soy().Select().
 		Where(d.keyCol, "=", "key").
-		ExecTx(ctx, tx, map[string]any{"key": key})
+		ExecTx(callCtx, tx, map[string]any{"key": key})
 	if err != nil {
 		if errors.Is(err, soy.ErrNotFound) {
-			return nil, ErrNotFound
+			err = ErrNotFound
 		}
-		return nil, err
+		return nil, d.wrapErr("get_tx", key, err)
 	}
 	return result, nil
 }
 
-// SetTx stores value at key within a transaction (insert or update via upsert).
-func (d *Database[T]) SetTx(ctx context.Context, tx *sqlx.Tx, _ string, value *T) error {
-	s := d.executor.Soy()
-	insert := s.InsertFull().OnConflict(d.keyCol).DoUpdate()
+// SetTx stores value at key within a transaction (insert or update via
+// upsert), generating an empty key as Set does.
+func (d *Database[T]) SetTx(ctx context.Context, tx *sqlx.Tx, key string, value *T) error {
+	if d.readOnly {
+		return d.wrapErr("set_tx", key, ErrReadOnly)
+	}
+	if tx == nil {
+		return d.wrapErr("set_tx", key, ErrNilTransaction)
+	}
+	return d.set(ctx, "set_tx", tx, key, value)
+}
 
-	for _, field := range s.Metadata().Fields {
-		col := field.Tags["db"]
-		if col == "" || col == "-" || col == d.keyCol {
-			continue
-		}
-		insert = insert.Set(col, col)
+// InsertReturningTx is InsertReturning within a transaction.
+func (d *Database[T]) InsertReturningTx(ctx context.Context, tx *sqlx.Tx, record *T) (*T, error) {
+	if d.readOnly {
+		return nil, d.wrapErr("insert_returning_tx", "", ErrReadOnly)
 	}
+	if tx == nil {
+		return nil, d.wrapErr("insert_returning_tx", "", ErrNilTransaction)
+	}
+	return d.insertReturning(ctx, "insert_returning_tx", tx, record)
+}
 
-	_, err := insert.Build().ExecTx(ctx, tx, value)
-	if err != nil {
-		return err
+// SetIfChangedTx is SetIfChanged within a transaction.
+func (d *Database[T]) SetIfChangedTx(ctx context.Context, tx *sqlx.Tx, key string, record *T) (bool, error) {
+	if d.readOnly {
+		return false, d.wrapErr("set_if_changed_tx", key, ErrReadOnly)
 	}
-	return callAfterSave(ctx, value)
+	if tx == nil {
+		return false, d.wrapErr("set_if_changed_tx", key, ErrNilTransaction)
+	}
+	current, err := d.GetTx(withRawLoad(ctx), tx, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+	if err == nil && d.sameColumns(current, record) {
+		return false, nil
+	}
+	if err := d.SetTx(ctx, tx, key, record); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // DeleteTx removes the record at key within a transaction.
 func (d *Database[T]) DeleteTx(ctx context.Context, tx *sqlx.Tx, key string) error {
-	if err := callBeforeDelete[T](ctx); err != nil {
-		return err
-	}
-	affected, err := d.executor.Soy().Remove().
-		Where(d.keyCol, "=", "key").
-		ExecTx(ctx, tx, map[string]any{"key": key})
-	if err != nil {
-		return err
+	if d.readOnly {
+		return d.wrapErr("delete_tx", key, ErrReadOnly)
 	}
-	if affected == 0 {
-		return ErrNotFound
+	if tx == nil {
+		return d.wrapErr("delete_tx", key, ErrNilTransaction)
 	}
-	return callAfterDelete[T](ctx)
+	return d.delete(ctx, "delete_tx", tx, key)
 }
 
 // ExistsTx checks whether a record exists at key within a transaction.
 func (d *Database[T]) ExistsTx(ctx context.Context, tx *sqlx.Tx, key string) (bool, error) {
-	results, err := d.executor.Soy().Query().
-		Where(d.keyCol, "=", "key").
-		Limit(1).
-		ExecTx(ctx, tx, map[string]any{"key": key})
+	if tx == nil {
+		return false, d.wrapErr("exists_tx", key, ErrNilTransaction)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	exists, err := d.exists(callCtx, tx, key)
 	if err != nil {
-		return false, err
+		return false, d.wrapErr("exists_tx", key, err)
 	}
-	return len(results) > 0, nil
+	return exists, nil
 }
 
 // ExecQueryTx executes a query statement within a transaction and returns multiple records.
 func (d *Database[T]) ExecQueryTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.QueryStatement, params map[string]any) ([]*T, error) {
-	return d.executor.ExecQueryTx(ctx, tx, stmt, params)
+	if tx == nil {
+		return nil, d.wrapErr("exec_query_tx", "", ErrNilTransaction)
+	}
+	if err := checkParams(stmt, params, func() (string, error) { return d.executor.RenderQuery(stmt) }); err != nil {
+		return nil, d.wrapErr("exec_query_tx", "", err)
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	result, err := d.runQuery(callCtx, d.primary(), tx, stmt, params)
+	if err != nil {
+		return nil, d.wrapErr("exec_query_tx", "", err)
+	}
+	return result, nil
 }
 
 // ExecSelectTx executes a select statement within a transaction and returns a single record.
 func (d *Database[T]) ExecSelectTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.SelectStatement, params map[string]any) (*T, error) {
-	return d.executor.ExecSelectTx(ctx, tx, stmt, params)
+	if tx == nil {
+		return nil, d.wrapErr("exec_select_tx", "", ErrNilTransaction)
+	}
+	if err := checkParams(stmt, params, func() (string, error) { return d.executor.RenderSelect(stmt) }); err != nil {
+		return nil, d.wrapErr("exec_select_tx", "", err)
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	result, err := d.runSelect(callCtx, d.primary(), tx, stmt, params)
+	if err != nil {
+		return nil, d.wrapErr("exec_select_tx", "", err)
+	}
+	return result, nil
 }
 
 // ExecUpdateTx executes an update statement within a transaction.
 func (d *Database[T]) ExecUpdateTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.UpdateStatement, params map[string]any) (*T, error) {
-	return d.executor.ExecUpdateTx(ctx, tx, stmt, params)
+	if d.readOnly {
+		return nil, d.wrapErr("exec_update_tx", "", ErrReadOnly)
+	}
+	if tx == nil {
+		return nil, d.wrapErr("exec_update_tx", "", ErrNilTransaction)
+	}
+	if err := checkParams(stmt, params, func() (string, error) { return d.executor.RenderUpdate(stmt) }); err != nil {
+		return nil, d.wrapErr("exec_update_tx", "", err)
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	result, err := d.executor.ExecUpdateTx(callCtx, tx, stmt, params)
+	d.invalidateCache(ctx, tx)
+	if err != nil {
+		return nil, d.wrapErr("exec_update_tx", "", err)
+	}
+	return result, nil
 }
 
 // ExecAggregateTx executes an aggregate statement within a transaction.
 func (d *Database[T]) ExecAggregateTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.AggregateStatement, params map[string]any) (float64, error) {
-	return d.executor.ExecAggregateTx(ctx, tx, stmt, params)
+	if tx == nil {
+		return 0, d.wrapErr("exec_aggregate_tx", "", ErrNilTransaction)
+	}
+	if err := checkParams(stmt, params, func() (string, error) { return d.executor.RenderAggregate(stmt) }); err != nil {
+		return 0, d.wrapErr("exec_aggregate_tx", "", err)
+	}
+	callCtx, cancel := withDefaultTimeout(d.withStatement(ctx, stmt.Name(), params), d.timeout)
+	defer cancel()
+	result, err := d.runAggregate(callCtx, d.primary(), tx, stmt, params)
+	if err != nil {
+		return 0, d.wrapErr("exec_aggregate_tx", "", err)
+	}
+	return result, nil
+}
+
+// NewDatabaseChecked creates a Database like NewDatabase, but validates that
+// T can be atomized before returning. A field the atomic view cannot map
+// (e.g. any, complex128, **int) is reported here, naming the field, rather
+// than as a panic from Atomic().
+func NewDatabaseChecked[T any](db *sqlx.DB, table string, renderer astql.Renderer, opts ...Option) (*Database[T], error) {
+	atomizer, err := useAtomizer[T]()
+	if err != nil {
+		return nil, err
+	}
+	d, err := NewDatabase[T](db, table, renderer, opts...)
+	if err != nil {
+		return nil, err
+	}
+	d.atomicOnce.Do(func() {
+		d.atomic = atomic.New(d.db, d.executor, d.keyCol, d.tableName, atomizer.Spec()).Redact(d.redact.fields())
+	})
+	return d, nil
 }
 
 // Atomic returns an atom-based view of this database.
 // The returned atomic.Database satisfies the AtomicDatabase interface.
 // The instance is created once and cached for subsequent calls.
-// Panics if T is not atomizable (a programmer error).
+// Nil pointer fields map to SQL NULL and back in both directions.
+// Panics if T is not atomizable (a programmer error); use
+// NewDatabaseChecked to report that at construction instead.
 func (d *Database[T]) Atomic() AtomicDatabase {
+	return d.atomicView()
+}
+
+// atomicView builds the atomic view on first use.
+func (d *Database[T]) atomicView() *atomic.Database[T] {
 	d.atomicOnce.Do(func() {
-		atomizer, err := atom.Use[T]()
+		atomizer, err := useAtomizer[T]()
 		if err != nil {
-			panic("grub: invalid type for atomization: " + err.Error())
+			panic(err.Error())
+		}
+		d.atomic = atomic.New(d.db, d.executor, d.keyCol, d.tableName, atomizer.Spec()).Redact(d.redact.fields())
+		if d.readOnly {
+			d.atomic = d.atomic.ReadOnly()
 		}
-		d.atomic = atomic.New(
-			d.executor,
-			d.keyCol,
-			d.tableName,
-			atomizer.Spec(),
-		)
 	})
 	return d.atomic
 }
+
+// Spec returns the atom spec of T: its type name and each field's name,
+// Go type, kind, and struct tags, as carried by Atomic's atoms. Panics if
+// T is not atomizable, like Atomic.
+func (d *Database[T]) Spec() atom.Spec {
+	return d.atomicView().Spec()
+}
+
+// sameColumns reports whether a and b hold equal values in every db-tagged field.
+// Times are compared with time.Time.Equal so location and monotonic readings
+// from the driver do not register as changes; empty and nil slices are equal.
+func (d *Database[T]) sameColumns(a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	for _, field := range d.executor.Soy().Metadata().Fields {
+		col := field.Tags["db"]
+		if col == "" || col == "-" {
+			continue
+		}
+		if !sameValue(va.FieldByIndex(field.Index), vb.FieldByIndex(field.Index)) {
+			return false
+		}
+	}
+	return true
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// sameValue compares two column values of the same type.
+func sameValue(a, b reflect.Value) bool {
+	switch {
+	case a.Type() == timeType:
+		return a.Interface().(time.Time).Equal(b.Interface().(time.Time))
+	case a.Kind() == reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return sameValue(a.Elem(), b.Elem())
+	case a.Kind() == reflect.Slice || a.Kind() == reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// cacheLoad returns ctx marked so records scanned under it skip AfterLoad
+// when they are about to be cached under key, leaving the caller to run it
+// once the raw rows are stored. ctx is returned as is when key is empty.
+func (d *Database[T]) cacheLoad(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, sharedLoadKey{}, true)
+}
+
+// afterLoad redacts a scanned or cached record and runs its AfterLoad hook,
+// unless ctx asks for the record as stored.
+func (d *Database[T]) afterLoad(ctx context.Context, record *T) error {
+	if isRawLoad(ctx) {
+		return nil
+	}
+	d.redact.apply(record)
+	if isSharedLoad(ctx) {
+		return nil
+	}
+	return callAfterLoad(ctx, record)
+}
+
+// wrapErr classifies constraint violations and annotates err with the
+// operation context for this table.
+func (d *Database[T]) wrapErr(op, key string, err error) error {
+	return shared.WrapError(KindDatabase, op, d.tableName, key, classifySerialization(classifyConstraint(err)))
+}
@@ -2,10 +2,18 @@ package grub
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	astqlpostgres "github.com/zoobzio/astql/postgres"
 	astqlsqlite "github.com/zoobzio/astql/sqlite"
 	"github.com/zoobzio/edamame"
 	"github.com/zoobzio/grub/internal/mockdb"
@@ -112,6 +120,210 @@ func TestDatabase_Set(t *testing.T) {
 	}
 }
 
+func TestDatabase_GetBatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("single query with deduplicated keys", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		result, err := db.GetBatch(ctx, []string{"1", "2", "1"})
+		if err != nil {
+			t.Fatalf("GetBatch failed: %v", err)
+		}
+		if len(result) != 0 {
+			t.Errorf("expected empty result from mock, got %d", len(result))
+		}
+		if len(capture.Queries) != 1 {
+			t.Fatalf("expected 1 query, got %d", len(capture.Queries))
+		}
+		query := capture.Queries[0]
+		if !strings.Contains(query.Query, `"id" IN (?, ?)`) {
+			t.Errorf("expected a key IN list, got: %s", query.Query)
+		}
+		if len(query.Args) != 2 {
+			t.Errorf("expected 2 args, got %v", query.Args)
+		}
+	})
+
+	t.Run("chunks large key sets", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		keys := make([]string, batchChunkSize*2+1)
+		for i := range keys {
+			keys[i] = strconv.Itoa(i)
+		}
+		if _, err := db.GetBatch(ctx, keys); err != nil {
+			t.Fatalf("GetBatch failed: %v", err)
+		}
+		if len(capture.Queries) != 3 {
+			t.Errorf("expected 3 queries, got %d", len(capture.Queries))
+		}
+	})
+
+	t.Run("empty keys", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		result, err := db.GetBatch(ctx, nil)
+		if err != nil || len(result) != 0 {
+			t.Errorf("expected empty result, got %v, %v", result, err)
+		}
+		if len(capture.Queries) != 0 {
+			t.Errorf("expected no queries, got %d", len(capture.Queries))
+		}
+	})
+}
+
+func TestDatabase_ColumnValue(t *testing.T) {
+	mockDB, _ := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	rec := &TestDBUser{ID: 42, Email: "a@example.com", Age: intPtr(7)}
+	for col, want := range map[string]string{"id": "42", "email": "a@example.com", "age": "7", "missing": ""} {
+		if got := db.columnValue(rec, col); got != want {
+			t.Errorf("columnValue(%q) = %q, want %q", col, got, want)
+		}
+	}
+	if got := db.columnValue(&TestDBUser{}, "age"); got != "" {
+		t.Errorf("expected empty string for nil pointer, got %q", got)
+	}
+}
+
+func TestDatabase_SetIfChanged(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing row is written", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		// The mock returns no rows, so the write itself errors; only the
+		// attempted INSERT matters here.
+		_, _ = db.SetIfChanged(ctx, "1", &TestDBUser{ID: 1, Email: "a@example.com", Name: "A"})
+
+		query, ok := capture.Last()
+		if !ok || !strings.Contains(query.Query, "INSERT") {
+			t.Errorf("expected INSERT as last query, got %q", query.Query)
+		}
+	})
+
+	t.Run("compares against the row as stored", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		db, err := NewDatabase[secretDBRecord](mockDB, "records", testDBRenderer, WithRedaction())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		cfg.SetRows([]string{"id", "password", "key", "hint"},
+			[]driver.Value{int64(1), "hunter2", []byte("k3y"), ""})
+
+		wrote, err := db.SetIfChanged(ctx, "1", &secretDBRecord{ID: 1, Password: "hunter2", Key: []byte("k3y")})
+		if err != nil {
+			t.Fatalf("SetIfChanged failed: %v", err)
+		}
+		if wrote {
+			t.Error("expected no write for a row matching its redacted columns")
+		}
+		for _, q := range capture.Queries {
+			if strings.Contains(q.Query, "INSERT") {
+				t.Errorf("unexpected write: %s", q.Query)
+			}
+		}
+	})
+
+	t.Run("read error skips write", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		readErr := errors.New("connection reset")
+		cfg.SetQueryErr(readErr)
+		defer cfg.Reset()
+
+		wrote, err := db.SetIfChanged(ctx, "1", &TestDBUser{ID: 1})
+		if !errors.Is(err, readErr) {
+			t.Fatalf("expected read error, got %v", err)
+		}
+		if wrote {
+			t.Error("expected no write on read error")
+		}
+		for _, q := range capture.Queries {
+			if strings.Contains(q.Query, "INSERT") {
+				t.Errorf("unexpected write: %s", q.Query)
+			}
+		}
+	})
+}
+
+func TestDatabase_SameColumns(t *testing.T) {
+	mockDB, _ := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	age, sameAge, otherAge := 30, 30, 31
+	base := TestDBUser{ID: 1, Email: "a@example.com", Name: "A", Age: &age}
+
+	tests := []struct {
+		name  string
+		other TestDBUser
+		same  bool
+	}{
+		{"identical", base, true},
+		{"equal pointer targets", TestDBUser{ID: 1, Email: "a@example.com", Name: "A", Age: &sameAge}, true},
+		{"different scalar", TestDBUser{ID: 1, Email: "b@example.com", Name: "A", Age: &age}, false},
+		{"different pointer target", TestDBUser{ID: 1, Email: "a@example.com", Name: "A", Age: &otherAge}, false},
+		{"nil vs set pointer", TestDBUser{ID: 1, Email: "a@example.com", Name: "A"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := base, tt.other
+			if got := db.sameColumns(&a, &b); got != tt.same {
+				t.Errorf("expected %v, got %v", tt.same, got)
+			}
+		})
+	}
+}
+
+func TestSameValue(t *testing.T) {
+	utc := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := utc.In(time.FixedZone("X", 3600))
+
+	tests := []struct {
+		name string
+		a, b any
+		same bool
+	}{
+		{"equal instants in different zones", utc, local, true},
+		{"different instants", utc, utc.Add(time.Second), false},
+		{"nil and empty slice", []byte(nil), []byte{}, true},
+		{"different slices", []byte("a"), []byte("b"), false},
+		{"nil and empty map", map[string]string(nil), map[string]string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameValue(reflect.ValueOf(tt.a), reflect.ValueOf(tt.b)); got != tt.same {
+				t.Errorf("expected %v, got %v", tt.same, got)
+			}
+		})
+	}
+}
+
 func TestDatabase_Delete(t *testing.T) {
 	mockDB, capture := mockdb.New()
 	ctx := context.Background()
@@ -149,19 +361,34 @@ func TestDatabase_Exists(t *testing.T) {
 		t.Fatalf("NewDatabase failed: %v", err)
 	}
 
-	_, _ = db.Exists(ctx, "123")
+	exists, err := db.Exists(ctx, "123")
+	if err != nil || exists {
+		t.Errorf("expected false for a key with no row, got %v, %v", exists, err)
+	}
 
 	query, ok := capture.Last()
 	if !ok {
 		t.Fatal("no query captured")
 	}
 
-	// Verify SELECT with LIMIT query structure
-	if !strings.Contains(query.Query, "SELECT") {
-		t.Errorf("expected SELECT query, got: %s", query.Query)
+	// Verify a single EXISTS probe rather than a row fetch
+	if !strings.HasPrefix(query.Query, "SELECT EXISTS (SELECT") {
+		t.Errorf("expected EXISTS query, got: %s", query.Query)
 	}
-	if !strings.Contains(query.Query, "LIMIT") {
-		t.Errorf("expected LIMIT clause, got: %s", query.Query)
+	if strings.Contains(query.Query, "LIMIT") {
+		t.Errorf("expected no LIMIT clause, got: %s", query.Query)
+	}
+}
+
+func TestDatabase_Table(t *testing.T) {
+	mockDB, _ := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	if got := db.Table(); got != "test_users" {
+		t.Errorf("expected 'test_users', got %q", got)
 	}
 }
 
@@ -178,6 +405,31 @@ func TestDatabase_Executor(t *testing.T) {
 	}
 }
 
+func TestDatabase_DB(t *testing.T) {
+	mockDB, _ := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer,
+		WithQueryLogger(func(context.Context, QueryInfo) {}))
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	if db.DB() != mockDB {
+		t.Error("expected DB to return the pool passed to NewDatabase")
+	}
+}
+
+func TestDatabase_Spec(t *testing.T) {
+	mockDB, _ := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	spec := db.Spec()
+	if spec.TypeName != "TestDBUser" || len(spec.Fields) != 4 || spec.Fields[1].Tags["db"] != "email" {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+}
+
 // --- Builder Accessor Tests ---
 
 func TestDatabase_QueryBuilder(t *testing.T) {
@@ -263,6 +515,65 @@ func TestDatabase_InsertBuilder(t *testing.T) {
 	}
 }
 
+func TestDatabase_InsertReturning(t *testing.T) {
+	ctx := context.Background()
+	renderers := map[string]struct {
+		renderer astql.Renderer
+		want     string
+	}{
+		"sqlite":   {testDBRenderer, `RETURNING "id"`},
+		"postgres": {astqlpostgres.New(), `RETURNING "id"`},
+	}
+	for name, tt := range renderers {
+		t.Run(name, func(t *testing.T) {
+			mockDB, capture := mockdb.New()
+			db, err := NewDatabase[TestDBUser](mockDB, "test_users", tt.renderer)
+			if err != nil {
+				t.Fatalf("NewDatabase failed: %v", err)
+			}
+
+			// The mock driver returns no rows, so only the SQL is checked.
+			_, err = db.InsertReturning(ctx, &TestDBUser{Email: "a@example.com", Name: "A"})
+			var gErr *Error
+			if !errors.As(err, &gErr) || gErr.Op != "insert_returning" {
+				t.Errorf("expected insert_returning error, got %v", err)
+			}
+			query, ok := capture.Last()
+			if !ok {
+				t.Fatal("no query captured")
+			}
+			if !strings.HasPrefix(query.Query, `INSERT INTO "test_users"`) || !strings.Contains(query.Query, tt.want) {
+				t.Errorf("expected INSERT with %q, got: %s", tt.want, query.Query)
+			}
+			if strings.Contains(query.Query[:strings.Index(query.Query, "VALUES")], `"id"`) {
+				t.Errorf("expected the key column to be omitted from the insert, got: %s", query.Query)
+			}
+		})
+	}
+
+	t.Run("tx", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		tx, err := mockDB.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTxx failed: %v", err)
+		}
+		defer tx.Rollback()
+
+		_, err = db.InsertReturningTx(ctx, tx, &TestDBUser{Email: "a@example.com", Name: "A"})
+		var gErr *Error
+		if !errors.As(err, &gErr) || gErr.Op != "insert_returning_tx" {
+			t.Errorf("expected insert_returning_tx error, got %v", err)
+		}
+		if query, ok := capture.Last(); !ok || !strings.Contains(query.Query, "RETURNING") {
+			t.Errorf("expected RETURNING clause, got: %+v", query)
+		}
+	})
+}
+
 func TestDatabase_InsertFullBuilder(t *testing.T) {
 	mockDB, capture := mockdb.New()
 	ctx := context.Background()
@@ -527,6 +838,61 @@ func TestDatabase_ExecAggregateSum(t *testing.T) {
 	}
 }
 
+func TestDatabase_ExecAggregateFuncs(t *testing.T) {
+	ctx := context.Background()
+	funcs := []edamame.AggregateFunc{edamame.AggMin, edamame.AggMax, edamame.AggAvg}
+
+	for _, fn := range funcs {
+		t.Run(string(fn), func(t *testing.T) {
+			mockDB, capture := mockdb.New()
+			db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+			if err != nil {
+				t.Fatalf("NewDatabase failed: %v", err)
+			}
+			stmt := edamame.NewAggregateStatement(strings.ToLower(string(fn))+"-age", "Aggregate of ages", fn, edamame.AggregateSpec{
+				Field: "age",
+			})
+
+			_, _ = db.ExecAggregate(ctx, stmt, nil)
+			query, ok := capture.Last()
+			if !ok {
+				t.Fatal("no query captured")
+			}
+			if !strings.Contains(query.Query, string(fn)+`("age")`) {
+				t.Errorf("expected %s(\"age\") in query, got: %s", fn, query.Query)
+			}
+
+			capture.Reset()
+			err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+				_, _ = db.ExecAggregateTx(ctx, tx, stmt, nil)
+				return nil
+			}, nil)
+			if err != nil {
+				t.Fatalf("WithTx failed: %v", err)
+			}
+			var found bool
+			for _, q := range capture.Queries {
+				found = found || strings.Contains(q.Query, string(fn)+`("age")`)
+			}
+			if !found {
+				t.Errorf("expected %s in Tx query, got %v", fn, capture.Queries)
+			}
+
+			pg, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlpostgres.New())
+			if err != nil {
+				t.Fatalf("NewDatabase failed: %v", err)
+			}
+			sql, err := pg.Executor().RenderAggregate(stmt)
+			if err != nil {
+				t.Fatalf("RenderAggregate failed: %v", err)
+			}
+			if !strings.Contains(sql, string(fn)+`("age")`) {
+				t.Errorf("expected %s in postgres SQL, got: %s", fn, sql)
+			}
+		})
+	}
+}
+
 func TestDatabase_Atomic(t *testing.T) {
 	mockDB, _ := mockdb.New()
 
@@ -783,11 +1149,11 @@ func TestDatabase_ExistsTx(t *testing.T) {
 		t.Fatal("no query captured")
 	}
 
-	if !strings.Contains(query.Query, "SELECT") {
-		t.Errorf("expected SELECT query, got: %s", query.Query)
+	if !strings.HasPrefix(query.Query, "SELECT EXISTS (SELECT") {
+		t.Errorf("expected EXISTS query, got: %s", query.Query)
 	}
-	if !strings.Contains(query.Query, "LIMIT") {
-		t.Errorf("expected LIMIT clause, got: %s", query.Query)
+	if strings.Contains(query.Query, "LIMIT") {
+		t.Errorf("expected no LIMIT clause, got: %s", query.Query)
 	}
 }
 
@@ -1142,3 +1508,70 @@ func TestNewDatabase_PrimaryKeyIgnoredDBTag(t *testing.T) {
 		t.Errorf("expected ErrNoPrimaryKey, got: %v", err)
 	}
 }
+
+type UnsupportedFieldUser struct {
+	ID    int        `db:"id" constraints:"primarykey"`
+	Score complex128 `db:"score"`
+}
+
+func TestNewDatabase_UnsupportedField(t *testing.T) {
+	mockDB, _ := mockdb.New()
+	db, err := NewDatabase[UnsupportedFieldUser](mockDB, "test", testDBRenderer)
+	if err != nil {
+		t.Fatalf("expected NewDatabase to defer the atom check, got: %v", err)
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), `"Score"`) {
+			t.Errorf("expected Atomic to panic naming the field, got: %v", r)
+		}
+	}()
+	db.Atomic()
+}
+
+func TestNewDatabaseChecked(t *testing.T) {
+	mockDB, _ := mockdb.New()
+	_, err := NewDatabaseChecked[UnsupportedFieldUser](mockDB, "test", testDBRenderer)
+	if err == nil {
+		t.Fatal("expected error for unsupported field type")
+	}
+	if !strings.Contains(err.Error(), `"Score"`) {
+		t.Errorf("expected error naming the field, got: %v", err)
+	}
+
+	db, err := NewDatabaseChecked[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabaseChecked failed: %v", err)
+	}
+	if db.atomic == nil || db.Atomic() != AtomicDatabase(db.atomic) {
+		t.Error("expected the atomic view to be built by NewDatabaseChecked")
+	}
+}
+
+func TestDatabase_ErrorContext(t *testing.T) {
+	mockDB, _, cfg := mockdb.NewWithConfig()
+	ctx := context.Background()
+
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	cfg.SetRowsAffected(0)
+	defer cfg.Reset()
+
+	err = db.Delete(ctx, "nonexistent")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+
+	var gerr *Error
+	if !errors.As(err, &gerr) {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if gerr.Kind != KindDatabase || gerr.Op != "delete" || gerr.Table != "test_users" || gerr.Key != "nonexistent" {
+		t.Errorf("unexpected context: kind=%q op=%q table=%q key=%q", gerr.Kind, gerr.Op, gerr.Table, gerr.Key)
+	}
+	if !strings.Contains(err.Error(), `key="nonexistent"`) {
+		t.Errorf("expected message to include key, got: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// deletePageSize is the number of keys DeletePrefix lists per page when the
+// provider cannot delete a prefix natively.
+const deletePageSize = 1000
+
+// checkDeletePrefix rejects the empty prefix, which would delete everything.
+func checkDeletePrefix(prefix string) error {
+	if prefix == "" {
+		return &KeyError{Key: prefix, Rule: "empty prefix would delete every key"}
+	}
+	return nil
+}
+
+// deletePaged lists up to deletePageSize keys under prefix and deletes them
+// one by one, repeating until a page comes back short. Keys that vanish
+// between listing and deletion are not counted. It stops early when a full
+// page deletes nothing, so a backend whose listing lags its deletes cannot
+// spin. Cancellation is checked between pages.
+func deletePaged(ctx context.Context, kind, prefix string, timeout time.Duration,
+	list func(ctx context.Context, prefix string, limit int) ([]string, error),
+	del func(ctx context.Context, key string) error,
+) (int64, error) {
+	var deleted int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, shared.WrapError(kind, "delete_prefix", "", prefix, err)
+		}
+		listCtx, cancel := withDefaultTimeout(ctx, timeout)
+		keys, err := list(listCtx, prefix, deletePageSize)
+		cancel()
+		if err != nil {
+			return deleted, shared.WrapError(kind, "delete_prefix", "", prefix, err)
+		}
+		var page int64
+		for _, key := range keys {
+			callCtx, cancel := withDefaultTimeout(ctx, timeout)
+			err := del(callCtx, key)
+			cancel()
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return deleted + page, shared.WrapError(kind, "delete_prefix", "", key, err)
+			}
+			page++
+		}
+		deleted += page
+		if len(keys) < deletePageSize || page == 0 {
+			return deleted, nil
+		}
+	}
+}
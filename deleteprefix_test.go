@@ -0,0 +1,135 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// flakyStoreProvider fails every Delete after the first allow succeed.
+type flakyStoreProvider struct {
+	*mockStoreProvider
+	allow int
+}
+
+func (p *flakyStoreProvider) Delete(ctx context.Context, key string) error {
+	if p.allow == 0 {
+		return errors.New("delete failed")
+	}
+	p.allow--
+	return p.mockStoreProvider.Delete(ctx, key)
+}
+
+// prefixDeleterStoreProvider records native DeletePrefix calls.
+type prefixDeleterStoreProvider struct {
+	*mockStoreProvider
+	prefixes []string
+}
+
+func (p *prefixDeleterStoreProvider) DeletePrefix(_ context.Context, prefix string) (int64, error) {
+	p.prefixes = append(p.prefixes, prefix)
+	return 7, nil
+}
+
+func seedStore(provider *mockStoreProvider, prefix string, n int) {
+	for i := 0; i < n; i++ {
+		provider.data[prefix+strconv.Itoa(i)] = []byte(`{}`)
+	}
+}
+
+func TestStore_DeletePrefix(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("paged fallback", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		seedStore(provider, "tmp/exports/2023-", 2500)
+		seedStore(provider, "tmp/exports/2024-", 3)
+		n, err := NewStore[testRecord](provider).DeletePrefix(ctx, "tmp/exports/2023-")
+		if err != nil {
+			t.Fatalf("DeletePrefix failed: %v", err)
+		}
+		if n != 2500 {
+			t.Errorf("expected 2500 deleted, got %d", n)
+		}
+		if len(provider.data) != 3 {
+			t.Errorf("expected only the other prefix left, got %d keys", len(provider.data))
+		}
+	})
+
+	t.Run("resumes after failure", func(t *testing.T) {
+		provider := &flakyStoreProvider{mockStoreProvider: newMockStoreProvider(), allow: 1200}
+		seedStore(provider.mockStoreProvider, "p/", 1500)
+		store := NewStore[testRecord](provider)
+		n, err := store.DeletePrefix(ctx, "p/")
+		if err == nil || n != 1200 {
+			t.Fatalf("expected an error after 1200 deletes, got %d, %v", n, err)
+		}
+		provider.allow = 300
+		n, err = store.DeletePrefix(ctx, "p/")
+		if err != nil || n != 300 {
+			t.Errorf("expected the remaining 300 deleted, got %d, %v", n, err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		seedStore(provider, "p/", 5)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		n, err := NewStore[testRecord](provider).DeletePrefix(cancelled, "p/")
+		if !errors.Is(err, context.Canceled) || n != 0 || len(provider.data) != 5 {
+			t.Errorf("expected nothing deleted and context.Canceled, got %d, %v", n, err)
+		}
+	})
+
+	t.Run("empty prefix", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		seedStore(provider, "p/", 5)
+		if _, err := NewStore[testRecord](provider).DeletePrefix(ctx, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey, got %v", err)
+		}
+		if len(provider.data) != 5 {
+			t.Error("expected nothing deleted")
+		}
+	})
+
+	t.Run("native", func(t *testing.T) {
+		provider := &prefixDeleterStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		n, err := NewStore[testRecord](provider).DeletePrefix(ctx, "p/")
+		if err != nil || n != 7 {
+			t.Errorf("expected the provider count, got %d, %v", n, err)
+		}
+		if len(provider.prefixes) != 1 || provider.prefixes[0] != "p/" {
+			t.Errorf("expected one native call for p/, got %v", provider.prefixes)
+		}
+	})
+}
+
+func TestBucket_DeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockBucketProvider()
+	for i := 0; i < 1001; i++ {
+		key := "tmp/" + strconv.Itoa(i)
+		provider.data[key] = []byte(`{}`)
+		provider.info[key] = &ObjectInfo{Key: key}
+	}
+	provider.data["keep"] = []byte(`{}`)
+	provider.info["keep"] = &ObjectInfo{Key: "keep"}
+
+	n, err := NewBucket[testPayload](provider).DeletePrefix(ctx, "tmp/")
+	if err != nil {
+		t.Fatalf("DeletePrefix failed: %v", err)
+	}
+	if n != 1001 {
+		t.Errorf("expected 1001 deleted, got %d", n)
+	}
+	if len(provider.data) != 1 || provider.data["keep"] == nil {
+		t.Errorf("expected only keep left, got %d objects", len(provider.data))
+	}
+
+	provider.listErr = errors.New("list failed")
+	if _, err := NewBucket[testPayload](provider).DeletePrefix(ctx, "tmp/"); err == nil {
+		t.Error("expected list error")
+	}
+}
@@ -0,0 +1,71 @@
+package grub
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+)
+
+// driverDialects maps database/sql driver names to the astql renderer
+// package that speaks their dialect.
+var driverDialects = map[string]string{
+	"postgres":  "postgres",
+	"pgx":       "postgres",
+	"mysql":     "mariadb",
+	"sqlite":    "sqlite",
+	"sqlite3":   "sqlite",
+	"sqlserver": "mssql",
+	"mssql":     "mssql",
+}
+
+// rendererDialect returns the dialect of one of the astql renderers, or ""
+// for any other renderer.
+func rendererDialect(renderer astql.Renderer) string {
+	t := reflect.TypeOf(renderer)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	pkg := t.PkgPath()
+	if path.Dir(pkg) != "github.com/zoobzio/astql" {
+		return ""
+	}
+	return path.Base(pkg)
+}
+
+// checkDialect reports ErrDialectMismatch when renderer is an astql renderer
+// for a different dialect than db's driver. Identifier quoting belongs to
+// the renderer (double quotes for postgres and sqlite, backticks for
+// mariadb, brackets for mssql), so a mismatched pair renders SQL that only
+// fails on the first query. Unknown drivers and custom renderers are not
+// checked.
+func checkDialect(db *sqlx.DB, renderer astql.Renderer) error {
+	want, ok := driverDialects[db.DriverName()]
+	if !ok {
+		return nil
+	}
+	got := rendererDialect(renderer)
+	if got == "" || got == want {
+		return nil
+	}
+	return fmt.Errorf("%w: astql/%s renderer on a %q connection; use astql/%s",
+		ErrDialectMismatch, got, db.DriverName(), want)
+}
+
+// quoteIdent quotes an identifier the way dialect's renderer does, with
+// ANSI double quotes for postgres, sqlite, and unknown dialects.
+func quoteIdent(dialect, name string) string {
+	switch dialect {
+	case "mariadb":
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	case "mssql":
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,105 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	astqlmariadb "github.com/zoobzio/astql/mariadb"
+	astqlmssql "github.com/zoobzio/astql/mssql"
+	astqlpostgres "github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// customRenderer stands in for a renderer outside astql.
+type customRenderer struct {
+	astql.Renderer
+}
+
+func TestNewDatabase_Dialect(t *testing.T) {
+	tests := []struct {
+		driver   string
+		renderer astql.Renderer
+		wantErr  bool
+	}{
+		{"mysql", astqlmariadb.New(), false},
+		{"mysql", astqlpostgres.New(), true},
+		{"mysql", testDBRenderer, true},
+		{"postgres", astqlpostgres.New(), false},
+		{"pgx", astqlmariadb.New(), true},
+		{"sqlserver", astqlmssql.New(), false},
+		{"sqlite", testDBRenderer, false},
+		{"sqlite", astqlmssql.New(), true},
+		{"mysql", customRenderer{astqlpostgres.New()}, false},
+		{"mockdb", astqlpostgres.New(), false},
+	}
+	for _, tt := range tests {
+		mockDB, _ := mockdb.New()
+		db := sqlx.NewDb(mockDB.DB, tt.driver)
+		_, err := NewDatabase[TestDBUser](db, "test_users", tt.renderer)
+		if tt.wantErr != errors.Is(err, ErrDialectMismatch) {
+			t.Errorf("%s with %T: expected mismatch %v, got %v", tt.driver, tt.renderer, tt.wantErr, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s with %T: NewDatabase failed: %v", tt.driver, tt.renderer, err)
+		}
+	}
+
+	t.Run("replicas", func(t *testing.T) {
+		primary, _ := mockdb.New()
+		replica, _ := mockdb.New()
+		_, err := NewDatabase[TestDBUser](sqlx.NewDb(primary.DB, "mysql"), "test_users", astqlmariadb.New(),
+			WithReadReplicas(sqlx.NewDb(replica.DB, "postgres")))
+		if !errors.Is(err, ErrDialectMismatch) {
+			t.Errorf("expected ErrDialectMismatch for a replica, got %v", err)
+		}
+	})
+}
+
+func TestNewDatabase_MariaDBQuoting(t *testing.T) {
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](sqlx.NewDb(mockDB.DB, "mysql"), "test_users", astqlmariadb.New())
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	_, _ = db.Get(context.Background(), "1")
+	last, ok := capture.Last()
+	if !ok {
+		t.Fatal("expected a captured query")
+	}
+	if !strings.Contains(last.Query, "FROM `test_users`") || strings.Contains(last.Query, `"`) {
+		t.Errorf("expected backtick-quoted identifiers, got: %s", last.Query)
+	}
+}
+
+func TestDatabase_Exists_Dialects(t *testing.T) {
+	tests := []struct {
+		driver   string
+		renderer astql.Renderer
+		want     string
+	}{
+		{"sqlite", testDBRenderer, `SELECT EXISTS (SELECT "id" FROM "test_users" WHERE "id" = `},
+		{"postgres", astqlpostgres.New(), `SELECT EXISTS (SELECT "id" FROM "test_users" WHERE "id" = `},
+		{"mysql", astqlmariadb.New(), "SELECT EXISTS (SELECT `id` FROM `test_users` WHERE `id` = "},
+		{"sqlserver", astqlmssql.New(), "SELECT CASE WHEN EXISTS (SELECT [id] FROM [test_users] WHERE [id] = "},
+	}
+	for _, tt := range tests {
+		mockDB, capture, config := mockdb.NewIsolated()
+		config.SetQueryRows("EXISTS", []string{"exists"}, []driver.Value{int64(1)})
+		db, err := NewDatabase[TestDBUser](sqlx.NewDb(mockDB.DB, tt.driver), "test_users", tt.renderer)
+		if err != nil {
+			t.Fatalf("%s: NewDatabase failed: %v", tt.driver, err)
+		}
+		exists, err := db.Exists(context.Background(), "1")
+		if err != nil || !exists {
+			t.Errorf("%s: expected true, got %v, %v", tt.driver, exists, err)
+		}
+		if query, _ := capture.Last(); !strings.HasPrefix(query.Query, tt.want) {
+			t.Errorf("%s: expected %s..., got: %s", tt.driver, tt.want, query.Query)
+		}
+	}
+}
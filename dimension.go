@@ -0,0 +1,34 @@
+package grub
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// dimensionPattern matches the dimension mismatch messages reported by
+// supported vector backends:
+//
+//	qdrant:   "Vector dimension error: expected dim: 1536, got 768"
+//	milvus:   "the dim (768) of field data(embedding) is not equal to schema dim (1536)"
+//	pinecone: "Vector dimension 768 does not match the dimension of the index 1536"
+//	weaviate: "new node has a vector with length 768. Existing nodes have vectors with length 1536"
+//	pgvector: "expected 1536 dimensions, not 768"
+var dimensionPattern = regexp.MustCompile(`(?i)vector dimension error|` +
+	`is not equal to schema dim|` +
+	`does not match the dimension of the index|` +
+	`existing nodes have vectors with length|` +
+	`expected \d+ dimensions, not \d+`)
+
+// classifyDimension wraps a provider error reporting a dimension mismatch so
+// that it matches ErrDimensionMismatch. Other errors are returned unchanged.
+// The original error remains reachable via errors.Is and errors.As.
+func classifyDimension(err error) error {
+	if err == nil || errors.Is(err, ErrDimensionMismatch) {
+		return err
+	}
+	if dimensionPattern.MatchString(err.Error()) {
+		return fmt.Errorf("%w: %w", ErrDimensionMismatch, err)
+	}
+	return err
+}
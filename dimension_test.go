@@ -0,0 +1,36 @@
+package grub
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyDimension(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		mismatch bool
+	}{
+		{"qdrant", errors.New("Wrong input: Vector dimension error: expected dim: 1536, got 768"), true},
+		{"milvus", errors.New("the dim (768) of field data(embedding) is not equal to schema dim (1536)"), true},
+		{"pinecone", errors.New("Vector dimension 768 does not match the dimension of the index 1536"), true},
+		{"weaviate", errors.New("new node has a vector with length 768. Existing nodes have vectors with length 1536"), true},
+		{"pgvector", errors.New("pq: expected 1536 dimensions, not 768"), true},
+		{"already classified", fmt.Errorf("%w: expected 3, got 2", ErrDimensionMismatch), true},
+		{"unrelated", errors.New("connection refused"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyDimension(tt.err)
+			if errors.Is(got, ErrDimensionMismatch) != tt.mismatch {
+				t.Errorf("expected mismatch=%v, got %v", tt.mismatch, got)
+			}
+			if tt.err != nil && !errors.Is(got, tt.err) {
+				t.Error("expected original error to remain reachable")
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package grub
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Encoded stores a value of any type in a single Database column by
+// encoding it with codec C on write and decoding it on scan, so nested
+// structs, maps, and slices can live in JSON, JSONB, TEXT, or BLOB
+// columns without marshaling at each call site:
+//
+//	type User struct {
+//		ID       string                                 `db:"id" constraints:"primarykey"`
+//		Settings grub.Encoded[Settings, grub.JSONCodec] `db:"settings"`
+//	}
+//
+// The codec is chosen by type because database/sql scans into the field
+// itself, before any struct tag could be consulted. A NULL column scans as
+// the zero V. JSONCodec values are bound as strings, which JSON and JSONB
+// columns accept; other codecs bind bytes for BLOB or BYTEA columns.
+// Encoded marshals to JSON as V alone, so records serialise as if the
+// field held V directly. Query values inside the column with WhereJSON.
+// V may be any type C can encode, such as map[string]any; only
+// Database.Atomic requires a V that atom can describe.
+type Encoded[V any, C Codec] struct {
+	V V
+}
+
+// JSON is an Encoded column holding V as JSON.
+type JSON[V any] = Encoded[V, JSONCodec]
+
+// Value encodes e.V with C for database/sql.
+func (e Encoded[V, C]) Value() (driver.Value, error) {
+	var codec C
+	data, err := codec.Encode(e.V)
+	if err != nil {
+		return nil, fmt.Errorf("grub: encoding %T column: %w", e.V, err)
+	}
+	if _, ok := any(codec).(JSONCodec); ok {
+		return string(data), nil
+	}
+	return data, nil
+}
+
+// Scan decodes a column value into e.V with C.
+func (e *Encoded[V, C]) Scan(src any) error {
+	var zero V
+	e.V = zero
+	var data []byte
+	switch s := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		return fmt.Errorf("grub: cannot scan %T into Encoded[%T]", src, e.V)
+	}
+	var codec C
+	if err := codec.Decode(data, &e.V); err != nil {
+		return fmt.Errorf("grub: decoding %T column: %w", e.V, err)
+	}
+	return nil
+}
+
+// MarshalJSON marshals e.V.
+func (e Encoded[V, C]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.V)
+}
+
+// UnmarshalJSON unmarshals data into e.V.
+func (e *Encoded[V, C]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &e.V)
+}
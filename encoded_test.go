@@ -0,0 +1,174 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+type encodedSettings struct {
+	Theme string   `json:"theme"`
+	Tags  []string `json:"tags"`
+}
+
+// encodedDBUser is a Database-compatible model with encoded columns.
+type encodedDBUser struct {
+	ID       string                             `db:"id" constraints:"primarykey"`
+	Settings JSON[encodedSettings]              `db:"settings"`
+	Blob     Encoded[encodedSettings, GobCodec] `db:"blob"`
+}
+
+func TestEncoded(t *testing.T) {
+	t.Run("json round trip", func(t *testing.T) {
+		in := JSON[encodedSettings]{V: encodedSettings{Theme: "dark", Tags: []string{"a"}}}
+		v, err := in.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		s, ok := v.(string)
+		if !ok || s != `{"theme":"dark","tags":["a"]}` {
+			t.Fatalf("expected JSON string, got %#v", v)
+		}
+		for _, src := range []any{s, []byte(s)} {
+			var out JSON[encodedSettings]
+			if err := out.Scan(src); err != nil {
+				t.Fatalf("Scan(%T) failed: %v", src, err)
+			}
+			if out.V.Theme != "dark" || len(out.V.Tags) != 1 {
+				t.Errorf("Scan(%T): unexpected value %+v", src, out.V)
+			}
+		}
+	})
+
+	t.Run("gob binds bytes", func(t *testing.T) {
+		in := Encoded[encodedSettings, GobCodec]{V: encodedSettings{Theme: "light"}}
+		v, err := in.Value()
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		data, ok := v.([]byte)
+		if !ok {
+			t.Fatalf("expected bytes, got %T", v)
+		}
+		var out Encoded[encodedSettings, GobCodec]
+		if err := out.Scan(data); err != nil || out.V.Theme != "light" {
+			t.Errorf("expected round trip, got %+v, %v", out.V, err)
+		}
+	})
+
+	t.Run("null scans as zero", func(t *testing.T) {
+		out := JSON[encodedSettings]{V: encodedSettings{Theme: "stale"}}
+		if err := out.Scan(nil); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if out.V.Theme != "" {
+			t.Errorf("expected zero value, got %+v", out.V)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var out JSON[encodedSettings]
+		if err := out.Scan(int64(1)); err == nil {
+			t.Error("expected error scanning an integer")
+		}
+		if err := out.Scan("{"); err == nil {
+			t.Error("expected decode error")
+		}
+		if _, err := (JSON[func()]{}).Value(); err == nil {
+			t.Error("expected encode error")
+		}
+	})
+
+	t.Run("marshals as the value", func(t *testing.T) {
+		data, err := json.Marshal(encodedDBUser{ID: "1", Settings: JSON[encodedSettings]{V: encodedSettings{Theme: "dark"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"Settings":{"theme":"dark","tags":null}`) {
+			t.Errorf("unexpected JSON %s", data)
+		}
+		var back encodedDBUser
+		if err := json.Unmarshal(data, &back); err != nil || back.Settings.V.Theme != "dark" {
+			t.Errorf("expected round trip, got %+v, %v", back, err)
+		}
+	})
+}
+
+func TestDatabase_Encoded(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture, cfg := mockdb.NewWithConfig()
+	defer cfg.Reset()
+	db, err := NewDatabase[encodedDBUser](mockDB, "users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	blob, err := (Encoded[encodedSettings, GobCodec]{V: encodedSettings{Theme: "blob"}}).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetRows([]string{"id", "settings", "blob"},
+		[]driver.Value{"1", `{"theme":"dark","tags":["x","y"]}`, blob})
+	got, err := db.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Settings.V.Theme != "dark" || len(got.Settings.V.Tags) != 2 || got.Blob.V.Theme != "blob" {
+		t.Errorf("unexpected record %+v", got)
+	}
+
+	capture.Reset()
+	_ = db.Set(ctx, "1", got)
+	last, _ := capture.Last()
+	var bound bool
+	for _, arg := range last.Args {
+		if arg == `{"theme":"dark","tags":["x","y"]}` {
+			bound = true
+		}
+	}
+	if !bound {
+		t.Errorf("expected encoded settings bound, got %v", last.Args)
+	}
+}
+
+// mapDBUser holds a JSON column whose value type has interface fields.
+type mapDBUser struct {
+	ID       string               `db:"id" constraints:"primarykey"`
+	Settings JSON[map[string]any] `db:"settings"`
+}
+
+func TestDatabase_EncodedMap(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture, cfg := mockdb.NewWithConfig()
+	defer cfg.Reset()
+	db, err := NewDatabase[mapDBUser](mockDB, "users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	cfg.SetRows([]string{"id", "settings"}, []driver.Value{"1", `{"theme":"dark","size":12}`})
+	got, err := db.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Settings.V["theme"] != "dark" || got.Settings.V["size"] != float64(12) {
+		t.Errorf("unexpected settings %v", got.Settings.V)
+	}
+
+	capture.Reset()
+	_ = db.Set(ctx, "1", &mapDBUser{ID: "1", Settings: JSON[map[string]any]{V: map[string]any{"theme": "light"}}})
+	last, _ := capture.Last()
+	var bound bool
+	for _, arg := range last.Args {
+		if arg == `{"theme":"light"}` {
+			bound = true
+		}
+	}
+	if !bound {
+		t.Errorf("expected encoded settings bound, got %v", last.Args)
+	}
+}
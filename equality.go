@@ -0,0 +1,52 @@
+package grub
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zoobzio/vecna"
+)
+
+// EqualityFilter converts a typed Search filter into the equivalent vecna
+// filter: an Eq condition on every field the codec encodes from filter,
+// joined with And. It bridges Search and Query, so an equality filter
+// written as a *T can be combined with range, Like, or Contains conditions:
+//
+//	b, _ := vecna.New[Embedding]()
+//	eq, err := index.EqualityFilter(&Embedding{Category: "tech"})
+//	results, err := index.Query(ctx, vector, 10, b.And(eq, b.Where("score").Gte(0.8)))
+//
+// A nil filter, or one that encodes no fields, returns a nil filter, which
+// matches everything. Returns ErrInvalidQuery if T is not a struct or an
+// encoded field has no counterpart in T's vecna schema.
+func (i *Index[T]) EqualityFilter(filter *T) (*vecna.Filter, error) {
+	m, err := i.encodeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	b, err := vecna.New[T]()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+
+	fields := make([]string, 0, len(m))
+	for field := range m {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	conditions := make([]*vecna.Filter, len(fields))
+	for n, field := range fields {
+		cond := b.Where(field).Eq(m[field])
+		if err := cond.Err(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+		}
+		conditions[n] = cond
+	}
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return b.And(conditions...), nil
+}
@@ -0,0 +1,90 @@
+package grub
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/vecna"
+)
+
+type equalityMetadata struct {
+	Category string   `json:"category,omitempty"`
+	Score    int      `json:"score,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+func TestIndex_EqualityFilter(t *testing.T) {
+	index := NewIndex[equalityMetadata](newMockVectorProvider())
+
+	t.Run("nil filter", func(t *testing.T) {
+		f, err := index.EqualityFilter(nil)
+		if err != nil || f != nil {
+			t.Errorf("expected nil filter, got %v, %v", f, err)
+		}
+	})
+
+	t.Run("no encoded fields", func(t *testing.T) {
+		f, err := index.EqualityFilter(&equalityMetadata{})
+		if err != nil || f != nil {
+			t.Errorf("expected nil filter, got %v, %v", f, err)
+		}
+	})
+
+	t.Run("single field", func(t *testing.T) {
+		f, err := index.EqualityFilter(&equalityMetadata{Category: "tech"})
+		if err != nil {
+			t.Fatalf("EqualityFilter failed: %v", err)
+		}
+		if f.Op() != vecna.Eq || f.Field() != "category" || f.Value() != "tech" {
+			t.Errorf("expected category = tech, got %s %s %v", f.Field(), f.Op(), f.Value())
+		}
+	})
+
+	t.Run("fields joined with and", func(t *testing.T) {
+		f, err := index.EqualityFilter(&equalityMetadata{Category: "tech", Score: 3})
+		if err != nil {
+			t.Fatalf("EqualityFilter failed: %v", err)
+		}
+		if f.Op() != vecna.And || len(f.Children()) != 2 {
+			t.Fatalf("expected an AND of two conditions, got %s with %d", f.Op(), len(f.Children()))
+		}
+		for _, tc := range []struct {
+			metadata map[string]any
+			want     bool
+		}{
+			{map[string]any{"category": "tech", "score": 3}, true},
+			{map[string]any{"category": "tech", "score": 4}, false},
+			{map[string]any{"category": "news", "score": 3}, false},
+		} {
+			if got, _ := MatchFilter(f, tc.metadata); got != tc.want {
+				t.Errorf("%v: expected %v, got %v", tc.metadata, tc.want, got)
+			}
+		}
+	})
+
+	t.Run("combined with range", func(t *testing.T) {
+		b, err := vecna.New[equalityMetadata]()
+		if err != nil {
+			t.Fatal(err)
+		}
+		eq, err := index.EqualityFilter(&equalityMetadata{Category: "tech"})
+		if err != nil {
+			t.Fatalf("EqualityFilter failed: %v", err)
+		}
+		f := b.And(eq, b.Where("score").Gte(5))
+		if ok, err := MatchFilter(f, map[string]any{"category": "tech", "score": 7}); !ok || err != nil {
+			t.Errorf("expected a match, got %v, %v", ok, err)
+		}
+		if ok, _ := MatchFilter(f, map[string]any{"category": "tech", "score": 2}); ok {
+			t.Error("expected the range condition to exclude score 2")
+		}
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		index := NewIndex[map[string]any](newMockVectorProvider())
+		_, err := index.EqualityFilter(&map[string]any{"category": "tech"})
+		if !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("expected ErrInvalidQuery, got %v", err)
+		}
+	})
+}
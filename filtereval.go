@@ -0,0 +1,182 @@
+package grub
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/zoobzio/vecna"
+)
+
+// MatchFilter reports whether metadata satisfies filter, evaluating every
+// vecna operator in process. metadata is a decoded JSON object keyed by the
+// same field names the filter uses; numbers may be any Go numeric type or
+// json.Number. A nil filter matches everything.
+//
+// A condition on a field absent from metadata does not match, except Ne and
+// Nin, which do. Gt, Gte, Lt, and Lte compare numerically and never match a
+// non-numeric value. Like treats
+// % as any sequence of characters and _ as any single character, case-
+// sensitively. Contains requires the field to hold an array.
+//
+// Returns ErrInvalidQuery if the filter contains validation errors.
+func MatchFilter(filter *vecna.Filter, metadata map[string]any) (bool, error) {
+	if filter == nil {
+		return true, nil
+	}
+	if err := filter.Err(); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	return matchNode(filter, metadata)
+}
+
+// matchNode evaluates one filter node against metadata.
+func matchNode(f *vecna.Filter, metadata map[string]any) (bool, error) {
+	switch f.Op() {
+	case vecna.And:
+		for _, child := range f.Children() {
+			ok, err := matchNode(child, metadata)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case vecna.Or:
+		for _, child := range f.Children() {
+			ok, err := matchNode(child, metadata)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case vecna.Not:
+		children := f.Children()
+		if len(children) != 1 {
+			return false, fmt.Errorf("%w: NOT requires exactly one child", ErrInvalidQuery)
+		}
+		ok, err := matchNode(children[0], metadata)
+		return !ok && err == nil, err
+	}
+
+	value, present := metadata[f.Field()]
+	switch f.Op() {
+	case vecna.Eq:
+		return present && valuesEqual(value, f.Value()), nil
+	case vecna.Ne:
+		return !present || !valuesEqual(value, f.Value()), nil
+	case vecna.Gt, vecna.Gte, vecna.Lt, vecna.Lte:
+		if !present {
+			return false, nil
+		}
+		x, ok := toFloat(value)
+		if !ok {
+			return false, nil
+		}
+		y, ok := toFloat(f.Value())
+		if !ok {
+			return false, nil
+		}
+		cmp := 0
+		if x < y {
+			cmp = -1
+		} else if x > y {
+			cmp = 1
+		}
+		switch f.Op() {
+		case vecna.Gt:
+			return cmp > 0, nil
+		case vecna.Gte:
+			return cmp >= 0, nil
+		case vecna.Lt:
+			return cmp < 0, nil
+		default:
+			return cmp <= 0, nil
+		}
+	case vecna.In:
+		return present && sliceContains(f.Value(), value), nil
+	case vecna.Nin:
+		return !present || !sliceContains(f.Value(), value), nil
+	case vecna.Like:
+		s, ok := value.(string)
+		pattern, _ := f.Value().(string)
+		return ok && likeMatch(s, pattern), nil
+	case vecna.Contains:
+		return present && sliceContains(value, f.Value()), nil
+	default:
+		return false, fmt.Errorf("%w: %s", ErrOperatorNotSupported, f.Op())
+	}
+}
+
+// valuesEqual compares two metadata values, treating numbers of any type as
+// equal when numerically equal.
+func valuesEqual(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// sliceContains reports whether the slice or array list holds an element
+// equal to v.
+func sliceContains(list, v any) bool {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+	for n := 0; n < rv.Len(); n++ {
+		if valuesEqual(rv.Index(n).Interface(), v) {
+			return true
+		}
+	}
+	return false
+}
+
+// toFloat converts any Go numeric value or json.Number to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case nil, bool, string:
+		return 0, false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// likeMatch matches s against a SQL LIKE pattern, where % matches any
+// sequence of characters and _ any single character.
+func likeMatch(s, pattern string) bool {
+	str, pat := []rune(s), []rune(pattern)
+	si, pi := 0, 0
+	star, mark := -1, 0
+	for si < len(str) {
+		switch {
+		case pi < len(pat) && pat[pi] == '%':
+			star, mark = pi, si
+			pi++
+		case pi < len(pat) && (pat[pi] == '_' || pat[pi] == str[si]):
+			si++
+			pi++
+		case star >= 0:
+			mark++
+			si, pi = mark, star+1
+		default:
+			return false
+		}
+	}
+	for pi < len(pat) && pat[pi] == '%' {
+		pi++
+	}
+	return pi == len(pat)
+}
@@ -0,0 +1,118 @@
+package grub
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/vecna"
+)
+
+type filterMetadata struct {
+	Category string   `json:"category"`
+	Score    float64  `json:"score"`
+	Tags     []string `json:"tags"`
+	Active   bool     `json:"active"`
+}
+
+func TestMatchFilter(t *testing.T) {
+	b, err := vecna.New[filterMetadata]()
+	if err != nil {
+		t.Fatalf("vecna.New failed: %v", err)
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal([]byte(`{"category":"tech","score":7,"tags":["go","db"],"active":true}`), &metadata); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter *vecna.Filter
+		want   bool
+	}{
+		{"nil", nil, true},
+		{"eq", b.Where("category").Eq("tech"), true},
+		{"eq miss", b.Where("category").Eq("art"), false},
+		{"eq int against float", b.Where("score").Eq(7), true},
+		{"eq bool", b.Where("active").Eq(true), true},
+		{"ne", b.Where("category").Ne("art"), true},
+		{"gt", b.Where("score").Gt(6), true},
+		{"gt equal", b.Where("score").Gt(7), false},
+		{"gte", b.Where("score").Gte(7), true},
+		{"lt", b.Where("score").Lt(7.5), true},
+		{"lte", b.Where("score").Lte(6.9), false},
+		{"in", b.Where("category").In("art", "tech"), true},
+		{"in miss", b.Where("category").In("art", "food"), false},
+		{"nin", b.Where("category").Nin("art", "food"), true},
+		{"like prefix", b.Where("category").Like("te%"), true},
+		{"like single", b.Where("category").Like("t_ch"), true},
+		{"like anchored", b.Where("category").Like("ech%"), false},
+		{"like case", b.Where("category").Like("Tech"), false},
+		{"contains", b.Where("tags").Contains("db"), true},
+		{"contains miss", b.Where("tags").Contains("rust"), false},
+		{"and", b.And(b.Where("category").Eq("tech"), b.Where("score").Gt(5)), true},
+		{"and short", b.And(b.Where("category").Eq("tech"), b.Where("score").Gt(9)), false},
+		{"or", b.Or(b.Where("category").Eq("art"), b.Where("score").Gt(5)), true},
+		{"not", b.Not(b.Where("category").Eq("art")), true},
+		{"not match", b.Not(b.Where("tags").Contains("go")), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MatchFilter(tc.filter, metadata)
+			if err != nil {
+				t.Fatalf("MatchFilter failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("missing field", func(t *testing.T) {
+		empty := map[string]any{}
+		for filter, want := range map[*vecna.Filter]bool{
+			b.Where("category").Eq("tech"):  false,
+			b.Where("category").Ne("tech"):  true,
+			b.Where("score").Gt(0):          false,
+			b.Where("category").Nin("tech"): true,
+			b.Where("tags").Contains("go"):  false,
+		} {
+			got, err := MatchFilter(filter, empty)
+			if err != nil {
+				t.Fatalf("MatchFilter failed: %v", err)
+			}
+			if got != want {
+				t.Errorf("%s on missing field: expected %v, got %v", filter.Op(), want, got)
+			}
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := MatchFilter(b.Where("nope").Eq(1), metadata)
+		if !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("expected ErrInvalidQuery, got %v", err)
+		}
+	})
+}
+
+func TestLikeMatch(t *testing.T) {
+	for _, tc := range []struct {
+		s, pattern string
+		want       bool
+	}{
+		{"", "", true},
+		{"", "%", true},
+		{"abc", "%", true},
+		{"abc", "a%c", true},
+		{"abc", "a%%", true},
+		{"abc", "%b%", true},
+		{"abc", "___", true},
+		{"abc", "__", false},
+		{"abcbc", "%bc", true},
+		{"abd", "%bc", false},
+		{"café", "caf_", true},
+	} {
+		if got := likeMatch(tc.s, tc.pattern); got != tc.want {
+			t.Errorf("likeMatch(%q, %q): expected %v, got %v", tc.s, tc.pattern, tc.want, got)
+		}
+	}
+}
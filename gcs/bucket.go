@@ -5,6 +5,9 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sort"
+	"strconv"
+	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/zoobzio/grub"
@@ -61,6 +64,13 @@ func (p *Provider) Get(ctx context.Context, key string) ([]byte, *grub.ObjectInf
 
 // Put stores data at key with associated metadata.
 func (p *Provider) Put(ctx context.Context, key string, data []byte, info *grub.ObjectInfo) error {
+	_, err := p.PutInfo(ctx, key, data, info)
+	return err
+}
+
+// PutInfo stores data at key and returns the attributes reported once the
+// upload completes. The object generation is used as the version ID.
+func (p *Provider) PutInfo(ctx context.Context, key string, data []byte, info *grub.ObjectInfo) (*grub.ObjectInfo, error) {
 	obj := p.client.Bucket(p.bucket).Object(key)
 	writer := obj.NewWriter(ctx)
 
@@ -71,14 +81,147 @@ func (p *Provider) Put(ctx context.Context, key string, data []byte, info *grub.
 		if len(info.Metadata) > 0 {
 			writer.Metadata = info.Metadata
 		}
+		writer.CustomTime = info.ExpiresAt
 	}
 
 	if _, err := writer.Write(data); err != nil {
 		_ = writer.Close()
-		return err
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	attrs := writer.Attrs()
+	result := &grub.ObjectInfo{
+		Key:          key,
+		ContentType:  attrs.ContentType,
+		Size:         attrs.Size,
+		ETag:         attrs.Etag,
+		Metadata:     attrs.Metadata,
+		VersionID:    strconv.FormatInt(attrs.Generation, 10),
+		LastModified: attrs.Updated,
+	}
+	if info != nil {
+		result.ExpiresAt = info.ExpiresAt
+	}
+	return result, nil
+}
+
+// ExpiryPrecision implements grub.BucketExpirer. Put stores ExpiresAt as
+// the object's custom time, which the ExpiryRule lifecycle rule deletes a
+// whole day after, checking about once a day, so an object may outlive its
+// ExpiresAt by up to two days.
+func (p *Provider) ExpiryPrecision() time.Duration {
+	return 48 * time.Hour
+}
+
+// ExpiryRule returns the lifecycle rule deleting objects whose custom time,
+// set by Put from ExpiresAt, has passed. Add it to the bucket's lifecycle.
+func ExpiryRule() storage.LifecycleRule {
+	return storage.LifecycleRule{
+		Action:    storage.LifecycleAction{Type: storage.DeleteAction},
+		Condition: storage.LifecycleCondition{DaysSinceCustomTime: 1},
+	}
+}
+
+// ListVersions returns every generation of key, newest first. The bucket
+// must have object versioning enabled for noncurrent generations to be kept.
+// GCS has no delete markers; a deleted key's generations are all noncurrent.
+func (p *Provider) ListVersions(ctx context.Context, key string) ([]grub.ObjectVersion, error) {
+	var results []grub.ObjectVersion
+
+	query := &storage.Query{Prefix: key, Versions: true}
+	it := p.client.Bucket(p.bucket).Objects(ctx, query)
+
+	var generations []int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Prefix also matches longer keys; keep only exact matches.
+		if attrs.Name != key {
+			continue
+		}
+
+		generations = append(generations, attrs.Generation)
+		results = append(results, grub.ObjectVersion{
+			Key:          key,
+			VersionID:    strconv.FormatInt(attrs.Generation, 10),
+			Size:         attrs.Size,
+			ETag:         attrs.Etag,
+			LastModified: attrs.Updated,
+			IsLatest:     attrs.Deleted.IsZero(),
+		})
 	}
 
-	return writer.Close()
+	if len(results) == 0 {
+		return nil, grub.ErrNotFound
+	}
+	sort.Sort(byGeneration{results, generations})
+	return results, nil
+}
+
+// GetVersion retrieves a specific generation of the blob at key.
+func (p *Provider) GetVersion(ctx context.Context, key, versionID string) ([]byte, *grub.ObjectInfo, error) {
+	gen, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		return nil, nil, grub.ErrNotFound
+	}
+	obj := p.client.Bucket(p.bucket).Object(key).Generation(gen)
+
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, nil, grub.ErrNotFound
+		}
+		return nil, nil, err
+	}
+
+	reader, err := obj.NewReader(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &grub.ObjectInfo{
+		Key:          key,
+		ContentType:  attrs.ContentType,
+		Size:         attrs.Size,
+		ETag:         attrs.Etag,
+		Metadata:     attrs.Metadata,
+		VersionID:    versionID,
+		LastModified: attrs.Updated,
+	}
+
+	return data, info, nil
+}
+
+// byGeneration sorts versions newest generation first.
+type byGeneration struct {
+	versions    []grub.ObjectVersion
+	generations []int64
+}
+
+func (s byGeneration) Len() int { return len(s.versions) }
+func (s byGeneration) Less(i, j int) bool {
+	return s.generations[i] > s.generations[j]
+}
+func (s byGeneration) Swap(i, j int) {
+	s.versions[i], s.versions[j] = s.versions[j], s.versions[i]
+	s.generations[i], s.generations[j] = s.generations[j], s.generations[i]
 }
 
 // Delete removes the blob at key.
@@ -107,6 +250,33 @@ func (p *Provider) Exists(ctx context.Context, key string) (bool, error) {
 	return true, nil
 }
 
+// Head returns the info for key, including its user metadata, without
+// downloading the body.
+func (p *Provider) Head(ctx context.Context, key string) (*grub.ObjectInfo, error) {
+	attrs, err := p.client.Bucket(p.bucket).Object(key).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, grub.ErrNotFound
+		}
+		return nil, err
+	}
+	return &grub.ObjectInfo{
+		Key:          key,
+		ContentType:  attrs.ContentType,
+		Size:         attrs.Size,
+		ETag:         attrs.Etag,
+		Metadata:     attrs.Metadata,
+		VersionID:    strconv.FormatInt(attrs.Generation, 10),
+		LastModified: attrs.Updated,
+	}, nil
+}
+
+// ListMetadata returns the same results as List; GCS listings already carry
+// user metadata.
+func (p *Provider) ListMetadata(ctx context.Context, prefix string, limit int) ([]grub.ObjectInfo, error) {
+	return p.List(ctx, prefix, limit)
+}
+
 // List returns object info for keys matching the given prefix.
 func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.ObjectInfo, error) {
 	var results []grub.ObjectInfo
@@ -115,6 +285,9 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.O
 	it := p.client.Bucket(p.bucket).Objects(ctx, query)
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		attrs, err := it.Next()
 		if errors.Is(err, iterator.Done) {
 			break
@@ -123,13 +296,7 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.O
 			return nil, err
 		}
 
-		results = append(results, grub.ObjectInfo{
-			Key:         attrs.Name,
-			ContentType: attrs.ContentType,
-			Size:        attrs.Size,
-			ETag:        attrs.Etag,
-			Metadata:    attrs.Metadata,
-		})
+		results = append(results, objectInfo(attrs))
 
 		if limit > 0 && len(results) >= limit {
 			break
@@ -138,3 +305,35 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.O
 
 	return results, nil
 }
+
+// ListPage returns up to limit objects under prefix, resuming from a GCS
+// page token. Listed objects carry all ObjectInfo fields. A limit of 0 or
+// less means 1000.
+func (p *Provider) ListPage(ctx context.Context, prefix string, limit int, cursor string) ([]grub.ObjectInfo, string, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	it := p.client.Bucket(p.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	var attrs []*storage.ObjectAttrs
+	next, err := iterator.NewPager(it, limit, cursor).NextPage(&attrs)
+	if err != nil {
+		return nil, "", err
+	}
+	results := make([]grub.ObjectInfo, 0, len(attrs))
+	for _, a := range attrs {
+		results = append(results, objectInfo(a))
+	}
+	return results, next, nil
+}
+
+// objectInfo converts listed object attributes.
+func objectInfo(attrs *storage.ObjectAttrs) grub.ObjectInfo {
+	return grub.ObjectInfo{
+		Key:          attrs.Name,
+		ContentType:  attrs.ContentType,
+		Size:         attrs.Size,
+		ETag:         attrs.Etag,
+		Metadata:     attrs.Metadata,
+		LastModified: attrs.Updated,
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/testcontainers/testcontainers-go"
@@ -175,6 +176,92 @@ func TestProvider_Put(t *testing.T) {
 	})
 }
 
+func TestProvider_PutInfo(t *testing.T) {
+	clearBucket(t)
+	ctx := context.Background()
+
+	data := []byte("receipt content")
+	info, err := testProvider.PutInfo(ctx, "put-info", data, &grub.ObjectInfo{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("PutInfo failed: %v", err)
+	}
+	if info.Key != "put-info" {
+		t.Errorf("unexpected key: %q", info.Key)
+	}
+	if info.Size != int64(len(data)) {
+		t.Errorf("expected size %d, got %d", len(data), info.Size)
+	}
+	if info.ETag == "" {
+		t.Error("expected ETag to be reported")
+	}
+	if info.LastModified.IsZero() {
+		t.Error("expected LastModified to be reported")
+	}
+	if info.VersionID == "" {
+		t.Error("expected generation as VersionID")
+	}
+}
+
+func TestProvider_PutExpiresAt(t *testing.T) {
+	clearBucket(t)
+	ctx := context.Background()
+
+	expires := time.Now().Add(36 * time.Hour).Truncate(time.Second)
+	if err := testProvider.Put(ctx, "expiring", []byte("tmp"), &grub.ObjectInfo{ExpiresAt: expires}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	attrs, err := testStorageClient.Bucket(testBucket).Object("expiring").Attrs(ctx)
+	if err != nil {
+		t.Fatalf("Attrs failed: %v", err)
+	}
+	if !attrs.CustomTime.Equal(expires) {
+		t.Errorf("expected custom time %v, got %v", expires, attrs.CustomTime)
+	}
+	if rule := ExpiryRule(); rule.Action.Type != storage.DeleteAction || rule.Condition.DaysSinceCustomTime != 1 {
+		t.Errorf("unexpected rule %+v", rule)
+	}
+}
+
+func TestProvider_Versions(t *testing.T) {
+	ctx := context.Background()
+	const versionedBucket = "test-versioned"
+
+	attrs := &storage.BucketAttrs{VersioningEnabled: true}
+	if err := testStorageClient.Bucket(versionedBucket).Create(ctx, "test-project", attrs); err != nil {
+		t.Fatalf("failed to create bucket: %v", err)
+	}
+	provider := New(testStorageClient, versionedBucket)
+
+	var ids []string
+	for _, body := range []string{"first", "second"} {
+		info, err := provider.PutInfo(ctx, "doc", []byte(body), nil)
+		if err != nil {
+			t.Fatalf("PutInfo failed: %v", err)
+		}
+		ids = append(ids, info.VersionID)
+	}
+
+	versions, err := provider.ListVersions(ctx, "doc")
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	if len(versions) != 2 || versions[0].VersionID != ids[1] {
+		t.Fatalf("expected 2 versions newest first, got %+v", versions)
+	}
+
+	data, info, err := provider.GetVersion(ctx, "doc", ids[0])
+	if err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if string(data) != "first" || info.VersionID != ids[0] {
+		t.Errorf("expected first version, got %q (%s)", data, info.VersionID)
+	}
+
+	if _, err := provider.ListVersions(ctx, "missing"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
 func TestProvider_Delete(t *testing.T) {
 	clearBucket(t)
 	ctx := context.Background()
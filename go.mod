@@ -9,6 +9,7 @@ require (
 	github.com/jmoiron/sqlx v1.4.0
 	github.com/zoobzio/astql v1.0.6
 	github.com/zoobzio/atom v1.0.0
+	github.com/zoobzio/capitan v1.0.0
 	github.com/zoobzio/edamame v1.0.1
 	github.com/zoobzio/sentinel v1.0.2
 	github.com/zoobzio/soy v1.0.5
@@ -16,7 +17,6 @@ require (
 )
 
 require (
-	github.com/zoobzio/capitan v1.0.0 // indirect
 	github.com/zoobzio/dbml v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,283 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/edamame"
+)
+
+// History selects how a Database answers time-travel reads.
+type History string
+
+const (
+	// HistoryNative reads a system-versioned table with FOR SYSTEM_TIME AS
+	// OF, the database keeping every row version itself. Supported on
+	// MariaDB and SQL Server.
+	HistoryNative History = "native"
+
+	// HistoryShadow reads <table>_history, a shadow table holding every
+	// row version with valid_from and valid_to columns, kept up to date by
+	// triggers EnsureHistory installs. Supported on Postgres.
+	HistoryShadow History = "shadow"
+)
+
+// asOfParam is the named parameter time-travel reads bind the point in
+// time to.
+const asOfParam = "grub_as_of"
+
+// GetAsOf retrieves the record at key as it was at time at.
+// Returns ErrNotFound if no version of the record existed at that time, and
+// ErrUnsupported unless WithHistory names a strategy the dialect supports.
+// Times are compared in UTC.
+func (d *Database[T]) GetAsOf(ctx context.Context, key string, at time.Time) (*T, error) {
+	if err := d.checkHistory(); err != nil {
+		return nil, d.wrapErr("get_as_of", key, err)
+	}
+	sel, err := d.executor.Soy().Select().Where(d.keyCol, "=", "key").Render()
+	if err != nil {
+		return nil, d.wrapErr("get_as_of", key, err)
+	}
+	records, err := d.queryAsOf(ctx, sel.SQL, map[string]any{"key": key}, at)
+	if err != nil {
+		return nil, d.wrapErr("get_as_of", key, err)
+	}
+	if len(records) == 0 {
+		return nil, d.wrapErr("get_as_of", key, ErrNotFound)
+	}
+	return records[0], nil
+}
+
+// ExecQueryAsOf executes a query statement against the table as it was at
+// time at. Results are never cached. Returns ErrUnsupported unless
+// WithHistory names a strategy the dialect supports.
+func (d *Database[T]) ExecQueryAsOf(ctx context.Context, stmt edamame.QueryStatement, params map[string]any, at time.Time) ([]*T, error) {
+	if err := d.checkHistory(); err != nil {
+		return nil, d.wrapErr("exec_query_as_of", "", err)
+	}
+	render := func() (string, error) { return d.executor.RenderQuery(stmt) }
+	if err := checkParams(stmt, params, render); err != nil {
+		return nil, d.wrapErr("exec_query_as_of", "", err)
+	}
+	query, err := render()
+	if err != nil {
+		return nil, d.wrapErr("exec_query_as_of", "", err)
+	}
+	query, args, _ := expandIn(query, params)
+	records, err := d.queryAsOf(d.withStatement(ctx, stmt.Name(), params), query, args, at)
+	if err != nil {
+		return nil, d.wrapErr("exec_query_as_of", "", err)
+	}
+	return records, nil
+}
+
+// queryAsOf rewrites query to read the table as of at and runs it.
+func (d *Database[T]) queryAsOf(ctx context.Context, query string, params map[string]any, at time.Time) ([]*T, error) {
+	query, err := d.asOfSQL(query)
+	if err != nil {
+		return nil, err
+	}
+	args := make(map[string]any, len(params)+1)
+	maps.Copy(args, params)
+	args[asOfParam] = at.UTC()
+
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	return read(callCtx, d, func(c conn[T]) ([]*T, error) {
+		return scanRecords(callCtx, c.execer(nil), query, args, d.afterLoad)
+	})
+}
+
+// checkHistory reports ErrUnsupported unless the configured strategy is
+// supported by the dialect.
+func (d *Database[T]) checkHistory() error {
+	switch {
+	case d.history == "":
+		return fmt.Errorf("%w: time-travel reads require WithHistory", ErrUnsupported)
+	case d.history == HistoryNative && (d.dialect == "mariadb" || d.dialect == "mssql"):
+		return nil
+	case d.history == HistoryShadow && d.dialect == "postgres":
+		return nil
+	}
+	dialect := d.dialect
+	if dialect == "" {
+		dialect = "custom renderer"
+	}
+	return fmt.Errorf("%w: %s history on %s", ErrUnsupported, d.history, dialect)
+}
+
+// asOfSQL rewrites the table reference in a rendered SELECT to read the
+// versions current at :grub_as_of: a FOR SYSTEM_TIME clause for native
+// history, or a subquery over the shadow table, aliased to the table name,
+// for shadow history.
+func (d *Database[T]) asOfSQL(query string) (string, error) {
+	from := "FROM " + d.quote(d.tableName)
+	n := strings.Index(query, from)
+	if n < 0 {
+		return "", fmt.Errorf("grub: no %q in rendered query", from)
+	}
+	var source string
+	switch d.history {
+	case HistoryNative:
+		clause := " FOR SYSTEM_TIME AS OF :" + asOfParam
+		if d.dialect == "mariadb" {
+			clause = " FOR SYSTEM_TIME AS OF TIMESTAMP :" + asOfParam
+		}
+		source = from + clause
+	default:
+		source = fmt.Sprintf("FROM (SELECT %s FROM %s WHERE %s <= :%s AND (%s IS NULL OR %s > :%s)) AS %s",
+			strings.Join(d.quotedColumns(), ", "),
+			d.quote(d.historyTable()),
+			d.quote("valid_from"), asOfParam,
+			d.quote("valid_to"), d.quote("valid_to"), asOfParam,
+			d.quote(d.tableName))
+	}
+	return query[:n] + source + query[n+len(from):], nil
+}
+
+// historyTable returns the name of the table row versions are kept in.
+func (d *Database[T]) historyTable() string {
+	return d.tableName + "_history"
+}
+
+// quote quotes an identifier for the dialect.
+func (d *Database[T]) quote(name string) string {
+	return quoteIdent(d.dialect, name)
+}
+
+// quotedColumns returns the quoted db columns of T.
+func (d *Database[T]) quotedColumns() []string {
+	var cols []string
+	for _, field := range d.executor.Soy().Metadata().Fields {
+		col := field.Tags["db"]
+		if col == "" || col == "-" {
+			continue
+		}
+		cols = append(cols, d.quote(col))
+	}
+	return cols
+}
+
+// EnsureHistory prepares the table for the WithHistory strategy and is safe
+// to call repeatedly.
+//
+// For HistoryNative on MariaDB it adds system versioning to the table. On
+// SQL Server it adds hidden valid_from and valid_to period columns and turns
+// on system versioning with <table>_history as the history table.
+//
+// For HistoryShadow on Postgres it creates <table>_history with the table's
+// columns plus valid_from and valid_to, and installs a trigger that closes
+// the current version and records the new one on every insert, update, and
+// delete. Rows already in the table are recorded as of the first call.
+// Columns added to the table later must be added to <table>_history too.
+//
+// Returns ErrUnsupported unless WithHistory names a strategy the dialect
+// supports.
+func (d *Database[T]) EnsureHistory(ctx context.Context) error {
+	if d.readOnly {
+		return d.wrapErr("ensure_history", "", ErrReadOnly)
+	}
+	if err := d.checkHistory(); err != nil {
+		return d.wrapErr("ensure_history", "", err)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	var err error
+	switch d.dialect {
+	case "mariadb":
+		err = d.ensureMariaDBVersioning(callCtx)
+	case "mssql":
+		err = d.ensureMSSQLVersioning(callCtx)
+	default:
+		err = d.execAll(callCtx, d.shadowHistoryDDL())
+	}
+	return d.wrapErr("ensure_history", "", err)
+}
+
+// ensureMariaDBVersioning adds system versioning unless the table has it.
+func (d *Database[T]) ensureMariaDBVersioning(ctx context.Context) error {
+	var tableType string
+	err := d.db.QueryRowxContext(ctx, d.db.Rebind(
+		`SELECT table_type FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?`),
+		d.tableName).Scan(&tableType)
+	if err != nil {
+		return err
+	}
+	if tableType == "SYSTEM VERSIONED" {
+		return nil
+	}
+	return d.execAll(ctx, []string{"ALTER TABLE " + d.quote(d.tableName) + " ADD SYSTEM VERSIONING"})
+}
+
+// ensureMSSQLVersioning adds the period columns and turns on system
+// versioning unless the table is already a temporal table.
+func (d *Database[T]) ensureMSSQLVersioning(ctx context.Context) error {
+	var temporal sql.NullInt64
+	var schema string
+	err := d.db.QueryRowxContext(ctx, d.db.Rebind(
+		`SELECT OBJECTPROPERTY(OBJECT_ID(?), 'TableTemporalType'), SCHEMA_NAME()`),
+		d.tableName).Scan(&temporal, &schema)
+	if err != nil {
+		return err
+	}
+	if temporal.Int64 == 2 { // SYSTEM_VERSIONED_TEMPORAL_TABLE
+		return nil
+	}
+	table := d.quote(d.tableName)
+	return d.execAll(ctx, []string{
+		fmt.Sprintf(`ALTER TABLE %s ADD
+			[valid_from] DATETIME2 GENERATED ALWAYS AS ROW START HIDDEN NOT NULL
+				CONSTRAINT %s DEFAULT SYSUTCDATETIME(),
+			[valid_to] DATETIME2 GENERATED ALWAYS AS ROW END HIDDEN NOT NULL
+				CONSTRAINT %s DEFAULT CONVERT(DATETIME2, '9999-12-31 23:59:59.9999999'),
+			PERIOD FOR SYSTEM_TIME ([valid_from], [valid_to])`,
+			table, d.quote(d.tableName+"_valid_from"), d.quote(d.tableName+"_valid_to")),
+		fmt.Sprintf(`ALTER TABLE %s SET (SYSTEM_VERSIONING = ON (HISTORY_TABLE = %s.%s))`,
+			table, d.quote(schema), d.quote(d.historyTable())),
+	})
+}
+
+// shadowHistoryDDL returns the Postgres statements that create the shadow
+// history table, its versioning trigger, and the first version of each
+// existing row.
+func (d *Database[T]) shadowHistoryDDL() []string {
+	table, history := d.quote(d.tableName), d.quote(d.historyTable())
+	key := d.quote(d.keyCol)
+	fn := d.quote(d.tableName + "_versioning")
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (LIKE %s, "valid_from" TIMESTAMPTZ NOT NULL, "valid_to" TIMESTAMPTZ)`,
+			history, table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (%s, "valid_from")`,
+			d.quote(d.historyTable()+"_key"), history, key),
+		fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		UPDATE %s SET "valid_to" = now() WHERE %s = OLD.%s AND "valid_to" IS NULL;
+	END IF;
+	IF TG_OP IN ('INSERT', 'UPDATE') THEN
+		INSERT INTO %s SELECT NEW.*, now(), NULL;
+	END IF;
+	RETURN NULL;
+END
+$$`, fn, history, key, key, history),
+		fmt.Sprintf(`DROP TRIGGER IF EXISTS %s ON %s`, fn, table),
+		fmt.Sprintf(`CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE FUNCTION %s()`,
+			fn, table, fn),
+		fmt.Sprintf(`INSERT INTO %s SELECT t.*, now(), NULL FROM %s t WHERE NOT EXISTS (SELECT 1 FROM %s h WHERE h.%s = t.%s AND h."valid_to" IS NULL)`,
+			history, table, history, key, key),
+	}
+}
+
+// execAll runs statements in order, stopping at the first error.
+func (d *Database[T]) execAll(ctx context.Context, statements []string) error {
+	for _, stmt := range statements {
+		if _, err := d.db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/astql"
+	astqlmariadb "github.com/zoobzio/astql/mariadb"
+	astqlmssql "github.com/zoobzio/astql/mssql"
+	astqlpostgres "github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestDatabase_GetAsOf(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		history  History
+		want     string
+	}{
+		{"mssql native", astqlmssql.New(), HistoryNative,
+			`FROM [test_users] FOR SYSTEM_TIME AS OF ? WHERE [id] = ?`},
+		{"mariadb native", astqlmariadb.New(), HistoryNative,
+			"FROM `test_users` FOR SYSTEM_TIME AS OF TIMESTAMP ? WHERE `id` = ?"},
+		{"postgres shadow", astqlpostgres.New(), HistoryShadow,
+			`FROM (SELECT "id", "email", "name", "age" FROM "test_users_history" WHERE "valid_from" <= ? AND ("valid_to" IS NULL OR "valid_to" > ?)) AS "test_users" WHERE "id" = ?`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, capture, cfg := mockdb.NewWithConfig()
+			cfg.SetRows([]string{"id", "email", "name", "age"}, []driver.Value{int64(1), "a@example.com", "Alice", nil})
+			db, err := NewDatabase[TestDBUser](mockDB, "test_users", tt.renderer, WithHistory(tt.history))
+			if err != nil {
+				t.Fatalf("NewDatabase failed: %v", err)
+			}
+
+			user, err := db.GetAsOf(context.Background(), "1", at)
+			if err != nil {
+				t.Fatalf("GetAsOf failed: %v", err)
+			}
+			if user.Name != "Alice" {
+				t.Errorf("expected Alice, got %q", user.Name)
+			}
+			last, _ := capture.Last()
+			if !strings.Contains(last.Query, tt.want) {
+				t.Errorf("expected %q in query, got: %s", tt.want, last.Query)
+			}
+			var bound bool
+			for _, arg := range last.Args {
+				if ts, ok := arg.(time.Time); ok {
+					bound = true
+					if ts.Location() != time.UTC || !ts.Equal(at) {
+						t.Errorf("expected %v in UTC, got %v", at, ts)
+					}
+				}
+			}
+			if !bound {
+				t.Errorf("expected the point in time to be bound, got %v", last.Args)
+			}
+		})
+	}
+}
+
+func TestDatabase_GetAsOf_NotFound(t *testing.T) {
+	mockDB, _, cfg := mockdb.NewWithConfig()
+	cfg.SetRows([]string{"id", "email", "name", "age"})
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlmssql.New(), WithHistory(HistoryNative))
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	if _, err := db.GetAsOf(context.Background(), "1", time.Now()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabase_AsOfUnsupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		opts     []Option
+	}{
+		{"no history", astqlmssql.New(), nil},
+		{"native on postgres", astqlpostgres.New(), []Option{WithHistory(HistoryNative)}},
+		{"shadow on mssql", astqlmssql.New(), []Option{WithHistory(HistoryShadow)}},
+		{"sqlite", testDBRenderer, []Option{WithHistory(HistoryNative)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, capture := mockdb.New()
+			db, err := NewDatabase[TestDBUser](mockDB, "test_users", tt.renderer, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewDatabase failed: %v", err)
+			}
+			ctx := context.Background()
+			if _, err := db.GetAsOf(ctx, "1", time.Now()); !errors.Is(err, ErrUnsupported) {
+				t.Errorf("GetAsOf: expected ErrUnsupported, got %v", err)
+			}
+			if _, err := db.ExecQueryAsOf(ctx, QueryAll, nil, time.Now()); !errors.Is(err, ErrUnsupported) {
+				t.Errorf("ExecQueryAsOf: expected ErrUnsupported, got %v", err)
+			}
+			if err := db.EnsureHistory(ctx); !errors.Is(err, ErrUnsupported) {
+				t.Errorf("EnsureHistory: expected ErrUnsupported, got %v", err)
+			}
+			if len(capture.Queries) != 0 {
+				t.Errorf("expected no queries, got %d", len(capture.Queries))
+			}
+		})
+	}
+}
+
+func TestDatabase_ExecQueryAsOf(t *testing.T) {
+	mockDB, capture, cfg := mockdb.NewWithConfig()
+	cfg.SetRows([]string{"id", "email", "name", "age"},
+		[]driver.Value{int64(1), "a@example.com", "Alice", nil},
+		[]driver.Value{int64(2), "b@example.com", "Bob", nil})
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlmssql.New(), WithHistory(HistoryNative))
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	users, err := db.ExecQueryAsOf(context.Background(), byNames, map[string]any{
+		"min_age": 18, "names": []string{"Alice", "Bob"}, "emails": []string{"c@example.com"},
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("ExecQueryAsOf failed: %v", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("expected 2 users, got %d", len(users))
+	}
+	last, _ := capture.Last()
+	for _, want := range []string{"FROM [test_users] FOR SYSTEM_TIME AS OF ? WHERE", "IN (?, ?)"} {
+		if !strings.Contains(last.Query, want) {
+			t.Errorf("expected %q in query, got: %s", want, last.Query)
+		}
+	}
+
+	if _, err := db.ExecQueryAsOf(context.Background(), byNames, map[string]any{"min_age": 18}, time.Now()); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("expected ErrInvalidParams, got %v", err)
+	}
+}
+
+func TestDatabase_EnsureHistory(t *testing.T) {
+	t.Run("postgres shadow", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlpostgres.New(), WithHistory(HistoryShadow))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if err := db.EnsureHistory(context.Background()); err != nil {
+			t.Fatalf("EnsureHistory failed: %v", err)
+		}
+		var all strings.Builder
+		for _, q := range capture.Queries {
+			all.WriteString(q.Query)
+			all.WriteString("\n")
+		}
+		for _, want := range []string{
+			`CREATE TABLE IF NOT EXISTS "test_users_history" (LIKE "test_users"`,
+			`UPDATE "test_users_history" SET "valid_to" = now() WHERE "id" = OLD."id"`,
+			`CREATE TRIGGER "test_users_versioning" AFTER INSERT OR UPDATE OR DELETE ON "test_users"`,
+			`INSERT INTO "test_users_history" SELECT t.*, now(), NULL FROM "test_users" t`,
+		} {
+			if !strings.Contains(all.String(), want) {
+				t.Errorf("expected %q, got:\n%s", want, all.String())
+			}
+		}
+	})
+
+	t.Run("mariadb native", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRows([]string{"table_type"}, []driver.Value{"BASE TABLE"})
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlmariadb.New(), WithHistory(HistoryNative))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if err := db.EnsureHistory(context.Background()); err != nil {
+			t.Fatalf("EnsureHistory failed: %v", err)
+		}
+		last, _ := capture.Last()
+		if last.Query != "ALTER TABLE `test_users` ADD SYSTEM VERSIONING" {
+			t.Errorf("unexpected statement: %s", last.Query)
+		}
+
+		capture.Reset()
+		cfg.SetRows([]string{"table_type"}, []driver.Value{"SYSTEM VERSIONED"})
+		if err := db.EnsureHistory(context.Background()); err != nil {
+			t.Fatalf("EnsureHistory failed: %v", err)
+		}
+		if len(capture.Queries) != 1 {
+			t.Errorf("expected only the versioning check, got %d queries", len(capture.Queries))
+		}
+	})
+
+	t.Run("mssql native", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRows([]string{"temporal", "schema"}, []driver.Value{int64(0), "dbo"})
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlmssql.New(), WithHistory(HistoryNative))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if err := db.EnsureHistory(context.Background()); err != nil {
+			t.Fatalf("EnsureHistory failed: %v", err)
+		}
+		last, _ := capture.Last()
+		want := `ALTER TABLE [test_users] SET (SYSTEM_VERSIONING = ON (HISTORY_TABLE = [dbo].[test_users_history]))`
+		if last.Query != want {
+			t.Errorf("expected %s, got: %s", want, last.Query)
+		}
+	})
+}
@@ -47,6 +47,20 @@ func callAfterSave[T any](ctx context.Context, value *T) error {
 	return nil
 }
 
+type rawLoadKey struct{}
+
+// withRawLoad returns ctx marked so reads under it return values as stored,
+// skipping redaction and AfterLoad. Unit steps snapshot values this way, so
+// an undo writes back exactly what it replaced.
+func withRawLoad(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rawLoadKey{}, true)
+}
+
+// isRawLoad reports whether ctx was marked by withRawLoad.
+func isRawLoad(ctx context.Context) bool {
+	return ctx.Value(rawLoadKey{}) != nil
+}
+
 // callAfterLoad calls AfterLoad on value if T implements the interface.
 func callAfterLoad[T any](ctx context.Context, value *T) error {
 	if h, ok := any(value).(AfterLoad); ok {
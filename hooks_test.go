@@ -392,6 +392,46 @@ func TestStoreHooks_GetBatchAfterLoadError(t *testing.T) {
 	}
 }
 
+func TestStoreHooks_ScanCallsAfterLoad(t *testing.T) {
+	provider := newMockStoreProvider()
+	store := NewStore[hookedRecord](provider)
+	ctx := context.Background()
+
+	data, _ := JSONCodec{}.Encode(&hookedRecord{ID: 1, Name: "a"})
+	provider.data["a"] = data
+
+	it, err := store.Scan(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !it.Next() {
+		t.Fatalf("expected a record, err: %v", it.Err())
+	}
+	if !it.Value().afterLoadCalled {
+		t.Error("AfterLoad not called")
+	}
+}
+
+func TestStoreHooks_ScanAfterLoadError(t *testing.T) {
+	provider := newMockStoreProvider()
+	store := NewStore[failingAfterLoad](provider)
+	ctx := context.Background()
+
+	data, _ := JSONCodec{}.Encode(&failingAfterLoad{ID: 1, Name: "a"})
+	provider.data["a"] = data
+
+	it, err := store.Scan(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if it.Next() {
+		t.Error("expected Next to return false")
+	}
+	if !errors.Is(it.Err(), errHook) {
+		t.Fatalf("expected hook error, got: %v", it.Err())
+	}
+}
+
 func TestStoreHooks_SetBatchCallsBeforeAndAfterSave(t *testing.T) {
 	provider := newMockStoreProvider()
 	store := NewStore[hookedRecord](provider)
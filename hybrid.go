@@ -0,0 +1,46 @@
+package grub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zoobzio/vecna"
+)
+
+// HybridSearch returns the k best matches for a combined keyword and vector
+// query, highest score first. alpha in [0, 1] weights the blend: 1 ranks by
+// vector similarity alone and 0 by keyword (BM25) relevance alone. Scores
+// are the provider's fused relevance, not distances. vector may be nil for
+// keyword-only search, or to let a provider with a vectorizer embed text.
+// Returns ErrUnsupported if the provider does not implement
+// VectorHybridSearcher, and ErrDimensionMismatch if vector does not match the
+// index dimension.
+func (i *Index[T]) HybridSearch(ctx context.Context, text string, vector []float32, k int, alpha float32, filter *vecna.Filter) ([]*Vector[T], error) {
+	searcher, ok := i.searcher.(VectorHybridSearcher)
+	if !ok {
+		return nil, i.wrapErr("hybrid_search", "", ErrUnsupported)
+	}
+	if alpha < 0 || alpha > 1 {
+		return nil, i.wrapErr("hybrid_search", "", fmt.Errorf("grub: alpha %v outside [0, 1]", alpha))
+	}
+	if vector != nil {
+		if err := i.checkDimension(vector); err != nil {
+			return nil, i.wrapErr("hybrid_search", "", err)
+		}
+		normalized, err := i.normalize(vector)
+		if err != nil {
+			return nil, i.wrapErr("hybrid_search", "", err)
+		}
+		vector = normalized
+	}
+	start := i.emitSearchStarted(ctx, "hybrid_search", k)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	results, err := searcher.HybridSearch(callCtx, text, vector, k, alpha, filter)
+	if err != nil {
+		i.emitSearchFailed(ctx, "hybrid_search", k, start, err)
+		return nil, i.wrapErr("hybrid_search", "", classifyDimension(err))
+	}
+	i.emitSearchCompleted(ctx, "hybrid_search", k, start, len(results))
+	return i.decodeVectors(ctx, results)
+}
@@ -0,0 +1,64 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/vecna"
+)
+
+// hybridVectorProvider is a mockVectorProvider that also implements
+// VectorHybridSearcher, recording the arguments of the last call.
+type hybridVectorProvider struct {
+	*mockVectorProvider
+	text  string
+	alpha float32
+}
+
+func (p *hybridVectorProvider) HybridSearch(ctx context.Context, text string, vector []float32, k int, alpha float32, filter *vecna.Filter) ([]VectorResult, error) {
+	p.text, p.alpha = text, alpha
+	return p.Query(ctx, vector, k, filter)
+}
+
+func TestIndex_HybridSearch(t *testing.T) {
+	ctx := context.Background()
+	provider := &hybridVectorProvider{mockVectorProvider: newMockVectorProvider()}
+	id := uuid.New()
+	provider.vectors[id] = vectorEntry{vector: []float32{1, 0}, metadata: []byte(`{"category": "docs"}`)}
+	index := NewIndex[testMetadata](provider, WithDimension(2))
+
+	t.Run("delegates", func(t *testing.T) {
+		results, err := index.HybridSearch(ctx, "storage", []float32{1, 0}, 5, 0.5, nil)
+		if err != nil {
+			t.Fatalf("HybridSearch failed: %v", err)
+		}
+		if len(results) != 1 || results[0].ID != id || results[0].Metadata.Category != "docs" {
+			t.Errorf("expected decoded result %s, got %v", id, results)
+		}
+		if provider.text != "storage" || provider.alpha != 0.5 {
+			t.Errorf("expected text and alpha passed through, got %q %v", provider.text, provider.alpha)
+		}
+	})
+
+	t.Run("alpha out of range", func(t *testing.T) {
+		if _, err := index.HybridSearch(ctx, "storage", nil, 5, 1.5, nil); err == nil {
+			t.Error("expected error for alpha 1.5")
+		}
+	})
+
+	t.Run("dimension mismatch", func(t *testing.T) {
+		_, err := index.HybridSearch(ctx, "storage", []float32{1, 0, 0}, 5, 0.5, nil)
+		if !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := NewIndex[testMetadata](newMockVectorProvider()).HybridSearch(ctx, "storage", nil, 5, 0.5, nil)
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,360 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/soy"
+)
+
+// The astql condition types live in an internal package, so markIn names
+// them by the zero values of the exported constructors' results.
+var (
+	conditionZero         = zeroElem((*astql.ASTQL)(nil).Conditions())
+	conditionGroupZero, _ = (*astql.ASTQL)(nil).TryAnd()
+)
+
+// inMarkerPattern matches the placeholder inRenderer substitutes for an IN
+// or NOT IN param before handing the AST to SQLite.
+var inMarkerPattern = regexp.MustCompile(`= :grub_in_([0-9]+)\b`)
+
+// inListPattern matches an IN or NOT IN predicate as the dialects render
+// it: "status" IN (:statuses) on MariaDB, SQL Server, and SQLite, and
+// "status" = ANY(:statuses) or "status" != ALL(:statuses) on PostgreSQL.
+var inListPattern = regexp.MustCompile(`([^()\s]+) (= ANY|!= ALL|NOT IN|IN) ?\(:([A-Za-z_][A-Za-z0-9_]*)\)`)
+
+// inCondition records an IN or NOT IN condition replaced by a marker.
+type inCondition struct {
+	param string
+	not   bool
+}
+
+// inRenderer renders IN and NOT IN conditions on SQLite, whose renderer
+// rejects them, as ("field" IN (:param)), so a slice param can be expanded
+// at execution time. Other dialects render them natively. It also renders
+// JSON conditions (see WhereJSON).
+type inRenderer struct {
+	astql.Renderer
+}
+
+// Render renders ast, rewriting IN and NOT IN conditions in the WHERE clause
+// on SQLite and JSON conditions.
+func (r inRenderer) Render(ast *astql.AST) (*astql.QueryResult, error) {
+	dialect := rendererDialect(r.Renderer)
+	var columns []jsonColumn
+	if ast != nil && ast.WhereClause != nil {
+		where, err := markJSON(ast.WhereClause, &columns)
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) > 0 {
+			rewritten := *ast
+			rewritten.WhereClause = where
+			ast = &rewritten
+		}
+	}
+	render := r.Renderer.Render
+	if dialect == "sqlite" {
+		render = r.renderIn
+	}
+	result, err := render(ast)
+	if err != nil || len(columns) == 0 {
+		return result, err
+	}
+	sql, err := renderJSON(result.SQL, columns, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &astql.QueryResult{SQL: sql, RequiredParams: result.RequiredParams}, nil
+}
+
+// renderIn renders ast for SQLite, rewriting IN and NOT IN conditions in
+// the WHERE clause.
+func (r inRenderer) renderIn(ast *astql.AST) (*astql.QueryResult, error) {
+	if ast == nil || ast.WhereClause == nil {
+		return r.Renderer.Render(ast)
+	}
+	var marked []inCondition
+	where := markIn(ast.WhereClause, &marked)
+	if len(marked) == 0 {
+		return r.Renderer.Render(ast)
+	}
+
+	rewritten := *ast
+	rewritten.WhereClause = where
+	result, err := r.Renderer.Render(&rewritten)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := inMarkerPattern.ReplaceAllStringFunc(result.SQL, func(match string) string {
+		i, _ := strconv.Atoi(inMarkerPattern.FindStringSubmatch(match)[1])
+		op := "IN"
+		if marked[i].not {
+			op = "NOT IN"
+		}
+		return op + " (:" + marked[i].param + ")"
+	})
+	required := make([]string, 0, len(result.RequiredParams))
+	seen := make(map[string]bool, len(result.RequiredParams))
+	for _, name := range result.RequiredParams {
+		if i, ok := strings.CutPrefix(name, "grub_in_"); ok {
+			if n, err := strconv.Atoi(i); err == nil && n < len(marked) {
+				name = marked[n].param
+			}
+		}
+		if !seen[name] {
+			seen[name] = true
+			required = append(required, name)
+		}
+	}
+	return &astql.QueryResult{SQL: sql, RequiredParams: required}, nil
+}
+
+// markIn returns item with each IN and NOT IN condition replaced by a
+// parenthesised = :grub_in_N condition, appending what it replaced to marked.
+// Items that hold no such condition are returned unchanged.
+func markIn(item astql.ConditionItem, marked *[]inCondition) astql.ConditionItem {
+	if cond, ok := itemAs(item, conditionZero); ok {
+		if cond.Operator != astql.IN && cond.Operator != astql.NotIn {
+			return item
+		}
+		*marked = append(*marked, inCondition{param: cond.Value.Name, not: cond.Operator == astql.NotIn})
+		cond.Value.Name = "grub_in_" + strconv.Itoa(len(*marked)-1)
+		cond.Operator = astql.EQ
+		return (*astql.ASTQL)(nil).And(cond)
+	}
+	if group, ok := itemAs(item, conditionGroupZero); ok {
+		before := len(*marked)
+		conditions := make([]astql.ConditionItem, len(group.Conditions))
+		for i, child := range group.Conditions {
+			conditions[i] = markIn(child, marked)
+		}
+		if len(*marked) == before {
+			return item
+		}
+		group.Conditions = conditions
+		return group
+	}
+	return item
+}
+
+// itemAs asserts item to the type of like.
+func itemAs[T any](item astql.ConditionItem, like T) (T, bool) {
+	v, ok := item.(T)
+	if !ok {
+		return like, false
+	}
+	return v, true
+}
+
+// zeroElem returns the zero value of the element type of s.
+func zeroElem[T any](_ []T) T {
+	var zero T
+	return zero
+}
+
+// expandIn rewrites each IN and NOT IN predicate in query whose param is
+// bound to a plain slice into one placeholder per element (:statuses__0,
+// ...), returning params extended with the element values. PostgreSQL's
+// = ANY and != ALL forms become IN and NOT IN lists. An empty slice renders
+// as a predicate that is always false for IN and always true for NOT IN.
+// Params bound to anything else, such as pq.Array, are left to the driver.
+// ok is false when no predicate was expanded. Builder queries, ExecUpdate,
+// and Atomic bind inside soy and are not expanded.
+func expandIn(query string, params map[string]any) (expanded string, args map[string]any, ok bool) {
+	args = params
+	expanded = inListPattern.ReplaceAllStringFunc(query, func(match string) string {
+		parts := inListPattern.FindStringSubmatch(match)
+		field, op, name := parts[1], parts[2], parts[3]
+		values, isList := inValues(params[name])
+		if !isList {
+			return match
+		}
+		if !ok {
+			ok = true
+			args = make(map[string]any, len(params)+len(values))
+			for k, v := range params {
+				args[k] = v
+			}
+		}
+		not := op == "NOT IN" || op == "!= ALL"
+		if len(values) == 0 {
+			if not {
+				return "1 = 1"
+			}
+			return "1 = 0"
+		}
+		placeholders := make([]string, len(values))
+		for i, value := range values {
+			key := name + "__" + strconv.Itoa(i)
+			args[key] = value
+			placeholders[i] = ":" + key
+		}
+		op = "IN"
+		if not {
+			op = "NOT IN"
+		}
+		return fmt.Sprintf("%s %s (%s)", field, op, strings.Join(placeholders, ", "))
+	})
+	return expanded, args, ok
+}
+
+// inValues returns the elements of v when v is a plain slice or array.
+// []byte binds as a single value and a driver.Valuer such as pq.Array
+// binds however it encodes itself, so neither is expanded.
+func inValues(v any) ([]any, bool) {
+	if _, ok := v.(driver.Valuer); ok {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, true
+}
+
+// hasList reports whether any param is bound to a slice that expandIn would
+// expand, so statements without one skip rendering.
+func hasList(params map[string]any) bool {
+	for _, v := range params {
+		if _, ok := inValues(v); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandStatement renders a statement and expands its slice params when
+// params holds any. ok is false when the executor can run the statement
+// as is.
+func expandStatement(params map[string]any, render func() (string, error)) (string, map[string]any, bool, error) {
+	if !hasList(params) {
+		return "", nil, false, nil
+	}
+	query, err := render()
+	if err != nil {
+		return "", nil, false, err
+	}
+	query, args, ok := expandIn(query, params)
+	return query, args, ok, nil
+}
+
+// runQuery executes a query statement on tx, or on c when tx is nil,
+// expanding slice params bound to IN conditions.
+func (d *Database[T]) runQuery(ctx context.Context, c conn[T], tx *sqlx.Tx, stmt edamame.QueryStatement, params map[string]any) ([]*T, error) {
+	query, args, ok, err := expandStatement(params, func() (string, error) { return d.executor.RenderQuery(stmt) })
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if tx != nil {
+			return d.executor.ExecQueryTx(ctx, tx, stmt, params)
+		}
+		return c.executor.ExecQuery(ctx, stmt, params)
+	}
+	return scanRecords(ctx, c.execer(tx), query, args, d.afterLoad)
+}
+
+// runSelect is runQuery for select statements, which match exactly one row.
+func (d *Database[T]) runSelect(ctx context.Context, c conn[T], tx *sqlx.Tx, stmt edamame.SelectStatement, params map[string]any) (*T, error) {
+	query, args, ok, err := expandStatement(params, func() (string, error) { return d.executor.RenderSelect(stmt) })
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if tx != nil {
+			return d.executor.ExecSelectTx(ctx, tx, stmt, params)
+		}
+		return c.executor.ExecSelect(ctx, stmt, params)
+	}
+	records, err := scanRecords(ctx, c.execer(tx), query, args, d.afterLoad)
+	if err != nil {
+		return nil, err
+	}
+	switch len(records) {
+	case 0:
+		return nil, soy.ErrNotFound
+	case 1:
+		return records[0], nil
+	default:
+		return nil, errors.New("select returned more than one row")
+	}
+}
+
+// runAggregate is runQuery for aggregate statements.
+func (d *Database[T]) runAggregate(ctx context.Context, c conn[T], tx *sqlx.Tx, stmt edamame.AggregateStatement, params map[string]any) (float64, error) {
+	query, args, ok, err := expandStatement(params, func() (string, error) { return d.executor.RenderAggregate(stmt) })
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		if tx != nil {
+			return d.executor.ExecAggregateTx(ctx, tx, stmt, params)
+		}
+		return c.executor.ExecAggregate(ctx, stmt, params)
+	}
+	rows, err := sqlx.NamedQueryContext(ctx, c.execer(tx), query, args)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, errors.New("aggregate query returned no rows")
+	}
+	var value *float64
+	if err := rows.Scan(&value); err != nil {
+		return 0, err
+	}
+	if value == nil {
+		return 0, nil
+	}
+	return *value, nil
+}
+
+// scanRecords runs query and scans every row into T, calling afterLoad
+// as the executor's scan hook would.
+func scanRecords[T any](ctx context.Context, execer sqlx.ExtContext, query string, params map[string]any, afterLoad func(context.Context, *T) error) ([]*T, error) {
+	rows, err := sqlx.NamedQueryContext(ctx, execer, query, params)
+	if err != nil {
+		return nil, err
+	}
+	return scanRows(ctx, rows, afterLoad)
+}
+
+// scanRows scans every row into T, calling afterLoad on each, and closes
+// rows.
+func scanRows[T any](ctx context.Context, rows *sqlx.Rows, afterLoad func(context.Context, *T) error) ([]*T, error) {
+	defer func() { _ = rows.Close() }()
+	var records []*T
+	for rows.Next() {
+		var record T
+		if err := rows.StructScan(&record); err != nil {
+			return nil, err
+		}
+		if err := afterLoad(ctx, &record); err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,235 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	astqlmariadb "github.com/zoobzio/astql/mariadb"
+	astqlmssql "github.com/zoobzio/astql/mssql"
+	astqlpostgres "github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// fakePQArray mimics lib/pq's array types: a slice that implements
+// driver.Valuer and binds as one array literal.
+type fakePQArray []any
+
+func (a fakePQArray) Value() (driver.Value, error) {
+	elems := make([]string, len(a))
+	for i, v := range a {
+		elems[i] = fmt.Sprint(v)
+	}
+	return "{" + strings.Join(elems, ",") + "}", nil
+}
+
+var byNames = edamame.NewQueryStatement("by-names", "", edamame.QuerySpec{
+	Where: []edamame.ConditionSpec{
+		{Field: "age", Operator: ">=", Param: "min_age"},
+		{Logic: "OR", Group: []edamame.ConditionSpec{
+			{Field: "name", Operator: "IN", Param: "names"},
+			{Field: "email", Operator: "NOT IN", Param: "emails"},
+		}},
+	},
+})
+
+func TestInRenderer(t *testing.T) {
+	renderers := map[string]struct {
+		renderer  astql.Renderer
+		in, notIn string
+	}{
+		"sqlite":   {testDBRenderer, `("name" IN (:names))`, `("email" NOT IN (:emails))`},
+		"postgres": {astqlpostgres.New(), `"name" = ANY(:names)`, `"email" != ALL(:emails)`},
+		"mariadb":  {astqlmariadb.New(), "`name` IN (:names)", "`email` NOT IN (:emails)"},
+		"mssql":    {astqlmssql.New(), `[name] IN (:names)`, `[email] NOT IN (:emails)`},
+	}
+	for name, tt := range renderers {
+		t.Run(name, func(t *testing.T) {
+			mockDB, _ := mockdb.New()
+			db, err := NewDatabase[TestDBUser](mockDB, "test_users", tt.renderer)
+			if err != nil {
+				t.Fatalf("NewDatabase failed: %v", err)
+			}
+			query, err := db.Executor().RenderQuery(byNames)
+			if err != nil {
+				t.Fatalf("RenderQuery failed: %v", err)
+			}
+			for _, want := range []string{tt.in, tt.notIn, ">= :min_age"} {
+				if !strings.Contains(query, want) {
+					t.Errorf("expected %q in query, got: %s", want, query)
+				}
+			}
+			if strings.Contains(query, "grub_in_") {
+				t.Errorf("marker left in query: %s", query)
+			}
+		})
+	}
+}
+
+func TestExpandIn(t *testing.T) {
+	const query = `SELECT * FROM "t" WHERE ("status" IN (:statuses)) AND ("id" NOT IN (:ids))`
+
+	tests := []struct {
+		name   string
+		params map[string]any
+		want   string
+		args   map[string]any
+	}{
+		{
+			"slices",
+			map[string]any{"statuses": []string{"a", "b"}, "ids": []int{7}},
+			`SELECT * FROM "t" WHERE ("status" IN (:statuses__0, :statuses__1)) AND ("id" NOT IN (:ids__0))`,
+			map[string]any{"statuses__0": "a", "statuses__1": "b", "ids__0": 7},
+		},
+		{
+			"empty",
+			map[string]any{"statuses": []string{}, "ids": []int(nil)},
+			`SELECT * FROM "t" WHERE (1 = 0) AND (1 = 1)`,
+			nil,
+		},
+		{
+			"scalars and bytes bind as one value",
+			map[string]any{"statuses": "a", "ids": []byte("x")},
+			query,
+			nil,
+		},
+		{
+			"valuers bind as one value",
+			map[string]any{"statuses": fakePQArray{"a"}, "ids": fakePQArray{int64(7)}},
+			query,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, _ := expandIn(query, tt.params)
+			if got != tt.want {
+				t.Errorf("got:  %s\nwant: %s", got, tt.want)
+			}
+			for k, v := range tt.args {
+				if !reflect.DeepEqual(args[k], v) {
+					t.Errorf("arg %q: got %v, want %v", k, args[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandIn_Postgres(t *testing.T) {
+	const query = `SELECT * FROM "t" WHERE ("status" = ANY(:statuses) OR "id" != ALL(:ids))`
+
+	got, args, ok := expandIn(query, map[string]any{"statuses": []string{"a", "b"}, "ids": []int{}})
+	want := `SELECT * FROM "t" WHERE ("status" IN (:statuses__0, :statuses__1) OR 1 = 1)`
+	if !ok || got != want {
+		t.Errorf("got:  %s\nwant: %s", got, want)
+	}
+	if args["statuses__1"] != "b" {
+		t.Errorf("expected element args, got %v", args)
+	}
+
+	if got, _, ok := expandIn(query, map[string]any{"statuses": fakePQArray{"a"}, "ids": fakePQArray{int64(1)}}); ok || got != query {
+		t.Errorf("expected array params to bind unchanged, got: %s", got)
+	}
+}
+
+func TestDatabase_ExecIn(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	t.Run("query", func(t *testing.T) {
+		capture.Reset()
+		_, err := db.ExecQuery(ctx, byNames, map[string]any{
+			"min_age": 18, "names": []string{"a", "b", "c"}, "emails": []string{},
+		})
+		if err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		query, ok := capture.Last()
+		if !ok {
+			t.Fatal("no query captured")
+		}
+		if !strings.Contains(query.Query, `("name" IN (?, ?, ?))`) || !strings.Contains(query.Query, "(1 = 1)") {
+			t.Errorf("expected expanded IN, got: %s", query.Query)
+		}
+		if len(query.Args) != 4 {
+			t.Errorf("expected 4 args, got %v", query.Args)
+		}
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		capture.Reset()
+		count := edamame.NewAggregateStatement("count-names", "", edamame.AggCount, edamame.AggregateSpec{
+			Where: []edamame.ConditionSpec{{Field: "name", Operator: "IN", Param: "names"}},
+		})
+		// The mock driver returns no rows, so only the SQL is checked.
+		_, _ = db.ExecAggregate(ctx, count, map[string]any{"names": []string{"a", "b"}})
+		query, ok := capture.Last()
+		if !ok || !strings.Contains(query.Query, `("name" IN (?, ?))`) {
+			t.Errorf("expected expanded IN, got: %+v", query)
+		}
+	})
+
+	t.Run("multi aggregate", func(t *testing.T) {
+		capture.Reset()
+		_, _ = db.ExecMultiAggregate(ctx, []AggregateSpec{{Alias: "n", Func: edamame.AggCount}},
+			[]edamame.ConditionSpec{{Field: "name", Operator: "IN", Param: "names"}},
+			map[string]any{"names": []string{}})
+		query, ok := capture.Last()
+		if !ok || !strings.Contains(query.Query, "(1 = 0)") {
+			t.Errorf("expected false predicate, got: %+v", query)
+		}
+	})
+
+	t.Run("scalar binds unchanged", func(t *testing.T) {
+		capture.Reset()
+		if _, err := db.ExecQuery(ctx, byNames, map[string]any{"min_age": 18, "names": "a", "emails": "b"}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		query, _ := capture.Last()
+		if !strings.Contains(query.Query, `("name" IN (?))`) {
+			t.Errorf("expected single placeholder, got: %s", query.Query)
+		}
+	})
+	t.Run("postgres arrays bind unchanged", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		pg, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlpostgres.New())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		names := fakePQArray{"a", "b"}
+		if _, err := pg.ExecQuery(ctx, byNames, map[string]any{"min_age": 18, "names": names, "emails": fakePQArray{}}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		query, ok := capture.Last()
+		if !ok || !strings.Contains(query.Query, `"name" = ANY(?)`) || !strings.Contains(query.Query, `"email" != ALL(?)`) {
+			t.Fatalf("expected native array predicates, got: %+v", query)
+		}
+		if len(query.Args) != 3 {
+			t.Errorf("expected one arg per param, got %v", query.Args)
+		}
+	})
+
+	t.Run("postgres slices expand", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		pg, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlpostgres.New())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if _, err := pg.ExecQuery(ctx, byNames, map[string]any{"min_age": 18, "names": []string{"a", "b"}, "emails": []string{}}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		query, _ := capture.Last()
+		if !strings.Contains(query.Query, `"name" IN (`) || strings.Contains(query.Query, "ANY") {
+			t.Errorf("expected an IN list, got: %s", query.Query)
+		}
+	})
+}
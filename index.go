@@ -2,55 +2,142 @@ package grub
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/zoobzio/atom"
+	"github.com/zoobzio/capitan"
 	atomic "github.com/zoobzio/grub/internal/atomic"
+	"github.com/zoobzio/grub/internal/shared"
 	"github.com/zoobzio/vecna"
 )
 
 // Index provides type-safe vector storage operations with metadata of type T.
 // Wraps a VectorProvider, handling serialization of T to/from map[string]any.
 type Index[T any] struct {
-	provider   VectorProvider
-	codec      Codec
-	atomic     *atomic.Index[T]
-	atomicOnce sync.Once
+	provider     VectorProvider
+	searcher     VectorProvider // provider, projected by WithSearchFields and ordered by WithIDOrder
+	lister       VectorProvider // provider, ordered by WithIDOrder
+	codec        Codec
+	name         string
+	dimension    int
+	timeout      time.Duration
+	onDecodeErr  DecodeErrorHandler
+	stamps       *timestamps
+	redact       *redaction
+	project      *projection
+	scanCap      int
+	maxScan      int
+	unitVectors  bool
+	rejectZero   bool
+	normScores   bool
+	idOrder      bool
+	schema       MetadataSchema
+	strictSchema bool
+	noMetaErr    bool
+	atomic       *atomic.Index[T]
+	atomicOnce   *sync.Once
+	readOnly     bool
 }
 
 // NewIndex creates an Index for metadata type T backed by the given provider.
 // Uses JSON codec by default.
-func NewIndex[T any](provider VectorProvider) *Index[T] {
-	return &Index[T]{
-		provider: provider,
-		codec:    JSONCodec{},
-	}
+func NewIndex[T any](provider VectorProvider, opts ...Option) *Index[T] {
+	return NewIndexWithCodec[T](provider, JSONCodec{}, opts...)
 }
 
 // NewIndexWithCodec creates an Index for metadata type T with a custom codec.
-func NewIndexWithCodec[T any](provider VectorProvider, codec Codec) *Index[T] {
+func NewIndexWithCodec[T any](provider VectorProvider, codec Codec, opts ...Option) *Index[T] {
+	o := applyOptions(opts)
+	project := newProjection[T](o, fieldTag(codec))
 	return &Index[T]{
-		provider: provider,
-		codec:    codec,
+		provider:     provider,
+		searcher:     orderedProvider(o, project.provider(provider)),
+		lister:       orderedProvider(o, provider),
+		codec:        codec,
+		name:         o.name,
+		dimension:    o.dimension,
+		timeout:      o.timeout,
+		onDecodeErr:  o.onDecodeErr,
+		stamps:       newTimestamps[T](o),
+		redact:       newRedaction[T](o),
+		project:      project,
+		scanCap:      clientFilterCap(o),
+		maxScan:      o.maxScan,
+		unitVectors:  o.normalize,
+		rejectZero:   o.rejectZero,
+		normScores:   o.normalizeScores,
+		idOrder:      o.idOrder,
+		schema:       deriveSchema[T](fieldTag(codec)),
+		strictSchema: o.metadataSchema,
+		noMetaErr:    o.noMetadataErr,
+		atomicOnce:   new(sync.Once),
+	}
+}
+
+// NewIndexChecked creates an Index like NewIndex, but validates that T can be
+// atomized before returning. Malformed tags or unsupported field types are
+// reported here rather than at the first operation. The validated atomic view
+// is cached so Atomic() will not panic.
+func NewIndexChecked[T any](provider VectorProvider, opts ...Option) (*Index[T], error) {
+	return NewIndexCheckedWithCodec[T](provider, JSONCodec{}, opts...)
+}
+
+// NewIndexCheckedWithCodec creates an Index like NewIndexWithCodec, validating
+// T at construction time as NewIndexChecked does.
+func NewIndexCheckedWithCodec[T any](provider VectorProvider, codec Codec, opts ...Option) (*Index[T], error) {
+	atomizer, err := useAtomizer[T]()
+	if err != nil {
+		return nil, err
 	}
+	idx := NewIndexWithCodec[T](provider, codec, opts...)
+	idx.atomicOnce.Do(func() {
+		idx.atomic = atomic.NewIndex[T](provider, codec, atomizer.Spec()).Redact(idx.redact.fields())
+	})
+	return idx, nil
 }
 
 // Upsert stores or updates a vector with associated metadata.
 // If the ID exists, the vector and metadata are replaced.
+// Returns ErrDimensionMismatch if the vector does not match the index dimension.
 func (i *Index[T]) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata *T) error {
+	if i.readOnly {
+		return i.wrapErr("upsert", id.String(), ErrReadOnly)
+	}
+	if err := i.checkDimension(vector); err != nil {
+		return i.wrapErr("upsert", id.String(), err)
+	}
+	vector, err := i.normalize(vector)
+	if err != nil {
+		return i.wrapErr("upsert", id.String(), err)
+	}
 	if metadata != nil {
+		i.stamps.stamp(metadata)
 		if err := callBeforeSave(ctx, metadata); err != nil {
 			return err
 		}
+		if err := i.redact.check(metadata); err != nil {
+			return i.wrapErr("upsert", id.String(), err)
+		}
 	}
 	m, err := i.encodeMetadata(metadata)
 	if err != nil {
 		return err
 	}
-	if err := i.provider.Upsert(ctx, id, vector, m); err != nil {
-		return err
+	if err := i.checkSchema(m); err != nil {
+		return i.wrapErr("upsert", id.String(), err)
+	}
+	start := time.Now()
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	if err := i.provider.Upsert(callCtx, id, vector, m); err != nil {
+		i.emitWriteFailed(ctx, IndexUpsertFailed, "upsert", 1, start, err)
+		return i.wrapErr("upsert", id.String(), classifyDimension(err))
 	}
+	i.emitWriteCompleted(ctx, IndexUpsertCompleted, "upsert", 1, start)
 	if metadata != nil {
 		return callAfterSave(ctx, metadata)
 	}
@@ -58,25 +145,55 @@ func (i *Index[T]) Upsert(ctx context.Context, id uuid.UUID, vector []float32, m
 }
 
 // UpsertBatch stores or updates multiple vectors.
+// Returns ErrDimensionMismatch if any vector does not match the index
+// dimension; nothing is written in that case. A batch larger than the
+// provider's BatchLimiter limit is written in sequential chunks, each with
+// its own timeout; if one fails, the chunks before it stay written and the
+// error is a *BatchChunkError naming it.
 func (i *Index[T]) UpsertBatch(ctx context.Context, vectors []Vector[T]) error {
+	if i.readOnly {
+		return i.wrapErr("upsert_batch", "", ErrReadOnly)
+	}
 	records := make([]VectorRecord, len(vectors))
 	for idx := range vectors {
+		if err := i.checkDimension(vectors[idx].Vector); err != nil {
+			return i.wrapErr("upsert_batch", vectors[idx].ID.String(), err)
+		}
+		vector, err := i.normalize(vectors[idx].Vector)
+		if err != nil {
+			return i.wrapErr("upsert_batch", vectors[idx].ID.String(), err)
+		}
+		records[idx].Vector = vector
+	}
+	for idx := range vectors {
+		i.stamps.stamp(&vectors[idx].Metadata)
 		if err := callBeforeSave(ctx, &vectors[idx].Metadata); err != nil {
 			return err
 		}
+		if err := i.redact.check(&vectors[idx].Metadata); err != nil {
+			return i.wrapErr("upsert_batch", vectors[idx].ID.String(), err)
+		}
 		m, err := i.encodeMetadata(&vectors[idx].Metadata)
 		if err != nil {
 			return err
 		}
-		records[idx] = VectorRecord{
-			ID:       vectors[idx].ID,
-			Vector:   vectors[idx].Vector,
-			Metadata: m,
+		if err := i.checkSchema(m); err != nil {
+			return i.wrapErr("upsert_batch", vectors[idx].ID.String(), err)
 		}
+		records[idx].ID = vectors[idx].ID
+		records[idx].Metadata = m
 	}
-	if err := i.provider.UpsertBatch(ctx, records); err != nil {
-		return err
+	start := time.Now()
+	err := inChunks(len(records), batchLimit(i.provider), func(from, to int) error {
+		callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+		defer cancel()
+		return classifyDimension(i.provider.UpsertBatch(callCtx, records[from:to]))
+	})
+	if err != nil {
+		i.emitWriteFailed(ctx, IndexUpsertFailed, "upsert_batch", len(records), start, err)
+		return i.wrapErr("upsert_batch", "", err)
 	}
+	i.emitWriteCompleted(ctx, IndexUpsertCompleted, "upsert_batch", len(records), start)
 	for idx := range vectors {
 		if err := callAfterSave(ctx, &vectors[idx].Metadata); err != nil {
 			return err
@@ -86,18 +203,33 @@ func (i *Index[T]) UpsertBatch(ctx context.Context, vectors []Vector[T]) error {
 }
 
 // Get retrieves a vector by ID.
-// Returns ErrNotFound if the ID does not exist.
+// Returns ErrNotFound if the ID does not exist. A vector stored without
+// metadata has zero-valued Metadata; with WithNoMetadataError, Get also
+// returns ErrNoMetadata.
 func (i *Index[T]) Get(ctx context.Context, id uuid.UUID) (*Vector[T], error) {
-	vector, info, err := i.provider.Get(ctx, id)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	vector, info, err := i.provider.Get(callCtx, id)
 	if err != nil {
-		return nil, err
+		return nil, i.wrapErr("get", id.String(), err)
+	}
+	if info.Metadata == nil && i.noMetaErr {
+		return &Vector[T]{
+			ID:     info.ID,
+			Vector: vector,
+			Score:  info.Score,
+		}, i.wrapErr("get", id.String(), ErrNoMetadata)
 	}
 	var metadata T
 	if err := i.decodeMetadata(info.Metadata, &metadata); err != nil {
 		return nil, err
 	}
-	if err := callAfterLoad(ctx, &metadata); err != nil {
-		return nil, err
+	i.checkDrift(ctx, info.Metadata)
+	if !isRawLoad(ctx) {
+		i.redact.apply(&metadata)
+		if err := callAfterLoad(ctx, &metadata); err != nil {
+			return nil, err
+		}
 	}
 	return &Vector[T]{
 		ID:       info.ID,
@@ -110,121 +242,314 @@ func (i *Index[T]) Get(ctx context.Context, id uuid.UUID) (*Vector[T], error) {
 // Delete removes a vector by ID.
 // Returns ErrNotFound if the ID does not exist.
 func (i *Index[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	if i.readOnly {
+		return i.wrapErr("delete", id.String(), ErrReadOnly)
+	}
 	if err := callBeforeDelete[T](ctx); err != nil {
 		return err
 	}
-	if err := i.provider.Delete(ctx, id); err != nil {
-		return err
+	start := time.Now()
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	if err := i.provider.Delete(callCtx, id); err != nil {
+		i.emitWriteFailed(ctx, IndexDeleteFailed, "delete", 1, start, err)
+		return i.wrapErr("delete", id.String(), err)
 	}
+	i.emitWriteCompleted(ctx, IndexDeleteCompleted, "delete", 1, start)
 	return callAfterDelete[T](ctx)
 }
 
 // DeleteBatch removes multiple vectors by ID.
-// Non-existent IDs are silently ignored.
+// Non-existent IDs are silently ignored. Batches are split to the
+// provider's BatchLimiter limit as in UpsertBatch.
 func (i *Index[T]) DeleteBatch(ctx context.Context, ids []uuid.UUID) error {
+	if i.readOnly {
+		return i.wrapErr("delete_batch", "", ErrReadOnly)
+	}
 	if err := callBeforeDelete[T](ctx); err != nil {
 		return err
 	}
-	if err := i.provider.DeleteBatch(ctx, ids); err != nil {
-		return err
+	start := time.Now()
+	err := inChunks(len(ids), batchLimit(i.provider), func(from, to int) error {
+		callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+		defer cancel()
+		return i.provider.DeleteBatch(callCtx, ids[from:to])
+	})
+	if err != nil {
+		i.emitWriteFailed(ctx, IndexDeleteFailed, "delete_batch", len(ids), start, err)
+		return i.wrapErr("delete_batch", "", err)
 	}
+	i.emitWriteCompleted(ctx, IndexDeleteCompleted, "delete_batch", len(ids), start)
 	return callAfterDelete[T](ctx)
 }
 
 // Search performs similarity search and returns the k nearest neighbors.
-// filter is optional metadata filtering (nil means no filter).
+// filter is optional metadata filtering (nil means no filter): every field
+// it encodes must be equal. For other operators use Query, converting a
+// typed filter with EqualityFilter to combine the two.
 func (i *Index[T]) Search(ctx context.Context, vector []float32, k int, filter *T) ([]*Vector[T], error) {
-	filterMap, err := i.encodeFilter(filter)
+	vector, err := i.normalize(vector)
 	if err != nil {
-		return nil, err
+		return nil, i.wrapErr("search", "", err)
 	}
-	results, err := i.provider.Search(ctx, vector, k, filterMap)
+	filterMap, err := i.encodeFilter(filter)
 	if err != nil {
 		return nil, err
 	}
-	vectors := make([]*Vector[T], len(results))
-	for idx, r := range results {
-		var metadata T
-		if err := i.decodeMetadata(r.Metadata, &metadata); err != nil {
-			return nil, err
-		}
-		if err := callAfterLoad(ctx, &metadata); err != nil {
-			return nil, err
-		}
-		vectors[idx] = &Vector[T]{
-			ID:       r.ID,
-			Vector:   r.Vector,
-			Score:    r.Score,
-			Metadata: metadata,
-		}
+	start := i.emitSearchStarted(ctx, "search", k)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	results, err := i.searcher.Search(callCtx, vector, k, filterMap)
+	if err != nil {
+		i.emitSearchFailed(ctx, "search", k, start, err)
+		return nil, i.wrapErr("search", "", classifyDimension(err))
 	}
-	return vectors, nil
+	i.emitSearchCompleted(ctx, "search", k, start, len(results))
+	return i.decodeResults(ctx, results)
 }
 
 // Query performs similarity search with vecna filter support.
 // Returns ErrInvalidQuery if the filter contains validation errors.
-// Returns ErrOperatorNotSupported if the provider doesn't support an operator.
+// Returns ErrOperatorNotSupported if the provider doesn't support an operator,
+// unless WithClientSideFilter is set.
 func (i *Index[T]) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]*Vector[T], error) {
-	results, err := i.provider.Query(ctx, vector, k, filter)
+	vector, err := i.normalize(vector)
+	if err != nil {
+		return nil, i.wrapErr("query", "", err)
+	}
+	start := i.emitSearchStarted(ctx, "query", k)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	results, err := i.searcher.Query(callCtx, vector, k, filter)
+	if filter != nil && i.scanCap > 0 && errors.Is(err, ErrOperatorNotSupported) {
+		results, err = i.queryClientSide(callCtx, vector, k, filter)
+	}
+	if err != nil {
+		i.emitSearchFailed(ctx, "query", k, start, err)
+		return nil, i.wrapErr("query", "", classifyDimension(err))
+	}
+	i.emitSearchCompleted(ctx, "query", k, start, len(results))
+	return i.decodeResults(ctx, results)
+}
+
+// SearchPage performs similarity search and returns up to k results after
+// skipping the offset nearest neighbors, in score order. Walking offsets 0,
+// k, 2k, ... pages through the neighbors of vector. An offset past the end
+// of the collection returns an empty slice.
+func (i *Index[T]) SearchPage(ctx context.Context, vector []float32, k, offset int, filter *T) ([]*Vector[T], error) {
+	if offset < 0 {
+		return nil, i.wrapErr("search_page", "", fmt.Errorf("grub: negative offset %d", offset))
+	}
+	vector, err := i.normalize(vector)
+	if err != nil {
+		return nil, i.wrapErr("search_page", "", err)
+	}
+	filterMap, err := i.encodeFilter(filter)
 	if err != nil {
 		return nil, err
 	}
-	vectors := make([]*Vector[T], len(results))
-	for idx, r := range results {
-		var metadata T
-		if err := i.decodeMetadata(r.Metadata, &metadata); err != nil {
-			return nil, err
-		}
-		if err := callAfterLoad(ctx, &metadata); err != nil {
-			return nil, err
-		}
-		vectors[idx] = &Vector[T]{
-			ID:       r.ID,
-			Vector:   r.Vector,
-			Score:    r.Score,
-			Metadata: metadata,
-		}
+	start := i.emitSearchStarted(ctx, "search_page", k)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	var results []VectorResult
+	if pager, ok := i.searcher.(VectorPager); ok {
+		results, err = pager.SearchPage(callCtx, vector, k, offset, filterMap)
+	} else {
+		results, err = i.searcher.Search(callCtx, vector, offset+k, filterMap)
+		results = skipResults(results, offset)
 	}
-	return vectors, nil
+	if err != nil {
+		i.emitSearchFailed(ctx, "search_page", k, start, err)
+		return nil, i.wrapErr("search_page", "", classifyDimension(err))
+	}
+	i.emitSearchCompleted(ctx, "search_page", k, start, len(results))
+	return i.decodeResults(ctx, results)
+}
+
+// QueryPage is SearchPage with vecna filter support.
+// Returns ErrInvalidQuery if the filter contains validation errors.
+// Returns ErrOperatorNotSupported if the provider doesn't support an operator.
+func (i *Index[T]) QueryPage(ctx context.Context, vector []float32, k, offset int, filter *vecna.Filter) ([]*Vector[T], error) {
+	if offset < 0 {
+		return nil, i.wrapErr("query_page", "", fmt.Errorf("grub: negative offset %d", offset))
+	}
+	vector, err := i.normalize(vector)
+	if err != nil {
+		return nil, i.wrapErr("query_page", "", err)
+	}
+	start := i.emitSearchStarted(ctx, "query_page", k)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	var results []VectorResult
+	if pager, ok := i.searcher.(VectorPager); ok {
+		results, err = pager.QueryPage(callCtx, vector, k, offset, filter)
+	} else {
+		results, err = i.searcher.Query(callCtx, vector, offset+k, filter)
+		results = skipResults(results, offset)
+	}
+	if err != nil {
+		i.emitSearchFailed(ctx, "query_page", k, start, err)
+		return nil, i.wrapErr("query_page", "", classifyDimension(err))
+	}
+	i.emitSearchCompleted(ctx, "query_page", k, start, len(results))
+	return i.decodeResults(ctx, results)
+}
+
+// skipResults drops the first offset results.
+func skipResults(results []VectorResult, offset int) []VectorResult {
+	if offset >= len(results) {
+		return nil
+	}
+	return results[offset:]
 }
 
 // Filter returns vectors matching the metadata filter without similarity search.
-// Result ordering is provider-dependent and not guaranteed.
-// Limit of 0 returns all matching vectors.
-// Returns ErrFilterNotSupported if the provider cannot perform metadata-only filtering.
+// Result ordering is provider-dependent unless WithIDOrder is set.
+// Limit of 0 returns all matching vectors, up to WithMaxScan.
+// Returns ErrFilterNotSupported if the provider cannot perform metadata-only
+// filtering, unless WithClientSideFilter is set.
 func (i *Index[T]) Filter(ctx context.Context, filter *vecna.Filter, limit int) ([]*Vector[T], error) {
-	results, err := i.provider.Filter(ctx, filter, limit)
-	if err != nil {
-		return nil, err
+	start := i.emitSearchStarted(ctx, "filter", limit)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	fetch := limit
+	if i.maxScan > 0 && (limit <= 0 || limit > i.maxScan) {
+		fetch = i.maxScan + 1
 	}
-	vectors := make([]*Vector[T], len(results))
-	for idx, r := range results {
-		var metadata T
-		if err := i.decodeMetadata(r.Metadata, &metadata); err != nil {
-			return nil, err
-		}
-		if err := callAfterLoad(ctx, &metadata); err != nil {
-			return nil, err
-		}
-		vectors[idx] = &Vector[T]{
-			ID:       r.ID,
-			Vector:   r.Vector,
-			Score:    r.Score,
-			Metadata: metadata,
-		}
+	results, err := i.searcher.Filter(callCtx, filter, fetch)
+	if i.scanCap > 0 && (errors.Is(err, ErrFilterNotSupported) || errors.Is(err, ErrOperatorNotSupported)) {
+		results, err = i.filterClientSide(callCtx, filter, fetch)
 	}
-	return vectors, nil
+	if err == nil && fetch != limit && len(results) > i.maxScan {
+		err = fmt.Errorf("%w: filter matched more than %d vectors", ErrScanLimitExceeded, i.maxScan)
+	}
+	if err != nil {
+		i.emitSearchFailed(ctx, "filter", limit, start, err)
+		return nil, i.wrapErr("filter", "", err)
+	}
+	i.emitSearchCompleted(ctx, "filter", limit, start, len(results))
+	i.sortResults(results)
+	return i.decodeVectors(ctx, results)
 }
 
-// List returns vector IDs.
+// List returns vector IDs, in ascending order when WithIDOrder is set.
 // Limit of 0 means no limit.
 func (i *Index[T]) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
-	return i.provider.List(ctx, limit)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	ids, err := i.lister.List(callCtx, limit)
+	if err != nil {
+		return nil, i.wrapErr("list", "", err)
+	}
+	i.sortIDs(ids)
+	return ids, nil
 }
 
 // Exists checks whether a vector ID exists.
 func (i *Index[T]) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
-	return i.provider.Exists(ctx, id)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	exists, err := i.provider.Exists(callCtx, id)
+	if err != nil {
+		return false, i.wrapErr("exists", id.String(), err)
+	}
+	return exists, nil
+}
+
+// ExistsBatch checks whether each ID exists.
+// Uses the provider's VectorBatchExister implementation when available;
+// otherwise probes with up to existsBatchConcurrency concurrent Exists calls.
+// The result has an entry for every input ID.
+func (i *Index[T]) ExistsBatch(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	var found map[uuid.UUID]bool
+	var err error
+	if batcher, ok := i.provider.(VectorBatchExister); ok {
+		found, err = batcher.ExistsBatch(callCtx, ids)
+	} else {
+		found, err = i.probeExists(callCtx, ids)
+	}
+	if err != nil {
+		return nil, i.wrapErr("exists_batch", "", err)
+	}
+	result := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		result[id] = found[id]
+	}
+	return result, nil
+}
+
+// existsBatchConcurrency bounds the concurrent Exists calls made by ExistsBatch
+// when the provider has no native batch check.
+const existsBatchConcurrency = 16
+
+// probeExists calls provider.Exists for each ID concurrently.
+// The first error cancels outstanding probes and is returned.
+func (i *Index[T]) probeExists(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		result   = make(map[uuid.UUID]bool, len(ids))
+		sem      = make(chan struct{}, existsBatchConcurrency)
+	)
+	for _, id := range ids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(id uuid.UUID) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			exists, err := i.provider.Exists(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			result[id] = exists
+		}(id)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Provider returns the VectorProvider passed to NewIndex, so callers can
+// type-assert to the concrete provider for features grub does not cover.
+func (i *Index[T]) Provider() VectorProvider {
+	return i.provider
+}
+
+// ReadOnly returns a view of this index whose writes (Upsert, UpsertBatch,
+// Delete, DeleteBatch, Reindex) fail with ErrReadOnly, naming the
+// operation, before hooks run or the provider is reached. Reads behave as
+// on i and share its provider, codec, and options. The view's Atomic
+// rejects writes the same way.
+func (i *Index[T]) ReadOnly() *Index[T] {
+	view := *i
+	view.readOnly = true
+	view.atomic = nil
+	view.atomicOnce = new(sync.Once)
+	return &view
 }
 
 // Atomic returns an atom-based view of this index.
@@ -237,11 +562,103 @@ func (i *Index[T]) Atomic() *atomic.Index[T] {
 		if err != nil {
 			panic("grub: invalid type for atomization: " + err.Error())
 		}
-		i.atomic = atomic.NewIndex[T](i.provider, i.codec, atomizer.Spec())
+		i.atomic = atomic.NewIndex[T](i.provider, i.codec, atomizer.Spec()).Redact(i.redact.fields())
+		if i.readOnly {
+			i.atomic = i.atomic.ReadOnly()
+		}
 	})
 	return i.atomic
 }
 
+// Spec returns the atom spec of T: its type name and each field's name,
+// Go type, kind, and struct tags, as carried by Atomic's atoms. Panics if
+// T is not atomizable, like Atomic. Schema describes the JSON metadata
+// instead.
+func (i *Index[T]) Spec() atom.Spec {
+	return i.Atomic().Spec()
+}
+
+// useAtomizer resolves the atomizer for T, converting registration panics
+// (e.g. non-struct types) into errors.
+func useAtomizer[T any]() (atomizer *atom.Atomizer[T], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("grub: invalid type for atomization: %v", r)
+		}
+	}()
+	atomizer, err = atom.Use[T]()
+	if err != nil {
+		return nil, fmt.Errorf("grub: invalid type for atomization: %w", err)
+	}
+	return atomizer, nil
+}
+
+// checkDimension validates vector against the configured dimension, if any.
+func (i *Index[T]) checkDimension(vector []float32) error {
+	if i.dimension > 0 && len(vector) != i.dimension {
+		return fmt.Errorf("%w: expected %d, got %d", ErrDimensionMismatch, i.dimension, len(vector))
+	}
+	return nil
+}
+
+// wrapErr annotates err with the operation context for this index.
+func (i *Index[T]) wrapErr(op, key string, err error) error {
+	return shared.WrapError(KindIndex, op, i.name, key, err)
+}
+
+// emitSearchStarted emits IndexSearchStarted and returns the start time.
+func (i *Index[T]) emitSearchStarted(ctx context.Context, op string, k int) time.Time {
+	capitan.Debug(ctx, IndexSearchStarted,
+		CollectionKey.Field(i.name),
+		OperationKey.Field(op),
+		KKey.Field(k),
+	)
+	return time.Now()
+}
+
+// emitSearchCompleted emits IndexSearchCompleted.
+func (i *Index[T]) emitSearchCompleted(ctx context.Context, op string, k int, start time.Time, count int) {
+	capitan.Info(ctx, IndexSearchCompleted,
+		CollectionKey.Field(i.name),
+		OperationKey.Field(op),
+		KKey.Field(k),
+		DurationMsKey.Field(time.Since(start).Milliseconds()),
+		ResultCountKey.Field(count),
+	)
+}
+
+// emitSearchFailed emits IndexSearchFailed.
+func (i *Index[T]) emitSearchFailed(ctx context.Context, op string, k int, start time.Time, err error) {
+	capitan.Error(ctx, IndexSearchFailed,
+		CollectionKey.Field(i.name),
+		OperationKey.Field(op),
+		KKey.Field(k),
+		DurationMsKey.Field(time.Since(start).Milliseconds()),
+		ErrorKey.Field(err.Error()),
+	)
+}
+
+// emitWriteCompleted emits an upsert or delete completion signal.
+func (i *Index[T]) emitWriteCompleted(ctx context.Context, signal capitan.Signal, op string, size int, start time.Time) {
+	capitan.Info(ctx, signal,
+		CollectionKey.Field(i.name),
+		OperationKey.Field(op),
+		DurationMsKey.Field(time.Since(start).Milliseconds()),
+		BatchSizeKey.Field(size),
+	)
+}
+
+// emitWriteFailed emits an upsert or delete failure signal.
+func (i *Index[T]) emitWriteFailed(ctx context.Context, signal capitan.Signal, op string, size int, start time.Time, err error) {
+	capitan.Error(ctx, signal,
+		CollectionKey.Field(i.name),
+		OperationKey.Field(op),
+		DurationMsKey.Field(time.Since(start).Milliseconds()),
+		BatchSizeKey.Field(size),
+		ErrorKey.Field(err.Error()),
+	)
+}
+
 // encodeMetadata converts typed metadata to bytes via codec.
 func (i *Index[T]) encodeMetadata(metadata *T) ([]byte, error) {
 	if metadata == nil {
@@ -258,6 +675,45 @@ func (i *Index[T]) decodeMetadata(data []byte, metadata *T) error {
 	return i.codec.Decode(data, metadata)
 }
 
+// decodeResults applies WithNormalizedScores to similarity search results
+// and decodes them.
+func (i *Index[T]) decodeResults(ctx context.Context, results []VectorResult) ([]*Vector[T], error) {
+	if err := i.normalizeScores(results); err != nil {
+		return nil, err
+	}
+	return i.decodeVectors(ctx, results)
+}
+
+// decodeVectors converts provider results to typed vectors, applying
+// WithSearchFields and running AfterLoad on each. Undecodable metadata is
+// passed to the DecodeErrorHandler, which may drop the result instead of
+// failing the call.
+func (i *Index[T]) decodeVectors(ctx context.Context, results []VectorResult) ([]*Vector[T], error) {
+	vectors := make([]*Vector[T], 0, len(results))
+	for _, r := range results {
+		var metadata T
+		if err := i.decodeMetadata(r.Metadata, &metadata); err != nil {
+			if err := handleDecodeErr(i.onDecodeErr, r.ID.String(), r.Metadata, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		i.checkDrift(ctx, r.Metadata)
+		i.project.apply(&metadata)
+		i.redact.apply(&metadata)
+		if err := callAfterLoad(ctx, &metadata); err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, &Vector[T]{
+			ID:       r.ID,
+			Vector:   r.Vector,
+			Score:    r.Score,
+			Metadata: metadata,
+		})
+	}
+	return vectors, nil
+}
+
 // encodeFilter converts typed filter to map[string]any via codec for search operations.
 func (i *Index[T]) encodeFilter(filter *T) (map[string]any, error) {
 	if filter == nil {
@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/zoobzio/capitan"
 	"github.com/zoobzio/vecna"
 )
 
@@ -265,6 +268,53 @@ func TestNewIndexWithCodec(t *testing.T) {
 	}
 }
 
+type unsupportedMetadata struct {
+	Callback func() `json:"-"`
+}
+
+func TestNewIndexChecked(t *testing.T) {
+	t.Run("valid type", func(t *testing.T) {
+		provider := newMockVectorProvider()
+		index, err := NewIndexChecked[testMetadata](provider)
+		if err != nil {
+			t.Fatalf("NewIndexChecked failed: %v", err)
+		}
+		if index.codec == nil {
+			t.Error("codec should default to JSONCodec")
+		}
+		if index.atomic == nil {
+			t.Error("atomic view should be built eagerly")
+		}
+		if index.Atomic() != index.atomic {
+			t.Error("Atomic should return the eagerly built instance")
+		}
+	})
+
+	t.Run("unsupported field type", func(t *testing.T) {
+		_, err := NewIndexChecked[unsupportedMetadata](newMockVectorProvider())
+		if err == nil {
+			t.Fatal("expected error for unsupported field type")
+		}
+	})
+
+	t.Run("non-struct type", func(t *testing.T) {
+		_, err := NewIndexChecked[string](newMockVectorProvider())
+		if err == nil {
+			t.Fatal("expected error for non-struct type")
+		}
+	})
+
+	t.Run("with codec", func(t *testing.T) {
+		index, err := NewIndexCheckedWithCodec[testMetadata](newMockVectorProvider(), GobCodec{})
+		if err != nil {
+			t.Fatalf("NewIndexCheckedWithCodec failed: %v", err)
+		}
+		if _, ok := index.codec.(GobCodec); !ok {
+			t.Errorf("expected GobCodec, got %T", index.codec)
+		}
+	})
+}
+
 func TestIndex_Upsert(t *testing.T) {
 	provider := newMockVectorProvider()
 	index := NewIndex[testMetadata](provider)
@@ -553,6 +603,99 @@ func TestIndex_Query(t *testing.T) {
 	})
 }
 
+// pagingVectorProvider is a mockVectorProvider that also implements VectorPager.
+type pagingVectorProvider struct {
+	*mockVectorProvider
+	pageCalls int
+}
+
+func (p *pagingVectorProvider) SearchPage(ctx context.Context, vector []float32, k, offset int, filter map[string]any) ([]VectorResult, error) {
+	p.pageCalls++
+	results, err := p.Search(ctx, vector, offset+k, filter)
+	return skipResults(results, offset), err
+}
+
+func (p *pagingVectorProvider) QueryPage(ctx context.Context, vector []float32, k, offset int, filter *vecna.Filter) ([]VectorResult, error) {
+	p.pageCalls++
+	results, err := p.Query(ctx, vector, offset+k, filter)
+	return skipResults(results, offset), err
+}
+
+func TestIndex_SearchPage(t *testing.T) {
+	ctx := context.Background()
+	query := []float32{0, 0}
+
+	// ten vectors at increasing distance from query
+	seed := func(m *mockVectorProvider) []uuid.UUID {
+		ids := make([]uuid.UUID, 10)
+		for n := range ids {
+			ids[n] = uuid.New()
+			m.vectors[ids[n]] = vectorEntry{
+				vector:   []float32{float32(n), 0},
+				metadata: []byte(fmt.Sprintf(`{"category": "c", "score": %d}`, n)),
+			}
+		}
+		return ids
+	}
+
+	fallback := newMockVectorProvider()
+	paging := &pagingVectorProvider{mockVectorProvider: newMockVectorProvider()}
+	for _, tc := range []struct {
+		name     string
+		mock     *mockVectorProvider
+		provider VectorProvider
+	}{
+		{"fallback", fallback, fallback},
+		{"pager", paging.mockVectorProvider, paging},
+	} {
+		ids := seed(tc.mock)
+		index := NewIndex[testMetadata](tc.provider)
+
+		t.Run(tc.name, func(t *testing.T) {
+			search := func(k, offset int) ([]*Vector[testMetadata], error) {
+				return index.SearchPage(ctx, query, k, offset, nil)
+			}
+			querier := func(k, offset int) ([]*Vector[testMetadata], error) {
+				return index.QueryPage(ctx, query, k, offset, nil)
+			}
+			for op, page := range map[string]func(k, offset int) ([]*Vector[testMetadata], error){
+				"SearchPage": search,
+				"QueryPage":  querier,
+			} {
+				var got []uuid.UUID
+				for offset := 0; offset < 12; offset += 4 {
+					results, err := page(4, offset)
+					if err != nil {
+						t.Fatalf("%s(offset=%d) failed: %v", op, offset, err)
+					}
+					for _, r := range results {
+						got = append(got, r.ID)
+					}
+				}
+				if len(got) != len(ids) {
+					t.Fatalf("%s: expected %d results across pages, got %d", op, len(ids), len(got))
+				}
+				for n := range ids {
+					if got[n] != ids[n] {
+						t.Errorf("%s: result %d out of order", op, n)
+					}
+				}
+
+				results, err := page(4, 20)
+				if err != nil || len(results) != 0 {
+					t.Errorf("%s: expected empty page past the end, got %d results, %v", op, len(results), err)
+				}
+				if _, err := page(4, -1); err == nil {
+					t.Errorf("%s: expected an error for a negative offset", op)
+				}
+			}
+		})
+	}
+	if paging.pageCalls == 0 {
+		t.Error("expected the VectorPager implementation to be used")
+	}
+}
+
 func TestIndex_List(t *testing.T) {
 	provider := newMockVectorProvider()
 	index := NewIndex[testMetadata](provider)
@@ -675,6 +818,66 @@ func TestIndex_FilterProviderError(t *testing.T) {
 	}
 }
 
+// limitRecordingProvider records the limit each Filter call asks for.
+type limitRecordingProvider struct {
+	*mockVectorProvider
+	limits []int
+}
+
+func (p *limitRecordingProvider) Filter(ctx context.Context, filter *vecna.Filter, limit int) ([]VectorResult, error) {
+	p.limits = append(p.limits, limit)
+	return p.mockVectorProvider.Filter(ctx, filter, limit)
+}
+
+func TestIndex_MaxScan(t *testing.T) {
+	ctx := context.Background()
+	provider := &limitRecordingProvider{mockVectorProvider: newMockVectorProvider()}
+	for n := 0; n < 5; n++ {
+		if err := NewIndex[testMetadata](provider).Upsert(ctx, uuid.New(), []float32{1}, &testMetadata{Score: n}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		maxScan   int
+		limit     int
+		wantFetch int
+		wantLen   int
+		wantErr   bool
+	}{
+		{"unbounded past the cap", 4, 0, 5, 0, true},
+		{"limit above the cap", 4, 10, 5, 0, true},
+		{"unbounded within the cap", 5, 0, 6, 5, false},
+		{"limit within the cap", 4, 3, 3, 3, false},
+		{"no cap", 0, 0, 0, 5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider.limits = nil
+			index := NewIndex[testMetadata](provider, WithMaxScan(tt.maxScan))
+			results, err := index.Filter(ctx, nil, tt.limit)
+			if tt.wantErr != errors.Is(err, ErrScanLimitExceeded) {
+				t.Fatalf("expected ErrScanLimitExceeded %v, got %v", tt.wantErr, err)
+			}
+			if len(results) != tt.wantLen {
+				t.Errorf("expected %d results, got %d", tt.wantLen, len(results))
+			}
+			if len(provider.limits) != 1 || provider.limits[0] != tt.wantFetch {
+				t.Errorf("expected the provider to be asked for %d, got %v", tt.wantFetch, provider.limits)
+			}
+		})
+	}
+
+	t.Run("client-side fallback", func(t *testing.T) {
+		index := NewIndex[testMetadata](&filterlessVectorProvider{provider.mockVectorProvider},
+			WithClientSideFilter(), WithMaxScan(4))
+		if _, err := index.Filter(ctx, nil, 0); !errors.Is(err, ErrScanLimitExceeded) {
+			t.Errorf("expected ErrScanLimitExceeded, got %v", err)
+		}
+	})
+}
+
 func TestIndex_NilMetadata(t *testing.T) {
 	provider := newMockVectorProvider()
 	index := NewIndex[testMetadata](provider)
@@ -708,6 +911,32 @@ func TestIndex_NilMetadata(t *testing.T) {
 		}
 	})
 
+	t.Run("Get with WithNoMetadataError", func(t *testing.T) {
+		strict := NewIndex[testMetadata](provider, WithNoMetadataError())
+		id := uuid.New()
+		provider.vectors[id] = vectorEntry{
+			vector:   []float32{1.0},
+			metadata: nil,
+		}
+		result, err := strict.Get(ctx, id)
+		if !errors.Is(err, ErrNoMetadata) {
+			t.Fatalf("expected ErrNoMetadata, got %v", err)
+		}
+		if result == nil || result.ID != id || len(result.Vector) != 1 {
+			t.Errorf("expected the vector alongside the error, got %+v", result)
+		}
+
+		// all-zero metadata is still metadata
+		zero := uuid.New()
+		provider.vectors[zero] = vectorEntry{
+			vector:   []float32{1.0},
+			metadata: []byte(`{"category":"","score":0}`),
+		}
+		if _, err := strict.Get(ctx, zero); err != nil {
+			t.Errorf("expected no error for zero-valued metadata, got %v", err)
+		}
+	})
+
 	t.Run("Search with nil metadata in results", func(t *testing.T) {
 		id := uuid.New()
 		provider.vectors[id] = vectorEntry{
@@ -809,6 +1038,61 @@ func TestIndex_DecodeErrors(t *testing.T) {
 	})
 }
 
+func TestIndex_DecodeErrorHandler(t *testing.T) {
+	provider := newMockVectorProvider()
+	ctx := context.Background()
+
+	goodID, badID := uuid.New(), uuid.New()
+	provider.vectors[goodID] = vectorEntry{
+		vector:   []float32{1.0, 2.0},
+		metadata: []byte(`{"category":"good"}`),
+	}
+	provider.vectors[badID] = vectorEntry{
+		vector:   []float32{1.0, 2.1},
+		metadata: []byte(`{invalid json`),
+	}
+
+	t.Run("nil skips bad records", func(t *testing.T) {
+		var skipped []string
+		index := NewIndex[testMetadata](provider, WithDecodeErrorHandler(func(key string, raw []byte, _ error) error {
+			skipped = append(skipped, key)
+			if string(raw) != `{invalid json` {
+				t.Errorf("unexpected raw bytes %q", raw)
+			}
+			return nil
+		}))
+
+		search := func() ([]*Vector[testMetadata], error) { return index.Search(ctx, []float32{1.0, 2.0}, 10, nil) }
+		query := func() ([]*Vector[testMetadata], error) { return index.Query(ctx, []float32{1.0, 2.0}, 10, nil) }
+		filter := func() ([]*Vector[testMetadata], error) { return index.Filter(ctx, nil, 0) }
+		for name, call := range map[string]func() ([]*Vector[testMetadata], error){
+			"Search": search, "Query": query, "Filter": filter,
+		} {
+			skipped = nil
+			results, err := call()
+			if err != nil {
+				t.Fatalf("%s failed: %v", name, err)
+			}
+			if len(results) != 1 || results[0].ID != goodID {
+				t.Errorf("%s: expected only the good record, got %d results", name, len(results))
+			}
+			if len(skipped) != 1 || skipped[0] != badID.String() {
+				t.Errorf("%s: expected bad record to be reported, got %v", name, skipped)
+			}
+		}
+	})
+
+	t.Run("returned error aborts", func(t *testing.T) {
+		abort := errors.New("abort")
+		index := NewIndex[testMetadata](provider, WithDecodeErrorHandler(func(string, []byte, error) error {
+			return abort
+		}))
+		if _, err := index.Search(ctx, []float32{1.0, 2.0}, 10, nil); !errors.Is(err, abort) {
+			t.Errorf("expected handler error, got %v", err)
+		}
+	})
+}
+
 // errorCodec is a codec that can be configured to fail.
 type errorCodec struct {
 	encodeErr error
@@ -875,6 +1159,29 @@ func TestIndex_EncodeFilterDecodeError(t *testing.T) {
 	}
 }
 
+func TestIndex_Provider(t *testing.T) {
+	provider := newMockVectorProvider()
+	index := NewIndex[testMetadata](provider, WithSearchFields("category"))
+
+	if p, ok := index.Provider().(*mockVectorProvider); !ok || p != provider {
+		t.Errorf("expected the provider passed to NewIndex, got %T", index.Provider())
+	}
+}
+
+func TestIndex_Spec(t *testing.T) {
+	index := NewIndex[testMetadata](newMockVectorProvider())
+	spec := index.Spec()
+	if spec.TypeName != "testMetadata" || len(spec.Fields) != 2 {
+		t.Fatalf("unexpected spec %+v", spec)
+	}
+	if f := spec.Fields[0]; f.Name != "Category" || f.Type != "string" || f.Tags["atom"] != "category" {
+		t.Errorf("unexpected field %+v", f)
+	}
+	if got := index.ReadOnly().Spec(); got.TypeName != spec.TypeName {
+		t.Errorf("expected the view to share the spec, got %+v", got)
+	}
+}
+
 func TestIndex_Atomic(t *testing.T) {
 	provider := newMockVectorProvider()
 	index := NewIndex[testMetadata](provider)
@@ -906,3 +1213,300 @@ func TestIndex_Atomic(t *testing.T) {
 		t.Errorf("unexpected Category: %q", a.Metadata.Strings["Category"])
 	}
 }
+
+func TestIndex_ErrorContext(t *testing.T) {
+	provider := newMockVectorProvider()
+	index := NewIndex[testMetadata](provider)
+	ctx := context.Background()
+
+	id := uuid.New()
+	_, err := index.Get(ctx, id)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	var gerr *Error
+	if !errors.As(err, &gerr) {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if gerr.Kind != KindIndex || gerr.Op != "get" || gerr.Key != id.String() {
+		t.Errorf("unexpected context: kind=%q op=%q key=%q", gerr.Kind, gerr.Op, gerr.Key)
+	}
+}
+
+func TestIndex_Signals(t *testing.T) {
+	provider := newMockVectorProvider()
+	index := NewIndex[testMetadata](provider, WithName("documents"))
+	ctx := context.Background()
+
+	events := make(chan *capitan.Event, 16)
+	capture := func(_ context.Context, e *capitan.Event) { events <- e }
+	for _, sig := range []capitan.Signal{IndexSearchCompleted, IndexSearchFailed, IndexUpsertCompleted, IndexDeleteCompleted} {
+		listener := capitan.Hook(sig, capture)
+		defer listener.Close()
+	}
+
+	next := func(t *testing.T, want capitan.Signal) *capitan.Event {
+		t.Helper()
+		select {
+		case e := <-events:
+			if e.Signal() != want {
+				t.Fatalf("expected signal %q, got %q", want.Name(), e.Signal().Name())
+			}
+			return e
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %q", want.Name())
+		}
+		return nil
+	}
+
+	t.Run("upsert", func(t *testing.T) {
+		if err := index.Upsert(ctx, uuid.New(), []float32{1, 0}, &testMetadata{Category: "a"}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		e := next(t, IndexUpsertCompleted)
+		if got, _ := CollectionKey.From(e); got != "documents" {
+			t.Errorf("collection: expected 'documents', got %q", got)
+		}
+		if got, _ := OperationKey.From(e); got != "upsert" {
+			t.Errorf("operation: expected 'upsert', got %q", got)
+		}
+		if got, _ := BatchSizeKey.From(e); got != 1 {
+			t.Errorf("batch_size: expected 1, got %d", got)
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		if _, err := index.Search(ctx, []float32{1, 0}, 5, nil); err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		e := next(t, IndexSearchCompleted)
+		if got, _ := KKey.From(e); got != 5 {
+			t.Errorf("k: expected 5, got %d", got)
+		}
+		if got, _ := ResultCountKey.From(e); got != 1 {
+			t.Errorf("result_count: expected 1, got %d", got)
+		}
+	})
+
+	t.Run("search failure", func(t *testing.T) {
+		provider.searchErr = errors.New("backend unavailable")
+		defer func() { provider.searchErr = nil }()
+
+		if _, err := index.Search(ctx, []float32{1, 0}, 3, nil); err == nil {
+			t.Fatal("expected search error")
+		}
+		e := next(t, IndexSearchFailed)
+		if got, _ := ErrorKey.From(e); got != "backend unavailable" {
+			t.Errorf("error: expected 'backend unavailable', got %q", got)
+		}
+	})
+
+	t.Run("delete batch", func(t *testing.T) {
+		if err := index.DeleteBatch(ctx, []uuid.UUID{uuid.New(), uuid.New()}); err != nil {
+			t.Fatalf("DeleteBatch failed: %v", err)
+		}
+		e := next(t, IndexDeleteCompleted)
+		if got, _ := BatchSizeKey.From(e); got != 2 {
+			t.Errorf("batch_size: expected 2, got %d", got)
+		}
+	})
+}
+
+func TestIndex_WithNameInError(t *testing.T) {
+	index := NewIndex[testMetadata](newMockVectorProvider(), WithName("documents"))
+
+	_, err := index.Get(context.Background(), uuid.New())
+	var gerr *Error
+	if !errors.As(err, &gerr) {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if gerr.Table != "documents" {
+		t.Errorf("expected Table 'documents', got %q", gerr.Table)
+	}
+}
+
+func TestIndex_WithDimension(t *testing.T) {
+	provider := newMockVectorProvider()
+	index := NewIndex[testMetadata](provider, WithDimension(3))
+	ctx := context.Background()
+
+	t.Run("Upsert rejects mismatch", func(t *testing.T) {
+		id := uuid.New()
+		err := index.Upsert(ctx, id, []float32{1, 2}, &testMetadata{Category: "short"})
+		if !errors.Is(err, ErrDimensionMismatch) {
+			t.Fatalf("expected ErrDimensionMismatch, got %v", err)
+		}
+		var gerr *Error
+		if !errors.As(err, &gerr) || gerr.Op != "upsert" || gerr.Key != id.String() {
+			t.Errorf("expected *Error with op 'upsert' and key, got %v", err)
+		}
+		if _, ok := provider.vectors[id]; ok {
+			t.Error("mismatched vector should not reach the provider")
+		}
+	})
+
+	t.Run("Upsert accepts match", func(t *testing.T) {
+		if err := index.Upsert(ctx, uuid.New(), []float32{1, 2, 3}, &testMetadata{Category: "ok"}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+	})
+
+	t.Run("UpsertBatch rejects whole batch", func(t *testing.T) {
+		before := len(provider.vectors)
+		bad := uuid.New()
+		err := index.UpsertBatch(ctx, []Vector[testMetadata]{
+			{ID: uuid.New(), Vector: []float32{1, 2, 3}},
+			{ID: bad, Vector: []float32{1, 2, 3, 4}},
+		})
+		if !errors.Is(err, ErrDimensionMismatch) {
+			t.Fatalf("expected ErrDimensionMismatch, got %v", err)
+		}
+		var gerr *Error
+		if !errors.As(err, &gerr) || gerr.Key != bad.String() {
+			t.Errorf("expected offending ID in error, got %v", err)
+		}
+		if len(provider.vectors) != before {
+			t.Error("no vectors should be written when the batch is rejected")
+		}
+	})
+
+	t.Run("zero disables check", func(t *testing.T) {
+		unchecked := NewIndex[testMetadata](newMockVectorProvider())
+		if err := unchecked.Upsert(ctx, uuid.New(), []float32{1}, &testMetadata{}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+	})
+}
+
+func TestIndex_ProviderDimensionError(t *testing.T) {
+	provider := newMockVectorProvider()
+	index := NewIndex[testMetadata](provider)
+	ctx := context.Background()
+
+	driverErr := errors.New("rpc error: Wrong input: Vector dimension error: expected dim: 1536, got 768")
+	provider.upsertErr = driverErr
+
+	err := index.Upsert(ctx, uuid.New(), []float32{1}, &testMetadata{})
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("expected ErrDimensionMismatch, got %v", err)
+	}
+	if !errors.Is(err, driverErr) {
+		t.Error("expected provider error to remain reachable")
+	}
+
+	provider.searchErr = errors.New("the dim (768) of field data(embedding) is not equal to schema dim (1536)")
+	if _, err := index.Search(ctx, []float32{1}, 5, nil); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("expected ErrDimensionMismatch from Search, got %v", err)
+	}
+}
+
+// batchExistsProvider adds a native ExistsBatch to mockVectorProvider.
+type batchExistsProvider struct {
+	*mockVectorProvider
+	calls int
+}
+
+func (b *batchExistsProvider) ExistsBatch(_ context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	b.calls++
+	result := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		if _, ok := b.vectors[id]; ok {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
+
+func TestIndex_ExistsBatch(t *testing.T) {
+	ctx := context.Background()
+	present, absent := uuid.New(), uuid.New()
+
+	check := func(t *testing.T, result map[uuid.UUID]bool) {
+		t.Helper()
+		if len(result) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(result))
+		}
+		if !result[present] {
+			t.Error("expected present ID to be true")
+		}
+		if v, ok := result[absent]; !ok || v {
+			t.Error("expected absent ID to be present and false")
+		}
+	}
+
+	t.Run("concurrent fallback", func(t *testing.T) {
+		provider := newMockVectorProvider()
+		provider.vectors[present] = vectorEntry{vector: []float32{1}}
+		index := NewIndex[testMetadata](provider)
+
+		result, err := index.ExistsBatch(ctx, []uuid.UUID{present, absent})
+		if err != nil {
+			t.Fatalf("ExistsBatch failed: %v", err)
+		}
+		check(t, result)
+	})
+
+	t.Run("native batch", func(t *testing.T) {
+		provider := &batchExistsProvider{mockVectorProvider: newMockVectorProvider()}
+		provider.vectors[present] = vectorEntry{vector: []float32{1}}
+		provider.existsErr = errors.New("per-ID Exists should not be called")
+		index := NewIndex[testMetadata](provider)
+
+		result, err := index.ExistsBatch(ctx, []uuid.UUID{present, absent})
+		if err != nil {
+			t.Fatalf("ExistsBatch failed: %v", err)
+		}
+		check(t, result)
+		if provider.calls != 1 {
+			t.Errorf("expected 1 native call, got %d", provider.calls)
+		}
+	})
+
+	t.Run("many IDs", func(t *testing.T) {
+		provider := newMockVectorProvider()
+		ids := make([]uuid.UUID, 100)
+		for idx := range ids {
+			ids[idx] = uuid.New()
+			if idx%2 == 0 {
+				provider.vectors[ids[idx]] = vectorEntry{vector: []float32{1}}
+			}
+		}
+		index := NewIndex[testMetadata](provider)
+
+		result, err := index.ExistsBatch(ctx, ids)
+		if err != nil {
+			t.Fatalf("ExistsBatch failed: %v", err)
+		}
+		for idx, id := range ids {
+			if result[id] != (idx%2 == 0) {
+				t.Errorf("ID %d: expected %v, got %v", idx, idx%2 == 0, result[id])
+			}
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		provider := newMockVectorProvider()
+		provider.existsErr = errors.New("unavailable")
+		index := NewIndex[testMetadata](provider, WithName("documents"))
+
+		_, err := index.ExistsBatch(ctx, []uuid.UUID{present, absent})
+		if !errors.Is(err, provider.existsErr) {
+			t.Fatalf("expected provider error, got %v", err)
+		}
+		var gerr *Error
+		if !errors.As(err, &gerr) || gerr.Op != "exists_batch" || gerr.Table != "documents" {
+			t.Errorf("expected *Error with op 'exists_batch', got %v", err)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		index := NewIndex[testMetadata](newMockVectorProvider())
+
+		if _, err := index.ExistsBatch(canceled, []uuid.UUID{present}); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package atomic
 
 import (
 	"context"
+	"time"
 
 	"github.com/zoobzio/atom"
 	"github.com/zoobzio/grub/internal/shared"
@@ -16,6 +17,11 @@ type BucketProvider interface {
 	Exists(ctx context.Context, key string) (bool, error)
 }
 
+// bucketExpirer mirrors grub.BucketExpirer.
+type bucketExpirer interface {
+	ExpiryPrecision() time.Duration
+}
+
 // Bucket provides atom-based blob storage operations.
 // Satisfies the grub.AtomicBucket interface.
 // Only the payload T is atomized; metadata remains as-is.
@@ -23,6 +29,9 @@ type Bucket[T any] struct {
 	provider BucketProvider
 	codec    Codec
 	spec     atom.Spec
+	redact   []string
+	keys     KeyFunc
+	readOnly bool
 }
 
 // NewBucket creates an atomic Bucket wrapper.
@@ -34,6 +43,28 @@ func NewBucket[T any](provider BucketProvider, codec Codec, spec atom.Spec) *Buc
 	}
 }
 
+// Redact zeroes the named top-level fields of T in every record read through
+// this view and rejects writes that would persist any of them as zero.
+func (b *Bucket[T]) Redact(fields []string) *Bucket[T] {
+	b.redact = fields
+	return b
+}
+
+// Keys applies keys to every key passed to this view, so the view honours
+// the parent's KeyPolicy.
+func (b *Bucket[T]) Keys(keys KeyFunc) *Bucket[T] {
+	b.keys = keys
+	return b
+}
+
+// ReadOnly returns a view sharing this bucket's provider and settings whose
+// Put and Delete fail with ErrReadOnly without reaching the provider.
+func (b *Bucket[T]) ReadOnly() *Bucket[T] {
+	view := *b
+	view.readOnly = true
+	return &view
+}
+
 // Spec returns the atom spec for this bucket's payload type T.
 func (b *Bucket[T]) Spec() atom.Spec {
 	return b.spec
@@ -41,6 +72,10 @@ func (b *Bucket[T]) Spec() atom.Spec {
 
 // Get retrieves the blob at key with atomized payload.
 func (b *Bucket[T]) Get(ctx context.Context, key string) (*shared.AtomicObject, error) {
+	key, err := applyKey(b.keys, shared.KindBucket, "get", key)
+	if err != nil {
+		return nil, err
+	}
 	data, info, err := b.provider.Get(ctx, key)
 	if err != nil {
 		return nil, err
@@ -53,18 +88,33 @@ func (b *Bucket[T]) Get(ctx context.Context, key string) (*shared.AtomicObject,
 	if err != nil {
 		return nil, err
 	}
+	a := atomizer.Atomize(&payload)
+	maskAtom(a, b.redact)
 	return &shared.AtomicObject{
 		Key:         info.Key,
 		ContentType: info.ContentType,
 		Size:        info.Size,
 		ETag:        info.ETag,
 		Metadata:    info.Metadata,
-		Data:        atomizer.Atomize(&payload),
+		ExpiresAt:   info.ExpiresAt,
+		Data:        a,
 	}, nil
 }
 
 // Put stores an object with atomized payload at key.
+// Returns ErrUnsupported for an object with ExpiresAt if the provider cannot
+// expire objects.
 func (b *Bucket[T]) Put(ctx context.Context, key string, obj *shared.AtomicObject) error {
+	if b.readOnly {
+		return readOnlyErr(shared.KindBucket, "put", "", key)
+	}
+	key, err := applyKey(b.keys, shared.KindBucket, "put", key)
+	if err != nil {
+		return err
+	}
+	if _, ok := b.provider.(bucketExpirer); !ok && !obj.ExpiresAt.IsZero() {
+		return shared.ErrUnsupported
+	}
 	atomizer, err := atom.Use[T]()
 	if err != nil {
 		return err
@@ -73,6 +123,9 @@ func (b *Bucket[T]) Put(ctx context.Context, key string, obj *shared.AtomicObjec
 	if err != nil {
 		return err
 	}
+	if err := checkRedacted(payload, b.redact); err != nil {
+		return err
+	}
 	data, err := b.codec.Encode(payload)
 	if err != nil {
 		return err
@@ -82,16 +135,28 @@ func (b *Bucket[T]) Put(ctx context.Context, key string, obj *shared.AtomicObjec
 		ContentType: obj.ContentType,
 		Size:        int64(len(data)),
 		Metadata:    obj.Metadata,
+		ExpiresAt:   obj.ExpiresAt,
 	}
 	return b.provider.Put(ctx, key, data, info)
 }
 
 // Delete removes the blob at key.
 func (b *Bucket[T]) Delete(ctx context.Context, key string) error {
+	if b.readOnly {
+		return readOnlyErr(shared.KindBucket, "delete", "", key)
+	}
+	key, err := applyKey(b.keys, shared.KindBucket, "delete", key)
+	if err != nil {
+		return err
+	}
 	return b.provider.Delete(ctx, key)
 }
 
 // Exists checks whether a key exists.
 func (b *Bucket[T]) Exists(ctx context.Context, key string) (bool, error) {
+	key, err := applyKey(b.keys, shared.KindBucket, "exists", key)
+	if err != nil {
+		return false, err
+	}
 	return b.provider.Exists(ctx, key)
 }
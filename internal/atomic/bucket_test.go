@@ -383,3 +383,41 @@ func TestBucket_EncodeDecode(t *testing.T) {
 		t.Errorf("stored data mismatch: got %+v, want %+v", decoded, payload)
 	}
 }
+
+func TestBucket_NullableRoundTrip(t *testing.T) {
+	provider := newMockBucketProvider()
+	atomizer, _ := atom.Use[nullableRecord]()
+	bucket := NewBucket[nullableRecord](provider, jsonCodec{}, atomizer.Spec())
+	ctx := context.Background()
+
+	for _, populated := range []bool{true, false} {
+		record := &nullableRecord{ID: 1, Created: nullableTime}
+		if populated {
+			record = populatedNullable(1)
+		}
+
+		// Typed write, atomic read.
+		data, err := jsonCodec{}.Encode(record)
+		if err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+		provider.data["typed"] = data
+		provider.info["typed"] = &shared.ObjectInfo{Key: "typed"}
+		obj, err := bucket.Get(ctx, "typed")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		checkNullableAtom(t, obj.Data, populated)
+
+		// Atomic write, typed read.
+		err = bucket.Put(ctx, "atomic", &shared.AtomicObject{Key: "atomic", Data: atomizer.Atomize(record)})
+		if err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		var got nullableRecord
+		if err := (jsonCodec{}).Decode(provider.data["atomic"], &got); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		checkNullableRecord(t, &got, populated)
+	}
+}
@@ -15,15 +15,20 @@ import (
 // Database provides atom-based storage operations.
 // Derived from grub.Database[T] via Atomic(), satisfies grub.AtomicDatabase interface.
 type Database[T any] struct {
+	db        *sqlx.DB
 	executor  *edamame.Executor[T]
 	keyCol    string
 	tableName string
 	spec      atom.Spec
+	redact    []string
+	readOnly  bool
 }
 
-// New creates an atomic Database wrapper.
-func New[T any](executor *edamame.Executor[T], keyCol, tableName string, spec atom.Spec) *Database[T] {
+// New creates an atomic Database wrapper. db runs raw queries; it should be
+// the connection executor was built on.
+func New[T any](db *sqlx.DB, executor *edamame.Executor[T], keyCol, tableName string, spec atom.Spec) *Database[T] {
 	return &Database[T]{
+		db:        db,
 		executor:  executor,
 		keyCol:    keyCol,
 		tableName: tableName,
@@ -31,6 +36,22 @@ func New[T any](executor *edamame.Executor[T], keyCol, tableName string, spec at
 	}
 }
 
+// Redact zeroes the named top-level fields of T in every record read through
+// this view and rejects writes that would persist any of them as zero.
+func (d *Database[T]) Redact(fields []string) *Database[T] {
+	d.redact = fields
+	return d
+}
+
+// ReadOnly returns a view sharing this database's connection and settings
+// whose Set, Delete, and their Tx variants fail with ErrReadOnly without
+// running a statement.
+func (d *Database[T]) ReadOnly() *Database[T] {
+	view := *d
+	view.readOnly = true
+	return &view
+}
+
 // Table returns the table name.
 func (d *Database[T]) Table() string {
 	return d.tableName
@@ -53,11 +74,15 @@ func (d *Database[T]) Get(ctx context.Context, key string) (*atom.Atom, error) {
 		}
 		return nil, err
 	}
+	maskAtom(result, d.redact)
 	return result, nil
 }
 
 // Set stores an Atom at key (insert or update via upsert).
-func (d *Database[T]) Set(ctx context.Context, _ string, data *atom.Atom) error {
+func (d *Database[T]) Set(ctx context.Context, key string, data *atom.Atom) error {
+	if d.readOnly {
+		return readOnlyErr(shared.KindDatabase, "set", d.tableName, key)
+	}
 	atomizer, err := atom.Use[T]()
 	if err != nil {
 		return err
@@ -66,6 +91,9 @@ func (d *Database[T]) Set(ctx context.Context, _ string, data *atom.Atom) error
 	if err != nil {
 		return err
 	}
+	if err := checkRedacted(value, d.redact); err != nil {
+		return err
+	}
 
 	s := d.executor.Soy()
 	insert := s.InsertFull().OnConflict(d.keyCol).DoUpdate()
@@ -84,6 +112,9 @@ func (d *Database[T]) Set(ctx context.Context, _ string, data *atom.Atom) error
 
 // Delete removes the record at key.
 func (d *Database[T]) Delete(ctx context.Context, key string) error {
+	if d.readOnly {
+		return readOnlyErr(shared.KindDatabase, "delete", d.tableName, key)
+	}
 	affected, err := d.executor.Soy().Remove().
 		Where(d.keyCol, "=", "key").
 		Exec(ctx, map[string]any{"key": key})
@@ -110,17 +141,20 @@ func (d *Database[T]) Exists(ctx context.Context, key string) (bool, error) {
 
 // ExecQuery executes a query statement and returns atoms.
 func (d *Database[T]) ExecQuery(ctx context.Context, stmt edamame.QueryStatement, params map[string]any) ([]*atom.Atom, error) {
-	return d.executor.ExecQueryAtom(ctx, stmt, params)
+	return d.maskAll(d.executor.ExecQueryAtom(ctx, stmt, params))
 }
 
 // ExecSelect executes a select statement and returns an atom.
 func (d *Database[T]) ExecSelect(ctx context.Context, stmt edamame.SelectStatement, params map[string]any) (*atom.Atom, error) {
-	return d.executor.ExecSelectAtom(ctx, stmt, params)
+	return d.mask(d.executor.ExecSelectAtom(ctx, stmt, params))
 }
 
 // GetTx retrieves the record at key as an Atom within a transaction.
 // Returns ErrNotFound if the key does not exist.
 func (d *Database[T]) GetTx(ctx context.Context, tx *sqlx.Tx, key string) (*atom.Atom, error) {
+	if tx == nil {
+		return nil, shared.ErrNilTransaction
+	}
 	result, err := d.executor.Soy().Select().
 		Where(d.keyCol, "=", "key").
 		ExecTxAtom(ctx, tx, map[string]any{"key": key})
@@ -130,11 +164,18 @@ func (d *Database[T]) GetTx(ctx context.Context, tx *sqlx.Tx, key string) (*atom
 		}
 		return nil, err
 	}
+	maskAtom(result, d.redact)
 	return result, nil
 }
 
 // SetTx stores an Atom at key within a transaction (insert or update via upsert).
-func (d *Database[T]) SetTx(ctx context.Context, tx *sqlx.Tx, _ string, data *atom.Atom) error {
+func (d *Database[T]) SetTx(ctx context.Context, tx *sqlx.Tx, key string, data *atom.Atom) error {
+	if d.readOnly {
+		return readOnlyErr(shared.KindDatabase, "set_tx", d.tableName, key)
+	}
+	if tx == nil {
+		return shared.ErrNilTransaction
+	}
 	atomizer, err := atom.Use[T]()
 	if err != nil {
 		return err
@@ -143,6 +184,9 @@ func (d *Database[T]) SetTx(ctx context.Context, tx *sqlx.Tx, _ string, data *at
 	if err != nil {
 		return err
 	}
+	if err := checkRedacted(value, d.redact); err != nil {
+		return err
+	}
 
 	s := d.executor.Soy()
 	insert := s.InsertFull().OnConflict(d.keyCol).DoUpdate()
@@ -161,6 +205,12 @@ func (d *Database[T]) SetTx(ctx context.Context, tx *sqlx.Tx, _ string, data *at
 
 // DeleteTx removes the record at key within a transaction.
 func (d *Database[T]) DeleteTx(ctx context.Context, tx *sqlx.Tx, key string) error {
+	if d.readOnly {
+		return readOnlyErr(shared.KindDatabase, "delete_tx", d.tableName, key)
+	}
+	if tx == nil {
+		return shared.ErrNilTransaction
+	}
 	affected, err := d.executor.Soy().Remove().
 		Where(d.keyCol, "=", "key").
 		ExecTx(ctx, tx, map[string]any{"key": key})
@@ -175,6 +225,9 @@ func (d *Database[T]) DeleteTx(ctx context.Context, tx *sqlx.Tx, key string) err
 
 // ExistsTx checks whether a record exists at key within a transaction.
 func (d *Database[T]) ExistsTx(ctx context.Context, tx *sqlx.Tx, key string) (bool, error) {
+	if tx == nil {
+		return false, shared.ErrNilTransaction
+	}
 	results, err := d.executor.Soy().Query().
 		Where(d.keyCol, "=", "key").
 		Limit(1).
@@ -187,18 +240,81 @@ func (d *Database[T]) ExistsTx(ctx context.Context, tx *sqlx.Tx, key string) (bo
 
 // ExecQueryTx executes a query statement within a transaction and returns atoms.
 func (d *Database[T]) ExecQueryTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.QueryStatement, params map[string]any) ([]*atom.Atom, error) {
+	if tx == nil {
+		return nil, shared.ErrNilTransaction
+	}
 	q, err := d.executor.Query(stmt)
 	if err != nil {
 		return nil, err
 	}
-	return q.ExecTxAtom(ctx, tx, params)
+	return d.maskAll(q.ExecTxAtom(ctx, tx, params))
 }
 
 // ExecSelectTx executes a select statement within a transaction and returns an atom.
 func (d *Database[T]) ExecSelectTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.SelectStatement, params map[string]any) (*atom.Atom, error) {
+	if tx == nil {
+		return nil, shared.ErrNilTransaction
+	}
 	s, err := d.executor.Select(stmt)
 	if err != nil {
 		return nil, err
 	}
-	return s.ExecTxAtom(ctx, tx, params)
+	return d.mask(s.ExecTxAtom(ctx, tx, params))
+}
+
+// ExecRaw runs query with positional args and returns each row as an Atom.
+// Rows are scanned into T by db tag and then atomized, so the query must
+// select columns T maps.
+func (d *Database[T]) ExecRaw(ctx context.Context, query string, args ...any) ([]*atom.Atom, error) {
+	return d.execRaw(ctx, d.db, query, args)
+}
+
+// ExecRawTx runs query within a transaction and returns each row as an Atom.
+func (d *Database[T]) ExecRawTx(ctx context.Context, tx *sqlx.Tx, query string, args ...any) ([]*atom.Atom, error) {
+	if tx == nil {
+		return nil, shared.ErrNilTransaction
+	}
+	return d.execRaw(ctx, tx, query, args)
+}
+
+func (d *Database[T]) execRaw(ctx context.Context, execer sqlx.QueryerContext, query string, args []any) ([]*atom.Atom, error) {
+	atomizer, err := atom.Use[T]()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := execer.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var results []*atom.Atom
+	for rows.Next() {
+		var record T
+		if err := rows.StructScan(&record); err != nil {
+			return nil, err
+		}
+		results = append(results, atomizer.Atomize(&record))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return d.maskAll(results, nil)
+}
+
+func (d *Database[T]) mask(a *atom.Atom, err error) (*atom.Atom, error) {
+	if err != nil {
+		return nil, err
+	}
+	maskAtom(a, d.redact)
+	return a, nil
+}
+
+func (d *Database[T]) maskAll(as []*atom.Atom, err error) ([]*atom.Atom, error) {
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range as {
+		maskAtom(a, d.redact)
+	}
+	return as, nil
 }
@@ -3,8 +3,11 @@ package atomic
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	astqlsqlite "github.com/zoobzio/astql/sqlite"
 	"github.com/zoobzio/atom"
@@ -40,7 +43,7 @@ func TestNew(t *testing.T) {
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
 
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	if db == nil {
 		t.Fatal("New returned nil")
@@ -66,7 +69,7 @@ func TestDatabase_Table(t *testing.T) {
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
 
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	if table := db.Table(); table != "test_users" {
 		t.Errorf("Table() returned %q, expected 'test_users'", table)
@@ -83,7 +86,7 @@ func TestDatabase_Spec(t *testing.T) {
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
 
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	returnedSpec := db.Spec()
 	if returnedSpec.TypeName != spec.TypeName {
@@ -100,7 +103,7 @@ func TestDatabase_Get(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -133,7 +136,7 @@ func TestDatabase_Set(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -172,7 +175,7 @@ func TestDatabase_Delete(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -204,7 +207,7 @@ func TestDatabase_Exists(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -233,7 +236,7 @@ func TestDatabase_ExecQuery(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -262,7 +265,7 @@ func TestDatabase_ExecSelect(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -296,7 +299,7 @@ func TestDatabase_Get_NotFound(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -319,7 +322,7 @@ func TestDatabase_Get_QueryError(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -346,7 +349,7 @@ func TestDatabase_Delete_NotFound(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -372,7 +375,7 @@ func TestDatabase_Delete_ExecError(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -399,7 +402,7 @@ func TestDatabase_Exists_QueryError(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -428,7 +431,7 @@ func TestDatabase_GetTx(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -462,7 +465,7 @@ func TestDatabase_SetTx(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -503,7 +506,7 @@ func TestDatabase_DeleteTx(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -537,7 +540,7 @@ func TestDatabase_ExistsTx(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -571,7 +574,7 @@ func TestDatabase_ExecQueryTx(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -606,7 +609,7 @@ func TestDatabase_ExecSelectTx(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -646,7 +649,7 @@ func TestDatabase_GetTx_NotFound(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -674,7 +677,7 @@ func TestDatabase_DeleteTx_NotFound(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -705,7 +708,7 @@ func TestDatabase_GetTx_QueryError(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -737,7 +740,7 @@ func TestDatabase_DeleteTx_ExecError(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -769,7 +772,7 @@ func TestDatabase_ExistsTx_QueryError(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -801,7 +804,7 @@ func TestDatabase_ExecQueryTx_StatementError(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -833,7 +836,7 @@ func TestDatabase_ExecSelectTx_StatementError(t *testing.T) {
 
 	atomizer, _ := atom.Use[TestUser]()
 	spec := atomizer.Spec()
-	db := New[TestUser](executor, "id", "test_users", spec)
+	db := New[TestUser](mockDB, executor, "id", "test_users", spec)
 
 	ctx := context.Background()
 
@@ -855,3 +858,163 @@ func TestDatabase_ExecSelectTx_StatementError(t *testing.T) {
 		t.Error("expected error for invalid statement field")
 	}
 }
+
+// nullableRecord covers every nullable kind the atom tables support.
+type nullableRecord struct {
+	ID      int        `db:"id" json:"id" constraints:"primarykey"`
+	Label   *string    `db:"label" json:"label"`
+	Qty     *int       `db:"qty" json:"qty"`
+	Units   *uint      `db:"units" json:"units"`
+	Ratio   *float64   `db:"ratio" json:"ratio"`
+	Active  *bool      `db:"active" json:"active"`
+	Blob    *[]byte    `db:"blob" json:"blob"`
+	Created time.Time  `db:"created" json:"created"`
+	Expires *time.Time `db:"expires" json:"expires"`
+}
+
+var nullableTime = time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+// populatedNullable returns a record with every pointer set.
+func populatedNullable(id int) *nullableRecord {
+	label, qty, units, ratio, active, blob := "x", 3, uint(4), 1.5, true, []byte("b")
+	expires := nullableTime.Add(time.Hour)
+	return &nullableRecord{
+		ID: id, Label: &label, Qty: &qty, Units: &units, Ratio: &ratio,
+		Active: &active, Blob: &blob, Created: nullableTime, Expires: &expires,
+	}
+}
+
+// checkNullableAtom verifies the pointer tables of an atomized nullableRecord.
+func checkNullableAtom(t *testing.T, a *atom.Atom, populated bool) {
+	t.Helper()
+	if !a.Times["Created"].Equal(nullableTime) {
+		t.Errorf("Created: got %v", a.Times["Created"])
+	}
+	ptrs := map[string]bool{
+		"Label":   a.StringPtrs["Label"] != nil,
+		"Qty":     a.IntPtrs["Qty"] != nil,
+		"Units":   a.UintPtrs["Units"] != nil,
+		"Ratio":   a.FloatPtrs["Ratio"] != nil,
+		"Active":  a.BoolPtrs["Active"] != nil,
+		"Blob":    a.BytePtrs["Blob"] != nil,
+		"Expires": a.TimePtrs["Expires"] != nil,
+	}
+	for field, set := range ptrs {
+		if set != populated {
+			t.Errorf("%s: expected set=%v", field, populated)
+		}
+	}
+	if !populated {
+		return
+	}
+	if *a.StringPtrs["Label"] != "x" || *a.IntPtrs["Qty"] != 3 || *a.UintPtrs["Units"] != 4 ||
+		*a.FloatPtrs["Ratio"] != 1.5 || !*a.BoolPtrs["Active"] || string(*a.BytePtrs["Blob"]) != "b" ||
+		!a.TimePtrs["Expires"].Equal(nullableTime.Add(time.Hour)) {
+		t.Errorf("unexpected pointer values in %+v", a)
+	}
+}
+
+// checkNullableRecord verifies a deatomized nullableRecord.
+func checkNullableRecord(t *testing.T, got *nullableRecord, populated bool) {
+	t.Helper()
+	want := &nullableRecord{ID: got.ID, Created: nullableTime}
+	if populated {
+		want = populatedNullable(got.ID)
+	}
+	if !got.Created.Equal(want.Created) {
+		t.Errorf("Created: got %v, want %v", got.Created, want.Created)
+	}
+	got.Created = want.Created
+	if (got.Expires == nil) != (want.Expires == nil) || (got.Expires != nil && !got.Expires.Equal(*want.Expires)) {
+		t.Errorf("Expires: got %v, want %v", got.Expires, want.Expires)
+	}
+	got.Expires = want.Expires
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDatabase_SetNullable(t *testing.T) {
+	mockDB, capture := mockdb.New()
+	executor, err := edamame.New[nullableRecord](mockDB, "nullables", testRenderer)
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+	atomizer, err := atom.Use[nullableRecord]()
+	if err != nil {
+		t.Fatalf("atom.Use failed: %v", err)
+	}
+	db := New[nullableRecord](mockDB, executor, "id", "nullables", atomizer.Spec())
+	ctx := context.Background()
+
+	populated := atomizer.Atomize(populatedNullable(1))
+	checkNullableAtom(t, populated, true)
+	_ = db.Set(ctx, "1", populated)
+	query, ok := capture.Last()
+	if !ok {
+		t.Fatal("no query captured")
+	}
+	for i, arg := range query.Args {
+		if arg == nil {
+			t.Errorf("arg %d: populated pointer written as NULL in %s", i, query.Query)
+		}
+	}
+
+	empty := atomizer.Atomize(&nullableRecord{ID: 2, Created: nullableTime})
+	checkNullableAtom(t, empty, false)
+	capture.Reset()
+	_ = db.Set(ctx, "2", empty)
+	query, _ = capture.Last()
+	// Only the key and the non-pointer time may be non-NULL.
+	nulls := 0
+	for _, arg := range query.Args {
+		switch v := arg.(type) {
+		case nil:
+			nulls++
+		case time.Time:
+			if !v.Equal(nullableTime) {
+				t.Errorf("unexpected time arg %v", v)
+			}
+		default:
+			if fmt.Sprint(v) != "2" {
+				t.Errorf("nil pointer written as %v", v)
+			}
+		}
+	}
+	if nulls == 0 {
+		t.Errorf("expected NULL args for nil pointers, got %v", query.Args)
+	}
+
+	back, err := atomizer.Deatomize(populated)
+	if err != nil {
+		t.Fatalf("Deatomize failed: %v", err)
+	}
+	checkNullableRecord(t, back, true)
+}
+
+func TestDatabase_NilTx(t *testing.T) {
+	mockDB, _ := mockdb.New()
+	executor, err := edamame.New[TestUser](mockDB, "test_users", testRenderer)
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+	atomizer, _ := atom.Use[TestUser]()
+	db := New[TestUser](mockDB, executor, "id", "test_users", atomizer.Spec())
+	ctx := context.Background()
+	all := edamame.NewQueryStatement("all", "", edamame.QuerySpec{})
+	one := edamame.NewSelectStatement("one", "", edamame.SelectSpec{})
+
+	calls := map[string]func() error{
+		"GetTx":        func() error { _, err := db.GetTx(ctx, nil, "1"); return err },
+		"SetTx":        func() error { return db.SetTx(ctx, nil, "1", atomizer.Atomize(&TestUser{ID: 1})) },
+		"DeleteTx":     func() error { return db.DeleteTx(ctx, nil, "1") },
+		"ExistsTx":     func() error { _, err := db.ExistsTx(ctx, nil, "1"); return err },
+		"ExecQueryTx":  func() error { _, err := db.ExecQueryTx(ctx, nil, all, nil); return err },
+		"ExecSelectTx": func() error { _, err := db.ExecSelectTx(ctx, nil, one, nil); return err },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, shared.ErrNilTransaction) {
+			t.Errorf("%s: expected ErrNilTransaction, got %v", name, err)
+		}
+	}
+}
@@ -30,6 +30,8 @@ type Index[T any] struct {
 	provider VectorProvider
 	codec    Codec
 	spec     atom.Spec
+	redact   []string
+	readOnly bool
 }
 
 // NewIndex creates an atomic Index wrapper.
@@ -41,6 +43,21 @@ func NewIndex[T any](provider VectorProvider, codec Codec, spec atom.Spec) *Inde
 	}
 }
 
+// Redact zeroes the named top-level fields of T in every record read through
+// this view and rejects writes that would persist any of them as zero.
+func (i *Index[T]) Redact(fields []string) *Index[T] {
+	i.redact = fields
+	return i
+}
+
+// ReadOnly returns a view sharing this index's provider and settings whose
+// Upsert and Delete fail with ErrReadOnly without reaching the provider.
+func (i *Index[T]) ReadOnly() *Index[T] {
+	view := *i
+	view.readOnly = true
+	return &view
+}
+
 // Spec returns the atom spec for this index's metadata type.
 func (i *Index[T]) Spec() atom.Spec {
 	return i.spec
@@ -66,6 +83,9 @@ func (i *Index[T]) Get(ctx context.Context, id uuid.UUID) (*shared.AtomicVector,
 
 // Upsert stores a vector with atomized metadata.
 func (i *Index[T]) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata *atom.Atom) error {
+	if i.readOnly {
+		return readOnlyErr(shared.KindIndex, "upsert", "", id.String())
+	}
 	m, err := i.atomToMetadata(metadata)
 	if err != nil {
 		return err
@@ -75,6 +95,9 @@ func (i *Index[T]) Upsert(ctx context.Context, id uuid.UUID, vector []float32, m
 
 // Delete removes the vector at ID.
 func (i *Index[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	if i.readOnly {
+		return readOnlyErr(shared.KindIndex, "delete", "", id.String())
+	}
 	return i.provider.Delete(ctx, id)
 }
 
@@ -166,7 +189,9 @@ func (i *Index[T]) metadataToAtom(data []byte) (*atom.Atom, error) {
 	if err != nil {
 		return nil, err
 	}
-	return atomizer.Atomize(&value), nil
+	a := atomizer.Atomize(&value)
+	maskAtom(a, i.redact)
+	return a, nil
 }
 
 // atomToMetadata converts an Atom to bytes metadata via T.
@@ -182,6 +207,9 @@ func (i *Index[T]) atomToMetadata(a *atom.Atom) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := checkRedacted(value, i.redact); err != nil {
+		return nil, err
+	}
 	return i.codec.Encode(value)
 }
 
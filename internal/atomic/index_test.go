@@ -38,8 +38,6 @@ func newMockVectorProvider() *mockVectorProvider {
 	}
 }
 
-var errVectorNotFound = errors.New("not found")
-
 func (m *mockVectorProvider) Upsert(_ context.Context, id uuid.UUID, vector []float32, metadata []byte) error {
 	if m.upsertErr != nil {
 		return m.upsertErr
@@ -64,7 +62,7 @@ func (m *mockVectorProvider) Get(_ context.Context, id uuid.UUID) ([]float32, *s
 	}
 	entry, ok := m.vectors[id]
 	if !ok {
-		return nil, nil, errVectorNotFound
+		return nil, nil, shared.ErrNotFound
 	}
 	return entry.vector, &shared.VectorInfo{
 		ID:        id,
@@ -78,7 +76,7 @@ func (m *mockVectorProvider) Delete(_ context.Context, id uuid.UUID) error {
 		return m.deleteErr
 	}
 	if _, ok := m.vectors[id]; !ok {
-		return errVectorNotFound
+		return shared.ErrNotFound
 	}
 	delete(m.vectors, id)
 	return nil
@@ -864,3 +862,42 @@ func TestIndex_NilMetadata(t *testing.T) {
 		}
 	})
 }
+
+func TestIndex_NullableRoundTrip(t *testing.T) {
+	provider := newMockVectorProvider()
+	atomizer, _ := atom.Use[nullableRecord]()
+	index := NewIndex[nullableRecord](provider, vectorJSONCodec{}, atomizer.Spec())
+	ctx := context.Background()
+	vector := []float32{1, 2, 3}
+
+	for _, populated := range []bool{true, false} {
+		record := &nullableRecord{ID: 1, Created: nullableTime}
+		if populated {
+			record = populatedNullable(1)
+		}
+
+		// Typed write, atomic read.
+		typedID := uuid.New()
+		data, err := vectorJSONCodec{}.Encode(record)
+		if err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+		provider.vectors[typedID] = vectorEntry{vector: vector, metadata: data}
+		got, err := index.Get(ctx, typedID)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		checkNullableAtom(t, got.Metadata, populated)
+
+		// Atomic write, typed read.
+		atomicID := uuid.New()
+		if err := index.Upsert(ctx, atomicID, vector, atomizer.Atomize(record)); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		var back nullableRecord
+		if err := (vectorJSONCodec{}).Decode(provider.vectors[atomicID].metadata, &back); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		checkNullableRecord(t, &back, populated)
+	}
+}
@@ -0,0 +1,20 @@
+package atomic
+
+import "github.com/zoobzio/grub/internal/shared"
+
+// KeyFunc normalizes and validates a key, returning the key to use.
+// Mirrors the KeyPolicy attached to the parent Store or Bucket.
+type KeyFunc func(key string) (string, error)
+
+// applyKey runs keys on key, wrapping a rejection like the parent facade
+// does. A nil KeyFunc accepts every key unchanged.
+func applyKey(keys KeyFunc, kind, op, key string) (string, error) {
+	if keys == nil {
+		return key, nil
+	}
+	normalized, err := keys(key)
+	if err != nil {
+		return key, shared.WrapError(kind, op, "", key, err)
+	}
+	return normalized, nil
+}
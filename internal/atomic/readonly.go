@@ -0,0 +1,9 @@
+package atomic
+
+import "github.com/zoobzio/grub/internal/shared"
+
+// readOnlyErr reports a write attempted through a ReadOnly view, naming the
+// operation so the offending call is clear from the error alone.
+func readOnlyErr(kind, op, table, key string) error {
+	return shared.WrapError(kind, op, table, key, shared.ErrReadOnly)
+}
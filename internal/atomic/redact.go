@@ -0,0 +1,48 @@
+package atomic
+
+import (
+	"reflect"
+
+	"github.com/zoobzio/atom"
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// maskAtom zeroes the named top-level fields of a wherever they appear.
+func maskAtom(a *atom.Atom, fields []string) {
+	if a == nil || len(fields) == 0 {
+		return
+	}
+	v := reflect.ValueOf(a).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		m := v.Field(i)
+		if m.Kind() != reflect.Map || m.IsNil() || m.Type().Key().Kind() != reflect.String {
+			continue
+		}
+		for _, name := range fields {
+			key := reflect.ValueOf(name)
+			if m.MapIndex(key).IsValid() {
+				m.SetMapIndex(key, reflect.Zero(m.Type().Elem()))
+			}
+		}
+	}
+}
+
+// checkRedacted returns ErrRedacted if any named field of value is zero, so
+// a record read through a redacting view is not written back without its
+// secrets. Fields tagged grub:"redact,allowempty" may be zero.
+func checkRedacted(value any, fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	v := reflect.Indirect(reflect.ValueOf(value))
+	for _, name := range fields {
+		sf, ok := v.Type().FieldByName(name)
+		if !ok || shared.HasGrubTag(sf, "allowempty") {
+			continue
+		}
+		if v.FieldByIndex(sf.Index).IsZero() {
+			return shared.ErrRedacted
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/zoobzio/atom"
+	"github.com/zoobzio/grub/internal/shared"
 )
 
 // StoreProvider defines raw key-value storage operations.
@@ -29,6 +30,9 @@ type Store[T any] struct {
 	provider StoreProvider
 	codec    Codec
 	spec     atom.Spec
+	redact   []string
+	keys     KeyFunc
+	readOnly bool
 }
 
 // NewStore creates an atomic Store wrapper.
@@ -40,6 +44,28 @@ func NewStore[T any](provider StoreProvider, codec Codec, spec atom.Spec) *Store
 	}
 }
 
+// Redact zeroes the named top-level fields of T in every record read through
+// this view and rejects writes that would persist any of them as zero.
+func (s *Store[T]) Redact(fields []string) *Store[T] {
+	s.redact = fields
+	return s
+}
+
+// Keys applies keys to every key passed to this view, so the view honours
+// the parent's KeyPolicy.
+func (s *Store[T]) Keys(keys KeyFunc) *Store[T] {
+	s.keys = keys
+	return s
+}
+
+// ReadOnly returns a view sharing this store's provider and settings whose
+// Set and Delete fail with ErrReadOnly without reaching the provider.
+func (s *Store[T]) ReadOnly() *Store[T] {
+	view := *s
+	view.readOnly = true
+	return &view
+}
+
 // Spec returns the atom spec for this store's type.
 func (s *Store[T]) Spec() atom.Spec {
 	return s.spec
@@ -47,6 +73,10 @@ func (s *Store[T]) Spec() atom.Spec {
 
 // Get retrieves the value at key as an Atom.
 func (s *Store[T]) Get(ctx context.Context, key string) (*atom.Atom, error) {
+	key, err := applyKey(s.keys, shared.KindStore, "get", key)
+	if err != nil {
+		return nil, err
+	}
 	data, err := s.provider.Get(ctx, key)
 	if err != nil {
 		return nil, err
@@ -59,11 +89,20 @@ func (s *Store[T]) Get(ctx context.Context, key string) (*atom.Atom, error) {
 	if err != nil {
 		return nil, err
 	}
-	return atomizer.Atomize(&value), nil
+	a := atomizer.Atomize(&value)
+	maskAtom(a, s.redact)
+	return a, nil
 }
 
 // Set stores an Atom at key with optional TTL.
 func (s *Store[T]) Set(ctx context.Context, key string, a *atom.Atom, ttl time.Duration) error {
+	if s.readOnly {
+		return readOnlyErr(shared.KindStore, "set", "", key)
+	}
+	key, err := applyKey(s.keys, shared.KindStore, "set", key)
+	if err != nil {
+		return err
+	}
 	atomizer, err := atom.Use[T]()
 	if err != nil {
 		return err
@@ -72,6 +111,9 @@ func (s *Store[T]) Set(ctx context.Context, key string, a *atom.Atom, ttl time.D
 	if err != nil {
 		return err
 	}
+	if err := checkRedacted(value, s.redact); err != nil {
+		return err
+	}
 	data, err := s.codec.Encode(value)
 	if err != nil {
 		return err
@@ -81,10 +123,21 @@ func (s *Store[T]) Set(ctx context.Context, key string, a *atom.Atom, ttl time.D
 
 // Delete removes the value at key.
 func (s *Store[T]) Delete(ctx context.Context, key string) error {
+	if s.readOnly {
+		return readOnlyErr(shared.KindStore, "delete", "", key)
+	}
+	key, err := applyKey(s.keys, shared.KindStore, "delete", key)
+	if err != nil {
+		return err
+	}
 	return s.provider.Delete(ctx, key)
 }
 
 // Exists checks whether a key exists.
 func (s *Store[T]) Exists(ctx context.Context, key string) (bool, error) {
+	key, err := applyKey(s.keys, shared.KindStore, "exists", key)
+	if err != nil {
+		return false, err
+	}
 	return s.provider.Exists(ctx, key)
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/zoobzio/atom"
+	"github.com/zoobzio/grub/internal/shared"
 )
 
 // mockStoreProvider implements StoreProvider for testing.
@@ -335,3 +336,52 @@ func TestStore_RoundTrip(t *testing.T) {
 		t.Errorf("Name mismatch: got %v, want %q", retrieved.Strings["Name"], original.Name)
 	}
 }
+
+func TestStore_Redact(t *testing.T) {
+	provider := newMockStoreProvider()
+	codec := jsonCodec{}
+	atomizer, _ := atom.Use[testRecord]()
+	spec := atomizer.Spec()
+	store := NewStore[testRecord](provider, codec, spec).Redact([]string{"ID", "Name"})
+	ctx := context.Background()
+
+	provider.data["k"], _ = json.Marshal(testRecord{ID: 7, Name: "secret"})
+
+	retrieved, err := store.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if retrieved.Strings["Name"] != "" || retrieved.Ints["ID"] != 0 {
+		t.Errorf("expected ID and Name masked, got %v and %q", retrieved.Ints["ID"], retrieved.Strings["Name"])
+	}
+
+	if err := store.Set(ctx, "k", retrieved, 0); !errors.Is(err, shared.ErrRedacted) {
+		t.Errorf("expected ErrRedacted, got %v", err)
+	}
+
+	retrieved.Ints["ID"] = 7
+	if err := store.Set(ctx, "k", retrieved, 0); !errors.Is(err, shared.ErrRedacted) {
+		t.Errorf("expected ErrRedacted for an empty Name, got %v", err)
+	}
+}
+
+type hintRecord struct {
+	ID   int64  `json:"id"`
+	Hint string `json:"hint" grub:"redact,allowempty"`
+}
+
+func TestStore_RedactAllowEmpty(t *testing.T) {
+	provider := newMockStoreProvider()
+	atomizer, _ := atom.Use[hintRecord]()
+	store := NewStore[hintRecord](provider, jsonCodec{}, atomizer.Spec()).Redact([]string{"Hint"})
+	ctx := context.Background()
+
+	provider.data["k"], _ = json.Marshal(hintRecord{ID: 7, Hint: "secret"})
+	retrieved, err := store.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := store.Set(ctx, "k", retrieved, 0); err != nil {
+		t.Errorf("expected an empty allowempty field to be accepted, got %v", err)
+	}
+}
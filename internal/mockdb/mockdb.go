@@ -6,7 +6,10 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"io"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -17,6 +20,13 @@ var globalCapture = &Capture{}
 // globalConfig is shared across all connections for configurable behavior.
 var globalConfig = &Config{}
 
+// isolated maps the DSNs opened by NewIsolated to their own capture and
+// config.
+var (
+	isolated    sync.Map // string -> *Conn
+	isolatedSeq atomic.Int64
+)
+
 func init() {
 	sql.Register("mockdb", &Driver{})
 }
@@ -25,9 +35,23 @@ func init() {
 type Config struct {
 	mu              sync.Mutex
 	QueryErr        error // Error to return from QueryContext
+	queryErrTimes   int   // Queries left to fail with QueryErr; 0 fails them all
 	ExecErr         error // Error to return from ExecContext
+	execErrTimes    int   // Execs left to fail with ExecErr; 0 fails them all
+	CommitErr       error // Error to return from Tx.Commit
 	RowsAffected    int64 // Value to return from RowsAffected (default 1)
 	rowsAffectedSet bool  // Whether RowsAffected was explicitly set
+	columns         []string
+	rows            [][]driver.Value
+	queryRows       []queryRows     // overrides for queries containing a match
+	queryGate       <-chan struct{} // QueryContext blocks until closed
+}
+
+// queryRows are the columns and rows returned for queries containing match.
+type queryRows struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
 }
 
 // SetQueryErr sets the error to return from queries.
@@ -35,6 +59,16 @@ func (c *Config) SetQueryErr(err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.QueryErr = err
+	c.queryErrTimes = 0
+}
+
+// SetQueryErrTimes makes the next n queries fail with err; later ones
+// succeed.
+func (c *Config) SetQueryErrTimes(err error, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.QueryErr = err
+	c.queryErrTimes = n
 }
 
 // SetExecErr sets the error to return from exec operations.
@@ -42,6 +76,23 @@ func (c *Config) SetExecErr(err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.ExecErr = err
+	c.execErrTimes = 0
+}
+
+// SetExecErrTimes makes the next n exec operations fail with err; later
+// ones succeed.
+func (c *Config) SetExecErrTimes(err error, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ExecErr = err
+	c.execErrTimes = n
+}
+
+// SetCommitErr sets the error to return from transaction commits.
+func (c *Config) SetCommitErr(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.CommitErr = err
 }
 
 // SetRowsAffected sets the rows affected value to return.
@@ -52,26 +103,105 @@ func (c *Config) SetRowsAffected(n int64) {
 	c.rowsAffectedSet = true
 }
 
+// SetRows sets the columns and rows every query returns.
+func (c *Config) SetRows(columns []string, rows ...[]driver.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.columns = columns
+	c.rows = rows
+}
+
+// SetQueryRows sets the columns and rows returned for queries containing
+// match, taking precedence over SetRows. The first matching override wins.
+func (c *Config) SetQueryRows(match string, columns []string, rows ...[]driver.Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queryRows = append(c.queryRows, queryRows{match: match, columns: columns, rows: rows})
+}
+
+// SetQueryGate makes QueryContext block until gate is closed or the
+// query's context ends, so tests can hold queries in flight.
+func (c *Config) SetQueryGate(gate <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queryGate = gate
+}
+
+// wait blocks on the query gate, if any, returning ctx's error if it ends
+// first.
+func (c *Config) wait(ctx context.Context) error {
+	c.mu.Lock()
+	gate := c.queryGate
+	c.mu.Unlock()
+	if gate == nil {
+		return nil
+	}
+	select {
+	case <-gate:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Reset resets all configuration to defaults.
 func (c *Config) Reset() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.QueryErr = nil
+	c.queryErrTimes = 0
 	c.ExecErr = nil
+	c.execErrTimes = 0
+	c.CommitErr = nil
 	c.RowsAffected = 0
 	c.rowsAffectedSet = false
+	c.columns = nil
+	c.rows = nil
+	c.queryRows = nil
+	c.queryGate = nil
 }
 
 func (c *Config) getQueryErr() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return c.QueryErr
+	err := c.QueryErr
+	if err != nil && c.queryErrTimes > 0 {
+		c.queryErrTimes--
+		if c.queryErrTimes == 0 {
+			c.QueryErr = nil
+		}
+	}
+	return err
 }
 
 func (c *Config) getExecErr() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return c.ExecErr
+	err := c.ExecErr
+	if err != nil && c.execErrTimes > 0 {
+		c.execErrTimes--
+		if c.execErrTimes == 0 {
+			c.ExecErr = nil
+		}
+	}
+	return err
+}
+
+func (c *Config) getCommitErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.CommitErr
+}
+
+func (c *Config) newRows(query string) *Rows {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, q := range c.queryRows {
+		if strings.Contains(query, q.match) {
+			return &Rows{columns: q.columns, rows: q.rows}
+		}
+	}
+	return &Rows{columns: c.columns, rows: c.rows}
 }
 
 func (c *Config) getRowsAffected() int64 {
@@ -86,15 +216,21 @@ func (c *Config) getRowsAffected() int64 {
 // Driver is a mock SQL driver that captures queries.
 type Driver struct{}
 
-// Open returns a new mock connection.
-func (*Driver) Open(_ string) (driver.Conn, error) {
+// Open returns a new mock connection, sharing the capture and config of
+// its DSN if it was opened by NewIsolated and the global ones otherwise.
+func (*Driver) Open(name string) (driver.Conn, error) {
+	if c, ok := isolated.Load(name); ok {
+		template := c.(*Conn)
+		return &Conn{capture: template.capture, config: template.config}, nil
+	}
 	return &Conn{capture: globalCapture, config: globalConfig}, nil
 }
 
 // Capture holds captured query information.
 type Capture struct {
-	mu      sync.Mutex
-	Queries []CapturedQuery
+	mu       sync.Mutex
+	Queries  []CapturedQuery
+	prepared []string
 }
 
 // CapturedQuery represents a captured SQL query.
@@ -118,6 +254,14 @@ func (c *Capture) Reset() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.Queries = nil
+	c.prepared = nil
+}
+
+// Prepared returns the queries prepared so far, in order.
+func (c *Capture) Prepared() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.prepared...)
 }
 
 // add records a query.
@@ -138,9 +282,12 @@ func (c *Conn) Capture() *Capture {
 	return c.capture
 }
 
-// Prepare returns a mock statement.
+// Prepare records the query and returns a mock statement.
 func (c *Conn) Prepare(query string) (driver.Stmt, error) {
-	return &Stmt{query: query, capture: c.capture}, nil
+	c.capture.mu.Lock()
+	c.capture.prepared = append(c.capture.prepared, query)
+	c.capture.mu.Unlock()
+	return &Stmt{query: query, capture: c.capture, config: c.config}, nil
 }
 
 // Close is a no-op.
@@ -149,17 +296,27 @@ func (*Conn) Close() error {
 }
 
 // Begin returns a mock transaction.
-func (*Conn) Begin() (driver.Tx, error) {
-	return &Tx{}, nil
+func (c *Conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx implements driver.ConnBeginTx.
+// Captures "BEGIN" with the requested isolation level as its only argument.
+func (c *Conn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.capture.add("BEGIN", []any{opts.Isolation})
+	return &Tx{capture: c.capture, config: c.config}, nil
 }
 
 // QueryContext implements driver.QueryerContext.
-func (c *Conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+func (c *Conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
 	c.capture.add(query, namedValuesToAny(args))
+	if err := c.config.wait(ctx); err != nil {
+		return nil, err
+	}
 	if err := c.config.getQueryErr(); err != nil {
 		return nil, err
 	}
-	return &Rows{}, nil
+	return c.config.newRows(query), nil
 }
 
 // ExecContext implements driver.ExecerContext.
@@ -175,6 +332,7 @@ func (c *Conn) ExecContext(_ context.Context, query string, args []driver.NamedV
 type Stmt struct {
 	query   string
 	capture *Capture
+	config  *Config
 }
 
 // Close is a no-op.
@@ -187,28 +345,47 @@ func (*Stmt) NumInput() int {
 	return -1
 }
 
-// Exec captures the query and returns a mock result.
+// Exec captures the query and returns a mock result, honouring the
+// connection's config when the statement was prepared on one.
 func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
 	s.capture.add(s.query, valuesToAny(args))
-	return &Result{}, nil
+	if s.config == nil {
+		return &Result{}, nil
+	}
+	if err := s.config.getExecErr(); err != nil {
+		return nil, err
+	}
+	return &Result{rowsAffected: s.config.getRowsAffected()}, nil
 }
 
-// Query captures the query and returns empty rows.
+// Query captures the query and returns the connection's configured rows,
+// or empty rows when the statement has no connection.
 func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
 	s.capture.add(s.query, valuesToAny(args))
-	return &Rows{}, nil
+	if s.config == nil {
+		return &Rows{}, nil
+	}
+	if err := s.config.getQueryErr(); err != nil {
+		return nil, err
+	}
+	return s.config.newRows(s.query), nil
 }
 
 // Tx is a mock transaction.
-type Tx struct{}
+type Tx struct {
+	capture *Capture
+	config  *Config
+}
 
-// Commit is a no-op.
-func (*Tx) Commit() error {
-	return nil
+// Commit captures "COMMIT" and returns the configured commit error.
+func (t *Tx) Commit() error {
+	t.capture.add("COMMIT", nil)
+	return t.config.getCommitErr()
 }
 
-// Rollback is a no-op.
-func (*Tx) Rollback() error {
+// Rollback captures "ROLLBACK".
+func (t *Tx) Rollback() error {
+	t.capture.add("ROLLBACK", nil)
 	return nil
 }
 
@@ -229,12 +406,17 @@ func (r *Result) RowsAffected() (int64, error) {
 
 // Rows is a mock rows result.
 type Rows struct {
-	closed bool
+	closed  bool
+	columns []string
+	rows    [][]driver.Value
 }
 
-// Columns returns empty column list.
-func (*Rows) Columns() []string {
-	return []string{}
+// Columns returns the configured columns, or an empty list.
+func (r *Rows) Columns() []string {
+	if r.columns == nil {
+		return []string{}
+	}
+	return r.columns
 }
 
 // Close marks rows as closed.
@@ -243,9 +425,15 @@ func (r *Rows) Close() error {
 	return nil
 }
 
-// Next always returns io.EOF (no rows).
-func (*Rows) Next(_ []driver.Value) error {
-	return io.EOF
+// Next copies the next configured row into dest, returning io.EOF when
+// none remain.
+func (r *Rows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
 }
 
 // New creates a new mock database connection and returns the sqlx.DB and the capture.
@@ -270,6 +458,19 @@ func NewWithConfig() (*sqlx.DB, *Capture, *Config) {
 	return sqlx.NewDb(db, "mockdb"), globalCapture, globalConfig
 }
 
+// NewIsolated creates a mock database connection with its own capture and
+// config, for tests that use several databases at once.
+func NewIsolated() (*sqlx.DB, *Capture, *Config) {
+	name := "isolated-" + strconv.FormatInt(isolatedSeq.Add(1), 10)
+	conn := &Conn{capture: &Capture{}, config: &Config{}}
+	isolated.Store(name, conn)
+	db, err := sql.Open("mockdb", name)
+	if err != nil {
+		panic("mockdb: failed to open: " + err.Error())
+	}
+	return sqlx.NewDb(db, "mockdb"), conn.capture, conn.config
+}
+
 func namedValuesToAny(nvs []driver.NamedValue) []any {
 	result := make([]any, len(nvs))
 	for i, nv := range nvs {
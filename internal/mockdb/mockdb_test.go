@@ -34,6 +34,37 @@ func TestNewWithConfig(t *testing.T) {
 	}
 }
 
+func TestNewIsolated(t *testing.T) {
+	ctx := context.Background()
+	shared, sharedCapture := New()
+	db1, capture1, config1 := NewIsolated()
+	db2, capture2, _ := NewIsolated()
+
+	config1.SetExecErr(errors.New("exec error"))
+	if _, err := db1.ExecContext(ctx, "INSERT INTO one VALUES (?)", 1); err == nil {
+		t.Error("expected db1 to use its own config")
+	}
+	if _, err := db2.ExecContext(ctx, "INSERT INTO two VALUES (?)", 2); err != nil {
+		t.Errorf("db2 exec failed: %v", err)
+	}
+	if _, err := shared.ExecContext(ctx, "INSERT INTO shared VALUES (?)", 3); err != nil {
+		t.Errorf("shared exec failed: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		capture *Capture
+		query   string
+	}{
+		"db1":    {capture1, "INSERT INTO one VALUES (?)"},
+		"db2":    {capture2, "INSERT INTO two VALUES (?)"},
+		"shared": {sharedCapture, "INSERT INTO shared VALUES (?)"},
+	} {
+		if len(tc.capture.Queries) != 1 || tc.capture.Queries[0].Query != tc.query {
+			t.Errorf("%s: expected only %q captured, got %v", name, tc.query, tc.capture.Queries)
+		}
+	}
+}
+
 func TestNew_ResetsCapture(t *testing.T) {
 	db1, capture1 := New()
 	ctx := context.Background()
@@ -160,6 +191,50 @@ func TestConfig_SetExecErr(t *testing.T) {
 	}
 }
 
+func TestConfig_SetQueryErrTimes(t *testing.T) {
+	c := &Config{}
+	testErr := errors.New("query error")
+
+	c.SetQueryErrTimes(testErr, 1)
+	if !errors.Is(c.getQueryErr(), testErr) {
+		t.Error("expected the first query to fail")
+	}
+	if c.getQueryErr() != nil {
+		t.Error("expected queries after n to succeed")
+	}
+}
+
+func TestConfig_SetExecErrTimes(t *testing.T) {
+	c := &Config{}
+	testErr := errors.New("exec error")
+
+	c.SetExecErrTimes(testErr, 2)
+	for i := range 2 {
+		if !errors.Is(c.getExecErr(), testErr) {
+			t.Errorf("exec %d: expected error", i)
+		}
+	}
+	if c.getExecErr() != nil {
+		t.Error("expected execs after n to succeed")
+	}
+}
+
+func TestConfig_SetQueryGate(t *testing.T) {
+	c := &Config{}
+	gate := make(chan struct{})
+	c.SetQueryGate(gate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled wait, got %v", err)
+	}
+	close(gate)
+	if err := c.wait(context.Background()); err != nil {
+		t.Errorf("expected an open gate to pass, got %v", err)
+	}
+}
+
 func TestConfig_SetRowsAffected(t *testing.T) {
 	c := &Config{}
 
@@ -328,6 +403,19 @@ func TestConn_Prepare(t *testing.T) {
 	if stmt == nil {
 		t.Fatal("Prepare returned nil statement")
 	}
+	if got := capture.Prepared(); len(got) != 1 || got[0] != "SELECT * FROM users WHERE id = ?" {
+		t.Errorf("expected prepared query recorded, got %v", got)
+	}
+
+	config.SetRows([]string{"id"}, []driver.Value{int64(7)})
+	rows, err := stmt.Query(nil)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	dest := make([]driver.Value, 1)
+	if err := rows.Next(dest); err != nil || dest[0] != int64(7) {
+		t.Errorf("expected configured row, got %v, %v", dest, err)
+	}
 }
 
 func TestConn_Close(t *testing.T) {
@@ -338,7 +426,8 @@ func TestConn_Close(t *testing.T) {
 }
 
 func TestConn_Begin(t *testing.T) {
-	conn := &Conn{}
+	capture := &Capture{}
+	conn := &Conn{capture: capture, config: &Config{}}
 	tx, err := conn.Begin()
 	if err != nil {
 		t.Fatalf("Begin failed: %v", err)
@@ -346,6 +435,23 @@ func TestConn_Begin(t *testing.T) {
 	if tx == nil {
 		t.Fatal("Begin returned nil transaction")
 	}
+	last, ok := capture.Last()
+	if !ok || last.Query != "BEGIN" {
+		t.Errorf("expected BEGIN to be captured, got %+v", last)
+	}
+}
+
+func TestConn_BeginTx(t *testing.T) {
+	capture := &Capture{}
+	conn := &Conn{capture: capture, config: &Config{}}
+	opts := driver.TxOptions{Isolation: driver.IsolationLevel(6)}
+	if _, err := conn.BeginTx(context.Background(), opts); err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	last, _ := capture.Last()
+	if len(last.Args) != 1 || last.Args[0] != opts.Isolation {
+		t.Errorf("expected isolation level in args, got %v", last.Args)
+	}
 }
 
 func TestConn_Capture(t *testing.T) {
@@ -417,17 +523,35 @@ func TestStmt_Close(t *testing.T) {
 }
 
 func TestTx_Commit(t *testing.T) {
-	tx := &Tx{}
+	capture := &Capture{}
+	tx := &Tx{capture: capture, config: &Config{}}
 	if err := tx.Commit(); err != nil {
 		t.Errorf("Commit returned error: %v", err)
 	}
+	if last, _ := capture.Last(); last.Query != "COMMIT" {
+		t.Errorf("expected COMMIT to be captured, got %q", last.Query)
+	}
+}
+
+func TestTx_CommitErr(t *testing.T) {
+	config := &Config{}
+	commitErr := errors.New("commit failed")
+	config.SetCommitErr(commitErr)
+	tx := &Tx{capture: &Capture{}, config: config}
+	if err := tx.Commit(); !errors.Is(err, commitErr) {
+		t.Errorf("expected commit error, got %v", err)
+	}
 }
 
 func TestTx_Rollback(t *testing.T) {
-	tx := &Tx{}
+	capture := &Capture{}
+	tx := &Tx{capture: capture, config: &Config{}}
 	if err := tx.Rollback(); err != nil {
 		t.Errorf("Rollback returned error: %v", err)
 	}
+	if last, _ := capture.Last(); last.Query != "ROLLBACK" {
+		t.Errorf("expected ROLLBACK to be captured, got %q", last.Query)
+	}
 }
 
 func TestResult_LastInsertId(t *testing.T) {
@@ -483,6 +607,55 @@ func TestRows_Next(t *testing.T) {
 	}
 }
 
+func TestConfig_SetRows(t *testing.T) {
+	config := &Config{}
+	config.SetRows([]string{"id", "name"}, []driver.Value{int64(1), "a"}, []driver.Value{int64(2), "b"})
+	conn := &Conn{capture: &Capture{}, config: config}
+
+	rows, err := conn.QueryContext(context.Background(), "SELECT id, name FROM users", nil)
+	if err != nil {
+		t.Fatalf("QueryContext failed: %v", err)
+	}
+	if cols := rows.Columns(); len(cols) != 2 || cols[1] != "name" {
+		t.Errorf("unexpected columns: %v", cols)
+	}
+	dest := make([]driver.Value, 2)
+	for _, want := range []string{"a", "b"} {
+		if err := rows.Next(dest); err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if dest[1] != want {
+			t.Errorf("expected %q, got %v", want, dest[1])
+		}
+	}
+	if err := rows.Next(dest); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+
+	config.Reset()
+	if cols := config.newRows("").Columns(); len(cols) != 0 {
+		t.Errorf("Reset did not clear rows, got columns %v", cols)
+	}
+}
+
+func TestConfig_SetQueryRows(t *testing.T) {
+	config := &Config{}
+	config.SetRows([]string{"id", "name"}, []driver.Value{int64(1), "a"})
+	config.SetQueryRows("EXISTS", []string{"exists"}, []driver.Value{true})
+
+	if cols := config.newRows("SELECT EXISTS (SELECT id FROM users)").Columns(); len(cols) != 1 || cols[0] != "exists" {
+		t.Errorf("expected the override columns, got %v", cols)
+	}
+	if cols := config.newRows("SELECT id, name FROM users").Columns(); len(cols) != 2 {
+		t.Errorf("expected the default columns, got %v", cols)
+	}
+
+	config.Reset()
+	if cols := config.newRows("SELECT EXISTS (SELECT 1)").Columns(); len(cols) != 0 {
+		t.Errorf("Reset did not clear overrides, got columns %v", cols)
+	}
+}
+
 func TestNamedValuesToAny(t *testing.T) {
 	nvs := []driver.NamedValue{
 		{Ordinal: 1, Value: "string"},
@@ -1,7 +1,12 @@
 // Package shared contains canonical type definitions shared across grub.
 package shared //nolint:revive // internal shared package is intentional
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
 
 // Semantic errors for storage operations.
 var (
@@ -17,10 +22,28 @@ var (
 	// ErrConstraint indicates a constraint violation (foreign key, check, etc.).
 	ErrConstraint = errors.New("grub: constraint violation")
 
+	// ErrUniqueViolation indicates a unique or primary key constraint was violated.
+	ErrUniqueViolation = errors.New("grub: unique constraint violation")
+
+	// ErrForeignKeyViolation indicates a foreign key constraint was violated.
+	ErrForeignKeyViolation = errors.New("grub: foreign key constraint violation")
+
+	// ErrCheckViolation indicates a check constraint was violated.
+	ErrCheckViolation = errors.New("grub: check constraint violation")
+
+	// ErrNotNullViolation indicates a not-null constraint was violated.
+	ErrNotNullViolation = errors.New("grub: not-null constraint violation")
+
+	// ErrSerializationFailure indicates the database aborted a transaction
+	// because it could not be serialized with concurrent transactions, or
+	// chose it as a deadlock victim. Retrying the whole transaction may succeed.
+	ErrSerializationFailure = errors.New("grub: serialization failure")
+
 	// ErrInvalidKey indicates the provided key is malformed or empty.
 	ErrInvalidKey = errors.New("grub: invalid key")
 
-	// ErrReadOnly indicates a write was attempted on a read-only connection.
+	// ErrReadOnly indicates a write was attempted on a read-only connection
+	// or through a ReadOnly view.
 	ErrReadOnly = errors.New("grub: read-only")
 
 	// ErrTableExists indicates a table with the same name is already registered.
@@ -29,6 +52,21 @@ var (
 	// ErrTableNotFound indicates the table is not registered.
 	ErrTableNotFound = errors.New("grub: table not registered")
 
+	// ErrStatementNotFound indicates no statement is registered under the given name.
+	ErrStatementNotFound = errors.New("grub: statement not registered")
+
+	// ErrInvalidParams indicates statement params do not match the statement's declared params.
+	ErrInvalidParams = errors.New("grub: invalid statement params")
+
+	// ErrKeyNotGenerated indicates an insert left the primary key unset because the table does not generate it.
+	ErrKeyNotGenerated = errors.New("grub: primary key not generated")
+
+	// ErrRedacted indicates a save through a redacting facade would persist a redacted field as zero.
+	ErrRedacted = errors.New("grub: redacted field would be persisted as zero")
+
+	// ErrNilTransaction indicates a nil transaction was passed to a Tx method.
+	ErrNilTransaction = errors.New("grub: nil transaction")
+
 	// ErrTTLNotSupported indicates the provider does not support TTL.
 	ErrTTLNotSupported = errors.New("grub: TTL not supported by provider")
 
@@ -50,9 +88,215 @@ var (
 	// ErrFilterNotSupported indicates the provider does not support metadata-only filtering.
 	ErrFilterNotSupported = errors.New("grub: filter not supported by provider")
 
+	// ErrScanLimitExceeded indicates a filter would scan or return more vectors than allowed.
+	ErrScanLimitExceeded = errors.New("grub: scan limit exceeded")
+
+	// ErrSchemaMismatch indicates vector metadata does not match the schema derived from its type.
+	ErrSchemaMismatch = errors.New("grub: metadata schema mismatch")
+
+	// ErrNoMetadata indicates a vector was stored without metadata.
+	ErrNoMetadata = errors.New("grub: vector has no metadata")
+
+	// ErrUnsupported indicates the provider does not implement an optional capability.
+	ErrUnsupported = errors.New("grub: operation not supported by provider")
+
+	// ErrDialectMismatch indicates a renderer for a different SQL dialect
+	// than the database connection's driver.
+	ErrDialectMismatch = errors.New("grub: renderer does not match database dialect")
+
+	// ErrCodecMismatch indicates a codec was given data encoded in another
+	// format.
+	ErrCodecMismatch = errors.New("grub: data is not in the codec's format")
+
 	// ErrNoPrimaryKey indicates no field has the primarykey constraint.
 	ErrNoPrimaryKey = errors.New("grub: no primary key defined in struct tags")
 
 	// ErrMultiplePrimaryKeys indicates multiple fields have the primarykey constraint.
 	ErrMultiplePrimaryKeys = errors.New("grub: multiple primary keys not supported")
 )
+
+// Error kinds identify the facade that produced an Error.
+const (
+	KindDatabase = "database"
+	KindStore    = "store"
+	KindBucket   = "bucket"
+	KindIndex    = "index"
+)
+
+// Error carries backend operation context for a failed storage operation.
+// It wraps the underlying error so errors.Is matching against the semantic
+// errors (ErrNotFound, etc.) continues to work.
+type Error struct {
+	// Op is the operation that failed (e.g. "get", "set_batch").
+	Op string
+
+	// Kind is the facade that produced the error (database, store, bucket, index).
+	Kind string
+
+	// Key is the record key or vector ID involved, if any.
+	Key string
+
+	// Table is the table, collection, or bucket name, if known.
+	Table string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error formats the error with its operation context.
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("grub: ")
+	b.WriteString(e.Kind)
+	b.WriteByte(' ')
+	b.WriteString(e.Op)
+	if e.Table != "" {
+		b.WriteByte(' ')
+		b.WriteString(e.Table)
+	}
+	if e.Key != "" {
+		b.WriteString(" key=")
+		b.WriteString(strconv.Quote(e.Key))
+	}
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WrapError annotates err with operation context.
+// Returns nil if err is nil. Errors that already carry context are returned unchanged.
+func WrapError(kind, op, table, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var existing *Error
+	if errors.As(err, &existing) {
+		return err
+	}
+	return &Error{Op: op, Kind: kind, Key: key, Table: table, Err: err}
+}
+
+// ConstraintError describes a database constraint violation.
+// It matches its violation sentinel (ErrUniqueViolation, etc.) and ErrConstraint
+// via errors.Is; unique violations also match ErrDuplicate. The original driver
+// error remains reachable through Unwrap.
+type ConstraintError struct {
+	// Violation is the sentinel identifying the kind of violation.
+	Violation error
+
+	// Constraint is the violated constraint name, if the driver reports it.
+	Constraint string
+
+	// Column is the offending column, if the driver reports it.
+	Column string
+
+	// Err is the original driver error.
+	Err error
+}
+
+// Error formats the violation followed by the driver message.
+func (e *ConstraintError) Error() string {
+	return e.Violation.Error() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the original driver error.
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the violation sentinel or one of its broader categories.
+func (e *ConstraintError) Is(target error) bool {
+	switch target {
+	case e.Violation, ErrConstraint:
+		return true
+	case ErrDuplicate:
+		return errors.Is(e.Violation, ErrUniqueViolation)
+	}
+	return false
+}
+
+// BatchQueryError identifies the query that failed within a batch search.
+// The whole batch fails with the first failing query.
+type BatchQueryError struct {
+	// Index is the position of the failing query in the batch.
+	Index int
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error formats the query position followed by the underlying error.
+func (e *BatchQueryError) Error() string {
+	return "query " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchQueryError) Unwrap() error {
+	return e.Err
+}
+
+// BatchChunkError identifies the chunk that failed when a batch write was
+// split to fit a provider's batch limit. Chunks run in order, so every item
+// before Start was written and none from Start+Size on was attempted.
+type BatchChunkError struct {
+	// Chunk is the zero-based position of the failing chunk.
+	Chunk int
+
+	// Start is the position of the chunk's first item in the batch.
+	Start int
+
+	// Size is the number of items in the chunk.
+	Size int
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error formats the chunk and its item range followed by the underlying
+// error.
+func (e *BatchChunkError) Error() string {
+	return "chunk " + strconv.Itoa(e.Chunk) + " (items " + strconv.Itoa(e.Start) + "-" +
+		strconv.Itoa(e.Start+e.Size-1) + "): " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchChunkError) Unwrap() error {
+	return e.Err
+}
+
+// KeyError describes a key rejected by a key policy. It matches
+// ErrInvalidKey via errors.Is.
+type KeyError struct {
+	// Key is the offending key or prefix, before normalization.
+	Key string
+
+	// Rule describes the policy rule the key violated.
+	Rule string
+}
+
+// Error formats the key followed by the violated rule.
+func (e *KeyError) Error() string {
+	return ErrInvalidKey.Error() + " " + strconv.Quote(e.Key) + ": " + e.Rule
+}
+
+// Unwrap returns ErrInvalidKey.
+func (e *KeyError) Unwrap() error {
+	return ErrInvalidKey
+}
+
+// CtxErr returns ctx's error when ctx is done, so a call cut short by
+// cancellation reports context.Canceled or context.DeadlineExceeded rather
+// than the transport's or driver's own error; otherwise it returns err.
+func CtxErr(ctx context.Context, err error) error {
+	if cerr := ctx.Err(); cerr != nil {
+		return cerr
+	}
+	return err
+}
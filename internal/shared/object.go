@@ -1,16 +1,48 @@
 // Package shared provides canonical type definitions used across grub modules.
 package shared //nolint:revive // internal shared package is intentional
 
-import "github.com/zoobzio/atom"
+import (
+	"time"
+
+	"github.com/zoobzio/atom"
+)
 
 // ObjectInfo holds provider-level metadata for blob storage.
 // Used by BucketProvider implementations.
+// VersionID and LastModified are empty when the provider does not report them.
+// ExpiresAt, when set on a Put, asks a provider implementing BucketExpirer to
+// delete the object at that time.
 type ObjectInfo struct {
-	Key         string
-	ContentType string
-	Size        int64
-	ETag        string
-	Metadata    map[string]string
+	Key          string
+	ContentType  string
+	Size         int64
+	ETag         string
+	Metadata     map[string]string
+	VersionID    string
+	LastModified time.Time
+	ExpiresAt    time.Time
+}
+
+// ObjectVersion describes one stored version of a blob.
+// Used by Versioner implementations.
+type ObjectVersion struct {
+	Key          string
+	VersionID    string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	// IsLatest reports whether this is the current version of the key.
+	IsLatest bool
+	// IsDeleteMarker reports whether this version records a deletion and
+	// carries no data.
+	IsDeleteMarker bool
+}
+
+// CompletedPart identifies an uploaded part of a multipart upload.
+// Used by MultipartUploader implementations.
+type CompletedPart struct {
+	Number int
+	ETag   string
 }
 
 // AtomicObject holds blob metadata with an atomized payload.
@@ -21,5 +53,6 @@ type AtomicObject struct {
 	Size        int64
 	ETag        string
 	Metadata    map[string]string
+	ExpiresAt   time.Time
 	Data        *atom.Atom
 }
@@ -0,0 +1,16 @@
+package shared
+
+import (
+	"reflect"
+	"strings"
+)
+
+// HasGrubTag reports whether f's grub tag lists option, e.g. grub:"redact".
+func HasGrubTag(f reflect.StructField, option string) bool {
+	for _, v := range strings.Split(f.Tag.Get("grub"), ",") {
+		if strings.TrimSpace(v) == option {
+			return true
+		}
+	}
+	return false
+}
@@ -35,6 +35,26 @@ const (
 	DistanceInnerProduct DistanceMetric = "inner_product"
 )
 
+// ScoreMeasure defines how a search score relates to closeness.
+type ScoreMeasure string
+
+const (
+	// ScoreDistance means lower scores are closer.
+	ScoreDistance ScoreMeasure = "distance"
+
+	// ScoreSquaredDistance means scores are squared L2 distances; lower is closer.
+	ScoreSquaredDistance ScoreMeasure = "squared_distance"
+
+	// ScoreSimilarity means higher scores are closer.
+	ScoreSimilarity ScoreMeasure = "similarity"
+)
+
+// ScoreKind describes what VectorResult.Score measures.
+type ScoreKind struct {
+	Metric  DistanceMetric
+	Measure ScoreMeasure
+}
+
 // VectorRecord represents a vector for batch operations.
 type VectorRecord struct {
 	ID       uuid.UUID
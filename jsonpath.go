@@ -0,0 +1,226 @@
+package grub
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/soy"
+)
+
+// jsonParamPrefix starts the param names WhereJSON and JSONCondition give
+// their conditions.
+const jsonParamPrefix = "grub_json_"
+
+// jsonPathPattern matches the JSON paths WhereJSON accepts: $ followed by
+// one or more .key or [index] steps. Paths are inlined into the SQL, so
+// nothing that could close the literal gets through.
+var jsonPathPattern = regexp.MustCompile(`^\$(\.[A-Za-z_][A-Za-z0-9_]*|\[[0-9]+\])+$`)
+
+// jsonStepPattern splits a JSON path into its steps.
+var jsonStepPattern = regexp.MustCompile(`\.([A-Za-z_][A-Za-z0-9_]*)|\[([0-9]+)\]`)
+
+// jsonCondition is what a JSON condition's param stands for: the path to
+// compare inside the condition's column, and the param the caller binds.
+type jsonCondition struct {
+	path  string
+	param string
+}
+
+// WhereJSON returns a condition comparing the value at path inside the
+// JSON column field, rather than the column itself, against param:
+//
+//	byTheme := edamame.NewQueryStatement("by-theme", "", edamame.QuerySpec{
+//	    Where: []edamame.ConditionSpec{grub.WhereJSON("settings", "$.theme", "=", "theme")},
+//	})
+//
+// binds :theme against settings->>'theme' on PostgreSQL,
+// json_extract("settings", '$.theme') on SQLite,
+// JSON_UNQUOTE(JSON_EXTRACT(`settings`, '$.theme')) on MariaDB and MySQL,
+// and JSON_VALUE([settings], '$.theme') on SQL Server. path is $ followed
+// by .key and [index] steps, such as $.address.city or $.tags[0]; any
+// other path fails when the query renders. PostgreSQL, MariaDB, and SQL
+// Server extract the value as text, so compare numbers and booleans with a
+// param of matching text or cast in a registered statement. operator is a
+// comparison or LIKE operator, not IN or BETWEEN.
+//
+// Reading and writing the column itself needs no condition: declare the
+// field as JSON[V] to store V encoded as JSON.
+func WhereJSON(field, path, operator, param string) edamame.ConditionSpec {
+	return edamame.ConditionSpec{Field: field, Operator: operator, Param: jsonParam(path, param)}
+}
+
+// JSONCondition is WhereJSON for builders, for use with WhereAnd and
+// WhereOr:
+//
+//	db.Query().WhereAnd(grub.JSONCondition("settings", "$.theme", "=", "theme"))
+func JSONCondition(field, path, operator, param string) soy.Condition {
+	return soy.C(field, operator, jsonParam(path, param))
+}
+
+// jsonParam returns the param name a JSON condition carries its path and
+// param in. Only field, operator, and param names reach the renderer, and
+// params are plain identifiers, so the path is hex encoded:
+// grub_json_<hex path>_<param>.
+func jsonParam(path, param string) string {
+	return jsonParamPrefix + hex.EncodeToString([]byte(path)) + "_" + param
+}
+
+// parseJSONParam returns the condition behind a name jsonParam returned.
+func parseJSONParam(name string) (jsonCondition, bool) {
+	rest, ok := strings.CutPrefix(name, jsonParamPrefix)
+	if !ok {
+		return jsonCondition{}, false
+	}
+	encoded, param, ok := strings.Cut(rest, "_")
+	if !ok || param == "" {
+		return jsonCondition{}, false
+	}
+	path, err := hex.DecodeString(encoded)
+	if err != nil {
+		return jsonCondition{}, false
+	}
+	return jsonCondition{path: string(path), param: param}, true
+}
+
+// jsonColumn records a condition whose column markJSON replaced with a
+// placeholder, to be swapped for the extraction once the AST renders.
+type jsonColumn struct {
+	placeholder string
+	table       string
+	column      string
+	path        string
+}
+
+// markJSON returns item with each JSON condition comparing a placeholder
+// column to the caller's param instead, appending what it replaced to
+// marked. Items that hold no such condition are
+// returned unchanged.
+func markJSON(item astql.ConditionItem, marked *[]jsonColumn) (astql.ConditionItem, error) {
+	if cond, ok := itemAs(item, conditionZero); ok {
+		json, ok := parseJSONParam(cond.Value.Name)
+		if !ok {
+			return item, nil
+		}
+		if cond.Operator == astql.IN || cond.Operator == astql.NotIn {
+			return nil, errors.New("JSON conditions support comparison and LIKE operators only")
+		}
+		placeholder := jsonParamPrefix + "col_" + strconv.Itoa(len(*marked))
+		*marked = append(*marked, jsonColumn{
+			placeholder: placeholder,
+			table:       cond.Field.Table,
+			column:      cond.Field.Name,
+			path:        json.path,
+		})
+		cond.Field.Name = placeholder
+		cond.Field.Table = ""
+		cond.Value.Name = json.param
+		return cond, nil
+	}
+	if group, ok := itemAs(item, conditionGroupZero); ok {
+		before := len(*marked)
+		conditions := make([]astql.ConditionItem, len(group.Conditions))
+		for i, child := range group.Conditions {
+			var err error
+			if conditions[i], err = markJSON(child, marked); err != nil {
+				return nil, err
+			}
+		}
+		if len(*marked) == before {
+			return item, nil
+		}
+		group.Conditions = conditions
+		return group, nil
+	}
+	return item, nil
+}
+
+// renderJSON swaps each placeholder column in sql for the extraction of
+// its path from the real column in dialect. A placeholder the renderer did
+// not quote as expected fails rather than leaving the column in place.
+func renderJSON(sql string, marked []jsonColumn, dialect string) (string, error) {
+	for _, col := range marked {
+		field, err := quoteColumn(dialect, col.column)
+		if err != nil {
+			return "", err
+		}
+		if col.table != "" {
+			field = col.table + "." + field
+		}
+		expr, err := jsonExtract(dialect, field, col.path)
+		if err != nil {
+			return "", err
+		}
+		placeholder, _ := quoteColumn(dialect, col.placeholder)
+		if !strings.Contains(sql, placeholder) {
+			return "", fmt.Errorf("JSON path column %s not found in rendered query", placeholder)
+		}
+		sql = strings.Replace(sql, placeholder, expr, 1)
+	}
+	return sql, nil
+}
+
+// quoteColumn quotes a column name as dialect's renderer does.
+func quoteColumn(dialect, name string) (string, error) {
+	switch dialect {
+	case "postgres", "sqlite":
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`, nil
+	case "mariadb":
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`", nil
+	case "mssql":
+		return "[" + strings.ReplaceAll(name, "]", "]]") + "]", nil
+	}
+	return "", errors.New("JSON paths are not supported by this renderer")
+}
+
+// jsonExtract returns the SQL extracting path from the quoted column field
+// in dialect.
+func jsonExtract(dialect, field, path string) (string, error) {
+	if !jsonPathPattern.MatchString(path) {
+		return "", fmt.Errorf("invalid JSON path %q: want $ followed by .key or [index] steps", path)
+	}
+	switch dialect {
+	case "postgres":
+		var steps []string
+		for _, step := range jsonStepPattern.FindAllStringSubmatch(path, -1) {
+			steps = append(steps, step[1]+step[2])
+		}
+		if len(steps) == 1 && !strings.HasPrefix(path, "$[") {
+			return fmt.Sprintf("(%s->>'%s')", field, steps[0]), nil
+		}
+		return fmt.Sprintf("(%s #>> '{%s}')", field, strings.Join(steps, ",")), nil
+	case "sqlite":
+		return fmt.Sprintf("json_extract(%s, '%s')", field, path), nil
+	case "mariadb":
+		return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, '%s'))", field, path), nil
+	case "mssql":
+		return fmt.Sprintf("JSON_VALUE(%s, '%s')", field, path), nil
+	}
+	return "", errors.New("JSON paths are not supported by this renderer")
+}
+
+// jsonParams returns specs with each JSON condition's param renamed to the
+// param the caller binds, so statements check params by the names callers
+// use.
+func jsonParams(specs []edamame.ParamSpec) []edamame.ParamSpec {
+	var renamed []edamame.ParamSpec
+	for i, spec := range specs {
+		cond, ok := parseJSONParam(spec.Name)
+		if !ok {
+			continue
+		}
+		if renamed == nil {
+			renamed = append([]edamame.ParamSpec(nil), specs...)
+		}
+		renamed[i].Name = cond.param
+	}
+	if renamed == nil {
+		return specs
+	}
+	return renamed
+}
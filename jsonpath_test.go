@@ -0,0 +1,121 @@
+package grub
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/astql"
+	astqlmariadb "github.com/zoobzio/astql/mariadb"
+	astqlmssql "github.com/zoobzio/astql/mssql"
+	astqlpostgres "github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+	"github.com/zoobzio/soy"
+)
+
+func TestWhereJSON(t *testing.T) {
+	renderers := map[string]struct {
+		renderer    astql.Renderer
+		theme, city string
+	}{
+		"sqlite":   {testDBRenderer, `json_extract("name", '$.theme') = :theme`, `json_extract("email", '$.address.city') LIKE :city`},
+		"postgres": {astqlpostgres.New(), `("name"->>'theme') = :theme`, `("email" #>> '{address,city}') LIKE :city`},
+		"mariadb": {astqlmariadb.New(), "JSON_UNQUOTE(JSON_EXTRACT(`name`, '$.theme')) = :theme",
+			"JSON_UNQUOTE(JSON_EXTRACT(`email`, '$.address.city')) LIKE :city"},
+		"mssql": {astqlmssql.New(), `JSON_VALUE([name], '$.theme') = :theme`, `JSON_VALUE([email], '$.address.city') LIKE :city`},
+	}
+	for name, tt := range renderers {
+		t.Run(name, func(t *testing.T) {
+			mockDB, _ := mockdb.New()
+			db, err := NewDatabase[TestDBUser](mockDB, "test_users", tt.renderer)
+			if err != nil {
+				t.Fatalf("NewDatabase failed: %v", err)
+			}
+			result, err := db.Query().
+				WhereAnd(JSONCondition("name", "$.theme", "=", "theme")).
+				WhereOr(soy.C("age", ">", "min_age"), JSONCondition("email", "$.address.city", "LIKE", "city")).
+				Render()
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			for _, want := range []string{tt.theme, tt.city, "> :min_age"} {
+				if !strings.Contains(result.SQL, want) {
+					t.Errorf("expected %q in query, got: %s", want, result.SQL)
+				}
+			}
+			if want := []string{"theme", "min_age", "city"}; !reflect.DeepEqual(result.RequiredParams, want) {
+				t.Errorf("expected params %v, got %v", want, result.RequiredParams)
+			}
+		})
+	}
+
+	t.Run("statements", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		stmt := edamame.NewQueryStatement("by-tag", "", edamame.QuerySpec{
+			Where: []edamame.ConditionSpec{WhereJSON("name", "$.tags[0]", "=", "tag")},
+		})
+		query, err := db.Executor().RenderQuery(stmt)
+		if err != nil {
+			t.Fatalf("RenderQuery failed: %v", err)
+		}
+		if want := `json_extract("name", '$.tags[0]') = :tag`; !strings.Contains(query, want) {
+			t.Errorf("expected %q in query, got: %s", want, query)
+		}
+		if err := checkParams(stmt, map[string]any{"tag": "admin"}, func() (string, error) { return query, nil }); err != nil {
+			t.Errorf("expected params to check against tag, got: %v", err)
+		}
+	})
+
+	t.Run("params carry the path", func(t *testing.T) {
+		cond, ok := parseJSONParam(WhereJSON("name", "$.tags[0]", "=", "first_tag").Param)
+		if !ok || cond.path != "$.tags[0]" || cond.param != "first_tag" {
+			t.Errorf("expected the path and param back, got %+v, %v", cond, ok)
+		}
+		for _, name := range []string{"theme", "grub_json_", "grub_json_zz_theme", "grub_json_2461_"} {
+			if _, ok := parseJSONParam(name); ok {
+				t.Errorf("%q: expected no JSON condition", name)
+			}
+		}
+	})
+
+	t.Run("postgres array index", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlpostgres.New())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		result, err := db.Query().WhereAnd(JSONCondition("name", "$[2]", "=", "v")).Render()
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if want := `("name" #>> '{2}') = :v`; !strings.Contains(result.SQL, want) {
+			t.Errorf("expected %q in query, got: %s", want, result.SQL)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		tests := map[string]*soy.Query[TestDBUser]{
+			"quote in path": db.Query().WhereAnd(JSONCondition("name", "$.a') OR 1=1 --", "=", "v")),
+			"bare root":     db.Query().WhereAnd(JSONCondition("name", "$", "=", "v")),
+			"wildcard":      db.Query().WhereAnd(JSONCondition("name", "$.*", "=", "v")),
+			"in":            db.Query().WhereAnd(JSONCondition("name", "$.a", "IN", "v")),
+		}
+		for name, q := range tests {
+			t.Run(name, func(t *testing.T) {
+				if result, err := q.Render(); err == nil {
+					t.Errorf("expected an error, got: %s", result.SQL)
+				}
+			})
+		}
+	})
+}
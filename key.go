@@ -0,0 +1,164 @@
+package grub
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/soy"
+)
+
+// keyFieldType returns the Go type of T's primary key field, with any
+// pointer removed.
+func keyFieldType[T any](exec *edamame.Executor[T], keyCol string) reflect.Type {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for _, field := range exec.Soy().Metadata().Fields {
+		if field.Tags["db"] != keyCol {
+			continue
+		}
+		ft := t.FieldByIndex(field.Index).Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		return ft
+	}
+	return nil
+}
+
+// keyFieldIndex returns the reflect field index of T's primary key field.
+func keyFieldIndex[T any](exec *edamame.Executor[T], keyCol string) []int {
+	for _, field := range exec.Soy().Metadata().Fields {
+		if field.Tags["db"] == keyCol {
+			return field.Index
+		}
+	}
+	return nil
+}
+
+// GetByKey retrieves the record whose primary key equals key, converting
+// key to the primary key field's type first so every spelling of a key
+// binds the same value. An integer key accepts any integer type or a
+// decimal string ("007" finds 7); a key type implementing
+// encoding.TextUnmarshaler, such as uuid.UUID, accepts its text form in
+// any case the parser does; a string key accepts a string, an integer
+// (formatted in decimal), or a fmt.Stringer. Returns ErrInvalidKey when key
+// cannot be converted and ErrNotFound when no row matches.
+func (d *Database[T]) GetByKey(ctx context.Context, key any) (*T, error) {
+	value, err := d.normalizeKey(key)
+	if err != nil {
+		return nil, d.wrapErr("get", fmt.Sprint(key), err)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	result, err := read(callCtx, d, func(c conn[T]) (*T, error) {
+		return c.executor.Soy().Select().
+			Where(d.keyCol, "=", "key").
+			Exec(callCtx, map[string]any{"key": value})
+	})
+	if err != nil {
+		if errors.Is(err, soy.ErrNotFound) {
+			err = ErrNotFound
+		}
+		return nil, d.wrapErr("get", fmt.Sprint(value), err)
+	}
+	return result, nil
+}
+
+// normalizeKey converts key to the primary key field's type.
+func (d *Database[T]) normalizeKey(key any) (any, error) {
+	if key == nil {
+		return nil, fmt.Errorf("%w: nil", ErrInvalidKey)
+	}
+	kt := d.keyType
+	v := reflect.ValueOf(key)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, fmt.Errorf("%w: nil", ErrInvalidKey)
+		}
+		v = v.Elem()
+	}
+	if kt == nil || v.Type() == kt {
+		return v.Interface(), nil
+	}
+
+	if s, ok := v.Interface().(string); ok {
+		if u, ok := reflect.New(kt).Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(s)); err != nil {
+				return nil, fmt.Errorf("%w: %q is not a valid %s: %v", ErrInvalidKey, s, kt, err)
+			}
+			return reflect.ValueOf(u).Elem().Interface(), nil
+		}
+	}
+
+	out := reflect.New(kt).Elem()
+	switch kt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if out.OverflowInt(v.Int()) {
+				break
+			}
+			out.SetInt(v.Int())
+			return out.Interface(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if u := v.Uint(); u > 1<<63-1 || out.OverflowInt(int64(u)) {
+				break
+			}
+			out.SetInt(int64(v.Uint()))
+			return out.Interface(), nil
+		case reflect.String:
+			n, err := strconv.ParseInt(v.String(), 10, kt.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q is not a valid %s", ErrInvalidKey, v.String(), kt)
+			}
+			out.SetInt(n)
+			return out.Interface(), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if n := v.Int(); n < 0 || out.OverflowUint(uint64(n)) {
+				break
+			}
+			out.SetUint(uint64(v.Int()))
+			return out.Interface(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if out.OverflowUint(v.Uint()) {
+				break
+			}
+			out.SetUint(v.Uint())
+			return out.Interface(), nil
+		case reflect.String:
+			n, err := strconv.ParseUint(v.String(), 10, kt.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q is not a valid %s", ErrInvalidKey, v.String(), kt)
+			}
+			out.SetUint(n)
+			return out.Interface(), nil
+		}
+	case reflect.String:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			out.SetString(strconv.FormatInt(v.Int(), 10))
+			return out.Interface(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			out.SetString(strconv.FormatUint(v.Uint(), 10))
+			return out.Interface(), nil
+		case reflect.String:
+			out.SetString(v.String())
+			return out.Interface(), nil
+		}
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			out.SetString(s.String())
+			return out.Interface(), nil
+		}
+	}
+	if v.Type().ConvertibleTo(kt) && v.Kind() == kt.Kind() {
+		return v.Convert(kt).Interface(), nil
+	}
+	return nil, fmt.Errorf("%w: cannot use %v (%T) as %s", ErrInvalidKey, key, key, kt)
+}
@@ -0,0 +1,120 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// stringKeyDBUser is a Database-compatible model with a string primary key.
+type stringKeyDBUser struct {
+	Code string `db:"code" constraints:"primarykey"`
+	Name string `db:"name"`
+}
+
+type userID int64
+
+func TestDatabase_GetByKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("integer key", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		for _, key := range []any{7, "007", int8(7), uint64(7), userID(7)} {
+			capture.Reset()
+			if _, err := db.GetByKey(ctx, key); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("GetByKey(%#v): expected ErrNotFound, got %v", key, err)
+			}
+			sent, _ := capture.Last()
+			if len(sent.Args) != 1 || fmt.Sprint(sent.Args[0]) != "7" {
+				t.Errorf("GetByKey(%#v) bound %v, want 7", key, sent.Args)
+			}
+		}
+	})
+
+	t.Run("string key", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[stringKeyDBUser](mockDB, "codes", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		for key, want := range map[any]string{7: "7", uint(7): "7", "007": "007", id: id.String()} {
+			capture.Reset()
+			if _, err := db.GetByKey(ctx, key); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("GetByKey(%#v): expected ErrNotFound, got %v", key, err)
+			}
+			sent, _ := capture.Last()
+			if len(sent.Args) != 1 || sent.Args[0] != want {
+				t.Errorf("GetByKey(%#v) bound %v, want %q", key, sent.Args, want)
+			}
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		var nilPtr *int
+		for _, key := range []any{nil, nilPtr, "seven", "7.0", 3.5, uint64(1) << 63} {
+			_, err := db.GetByKey(ctx, key)
+			if !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("GetByKey(%#v): expected ErrInvalidKey, got %v", key, err)
+			}
+			var gerr *Error
+			if !errors.As(err, &gerr) || gerr.Op != "get" {
+				t.Errorf("GetByKey(%#v): expected wrapped get error, got %v", key, err)
+			}
+		}
+		if _, ok := capture.Last(); ok {
+			t.Error("an invalid key reached the database")
+		}
+	})
+
+	t.Run("found", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		cfg.SetRows([]string{"id", "email", "name", "age"},
+			[]driver.Value{int64(1), "a@example.com", "A", int64(30)},
+		)
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		user, err := db.GetByKey(ctx, "1")
+		if err != nil {
+			t.Fatalf("GetByKey failed: %v", err)
+		}
+		if user.ID != 1 || user.Email != "a@example.com" {
+			t.Errorf("unexpected record: %+v", user)
+		}
+	})
+}
+
+func TestDatabase_normalizeKey_TextUnmarshaler(t *testing.T) {
+	d := &Database[TestDBUser]{keyType: reflect.TypeOf(uuid.UUID{})}
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	for _, key := range []any{id, &id, id.String(), "6BA7B810-9DAD-11D1-80B4-00C04FD430C8"} {
+		got, err := d.normalizeKey(key)
+		if err != nil {
+			t.Fatalf("normalizeKey(%#v) failed: %v", key, err)
+		}
+		if got != id {
+			t.Errorf("normalizeKey(%#v) = %v, want %v", key, got, id)
+		}
+	}
+	if _, err := d.normalizeKey("not-a-uuid"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package grub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/vecna"
+)
+
+// KeyField is the metadata field in which a KeyedIndex stores each vector's
+// original string key. Metadata types used with a KeyedIndex must not encode
+// a field of the same name.
+const KeyField = "grub_key"
+
+// DeterministicID derives the vector ID for key within namespace as a
+// UUIDv5 (SHA-1 name-based UUID). The same namespace and key always yield
+// the same ID, so re-ingesting a document overwrites its vectors instead of
+// duplicating them. Distinct keys colliding is as unlikely as a SHA-1
+// collision.
+//
+//	chunks := uuid.MustParse("6f1c0b5e-3d0a-4c8e-9d3b-1a2b3c4d5e6f")
+//	id := grub.DeterministicID(chunks, "doc:123:chunk:4")
+func DeterministicID(namespace uuid.UUID, key string) uuid.UUID {
+	return uuid.NewSHA1(namespace, []byte(key))
+}
+
+// KeyedVector is a search or lookup result from a KeyedIndex, carrying the
+// original string key alongside the vector.
+type KeyedVector[T any] struct {
+	Key      string
+	ID       uuid.UUID
+	Vector   []float32
+	Score    float32
+	Metadata T
+}
+
+// KeyedIndex is an Index addressed by string keys instead of UUIDs. Each key
+// maps to DeterministicID(namespace, key), and the key itself is stored in
+// the vector's metadata under KeyField so results can surface it. Metadata
+// is encoded as JSON.
+type KeyedIndex[T any] struct {
+	index     *Index[T]
+	namespace uuid.UUID
+}
+
+// NewKeyedIndex creates a KeyedIndex for metadata type T backed by the given
+// provider, deriving vector IDs within namespace. Options are those of
+// NewIndex. Providers that return only configured metadata fields (such as
+// Weaviate's Properties) must include KeyField for results to carry keys.
+func NewKeyedIndex[T any](provider VectorProvider, namespace uuid.UUID, opts ...Option) *KeyedIndex[T] {
+	return &KeyedIndex[T]{
+		index:     NewIndex[T](&keyedProvider{VectorProvider: provider}, opts...),
+		namespace: namespace,
+	}
+}
+
+// ID returns the vector ID key maps to.
+func (k *KeyedIndex[T]) ID(key string) uuid.UUID {
+	return DeterministicID(k.namespace, key)
+}
+
+// Index returns the underlying Index for operations addressed by ID.
+// Vectors upserted through it carry no key.
+func (k *KeyedIndex[T]) Index() *Index[T] {
+	return k.index
+}
+
+// Upsert stores or updates the vector at key.
+// Returns ErrInvalidKey if key is empty.
+func (k *KeyedIndex[T]) Upsert(ctx context.Context, key string, vector []float32, metadata *T) error {
+	if key == "" {
+		return k.index.wrapErr("upsert", "", fmt.Errorf("%w: empty", ErrInvalidKey))
+	}
+	id := k.ID(key)
+	call := &keyedCall{keys: map[uuid.UUID]string{id: key}}
+	return k.index.Upsert(withKeyedCall(ctx, call), id, vector, metadata)
+}
+
+// Get retrieves the vector at key.
+// Returns ErrNotFound if key does not exist, and ErrConflict if the vector
+// at key's ID was stored under a different key.
+func (k *KeyedIndex[T]) Get(ctx context.Context, key string) (*KeyedVector[T], error) {
+	id := k.ID(key)
+	call := &keyedCall{keys: map[uuid.UUID]string{}}
+	v, err := k.index.Get(withKeyedCall(ctx, call), id)
+	if err != nil {
+		return nil, err
+	}
+	if stored := call.keys[id]; stored != "" && stored != key {
+		return nil, k.index.wrapErr("get", key, fmt.Errorf("%w: ID %s holds key %q", ErrConflict, id, stored))
+	}
+	return &KeyedVector[T]{Key: key, ID: v.ID, Vector: v.Vector, Score: v.Score, Metadata: v.Metadata}, nil
+}
+
+// Delete removes the vector at key.
+// Returns ErrNotFound if key does not exist.
+func (k *KeyedIndex[T]) Delete(ctx context.Context, key string) error {
+	return k.index.Delete(ctx, k.ID(key))
+}
+
+// Search performs similarity search and returns the k nearest neighbors
+// with their keys. filter is optional metadata filtering (nil means no
+// filter). A result stored without a key has an empty Key.
+func (k *KeyedIndex[T]) Search(ctx context.Context, vector []float32, n int, filter *T) ([]*KeyedVector[T], error) {
+	call := &keyedCall{keys: map[uuid.UUID]string{}}
+	results, err := k.index.Search(withKeyedCall(ctx, call), vector, n, filter)
+	if err != nil {
+		return nil, err
+	}
+	return attachKeys(call, results), nil
+}
+
+// Query performs similarity search with vecna filter support, returning
+// results with their keys.
+func (k *KeyedIndex[T]) Query(ctx context.Context, vector []float32, n int, filter *vecna.Filter) ([]*KeyedVector[T], error) {
+	call := &keyedCall{keys: map[uuid.UUID]string{}}
+	results, err := k.index.Query(withKeyedCall(ctx, call), vector, n, filter)
+	if err != nil {
+		return nil, err
+	}
+	return attachKeys(call, results), nil
+}
+
+// keyedCallKey is the context key carrying a KeyedIndex call's keys to its
+// keyedProvider.
+type keyedCallKey struct{}
+
+// keyedCall holds the keys a KeyedIndex call writes, or collects the keys
+// read back from metadata.
+type keyedCall struct {
+	keys map[uuid.UUID]string
+}
+
+func withKeyedCall(ctx context.Context, call *keyedCall) context.Context {
+	return context.WithValue(ctx, keyedCallKey{}, call)
+}
+
+func keyedCallFrom(ctx context.Context) *keyedCall {
+	call, _ := ctx.Value(keyedCallKey{}).(*keyedCall)
+	return call
+}
+
+// attachKeys pairs typed results with the keys collected while reading them.
+func attachKeys[T any](call *keyedCall, results []*Vector[T]) []*KeyedVector[T] {
+	out := make([]*KeyedVector[T], len(results))
+	for n, v := range results {
+		out[n] = &KeyedVector[T]{Key: call.keys[v.ID], ID: v.ID, Vector: v.Vector, Score: v.Score, Metadata: v.Metadata}
+	}
+	return out
+}
+
+// keyedProvider stores and recovers KeyField in metadata for a KeyedIndex,
+// passing every other operation through.
+type keyedProvider struct {
+	VectorProvider
+}
+
+func (p *keyedProvider) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata []byte) error {
+	metadata, err := injectKey(ctx, id, metadata)
+	if err != nil {
+		return err
+	}
+	return p.VectorProvider.Upsert(ctx, id, vector, metadata)
+}
+
+func (p *keyedProvider) UpsertBatch(ctx context.Context, vectors []VectorRecord) error {
+	records := make([]VectorRecord, len(vectors))
+	for n, v := range vectors {
+		metadata, err := injectKey(ctx, v.ID, v.Metadata)
+		if err != nil {
+			return err
+		}
+		records[n] = VectorRecord{ID: v.ID, Vector: v.Vector, Metadata: metadata}
+	}
+	return p.VectorProvider.UpsertBatch(ctx, records)
+}
+
+func (p *keyedProvider) Get(ctx context.Context, id uuid.UUID) ([]float32, *VectorInfo, error) {
+	vector, info, err := p.VectorProvider.Get(ctx, id)
+	if err == nil {
+		collectKey(ctx, id, info.Metadata)
+	}
+	return vector, info, err
+}
+
+func (p *keyedProvider) Search(ctx context.Context, vector []float32, k int, filter map[string]any) ([]VectorResult, error) {
+	results, err := p.VectorProvider.Search(ctx, vector, k, filter)
+	for _, r := range results {
+		collectKey(ctx, r.ID, r.Metadata)
+	}
+	return results, err
+}
+
+func (p *keyedProvider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]VectorResult, error) {
+	results, err := p.VectorProvider.Query(ctx, vector, k, filter)
+	for _, r := range results {
+		collectKey(ctx, r.ID, r.Metadata)
+	}
+	return results, err
+}
+
+// injectKey adds the call's key for id to the JSON metadata object.
+func injectKey(ctx context.Context, id uuid.UUID, metadata []byte) ([]byte, error) {
+	call := keyedCallFrom(ctx)
+	if call == nil || call.keys[id] == "" {
+		return metadata, nil
+	}
+	fields := map[string]json.RawMessage{}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &fields); err != nil {
+			return nil, fmt.Errorf("grub: keyed metadata must encode as a JSON object: %w", err)
+		}
+	}
+	key, err := json.Marshal(call.keys[id])
+	if err != nil {
+		return nil, err
+	}
+	fields[KeyField] = key
+	return json.Marshal(fields)
+}
+
+// collectKey records the key stored in metadata for id, if any.
+func collectKey(ctx context.Context, id uuid.UUID, metadata []byte) {
+	call := keyedCallFrom(ctx)
+	if call == nil || len(metadata) == 0 {
+		return
+	}
+	var stored struct {
+		Key string `json:"grub_key"`
+	}
+	if json.Unmarshal(metadata, &stored) == nil && stored.Key != "" {
+		call.keys[id] = stored.Key
+	}
+}
@@ -0,0 +1,138 @@
+package grub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var testNamespace = uuid.MustParse("6f1c0b5e-3d0a-4c8e-9d3b-1a2b3c4d5e6f")
+
+func TestDeterministicID(t *testing.T) {
+	a := DeterministicID(testNamespace, "doc:123:chunk:4")
+	if a != DeterministicID(testNamespace, "doc:123:chunk:4") {
+		t.Error("expected the same key to derive the same ID")
+	}
+	if a.Version() != 5 {
+		t.Errorf("expected a version 5 UUID, got version %d", a.Version())
+	}
+	if a == DeterministicID(testNamespace, "doc:123:chunk:5") {
+		t.Error("expected different keys to derive different IDs")
+	}
+	if a == DeterministicID(uuid.New(), "doc:123:chunk:4") {
+		t.Error("expected different namespaces to derive different IDs")
+	}
+}
+
+func TestKeyedIndex(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("re-ingesting a key overwrites", func(t *testing.T) {
+		provider := newMockVectorProvider()
+		index := NewKeyedIndex[testMetadata](provider, testNamespace)
+
+		if err := index.Upsert(ctx, "doc:1", []float32{1, 0}, &testMetadata{Category: "a", Score: 1}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		if err := index.Upsert(ctx, "doc:1", []float32{0, 1}, &testMetadata{Category: "a", Score: 2}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		if len(provider.vectors) != 1 {
+			t.Fatalf("expected 1 stored vector, got %d", len(provider.vectors))
+		}
+		entry, ok := provider.vectors[index.ID("doc:1")]
+		if !ok {
+			t.Fatal("expected the vector stored at the deterministic ID")
+		}
+		var stored map[string]any
+		if err := json.Unmarshal(entry.metadata, &stored); err != nil {
+			t.Fatalf("stored metadata is not JSON: %v", err)
+		}
+		if stored[KeyField] != "doc:1" || stored["score"] != float64(2) {
+			t.Errorf("unexpected stored metadata: %v", stored)
+		}
+
+		got, err := index.Get(ctx, "doc:1")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if got.Key != "doc:1" || got.Metadata.Score != 2 || got.Vector[1] != 1 {
+			t.Errorf("unexpected vector: %+v", got)
+		}
+	})
+
+	t.Run("results expose keys", func(t *testing.T) {
+		provider := newMockVectorProvider()
+		index := NewKeyedIndex[testMetadata](provider, testNamespace)
+		for key, vec := range map[string][]float32{"near": {1, 0}, "far": {0, 1}} {
+			if err := index.Upsert(ctx, key, vec, &testMetadata{Category: "a"}); err != nil {
+				t.Fatalf("Upsert failed: %v", err)
+			}
+		}
+		if err := index.Upsert(ctx, "bare", []float32{0.5, 0.5}, nil); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		if err := index.Index().Upsert(ctx, uuid.New(), []float32{-1, 0}, &testMetadata{}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+
+		results, err := index.Search(ctx, []float32{1, 0}, 4, nil)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		keys := make([]string, len(results))
+		for n, r := range results {
+			keys[n] = r.Key
+			if r.Key != "" && r.ID != index.ID(r.Key) {
+				t.Errorf("result %q has ID %s, want %s", r.Key, r.ID, index.ID(r.Key))
+			}
+		}
+		if want := []string{"near", "bare", "far", ""}; !slices.Equal(keys, want) {
+			t.Errorf("expected keys %v, got %v", want, keys)
+		}
+
+		queried, err := index.Query(ctx, []float32{0, 1}, 1, nil)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if len(queried) != 1 || queried[0].Key != "far" {
+			t.Errorf("expected key far, got %+v", queried)
+		}
+	})
+
+	t.Run("delete and missing keys", func(t *testing.T) {
+		provider := newMockVectorProvider()
+		index := NewKeyedIndex[testMetadata](provider, testNamespace)
+		if err := index.Upsert(ctx, "doc:1", []float32{1, 0}, &testMetadata{}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		if err := index.Delete(ctx, "doc:1"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, err := index.Get(ctx, "doc:1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound after delete, got %v", err)
+		}
+		if err := index.Delete(ctx, "doc:1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if err := index.Upsert(ctx, "", []float32{1, 0}, nil); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey for an empty key, got %v", err)
+		}
+	})
+
+	t.Run("key mismatch", func(t *testing.T) {
+		provider := newMockVectorProvider()
+		index := NewKeyedIndex[testMetadata](provider, testNamespace)
+		provider.vectors[index.ID("doc:1")] = vectorEntry{
+			vector:   []float32{1, 0},
+			metadata: []byte(`{"grub_key": "doc:other"}`),
+		}
+		if _, err := index.Get(ctx, "doc:1"); !errors.Is(err, ErrConflict) {
+			t.Errorf("expected ErrConflict, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package grub
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// KeyGenerator returns a new primary key in its text form.
+// See WithKeyGenerator.
+type KeyGenerator func() string
+
+// GenerateUUIDv4 returns a random (version 4) UUID in canonical form.
+func GenerateUUIDv4() string {
+	return uuid.NewString()
+}
+
+// GenerateUUIDv7 returns a time-ordered (version 7) UUID in canonical form,
+// so keys generated later sort after earlier ones.
+func GenerateUUIDv7() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// GenerateULID returns a ULID: a 48-bit millisecond timestamp followed by
+// 80 random bits, as 26 Crockford base32 characters. ULIDs sort by the
+// millisecond they were generated in; order within one millisecond is
+// random.
+func GenerateULID() string {
+	var b [16]byte
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(b[:6], ms[2:])
+	_, _ = rand.Read(b[6:])
+
+	// 26 characters of 5 bits hold 130 bits; the 2 leading bits are zero.
+	var out [26]byte
+	for i := range out {
+		var c byte
+		for j := 0; j < 5; j++ {
+			c <<= 1
+			if bit := i*5 + j - 2; bit >= 0 {
+				c |= b[bit/8] >> (7 - bit%8) & 1
+			}
+		}
+		out[i] = crockford[c]
+	}
+	return string(out[:])
+}
+
+// checkKeyGenerator rejects WithKeyGenerator on a primary key that cannot
+// hold a generated string: it must be a string kind or implement
+// encoding.TextUnmarshaler, as uuid.UUID does.
+func checkKeyGenerator(keyType reflect.Type, keyCol string) error {
+	if keyType == nil || keyType.Kind() == reflect.String {
+		return nil
+	}
+	if reflect.PointerTo(keyType).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) {
+		return nil
+	}
+	return fmt.Errorf("%w: WithKeyGenerator needs a string or text-unmarshalable primary key, column %q is %s", ErrInvalidKey, keyCol, keyType)
+}
+
+// generateKey fills record's primary key from the KeyGenerator when it is
+// zero, reporting whether it did.
+func (d *Database[T]) generateKey(record *T) (bool, error) {
+	if d.keyGen == nil {
+		return false, nil
+	}
+	fv := reflect.ValueOf(record).Elem().FieldByIndex(d.keyIndex)
+	if !fv.IsZero() {
+		return false, nil
+	}
+	key := d.keyGen()
+	value, err := d.normalizeKey(key)
+	if err != nil {
+		return false, err
+	}
+	if fv.Kind() == reflect.Pointer {
+		fv.Set(reflect.New(fv.Type().Elem()))
+		fv = fv.Elem()
+	}
+	fv.Set(reflect.ValueOf(value).Convert(fv.Type()))
+	return true, nil
+}
+
+// Create inserts record and returns the stored row with its key. A zero
+// primary key is first filled from the WithKeyGenerator generator, before
+// BeforeSave runs; without a generator the key must already be set.
+// Create never overwrites: a key that already exists, generated or not,
+// fails with an error matching ErrDuplicate.
+func (d *Database[T]) Create(ctx context.Context, record *T) (*T, string, error) {
+	if d.readOnly {
+		return nil, "", d.wrapErr("create", "", ErrReadOnly)
+	}
+	return d.create(ctx, "create", nil, record)
+}
+
+// CreateTx is Create within a transaction.
+func (d *Database[T]) CreateTx(ctx context.Context, tx *sqlx.Tx, record *T) (*T, string, error) {
+	if d.readOnly {
+		return nil, "", d.wrapErr("create_tx", "", ErrReadOnly)
+	}
+	if tx == nil {
+		return nil, "", d.wrapErr("create_tx", "", ErrNilTransaction)
+	}
+	return d.create(ctx, "create_tx", tx, record)
+}
+
+// create implements Create and CreateTx, running outside a transaction
+// when tx is nil.
+func (d *Database[T]) create(ctx context.Context, op string, tx *sqlx.Tx, record *T) (*T, string, error) {
+	if tx == nil && d.outbox != nil {
+		var inserted *T
+		var key string
+		err := d.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			var err error
+			inserted, key, err = d.create(ctx, op, tx, record)
+			return err
+		}, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return inserted, key, nil
+	}
+	if err := d.prepareCreate(record); err != nil {
+		return nil, "", d.wrapErr(op, "", err)
+	}
+	key := d.columnValue(record, d.keyCol)
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	insert := d.executor.Soy().InsertFull()
+	var inserted *T
+	var err error
+	if tx != nil {
+		inserted, err = insert.ExecTx(callCtx, tx, record)
+	} else {
+		inserted, err = insert.Exec(callCtx, record)
+	}
+	d.invalidateCache(ctx, tx)
+	if err == nil {
+		err = d.outbox.record(callCtx, tx, op, key, inserted)
+	}
+	if err != nil {
+		return nil, "", d.wrapErr(op, key, err)
+	}
+	if err := callAfterSave(ctx, inserted); err != nil {
+		return nil, "", err
+	}
+	return inserted, key, nil
+}
+
+// prepareCreate generates record's key if needed and rejects a record left
+// without one.
+func (d *Database[T]) prepareCreate(record *T) error {
+	if _, err := d.generateKey(record); err != nil {
+		return err
+	}
+	if reflect.ValueOf(record).Elem().FieldByIndex(d.keyIndex).IsZero() {
+		return fmt.Errorf("%w: column %q is empty and no key generator is set", ErrInvalidKey, d.keyCol)
+	}
+	return nil
+}
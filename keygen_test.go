@@ -0,0 +1,184 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// uuidKeyDBUser is a Database-compatible model with a uuid.UUID primary key
+// that records the key it held when BeforeSave ran.
+type uuidKeyDBUser struct {
+	ID         uuid.UUID `db:"id" constraints:"primarykey"`
+	Name       string    `db:"name"`
+	keyAtSave  uuid.UUID
+	savedCalls int
+}
+
+func (u *uuidKeyDBUser) BeforeSave(_ context.Context) error {
+	u.keyAtSave = u.ID
+	u.savedCalls++
+	return nil
+}
+
+func TestGenerateKeys(t *testing.T) {
+	t.Run("uuid versions", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			gen     func() string
+			version uuid.Version
+		}{
+			"v4": {GenerateUUIDv4, 4},
+			"v7": {GenerateUUIDv7, 7},
+		} {
+			id, err := uuid.Parse(tc.gen())
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if id.Version() != tc.version {
+				t.Errorf("%s: got version %d", name, id.Version())
+			}
+		}
+	})
+
+	t.Run("ulid", func(t *testing.T) {
+		before := time.Now().UnixMilli()
+		id := GenerateULID()
+		after := time.Now().UnixMilli()
+		if len(id) != 26 {
+			t.Fatalf("expected 26 characters, got %q", id)
+		}
+		var ms int64
+		for _, c := range id[:10] {
+			n := strings.IndexRune(crockford, c)
+			if n < 0 {
+				t.Fatalf("unexpected character %q in %q", c, id)
+			}
+			ms = ms<<5 | int64(n)
+		}
+		if ms < before || ms > after {
+			t.Errorf("timestamp %d outside [%d, %d]", ms, before, after)
+		}
+		if id == GenerateULID() {
+			t.Error("expected distinct ULIDs")
+		}
+	})
+
+	t.Run("ulid sorts by time", func(t *testing.T) {
+		first := GenerateULID()
+		time.Sleep(2 * time.Millisecond)
+		if second := GenerateULID(); second <= first {
+			t.Errorf("expected %q after %q", second, first)
+		}
+	})
+}
+
+func TestDatabase_KeyGenerator(t *testing.T) {
+	ctx := context.Background()
+	fixed := uuid.MustParse("0190f1f4-8e3a-7c1e-9a51-0e6f7c2b1a11")
+	newDB := func(t *testing.T, opts ...Option) (*Database[uuidKeyDBUser], *mockdb.Capture, *mockdb.Config) {
+		t.Helper()
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		t.Cleanup(cfg.Reset)
+		cfg.SetRows([]string{"id", "name"}, []driver.Value{fixed.String(), "Alice"})
+		db, err := NewDatabase[uuidKeyDBUser](mockDB, "users", testDBRenderer, opts...)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		return db, capture, cfg
+	}
+	gen := WithKeyGenerator(func() string { return fixed.String() })
+
+	t.Run("rejects integer key", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		_, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithKeyGenerator(GenerateUUIDv4))
+		if !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey, got %v", err)
+		}
+	})
+
+	t.Run("accepts string key", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		if _, err := NewDatabase[stringKeyDBUser](mockDB, "codes", testDBRenderer, WithKeyGenerator(GenerateULID)); err != nil {
+			t.Errorf("NewDatabase failed: %v", err)
+		}
+	})
+
+	t.Run("set generates key before BeforeSave", func(t *testing.T) {
+		db, capture, _ := newDB(t, gen)
+		user := &uuidKeyDBUser{Name: "Alice"}
+		if err := db.Set(ctx, "", user); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if user.ID != fixed {
+			t.Errorf("expected key assigned into record, got %s", user.ID)
+		}
+		if user.keyAtSave != fixed {
+			t.Errorf("expected key set before BeforeSave, got %s", user.keyAtSave)
+		}
+		last, _ := capture.Last()
+		if !strings.Contains(last.Query, "INSERT") || strings.Contains(last.Query, "ON CONFLICT") {
+			t.Errorf("expected a plain INSERT, got %q", last.Query)
+		}
+	})
+
+	t.Run("set with key upserts", func(t *testing.T) {
+		db, capture, _ := newDB(t, gen)
+		user := &uuidKeyDBUser{ID: uuid.New(), Name: "Alice"}
+		id := user.ID
+		_ = db.Set(ctx, id.String(), user)
+		if user.ID != id {
+			t.Errorf("expected key kept, got %s", user.ID)
+		}
+		last, _ := capture.Last()
+		if !strings.Contains(last.Query, "ON CONFLICT") {
+			t.Errorf("expected an upsert, got %q", last.Query)
+		}
+	})
+
+	t.Run("create returns key", func(t *testing.T) {
+		db, _, _ := newDB(t, gen)
+		user := &uuidKeyDBUser{Name: "Alice"}
+		inserted, key, err := db.Create(ctx, user)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if key != fixed.String() || inserted.ID != fixed {
+			t.Errorf("expected key %s, got %q and %s", fixed, key, inserted.ID)
+		}
+		if user.savedCalls != 1 {
+			t.Errorf("expected BeforeSave once, got %d", user.savedCalls)
+		}
+	})
+
+	t.Run("create without generator", func(t *testing.T) {
+		db, capture, _ := newDB(t)
+		if _, _, err := db.Create(ctx, &uuidKeyDBUser{Name: "Alice"}); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey, got %v", err)
+		}
+		if len(capture.Queries) != 0 {
+			t.Errorf("expected no queries, got %v", capture.Queries)
+		}
+	})
+
+	t.Run("collision is a duplicate", func(t *testing.T) {
+		db, _, cfg := newDB(t, gen)
+		cfg.SetQueryErr(&fakeSQLiteError{code: 1555, msg: "constraint failed: UNIQUE constraint failed: users.id (1555)"})
+		err := db.Set(ctx, "", &uuidKeyDBUser{Name: "Bob"})
+		if !errors.Is(err, ErrDuplicate) {
+			t.Errorf("expected ErrDuplicate, got %v", err)
+		}
+	})
+
+	t.Run("invalid generated key", func(t *testing.T) {
+		db, _, _ := newDB(t, WithKeyGenerator(func() string { return "not-a-uuid" }))
+		if _, _, err := db.Create(ctx, &uuidKeyDBUser{}); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey, got %v", err)
+		}
+	})
+}
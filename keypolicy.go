@@ -0,0 +1,153 @@
+package grub
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// KeyPolicy validates and optionally normalizes the keys passed to a Store
+// or Bucket. Attach one with WithKeyPolicy. The zero KeyPolicy accepts every
+// key unchanged.
+type KeyPolicy struct {
+	// RejectEmpty rejects the empty key. List prefixes may still be empty.
+	RejectEmpty bool
+
+	// MaxLength is the maximum key length in bytes. Zero means no limit.
+	MaxLength int
+
+	// Allowed reports whether a rune may appear in a key. Nil allows any
+	// rune; invalid UTF-8 is always rejected when Allowed is set.
+	Allowed func(r rune) bool
+
+	// Pattern must match the key, if set. Anchor it with ^ and $ to
+	// constrain the whole key.
+	Pattern *regexp.Regexp
+
+	// Normalize rewrites each key before validation, if set. The normalized
+	// key is validated and passed to the provider.
+	Normalize func(key string) string
+}
+
+// defaultMaxKeyLength is the key length limit of DefaultKeyPolicy.
+const defaultMaxKeyLength = 512
+
+// DefaultKeyPolicy returns a policy rejecting empty keys, keys longer than
+// 512 bytes, and keys containing anything other than printable ASCII. It is
+// not applied unless passed to WithKeyPolicy, so existing data keyed outside
+// these rules stays reachable.
+func DefaultKeyPolicy() KeyPolicy {
+	return KeyPolicy{
+		RejectEmpty: true,
+		MaxLength:   defaultMaxKeyLength,
+		Allowed: func(r rune) bool {
+			return r >= 0x20 && r <= 0x7e
+		},
+	}
+}
+
+// apply normalizes and validates key, returning the key to use. On error,
+// or with a nil policy, key is returned unchanged.
+func (p *KeyPolicy) apply(key string) (string, error) {
+	if p == nil {
+		return key, nil
+	}
+	if p.RejectEmpty && key == "" {
+		return key, &KeyError{Key: key, Rule: "empty"}
+	}
+	return p.check(key)
+}
+
+// applyPrefix normalizes and validates a List prefix. The empty prefix is
+// always accepted.
+func (p *KeyPolicy) applyPrefix(prefix string) (string, error) {
+	if p == nil || prefix == "" {
+		return prefix, nil
+	}
+	return p.check(prefix)
+}
+
+// applyAll applies the policy to each key in order, reporting the first
+// invalid one.
+func (p *KeyPolicy) applyAll(keys []string) ([]string, error) {
+	if p == nil {
+		return keys, nil
+	}
+	out := make([]string, len(keys))
+	for n, key := range keys {
+		normalized, err := p.apply(key)
+		if err != nil {
+			return nil, err
+		}
+		out[n] = normalized
+	}
+	return out, nil
+}
+
+// applyMap applies the policy to every key of items, returning a copy keyed
+// by the normalized keys. Map order is random, so keys are checked in sorted
+// order and the first invalid one is reported deterministically.
+func applyMap[V any](p *KeyPolicy, items map[string]V) (map[string]V, error) {
+	if p == nil {
+		return items, nil
+	}
+	keys := make([]string, 0, len(items))
+	for k := range items {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	out := make(map[string]V, len(items))
+	for _, k := range keys {
+		normalized, err := p.apply(k)
+		if err != nil {
+			return nil, err
+		}
+		out[normalized] = items[k]
+	}
+	return out, nil
+}
+
+// originalKeys re-keys a provider result fetched by normalized keys under
+// the caller's keys.
+func originalKeys[V any](raw map[string]V, keys, normalized []string) map[string]V {
+	if slices.Equal(keys, normalized) {
+		return raw
+	}
+	out := make(map[string]V, len(raw))
+	for n, k := range keys {
+		if v, ok := raw[normalized[n]]; ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (p *KeyPolicy) check(key string) (string, error) {
+	original := key
+	if p.Normalize != nil {
+		key = p.Normalize(key)
+		if p.RejectEmpty && key == "" {
+			return original, &KeyError{Key: original, Rule: "empty after normalization"}
+		}
+	}
+	if p.MaxLength > 0 && len(key) > p.MaxLength {
+		return original, &KeyError{Key: original, Rule: fmt.Sprintf("longer than %d bytes", p.MaxLength)}
+	}
+	if p.Allowed != nil {
+		for i, r := range key {
+			if r == utf8.RuneError {
+				if _, size := utf8.DecodeRuneInString(key[i:]); size == 1 {
+					return original, &KeyError{Key: original, Rule: fmt.Sprintf("invalid UTF-8 at byte %d", i)}
+				}
+			}
+			if !p.Allowed(r) {
+				return original, &KeyError{Key: original, Rule: fmt.Sprintf("disallowed rune %U at byte %d", r, i)}
+			}
+		}
+	}
+	if p.Pattern != nil && !p.Pattern.MatchString(key) {
+		return original, &KeyError{Key: original, Rule: fmt.Sprintf("does not match %s", p.Pattern)}
+	}
+	return key, nil
+}
@@ -0,0 +1,193 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestKeyPolicy_Rules(t *testing.T) {
+	policy := DefaultKeyPolicy()
+	for _, tc := range []struct {
+		name   string
+		policy KeyPolicy
+		key    string
+		want   string
+		rule   string
+	}{
+		{"valid", policy, "users/42", "users/42", ""},
+		{"empty", policy, "", "", "empty"},
+		{"too long", policy, strings.Repeat("k", 513), "", "longer than 512 bytes"},
+		{"max length", policy, strings.Repeat("k", 512), strings.Repeat("k", 512), ""},
+		{"newline", policy, "a\nb", "", "disallowed rune U+000A at byte 1"},
+		{"non-ascii", policy, "café", "", "disallowed rune U+00E9 at byte 3"},
+		{"invalid utf-8", KeyPolicy{Allowed: func(rune) bool { return true }}, "a\xffb", "", "invalid UTF-8 at byte 1"},
+		{"pattern", KeyPolicy{Pattern: regexp.MustCompile(`^[a-z]+$`)}, "abc1", "", "does not match ^[a-z]+$"},
+		{"zero policy", KeyPolicy{}, "", "", ""},
+		{"normalize", KeyPolicy{Normalize: strings.ToLower}, "Users/42", "users/42", ""},
+		{"empty after normalization", KeyPolicy{RejectEmpty: true, Normalize: strings.TrimSpace}, "  ", "", "empty after normalization"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.policy.apply(tc.key)
+			if tc.rule == "" {
+				if err != nil {
+					t.Fatalf("expected %q to pass, got %v", tc.key, err)
+				}
+				if got != tc.want {
+					t.Errorf("expected %q, got %q", tc.want, got)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidKey) {
+				t.Fatalf("expected ErrInvalidKey, got %v", err)
+			}
+			var ke *KeyError
+			if !errors.As(err, &ke) {
+				t.Fatalf("expected KeyError, got %T", err)
+			}
+			if ke.Key != tc.key || ke.Rule != tc.rule {
+				t.Errorf("expected key %q rule %q, got key %q rule %q", tc.key, tc.rule, ke.Key, ke.Rule)
+			}
+		})
+	}
+}
+
+func TestStore_KeyPolicy(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockStoreProvider()
+	store := NewStore[testRecord](provider, WithKeyPolicy(DefaultKeyPolicy()))
+
+	t.Run("single key", func(t *testing.T) {
+		err := store.Set(ctx, "bad\nkey", &testRecord{ID: 1}, 0)
+		if !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("expected ErrInvalidKey, got %v", err)
+		}
+		var gerr *Error
+		if !errors.As(err, &gerr) || gerr.Op != "set" || gerr.Key != "bad\nkey" {
+			t.Errorf("expected set error for the offending key, got %v", err)
+		}
+		if len(provider.data) != 0 {
+			t.Error("invalid key reached the provider")
+		}
+		if _, err := store.Get(ctx, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey from Get, got %v", err)
+		}
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		a := store.Atomic()
+		rec, err := a.Get(ctx, "absent")
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound for a valid key, got %v %v", rec, err)
+		}
+		if err := a.Set(ctx, "bad\nkey", nil, 0); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey from Atomic Set, got %v", err)
+		}
+		if err := a.Delete(ctx, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey from Atomic Delete, got %v", err)
+		}
+		if len(provider.data) != 0 {
+			t.Error("invalid key reached the provider")
+		}
+	})
+
+	t.Run("batch reports first invalid key", func(t *testing.T) {
+		items := map[string]*testRecord{
+			"ok":   {ID: 1},
+			"z\tb": {ID: 2},
+			"b\tz": {ID: 3},
+		}
+		err := store.SetBatch(ctx, items, 0)
+		var ke *KeyError
+		if !errors.As(err, &ke) || ke.Key != "b\tz" {
+			t.Fatalf("expected KeyError for %q, got %v", "b\tz", err)
+		}
+		if len(provider.data) != 0 {
+			t.Error("batch with an invalid key reached the provider")
+		}
+
+		_, err = store.GetBatch(ctx, []string{"ok", "z\tb", "b\tz"})
+		if !errors.As(err, &ke) || ke.Key != "z\tb" {
+			t.Errorf("expected KeyError for %q, got %v", "z\tb", err)
+		}
+		_, err = store.ExistsBatch(ctx, []string{"", "ok"})
+		if !errors.As(err, &ke) || ke.Key != "" {
+			t.Errorf("expected KeyError for the empty key, got %v", err)
+		}
+	})
+
+	t.Run("list prefix", func(t *testing.T) {
+		if _, err := store.List(ctx, "users\x00", 0); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey for prefix, got %v", err)
+		}
+		if _, err := store.List(ctx, "", 0); err != nil {
+			t.Errorf("expected empty prefix to pass, got %v", err)
+		}
+	})
+
+	t.Run("normalization", func(t *testing.T) {
+		policy := DefaultKeyPolicy()
+		policy.Normalize = strings.ToLower
+		provider := newMockStoreProvider()
+		store := NewStore[testRecord](provider, WithKeyPolicy(policy))
+
+		if err := store.Set(ctx, "Users/1", &testRecord{ID: 1}, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if _, ok := provider.data["users/1"]; !ok {
+			t.Errorf("expected normalized key in provider, got %v", provider.data)
+		}
+		got, err := store.GetBatch(ctx, []string{"USERS/1"})
+		if err != nil {
+			t.Fatalf("GetBatch failed: %v", err)
+		}
+		if got["USERS/1"] == nil {
+			t.Errorf("expected result under the caller's key, got %v", got)
+		}
+		keys, err := store.List(ctx, "USERS/", 0)
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if len(keys) != 1 {
+			t.Errorf("expected normalized prefix to match 1 key, got %v", keys)
+		}
+	})
+}
+
+func TestBucket_KeyPolicy(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockBucketProvider()
+	bucket := NewBucket[testPayload](provider, WithKeyPolicy(KeyPolicy{
+		RejectEmpty: true,
+		Pattern:     regexp.MustCompile(`^[a-z0-9/_-]+(\.[a-z]+)?$`),
+	}))
+
+	err := bucket.Put(ctx, &Object[testPayload]{Key: "docs/../etc", Data: testPayload{}})
+	var ke *KeyError
+	if !errors.As(err, &ke) || ke.Key != "docs/../etc" {
+		t.Fatalf("expected KeyError for a dot-dot key, got %v", err)
+	}
+	if len(provider.data) != 0 {
+		t.Error("invalid key reached the provider")
+	}
+	if _, err := bucket.Exists(ctx, ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey from Exists, got %v", err)
+	}
+	if _, err := bucket.List(ctx, "docs/../", 0); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey for prefix, got %v", err)
+	}
+	if err := bucket.Atomic().Put(ctx, "docs/../etc", nil); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey from Atomic Put, got %v", err)
+	}
+	if err := bucket.Atomic().Delete(ctx, ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey from Atomic Delete, got %v", err)
+	}
+	if len(provider.data) != 0 {
+		t.Error("invalid key reached the provider")
+	}
+	if err := bucket.Put(ctx, &Object[testPayload]{Key: "docs/a.json", Data: testPayload{}}); err != nil {
+		t.Errorf("expected valid key to pass, got %v", err)
+	}
+}
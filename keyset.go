@@ -0,0 +1,60 @@
+package grub
+
+import (
+	"context"
+	"reflect"
+)
+
+// QueryAfter returns up to limit records ordered by orderCol ascending,
+// starting after the row whose orderCol value is after (nil for the first
+// page), and the cursor to pass as after for the next page (nil when no
+// rows remain). Each page is one WHERE orderCol > ? ORDER BY orderCol LIMIT
+// query, so an index on orderCol keeps deep pages as fast as the first,
+// unlike OFFSET. orderCol should be unique and non-null, such as the
+// primary key or a unique timestamp: rows that tie on a page boundary are
+// skipped. Limit of 0 or less uses 1000.
+func (d *Database[T]) QueryAfter(ctx context.Context, orderCol string, after any, limit int) ([]*T, any, error) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	params := make(map[string]any, 1)
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	rows, err := read(callCtx, d, func(c conn[T]) ([]*T, error) {
+		q := c.executor.Soy().Query()
+		if after != nil {
+			q = q.Where(orderCol, ">", "after")
+			params["after"] = after
+		}
+		// one extra row tells whether another page exists
+		return q.OrderBy(orderCol, "asc").Limit(limit+1).Exec(callCtx, params)
+	})
+	if err != nil {
+		return nil, nil, d.wrapErr("query_after", "", err)
+	}
+	if len(rows) <= limit {
+		return rows, nil, nil
+	}
+	rows = rows[:limit]
+	return rows, d.fieldValue(rows[limit-1], orderCol), nil
+}
+
+// fieldValue returns the value of the field mapped to column in rec, with
+// any pointer removed. Nil pointers return nil.
+func (d *Database[T]) fieldValue(rec *T, column string) any {
+	v := reflect.ValueOf(rec).Elem()
+	for _, field := range d.executor.Soy().Metadata().Fields {
+		if field.Tags["db"] != column {
+			continue
+		}
+		fv := v.FieldByIndex(field.Index)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				return nil
+			}
+			fv = fv.Elem()
+		}
+		return fv.Interface()
+	}
+	return nil
+}
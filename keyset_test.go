@@ -0,0 +1,63 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestDatabase_QueryAfter(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture, config := mockdb.NewIsolated()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	row := func(id int64) []driver.Value { return []driver.Value{id, "u@example.com", "U", nil} }
+	columns := []string{"id", "email", "name", "age"}
+
+	t.Run("first page", func(t *testing.T) {
+		config.SetRows(columns, row(1), row(2), row(3))
+		rows, next, err := db.QueryAfter(ctx, "id", nil, 2)
+		if err != nil {
+			t.Fatalf("QueryAfter failed: %v", err)
+		}
+		if len(rows) != 2 || next != 2 {
+			t.Errorf("expected 2 rows and cursor 2, got %d rows and %v", len(rows), next)
+		}
+		query, _ := capture.Last()
+		if strings.Contains(query.Query, "WHERE") {
+			t.Errorf("expected no WHERE on the first page, got: %s", query.Query)
+		}
+		if !strings.Contains(query.Query, `ORDER BY "id" ASC`) || !strings.Contains(query.Query, "LIMIT 3") {
+			t.Errorf("expected ORDER BY id with one extra row, got: %s", query.Query)
+		}
+	})
+
+	t.Run("next page", func(t *testing.T) {
+		config.SetRows(columns, row(3))
+		rows, next, err := db.QueryAfter(ctx, "id", 2, 2)
+		if err != nil {
+			t.Fatalf("QueryAfter failed: %v", err)
+		}
+		if len(rows) != 1 || next != nil {
+			t.Errorf("expected the last row and no cursor, got %d rows and %v", len(rows), next)
+		}
+		query, _ := capture.Last()
+		if !strings.Contains(query.Query, `WHERE "id" >`) {
+			t.Errorf("expected a seek condition, got: %s", query.Query)
+		}
+		if len(query.Args) != 1 || query.Args[0] != int64(2) {
+			t.Errorf("expected the cursor bound, got %v", query.Args)
+		}
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		if _, _, err := db.QueryAfter(ctx, "missing", nil, 2); err == nil {
+			t.Error("expected an error for an unknown column")
+		}
+	})
+}
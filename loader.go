@@ -0,0 +1,220 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Loader defaults.
+const (
+	defaultBatchWindow  = 2 * time.Millisecond
+	defaultMaxBatchSize = 100
+)
+
+// Getter fetches a single record by key. Store and Database satisfy it.
+type Getter[T any] interface {
+	Get(ctx context.Context, key string) (*T, error)
+}
+
+// BatchGetter fetches many records in one call, omitting missing keys.
+// Store and Database satisfy it.
+type BatchGetter[T any] interface {
+	GetBatch(ctx context.Context, keys []string) (map[string]*T, error)
+}
+
+// Loader coalesces and caches Get calls for the lifetime of one request.
+//
+// Concurrent loads of the same key share a single fetch. When the source
+// implements BatchGetter, distinct keys requested within the batch window are
+// fetched together, up to the maximum batch size; otherwise each key is
+// fetched with Get. Results, including ErrNotFound and other errors, are
+// cached until Clear, so repeated misses do not re-query. Context errors are
+// not cached.
+//
+// A Loader is safe for concurrent use. Create one per request: it never
+// expires entries and does not see writes made after a key was loaded.
+type Loader[T any] struct {
+	source   Getter[T]
+	batch    BatchGetter[T]
+	window   time.Duration
+	maxBatch int
+
+	mu       sync.Mutex
+	cache    map[string]*loaderEntry[T]
+	pending  []pendingLoad[T]
+	batchCtx context.Context
+	timer    *time.Timer
+}
+
+type loaderEntry[T any] struct {
+	done  chan struct{}
+	value *T
+	err   error
+}
+
+type pendingLoad[T any] struct {
+	key   string
+	entry *loaderEntry[T]
+}
+
+// NewLoader creates a Loader over source. Honours WithBatchWindow and
+// WithMaxBatchSize; defaults are 2ms and 100 keys.
+func NewLoader[T any](source Getter[T], opts ...Option) *Loader[T] {
+	o := applyOptions(opts)
+	l := &Loader[T]{
+		source:   source,
+		window:   defaultBatchWindow,
+		maxBatch: defaultMaxBatchSize,
+		cache:    make(map[string]*loaderEntry[T]),
+	}
+	if b, ok := source.(BatchGetter[T]); ok {
+		l.batch = b
+	}
+	if o.batchWindow > 0 {
+		l.window = o.batchWindow
+	}
+	if o.maxBatchSize > 0 {
+		l.maxBatch = o.maxBatchSize
+	}
+	return l
+}
+
+// Load returns the record at key, fetching it at most once per Loader.
+// Returns an error wrapping ErrNotFound if the key does not exist.
+func (l *Loader[T]) Load(ctx context.Context, key string) (*T, error) {
+	return l.enqueue(ctx, []string{key})[0].wait(ctx)
+}
+
+// LoadMany returns the records at keys, fetching uncached keys together.
+// Missing keys are omitted from the result; any other error aborts the call.
+func (l *Loader[T]) LoadMany(ctx context.Context, keys []string) (map[string]*T, error) {
+	entries := l.enqueue(ctx, keys)
+	result := make(map[string]*T, len(keys))
+	for idx, e := range entries {
+		value, err := e.wait(ctx)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[keys[idx]] = value
+	}
+	return result, nil
+}
+
+// Clear drops key from the cache so the next Load fetches it again.
+// Use it after writing the key within the same request.
+func (l *Loader[T]) Clear(key string) {
+	l.mu.Lock()
+	delete(l.cache, key)
+	l.mu.Unlock()
+}
+
+// enqueue returns the cache entry for each key, scheduling fetches for keys
+// seen for the first time.
+func (l *Loader[T]) enqueue(ctx context.Context, keys []string) []*loaderEntry[T] {
+	entries := make([]*loaderEntry[T], len(keys))
+	var direct []pendingLoad[T]
+	var full [][]pendingLoad[T]
+
+	l.mu.Lock()
+	for idx, key := range keys {
+		if e, ok := l.cache[key]; ok {
+			entries[idx] = e
+			continue
+		}
+		e := &loaderEntry[T]{done: make(chan struct{})}
+		l.cache[key] = e
+		entries[idx] = e
+		if l.batch == nil {
+			direct = append(direct, pendingLoad[T]{key: key, entry: e})
+			continue
+		}
+		if len(l.pending) == 0 {
+			// Fetches outlive any single caller, so the batch keeps the
+			// first caller's values but not its cancellation.
+			l.batchCtx = context.WithoutCancel(ctx)
+			l.timer = time.AfterFunc(l.window, l.flush)
+		}
+		l.pending = append(l.pending, pendingLoad[T]{key: key, entry: e})
+		if len(l.pending) >= l.maxBatch {
+			l.timer.Stop()
+			full = append(full, l.pending)
+			l.pending = nil
+		}
+	}
+	batchCtx := l.batchCtx
+	l.mu.Unlock()
+
+	for _, p := range full {
+		go l.dispatch(batchCtx, p)
+	}
+	for _, p := range direct {
+		go l.fetch(context.WithoutCancel(ctx), p)
+	}
+	return entries
+}
+
+// flush dispatches the pending batch when the window closes.
+func (l *Loader[T]) flush() {
+	l.mu.Lock()
+	pending, ctx := l.pending, l.batchCtx
+	l.pending = nil
+	l.mu.Unlock()
+	if len(pending) > 0 {
+		l.dispatch(ctx, pending)
+	}
+}
+
+// dispatch fetches a batch with one GetBatch call.
+func (l *Loader[T]) dispatch(ctx context.Context, pending []pendingLoad[T]) {
+	keys := make([]string, len(pending))
+	for idx, p := range pending {
+		keys[idx] = p.key
+	}
+	found, err := l.batch.GetBatch(ctx, keys)
+	for _, p := range pending {
+		if err != nil {
+			l.complete(p, nil, err)
+			continue
+		}
+		if value, ok := found[p.key]; ok {
+			l.complete(p, value, nil)
+		} else {
+			l.complete(p, nil, ErrNotFound)
+		}
+	}
+}
+
+// fetch loads a single key from a source without batch support.
+func (l *Loader[T]) fetch(ctx context.Context, p pendingLoad[T]) {
+	value, err := l.source.Get(ctx, p.key)
+	l.complete(p, value, err)
+}
+
+// complete resolves an entry, evicting it again on context errors so a
+// later Load retries.
+func (l *Loader[T]) complete(p pendingLoad[T], value *T, err error) {
+	p.entry.value, p.entry.err = value, err
+	close(p.entry.done)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		l.mu.Lock()
+		if l.cache[p.key] == p.entry {
+			delete(l.cache, p.key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// wait blocks until the entry resolves or ctx ends.
+func (e *loaderEntry[T]) wait(ctx context.Context) (*T, error) {
+	select {
+	case <-e.done:
+		return e.value, e.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,220 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	_ BatchGetter[testRecord] = (*Store[testRecord])(nil)
+	_ BatchGetter[TestDBUser] = (*Database[TestDBUser])(nil)
+)
+
+// countingSource records every key it is asked for.
+type countingSource struct {
+	mu       sync.Mutex
+	records  map[string]*testRecord
+	gets     int
+	batches  int
+	requests map[string]int
+	batchErr error
+}
+
+func newCountingSource(n int) *countingSource {
+	src := &countingSource{
+		records:  make(map[string]*testRecord),
+		requests: make(map[string]int),
+	}
+	for i := range n {
+		src.records[strconv.Itoa(i)] = &testRecord{ID: i, Name: "r" + strconv.Itoa(i)}
+	}
+	return src
+}
+
+func (s *countingSource) Get(_ context.Context, key string) (*testRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gets++
+	s.requests[key]++
+	if r, ok := s.records[key]; ok {
+		return r, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *countingSource) GetBatch(_ context.Context, keys []string) (map[string]*testRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches++
+	if s.batchErr != nil {
+		return nil, s.batchErr
+	}
+	out := make(map[string]*testRecord, len(keys))
+	for _, k := range keys {
+		s.requests[k]++
+		if r, ok := s.records[k]; ok {
+			out[k] = r
+		}
+	}
+	return out, nil
+}
+
+// singleSource hides GetBatch so the loader falls back to Get.
+type singleSource struct{ src *countingSource }
+
+func (s singleSource) Get(ctx context.Context, key string) (*testRecord, error) {
+	return s.src.Get(ctx, key)
+}
+
+func TestLoader_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	src := newCountingSource(20)
+	loader := NewLoader[testRecord](src, WithBatchWindow(10*time.Millisecond), WithMaxBatchSize(8))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for g := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Keys 0-24 overlap heavily; 20-24 do not exist.
+			key := strconv.Itoa(g % 25)
+			rec, err := loader.Load(ctx, key)
+			if g%25 >= 20 {
+				if !errors.Is(err, ErrNotFound) {
+					errs <- errors.New("expected ErrNotFound for " + key)
+				}
+				return
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			if strconv.Itoa(rec.ID) != key {
+				errs <- errors.New("wrong record for " + key)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if src.gets != 0 {
+		t.Errorf("expected batched fetches only, got %d Get calls", src.gets)
+	}
+	for key, n := range src.requests {
+		if n != 1 {
+			t.Errorf("key %s fetched %d times", key, n)
+		}
+	}
+	if len(src.requests) != 25 {
+		t.Errorf("expected 25 distinct keys fetched, got %d", len(src.requests))
+	}
+	// 25 keys at 8 per batch need at least 4 calls; the window bounds the rest.
+	if src.batches < 4 || src.batches > 25 {
+		t.Errorf("unexpected batch count %d", src.batches)
+	}
+}
+
+func TestLoader_CachesMisses(t *testing.T) {
+	ctx := context.Background()
+	src := newCountingSource(1)
+	loader := NewLoader[testRecord](src)
+
+	for range 3 {
+		if _, err := loader.Load(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	}
+	if src.requests["missing"] != 1 {
+		t.Errorf("expected the miss to be cached, fetched %d times", src.requests["missing"])
+	}
+
+	loader.Clear("missing")
+	_, _ = loader.Load(ctx, "missing")
+	if src.requests["missing"] != 2 {
+		t.Errorf("expected Clear to force a refetch, fetched %d times", src.requests["missing"])
+	}
+}
+
+func TestLoader_CachesErrors(t *testing.T) {
+	ctx := context.Background()
+	src := newCountingSource(1)
+	src.batchErr = errors.New("backend down")
+	loader := NewLoader[testRecord](src)
+
+	for range 2 {
+		if _, err := loader.Load(ctx, "0"); !errors.Is(err, src.batchErr) {
+			t.Fatalf("expected batch error, got %v", err)
+		}
+	}
+	if src.batches != 1 {
+		t.Errorf("expected error to be cached, got %d batches", src.batches)
+	}
+}
+
+func TestLoader_LoadMany(t *testing.T) {
+	ctx := context.Background()
+	src := newCountingSource(3)
+	loader := NewLoader[testRecord](src)
+
+	got, err := loader.LoadMany(ctx, []string{"0", "1", "1", "missing"})
+	if err != nil {
+		t.Fatalf("LoadMany failed: %v", err)
+	}
+	if len(got) != 2 || got["0"] == nil || got["1"] == nil {
+		t.Errorf("expected keys 0 and 1, got %v", got)
+	}
+	if src.batches != 1 {
+		t.Errorf("expected one batch, got %d", src.batches)
+	}
+
+	if _, err := loader.Load(ctx, "0"); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if src.batches != 1 {
+		t.Errorf("expected cached Load, got %d batches", src.batches)
+	}
+}
+
+func TestLoader_WithoutBatch(t *testing.T) {
+	ctx := context.Background()
+	src := newCountingSource(2)
+	loader := NewLoader[testRecord](singleSource{src})
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = loader.Load(ctx, "1")
+		}()
+	}
+	wg.Wait()
+	if src.batches != 0 || src.gets != 1 {
+		t.Errorf("expected a single Get, got %d gets and %d batches", src.gets, src.batches)
+	}
+}
+
+func TestLoader_CallerCancel(t *testing.T) {
+	src := newCountingSource(1)
+	loader := NewLoader[testRecord](src, WithBatchWindow(20*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := loader.Load(ctx, "0"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// The fetch is detached from the cancelled caller, so others still get it.
+	rec, err := loader.Load(context.Background(), "0")
+	if err != nil || rec.ID != 0 {
+		t.Errorf("expected record 0, got %v, %v", rec, err)
+	}
+}
@@ -0,0 +1,170 @@
+package grub
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// ListMatch returns up to limit keys matching a Redis-style glob pattern,
+// starting after cursor, along with the cursor for the next page. Pass ""
+// to start; an empty returned cursor means there are no more matches.
+// Limit of 0 means no limit. Cursors are opaque and only valid for the
+// Store that returned them.
+//
+// In a pattern, * matches any sequence of characters, ? any single
+// character, [abc] one of the listed characters, [a-z] one character in the
+// range, [^a] one character not listed, and \x the character x literally.
+// Matching is case-sensitive; use classes such as [Uu]ser:* to match
+// either case.
+//
+// Providers implementing StoreMatcher (redis) evaluate the pattern on the
+// server. Others list every key under the pattern's literal prefix and
+// filter client-side on each call, which can be expensive over a large
+// keyspace; anchor patterns with a literal prefix where possible.
+func (s *Store[T]) ListMatch(ctx context.Context, pattern, cursor string, limit int) ([]string, string, error) {
+	callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+	defer cancel()
+	var keys []string
+	var next string
+	var err error
+	if m, ok := storeMatcher(s.provider); ok {
+		keys, next, err = m.ListMatch(callCtx, pattern, cursor, limit)
+	} else {
+		keys, next, err = s.listMatch(callCtx, pattern, cursor, limit)
+	}
+	if err != nil {
+		return nil, "", shared.WrapError(KindStore, "list_match", "", pattern, err)
+	}
+	return keys, next, nil
+}
+
+// listMatch filters List results against pattern. Keys are sorted so the
+// last key returned can serve as the cursor.
+func (s *Store[T]) listMatch(ctx context.Context, pattern, cursor string, limit int) ([]string, string, error) {
+	all, err := s.provider.List(ctx, globPrefix(pattern), 0)
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Strings(all)
+	var keys []string
+	for _, key := range all {
+		if key <= cursor || !matchGlob(pattern, key) {
+			continue
+		}
+		if limit > 0 && len(keys) == limit {
+			return keys, keys[len(keys)-1], nil
+		}
+		keys = append(keys, key)
+	}
+	return keys, "", nil
+}
+
+// globEscaper escapes the characters special to Redis-style globs.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// escapeGlob returns a pattern matching s literally.
+func escapeGlob(s string) string {
+	return globEscaper.Replace(s)
+}
+
+// globPrefix returns the literal prefix of pattern up to its first special
+// character.
+func globPrefix(pattern string) string {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*', '?', '[', '\\':
+			return pattern[:i]
+		}
+	}
+	return pattern
+}
+
+// matchGlob reports whether s matches pattern, following Redis's
+// stringmatch: bytes are compared, an unterminated class is closed at the
+// end of the pattern, and a trailing backslash matches itself.
+func matchGlob(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if matchGlob(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			s = s[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			var ok bool
+			ok, pattern = matchClass(pattern[1:], s[0])
+			if !ok {
+				return false
+			}
+			s = s[1:]
+			if len(pattern) == 0 {
+				return len(s) == 0
+			}
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			s = s[1:]
+		}
+		pattern = pattern[1:]
+	}
+	return len(s) == 0
+}
+
+// matchClass matches c against the class body that follows '[' and returns
+// the pattern positioned at the closing ']', or empty if there is none.
+func matchClass(pattern string, c byte) (bool, string) {
+	negate := len(pattern) > 0 && pattern[0] == '^'
+	if negate {
+		pattern = pattern[1:]
+	}
+	match := false
+	for len(pattern) > 0 && pattern[0] != ']' {
+		switch {
+		case pattern[0] == '\\' && len(pattern) >= 2:
+			pattern = pattern[1:]
+			if pattern[0] == c {
+				match = true
+			}
+		case len(pattern) >= 3 && pattern[1] == '-':
+			lo, hi := pattern[0], pattern[2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			if c >= lo && c <= hi {
+				match = true
+			}
+			pattern = pattern[2:]
+		default:
+			if pattern[0] == c {
+				match = true
+			}
+		}
+		pattern = pattern[1:]
+	}
+	return match != negate, pattern
+}
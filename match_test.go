@@ -0,0 +1,144 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "", true},
+		{"user:*", "user:42", true},
+		{"user:*", "users:42", false},
+		{"*:42", "user:42", true},
+		{"a**b", "axyzb", true},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{"h[c-a]llo", "hbllo", true},
+		{"h[a-c]llo", "hdllo", false},
+		{`h\*llo`, "h*llo", true},
+		{`h\*llo`, "hello", false},
+		{`[\]]`, "]", true},
+		{"[Uu]ser:*", "User:1", true},
+		{"user:*", "USER:1", false},
+		{"ab[c", "abc", true},
+		{`ab\`, `ab\`, true},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGlobPrefix(t *testing.T) {
+	for pattern, want := range map[string]string{
+		"user:*":   "user:",
+		"user:?:x": "user:",
+		"[ab]*":    "",
+		`a\*b`:     "a",
+		"plain":    "plain",
+		"":         "",
+	} {
+		if got := globPrefix(pattern); got != want {
+			t.Errorf("globPrefix(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+// matchingStoreProvider pushes ListMatch down, recording the call.
+type matchingStoreProvider struct {
+	*mockStoreProvider
+	pattern, cursor string
+}
+
+func (m *matchingStoreProvider) ListMatch(_ context.Context, pattern, cursor string, _ int) ([]string, string, error) {
+	m.pattern, m.cursor = pattern, cursor
+	return []string{"pushed"}, "next", nil
+}
+
+func TestStore_ListMatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("client-side pages", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		for _, k := range []string{"user:1:name", "user:2:name", "user:2:email", "user:3:name", "order:1"} {
+			provider.data[k] = []byte(`{}`)
+		}
+		store := NewStore[testRecord](provider)
+
+		var got []string
+		cursor := ""
+		for pages := 0; ; pages++ {
+			if pages > 3 {
+				t.Fatal("cursor did not terminate")
+			}
+			keys, next, err := store.ListMatch(ctx, "user:*:name", cursor, 2)
+			if err != nil {
+				t.Fatalf("ListMatch failed: %v", err)
+			}
+			got = append(got, keys...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		want := []string{"user:1:name", "user:2:name", "user:3:name"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("exact final page has no cursor", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		provider.data["a1"] = []byte(`{}`)
+		provider.data["a2"] = []byte(`{}`)
+		store := NewStore[testRecord](provider)
+
+		keys, next, err := store.ListMatch(ctx, "a?", "", 2)
+		if err != nil {
+			t.Fatalf("ListMatch failed: %v", err)
+		}
+		if len(keys) != 2 || next != "" {
+			t.Errorf("expected 2 keys and no cursor, got %v %q", keys, next)
+		}
+	})
+
+	t.Run("pushed down", func(t *testing.T) {
+		provider := &matchingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		store := NewStore[testRecord](provider)
+
+		keys, next, err := store.ListMatch(ctx, "user:*", "7", 10)
+		if err != nil {
+			t.Fatalf("ListMatch failed: %v", err)
+		}
+		if len(keys) != 1 || keys[0] != "pushed" || next != "next" {
+			t.Errorf("unexpected result %v %q", keys, next)
+		}
+		if provider.pattern != "user:*" || provider.cursor != "7" {
+			t.Errorf("provider got pattern %q cursor %q", provider.pattern, provider.cursor)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		provider.listErr = errors.New("boom")
+		store := NewStore[testRecord](provider)
+
+		_, _, err := store.ListMatch(ctx, "user:*", "", 0)
+		var gErr *Error
+		if !errors.As(err, &gErr) || gErr.Op != "list_match" {
+			t.Errorf("expected list_match error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,16 @@
+module github.com/zoobzio/grub/metrics
+
+go 1.24.0
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/jmoiron/sqlx v1.4.0
+	github.com/prometheus/client_golang v1.23.2
+	github.com/zoobzio/astql v1.0.6
+	github.com/zoobzio/capitan v1.0.0
+	github.com/zoobzio/grub v0.0.0
+	github.com/zoobzio/sentinel v1.0.2
+	github.com/zoobzio/soy v1.0.5
+	github.com/zoobzio/vecna v0.0.2
+	modernc.org/sqlite v1.42.2
+)
@@ -0,0 +1,159 @@
+// Package metrics exports grub and soy capitan signals as Prometheus metrics.
+//
+// Register observes the completed and failed signals emitted by grub
+// facades and maintains an operation counter and a latency histogram,
+// labeled by component, operation, and outcome. Keys, IDs, and table names
+// are never used as labels, keeping cardinality bounded.
+//
+//	m, err := metrics.Register(prometheus.DefaultRegisterer)
+//	if err != nil {
+//	    return err
+//	}
+//	defer m.Close()
+package metrics
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/soy"
+)
+
+// Component label values.
+const (
+	ComponentDatabase = "database"
+	ComponentIndex    = "index"
+)
+
+// Outcome label values.
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Option configures Register.
+type Option func(*options)
+
+type options struct {
+	namespace string
+	buckets   []float64
+}
+
+// WithNamespace sets the metric namespace. Defaults to "grub".
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithBuckets sets the latency histogram buckets, in seconds.
+// Defaults to prometheus.DefBuckets.
+func WithBuckets(buckets []float64) Option {
+	return func(o *options) {
+		o.buckets = buckets
+	}
+}
+
+// source describes how to read labels and latency from one signal.
+type source struct {
+	component string
+	outcome   string
+	operation capitan.StringKey
+	duration  capitan.Int64Key
+}
+
+// sources maps each observed signal to its label values and payload keys.
+var sources = map[capitan.Signal]source{
+	soy.QueryCompleted: {ComponentDatabase, OutcomeSuccess, soy.OperationKey, soy.DurationMsKey},
+	soy.QueryFailed:    {ComponentDatabase, OutcomeError, soy.OperationKey, soy.DurationMsKey},
+
+	grub.IndexSearchCompleted: {ComponentIndex, OutcomeSuccess, grub.OperationKey, grub.DurationMsKey},
+	grub.IndexSearchFailed:    {ComponentIndex, OutcomeError, grub.OperationKey, grub.DurationMsKey},
+	grub.IndexUpsertCompleted: {ComponentIndex, OutcomeSuccess, grub.OperationKey, grub.DurationMsKey},
+	grub.IndexUpsertFailed:    {ComponentIndex, OutcomeError, grub.OperationKey, grub.DurationMsKey},
+	grub.IndexDeleteCompleted: {ComponentIndex, OutcomeSuccess, grub.OperationKey, grub.DurationMsKey},
+	grub.IndexDeleteFailed:    {ComponentIndex, OutcomeError, grub.OperationKey, grub.DurationMsKey},
+}
+
+// Metrics holds the registered collectors and the capitan observer feeding them.
+type Metrics struct {
+	registerer prometheus.Registerer
+	operations *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	observer   *capitan.Observer
+}
+
+// Register creates the grub collectors, registers them with reg, and starts
+// observing capitan signals. Call Close to stop observing and unregister.
+//
+// Exported metrics:
+//   - <namespace>_operations_total{component, operation, outcome}
+//   - <namespace>_operation_duration_seconds{component, operation, outcome}
+func Register(reg prometheus.Registerer, opts ...Option) (*Metrics, error) {
+	o := options{namespace: "grub", buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	labels := []string{"component", "operation", "outcome"}
+	m := &Metrics{
+		registerer: reg,
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "operations_total",
+			Help:      "Total grub storage operations.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: o.namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of grub storage operations in seconds.",
+			Buckets:   o.buckets,
+		}, labels),
+	}
+
+	if err := reg.Register(m.operations); err != nil {
+		return nil, err
+	}
+	if err := reg.Register(m.duration); err != nil {
+		reg.Unregister(m.operations)
+		return nil, err
+	}
+
+	signals := make([]capitan.Signal, 0, len(sources))
+	for signal := range sources {
+		signals = append(signals, signal)
+	}
+	m.observer = capitan.Observe(m.record, signals...)
+	return m, nil
+}
+
+// record updates the collectors for a single event.
+func (m *Metrics) record(_ context.Context, e *capitan.Event) {
+	src, ok := sources[e.Signal()]
+	if !ok {
+		return
+	}
+	op, _ := src.operation.From(e)
+	op = strings.ToLower(op)
+
+	m.operations.WithLabelValues(src.component, op, src.outcome).Inc()
+	if ms, ok := src.duration.From(e); ok {
+		m.duration.WithLabelValues(src.component, op, src.outcome).Observe(float64(ms) / 1000)
+	}
+}
+
+// Drain blocks until all signals emitted before the call have been recorded.
+func (m *Metrics) Drain(ctx context.Context) error {
+	return m.observer.Drain(ctx)
+}
+
+// Close stops observing signals and unregisters the collectors.
+// Signals already queued are recorded before Close returns.
+func (m *Metrics) Close() {
+	m.observer.Close()
+	m.registerer.Unregister(m.operations)
+	m.registerer.Unregister(m.duration)
+}
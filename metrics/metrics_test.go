@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	astqlsqlite "github.com/zoobzio/astql/sqlite"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/sentinel"
+	"github.com/zoobzio/vecna"
+	_ "modernc.org/sqlite"
+)
+
+func init() {
+	sentinel.Tag("db")
+	sentinel.Tag("constraints")
+}
+
+type user struct {
+	ID    int    `db:"id" constraints:"primarykey"`
+	Email string `db:"email" constraints:"notnull"`
+}
+
+type embeddingMeta struct {
+	Source string `json:"source"`
+}
+
+// stubIndex is a minimal grub.VectorProvider whose Search fails on demand.
+type stubIndex struct {
+	searchErr error
+}
+
+func (s *stubIndex) Upsert(context.Context, uuid.UUID, []float32, []byte) error { return nil }
+func (s *stubIndex) UpsertBatch(context.Context, []grub.VectorRecord) error     { return nil }
+func (s *stubIndex) Get(context.Context, uuid.UUID) ([]float32, *grub.VectorInfo, error) {
+	return nil, nil, grub.ErrNotFound
+}
+func (s *stubIndex) Delete(context.Context, uuid.UUID) error        { return nil }
+func (s *stubIndex) DeleteBatch(context.Context, []uuid.UUID) error { return nil }
+func (s *stubIndex) Search(context.Context, []float32, int, map[string]any) ([]grub.VectorResult, error) {
+	return nil, s.searchErr
+}
+func (s *stubIndex) Query(context.Context, []float32, int, *vecna.Filter) ([]grub.VectorResult, error) {
+	return nil, s.searchErr
+}
+func (s *stubIndex) Filter(context.Context, *vecna.Filter, int) ([]grub.VectorResult, error) {
+	return nil, grub.ErrFilterNotSupported
+}
+func (s *stubIndex) List(context.Context, int) ([]uuid.UUID, error)  { return nil, nil }
+func (s *stubIndex) Exists(context.Context, uuid.UUID) (bool, error) { return false, nil }
+
+func newUserDB(t *testing.T) *grub.Database[user] {
+	t.Helper()
+	conn, err := sqlx.Connect("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	if _, err := conn.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL)`); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+	db, err := grub.NewDatabase[user](conn, "users", astqlsqlite.New())
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	return db
+}
+
+func register(t *testing.T, opts ...Option) (*Metrics, *prometheus.Registry) {
+	t.Helper()
+	reg := prometheus.NewRegistry()
+	m, err := Register(reg, opts...)
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	t.Cleanup(m.Close)
+	return m, reg
+}
+
+func drain(t *testing.T, m *Metrics) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.Drain(ctx); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+}
+
+func TestRegister_Database(t *testing.T) {
+	m, reg := register(t)
+	db := newUserDB(t)
+	ctx := context.Background()
+
+	if err := db.Set(ctx, "1", &user{ID: 1, Email: "a@example.com"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, err := db.Get(ctx, "1"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if err := db.Delete(ctx, "1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	drain(t, m)
+
+	for _, op := range []string{"insert", "select", "delete"} {
+		got := testutil.ToFloat64(m.operations.WithLabelValues(ComponentDatabase, op, OutcomeSuccess))
+		if got < 1 {
+			t.Errorf("expected at least one successful %s, got %v", op, got)
+		}
+	}
+	if n, err := testutil.GatherAndCount(reg, "grub_operation_duration_seconds"); err != nil || n < 3 {
+		t.Errorf("expected duration series for each operation, got %d (%v)", n, err)
+	}
+}
+
+func TestRegister_Index(t *testing.T) {
+	m, reg := register(t)
+	provider := &stubIndex{}
+	index := grub.NewIndex[embeddingMeta](provider, grub.WithName("embeddings"))
+	ctx := context.Background()
+
+	id := uuid.New()
+	if err := index.Upsert(ctx, id, []float32{1, 0}, &embeddingMeta{Source: "a"}); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if _, err := index.Search(ctx, []float32{1, 0}, 5, nil); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	provider.searchErr = errors.New("unavailable")
+	_, _ = index.Search(ctx, []float32{1, 0}, 5, nil)
+	if err := index.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	drain(t, m)
+
+	expected := `
+# HELP grub_operations_total Total grub storage operations.
+# TYPE grub_operations_total counter
+grub_operations_total{component="index",operation="delete",outcome="success"} 1
+grub_operations_total{component="index",operation="search",outcome="error"} 1
+grub_operations_total{component="index",operation="search",outcome="success"} 1
+grub_operations_total{component="index",operation="upsert",outcome="success"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "grub_operations_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRegister_NoKeyLabels(t *testing.T) {
+	m, reg := register(t)
+	db := newUserDB(t)
+	if err := db.Set(context.Background(), "42", &user{ID: 42, Email: "secret@example.com"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	drain(t, m)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, mf := range families {
+		for _, metric := range mf.GetMetric() {
+			for _, lp := range metric.GetLabel() {
+				switch lp.GetName() {
+				case "component", "operation", "outcome":
+				default:
+					t.Errorf("unexpected label %q on %s", lp.GetName(), mf.GetName())
+				}
+			}
+		}
+	}
+}
+
+func TestRegister_Options(t *testing.T) {
+	m, reg := register(t, WithNamespace("app"), WithBuckets([]float64{0.5, 1}))
+	index := grub.NewIndex[embeddingMeta](&stubIndex{})
+	if err := index.Delete(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	drain(t, m)
+
+	if n, err := testutil.GatherAndCount(reg, "app_operations_total"); err != nil || n != 1 {
+		t.Errorf("expected 1 app_operations_total series, got %d (%v)", n, err)
+	}
+	if n, _ := testutil.GatherAndCount(reg, "grub_operations_total"); n != 0 {
+		t.Errorf("expected no default-namespace series, got %d", n)
+	}
+}
+
+func TestRegister_Duplicate(t *testing.T) {
+	_, reg := register(t)
+	if _, err := Register(reg); err == nil {
+		t.Error("expected error registering twice on the same registry")
+	}
+}
+
+func TestClose_Unregisters(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := Register(reg)
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	m.Close()
+
+	again, err := Register(reg)
+	if err != nil {
+		t.Fatalf("expected re-register after Close to succeed: %v", err)
+	}
+	again.Close()
+}
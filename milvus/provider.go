@@ -3,6 +3,7 @@ package milvus
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	"github.com/zoobzio/grub"
+	"github.com/zoobzio/grub/internal/shared"
 	"github.com/zoobzio/vecna"
 )
 
@@ -27,8 +29,49 @@ type Config struct {
 	VectorField string
 	// MetadataField is the name of the JSON metadata field. Defaults to "metadata".
 	MetadataField string
+	// ReturnVectors includes the stored vector in Search, Query, and
+	// SearchBatch results. Off by default, since Milvus must fetch each
+	// vector in full; Get and Filter always return vectors.
+	ReturnVectors bool
+	// Dimension is the vector dimensionality EnsureCollection creates the
+	// vector field with.
+	Dimension int
+	// Metric is the metric type of the vector field's index, used for
+	// searches and EnsureCollection and reported by ScoreKind. Defaults to L2. Milvus scores L2
+	// as the squared distance and IP and COSINE as similarities.
+	Metric grub.DistanceMetric
+	// ConsistencyMode controls when writes are flushed. Defaults to
+	// ConsistencyImmediate.
+	ConsistencyMode ConsistencyMode
+	// ReadConsistency is the consistency level reads request under
+	// ConsistencyEventual. The zero value is entity.ClStrong, which sees
+	// every acknowledged write; entity.ClBounded and entity.ClEventually
+	// trade staleness for latency. Ignored in the other modes, where reads
+	// use the collection's level.
+	ReadConsistency entity.ConsistencyLevel
 }
 
+// ConsistencyMode selects when the provider flushes writes, trading write
+// throughput against when a write becomes visible to reads.
+type ConsistencyMode int
+
+const (
+	// ConsistencyImmediate flushes after every Upsert, UpsertBatch, Delete,
+	// and DeleteBatch, so each write is searchable when the call returns.
+	// Flushing seals segments and serialises writers, so throughput is
+	// bounded by the flush rate.
+	ConsistencyImmediate ConsistencyMode = iota
+	// ConsistencyEventual never flushes. Reads request ReadConsistency, so
+	// whether a write is visible to the next read is decided by that level:
+	// Strong sees it, Bounded sees it within the collection's staleness
+	// window, Eventually makes no promise.
+	ConsistencyEventual
+	// ConsistencyManual never flushes and leaves read consistency to the
+	// collection. Call Flush after a batch of writes to make them
+	// searchable; until then reads at the collection's level may miss them.
+	ConsistencyManual
+)
+
 // Provider implements grub.VectorProvider for Milvus.
 type Provider struct {
 	client client.Client
@@ -46,12 +89,83 @@ func New(c client.Client, config Config) *Provider {
 	if config.MetadataField == "" {
 		config.MetadataField = "metadata"
 	}
+	if config.Metric == "" {
+		config.Metric = grub.DistanceL2
+	}
 	return &Provider{
 		client: c,
 		config: config,
 	}
 }
 
+// EnsureCollection creates the collection if it does not exist, with a
+// VarChar primary key, a FloatVector field of Config.Dimension, and a JSON
+// metadata field, then indexes the vector field with AUTOINDEX and
+// Config.Metric and loads the collection. An existing collection is left
+// as is, even if its schema differs.
+func (p *Provider) EnsureCollection(ctx context.Context) error {
+	schema, err := p.schema()
+	if err != nil {
+		return err
+	}
+	exists, err := p.client.HasCollection(ctx, p.config.Collection)
+	if err != nil || exists {
+		return err
+	}
+	idx, err := entity.NewIndexAUTOINDEX(p.metricType())
+	if err != nil {
+		return err
+	}
+	if err := p.client.CreateCollection(ctx, schema, entity.DefaultShardNumber); err != nil {
+		return err
+	}
+	if err := p.client.CreateIndex(ctx, p.config.Collection, p.config.VectorField, idx, false); err != nil {
+		return err
+	}
+	return p.client.LoadCollection(ctx, p.config.Collection, false)
+}
+
+// schema returns the collection schema EnsureCollection creates.
+func (p *Provider) schema() (*entity.Schema, error) {
+	if p.config.Dimension <= 0 {
+		return nil, errors.New("milvus: EnsureCollection requires Config.Dimension")
+	}
+	return entity.NewSchema().
+		WithName(p.config.Collection).
+		WithField(entity.NewField().WithName(p.config.IDField).WithDataType(entity.FieldTypeVarChar).
+			WithIsPrimaryKey(true).WithMaxLength(36)).
+		WithField(entity.NewField().WithName(p.config.VectorField).WithDataType(entity.FieldTypeFloatVector).
+			WithDim(int64(p.config.Dimension))).
+		WithField(entity.NewField().WithName(p.config.MetadataField).WithDataType(entity.FieldTypeJSON)), nil
+}
+
+// Flush seals the collection's growing segments, making every write so far
+// searchable at any consistency level. Under ConsistencyManual, call it
+// once after a batch of writes.
+func (p *Provider) Flush(ctx context.Context) error {
+	return p.client.Flush(ctx, p.config.Collection, false)
+}
+
+// afterWrite flushes under ConsistencyImmediate and does nothing otherwise.
+func (p *Provider) afterWrite(ctx context.Context) error {
+	if p.config.ConsistencyMode != ConsistencyImmediate {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.Flush(ctx)
+}
+
+// readOptions returns opts with the ReadConsistency level added under
+// ConsistencyEventual.
+func (p *Provider) readOptions(opts ...client.SearchQueryOptionFunc) []client.SearchQueryOptionFunc {
+	if p.config.ConsistencyMode != ConsistencyEventual {
+		return opts
+	}
+	return append(opts, client.WithSearchQueryConsistencyLevel(p.config.ReadConsistency))
+}
+
 // Upsert stores or updates a vector with associated metadata.
 func (p *Provider) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata []byte) error {
 	idCol := entity.NewColumnVarChar(p.config.IDField, []string{id.String()})
@@ -63,8 +177,7 @@ func (p *Provider) Upsert(ctx context.Context, id uuid.UUID, vector []float32, m
 		return err
 	}
 
-	// Flush to make data immediately searchable
-	return p.client.Flush(ctx, p.config.Collection, false)
+	return p.afterWrite(ctx)
 }
 
 // UpsertBatch stores or updates multiple vectors.
@@ -96,8 +209,7 @@ func (p *Provider) UpsertBatch(ctx context.Context, vectors []grub.VectorRecord)
 		return err
 	}
 
-	// Flush to make data immediately searchable
-	return p.client.Flush(ctx, p.config.Collection, false)
+	return p.afterWrite(ctx)
 }
 
 // Get retrieves a vector by ID.
@@ -111,9 +223,10 @@ func (p *Provider) Get(ctx context.Context, id uuid.UUID) ([]float32, *grub.Vect
 		nil,
 		expr,
 		[]string{p.config.IDField, p.config.VectorField, p.config.MetadataField},
+		p.readOptions()...,
 	)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, shared.CtxErr(ctx, err)
 	}
 
 	if len(results) == 0 {
@@ -170,8 +283,7 @@ func (p *Provider) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	// Flush to make deletion immediately visible
-	return p.client.Flush(ctx, p.config.Collection, false)
+	return p.afterWrite(ctx)
 }
 
 // DeleteBatch removes multiple vectors by ID.
@@ -190,12 +302,21 @@ func (p *Provider) DeleteBatch(ctx context.Context, ids []uuid.UUID) error {
 		return err
 	}
 
-	// Flush to make deletion immediately visible
-	return p.client.Flush(ctx, p.config.Collection, false)
+	return p.afterWrite(ctx)
 }
 
 // Search performs similarity search and returns the k nearest neighbors.
 func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter map[string]any) ([]grub.VectorResult, error) {
+	return p.SearchPage(ctx, vector, k, 0, filter)
+}
+
+// SearchPage performs similarity search, skipping the first offset results.
+// Milvus requires offset + k to stay below 16384.
+func (p *Provider) SearchPage(ctx context.Context, vector []float32, k, offset int, filter map[string]any) ([]grub.VectorResult, error) {
+	opts, err := searchPageOptions(k, offset)
+	if err != nil {
+		return nil, err
+	}
 	sp, _ := entity.NewIndexFlatSearchParam()
 
 	var expr string
@@ -208,55 +329,49 @@ func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter m
 		p.config.Collection,
 		nil,
 		expr,
-		[]string{p.config.IDField, p.config.MetadataField},
+		p.searchOutputFields(),
 		[]entity.Vector{entity.FloatVector(vector)},
 		p.config.VectorField,
-		entity.L2,
+		p.metricType(),
 		k,
 		sp,
+		p.readOptions(opts...)...,
 	)
 	if err != nil {
-		return nil, err
+		return nil, shared.CtxErr(ctx, err)
 	}
 
 	if len(results) == 0 {
 		return nil, nil
 	}
 
-	result := results[0]
-	vectorResults := make([]grub.VectorResult, result.ResultCount)
-
-	for i := 0; i < result.ResultCount; i++ {
-		var id uuid.UUID
-		var metadata []byte
-
-		// Get ID
-		if idCol := result.Fields.GetColumn(p.config.IDField); idCol != nil {
-			if vc, ok := idCol.(*entity.ColumnVarChar); ok {
-				idStr, _ := vc.ValueByIdx(i)
-				id, _ = uuid.Parse(idStr)
-			}
-		}
-
-		// Get metadata
-		if metaCol := result.Fields.GetColumn(p.config.MetadataField); metaCol != nil {
-			if jc, ok := metaCol.(*entity.ColumnJSONBytes); ok {
-				metadata, _ = jc.ValueByIdx(i)
-			}
-		}
+	return p.searchResults(results[0]), nil
+}
 
-		vectorResults[i] = grub.VectorResult{
-			ID:       id,
-			Metadata: metadata,
-			Score:    result.Scores[i],
-		}
+// searchPageOptions returns the search options for a page of k results
+// starting at offset.
+func searchPageOptions(k, offset int) ([]client.SearchQueryOptionFunc, error) {
+	if offset <= 0 {
+		return nil, nil
 	}
-
-	return vectorResults, nil
+	if offset+k >= maxOffsetPlusLimit {
+		return nil, fmt.Errorf("%w: milvus pagination limit (offset=%d + limit=%d >= %d)", grub.ErrScanLimitExceeded, offset, k, maxOffsetPlusLimit)
+	}
+	return []client.SearchQueryOptionFunc{client.WithOffset(int64(offset))}, nil
 }
 
 // Query performs similarity search with vecna filter support.
 func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	return p.QueryPage(ctx, vector, k, 0, filter)
+}
+
+// QueryPage performs similarity search with vecna filter support, skipping
+// the first offset results. Milvus requires offset + k to stay below 16384.
+func (p *Provider) QueryPage(ctx context.Context, vector []float32, k, offset int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	opts, err := searchPageOptions(k, offset)
+	if err != nil {
+		return nil, err
+	}
 	sp, _ := entity.NewIndexFlatSearchParam()
 
 	expr, err := translateFilter(filter, p.config.MetadataField)
@@ -269,22 +384,107 @@ func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *v
 		p.config.Collection,
 		nil,
 		expr,
-		[]string{p.config.IDField, p.config.MetadataField},
+		p.searchOutputFields(),
 		[]entity.Vector{entity.FloatVector(vector)},
 		p.config.VectorField,
-		entity.L2,
+		p.metricType(),
 		k,
 		sp,
+		p.readOptions(opts...)...,
 	)
 	if err != nil {
-		return nil, err
+		return nil, shared.CtxErr(ctx, err)
 	}
 
 	if len(results) == 0 {
 		return nil, nil
 	}
 
-	result := results[0]
+	return p.searchResults(results[0]), nil
+}
+
+// SearchBatch runs one similarity search per query in a single Search call,
+// which accepts many vectors and returns one result set per vector.
+func (p *Provider) SearchBatch(ctx context.Context, queries [][]float32, k int, filter *vecna.Filter) ([][]grub.VectorResult, error) {
+	sp, _ := entity.NewIndexFlatSearchParam()
+
+	expr, err := translateFilter(filter, p.config.MetadataField)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make([]entity.Vector, len(queries))
+	for i, vector := range queries {
+		vectors[i] = entity.FloatVector(vector)
+	}
+
+	results, err := p.client.Search(
+		ctx,
+		p.config.Collection,
+		nil,
+		expr,
+		p.searchOutputFields(),
+		vectors,
+		p.config.VectorField,
+		p.metricType(),
+		k,
+		sp,
+		p.readOptions()...,
+	)
+	if err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+
+	batches := make([][]grub.VectorResult, len(queries))
+	for i := range batches {
+		if i >= len(results) {
+			batches[i] = []grub.VectorResult{}
+			continue
+		}
+		if results[i].Err != nil {
+			return nil, &grub.BatchQueryError{Index: i, Err: results[i].Err}
+		}
+		batches[i] = p.searchResults(results[i])
+	}
+	return batches, nil
+}
+
+// ScoreKind reports how search scores are measured for Config.Metric.
+// It implements grub.VectorScorer.
+func (p *Provider) ScoreKind() grub.ScoreKind {
+	switch metric := p.config.Metric; metric {
+	case grub.DistanceL2:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreSquaredDistance}
+	case grub.DistanceCosine, grub.DistanceInnerProduct:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreSimilarity}
+	}
+	return grub.ScoreKind{}
+}
+
+// metricType maps Config.Metric to its Milvus metric type. Unknown metrics
+// are passed through for Milvus to reject.
+func (p *Provider) metricType() entity.MetricType {
+	switch p.config.Metric {
+	case grub.DistanceL2:
+		return entity.L2
+	case grub.DistanceCosine:
+		return entity.COSINE
+	case grub.DistanceInnerProduct:
+		return entity.IP
+	}
+	return entity.MetricType(p.config.Metric)
+}
+
+// searchOutputFields lists the fields returned with each search hit.
+func (p *Provider) searchOutputFields() []string {
+	if p.config.ReturnVectors {
+		return []string{p.config.IDField, p.config.MetadataField, p.config.VectorField}
+	}
+	return []string{p.config.IDField, p.config.MetadataField}
+}
+
+// searchResults converts one Milvus search result set to grub results.
+func (p *Provider) searchResults(result client.SearchResult) []grub.VectorResult {
 	vectorResults := make([]grub.VectorResult, result.ResultCount)
 
 	for i := 0; i < result.ResultCount; i++ {
@@ -306,20 +506,29 @@ func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *v
 			}
 		}
 
+		// Get vector, present only with ReturnVectors
+		var vec []float32
+		if vecCol := result.Fields.GetColumn(p.config.VectorField); vecCol != nil {
+			if fc, ok := vecCol.(*entity.ColumnFloatVector); ok && i < fc.Len() {
+				vec = fc.Data()[i]
+			}
+		}
+
 		vectorResults[i] = grub.VectorResult{
 			ID:       id,
+			Vector:   vec,
 			Metadata: metadata,
 			Score:    result.Scores[i],
 		}
 	}
 
-	return vectorResults, nil
+	return vectorResults
 }
 
 // Filter returns vectors matching the metadata filter without similarity search.
 // Uses Query API with expression. Paginates when limit=0.
 // Note: Due to Milvus SDK limitations (offset+limit < 16384), this method cannot
-// return more than ~16000 results. Returns an error if the limit would be exceeded.
+// return more than ~16000 results. Returns grub.ErrScanLimitExceeded if the limit would be exceeded.
 func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int) ([]grub.VectorResult, error) {
 	expr, err := translateFilter(filter, p.config.MetadataField)
 	if err != nil {
@@ -331,6 +540,9 @@ func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int)
 	offset := int64(0)
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		fetchLimit := int64(batchSize)
 		if limit > 0 {
 			remaining := int64(limit) - int64(len(allResults))
@@ -344,7 +556,7 @@ func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int)
 
 		// Milvus constraint: offset + limit must be < 16384
 		if offset+fetchLimit >= maxOffsetPlusLimit {
-			return nil, fmt.Errorf("milvus: pagination limit exceeded (offset=%d + limit=%d >= %d)", offset, fetchLimit, maxOffsetPlusLimit)
+			return nil, fmt.Errorf("%w: milvus pagination limit (offset=%d + limit=%d >= %d)", grub.ErrScanLimitExceeded, offset, fetchLimit, maxOffsetPlusLimit)
 		}
 
 		opts := []client.SearchQueryOptionFunc{
@@ -358,10 +570,10 @@ func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int)
 			nil,
 			expr,
 			[]string{p.config.IDField, p.config.VectorField, p.config.MetadataField},
-			opts...,
+			p.readOptions(opts...)...,
 		)
 		if err != nil {
-			return nil, err
+			return nil, shared.CtxErr(ctx, err)
 		}
 
 		batch, err := p.parseQueryResults(results)
@@ -442,13 +654,16 @@ func (p *Provider) parseQueryResults(results []entity.Column) ([]grub.VectorResu
 
 // List returns vector IDs. Paginates when limit=0.
 // Note: Due to Milvus SDK limitations (offset+limit < 16384), this method cannot
-// return more than ~16000 IDs. Returns an error if the limit would be exceeded.
+// return more than ~16000 IDs. Returns grub.ErrScanLimitExceeded if the limit would be exceeded.
 func (p *Provider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
 	const batchSize = 1000
 	var allIDs []uuid.UUID
 	offset := int64(0)
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		fetchLimit := int64(batchSize)
 		if limit > 0 {
 			remaining := int64(limit) - int64(len(allIDs))
@@ -462,7 +677,7 @@ func (p *Provider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
 
 		// Milvus constraint: offset + limit must be < 16384
 		if offset+fetchLimit >= maxOffsetPlusLimit {
-			return nil, fmt.Errorf("milvus: pagination limit exceeded (offset=%d + limit=%d >= %d)", offset, fetchLimit, maxOffsetPlusLimit)
+			return nil, fmt.Errorf("%w: milvus pagination limit (offset=%d + limit=%d >= %d)", grub.ErrScanLimitExceeded, offset, fetchLimit, maxOffsetPlusLimit)
 		}
 
 		opts := []client.SearchQueryOptionFunc{
@@ -470,9 +685,9 @@ func (p *Provider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
 			client.WithOffset(offset),
 		}
 
-		results, err := p.client.Query(ctx, p.config.Collection, nil, "", []string{p.config.IDField}, opts...)
+		results, err := p.client.Query(ctx, p.config.Collection, nil, "", []string{p.config.IDField}, p.readOptions(opts...)...)
 		if err != nil {
-			return nil, err
+			return nil, shared.CtxErr(ctx, err)
 		}
 
 		var batchIDs []uuid.UUID
@@ -520,10 +735,10 @@ func (p *Provider) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
 		nil,
 		expr,
 		[]string{p.config.IDField},
-		client.WithLimit(1),
+		p.readOptions(client.WithLimit(1))...,
 	)
 	if err != nil {
-		return false, err
+		return false, shared.CtxErr(ctx, err)
 	}
 
 	for _, col := range results {
@@ -1,7 +1,15 @@
 package milvus
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"testing"
+
+	"github.com/google/uuid"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/zoobzio/grub"
 )
 
 func TestNew(t *testing.T) {
@@ -26,4 +34,275 @@ func TestConfig_Defaults(t *testing.T) {
 	if p.config.MetadataField != "metadata" {
 		t.Errorf("expected MetadataField default 'metadata', got %q", p.config.MetadataField)
 	}
+	if p.config.Metric != grub.DistanceL2 {
+		t.Errorf("expected Metric default l2, got %q", p.config.Metric)
+	}
+}
+
+func TestScoreKind(t *testing.T) {
+	for metric, want := range map[grub.DistanceMetric]struct {
+		kind   grub.ScoreKind
+		metric entity.MetricType
+	}{
+		"":                        {grub.ScoreKind{Metric: grub.DistanceL2, Measure: grub.ScoreSquaredDistance}, entity.L2},
+		grub.DistanceCosine:       {grub.ScoreKind{Metric: grub.DistanceCosine, Measure: grub.ScoreSimilarity}, entity.COSINE},
+		grub.DistanceInnerProduct: {grub.ScoreKind{Metric: grub.DistanceInnerProduct, Measure: grub.ScoreSimilarity}, entity.IP},
+	} {
+		p := New(nil, Config{Collection: "test", Metric: metric})
+		if got := p.ScoreKind(); got != want.kind {
+			t.Errorf("%q: expected %+v, got %+v", metric, want.kind, got)
+		}
+		if got := p.metricType(); got != want.metric {
+			t.Errorf("%q: expected metric type %s, got %s", metric, want.metric, got)
+		}
+	}
+}
+
+func TestSearchPageOptions(t *testing.T) {
+	if opts, err := searchPageOptions(10, 0); err != nil || opts != nil {
+		t.Errorf("expected no options for offset 0, got %v, %v", opts, err)
+	}
+	if opts, err := searchPageOptions(10, 20); err != nil || len(opts) != 1 {
+		t.Errorf("expected an offset option, got %v, %v", opts, err)
+	}
+	if _, err := searchPageOptions(10, maxOffsetPlusLimit-10); !errors.Is(err, grub.ErrScanLimitExceeded) {
+		t.Errorf("expected ErrScanLimitExceeded when offset + k reaches the Milvus cap, got %v", err)
+	}
+}
+
+func TestSearchOutputFields(t *testing.T) {
+	p := New(nil, Config{Collection: "test"})
+	if got := p.searchOutputFields(); !slices.Equal(got, []string{"id", "metadata"}) {
+		t.Errorf("expected vectors omitted by default, got %v", got)
+	}
+
+	p = New(nil, Config{Collection: "test", ReturnVectors: true})
+	if got := p.searchOutputFields(); !slices.Equal(got, []string{"id", "metadata", "embedding"}) {
+		t.Errorf("expected vector field requested, got %v", got)
+	}
+}
+
+func TestSearchResults_Vectors(t *testing.T) {
+	p := New(nil, Config{Collection: "test", ReturnVectors: true})
+	id := uuid.New()
+	result := client.SearchResult{
+		ResultCount: 1,
+		Scores:      []float32{0.5},
+		Fields: client.ResultSet{
+			entity.NewColumnVarChar("id", []string{id.String()}),
+			entity.NewColumnJSONBytes("metadata", [][]byte{[]byte(`{"a":1}`)}),
+			entity.NewColumnFloatVector("embedding", 2, [][]float32{{0.1, 0.2}}),
+		},
+	}
+
+	results := p.searchResults(result)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].ID != id {
+		t.Errorf("expected ID %s, got %s", id, results[0].ID)
+	}
+	if !slices.Equal(results[0].Vector, []float32{0.1, 0.2}) {
+		t.Errorf("expected vector parsed, got %v", results[0].Vector)
+	}
+
+	result.Fields = result.Fields[:2]
+	if results := p.searchResults(result); results[0].Vector != nil {
+		t.Errorf("expected nil vector without the vector column, got %v", results[0].Vector)
+	}
+}
+
+// writeClient records the writes, flushes, and query options a Provider
+// sends. Methods it does not override panic through the nil client.Client.
+type writeClient struct {
+	client.Client
+	upserts, deletes, flushes int
+	queryOpts                 client.SearchQueryOption
+}
+
+func (c *writeClient) Upsert(context.Context, string, string, ...entity.Column) (entity.Column, error) {
+	c.upserts++
+	return nil, nil
+}
+
+func (c *writeClient) Delete(context.Context, string, string, string) error {
+	c.deletes++
+	return nil
+}
+
+func (c *writeClient) Flush(context.Context, string, bool, ...client.FlushOption) error {
+	c.flushes++
+	return nil
+}
+
+func (c *writeClient) Query(_ context.Context, _ string, _ []string, _ string, _ []string, opts ...client.SearchQueryOptionFunc) (client.ResultSet, error) {
+	c.queryOpts = client.SearchQueryOption{ConsistencyLevel: entity.ClBounded}
+	for _, opt := range opts {
+		opt(&c.queryOpts)
+	}
+	return nil, nil
+}
+
+func TestConsistencyMode(t *testing.T) {
+	ctx := context.Background()
+	records := []grub.VectorRecord{{ID: uuid.New(), Vector: []float32{1, 2}}, {ID: uuid.New(), Vector: []float32{3, 4}}}
+
+	for _, tt := range []struct {
+		name      string
+		mode      ConsistencyMode
+		flushes   int
+		readLevel entity.ConsistencyLevel
+	}{
+		{"immediate", ConsistencyImmediate, 3, entity.ClBounded},
+		{"eventual", ConsistencyEventual, 0, entity.ClEventually},
+		{"manual", ConsistencyManual, 0, entity.ClBounded},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &writeClient{}
+			p := New(c, Config{Collection: "test", ConsistencyMode: tt.mode, ReadConsistency: entity.ClEventually})
+
+			if err := p.Upsert(ctx, uuid.New(), []float32{1, 2}, nil); err != nil {
+				t.Fatalf("Upsert failed: %v", err)
+			}
+			if err := p.UpsertBatch(ctx, records); err != nil {
+				t.Fatalf("UpsertBatch failed: %v", err)
+			}
+			if err := p.DeleteBatch(ctx, []uuid.UUID{records[0].ID}); err != nil {
+				t.Fatalf("DeleteBatch failed: %v", err)
+			}
+			if c.upserts != 2 || c.deletes != 1 || c.flushes != tt.flushes {
+				t.Errorf("expected 2 upserts, 1 delete, and %d flushes, got %d, %d, and %d", tt.flushes, c.upserts, c.deletes, c.flushes)
+			}
+
+			if err := p.Flush(ctx); err != nil || c.flushes != tt.flushes+1 {
+				t.Errorf("expected Flush to flush once, got %d flushes, %v", c.flushes-tt.flushes, err)
+			}
+
+			if _, err := p.Exists(ctx, uuid.New()); err != nil {
+				t.Fatalf("Exists failed: %v", err)
+			}
+			if c.queryOpts.ConsistencyLevel != tt.readLevel {
+				t.Errorf("expected reads at level %v, got %v", tt.readLevel, c.queryOpts.ConsistencyLevel)
+			}
+			if c.queryOpts.Limit != 1 {
+				t.Errorf("expected the query's own options to be kept, got limit %d", c.queryOpts.Limit)
+			}
+		})
+	}
+}
+
+// pageClient serves full pages of IDs, cancelling the caller's context after
+// the first page. Once cancelled, queries fail with a transport error, as
+// gRPC does.
+type pageClient struct {
+	client.Client
+	cancel  context.CancelFunc
+	queries int
+}
+
+func (c *pageClient) Query(ctx context.Context, _ string, _ []string, _ string, _ []string, opts ...client.SearchQueryOptionFunc) (client.ResultSet, error) {
+	c.queries++
+	if ctx.Err() != nil {
+		return nil, errors.New("rpc error: code = Canceled desc = context canceled")
+	}
+	var o client.SearchQueryOption
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ids := make([]string, o.Limit)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	c.cancel()
+	return client.ResultSet{entity.NewColumnVarChar("id", ids)}, nil
+}
+
+func TestCancellation(t *testing.T) {
+	t.Run("between pages", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c := &pageClient{cancel: cancel}
+		p := New(c, Config{Collection: "test"})
+
+		if _, err := p.List(ctx, 0); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		if c.queries != 1 {
+			t.Errorf("expected List to stop after the first page, got %d queries", c.queries)
+		}
+	})
+
+	t.Run("in flight", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		p := New(&pageClient{cancel: cancel}, Config{Collection: "test"})
+
+		if _, _, err := p.Get(ctx, uuid.New()); !errors.Is(err, context.Canceled) {
+			t.Errorf("Get: expected context.Canceled, got %v", err)
+		}
+		if _, err := p.Exists(ctx, uuid.New()); !errors.Is(err, context.Canceled) {
+			t.Errorf("Exists: expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+// collectionClient records the schema, index, and load calls EnsureCollection
+// makes against a collection that exists once created.
+type collectionClient struct {
+	client.Client
+	schema *entity.Schema
+	index  entity.Index
+	field  string
+	loads  int
+}
+
+func (c *collectionClient) HasCollection(context.Context, string) (bool, error) {
+	return c.schema != nil, nil
+}
+
+func (c *collectionClient) CreateCollection(_ context.Context, schema *entity.Schema, _ int32, _ ...client.CreateCollectionOption) error {
+	c.schema = schema
+	return nil
+}
+
+func (c *collectionClient) CreateIndex(_ context.Context, _, field string, idx entity.Index, _ bool, _ ...client.IndexOption) error {
+	c.field, c.index = field, idx
+	return nil
+}
+
+func (c *collectionClient) LoadCollection(context.Context, string, bool, ...client.LoadCollectionOption) error {
+	c.loads++
+	return nil
+}
+
+func TestEnsureCollection(t *testing.T) {
+	ctx := context.Background()
+	c := &collectionClient{}
+	p := New(c, Config{Collection: "docs", Dimension: 3, Metric: grub.DistanceCosine})
+
+	for range 2 {
+		if err := p.EnsureCollection(ctx); err != nil {
+			t.Fatalf("EnsureCollection failed: %v", err)
+		}
+	}
+	if c.loads != 1 {
+		t.Errorf("expected the collection to be created and loaded once, got %d loads", c.loads)
+	}
+	var names []string
+	for _, f := range c.schema.Fields {
+		names = append(names, f.Name)
+		if f.DataType == entity.FieldTypeFloatVector && f.TypeParams[entity.TypeParamDim] != "3" {
+			t.Errorf("expected dimension 3, got %v", f.TypeParams)
+		}
+	}
+	if c.schema.CollectionName != "docs" || !slices.Equal(names, []string{"id", "embedding", "metadata"}) {
+		t.Errorf("unexpected schema %q %v", c.schema.CollectionName, names)
+	}
+	if c.field != "embedding" || c.index.Params()["metric_type"] != string(entity.COSINE) {
+		t.Errorf("expected a cosine index on embedding, got %q %v", c.field, c.index.Params())
+	}
+
+	if err := New(c, Config{Collection: "docs"}).EnsureCollection(ctx); err == nil {
+		t.Error("expected an error without a dimension")
+	}
 }
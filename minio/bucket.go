@@ -4,9 +4,14 @@ package minio
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
 	"github.com/zoobzio/grub"
 )
 
@@ -16,6 +21,12 @@ type Provider struct {
 	bucket string
 }
 
+// ExpiryTag is the object tag Put sets from ObjectInfo.ExpiresAt to the
+// whole days, rounded up, until the object should expire. MinIO only
+// deletes objects by lifecycle rule, so the bucket needs a rule for every
+// day count in use; ExpiryRules builds them.
+const ExpiryTag = "grub-expire-days"
+
 // New creates a MinIO provider with the given client and bucket name.
 func New(client *minio.Client, bucket string) *Provider {
 	return &Provider{
@@ -58,7 +69,15 @@ func (p *Provider) Get(ctx context.Context, key string) ([]byte, *grub.ObjectInf
 
 // Put stores data at key with associated metadata.
 func (p *Provider) Put(ctx context.Context, key string, data []byte, info *grub.ObjectInfo) error {
+	_, err := p.PutInfo(ctx, key, data, info)
+	return err
+}
+
+// PutInfo stores data at key and returns the ETag, version ID, and
+// last-modified time from the upload response.
+func (p *Provider) PutInfo(ctx context.Context, key string, data []byte, info *grub.ObjectInfo) (*grub.ObjectInfo, error) {
 	opts := minio.PutObjectOptions{}
+	result := &grub.ObjectInfo{Key: key}
 	if info != nil {
 		if info.ContentType != "" {
 			opts.ContentType = info.ContentType
@@ -66,8 +85,188 @@ func (p *Provider) Put(ctx context.Context, key string, data []byte, info *grub.
 		if len(info.Metadata) > 0 {
 			opts.UserMetadata = info.Metadata
 		}
+		if !info.ExpiresAt.IsZero() {
+			opts.UserTags = map[string]string{ExpiryTag: strconv.Itoa(expiryDays(info.ExpiresAt))}
+		}
+		result.ContentType = info.ContentType
+		result.Metadata = info.Metadata
+		result.ExpiresAt = info.ExpiresAt
+	}
+	upload, err := p.client.PutObject(ctx, p.bucket, key, bytes.NewReader(data), int64(len(data)), opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Size = upload.Size
+	result.ETag = upload.ETag
+	result.VersionID = upload.VersionID
+	result.LastModified = upload.LastModified
+	return result, nil
+}
+
+// ExpiryPrecision implements grub.BucketExpirer. Lifecycle rules count
+// whole days and the scanner applies them in the background, so an object
+// may outlive its ExpiresAt by up to two days.
+func (p *Provider) ExpiryPrecision() time.Duration {
+	return 48 * time.Hour
+}
+
+// ExpiryRules returns the lifecycle rules deleting objects tagged by Put
+// with ExpiresAt up to maxDays away, one rule per day count. Add them to the
+// bucket's lifecycle configuration; objects expiring further out are kept
+// until a rule for their day count exists.
+func ExpiryRules(maxDays int) []lifecycle.Rule {
+	rules := make([]lifecycle.Rule, 0, maxDays)
+	for days := 1; days <= maxDays; days++ {
+		rules = append(rules, lifecycle.Rule{
+			ID:         fmt.Sprintf("%s-%d", ExpiryTag, days),
+			Status:     "Enabled",
+			RuleFilter: lifecycle.Filter{Tag: lifecycle.Tag{Key: ExpiryTag, Value: strconv.Itoa(days)}},
+			Expiration: lifecycle.Expiration{Days: lifecycle.ExpirationDays(days)},
+		})
+	}
+	return rules
+}
+
+// expiryDays returns the whole days, at least one, from now until at.
+func expiryDays(at time.Time) int {
+	return max(int(math.Ceil(time.Until(at).Hours()/24)), 1)
+}
+
+// ListVersions returns every version of key, including delete markers,
+// newest first. The bucket must have versioning enabled.
+func (p *Provider) ListVersions(ctx context.Context, key string) ([]grub.ObjectVersion, error) {
+	var results []grub.ObjectVersion
+
+	opts := minio.ListObjectsOptions{
+		Prefix:       key,
+		Recursive:    true,
+		WithVersions: true,
+	}
+
+	for obj := range p.client.ListObjects(ctx, p.bucket, opts) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		// Prefix also matches longer keys; keep only exact matches.
+		if obj.Key != key {
+			continue
+		}
+		results = append(results, grub.ObjectVersion{
+			Key:            obj.Key,
+			VersionID:      obj.VersionID,
+			Size:           obj.Size,
+			ETag:           obj.ETag,
+			LastModified:   obj.LastModified,
+			IsLatest:       obj.IsLatest,
+			IsDeleteMarker: obj.IsDeleteMarker,
+		})
+	}
+
+	if len(results) == 0 {
+		return nil, grub.ErrNotFound
+	}
+	return results, nil
+}
+
+// GetVersion retrieves a specific version of the blob at key.
+func (p *Provider) GetVersion(ctx context.Context, key, versionID string) ([]byte, *grub.ObjectInfo, error) {
+	obj, err := p.client.GetObject(ctx, p.bucket, key, minio.GetObjectOptions{VersionID: versionID})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = obj.Close() }()
+
+	stat, err := obj.Stat()
+	if err != nil {
+		switch minio.ToErrorResponse(err).Code {
+		case "NoSuchKey", "NoSuchVersion":
+			return nil, nil, grub.ErrNotFound
+		}
+		return nil, nil, err
+	}
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &grub.ObjectInfo{
+		Key:          key,
+		Size:         stat.Size,
+		ContentType:  stat.ContentType,
+		ETag:         stat.ETag,
+		Metadata:     stat.UserMetadata,
+		VersionID:    stat.VersionID,
+		LastModified: stat.LastModified,
+	}
+
+	return data, info, nil
+}
+
+// CreateMultipartUpload starts a multipart upload to key.
+func (p *Provider) CreateMultipartUpload(ctx context.Context, key string, info *grub.ObjectInfo) (string, error) {
+	opts := minio.PutObjectOptions{}
+	if info != nil {
+		opts.ContentType = info.ContentType
+		if len(info.Metadata) > 0 {
+			opts.UserMetadata = info.Metadata
+		}
+	}
+	return p.core().NewMultipartUpload(ctx, p.bucket, key, opts)
+}
+
+// UploadPart uploads part n of an upload. The part is buffered in memory
+// because the MinIO API needs its size up front.
+func (p *Provider) UploadPart(ctx context.Context, key, uploadID string, n int, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	part, err := p.core().PutObjectPart(ctx, p.bucket, key, uploadID, n,
+		bytes.NewReader(data), int64(len(data)), minio.PutObjectPartOptions{})
+	if err != nil {
+		return "", mapUploadErr(err)
+	}
+	return part.ETag, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the object.
+func (p *Provider) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []grub.CompletedPart) (*grub.ObjectInfo, error) {
+	completed := make([]minio.CompletePart, len(parts))
+	for i, part := range parts {
+		completed[i] = minio.CompletePart{PartNumber: part.Number, ETag: part.ETag}
+	}
+	upload, err := p.core().CompleteMultipartUpload(ctx, p.bucket, key, uploadID, completed, minio.PutObjectOptions{})
+	if err != nil {
+		return nil, mapUploadErr(err)
+	}
+	return &grub.ObjectInfo{
+		Key:          key,
+		Size:         upload.Size,
+		ETag:         upload.ETag,
+		VersionID:    upload.VersionID,
+		LastModified: upload.LastModified,
+	}, nil
+}
+
+// AbortMultipartUpload discards an upload and its stored parts.
+func (p *Provider) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	return mapUploadErr(p.core().AbortMultipartUpload(ctx, p.bucket, key, uploadID))
+}
+
+// core exposes the low-level MinIO API needed for multipart uploads.
+func (p *Provider) core() minio.Core {
+	return minio.Core{Client: p.client}
+}
+
+// mapUploadErr translates a missing multipart upload to grub.ErrNotFound.
+func mapUploadErr(err error) error {
+	if err != nil && minio.ToErrorResponse(err).Code == "NoSuchUpload" {
+		return grub.ErrNotFound
 	}
-	_, err := p.client.PutObject(ctx, p.bucket, key, bytes.NewReader(data), int64(len(data)), opts)
 	return err
 }
 
@@ -84,6 +283,56 @@ func (p *Provider) Delete(ctx context.Context, key string) error {
 	return p.client.RemoveObject(ctx, p.bucket, key, minio.RemoveObjectOptions{})
 }
 
+// DeletePrefix removes every object under prefix, streaming the listing
+// into RemoveObjects, which deletes in multi-object batches of up to 1000.
+// Cancelling ctx stops both the listing and the deletion; the count then
+// may include objects queued for a batch that was never sent.
+func (p *Provider) DeletePrefix(parent context.Context, prefix string) (int64, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var listed int64
+	var listErr error
+	objects := make(chan minio.ObjectInfo)
+	listDone := make(chan struct{})
+	go func() {
+		defer close(listDone)
+		defer close(objects)
+		opts := minio.ListObjectsOptions{Prefix: prefix, Recursive: true}
+		for obj := range p.client.ListObjects(ctx, p.bucket, opts) {
+			if obj.Err != nil {
+				listErr = obj.Err
+				return
+			}
+			select {
+			case objects <- obj:
+				listed++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var failed int64
+	var removeErr error
+	for rerr := range p.client.RemoveObjects(ctx, p.bucket, objects, minio.RemoveObjectsOptions{}) {
+		failed++
+		if removeErr == nil {
+			removeErr = fmt.Errorf("minio: deleting %q: %w", rerr.ObjectName, rerr.Err)
+		}
+	}
+	cancel() // RemoveObjects may stop reading early on error.
+	<-listDone
+	deleted := listed - failed
+	switch {
+	case removeErr != nil:
+		return deleted, removeErr
+	case listErr != nil:
+		return deleted, listErr
+	}
+	return deleted, parent.Err()
+}
+
 // Exists checks whether a key exists.
 func (p *Provider) Exists(ctx context.Context, key string) (bool, error) {
 	_, err := p.client.StatObject(ctx, p.bucket, key, minio.StatObjectOptions{})
@@ -96,6 +345,27 @@ func (p *Provider) Exists(ctx context.Context, key string) (bool, error) {
 	return true, nil
 }
 
+// Head returns the info for key, including its user metadata, without
+// downloading the body.
+func (p *Provider) Head(ctx context.Context, key string) (*grub.ObjectInfo, error) {
+	stat, err := p.client.StatObject(ctx, p.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, grub.ErrNotFound
+		}
+		return nil, err
+	}
+	return &grub.ObjectInfo{
+		Key:          key,
+		Size:         stat.Size,
+		ContentType:  stat.ContentType,
+		ETag:         stat.ETag,
+		Metadata:     stat.UserMetadata,
+		VersionID:    stat.VersionID,
+		LastModified: stat.LastModified,
+	}, nil
+}
+
 // List returns object info for keys matching the given prefix.
 func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.ObjectInfo, error) {
 	var results []grub.ObjectInfo
@@ -106,14 +376,13 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.O
 	}
 
 	for obj := range p.client.ListObjects(ctx, p.bucket, opts) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if obj.Err != nil {
 			return nil, obj.Err
 		}
-		results = append(results, grub.ObjectInfo{
-			Key:  obj.Key,
-			Size: obj.Size,
-			ETag: obj.ETag,
-		})
+		results = append(results, objectInfo(obj))
 		if limit > 0 && len(results) >= limit {
 			break
 		}
@@ -121,3 +390,47 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.O
 
 	return results, nil
 }
+
+// ListPage returns up to limit objects under prefix, listed in key order
+// after the cursor key. The cursor is the last key of the previous page,
+// passed to MinIO as StartAfter. A limit of 0 or less means 1000.
+func (p *Provider) ListPage(ctx context.Context, prefix string, limit int, cursor string) ([]grub.ObjectInfo, string, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	// Cancelling stops the listing goroutine once the page is full.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	opts := minio.ListObjectsOptions{
+		Prefix:     prefix,
+		Recursive:  true,
+		StartAfter: cursor,
+	}
+	var results []grub.ObjectInfo
+	for obj := range p.client.ListObjects(ctx, p.bucket, opts) {
+		if obj.Err != nil {
+			return nil, "", obj.Err
+		}
+		if len(results) == limit {
+			// Another object follows, so the page is not the last.
+			return results, results[limit-1].Key, nil
+		}
+		results = append(results, objectInfo(obj))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	return results, "", nil
+}
+
+// objectInfo converts a listed object. Listings carry no content type or
+// user metadata; those need a StatObject per key.
+func objectInfo(obj minio.ObjectInfo) grub.ObjectInfo {
+	return grub.ObjectInfo{
+		Key:          obj.Key,
+		Size:         obj.Size,
+		ETag:         obj.ETag,
+		LastModified: obj.LastModified,
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/zoobzio/grub"
@@ -175,6 +177,148 @@ func TestProvider_Put(t *testing.T) {
 	})
 }
 
+func TestProvider_PutInfo(t *testing.T) {
+	clearBucket(t)
+	ctx := context.Background()
+
+	data := []byte("receipt content")
+	info, err := testProvider.PutInfo(ctx, "put-info", data, &grub.ObjectInfo{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("PutInfo failed: %v", err)
+	}
+	if info.Key != "put-info" {
+		t.Errorf("unexpected key: %q", info.Key)
+	}
+	if info.Size != int64(len(data)) {
+		t.Errorf("expected size %d, got %d", len(data), info.Size)
+	}
+	if info.ETag == "" {
+		t.Error("expected ETag to be reported")
+	}
+	if info.LastModified.IsZero() {
+		t.Error("expected LastModified to be reported")
+	}
+}
+
+func TestProvider_PutExpiresAt(t *testing.T) {
+	clearBucket(t)
+	ctx := context.Background()
+
+	expires := time.Now().Add(36 * time.Hour)
+	if err := testProvider.Put(ctx, "expiring", []byte("tmp"), &grub.ObjectInfo{ExpiresAt: expires}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	objectTags, err := testClient.GetObjectTagging(ctx, testBucket, "expiring", minio.GetObjectTaggingOptions{})
+	if err != nil {
+		t.Fatalf("GetObjectTagging failed: %v", err)
+	}
+	if got := objectTags.ToMap()[ExpiryTag]; got != "2" {
+		t.Errorf("expected %s=2, got %q", ExpiryTag, got)
+	}
+
+	config := lifecycle.NewConfiguration()
+	config.Rules = ExpiryRules(3)
+	if err := testClient.SetBucketLifecycle(ctx, testBucket, config); err != nil {
+		t.Errorf("expected the rules to be accepted, got %v", err)
+	}
+}
+
+func TestProvider_Versions(t *testing.T) {
+	ctx := context.Background()
+	const versionedBucket = "test-versioned"
+
+	if err := testClient.MakeBucket(ctx, versionedBucket, minio.MakeBucketOptions{}); err != nil {
+		t.Fatalf("failed to create bucket: %v", err)
+	}
+	if err := testClient.EnableVersioning(ctx, versionedBucket); err != nil {
+		t.Fatalf("failed to enable versioning: %v", err)
+	}
+	provider := New(testClient, versionedBucket)
+
+	var ids []string
+	for _, body := range []string{"first", "second"} {
+		info, err := provider.PutInfo(ctx, "doc", []byte(body), nil)
+		if err != nil {
+			t.Fatalf("PutInfo failed: %v", err)
+		}
+		ids = append(ids, info.VersionID)
+	}
+
+	versions, err := provider.ListVersions(ctx, "doc")
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	if len(versions) != 2 || !versions[0].IsLatest {
+		t.Fatalf("expected 2 versions newest first, got %+v", versions)
+	}
+
+	data, info, err := provider.GetVersion(ctx, "doc", ids[0])
+	if err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if string(data) != "first" || info.VersionID != ids[0] {
+		t.Errorf("expected first version, got %q (%s)", data, info.VersionID)
+	}
+
+	if _, err := provider.ListVersions(ctx, "missing"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestProvider_MultipartUpload(t *testing.T) {
+	clearBucket(t)
+	ctx := context.Background()
+
+	uploadID, err := testProvider.CreateMultipartUpload(ctx, "multipart", &grub.ObjectInfo{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("CreateMultipartUpload failed: %v", err)
+	}
+
+	// Every part but the last must be at least 5 MiB.
+	first := bytes.Repeat([]byte("a"), 5<<20)
+	etag1, err := testProvider.UploadPart(ctx, "multipart", uploadID, 1, bytes.NewReader(first))
+	if err != nil {
+		t.Fatalf("UploadPart 1 failed: %v", err)
+	}
+	etag2, err := testProvider.UploadPart(ctx, "multipart", uploadID, 2, bytes.NewReader([]byte("tail")))
+	if err != nil {
+		t.Fatalf("UploadPart 2 failed: %v", err)
+	}
+
+	info, err := testProvider.CompleteMultipartUpload(ctx, "multipart", uploadID, []grub.CompletedPart{
+		{Number: 1, ETag: etag1},
+		{Number: 2, ETag: etag2},
+	})
+	if err != nil {
+		t.Fatalf("CompleteMultipartUpload failed: %v", err)
+	}
+	if info.ETag == "" {
+		t.Error("expected ETag to be reported")
+	}
+
+	data, _, err := testProvider.Get(ctx, "multipart")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(data) != len(first)+4 || string(data[len(first):]) != "tail" {
+		t.Errorf("unexpected assembled object of %d bytes", len(data))
+	}
+
+	t.Run("abort", func(t *testing.T) {
+		id, err := testProvider.CreateMultipartUpload(ctx, "aborted", nil)
+		if err != nil {
+			t.Fatalf("CreateMultipartUpload failed: %v", err)
+		}
+		if err := testProvider.AbortMultipartUpload(ctx, "aborted", id); err != nil {
+			t.Fatalf("AbortMultipartUpload failed: %v", err)
+		}
+		_, err = testProvider.CompleteMultipartUpload(ctx, "aborted", id, nil)
+		if !errors.Is(err, grub.ErrNotFound) {
+			t.Errorf("expected ErrNotFound after abort, got %v", err)
+		}
+	})
+}
+
 func TestProvider_Delete(t *testing.T) {
 	clearBucket(t)
 	ctx := context.Background()
@@ -20,13 +20,13 @@ require (
 	github.com/minio/md5-simd v1.1.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rs/xid v1.6.0 // indirect
-	github.com/zoobzio/astql v1.0.5 // indirect
+	github.com/zoobzio/astql v1.0.6 // indirect
 	github.com/zoobzio/atom v1.0.0 // indirect
 	github.com/zoobzio/capitan v1.0.0 // indirect
 	github.com/zoobzio/dbml v1.0.0 // indirect
 	github.com/zoobzio/edamame v1.0.1 // indirect
 	github.com/zoobzio/sentinel v1.0.2 // indirect
-	github.com/zoobzio/soy v1.0.3 // indirect
+	github.com/zoobzio/soy v1.0.5 // indirect
 	github.com/zoobzio/vecna v0.0.2 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
@@ -0,0 +1,134 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/edamame"
+)
+
+// AggregateSpec names one aggregate computed by ExecMultiAggregate.
+type AggregateSpec struct {
+	Alias string                // key in the result map
+	Func  edamame.AggregateFunc // AggCount, AggSum, AggAvg, AggMin, or AggMax
+	Field string                // column; empty with AggCount counts rows
+}
+
+// ExecMultiAggregate computes several aggregates over the rows matching
+// where in a single statement, returning each result under its Alias.
+// Results are float64 and NULL aggregates (no matching rows) are 0, as with
+// ExecAggregate. params are validated like ExecQuery.
+//
+//	stats, err := db.ExecMultiAggregate(ctx, []grub.AggregateSpec{
+//	    {Alias: "n", Func: edamame.AggCount},
+//	    {Alias: "avg_age", Func: edamame.AggAvg, Field: "age"},
+//	}, where, params)
+func (d *Database[T]) ExecMultiAggregate(ctx context.Context, specs []AggregateSpec, where []edamame.ConditionSpec, params map[string]any) (map[string]float64, error) {
+	result, err := read(ctx, d, func(c conn[T]) (map[string]float64, error) {
+		return d.execMultiAggregate(ctx, c.db, specs, where, params)
+	})
+	if err != nil {
+		return nil, d.wrapErr("exec_multi_aggregate", "", err)
+	}
+	return result, nil
+}
+
+// ExecMultiAggregateTx is ExecMultiAggregate within a transaction.
+func (d *Database[T]) ExecMultiAggregateTx(ctx context.Context, tx *sqlx.Tx, specs []AggregateSpec, where []edamame.ConditionSpec, params map[string]any) (map[string]float64, error) {
+	if tx == nil {
+		return nil, d.wrapErr("exec_multi_aggregate_tx", "", ErrNilTransaction)
+	}
+	result, err := d.execMultiAggregate(ctx, tx, specs, where, params)
+	if err != nil {
+		return nil, d.wrapErr("exec_multi_aggregate_tx", "", err)
+	}
+	return result, nil
+}
+
+func (d *Database[T]) execMultiAggregate(ctx context.Context, execer sqlx.ExtContext, specs []AggregateSpec, where []edamame.ConditionSpec, params map[string]any) (map[string]float64, error) {
+	exprs, err := aggregateExprs(specs)
+	if err != nil {
+		return nil, err
+	}
+	stmt := edamame.NewQueryStatement("multi_aggregate", "", edamame.QuerySpec{
+		SelectExprs: exprs,
+		Where:       where,
+	})
+	if err := checkParams(stmt, params, func() (string, error) { return d.executor.RenderQuery(stmt) }); err != nil {
+		return nil, err
+	}
+	query, err := d.executor.RenderQuery(stmt)
+	if err != nil {
+		return nil, err
+	}
+	query, params, _ = expandIn(query, params)
+
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	rows, err := sqlx.NamedQueryContext(callCtx, execer, query, params)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("aggregate query returned no rows")
+	}
+	values := make([]*float64, len(specs))
+	dest := make([]any, len(specs))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]float64, len(specs))
+	for i, spec := range specs {
+		result[spec.Alias] = 0
+		if values[i] != nil {
+			result[spec.Alias] = *values[i]
+		}
+	}
+	return result, nil
+}
+
+// aggregateExprs converts specs to select expressions, rejecting missing or
+// repeated aliases and aggregates other than COUNT without a field.
+func aggregateExprs(specs []AggregateSpec) ([]edamame.SelectExprSpec, error) {
+	if len(specs) == 0 {
+		return nil, errors.New("no aggregates given")
+	}
+	seen := make(map[string]bool, len(specs))
+	exprs := make([]edamame.SelectExprSpec, len(specs))
+	for i, spec := range specs {
+		if spec.Alias == "" {
+			return nil, fmt.Errorf("aggregate %d: alias is required", i)
+		}
+		if seen[spec.Alias] {
+			return nil, fmt.Errorf("aggregate %d: duplicate alias %q", i, spec.Alias)
+		}
+		seen[spec.Alias] = true
+
+		switch spec.Func {
+		case edamame.AggCount:
+			if spec.Field == "" {
+				exprs[i] = edamame.SelectExprSpec{Func: "count_star", Alias: spec.Alias}
+				continue
+			}
+		case edamame.AggSum, edamame.AggAvg, edamame.AggMin, edamame.AggMax:
+			if spec.Field == "" {
+				return nil, fmt.Errorf("aggregate %q: %s requires a field", spec.Alias, spec.Func)
+			}
+		default:
+			return nil, fmt.Errorf("aggregate %q: unknown function %q", spec.Alias, spec.Func)
+		}
+		exprs[i] = edamame.SelectExprSpec{Func: strings.ToLower(string(spec.Func)), Field: spec.Field, Alias: spec.Alias}
+	}
+	return exprs, nil
+}
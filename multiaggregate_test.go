@@ -0,0 +1,70 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestDatabase_ExecMultiAggregate(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	t.Run("single statement", func(t *testing.T) {
+		capture.Reset()
+		// The mock driver returns no rows, so only the SQL is checked.
+		_, _ = db.ExecMultiAggregate(ctx, []AggregateSpec{
+			{Alias: "n", Func: edamame.AggCount},
+			{Alias: "total", Func: edamame.AggSum, Field: "age"},
+			{Alias: "mean", Func: edamame.AggAvg, Field: "age"},
+		}, []edamame.ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}, map[string]any{"min_age": 18})
+
+		if len(capture.Queries) != 1 {
+			t.Fatalf("expected one query, got %d", len(capture.Queries))
+		}
+		query := capture.Queries[0].Query
+		for _, want := range []string{`COUNT(*) AS "n"`, `SUM("age") AS "total"`, `AVG("age") AS "mean"`, `WHERE "age" >= ?`} {
+			if !strings.Contains(query, want) {
+				t.Errorf("expected %q in query, got: %s", want, query)
+			}
+		}
+	})
+
+	t.Run("invalid specs", func(t *testing.T) {
+		cases := map[string][]AggregateSpec{
+			"empty":           nil,
+			"missing alias":   {{Func: edamame.AggCount}},
+			"duplicate alias": {{Alias: "a", Func: edamame.AggCount}, {Alias: "a", Func: edamame.AggMax, Field: "age"}},
+			"missing field":   {{Alias: "a", Func: edamame.AggSum}},
+			"unknown func":    {{Alias: "a", Func: "MEDIAN", Field: "age"}},
+			"unknown field":   {{Alias: "a", Func: edamame.AggMax, Field: "aeg"}},
+		}
+		for name, specs := range cases {
+			capture.Reset()
+			_, err := db.ExecMultiAggregate(ctx, specs, nil, nil)
+			var gErr *Error
+			if !errors.As(err, &gErr) || gErr.Op != "exec_multi_aggregate" {
+				t.Errorf("%s: expected exec_multi_aggregate error, got %v", name, err)
+			}
+			if len(capture.Queries) != 0 {
+				t.Errorf("%s: expected no query", name)
+			}
+		}
+	})
+
+	t.Run("params validated", func(t *testing.T) {
+		_, err := db.ExecMultiAggregate(ctx, []AggregateSpec{{Alias: "n", Func: edamame.AggCount}},
+			[]edamame.ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}}, map[string]any{"minAge": 18})
+		if !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("expected ErrInvalidParams, got %v", err)
+		}
+	})
+}
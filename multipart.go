@@ -0,0 +1,151 @@
+package grub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// maxPartNumber is the highest part number S3-compatible backends accept.
+const maxPartNumber = 10000
+
+// MultipartUpload assembles a large object from separately uploaded parts.
+// Parts carry raw bytes and bypass the Bucket's codec and lifecycle hooks.
+// UploadPart is safe for concurrent use; Complete and Abort end the upload.
+//
+// To resume after a restart, persist Key, UploadID, and Parts, then call
+// Bucket.ResumeMultipartUpload.
+type MultipartUpload struct {
+	uploader MultipartUploader
+	key      string
+	uploadID string
+	timeout  time.Duration
+
+	mu    sync.Mutex
+	parts map[int]string
+}
+
+// NewMultipartUpload starts a multipart upload to key.
+// Returns ErrUnsupported if the provider does not implement MultipartUploader.
+func (b *Bucket[T]) NewMultipartUpload(ctx context.Context, key, contentType string) (*MultipartUpload, error) {
+	if b.readOnly {
+		return nil, shared.WrapError(KindBucket, "create_multipart", "", key, ErrReadOnly)
+	}
+	key, err := b.keys.apply(key)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "create_multipart", "", key, err)
+	}
+	uploader, ok := b.provider.(MultipartUploader)
+	if !ok {
+		return nil, shared.WrapError(KindBucket, "create_multipart", "", key, ErrUnsupported)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, b.timeout)
+	defer cancel()
+	uploadID, err := uploader.CreateMultipartUpload(callCtx, key, &ObjectInfo{Key: key, ContentType: b.contentType(key, contentType, nil)})
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "create_multipart", "", key, err)
+	}
+	return &MultipartUpload{
+		uploader: uploader,
+		key:      key,
+		uploadID: uploadID,
+		timeout:  b.timeout,
+		parts:    make(map[int]string),
+	}, nil
+}
+
+// ResumeMultipartUpload reattaches to an upload started earlier, seeded with
+// the parts already uploaded. Parts not listed must be uploaded again.
+// Returns ErrUnsupported if the provider does not implement MultipartUploader.
+func (b *Bucket[T]) ResumeMultipartUpload(key, uploadID string, parts []CompletedPart) (*MultipartUpload, error) {
+	if b.readOnly {
+		return nil, shared.WrapError(KindBucket, "resume_multipart", "", key, ErrReadOnly)
+	}
+	key, err := b.keys.apply(key)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "resume_multipart", "", key, err)
+	}
+	uploader, ok := b.provider.(MultipartUploader)
+	if !ok {
+		return nil, shared.WrapError(KindBucket, "resume_multipart", "", key, ErrUnsupported)
+	}
+	m := &MultipartUpload{
+		uploader: uploader,
+		key:      key,
+		uploadID: uploadID,
+		timeout:  b.timeout,
+		parts:    make(map[int]string, len(parts)),
+	}
+	for _, p := range parts {
+		m.parts[p.Number] = p.ETag
+	}
+	return m, nil
+}
+
+// Key returns the destination key.
+func (m *MultipartUpload) Key() string {
+	return m.key
+}
+
+// UploadID returns the provider's identifier for the upload.
+func (m *MultipartUpload) UploadID() string {
+	return m.uploadID
+}
+
+// UploadPart uploads part n, numbered from 1 to 10000. Parts may be uploaded
+// concurrently and in any order; uploading a number again replaces that part.
+// Backends may require every part except the last to meet a minimum size
+// (5 MiB on S3). The default timeout does not apply, since a single large part
+// may legitimately take longer.
+func (m *MultipartUpload) UploadPart(ctx context.Context, n int, r io.Reader) error {
+	if n < 1 || n > maxPartNumber {
+		return shared.WrapError(KindBucket, "upload_part", "", m.key,
+			fmt.Errorf("grub: part number %d out of range 1-%d", n, maxPartNumber))
+	}
+	etag, err := m.uploader.UploadPart(ctx, m.key, m.uploadID, n, r)
+	if err != nil {
+		return shared.WrapError(KindBucket, "upload_part", "", m.key, err)
+	}
+	m.mu.Lock()
+	m.parts[n] = etag
+	m.mu.Unlock()
+	return nil
+}
+
+// Parts returns the parts uploaded so far, sorted by number.
+func (m *MultipartUpload) Parts() []CompletedPart {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	parts := make([]CompletedPart, 0, len(m.parts))
+	for n, etag := range m.parts {
+		parts = append(parts, CompletedPart{Number: n, ETag: etag})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts
+}
+
+// Complete assembles the uploaded parts into the object and returns its info.
+func (m *MultipartUpload) Complete(ctx context.Context) (*ObjectInfo, error) {
+	callCtx, cancel := withDefaultTimeout(ctx, m.timeout)
+	defer cancel()
+	info, err := m.uploader.CompleteMultipartUpload(callCtx, m.key, m.uploadID, m.Parts())
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "complete_multipart", "", m.key, err)
+	}
+	return info, nil
+}
+
+// Abort discards the upload and any parts stored so far.
+func (m *MultipartUpload) Abort(ctx context.Context) error {
+	callCtx, cancel := withDefaultTimeout(ctx, m.timeout)
+	defer cancel()
+	if err := m.uploader.AbortMultipartUpload(callCtx, m.key, m.uploadID); err != nil {
+		return shared.WrapError(KindBucket, "abort_multipart", "", m.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package grub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// multipartProvider assembles parts in memory.
+type multipartProvider struct {
+	*mockBucketProvider
+	mu      sync.Mutex
+	uploads map[string]map[int][]byte
+	aborted []string
+}
+
+func newMultipartProvider() *multipartProvider {
+	return &multipartProvider{
+		mockBucketProvider: newMockBucketProvider(),
+		uploads:            make(map[string]map[int][]byte),
+	}
+}
+
+func (p *multipartProvider) CreateMultipartUpload(_ context.Context, key string, info *ObjectInfo) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	id := key + "-" + strconv.Itoa(len(p.uploads)+1)
+	p.uploads[id] = make(map[int][]byte)
+	p.info[key] = info
+	return id, nil
+}
+
+func (p *multipartProvider) UploadPart(_ context.Context, _, uploadID string, n int, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	parts, ok := p.uploads[uploadID]
+	if !ok {
+		return "", ErrNotFound
+	}
+	parts[n] = data
+	return fmt.Sprintf("etag-%d", n), nil
+}
+
+func (p *multipartProvider) CompleteMultipartUpload(_ context.Context, key, uploadID string, parts []CompletedPart) (*ObjectInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stored, ok := p.uploads[uploadID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !sort.SliceIsSorted(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number }) {
+		return nil, errors.New("parts not sorted")
+	}
+	var buf bytes.Buffer
+	for _, part := range parts {
+		if part.ETag != fmt.Sprintf("etag-%d", part.Number) {
+			return nil, fmt.Errorf("etag mismatch for part %d", part.Number)
+		}
+		buf.Write(stored[part.Number])
+	}
+	p.data[key] = buf.Bytes()
+	delete(p.uploads, uploadID)
+	return &ObjectInfo{Key: key, Size: int64(buf.Len()), ETag: "final"}, nil
+}
+
+func (p *multipartProvider) AbortMultipartUpload(_ context.Context, _, uploadID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.uploads[uploadID]; !ok {
+		return ErrNotFound
+	}
+	delete(p.uploads, uploadID)
+	p.aborted = append(p.aborted, uploadID)
+	return nil
+}
+
+func TestBucket_MultipartUpload(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("content type from extension", func(t *testing.T) {
+		provider := newMultipartProvider()
+		bucket := NewBucket[testPayload](provider, WithContentTypeSniffing())
+
+		if _, err := bucket.NewMultipartUpload(ctx, "scan.pdf", ""); err != nil {
+			t.Fatalf("NewMultipartUpload failed: %v", err)
+		}
+		if got := provider.info["scan.pdf"].ContentType; got != "application/pdf" {
+			t.Errorf("expected application/pdf, got %q", got)
+		}
+	})
+
+	t.Run("parallel parts assemble in order", func(t *testing.T) {
+		provider := newMultipartProvider()
+		bucket := NewBucket[testPayload](provider)
+
+		upload, err := bucket.NewMultipartUpload(ctx, "big", "application/octet-stream")
+		if err != nil {
+			t.Fatalf("NewMultipartUpload failed: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 5)
+		for n := 5; n >= 1; n-- {
+			wg.Add(1)
+			go func(n int) {
+				defer wg.Done()
+				errs <- upload.UploadPart(ctx, n, strings.NewReader(strconv.Itoa(n)))
+			}(n)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("UploadPart failed: %v", err)
+			}
+		}
+
+		info, err := upload.Complete(ctx)
+		if err != nil {
+			t.Fatalf("Complete failed: %v", err)
+		}
+		if got := string(provider.data["big"]); got != "12345" {
+			t.Errorf("expected parts in order, got %q", got)
+		}
+		if info.ETag != "final" || info.Size != 5 {
+			t.Errorf("unexpected info %+v", info)
+		}
+	})
+
+	t.Run("resume keeps earlier parts", func(t *testing.T) {
+		provider := newMultipartProvider()
+		bucket := NewBucket[testPayload](provider)
+
+		first, err := bucket.NewMultipartUpload(ctx, "big", "")
+		if err != nil {
+			t.Fatalf("NewMultipartUpload failed: %v", err)
+		}
+		if err := first.UploadPart(ctx, 1, strings.NewReader("a")); err != nil {
+			t.Fatalf("UploadPart failed: %v", err)
+		}
+
+		resumed, err := bucket.ResumeMultipartUpload(first.Key(), first.UploadID(), first.Parts())
+		if err != nil {
+			t.Fatalf("ResumeMultipartUpload failed: %v", err)
+		}
+		if err := resumed.UploadPart(ctx, 2, strings.NewReader("b")); err != nil {
+			t.Fatalf("UploadPart failed: %v", err)
+		}
+		if _, err := resumed.Complete(ctx); err != nil {
+			t.Fatalf("Complete failed: %v", err)
+		}
+		if got := string(provider.data["big"]); got != "ab" {
+			t.Errorf("expected %q, got %q", "ab", got)
+		}
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		provider := newMultipartProvider()
+		upload, err := NewBucket[testPayload](provider).NewMultipartUpload(ctx, "big", "")
+		if err != nil {
+			t.Fatalf("NewMultipartUpload failed: %v", err)
+		}
+		if err := upload.Abort(ctx); err != nil {
+			t.Fatalf("Abort failed: %v", err)
+		}
+		if len(provider.aborted) != 1 {
+			t.Errorf("expected upload to be aborted")
+		}
+		if _, err := upload.Complete(ctx); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected Complete after Abort to fail, got %v", err)
+		}
+	})
+
+	t.Run("part number out of range", func(t *testing.T) {
+		upload, err := NewBucket[testPayload](newMultipartProvider()).NewMultipartUpload(ctx, "big", "")
+		if err != nil {
+			t.Fatalf("NewMultipartUpload failed: %v", err)
+		}
+		for _, n := range []int{0, maxPartNumber + 1} {
+			if err := upload.UploadPart(ctx, n, strings.NewReader("x")); err == nil {
+				t.Errorf("expected error for part %d", n)
+			}
+		}
+	})
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		bucket := NewBucket[testPayload](newMockBucketProvider())
+		if _, err := bucket.NewMultipartUpload(ctx, "big", ""); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+		if _, err := bucket.ResumeMultipartUpload("big", "id", nil); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package grub
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// namespacedStore prefixes every key passed to a StoreProvider with ns and
+// strips it from the keys the provider returns, so several Stores can share
+// one provider without colliding.
+type namespacedStore struct {
+	inner StoreProvider
+	ns    string
+}
+
+// namespaceStore wraps provider in ns, or returns it unchanged when ns is
+// empty.
+func namespaceStore(provider StoreProvider, ns string) StoreProvider {
+	if ns == "" {
+		return provider
+	}
+	return &namespacedStore{inner: provider, ns: ns}
+}
+
+func (p *namespacedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return p.inner.Get(ctx, p.ns+key)
+}
+
+func (p *namespacedStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return p.inner.Set(ctx, p.ns+key, value, ttl)
+}
+
+func (p *namespacedStore) Delete(ctx context.Context, key string) error {
+	return p.inner.Delete(ctx, p.ns+key)
+}
+
+func (p *namespacedStore) Exists(ctx context.Context, key string) (bool, error) {
+	return p.inner.Exists(ctx, p.ns+key)
+}
+
+func (p *namespacedStore) List(ctx context.Context, prefix string, limit int) ([]string, error) {
+	keys, err := p.inner.List(ctx, p.ns+prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	return p.strip(keys), nil
+}
+
+func (p *namespacedStore) GetBatch(ctx context.Context, keys []string) (map[string][]byte, error) {
+	prefixed := make([]string, len(keys))
+	for n, key := range keys {
+		prefixed[n] = p.ns + key
+	}
+	raw, err := p.inner.GetBatch(ctx, prefixed)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]byte, len(raw))
+	for key, data := range raw {
+		result[strings.TrimPrefix(key, p.ns)] = data
+	}
+	return result, nil
+}
+
+func (p *namespacedStore) SetBatch(ctx context.Context, items map[string][]byte, ttl time.Duration) error {
+	prefixed := make(map[string][]byte, len(items))
+	for key, data := range items {
+		prefixed[p.ns+key] = data
+	}
+	return p.inner.SetBatch(ctx, prefixed, ttl)
+}
+
+// ListMatch anchors pattern at the namespace, escaping any glob characters
+// in it. Only reached when the inner provider is a StoreMatcher.
+func (p *namespacedStore) ListMatch(ctx context.Context, pattern, cursor string, limit int) ([]string, string, error) {
+	keys, next, err := p.inner.(StoreMatcher).ListMatch(ctx, escapeGlob(p.ns)+pattern, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	return p.strip(keys), next, nil
+}
+
+// DeletePrefix deletes under the namespaced prefix. Only reached when the
+// inner provider is a StorePrefixDeleter.
+func (p *namespacedStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	return p.inner.(StorePrefixDeleter).DeletePrefix(ctx, p.ns+prefix)
+}
+
+// CompareAndSwap swaps the namespaced key. Only reached when the inner
+// provider is a StoreSwapper.
+func (p *namespacedStore) CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (bool, error) {
+	return p.inner.(StoreSwapper).CompareAndSwap(ctx, p.ns+key, old, value, ttl)
+}
+
+// TTL reads the namespaced key's TTL. Only reached when the inner provider
+// is a StoreTTLReader.
+func (p *namespacedStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return p.inner.(StoreTTLReader).TTL(ctx, p.ns+key)
+}
+
+// strip removes the namespace from keys in place.
+func (p *namespacedStore) strip(keys []string) []string {
+	for n, key := range keys {
+		keys[n] = strings.TrimPrefix(key, p.ns)
+	}
+	return keys
+}
+
+// storeMatcher returns provider as a StoreMatcher, looking through a
+// namespace to the provider it wraps.
+func storeMatcher(provider StoreProvider) (StoreMatcher, bool) {
+	if ns, ok := provider.(*namespacedStore); ok {
+		if _, ok := ns.inner.(StoreMatcher); !ok {
+			return nil, false
+		}
+	}
+	m, ok := provider.(StoreMatcher)
+	return m, ok
+}
+
+// storePrefixDeleter returns provider as a StorePrefixDeleter, looking
+// through a namespace to the provider it wraps.
+func storePrefixDeleter(provider StoreProvider) (StorePrefixDeleter, bool) {
+	if ns, ok := provider.(*namespacedStore); ok {
+		if _, ok := ns.inner.(StorePrefixDeleter); !ok {
+			return nil, false
+		}
+	}
+	d, ok := provider.(StorePrefixDeleter)
+	return d, ok
+}
+
+// storeSwapper returns provider as a StoreSwapper, looking through a
+// namespace to the provider it wraps.
+func storeSwapper(provider StoreProvider) (StoreSwapper, bool) {
+	if ns, ok := provider.(*namespacedStore); ok {
+		if _, ok := ns.inner.(StoreSwapper); !ok {
+			return nil, false
+		}
+	}
+	sw, ok := provider.(StoreSwapper)
+	return sw, ok
+}
+
+// storeTTLReader returns provider as a StoreTTLReader, looking through a
+// namespace to the provider it wraps.
+func storeTTLReader(provider StoreProvider) (StoreTTLReader, bool) {
+	if ns, ok := provider.(*namespacedStore); ok {
+		if _, ok := ns.inner.(StoreTTLReader); !ok {
+			return nil, false
+		}
+	}
+	r, ok := provider.(StoreTTLReader)
+	return r, ok
+}
@@ -0,0 +1,108 @@
+package grub
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestStore_KeyNamespace(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockStoreProvider()
+	users := NewStore[testRecord](provider, WithKeyNamespace("user:"))
+	orders := NewStore[testPayload](provider, WithKeyNamespace("order:"))
+
+	if err := users.Set(ctx, "1", &testRecord{ID: 1, Name: "alice"}, 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := orders.SetBatch(ctx, map[string]*testPayload{"1": {Field1: "o1"}, "2": {Field1: "o2"}}, 0); err != nil {
+		t.Fatalf("SetBatch failed: %v", err)
+	}
+
+	stored := make([]string, 0, len(provider.data))
+	for key := range provider.data {
+		stored = append(stored, key)
+	}
+	sort.Strings(stored)
+	if want := []string{"order:1", "order:2", "user:1"}; !reflect.DeepEqual(stored, want) {
+		t.Errorf("expected provider keys %v, got %v", want, stored)
+	}
+
+	user, err := users.Get(ctx, "1")
+	if err != nil || user.Name != "alice" {
+		t.Errorf("expected alice, got %v, %v", user, err)
+	}
+	if exists, _ := users.Exists(ctx, "2"); exists {
+		t.Error("expected another namespace's key to be invisible")
+	}
+
+	keys, err := orders.List(ctx, "", 0)
+	sort.Strings(keys)
+	if err != nil || !reflect.DeepEqual(keys, []string{"1", "2"}) {
+		t.Errorf("expected logical keys from List, got %v, %v", keys, err)
+	}
+	batch, err := orders.GetBatch(ctx, []string{"1", "2", "3"})
+	if err != nil || len(batch) != 2 || batch["2"].Field1 != "o2" {
+		t.Errorf("expected logical keys from GetBatch, got %v, %v", batch, err)
+	}
+
+	it, err := users.Scan(ctx)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	var scanned []string
+	for it.Next() {
+		scanned = append(scanned, it.Key())
+	}
+	if !reflect.DeepEqual(scanned, []string{"1"}) {
+		t.Errorf("expected Scan to stay in the namespace, got %v", scanned)
+	}
+
+	if err := orders.Delete(ctx, "1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := provider.data["user:1"]; !ok {
+		t.Error("expected Delete to leave other namespaces alone")
+	}
+
+	n, err := orders.DeletePrefix(ctx, "2")
+	if err != nil || n != 1 || provider.data["user:1"] == nil {
+		t.Errorf("expected DeletePrefix to stay in the namespace, got %d, %v", n, err)
+	}
+}
+
+func TestStore_KeyNamespaceCapabilities(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("native match", func(t *testing.T) {
+		provider := &matchingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		store := NewStore[testRecord](provider, WithKeyNamespace("app[1]:"))
+		if _, _, err := store.ListMatch(ctx, "user:*", "", 10); err != nil {
+			t.Fatalf("ListMatch failed: %v", err)
+		}
+		if provider.pattern != `app\[1\]:user:*` {
+			t.Errorf("expected the escaped namespace on the pattern, got %q", provider.pattern)
+		}
+	})
+
+	t.Run("client-side match", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		provider.data["a:user:1"] = []byte(`{}`)
+		provider.data["b:user:2"] = []byte(`{}`)
+		keys, _, err := NewStore[testRecord](provider, WithKeyNamespace("a:")).ListMatch(ctx, "user:*", "", 0)
+		if err != nil || !reflect.DeepEqual(keys, []string{"user:1"}) {
+			t.Errorf("expected only the namespace's logical key, got %v, %v", keys, err)
+		}
+	})
+
+	t.Run("native delete prefix", func(t *testing.T) {
+		provider := &prefixDeleterStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		if _, err := NewStore[testRecord](provider, WithKeyNamespace("a:")).DeletePrefix(ctx, "tmp/"); err != nil {
+			t.Fatalf("DeletePrefix failed: %v", err)
+		}
+		if !reflect.DeepEqual(provider.prefixes, []string{"a:tmp/"}) {
+			t.Errorf("expected the namespaced prefix, got %v", provider.prefixes)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package grub
+
+import (
+	"fmt"
+	"math"
+)
+
+// unitTolerance is how far a vector's L2 norm may be from 1 and still be
+// treated as already normalized.
+const unitTolerance = 1e-6
+
+// normalize applies WithNormalization to vector. Without it, vector is
+// returned unchanged.
+func (i *Index[T]) normalize(vector []float32) ([]float32, error) {
+	if !i.unitVectors {
+		return vector, nil
+	}
+	return normalizeL2(vector, i.rejectZero)
+}
+
+// normalizeQueries applies normalize to each SearchBatch query, reporting
+// a failure as a *BatchQueryError.
+func (i *Index[T]) normalizeQueries(queries [][]float32) ([][]float32, error) {
+	if !i.unitVectors {
+		return queries, nil
+	}
+	out := make([][]float32, len(queries))
+	for n, vector := range queries {
+		normalized, err := normalizeL2(vector, i.rejectZero)
+		if err != nil {
+			return nil, &BatchQueryError{Index: n, Err: err}
+		}
+		out[n] = normalized
+	}
+	return out, nil
+}
+
+// normalizeL2 returns vector scaled to unit L2 norm. Vectors already within
+// unitTolerance of unit length are returned as is; otherwise the result is a
+// new slice and vector is left untouched. The norm is accumulated in float64
+// so components near the float32 limits neither underflow nor overflow. A
+// zero vector has no direction: it is returned unchanged, or rejected with
+// ErrInvalidVector when rejectZero is set. Non-finite components are always
+// rejected.
+func normalizeL2(vector []float32, rejectZero bool) ([]float32, error) {
+	var sum float64
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	norm := math.Sqrt(sum)
+	switch {
+	case math.IsNaN(norm) || math.IsInf(norm, 0):
+		return nil, fmt.Errorf("%w: non-finite component", ErrInvalidVector)
+	case norm == 0:
+		if rejectZero {
+			return nil, fmt.Errorf("%w: zero vector cannot be normalized", ErrInvalidVector)
+		}
+		return vector, nil
+	case math.Abs(norm-1) <= unitTolerance:
+		return vector, nil
+	}
+	out := make([]float32, len(vector))
+	for n, v := range vector {
+		out[n] = float32(float64(v) / norm)
+	}
+	return out, nil
+}
@@ -0,0 +1,235 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/vecna"
+)
+
+func norm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+func TestNormalizeL2(t *testing.T) {
+	t.Run("scales to unit length", func(t *testing.T) {
+		in := []float32{3, 4}
+		out, err := normalizeL2(in, false)
+		if err != nil {
+			t.Fatalf("normalizeL2 failed: %v", err)
+		}
+		if out[0] != 0.6 || out[1] != 0.8 {
+			t.Errorf("expected [0.6 0.8], got %v", out)
+		}
+		if in[0] != 3 || in[1] != 4 {
+			t.Errorf("input modified: %v", in)
+		}
+	})
+
+	t.Run("unit vector not copied", func(t *testing.T) {
+		in := []float32{0.6, 0.8}
+		out, err := normalizeL2(in, false)
+		if err != nil {
+			t.Fatalf("normalizeL2 failed: %v", err)
+		}
+		if &out[0] != &in[0] {
+			t.Error("expected an already-unit vector to be returned as is")
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		in   []float32
+	}{
+		{"near zero", []float32{1e-30, 2e-30, -2e-30}},
+		{"subnormal", []float32{math.SmallestNonzeroFloat32, 0}},
+		{"near max", []float32{3e38, 3e38}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := normalizeL2(tc.in, true)
+			if err != nil {
+				t.Fatalf("normalizeL2 failed: %v", err)
+			}
+			if n := norm(out); math.Abs(n-1) > 1e-6 {
+				t.Errorf("expected unit norm, got %v (%v)", n, out)
+			}
+		})
+	}
+
+	t.Run("zero vector", func(t *testing.T) {
+		zero := []float32{0, 0, 0}
+		out, err := normalizeL2(zero, false)
+		if err != nil || &out[0] != &zero[0] {
+			t.Errorf("expected zero vector passed through, got %v, %v", out, err)
+		}
+		if _, err := normalizeL2(zero, true); !errors.Is(err, ErrInvalidVector) {
+			t.Errorf("expected ErrInvalidVector when rejecting zero vectors, got %v", err)
+		}
+	})
+
+	t.Run("non-finite", func(t *testing.T) {
+		for _, in := range [][]float32{
+			{float32(math.NaN()), 1},
+			{float32(math.Inf(1)), 1},
+		} {
+			if _, err := normalizeL2(in, false); !errors.Is(err, ErrInvalidVector) {
+				t.Errorf("%v: expected ErrInvalidVector, got %v", in, err)
+			}
+		}
+	})
+}
+
+// recordingVectorProvider remembers the last query vector it was sent.
+type recordingVectorProvider struct {
+	*mockVectorProvider
+	query []float32
+}
+
+func (p *recordingVectorProvider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]VectorResult, error) {
+	p.query = vector
+	return p.mockVectorProvider.Query(ctx, vector, k, filter)
+}
+
+func (p *recordingVectorProvider) Search(ctx context.Context, vector []float32, k int, filter map[string]any) ([]VectorResult, error) {
+	p.query = vector
+	return p.mockVectorProvider.Search(ctx, vector, k, filter)
+}
+
+func TestIndex_Normalization(t *testing.T) {
+	ctx := context.Background()
+	provider := &recordingVectorProvider{mockVectorProvider: newMockVectorProvider()}
+	index := NewIndex[testMetadata](provider, WithNormalization())
+
+	t.Run("writes", func(t *testing.T) {
+		id := uuid.New()
+		in := []float32{3, 4}
+		if err := index.Upsert(ctx, id, in, &testMetadata{}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		if got := provider.vectors[id].vector; got[0] != 0.6 || got[1] != 0.8 {
+			t.Errorf("expected normalized vector stored, got %v", got)
+		}
+		if in[0] != 3 {
+			t.Errorf("caller's vector modified: %v", in)
+		}
+
+		batch := []Vector[testMetadata]{{ID: uuid.New(), Vector: []float32{0, 5}}}
+		if err := index.UpsertBatch(ctx, batch); err != nil {
+			t.Fatalf("UpsertBatch failed: %v", err)
+		}
+		if got := provider.vectors[batch[0].ID].vector; got[1] != 1 {
+			t.Errorf("expected normalized batch vector stored, got %v", got)
+		}
+	})
+
+	t.Run("reads", func(t *testing.T) {
+		if _, err := index.Query(ctx, []float32{10, 0}, 1, nil); err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if provider.query[0] != 1 {
+			t.Errorf("expected normalized Query vector, got %v", provider.query)
+		}
+		if _, err := index.Search(ctx, []float32{0, -2}, 1, nil); err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if provider.query[1] != -1 {
+			t.Errorf("expected normalized Search vector, got %v", provider.query)
+		}
+		if _, err := index.SearchBatch(ctx, [][]float32{{0, 7}}, 1, nil); err != nil {
+			t.Fatalf("SearchBatch failed: %v", err)
+		}
+		if provider.query[1] != 1 {
+			t.Errorf("expected normalized SearchBatch vector, got %v", provider.query)
+		}
+	})
+
+	t.Run("zero vectors", func(t *testing.T) {
+		if err := index.Upsert(ctx, uuid.New(), []float32{0, 0}, nil); err != nil {
+			t.Errorf("expected zero vector passed through, got %v", err)
+		}
+		strict := NewIndex[testMetadata](provider, WithNormalization(), WithRejectZeroVectors())
+		if err := strict.Upsert(ctx, uuid.New(), []float32{0, 0}, nil); !errors.Is(err, ErrInvalidVector) {
+			t.Errorf("expected ErrInvalidVector, got %v", err)
+		}
+		_, err := strict.SearchBatch(ctx, [][]float32{{1, 0}, {0, 0}}, 1, nil)
+		var bqe *BatchQueryError
+		if !errors.As(err, &bqe) || bqe.Index != 1 {
+			t.Errorf("expected zero query reported at index 1, got %v", err)
+		}
+	})
+}
+
+// dotVectorProvider ranks Query results by descending dot product, as a
+// dot-product backend would.
+type dotVectorProvider struct {
+	*mockVectorProvider
+}
+
+func (p *dotVectorProvider) Query(_ context.Context, vector []float32, k int, _ *vecna.Filter) ([]VectorResult, error) {
+	results := make([]VectorResult, 0, len(p.vectors))
+	for id, entry := range p.vectors {
+		var dot float32
+		for n := range vector {
+			dot += vector[n] * entry.vector[n]
+		}
+		results = append(results, VectorResult{ID: id, Vector: entry.vector, Score: dot})
+	}
+	sort.Slice(results, func(a, b int) bool { return results[a].Score > results[b].Score })
+	if k < len(results) {
+		results = results[:k]
+	}
+	return results, nil
+}
+
+func TestIndex_NormalizationDotMatchesCosine(t *testing.T) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+	random := func() []float32 {
+		v := make([]float32, 8)
+		for n := range v {
+			v[n] = float32(rng.NormFloat64() * math.Pow(10, float64(rng.Intn(6)-3)))
+		}
+		return v
+	}
+	cosine := func(a, b []float32) float64 {
+		var dot float64
+		for n := range a {
+			dot += float64(a[n]) * float64(b[n])
+		}
+		return dot / (norm(a) * norm(b))
+	}
+
+	for trial := 0; trial < 20; trial++ {
+		provider := &dotVectorProvider{newMockVectorProvider()}
+		index := NewIndex[testMetadata](provider, WithNormalization())
+		raw := make(map[uuid.UUID][]float32)
+		for n := 0; n < 50; n++ {
+			id, v := uuid.New(), random()
+			raw[id] = v
+			if err := index.Upsert(ctx, id, v, nil); err != nil {
+				t.Fatalf("Upsert failed: %v", err)
+			}
+		}
+
+		query := random()
+		results, err := index.Query(ctx, query, len(raw), nil)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		for n := 1; n < len(results); n++ {
+			prev, cur := cosine(query, raw[results[n-1].ID]), cosine(query, raw[results[n].ID])
+			if prev < cur-1e-6 {
+				t.Fatalf("trial %d: dot-product order differs from cosine order at %d: %v < %v", trial, n, prev, cur)
+			}
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package grub
 
-import "github.com/zoobzio/grub/internal/shared"
+import (
+	"time"
+
+	"github.com/zoobzio/grub/internal/shared"
+)
 
 // ObjectInfo is re-exported from internal/shared for the public API.
 type ObjectInfo = shared.ObjectInfo
@@ -8,11 +12,15 @@ type ObjectInfo = shared.ObjectInfo
 // Object wraps payload T with blob metadata for atomization.
 // The entire structure is atomizable, enabling field-level operations
 // on both metadata and payload.
+//
+// A non-zero ExpiresAt on Put has the backend delete the object at that
+// time; see BucketExpirer.
 type Object[T any] struct {
 	Key         string            `json:"key" atom:"key"`
 	ContentType string            `json:"content_type" atom:"content_type"`
 	Size        int64             `json:"size" atom:"size"`
 	ETag        string            `json:"etag,omitempty" atom:"etag"`
 	Metadata    map[string]string `json:"metadata,omitempty" atom:"metadata"`
+	ExpiresAt   time.Time         `json:"expires_at,omitzero" atom:"expires_at"`
 	Data        T                 `json:"data" atom:"data"`
 }
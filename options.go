@@ -0,0 +1,562 @@
+package grub
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Option configures optional behaviour of a grub facade.
+// Each option documents which facades honour it; others ignore it.
+type Option func(*options)
+
+// options holds settings shared across facade constructors.
+type options struct {
+	name        string
+	dimension   int
+	timeout     time.Duration
+	onDecodeErr DecodeErrorHandler
+	cacheStore  StoreProvider
+	cacheTTL    time.Duration
+
+	batchWindow  time.Duration
+	maxBatchSize int
+
+	now          func() time.Time
+	noTimestamps bool
+	redact       bool
+
+	queryLogger QueryLogger
+	logParams   bool
+
+	sniffContentType bool
+
+	keyPolicy    *KeyPolicy
+	keyNamespace string
+
+	updateRetries int
+
+	clientFilter    bool
+	clientFilterCap int
+
+	maxScan int
+
+	normalize  bool
+	rejectZero bool
+
+	searchFields []string
+
+	normalizeScores bool
+
+	idOrder bool
+
+	readReplicas []*sqlx.DB
+
+	stmtCache     bool
+	stmtCacheSize int
+
+	keyGen KeyGenerator
+
+	metadataSchema bool
+
+	noMetadataErr bool
+
+	history History
+
+	outboxTable   string
+	outboxPayload any // OutboxPayload[T], checked by NewDatabase
+	pollInterval  time.Duration
+	writeAttempts int
+	writeBackoff  time.Duration
+	retryable     func(error) bool
+
+	singleflight bool
+}
+
+// applyOptions resolves opts into an options value.
+func applyOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithName labels the facade with the name of its backing collection.
+// The name is attached to emitted signals and to the Table field of returned
+// errors. Honoured by Index.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithDimension declares the vector dimension of the backing collection.
+// Vectors of any other length are rejected with ErrDimensionMismatch before
+// reaching the provider. Zero (the default) disables the check.
+// Honoured by Index.
+func WithDimension(dim int) Option {
+	return func(o *options) {
+		o.dimension = dim
+	}
+}
+
+// WithDefaultTimeout bounds each provider call by d when the incoming context
+// has no deadline of its own. Contexts that already carry a deadline are used
+// unchanged. Lifecycle hooks still receive the caller's context. Iterators
+// such as Store.Scan are exempt, since a scan may legitimately outlive any
+// single call. Honoured by Store, Bucket, Database, and Index.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// DecodeErrorHandler decides what happens when a record in a batch or stream
+// read cannot be decoded. key identifies the record and raw holds its stored
+// bytes. Returning nil skips the record; returning an error aborts the read
+// with that error.
+type DecodeErrorHandler func(key string, raw []byte, err error) error
+
+// WithDecodeErrorHandler routes per-record decode failures in batch and stream
+// reads through h instead of failing the whole call. Single-record reads such
+// as Get are unaffected. Honoured by Store (GetBatch, Scan) and Index (Search,
+// Query, Filter).
+func WithDecodeErrorHandler(h DecodeErrorHandler) Option {
+	return func(o *options) {
+		o.onDecodeErr = h
+	}
+}
+
+// WithQueryCache caches ExecQuery, ExecSelect, and ExecAggregate results in
+// store for ttl, keyed by statement name and a hash of the rendered SQL and
+// the params, so statements sharing a name never share entries. Set,
+// Delete, ExecUpdate and their Tx variants invalidate every cached entry for
+// the table, as does each WithTx commit; writes made through the query
+// builders, Atomic, or another process not sharing store go unseen until
+// entries expire. Tx writes made with a WithTx context invalidate once it
+// commits; in a transaction the caller commits itself they invalidate at
+// once, so a read before the commit may re-cache the old rows. Records are cached by their db-mapped fields, whatever
+// their json tags, so a hit returns the record a miss would. They are
+// cached before AfterLoad, which runs once per returned record on a hit or
+// a miss. Tx reads never consult the cache, and cache failures fall back to
+// the database. Honoured by Database.
+func WithQueryCache(store StoreProvider, ttl time.Duration) Option {
+	return func(o *options) {
+		o.cacheStore = store
+		o.cacheTTL = ttl
+	}
+}
+
+// WithBatchWindow sets how long a Loader waits after the first uncached key
+// before fetching the batch. Longer windows coalesce more keys at the cost of
+// latency. Honoured by Loader.
+func WithBatchWindow(d time.Duration) Option {
+	return func(o *options) {
+		o.batchWindow = d
+	}
+}
+
+// WithMaxBatchSize caps the keys a Loader fetches in one GetBatch call; a
+// full batch is dispatched without waiting for the window. It also caps the
+// records an OutboxPoller reads per poll and the rows Database.SetBatch
+// writes per statement. Honoured by Loader, OutboxPoller, and Database.
+func WithMaxBatchSize(n int) Option {
+	return func(o *options) {
+		o.maxBatchSize = n
+	}
+}
+
+// WithClock sets the source of the current time used to fill timestamp
+// fields on save, in place of time.Now. Intended for tests. Honoured by
+// Store, Bucket, Database, Index, and Snapshot, which uses it to time
+// expiry.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithoutTimestamps disables automatic created and updated timestamps, for
+// tables whose timestamps are maintained by the database. Honoured by Store,
+// Bucket, Database, and Index.
+func WithoutTimestamps() Option {
+	return func(o *options) {
+		o.noTimestamps = true
+	}
+}
+
+// WithRedaction zeroes fields tagged grub:"redact" in every record read,
+// after decode and before AfterLoad, including through the Atomic view.
+// Saves that would persist a redacted field as zero, such as writing back a
+// record read through this facade, fail with ErrRedacted; write secrets
+// through a facade built without this option. A field tagged
+// grub:"redact,allowempty" is not checked, so a write-back stores it empty.
+// Honoured by Store, Bucket, Database, and Index.
+func WithRedaction() Option {
+	return func(o *options) {
+		o.redact = true
+	}
+}
+
+// WithContentTypeSniffing fills in an empty ContentType on put. The type is
+// inferred from the key's extension with mime.TypeByExtension, and failing
+// that detected from the first 512 bytes of the encoded payload with
+// http.DetectContentType. An explicitly set ContentType always wins.
+// Multipart uploads infer from the extension only. Honoured by Bucket.
+func WithContentTypeSniffing() Option {
+	return func(o *options) {
+		o.sniffContentType = true
+	}
+}
+
+// WithKeyPolicy validates, and optionally normalizes, every key and List
+// prefix passed to the facade against p. Rejected keys fail with a *KeyError
+// matching ErrInvalidKey before reaching the provider; batch operations
+// report the first invalid key. Keys returned by List are passed through as
+// stored. Honoured by Store and Bucket.
+func WithKeyPolicy(p KeyPolicy) Option {
+	return func(o *options) {
+		o.keyPolicy = &p
+	}
+}
+
+// WithKeyNamespace prefixes every key the facade passes to its provider
+// with ns and strips it from keys the provider returns, so Stores for
+// different types can share one provider without colliding. Callers keep
+// using logical keys: List, Scan, and ListMatch return them without the
+// namespace. Include a separator in ns ("user:"), since none is added. The
+// key policy applies to logical keys. Honoured by Store.
+func WithKeyNamespace(ns string) Option {
+	return func(o *options) {
+		o.keyNamespace = ns
+	}
+}
+
+// defaultUpdateRetries is the number of times Update retries a conflicting
+// write when WithUpdateRetries is not given.
+const defaultUpdateRetries = 10
+
+// WithUpdateRetries bounds how many times Update re-reads and retries after
+// another writer changed the value between its read and its write. Update
+// returns ErrConflict once the retries are spent. Zero or less uses the
+// default of 10. Honoured by Store.
+func WithUpdateRetries(n int) Option {
+	return func(o *options) {
+		o.updateRetries = n
+	}
+}
+
+// defaultClientFilterCap is the scan cap of WithClientSideFilter when
+// WithClientSideFilterCap is not given.
+const defaultClientFilterCap = 10000
+
+// WithClientSideFilter evaluates filters in process when the provider
+// rejects them with ErrFilterNotSupported or ErrOperatorNotSupported,
+// instead of returning the error. Filter fetches every vector through List
+// and Get; Query fetches the nearest neighbors without a filter and keeps the
+// first k that match. Both are slow paths: each use emits
+// IndexClientFilterUsed, and a collection larger than the scan cap (10,000
+// vectors unless set by WithClientSideFilterCap) fails with
+// ErrScanLimitExceeded rather than being scanned. Metadata is matched with
+// MatchFilter. Honoured by Index.
+func WithClientSideFilter() Option {
+	return func(o *options) {
+		o.clientFilter = true
+	}
+}
+
+// WithClientSideFilterCap sets the most vectors WithClientSideFilter may
+// fetch for a single call. Honoured by Index.
+func WithClientSideFilterCap(n int) Option {
+	return func(o *options) {
+		o.clientFilterCap = n
+	}
+}
+
+// WithMaxScan caps how many vectors a single Filter may return. A Filter
+// with no limit, or a limit above n, fetches at most n+1 matches and fails
+// with ErrScanLimitExceeded when there are more than n, so a filter that
+// matches most of a large collection is caught instead of scanned in full.
+// Callers that mean to read that much narrow the filter or pass a limit of
+// n or less. The cap applies whatever the provider, including the
+// WithClientSideFilter fallback. Zero, the default, leaves Filter
+// unbounded. Honoured by Index.
+func WithMaxScan(n int) Option {
+	return func(o *options) {
+		o.maxScan = n
+	}
+}
+
+// WithNormalization scales every vector passed to Upsert, UpsertBatch,
+// Search, SearchPage, Query, QueryPage, SearchBatch, and HybridSearch to unit
+// L2 length before it reaches the provider, so dot-product and cosine
+// backends rank alike. Callers' slices are never modified. Vectors already
+// stored, and those returned by Get and searches, are as the provider holds
+// them: only new writes are normalized. Zero vectors are passed through
+// unchanged unless WithRejectZeroVectors is also set; vectors with NaN or
+// infinite components fail with ErrInvalidVector. Honoured by Index.
+func WithNormalization() Option {
+	return func(o *options) {
+		o.normalize = true
+	}
+}
+
+// WithRejectZeroVectors makes WithNormalization fail zero vectors with
+// ErrInvalidVector instead of passing them through. Honoured by Index.
+func WithRejectZeroVectors() Option {
+	return func(o *options) {
+		o.rejectZero = true
+	}
+}
+
+// WithSearchFields limits the metadata returned by Search, SearchPage,
+// Query, QueryPage, SearchBatch, HybridSearch, Recommend, and Filter to the
+// named top-level fields, keyed by their JSON names. Providers implementing
+// VectorProjector return only those properties; with others the full
+// metadata is fetched and trimmed after decoding. Either way, every other
+// field of T is zero-valued in the results, so do not write them back
+// through Upsert. Get still returns full metadata. Honoured by Index.
+func WithSearchFields(fields ...string) Option {
+	return func(o *options) {
+		o.searchFields = fields
+	}
+}
+
+// WithNormalizedScores converts the Score of Search, SearchPage, Query,
+// QueryPage, SearchBatch, and Recommend results to the canonical distance
+// for the provider's metric, so lower is always closer: cosine distance
+// (1 - similarity), negated inner product, or Euclidean distance. The
+// provider must implement VectorScorer; otherwise those calls fail with
+// ErrUnsupported. Filter and HybridSearch scores are not distances and are
+// left as is. Honoured by Index.
+func WithNormalizedScores() Option {
+	return func(o *options) {
+		o.normalizeScores = true
+	}
+}
+
+// WithIDOrder returns List and Filter results in ascending ID order.
+// Providers implementing VectorIDOrderer order the scan itself, so a limit
+// keeps the lowest IDs. With others the returned results are sorted after
+// the fact; this is best-effort, ordering only the page the provider
+// returned. Honoured by Index.
+func WithIDOrder() Option {
+	return func(o *options) {
+		o.idOrder = true
+	}
+}
+
+// QueryInfo describes one SQL statement a Database ran.
+type QueryInfo struct {
+	// SQL is the final rendered statement, with the driver's bindvars.
+	SQL string
+
+	// Statement is the name of the edamame statement the call executed,
+	// for ExecQuery, ExecSelect, ExecUpdate, ExecAggregate, their Tx
+	// variants, ExecNamed, and ExecQueryAsOf. Empty otherwise.
+	Statement string
+
+	// ParamNames are the names of the params passed with Statement, sorted.
+	ParamNames []string
+
+	// Params are the bound values in bind order. Nil unless
+	// WithParamLogging is set, since they may hold personal data.
+	Params []any
+
+	// Duration is how long the statement took; for queries it covers
+	// reading the results.
+	Duration time.Duration
+
+	// Rows is the number of rows a query returned or an exec affected.
+	Rows int64
+
+	// Err is the error the statement failed with, if any.
+	Err error
+}
+
+// QueryLogger receives each SQL statement a Database runs. It is called
+// synchronously on the calling goroutine, so it should return quickly.
+type QueryLogger func(ctx context.Context, info QueryInfo)
+
+// WithQueryLogger calls fn after every statement a Database executes,
+// including builder queries, Atomic calls, and transactions begun through
+// WithTx. Queries are reported once their rows are closed, so the duration
+// covers reading the results. Bound values are left out unless
+// WithParamLogging is also given. Transactions begun on the *sqlx.DB
+// directly and passed to the Tx methods are not seen. Each statement pays
+// for an extra layer of connection handling. Honoured by Database.
+func WithQueryLogger(fn QueryLogger) Option {
+	return func(o *options) {
+		o.queryLogger = fn
+	}
+}
+
+// WithParamLogging fills QueryInfo.Params with each statement's bound
+// values, unredacted. Leave it off wherever the logs may not hold personal
+// data. Honoured by Database with WithQueryLogger.
+func WithParamLogging() Option {
+	return func(o *options) {
+		o.logParams = true
+	}
+}
+
+// WithReadReplicas sends Database reads (Get, GetByKey, GetBatch, Exists,
+// ExecQuery, ExecSelect, ExecAggregate, ExecMultiAggregate, and ExecNamed
+// for those kinds) to the given replicas in round-robin order. A read that
+// fails to reach its replica is retried once on the primary. Writes,
+// transactions, raw SQL, and the query builders always use the primary, as
+// do reads under WithPrimaryReads. Honoured by Database.
+func WithReadReplicas(replicas ...*sqlx.DB) Option {
+	return func(o *options) {
+		o.readReplicas = replicas
+	}
+}
+
+// WithStatementCache runs Database statements as prepared statements,
+// preparing each distinct SQL string once and reusing it across calls.
+// Up to size statements are kept per connection pool, least recently used
+// first out; size <= 0 keeps 256. Each pooled connection prepares a
+// statement the first time it runs it, including connections the pool
+// opens to replace recycled ones. Statements in transactions begun through
+// the Database are prepared on the transaction and closed with it, without
+// entering the cache. Database.StatementCacheStats reports reuse. Honoured
+// by Database.
+func WithStatementCache(size int) Option {
+	return func(o *options) {
+		o.stmtCache = true
+		o.stmtCacheSize = size
+	}
+}
+
+// WithKeyGenerator mints primary keys for Database.Create, and for Set and
+// SetTx called with an empty key, when the record's primary key field is
+// zero. The key is assigned into the field before BeforeSave runs. The
+// field must be a string or implement encoding.TextUnmarshaler (such as
+// uuid.UUID); NewDatabase rejects any other type with ErrInvalidKey.
+// GenerateUUIDv4, GenerateUUIDv7, and GenerateULID are ready to use.
+// Honoured by Database.
+func WithKeyGenerator(fn KeyGenerator) Option {
+	return func(o *options) {
+		o.keyGen = fn
+	}
+}
+
+// WithMetadataSchema checks every Upsert and UpsertBatch payload, after
+// encoding, against the schema Index.Schema derives from T: each field must
+// be present (unless omitempty), non-null (unless a pointer, slice, or map),
+// and of the declared kind, and no other field may appear. A mismatch fails
+// with ErrSchemaMismatch naming the field and the expected and actual
+// kinds. Fields outside the schema in metadata read back by Get, Search,
+// Query, and the other reads emit IndexSchemaDrift instead of failing, so
+// older readers tolerate newer writers. Requires a JSON codec. Honoured by
+// Index.
+func WithMetadataSchema() Option {
+	return func(o *options) {
+		o.metadataSchema = true
+	}
+}
+
+// WithNoMetadataError makes Index.Get fail with ErrNoMetadata when the
+// vector was stored without metadata, so callers can tell it apart from
+// metadata whose fields are all zero. The vector is still returned, with
+// zero-valued metadata, alongside the error. Without this option Get
+// returns zero-valued metadata and no error. Searches and Filter always
+// return zero-valued metadata. Honoured by Index.
+func WithNoMetadataError() Option {
+	return func(o *options) {
+		o.noMetadataErr = true
+	}
+}
+
+// handleDecodeErr applies h to a decode failure, or returns err unchanged
+// when no handler is configured.
+func handleDecodeErr(h DecodeErrorHandler, key string, raw []byte, err error) error {
+	if h == nil {
+		return err
+	}
+	return h(key, raw, err)
+}
+
+// withDefaultTimeout derives a context bounded by d if d is positive and ctx
+// has no deadline. The returned cancel func must always be called.
+func withDefaultTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// WithHistory enables GetAsOf and ExecQueryAsOf using strategy h. The
+// strategy must be supported by the renderer's dialect; otherwise those
+// calls and EnsureHistory fail with ErrUnsupported. Honoured by Database.
+func WithHistory(h History) Option {
+	return func(o *options) {
+		o.history = h
+	}
+}
+
+// WithOutbox records every Set, Create, InsertReturning, and Delete, and
+// their Tx forms, as a row of the outbox table written in the same
+// transaction as the change, so the row and its event commit or roll back
+// together. These run in a transaction of their own when the context
+// carries none.
+// payload renders the row's payload; nil stores the record as JSON, or
+// null for deletes. T must match the Database's record type or NewDatabase
+// fails. Other writes, such as ExecUpdate and ExecRaw, are not recorded.
+// EnsureOutbox creates the table and OutboxPoller delivers its rows.
+// Honoured by Database.
+func WithOutbox[T any](table string, payload func(op, key string, rec *T) ([]byte, error)) Option {
+	return func(o *options) {
+		o.outboxTable = table
+		o.outboxPayload = OutboxPayload[T](payload)
+	}
+}
+
+// WithPollInterval sets how long an OutboxPoller waits after a poll that
+// found no records. Honoured by OutboxPoller.
+func WithPollInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = d
+	}
+}
+
+// WithWriteRetry makes Database.Set and Database.Delete try up to attempts
+// times when a write fails with a transient conflict, such as a deadlock,
+// waiting backoff before the second attempt and twice as long before each
+// one after. Errors that are not transient surface immediately. Hooks run
+// again on every attempt. The Tx variants, and writes through a context
+// carrying a transaction, are never retried: the conflict aborts the whole
+// transaction, so retry WithTx instead. Only use it where repeating the
+// write is safe. Honoured by Database.
+func WithWriteRetry(attempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.writeAttempts = attempts
+		o.writeBackoff = backoff
+	}
+}
+
+// WithRetryable replaces IsRetryable as the check WithWriteRetry uses to
+// decide which errors are transient. Honoured by Database.
+func WithRetryable(fn func(err error) bool) Option {
+	return func(o *options) {
+		o.retryable = fn
+	}
+}
+
+// WithSingleflight coalesces concurrent Get and Exists calls for the same
+// key into a single read whose result, ErrNotFound included, every caller
+// shares. Each caller still gets its own decoded value, sharing no memory
+// with the others, and its own AfterLoad call. A caller whose context ends
+// stops waiting without cancelling the read for the others. A read may have started before a
+// caller's own preceding write, so Database reads under WithPrimaryReads
+// are never coalesced. Honoured by Database and Store.
+func WithSingleflight() Option {
+	return func(o *options) {
+		o.singleflight = true
+	}
+}
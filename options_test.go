@@ -0,0 +1,101 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApplyOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		o := applyOptions(nil)
+		if o.name != "" {
+			t.Errorf("expected empty name, got %q", o.name)
+		}
+		if o.dimension != 0 {
+			t.Errorf("expected zero dimension, got %d", o.dimension)
+		}
+	})
+
+	t.Run("WithDefaultTimeout", func(t *testing.T) {
+		o := applyOptions([]Option{WithDefaultTimeout(time.Second)})
+		if o.timeout != time.Second {
+			t.Errorf("expected 1s, got %v", o.timeout)
+		}
+	})
+
+	t.Run("WithDecodeErrorHandler", func(t *testing.T) {
+		o := applyOptions([]Option{WithDecodeErrorHandler(func(string, []byte, error) error { return nil })})
+		if o.onDecodeErr == nil {
+			t.Error("expected handler to be set")
+		}
+	})
+
+	t.Run("WithDimension", func(t *testing.T) {
+		o := applyOptions([]Option{WithDimension(1536)})
+		if o.dimension != 1536 {
+			t.Errorf("expected 1536, got %d", o.dimension)
+		}
+	})
+
+	t.Run("WithName", func(t *testing.T) {
+		o := applyOptions([]Option{WithName("documents")})
+		if o.name != "documents" {
+			t.Errorf("expected 'documents', got %q", o.name)
+		}
+	})
+
+	t.Run("last wins", func(t *testing.T) {
+		o := applyOptions([]Option{WithName("a"), WithName("b")})
+		if o.name != "b" {
+			t.Errorf("expected 'b', got %q", o.name)
+		}
+	})
+}
+
+func TestWithDefaultTimeout(t *testing.T) {
+	t.Run("applies when no deadline", func(t *testing.T) {
+		ctx, cancel := withDefaultTimeout(context.Background(), time.Minute)
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("expected deadline to be set")
+		}
+		if remaining := time.Until(deadline); remaining > time.Minute || remaining < 59*time.Second {
+			t.Errorf("unexpected deadline %v from now", remaining)
+		}
+	})
+
+	t.Run("keeps caller deadline", func(t *testing.T) {
+		parent, parentCancel := context.WithTimeout(context.Background(), time.Hour)
+		defer parentCancel()
+		want, _ := parent.Deadline()
+
+		ctx, cancel := withDefaultTimeout(parent, time.Millisecond)
+		defer cancel()
+		if got, _ := ctx.Deadline(); !got.Equal(want) {
+			t.Errorf("expected caller deadline %v, got %v", want, got)
+		}
+	})
+
+	t.Run("zero disables", func(t *testing.T) {
+		ctx, cancel := withDefaultTimeout(context.Background(), 0)
+		defer cancel()
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("expected no deadline")
+		}
+	})
+}
+
+func TestHandleDecodeErr(t *testing.T) {
+	decodeErr := errors.New("bad bytes")
+
+	if err := handleDecodeErr(nil, "k", nil, decodeErr); !errors.Is(err, decodeErr) {
+		t.Errorf("expected decode error without handler, got %v", err)
+	}
+	skip := func(string, []byte, error) error { return nil }
+	if err := handleDecodeErr(skip, "k", nil, decodeErr); err != nil {
+		t.Errorf("expected nil from skipping handler, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package grub
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// orderedProvider returns provider's VectorIDOrderer view when WithIDOrder
+// is set, and provider otherwise.
+func orderedProvider(o options, provider VectorProvider) VectorProvider {
+	if !o.idOrder {
+		return provider
+	}
+	if orderer, ok := provider.(VectorIDOrderer); ok {
+		return orderer.OrderByID()
+	}
+	return provider
+}
+
+// compareIDs orders UUIDs by their bytes, which matches their string form.
+func compareIDs(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// sortIDs applies WithIDOrder to the IDs returned by List.
+func (i *Index[T]) sortIDs(ids []uuid.UUID) {
+	if i.idOrder {
+		slices.SortFunc(ids, compareIDs)
+	}
+}
+
+// sortResults applies WithIDOrder to the results returned by Filter.
+func (i *Index[T]) sortResults(results []VectorResult) {
+	if i.idOrder {
+		slices.SortFunc(results, func(a, b VectorResult) int { return compareIDs(a.ID, b.ID) })
+	}
+}
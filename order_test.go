@@ -0,0 +1,135 @@
+package grub
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/vecna"
+)
+
+// orderingVectorProvider implements VectorIDOrderer; its ordered view scans
+// in ascending ID order before applying the limit.
+type orderingVectorProvider struct {
+	*mockVectorProvider
+	ordered bool
+}
+
+func (p *orderingVectorProvider) OrderByID() VectorProvider {
+	return &orderingVectorProvider{mockVectorProvider: p.mockVectorProvider, ordered: true}
+}
+
+func (p *orderingVectorProvider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	if !p.ordered {
+		return p.mockVectorProvider.List(ctx, limit)
+	}
+	ids, err := p.mockVectorProvider.List(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(ids, compareIDs)
+	if limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (p *orderingVectorProvider) Filter(ctx context.Context, filter *vecna.Filter, limit int) ([]VectorResult, error) {
+	if !p.ordered {
+		return p.mockVectorProvider.Filter(ctx, filter, limit)
+	}
+	results, err := p.mockVectorProvider.Filter(ctx, filter, 0)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(results, func(a, b VectorResult) int { return compareIDs(a.ID, b.ID) })
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func TestIndex_IDOrder(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockVectorProvider()
+	var ids []uuid.UUID
+	for n := 0; n < 20; n++ {
+		id := uuid.New()
+		ids = append(ids, id)
+		mock.vectors[id] = vectorEntry{vector: []float32{float32(n)}, metadata: []byte(`{}`)}
+	}
+	slices.SortFunc(ids, compareIDs)
+
+	sorted := func(t *testing.T, got []uuid.UUID) {
+		t.Helper()
+		if !slices.IsSortedFunc(got, compareIDs) {
+			t.Errorf("expected ascending IDs, got %v", got)
+		}
+	}
+	resultIDs := func(results []*Vector[testMetadata]) []uuid.UUID {
+		out := make([]uuid.UUID, len(results))
+		for n, r := range results {
+			out[n] = r.ID
+		}
+		return out
+	}
+
+	t.Run("client-side", func(t *testing.T) {
+		index := NewIndex[testMetadata](mock, WithIDOrder())
+		listed, err := index.List(ctx, 0)
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if !slices.Equal(listed, ids) {
+			t.Errorf("expected all IDs in order, got %v", listed)
+		}
+		page, err := index.List(ctx, 5)
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		sorted(t, page)
+
+		results, err := index.Filter(ctx, nil, 5)
+		if err != nil {
+			t.Fatalf("Filter failed: %v", err)
+		}
+		sorted(t, resultIDs(results))
+	})
+
+	t.Run("provider ordered", func(t *testing.T) {
+		index := NewIndex[testMetadata](&orderingVectorProvider{mockVectorProvider: mock}, WithIDOrder())
+		page, err := index.List(ctx, 5)
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if !slices.Equal(page, ids[:5]) {
+			t.Errorf("expected the five lowest IDs, got %v", page)
+		}
+		results, err := index.Filter(ctx, nil, 5)
+		if err != nil {
+			t.Fatalf("Filter failed: %v", err)
+		}
+		if got := resultIDs(results); !slices.Equal(got, ids[:5]) {
+			t.Errorf("expected the five lowest IDs, got %v", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		index := NewIndex[testMetadata](&orderingVectorProvider{mockVectorProvider: mock})
+		if p, ok := index.lister.(*orderingVectorProvider); !ok || p.ordered {
+			t.Error("expected the unordered provider without WithIDOrder")
+		}
+	})
+}
+
+func TestCompareIDs(t *testing.T) {
+	a := uuid.MustParse("0f000000-0000-0000-0000-000000000000")
+	b := uuid.MustParse("a0000000-0000-0000-0000-000000000000")
+	if compareIDs(a, b) >= 0 || compareIDs(b, a) <= 0 || compareIDs(a, a) != 0 {
+		t.Error("expected byte order to match string order")
+	}
+	if (a.String() < b.String()) != (compareIDs(a, b) < 0) {
+		t.Error("byte order disagrees with string order")
+	}
+}
@@ -0,0 +1,126 @@
+package otelgrub
+
+import (
+	"context"
+
+	"github.com/zoobzio/grub"
+)
+
+// Bucket wraps a grub.Bucket with a span per operation.
+type Bucket[T any] struct {
+	bucket *grub.Bucket[T]
+	cfg    *config
+}
+
+// WrapBucket returns a traced view of bucket.
+func WrapBucket[T any](bucket *grub.Bucket[T], opts ...Option) *Bucket[T] {
+	return &Bucket[T]{bucket: bucket, cfg: newConfig("Bucket", "", opts)}
+}
+
+// Unwrap returns the underlying grub.Bucket for untraced access.
+func (b *Bucket[T]) Unwrap() *grub.Bucket[T] {
+	return b.bucket
+}
+
+// Get retrieves the object at key.
+func (b *Bucket[T]) Get(ctx context.Context, key string) (*grub.Object[T], error) {
+	ctx, span := b.cfg.start(ctx, "Get", b.cfg.key(key))
+	obj, err := b.bucket.Get(ctx, key)
+	end(span, err)
+	return obj, err
+}
+
+// Put stores an object.
+func (b *Bucket[T]) Put(ctx context.Context, obj *grub.Object[T]) error {
+	ctx, span := b.cfg.start(ctx, "Put", b.cfg.key(obj.Key))
+	err := b.bucket.Put(ctx, obj)
+	end(span, err)
+	return err
+}
+
+// PutInfo stores an object and returns the provider's view of it.
+func (b *Bucket[T]) PutInfo(ctx context.Context, obj *grub.Object[T]) (*grub.ObjectInfo, error) {
+	ctx, span := b.cfg.start(ctx, "PutInfo", b.cfg.key(obj.Key))
+	info, err := b.bucket.PutInfo(ctx, obj)
+	end(span, err)
+	return info, err
+}
+
+// ListVersions returns every stored version of key.
+func (b *Bucket[T]) ListVersions(ctx context.Context, key string) ([]grub.ObjectVersion, error) {
+	ctx, span := b.cfg.start(ctx, "ListVersions", b.cfg.key(key))
+	versions, err := b.bucket.ListVersions(ctx, key)
+	span.SetAttributes(ResultCountKey.Int(len(versions)))
+	end(span, err)
+	return versions, err
+}
+
+// GetVersion retrieves a specific version of the object at key.
+func (b *Bucket[T]) GetVersion(ctx context.Context, key, versionID string) (*grub.Object[T], error) {
+	ctx, span := b.cfg.start(ctx, "GetVersion", b.cfg.key(key))
+	obj, err := b.bucket.GetVersion(ctx, key, versionID)
+	end(span, err)
+	return obj, err
+}
+
+// NewMultipartUpload starts a multipart upload to key. Only the start is
+// traced; part uploads, Complete, and Abort go straight to the provider.
+func (b *Bucket[T]) NewMultipartUpload(ctx context.Context, key, contentType string) (*grub.MultipartUpload, error) {
+	ctx, span := b.cfg.start(ctx, "NewMultipartUpload", b.cfg.key(key))
+	upload, err := b.bucket.NewMultipartUpload(ctx, key, contentType)
+	end(span, err)
+	return upload, err
+}
+
+// Delete removes the object at key.
+func (b *Bucket[T]) Delete(ctx context.Context, key string) error {
+	ctx, span := b.cfg.start(ctx, "Delete", b.cfg.key(key))
+	err := b.bucket.Delete(ctx, key)
+	end(span, err)
+	return err
+}
+
+// DeletePrefix removes every object under prefix and returns how many were removed.
+func (b *Bucket[T]) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	ctx, span := b.cfg.start(ctx, "DeletePrefix", PrefixKey.String(prefix))
+	n, err := b.bucket.DeletePrefix(ctx, prefix)
+	span.SetAttributes(ResultCountKey.Int64(n))
+	end(span, err)
+	return n, err
+}
+
+// Exists checks whether an object exists at key.
+func (b *Bucket[T]) Exists(ctx context.Context, key string) (bool, error) {
+	ctx, span := b.cfg.start(ctx, "Exists", b.cfg.key(key))
+	exists, err := b.bucket.Exists(ctx, key)
+	end(span, err)
+	return exists, err
+}
+
+// List returns object info for keys matching the given prefix.
+func (b *Bucket[T]) List(ctx context.Context, prefix string, limit int) ([]grub.ObjectInfo, error) {
+	ctx, span := b.cfg.start(ctx, "List", PrefixKey.String(prefix), LimitKey.Int(limit))
+	infos, err := b.bucket.List(ctx, prefix, limit)
+	span.SetAttributes(ResultCountKey.Int(len(infos)))
+	end(span, err)
+	return infos, err
+}
+
+// ListPage returns one page of object info under prefix and the next cursor.
+func (b *Bucket[T]) ListPage(ctx context.Context, prefix string, limit int, cursor string) ([]grub.ObjectInfo, string, error) {
+	ctx, span := b.cfg.start(ctx, "ListPage", PrefixKey.String(prefix), LimitKey.Int(limit))
+	infos, next, err := b.bucket.ListPage(ctx, prefix, limit, cursor)
+	span.SetAttributes(ResultCountKey.Int(len(infos)))
+	end(span, err)
+	return infos, next, err
+}
+
+// FilterByMetadata returns info for objects under prefix whose metadata
+// contains every pair in match.
+func (b *Bucket[T]) FilterByMetadata(ctx context.Context, prefix string, match map[string]string, limit int) ([]grub.ObjectInfo, error) {
+	ctx, span := b.cfg.start(ctx, "FilterByMetadata", PrefixKey.String(prefix), LimitKey.Int(limit))
+	infos, err := b.bucket.FilterByMetadata(ctx, prefix, match, limit)
+	span.SetAttributes(ResultCountKey.Int(len(infos)))
+	end(span, err)
+	return infos, err
+}
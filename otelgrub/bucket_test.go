@@ -0,0 +1,81 @@
+package otelgrub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zoobzio/grub"
+)
+
+// memBucket is an in-memory grub.BucketProvider for tests.
+type memBucket struct {
+	data map[string][]byte
+	info map[string]grub.ObjectInfo
+}
+
+func newMemBucket() *memBucket {
+	return &memBucket{data: make(map[string][]byte), info: make(map[string]grub.ObjectInfo)}
+}
+
+func (m *memBucket) Get(_ context.Context, key string) ([]byte, *grub.ObjectInfo, error) {
+	d, ok := m.data[key]
+	if !ok {
+		return nil, nil, grub.ErrNotFound
+	}
+	info := m.info[key]
+	return d, &info, nil
+}
+
+func (m *memBucket) Put(_ context.Context, key string, data []byte, info *grub.ObjectInfo) error {
+	m.data[key] = data
+	m.info[key] = *info
+	return nil
+}
+
+func (m *memBucket) Delete(_ context.Context, key string) error {
+	if _, ok := m.data[key]; !ok {
+		return grub.ErrNotFound
+	}
+	delete(m.data, key)
+	delete(m.info, key)
+	return nil
+}
+
+func (m *memBucket) Exists(_ context.Context, key string) (bool, error) {
+	_, ok := m.data[key]
+	return ok, nil
+}
+
+func (m *memBucket) List(_ context.Context, _ string, _ int) ([]grub.ObjectInfo, error) {
+	out := make([]grub.ObjectInfo, 0, len(m.info))
+	for _, info := range m.info {
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+type document struct {
+	Title string `json:"title"`
+}
+
+func TestBucket_Spans(t *testing.T) {
+	ctx := context.Background()
+	sr, opt := newRecorder()
+	bucket := WrapBucket(grub.NewBucket[document](newMemBucket()), opt, WithName("docs"), WithHashedKeys())
+
+	err := bucket.Put(ctx, &grub.Object[document]{Key: "reports/q1.json", Data: document{Title: "Q1"}})
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	span := onlySpan(t, sr)
+	if span.Name() != "grub.Bucket.Put" {
+		t.Errorf("expected span 'grub.Bucket.Put', got %q", span.Name())
+	}
+	if got := attr(span, TableKey).AsString(); got != "docs" {
+		t.Errorf("expected table 'docs', got %q", got)
+	}
+	if got := attr(span, KeyKey).AsString(); got == "reports/q1.json" || got == "" {
+		t.Errorf("expected hashed key, got %q", got)
+	}
+}
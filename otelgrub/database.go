@@ -0,0 +1,300 @@
+package otelgrub
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub"
+)
+
+// Database wraps a grub.Database with a span per operation.
+type Database[T any] struct {
+	db  *grub.Database[T]
+	cfg *config
+}
+
+// WrapDatabase returns a traced view of db.
+// The table name is recorded on every span unless overridden with WithName.
+func WrapDatabase[T any](db *grub.Database[T], opts ...Option) *Database[T] {
+	return &Database[T]{db: db, cfg: newConfig("Database", db.Table(), opts)}
+}
+
+// Unwrap returns the underlying grub.Database for untraced access (builders, Atomic).
+func (d *Database[T]) Unwrap() *grub.Database[T] {
+	return d.db
+}
+
+// Get retrieves the record at key.
+func (d *Database[T]) Get(ctx context.Context, key string) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "Get", d.cfg.key(key))
+	result, err := d.db.Get(ctx, key)
+	end(span, err)
+	return result, err
+}
+
+// GetByKey retrieves the record whose primary key equals key.
+func (d *Database[T]) GetByKey(ctx context.Context, key any) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "GetByKey", d.cfg.key(fmt.Sprint(key)))
+	result, err := d.db.GetByKey(ctx, key)
+	end(span, err)
+	return result, err
+}
+
+// Set stores value at key.
+func (d *Database[T]) Set(ctx context.Context, key string, value *T) error {
+	ctx, span := d.cfg.start(ctx, "Set", d.cfg.key(key))
+	err := d.db.Set(ctx, key, value)
+	end(span, err)
+	return err
+}
+
+// Patch updates only the named columns of the record at key.
+func (d *Database[T]) Patch(ctx context.Context, key string, fields map[string]any) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "Patch", d.cfg.key(key))
+	result, err := d.db.Patch(ctx, key, fields)
+	end(span, err)
+	return result, err
+}
+
+// PatchStruct updates the columns patch sets on the record at key.
+func (d *Database[T]) PatchStruct(ctx context.Context, key string, patch any) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "PatchStruct", d.cfg.key(key))
+	result, err := d.db.PatchStruct(ctx, key, patch)
+	end(span, err)
+	return result, err
+}
+
+// Delete removes the record at key.
+func (d *Database[T]) Delete(ctx context.Context, key string) error {
+	ctx, span := d.cfg.start(ctx, "Delete", d.cfg.key(key))
+	err := d.db.Delete(ctx, key)
+	end(span, err)
+	return err
+}
+
+// InsertReturning inserts record and returns the stored row with its generated key.
+func (d *Database[T]) InsertReturning(ctx context.Context, record *T) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "InsertReturning")
+	result, err := d.db.InsertReturning(ctx, record)
+	end(span, err)
+	return result, err
+}
+
+// Exists checks whether a record exists at key.
+func (d *Database[T]) Exists(ctx context.Context, key string) (bool, error) {
+	ctx, span := d.cfg.start(ctx, "Exists", d.cfg.key(key))
+	exists, err := d.db.Exists(ctx, key)
+	end(span, err)
+	return exists, err
+}
+
+// ExecQuery executes a query statement and returns multiple records.
+func (d *Database[T]) ExecQuery(ctx context.Context, stmt edamame.QueryStatement, params map[string]any) ([]*T, error) {
+	ctx, span := d.cfg.start(ctx, "ExecQuery", StatementKey.String(stmt.Name()))
+	results, err := d.db.ExecQuery(ctx, stmt, params)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// QueryAfter returns the page of records ordered by orderCol after the cursor.
+func (d *Database[T]) QueryAfter(ctx context.Context, orderCol string, after any, limit int) ([]*T, any, error) {
+	ctx, span := d.cfg.start(ctx, "QueryAfter")
+	results, next, err := d.db.QueryAfter(ctx, orderCol, after, limit)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, next, err
+}
+
+// ExecSelect executes a select statement and returns a single record.
+func (d *Database[T]) ExecSelect(ctx context.Context, stmt edamame.SelectStatement, params map[string]any) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "ExecSelect", StatementKey.String(stmt.Name()))
+	result, err := d.db.ExecSelect(ctx, stmt, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecUpdate executes an update statement.
+func (d *Database[T]) ExecUpdate(ctx context.Context, stmt edamame.UpdateStatement, params map[string]any) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "ExecUpdate", StatementKey.String(stmt.Name()))
+	result, err := d.db.ExecUpdate(ctx, stmt, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecAggregate executes an aggregate statement.
+func (d *Database[T]) ExecAggregate(ctx context.Context, stmt edamame.AggregateStatement, params map[string]any) (float64, error) {
+	ctx, span := d.cfg.start(ctx, "ExecAggregate", StatementKey.String(stmt.Name()))
+	result, err := d.db.ExecAggregate(ctx, stmt, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecAggregateInt executes an aggregate statement returning an int64.
+func (d *Database[T]) ExecAggregateInt(ctx context.Context, stmt edamame.AggregateStatement, params map[string]any) (int64, error) {
+	ctx, span := d.cfg.start(ctx, "ExecAggregateInt", StatementKey.String(stmt.Name()))
+	result, err := d.db.ExecAggregateInt(ctx, stmt, params)
+	end(span, err)
+	return result, err
+}
+
+// CountAllInt counts all records in the table.
+func (d *Database[T]) CountAllInt(ctx context.Context) (int64, error) {
+	ctx, span := d.cfg.start(ctx, "CountAllInt", StatementKey.String(grub.CountAll.Name()))
+	result, err := d.db.CountAllInt(ctx)
+	end(span, err)
+	return result, err
+}
+
+// ExecMultiAggregate computes several aggregates in one statement.
+func (d *Database[T]) ExecMultiAggregate(ctx context.Context, specs []grub.AggregateSpec, where []edamame.ConditionSpec, params map[string]any) (map[string]float64, error) {
+	ctx, span := d.cfg.start(ctx, "ExecMultiAggregate")
+	result, err := d.db.ExecMultiAggregate(ctx, specs, where, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecAggregateGroup computes an aggregate per group in one statement.
+func (d *Database[T]) ExecAggregateGroup(ctx context.Context, spec grub.AggregateGroupSpec, params map[string]any) ([]grub.AggregateRow, error) {
+	ctx, span := d.cfg.start(ctx, "ExecAggregateGroup")
+	result, err := d.db.ExecAggregateGroup(ctx, spec, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecRaw runs arbitrary SQL and scans the rows into T.
+// The query text is not recorded on the span.
+func (d *Database[T]) ExecRaw(ctx context.Context, query string, args ...any) ([]*T, error) {
+	ctx, span := d.cfg.start(ctx, "ExecRaw")
+	results, err := d.db.ExecRaw(ctx, query, args...)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// GetTx retrieves the record at key within a transaction.
+func (d *Database[T]) GetTx(ctx context.Context, tx *sqlx.Tx, key string) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "GetTx", d.cfg.key(key), txAttr)
+	result, err := d.db.GetTx(ctx, tx, key)
+	end(span, err)
+	return result, err
+}
+
+// SetTx stores value at key within a transaction.
+func (d *Database[T]) SetTx(ctx context.Context, tx *sqlx.Tx, key string, value *T) error {
+	ctx, span := d.cfg.start(ctx, "SetTx", d.cfg.key(key), txAttr)
+	err := d.db.SetTx(ctx, tx, key, value)
+	end(span, err)
+	return err
+}
+
+// InsertReturningTx inserts record within a transaction and returns the stored row.
+func (d *Database[T]) InsertReturningTx(ctx context.Context, tx *sqlx.Tx, record *T) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "InsertReturningTx", txAttr)
+	result, err := d.db.InsertReturningTx(ctx, tx, record)
+	end(span, err)
+	return result, err
+}
+
+// PatchTx updates only the named columns of the record at key within a transaction.
+func (d *Database[T]) PatchTx(ctx context.Context, tx *sqlx.Tx, key string, fields map[string]any) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "PatchTx", d.cfg.key(key), txAttr)
+	result, err := d.db.PatchTx(ctx, tx, key, fields)
+	end(span, err)
+	return result, err
+}
+
+// PatchStructTx updates the columns patch sets on the record at key within a transaction.
+func (d *Database[T]) PatchStructTx(ctx context.Context, tx *sqlx.Tx, key string, patch any) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "PatchStructTx", d.cfg.key(key), txAttr)
+	result, err := d.db.PatchStructTx(ctx, tx, key, patch)
+	end(span, err)
+	return result, err
+}
+
+// DeleteTx removes the record at key within a transaction.
+func (d *Database[T]) DeleteTx(ctx context.Context, tx *sqlx.Tx, key string) error {
+	ctx, span := d.cfg.start(ctx, "DeleteTx", d.cfg.key(key), txAttr)
+	err := d.db.DeleteTx(ctx, tx, key)
+	end(span, err)
+	return err
+}
+
+// ExistsTx checks whether a record exists at key within a transaction.
+func (d *Database[T]) ExistsTx(ctx context.Context, tx *sqlx.Tx, key string) (bool, error) {
+	ctx, span := d.cfg.start(ctx, "ExistsTx", d.cfg.key(key), txAttr)
+	exists, err := d.db.ExistsTx(ctx, tx, key)
+	end(span, err)
+	return exists, err
+}
+
+// ExecQueryTx executes a query statement within a transaction.
+func (d *Database[T]) ExecQueryTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.QueryStatement, params map[string]any) ([]*T, error) {
+	ctx, span := d.cfg.start(ctx, "ExecQueryTx", StatementKey.String(stmt.Name()), txAttr)
+	results, err := d.db.ExecQueryTx(ctx, tx, stmt, params)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// ExecSelectTx executes a select statement within a transaction.
+func (d *Database[T]) ExecSelectTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.SelectStatement, params map[string]any) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "ExecSelectTx", StatementKey.String(stmt.Name()), txAttr)
+	result, err := d.db.ExecSelectTx(ctx, tx, stmt, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecUpdateTx executes an update statement within a transaction.
+func (d *Database[T]) ExecUpdateTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.UpdateStatement, params map[string]any) (*T, error) {
+	ctx, span := d.cfg.start(ctx, "ExecUpdateTx", StatementKey.String(stmt.Name()), txAttr)
+	result, err := d.db.ExecUpdateTx(ctx, tx, stmt, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecAggregateTx executes an aggregate statement within a transaction.
+func (d *Database[T]) ExecAggregateTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.AggregateStatement, params map[string]any) (float64, error) {
+	ctx, span := d.cfg.start(ctx, "ExecAggregateTx", StatementKey.String(stmt.Name()), txAttr)
+	result, err := d.db.ExecAggregateTx(ctx, tx, stmt, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecAggregateIntTx executes an aggregate statement returning an int64 within a transaction.
+func (d *Database[T]) ExecAggregateIntTx(ctx context.Context, tx *sqlx.Tx, stmt edamame.AggregateStatement, params map[string]any) (int64, error) {
+	ctx, span := d.cfg.start(ctx, "ExecAggregateIntTx", StatementKey.String(stmt.Name()), txAttr)
+	result, err := d.db.ExecAggregateIntTx(ctx, tx, stmt, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecMultiAggregateTx computes several aggregates in one statement within a transaction.
+func (d *Database[T]) ExecMultiAggregateTx(ctx context.Context, tx *sqlx.Tx, specs []grub.AggregateSpec, where []edamame.ConditionSpec, params map[string]any) (map[string]float64, error) {
+	ctx, span := d.cfg.start(ctx, "ExecMultiAggregateTx", txAttr)
+	result, err := d.db.ExecMultiAggregateTx(ctx, tx, specs, where, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecAggregateGroupTx computes an aggregate per group in one statement within a transaction.
+func (d *Database[T]) ExecAggregateGroupTx(ctx context.Context, tx *sqlx.Tx, spec grub.AggregateGroupSpec, params map[string]any) ([]grub.AggregateRow, error) {
+	ctx, span := d.cfg.start(ctx, "ExecAggregateGroupTx", txAttr)
+	result, err := d.db.ExecAggregateGroupTx(ctx, tx, spec, params)
+	end(span, err)
+	return result, err
+}
+
+// ExecRawTx runs arbitrary SQL within a transaction.
+func (d *Database[T]) ExecRawTx(ctx context.Context, tx *sqlx.Tx, query string, args ...any) ([]*T, error) {
+	ctx, span := d.cfg.start(ctx, "ExecRawTx", txAttr)
+	results, err := d.db.ExecRawTx(ctx, tx, query, args...)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// txAttr marks spans for operations running inside a caller-supplied transaction.
+var txAttr = TxKey.Bool(true)
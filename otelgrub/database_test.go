@@ -0,0 +1,154 @@
+package otelgrub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	astqlsqlite "github.com/zoobzio/astql/sqlite"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/sentinel"
+	"go.opentelemetry.io/otel/codes"
+	_ "modernc.org/sqlite"
+)
+
+func init() {
+	sentinel.Tag("db")
+	sentinel.Tag("constraints")
+}
+
+type user struct {
+	ID    int    `db:"id" constraints:"primarykey"`
+	Email string `db:"email" constraints:"notnull,unique"`
+}
+
+func newUserDB(t *testing.T) (*grub.Database[user], *sqlx.DB) {
+	t.Helper()
+	conn, err := sqlx.Connect("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	if _, err := conn.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE)`); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+	db, err := grub.NewDatabase[user](conn, "users", astqlsqlite.New())
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	return db, conn
+}
+
+func TestDatabase_Spans(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Set and table attribute", func(t *testing.T) {
+		sr, opt := newRecorder()
+		inner, _ := newUserDB(t)
+		db := WrapDatabase(inner, opt)
+
+		if err := db.Set(ctx, "1", &user{ID: 1, Email: "a@example.com"}); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if span.Name() != "grub.Database.Set" {
+			t.Errorf("expected span 'grub.Database.Set', got %q", span.Name())
+		}
+		if got := attr(span, TableKey).AsString(); got != "users" {
+			t.Errorf("expected table 'users', got %q", got)
+		}
+		if got := attr(span, KeyKey).AsString(); got != "1" {
+			t.Errorf("expected key '1', got %q", got)
+		}
+	})
+
+	t.Run("Get error status", func(t *testing.T) {
+		sr, opt := newRecorder()
+		inner, _ := newUserDB(t)
+		db := WrapDatabase(inner, opt)
+
+		_, err := db.Get(ctx, "404")
+		if !errors.Is(err, grub.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		if span := onlySpan(t, sr); span.Status().Code != codes.Error {
+			t.Errorf("expected error status, got %v", span.Status().Code)
+		}
+	})
+
+	t.Run("ExecQuery result count", func(t *testing.T) {
+		sr, opt := newRecorder()
+		inner, _ := newUserDB(t)
+		_ = inner.Set(ctx, "1", &user{ID: 1, Email: "a@example.com"})
+		_ = inner.Set(ctx, "2", &user{ID: 2, Email: "b@example.com"})
+		db := WrapDatabase(inner, opt)
+
+		if _, err := db.ExecQuery(ctx, grub.QueryAll, nil); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if got := attr(span, ResultCountKey).AsInt64(); got != 2 {
+			t.Errorf("expected result count 2, got %d", got)
+		}
+		if got := attr(span, StatementKey).AsString(); got != grub.QueryAll.Name() {
+			t.Errorf("expected statement %q, got %q", grub.QueryAll.Name(), got)
+		}
+	})
+
+	t.Run("QueryAfter result count", func(t *testing.T) {
+		sr, opt := newRecorder()
+		inner, _ := newUserDB(t)
+		_ = inner.Set(ctx, "1", &user{ID: 1, Email: "a@example.com"})
+		_ = inner.Set(ctx, "2", &user{ID: 2, Email: "b@example.com"})
+		db := WrapDatabase(inner, opt)
+
+		if _, _, err := db.QueryAfter(ctx, "id", 1, 10); err != nil {
+			t.Fatalf("QueryAfter failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if span.Name() != "grub.Database.QueryAfter" {
+			t.Errorf("expected span 'grub.Database.QueryAfter', got %q", span.Name())
+		}
+		if got := attr(span, ResultCountKey).AsInt64(); got != 1 {
+			t.Errorf("expected result count 1, got %d", got)
+		}
+	})
+
+	t.Run("ExecRaw result count", func(t *testing.T) {
+		sr, opt := newRecorder()
+		inner, _ := newUserDB(t)
+		_ = inner.Set(ctx, "1", &user{ID: 1, Email: "a@example.com"})
+		db := WrapDatabase(inner, opt)
+
+		if _, err := db.ExecRaw(ctx, `SELECT id, email FROM users WHERE id = ?`, 1); err != nil {
+			t.Fatalf("ExecRaw failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if span.Name() != "grub.Database.ExecRaw" {
+			t.Errorf("expected span 'grub.Database.ExecRaw', got %q", span.Name())
+		}
+		if got := attr(span, ResultCountKey).AsInt64(); got != 1 {
+			t.Errorf("expected result count 1, got %d", got)
+		}
+	})
+
+	t.Run("Tx attribute", func(t *testing.T) {
+		sr, opt := newRecorder()
+		inner, conn := newUserDB(t)
+		db := WrapDatabase(inner, opt)
+
+		tx, err := conn.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTxx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if _, err := db.ExistsTx(ctx, tx, "1"); err != nil {
+			t.Fatalf("ExistsTx failed: %v", err)
+		}
+		if !attr(onlySpan(t, sr), TxKey).AsBool() {
+			t.Error("expected grub.tx attribute")
+		}
+	})
+}
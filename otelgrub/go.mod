@@ -0,0 +1,17 @@
+module github.com/zoobzio/grub/otelgrub
+
+go 1.24.0
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/jmoiron/sqlx v1.4.0
+	github.com/zoobzio/astql v1.0.6
+	github.com/zoobzio/edamame v1.0.1
+	github.com/zoobzio/grub v0.0.0
+	github.com/zoobzio/sentinel v1.0.2
+	github.com/zoobzio/vecna v0.0.2
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/sdk v1.38.0
+	go.opentelemetry.io/otel/trace v1.38.0
+	modernc.org/sqlite v1.42.2
+)
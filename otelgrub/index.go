@@ -0,0 +1,169 @@
+package otelgrub
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/vecna"
+)
+
+// Index wraps a grub.Index with a span per operation.
+type Index[T any] struct {
+	index *grub.Index[T]
+	cfg   *config
+}
+
+// WrapIndex returns a traced view of index.
+func WrapIndex[T any](index *grub.Index[T], opts ...Option) *Index[T] {
+	return &Index[T]{index: index, cfg: newConfig("Index", "", opts)}
+}
+
+// Unwrap returns the underlying grub.Index for untraced access.
+func (i *Index[T]) Unwrap() *grub.Index[T] {
+	return i.index
+}
+
+// Upsert stores or updates a vector with associated metadata.
+func (i *Index[T]) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata *T) error {
+	ctx, span := i.cfg.start(ctx, "Upsert", i.cfg.key(id.String()))
+	err := i.index.Upsert(ctx, id, vector, metadata)
+	end(span, err)
+	return err
+}
+
+// UpsertBatch stores or updates multiple vectors.
+func (i *Index[T]) UpsertBatch(ctx context.Context, vectors []grub.Vector[T]) error {
+	ctx, span := i.cfg.start(ctx, "UpsertBatch", BatchSizeKey.Int(len(vectors)))
+	err := i.index.UpsertBatch(ctx, vectors)
+	end(span, err)
+	return err
+}
+
+// Get retrieves a vector by ID.
+func (i *Index[T]) Get(ctx context.Context, id uuid.UUID) (*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "Get", i.cfg.key(id.String()))
+	result, err := i.index.Get(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+// Delete removes a vector by ID.
+func (i *Index[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := i.cfg.start(ctx, "Delete", i.cfg.key(id.String()))
+	err := i.index.Delete(ctx, id)
+	end(span, err)
+	return err
+}
+
+// DeleteBatch removes multiple vectors by ID.
+func (i *Index[T]) DeleteBatch(ctx context.Context, ids []uuid.UUID) error {
+	ctx, span := i.cfg.start(ctx, "DeleteBatch", BatchSizeKey.Int(len(ids)))
+	err := i.index.DeleteBatch(ctx, ids)
+	end(span, err)
+	return err
+}
+
+// Search performs similarity search and returns the k nearest neighbors.
+func (i *Index[T]) Search(ctx context.Context, vector []float32, k int, filter *T) ([]*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "Search", LimitKey.Int(k))
+	results, err := i.index.Search(ctx, vector, k, filter)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// Query performs similarity search with vecna filter support.
+func (i *Index[T]) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "Query", LimitKey.Int(k))
+	results, err := i.index.Query(ctx, vector, k, filter)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// SearchPage performs similarity search, skipping the first offset results.
+func (i *Index[T]) SearchPage(ctx context.Context, vector []float32, k, offset int, filter *T) ([]*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "SearchPage", LimitKey.Int(k), OffsetKey.Int(offset))
+	results, err := i.index.SearchPage(ctx, vector, k, offset, filter)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// QueryPage performs similarity search with vecna filter support, skipping
+// the first offset results.
+func (i *Index[T]) QueryPage(ctx context.Context, vector []float32, k, offset int, filter *vecna.Filter) ([]*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "QueryPage", LimitKey.Int(k), OffsetKey.Int(offset))
+	results, err := i.index.QueryPage(ctx, vector, k, offset, filter)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// Recommend returns vectors similar to the stored vector at id.
+func (i *Index[T]) Recommend(ctx context.Context, id uuid.UUID, k int, filter *vecna.Filter) ([]*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "Recommend", i.cfg.key(id.String()), LimitKey.Int(k))
+	results, err := i.index.Recommend(ctx, id, k, filter)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// RecommendMulti returns vectors similar to the average of the stored vectors at ids.
+func (i *Index[T]) RecommendMulti(ctx context.Context, ids []uuid.UUID, k int, filter *vecna.Filter) ([]*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "RecommendMulti", BatchSizeKey.Int(len(ids)), LimitKey.Int(k))
+	results, err := i.index.RecommendMulti(ctx, ids, k, filter)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// SearchBatch performs one similarity search per query vector.
+// The result count is the total across all queries.
+func (i *Index[T]) SearchBatch(ctx context.Context, queries [][]float32, k int, filter *vecna.Filter) ([][]*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "SearchBatch", BatchSizeKey.Int(len(queries)), LimitKey.Int(k))
+	batches, err := i.index.SearchBatch(ctx, queries, k, filter)
+	count := 0
+	for _, results := range batches {
+		count += len(results)
+	}
+	span.SetAttributes(ResultCountKey.Int(count))
+	end(span, err)
+	return batches, err
+}
+
+// HybridSearch returns the best matches for a combined keyword and vector query.
+func (i *Index[T]) HybridSearch(ctx context.Context, text string, vector []float32, k int, alpha float32, filter *vecna.Filter) ([]*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "HybridSearch", LimitKey.Int(k))
+	results, err := i.index.HybridSearch(ctx, text, vector, k, alpha, filter)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// Filter returns vectors matching the metadata filter without similarity search.
+func (i *Index[T]) Filter(ctx context.Context, filter *vecna.Filter, limit int) ([]*grub.Vector[T], error) {
+	ctx, span := i.cfg.start(ctx, "Filter", LimitKey.Int(limit))
+	results, err := i.index.Filter(ctx, filter, limit)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// List returns vector IDs.
+func (i *Index[T]) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	ctx, span := i.cfg.start(ctx, "List", LimitKey.Int(limit))
+	ids, err := i.index.List(ctx, limit)
+	span.SetAttributes(ResultCountKey.Int(len(ids)))
+	end(span, err)
+	return ids, err
+}
+
+// Exists checks whether a vector ID exists.
+func (i *Index[T]) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	ctx, span := i.cfg.start(ctx, "Exists", i.cfg.key(id.String()))
+	exists, err := i.index.Exists(ctx, id)
+	end(span, err)
+	return exists, err
+}
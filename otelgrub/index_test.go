@@ -0,0 +1,214 @@
+package otelgrub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/vecna"
+)
+
+// memIndex is an in-memory grub.VectorProvider for tests.
+type memIndex struct {
+	vectors map[uuid.UUID]grub.VectorRecord
+}
+
+func newMemIndex() *memIndex { return &memIndex{vectors: make(map[uuid.UUID]grub.VectorRecord)} }
+
+func (m *memIndex) Upsert(_ context.Context, id uuid.UUID, vector []float32, metadata []byte) error {
+	m.vectors[id] = grub.VectorRecord{ID: id, Vector: vector, Metadata: metadata}
+	return nil
+}
+
+func (m *memIndex) UpsertBatch(_ context.Context, records []grub.VectorRecord) error {
+	for _, r := range records {
+		m.vectors[r.ID] = r
+	}
+	return nil
+}
+
+func (m *memIndex) Get(_ context.Context, id uuid.UUID) ([]float32, *grub.VectorInfo, error) {
+	r, ok := m.vectors[id]
+	if !ok {
+		return nil, nil, grub.ErrNotFound
+	}
+	return r.Vector, &grub.VectorInfo{ID: id, Dimension: len(r.Vector), Metadata: r.Metadata}, nil
+}
+
+func (m *memIndex) Delete(_ context.Context, id uuid.UUID) error {
+	if _, ok := m.vectors[id]; !ok {
+		return grub.ErrNotFound
+	}
+	delete(m.vectors, id)
+	return nil
+}
+
+func (m *memIndex) DeleteBatch(_ context.Context, ids []uuid.UUID) error {
+	for _, id := range ids {
+		delete(m.vectors, id)
+	}
+	return nil
+}
+
+func (m *memIndex) Search(_ context.Context, _ []float32, k int, _ map[string]any) ([]grub.VectorResult, error) {
+	out := make([]grub.VectorResult, 0, k)
+	for _, r := range m.vectors {
+		if len(out) == k {
+			break
+		}
+		out = append(out, grub.VectorResult{ID: r.ID, Vector: r.Vector, Metadata: r.Metadata})
+	}
+	return out, nil
+}
+
+func (m *memIndex) Query(ctx context.Context, vector []float32, k int, _ *vecna.Filter) ([]grub.VectorResult, error) {
+	return m.Search(ctx, vector, k, nil)
+}
+
+func (m *memIndex) Filter(_ context.Context, _ *vecna.Filter, _ int) ([]grub.VectorResult, error) {
+	return nil, grub.ErrFilterNotSupported
+}
+
+func (m *memIndex) List(_ context.Context, _ int) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(m.vectors))
+	for id := range m.vectors {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (m *memIndex) Exists(_ context.Context, id uuid.UUID) (bool, error) {
+	_, ok := m.vectors[id]
+	return ok, nil
+}
+
+type embeddingMeta struct {
+	Source string `json:"source"`
+}
+
+func TestIndex_Spans(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("UpsertBatch", func(t *testing.T) {
+		sr, opt := newRecorder()
+		index := WrapIndex(grub.NewIndex[embeddingMeta](newMemIndex()), opt, WithName("embeddings"))
+
+		vectors := []grub.Vector[embeddingMeta]{
+			{ID: uuid.New(), Vector: []float32{1, 0}},
+			{ID: uuid.New(), Vector: []float32{0, 1}},
+		}
+		if err := index.UpsertBatch(ctx, vectors); err != nil {
+			t.Fatalf("UpsertBatch failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if span.Name() != "grub.Index.UpsertBatch" {
+			t.Errorf("expected span 'grub.Index.UpsertBatch', got %q", span.Name())
+		}
+		if got := attr(span, BatchSizeKey).AsInt64(); got != 2 {
+			t.Errorf("expected batch size 2, got %d", got)
+		}
+		if got := attr(span, TableKey).AsString(); got != "embeddings" {
+			t.Errorf("expected table 'embeddings', got %q", got)
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		sr, opt := newRecorder()
+		mem := newMemIndex()
+		mem.vectors[uuid.New()] = grub.VectorRecord{Vector: []float32{1, 0}}
+		index := WrapIndex(grub.NewIndex[embeddingMeta](mem), opt)
+
+		if _, err := index.Search(ctx, []float32{1, 0}, 10, nil); err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if got := attr(span, LimitKey).AsInt64(); got != 10 {
+			t.Errorf("expected limit 10, got %d", got)
+		}
+		if got := attr(span, ResultCountKey).AsInt64(); got != 1 {
+			t.Errorf("expected result count 1, got %d", got)
+		}
+	})
+
+	t.Run("SearchPage", func(t *testing.T) {
+		sr, opt := newRecorder()
+		mem := newMemIndex()
+		for range 3 {
+			mem.vectors[uuid.New()] = grub.VectorRecord{Vector: []float32{1, 0}}
+		}
+		index := WrapIndex(grub.NewIndex[embeddingMeta](mem), opt)
+
+		if _, err := index.SearchPage(ctx, []float32{1, 0}, 2, 2, nil); err != nil {
+			t.Fatalf("SearchPage failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if span.Name() != "grub.Index.SearchPage" {
+			t.Errorf("expected span 'grub.Index.SearchPage', got %q", span.Name())
+		}
+		if got := attr(span, OffsetKey).AsInt64(); got != 2 {
+			t.Errorf("expected offset 2, got %d", got)
+		}
+		if got := attr(span, ResultCountKey).AsInt64(); got != 1 {
+			t.Errorf("expected result count 1, got %d", got)
+		}
+	})
+
+	t.Run("Recommend", func(t *testing.T) {
+		sr, opt := newRecorder()
+		mem := newMemIndex()
+		seed := uuid.New()
+		mem.vectors[seed] = grub.VectorRecord{ID: seed, Vector: []float32{1, 0}}
+		other := uuid.New()
+		mem.vectors[other] = grub.VectorRecord{ID: other, Vector: []float32{0, 1}}
+		index := WrapIndex(grub.NewIndex[embeddingMeta](mem), opt)
+
+		results, err := index.Recommend(ctx, seed, 5, nil)
+		if err != nil {
+			t.Fatalf("Recommend failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if span.Name() != "grub.Index.Recommend" {
+			t.Errorf("expected span 'grub.Index.Recommend', got %q", span.Name())
+		}
+		if got := attr(span, KeyKey).AsString(); got != seed.String() {
+			t.Errorf("expected key %s, got %q", seed, got)
+		}
+		if got := attr(span, ResultCountKey).AsInt64(); got != int64(len(results)) || got != 1 {
+			t.Errorf("expected result count 1, got %d", got)
+		}
+	})
+
+	t.Run("SearchBatch", func(t *testing.T) {
+		sr, opt := newRecorder()
+		mem := newMemIndex()
+		id := uuid.New()
+		mem.vectors[id] = grub.VectorRecord{ID: id, Vector: []float32{1, 0}}
+		index := WrapIndex(grub.NewIndex[embeddingMeta](mem), opt)
+
+		if _, err := index.SearchBatch(ctx, [][]float32{{1, 0}, {0, 1}, {1, 1}}, 5, nil); err != nil {
+			t.Fatalf("SearchBatch failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if span.Name() != "grub.Index.SearchBatch" {
+			t.Errorf("expected span 'grub.Index.SearchBatch', got %q", span.Name())
+		}
+		if got := attr(span, BatchSizeKey).AsInt64(); got != 3 {
+			t.Errorf("expected batch size 3, got %d", got)
+		}
+		if got := attr(span, ResultCountKey).AsInt64(); got != 3 {
+			t.Errorf("expected result count 3, got %d", got)
+		}
+	})
+
+	t.Run("Get records id", func(t *testing.T) {
+		sr, opt := newRecorder()
+		index := WrapIndex(grub.NewIndex[embeddingMeta](newMemIndex()), opt)
+
+		id := uuid.New()
+		_, _ = index.Get(ctx, id)
+		if got := attr(onlySpan(t, sr), KeyKey).AsString(); got != id.String() {
+			t.Errorf("expected key %q, got %q", id.String(), got)
+		}
+	})
+}
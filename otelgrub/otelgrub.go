@@ -0,0 +1,134 @@
+// Package otelgrub provides OpenTelemetry tracing decorators for grub.
+//
+// Each wrapper mirrors the operations of its grub counterpart and starts one
+// span per call, named like "grub.Database.Get". Spans are children of the
+// span carried by the incoming context and record the table or collection,
+// key or ID, batch sizes, result counts, and error status.
+//
+//	users := otelgrub.WrapDatabase(db)
+//	user, err := users.Get(ctx, "42") // span: grub.Database.Get
+package otelgrub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name used for the tracer.
+const ScopeName = "github.com/zoobzio/grub/otelgrub"
+
+// Span attribute keys.
+const (
+	// TableKey identifies the database table, collection, or bucket.
+	TableKey = attribute.Key("grub.table")
+
+	// KeyKey holds the record key or vector ID (hashed when WithHashedKeys is set).
+	KeyKey = attribute.Key("grub.key")
+
+	// PrefixKey holds the prefix passed to List operations.
+	PrefixKey = attribute.Key("grub.prefix")
+
+	// PatternKey holds the glob pattern passed to ListMatch.
+	PatternKey = attribute.Key("grub.pattern")
+
+	// StatementKey holds the edamame statement name for Exec operations.
+	StatementKey = attribute.Key("grub.statement")
+
+	// BatchSizeKey holds the number of records in a batch operation.
+	BatchSizeKey = attribute.Key("grub.batch_size")
+
+	// ResultCountKey holds the number of records or vectors returned.
+	ResultCountKey = attribute.Key("grub.result_count")
+
+	// LimitKey holds the requested limit (k for similarity search).
+	LimitKey = attribute.Key("grub.limit")
+
+	// OffsetKey holds the number of results skipped by a paged similarity search.
+	OffsetKey = attribute.Key("grub.offset")
+
+	// TxKey is true when the operation runs inside a caller-supplied transaction.
+	TxKey = attribute.Key("grub.tx")
+)
+
+// Option configures a tracing wrapper.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	name       string
+	hashKeys   bool
+	tracer     trace.Tracer
+	spanPrefix string
+}
+
+// WithTracerProvider sets the TracerProvider used to create spans.
+// Defaults to the global provider from otel.GetTracerProvider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = tp
+	}
+}
+
+// WithName sets the table or collection name recorded on spans.
+// WrapDatabase defaults to the database's table name.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithHashedKeys records a truncated SHA-256 of keys and IDs instead of the raw value.
+// Use when keys carry sensitive data such as email addresses.
+func WithHashedKeys() Option {
+	return func(c *config) {
+		c.hashKeys = true
+	}
+}
+
+// newConfig resolves options for a wrapper of the given kind ("Database", "Store", ...).
+func newConfig(kind, name string, opts []Option) *config {
+	c := &config{name: name, spanPrefix: "grub." + kind + "."}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.provider == nil {
+		c.provider = otel.GetTracerProvider()
+	}
+	c.tracer = c.provider.Tracer(ScopeName)
+	return c
+}
+
+// start begins a span for op with the common attributes applied.
+func (c *config) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if c.name != "" {
+		attrs = append(attrs, TableKey.String(c.name))
+	}
+	return c.tracer.Start(ctx, c.spanPrefix+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// key returns the key attribute, hashed if configured.
+func (c *config) key(k string) attribute.KeyValue {
+	if c.hashKeys {
+		sum := sha256.Sum256([]byte(k))
+		return KeyKey.String(hex.EncodeToString(sum[:8]))
+	}
+	return KeyKey.String(k)
+}
+
+// end records err on span, if any, and ends it.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
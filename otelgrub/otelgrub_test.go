@@ -0,0 +1,89 @@
+package otelgrub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newRecorder returns a span recorder and an option wiring it into a wrapper.
+func newRecorder() (*tracetest.SpanRecorder, Option) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	return sr, WithTracerProvider(tp)
+}
+
+// onlySpan asserts exactly one span was recorded and returns it.
+func onlySpan(t *testing.T, sr *tracetest.SpanRecorder) sdktrace.ReadOnlySpan {
+	t.Helper()
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	return spans[0]
+}
+
+// attr returns the value of key on span, or an empty Value if absent.
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestConfig_KeyHashing(t *testing.T) {
+	plain := newConfig("Store", "", nil)
+	if got := plain.key("user@example.com").Value.AsString(); got != "user@example.com" {
+		t.Errorf("expected raw key, got %q", got)
+	}
+
+	hashed := newConfig("Store", "", []Option{WithHashedKeys()})
+	got := hashed.key("user@example.com").Value.AsString()
+	if got == "user@example.com" || len(got) != 16 {
+		t.Errorf("expected 16-char hash, got %q", got)
+	}
+	if again := hashed.key("user@example.com").Value.AsString(); again != got {
+		t.Error("expected hashing to be deterministic")
+	}
+}
+
+func TestConfig_ParentSpan(t *testing.T) {
+	sr, opt := newRecorder()
+	cfg := newConfig("Store", "", []Option{opt})
+
+	ctx, parent := cfg.tracer.Start(context.Background(), "caller")
+	_, child := cfg.start(ctx, "Get")
+	child.End()
+	parent.End()
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected grub span to be a child of the caller span")
+	}
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	sr, opt := newRecorder()
+	cfg := newConfig("Store", "", []Option{opt})
+
+	_, span := cfg.start(context.Background(), "Get")
+	end(span, errors.New("boom"))
+
+	s := onlySpan(t, sr)
+	if s.Status().Code != codes.Error {
+		t.Errorf("expected error status, got %v", s.Status().Code)
+	}
+	if len(s.Events()) == 0 || s.Events()[0].Name != "exception" {
+		t.Error("expected recorded exception event")
+	}
+}
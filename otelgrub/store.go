@@ -0,0 +1,108 @@
+package otelgrub
+
+import (
+	"context"
+	"time"
+
+	"github.com/zoobzio/grub"
+)
+
+// Store wraps a grub.Store with a span per operation.
+type Store[T any] struct {
+	store *grub.Store[T]
+	cfg   *config
+}
+
+// WrapStore returns a traced view of store.
+func WrapStore[T any](store *grub.Store[T], opts ...Option) *Store[T] {
+	return &Store[T]{store: store, cfg: newConfig("Store", "", opts)}
+}
+
+// Unwrap returns the underlying grub.Store for untraced access.
+func (s *Store[T]) Unwrap() *grub.Store[T] {
+	return s.store
+}
+
+// Get retrieves the value at key.
+func (s *Store[T]) Get(ctx context.Context, key string) (*T, error) {
+	ctx, span := s.cfg.start(ctx, "Get", s.cfg.key(key))
+	result, err := s.store.Get(ctx, key)
+	end(span, err)
+	return result, err
+}
+
+// Set stores value at key with optional TTL.
+func (s *Store[T]) Set(ctx context.Context, key string, value *T, ttl time.Duration) error {
+	ctx, span := s.cfg.start(ctx, "Set", s.cfg.key(key))
+	err := s.store.Set(ctx, key, value, ttl)
+	end(span, err)
+	return err
+}
+
+// Update applies mutate to the value at key and stores the result.
+func (s *Store[T]) Update(ctx context.Context, key string, mutate func(*T) error, ttl time.Duration) error {
+	ctx, span := s.cfg.start(ctx, "Update", s.cfg.key(key))
+	err := s.store.Update(ctx, key, mutate, ttl)
+	end(span, err)
+	return err
+}
+
+// Delete removes the value at key.
+func (s *Store[T]) Delete(ctx context.Context, key string) error {
+	ctx, span := s.cfg.start(ctx, "Delete", s.cfg.key(key))
+	err := s.store.Delete(ctx, key)
+	end(span, err)
+	return err
+}
+
+// DeletePrefix removes every key under prefix and returns how many were removed.
+func (s *Store[T]) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	ctx, span := s.cfg.start(ctx, "DeletePrefix", PrefixKey.String(prefix))
+	n, err := s.store.DeletePrefix(ctx, prefix)
+	span.SetAttributes(ResultCountKey.Int64(n))
+	end(span, err)
+	return n, err
+}
+
+// Exists checks whether a key exists.
+func (s *Store[T]) Exists(ctx context.Context, key string) (bool, error) {
+	ctx, span := s.cfg.start(ctx, "Exists", s.cfg.key(key))
+	exists, err := s.store.Exists(ctx, key)
+	end(span, err)
+	return exists, err
+}
+
+// List returns keys matching the given prefix.
+func (s *Store[T]) List(ctx context.Context, prefix string, limit int) ([]string, error) {
+	ctx, span := s.cfg.start(ctx, "List", PrefixKey.String(prefix), LimitKey.Int(limit))
+	keys, err := s.store.List(ctx, prefix, limit)
+	span.SetAttributes(ResultCountKey.Int(len(keys)))
+	end(span, err)
+	return keys, err
+}
+
+// ListMatch returns a page of keys matching a glob pattern.
+func (s *Store[T]) ListMatch(ctx context.Context, pattern, cursor string, limit int) ([]string, string, error) {
+	ctx, span := s.cfg.start(ctx, "ListMatch", PatternKey.String(pattern), LimitKey.Int(limit))
+	keys, next, err := s.store.ListMatch(ctx, pattern, cursor, limit)
+	span.SetAttributes(ResultCountKey.Int(len(keys)))
+	end(span, err)
+	return keys, next, err
+}
+
+// GetBatch retrieves multiple values by key.
+func (s *Store[T]) GetBatch(ctx context.Context, keys []string) (map[string]*T, error) {
+	ctx, span := s.cfg.start(ctx, "GetBatch", BatchSizeKey.Int(len(keys)))
+	results, err := s.store.GetBatch(ctx, keys)
+	span.SetAttributes(ResultCountKey.Int(len(results)))
+	end(span, err)
+	return results, err
+}
+
+// SetBatch stores multiple values with optional TTL.
+func (s *Store[T]) SetBatch(ctx context.Context, items map[string]*T, ttl time.Duration) error {
+	ctx, span := s.cfg.start(ctx, "SetBatch", BatchSizeKey.Int(len(items)))
+	err := s.store.SetBatch(ctx, items, ttl)
+	end(span, err)
+	return err
+}
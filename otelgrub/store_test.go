@@ -0,0 +1,192 @@
+package otelgrub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/grub"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// memStore is an in-memory grub.StoreProvider for tests.
+type memStore struct {
+	data map[string][]byte
+}
+
+func newMemStore() *memStore { return &memStore{data: make(map[string][]byte)} }
+
+func (m *memStore) Get(_ context.Context, key string) ([]byte, error) {
+	v, ok := m.data[key]
+	if !ok {
+		return nil, grub.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memStore) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	m.data[key] = value
+	return nil
+}
+
+func (m *memStore) Delete(_ context.Context, key string) error {
+	if _, ok := m.data[key]; !ok {
+		return grub.ErrNotFound
+	}
+	delete(m.data, key)
+	return nil
+}
+
+func (m *memStore) Exists(_ context.Context, key string) (bool, error) {
+	_, ok := m.data[key]
+	return ok, nil
+}
+
+func (m *memStore) List(_ context.Context, prefix string, _ int) ([]string, error) {
+	var keys []string
+	for k := range m.data {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (m *memStore) GetBatch(_ context.Context, keys []string) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	for _, k := range keys {
+		if v, ok := m.data[k]; ok {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+func (m *memStore) SetBatch(_ context.Context, items map[string][]byte, _ time.Duration) error {
+	for k, v := range items {
+		m.data[k] = v
+	}
+	return nil
+}
+
+func (m *memStore) CompareAndSwap(_ context.Context, key string, old, value []byte, _ time.Duration) (bool, error) {
+	if v, ok := m.data[key]; !ok || string(v) != string(old) {
+		return false, nil
+	}
+	m.data[key] = value
+	return true, nil
+}
+
+type session struct {
+	UserID string `json:"user_id"`
+}
+
+func TestStore_Spans(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Set", func(t *testing.T) {
+		sr, opt := newRecorder()
+		store := WrapStore(grub.NewStore[session](newMemStore()), opt, WithName("sessions"))
+
+		if err := store.Set(ctx, "s:1", &session{UserID: "u1"}, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if span.Name() != "grub.Store.Set" {
+			t.Errorf("expected span 'grub.Store.Set', got %q", span.Name())
+		}
+		if got := attr(span, TableKey).AsString(); got != "sessions" {
+			t.Errorf("expected table 'sessions', got %q", got)
+		}
+		if got := attr(span, KeyKey).AsString(); got != "s:1" {
+			t.Errorf("expected key 's:1', got %q", got)
+		}
+	})
+
+	t.Run("Get not found", func(t *testing.T) {
+		sr, opt := newRecorder()
+		store := WrapStore(grub.NewStore[session](newMemStore()), opt)
+
+		_, err := store.Get(ctx, "missing")
+		if !errors.Is(err, grub.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		if span := onlySpan(t, sr); span.Status().Code != codes.Error {
+			t.Errorf("expected error status, got %v", span.Status().Code)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		sr, opt := newRecorder()
+		mem := newMemStore()
+		mem.data["s:1"] = []byte(`{"user_id":"u1"}`)
+		store := WrapStore(grub.NewStore[session](mem), opt)
+
+		err := store.Update(ctx, "s:1", func(s *session) error {
+			s.UserID = "u2"
+			return nil
+		}, 0)
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if span.Name() != "grub.Store.Update" {
+			t.Errorf("expected span 'grub.Store.Update', got %q", span.Name())
+		}
+		if got := attr(span, KeyKey).AsString(); got != "s:1" {
+			t.Errorf("expected key 's:1', got %q", got)
+		}
+	})
+
+	t.Run("SetBatch", func(t *testing.T) {
+		sr, opt := newRecorder()
+		store := WrapStore(grub.NewStore[session](newMemStore()), opt)
+
+		items := map[string]*session{"a": {UserID: "1"}, "b": {UserID: "2"}, "c": {UserID: "3"}}
+		if err := store.SetBatch(ctx, items, 0); err != nil {
+			t.Fatalf("SetBatch failed: %v", err)
+		}
+		if got := attr(onlySpan(t, sr), BatchSizeKey).AsInt64(); got != 3 {
+			t.Errorf("expected batch size 3, got %d", got)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		sr, opt := newRecorder()
+		mem := newMemStore()
+		mem.data["s:1"] = []byte(`{}`)
+		mem.data["s:2"] = []byte(`{}`)
+		store := WrapStore(grub.NewStore[session](mem), opt)
+
+		if _, err := store.List(ctx, "s:", 0); err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if got := attr(span, ResultCountKey).AsInt64(); got != 2 {
+			t.Errorf("expected result count 2, got %d", got)
+		}
+		if got := attr(span, PrefixKey).AsString(); got != "s:" {
+			t.Errorf("expected prefix 's:', got %q", got)
+		}
+	})
+	t.Run("ListMatch", func(t *testing.T) {
+		sr, opt := newRecorder()
+		mem := newMemStore()
+		mem.data["s:1"] = []byte(`{}`)
+		mem.data["s:22"] = []byte(`{}`)
+		store := WrapStore(grub.NewStore[session](mem), opt)
+
+		if _, _, err := store.ListMatch(ctx, "s:?", "", 0); err != nil {
+			t.Fatalf("ListMatch failed: %v", err)
+		}
+		span := onlySpan(t, sr)
+		if got := attr(span, ResultCountKey).AsInt64(); got != 1 {
+			t.Errorf("expected result count 1, got %d", got)
+		}
+		if got := attr(span, PatternKey).AsString(); got != "s:?" {
+			t.Errorf("expected pattern 's:?', got %q", got)
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package grub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// OutboxPoller defaults.
+const (
+	defaultPollInterval    = time.Second
+	defaultOutboxBatchSize = 100
+)
+
+// OutboxPayload renders the payload of an outbox row. op is "set",
+// "create", or "delete"; rec is nil for deletes.
+type OutboxPayload[T any] func(op, key string, rec *T) ([]byte, error)
+
+// outbox writes the outbox row recording a Database change.
+type outbox[T any] struct {
+	table     string
+	aggregate string
+	insertSQL string
+	payload   OutboxPayload[T]
+}
+
+// newOutbox builds the outbox configured by WithOutbox for a Database of T
+// on table, or returns nil without it.
+func newOutbox[T any](o options, table, dialect string) (*outbox[T], error) {
+	if o.outboxTable == "" {
+		return nil, nil
+	}
+	payload, ok := o.outboxPayload.(OutboxPayload[T])
+	if !ok {
+		return nil, fmt.Errorf("grub: WithOutbox payload is a %T, want an OutboxPayload[%s]",
+			o.outboxPayload, reflect.TypeFor[T]())
+	}
+	if payload == nil {
+		payload = func(_, _ string, rec *T) ([]byte, error) { return json.Marshal(rec) }
+	}
+	q := func(name string) string { return quoteIdent(dialect, name) }
+	return &outbox[T]{
+		table:     o.outboxTable,
+		aggregate: table,
+		insertSQL: fmt.Sprintf("INSERT INTO %s (%s, %s, %s, %s) VALUES (?, ?, ?, ?)",
+			q(o.outboxTable), q("aggregate"), q("key"), q("op"), q("payload")),
+		payload: payload,
+	}, nil
+}
+
+// record inserts the outbox row for op on key through tx. op may carry a
+// _tx suffix, which is dropped. A nil outbox records nothing.
+func (o *outbox[T]) record(ctx context.Context, tx *sqlx.Tx, op, key string, rec *T) error {
+	if o == nil {
+		return nil
+	}
+	op = strings.TrimSuffix(op, "_tx")
+	payload, err := o.payload(op, key, rec)
+	if err != nil {
+		return fmt.Errorf("outbox payload: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(o.insertSQL), o.aggregate, key, op, payload); err != nil {
+		return fmt.Errorf("outbox insert: %w", err)
+	}
+	return nil
+}
+
+// EnsureOutbox creates the WithOutbox table if it does not exist, with the
+// columns OutboxRecord describes plus a nullable processed_at that
+// OutboxPoller sets. Returns ErrUnsupported without WithOutbox or for a
+// custom renderer.
+func (d *Database[T]) EnsureOutbox(ctx context.Context) error {
+	if d.readOnly {
+		return d.wrapErr("ensure_outbox", "", ErrReadOnly)
+	}
+	if d.outbox == nil {
+		return d.wrapErr("ensure_outbox", "", fmt.Errorf("%w: no outbox configured", ErrUnsupported))
+	}
+	ddl, err := outboxDDL(d.dialect, d.outbox.table)
+	if err != nil {
+		return d.wrapErr("ensure_outbox", "", err)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	return d.wrapErr("ensure_outbox", "", d.execAll(callCtx, ddl))
+}
+
+// outboxDDL returns the statements creating the outbox table and its index
+// of unprocessed rows.
+func outboxDDL(dialect, table string) ([]string, error) {
+	q := func(name string) string { return quoteIdent(dialect, name) }
+	var id, text, blob, stamp, now string
+	switch dialect {
+	case "postgres":
+		id, text, blob, stamp, now = "BIGSERIAL PRIMARY KEY", "TEXT", "BYTEA", "TIMESTAMPTZ", "now()"
+	case "sqlite":
+		id, text, blob, stamp, now = "INTEGER PRIMARY KEY AUTOINCREMENT", "TEXT", "BLOB", "TIMESTAMP", "CURRENT_TIMESTAMP"
+	case "mariadb":
+		id, text, blob, stamp, now = "BIGINT AUTO_INCREMENT PRIMARY KEY", "VARCHAR(255)", "LONGBLOB", "DATETIME(6)", "CURRENT_TIMESTAMP(6)"
+	case "mssql":
+		id, text, blob, stamp, now = "BIGINT IDENTITY(1,1) PRIMARY KEY", "NVARCHAR(255)", "VARBINARY(MAX)", "DATETIME2", "SYSUTCDATETIME()"
+	default:
+		return nil, fmt.Errorf("%w: outbox schema for a custom renderer", ErrUnsupported)
+	}
+	columns := fmt.Sprintf("%s %s, %s %s NOT NULL, %s %s NOT NULL, %s %s NOT NULL, %s %s, %s %s NOT NULL DEFAULT %s, %s %s",
+		q("id"), id, q("aggregate"), text, q("key"), text, q("op"), text, q("payload"), blob,
+		q("created_at"), stamp, now, q("processed_at"), stamp)
+	index := q(table + "_pending")
+	if dialect == "mssql" {
+		return []string{
+			fmt.Sprintf("IF OBJECT_ID(N'%s', N'U') IS NULL CREATE TABLE %s (%s)",
+				strings.ReplaceAll(table, "'", "''"), q(table), columns),
+			fmt.Sprintf("IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = N'%s') CREATE INDEX %s ON %s (%s, %s)",
+				strings.ReplaceAll(table+"_pending", "'", "''"), index, q(table), q("processed_at"), q("id")),
+		}, nil
+	}
+	return []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", q(table), columns),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s, %s)", index, q(table), q("processed_at"), q("id")),
+	}, nil
+}
+
+// OutboxRecord is a row of the outbox table.
+type OutboxRecord struct {
+	ID        int64     `db:"id"`
+	Aggregate string    `db:"aggregate"` // table of the changed row
+	Key       string    `db:"key"`
+	Op        string    `db:"op"` // "set", "create", or "delete"
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// OutboxHandler processes one outbox record. Returning an error leaves the
+// record, and every later one, unprocessed.
+type OutboxHandler func(ctx context.Context, rec *OutboxRecord) error
+
+// OutboxPoller delivers outbox rows to a handler in id order, marking each
+// one processed after the handler succeeds. Delivery is at least once: a
+// record is redelivered if the poller stops between the handler returning
+// and the record being marked, so handlers must be idempotent.
+//
+// Run one poller per outbox table; concurrent pollers deliver the same
+// records to both.
+type OutboxPoller struct {
+	db       *sqlx.DB
+	table    string
+	handler  OutboxHandler
+	interval time.Duration
+	selSQL   string
+	markSQL  string
+}
+
+// NewOutboxPoller creates a poller for the outbox table on db, using the
+// dialect of db's driver. Honours WithMaxBatchSize and WithPollInterval;
+// defaults are 100 records and one second.
+func NewOutboxPoller(db *sqlx.DB, table string, handler OutboxHandler, opts ...Option) *OutboxPoller {
+	o := applyOptions(opts)
+	dialect := driverDialects[db.DriverName()]
+	q := func(name string) string { return quoteIdent(dialect, name) }
+	batch := defaultOutboxBatchSize
+	if o.maxBatchSize > 0 {
+		batch = o.maxBatchSize
+	}
+	interval := defaultPollInterval
+	if o.pollInterval > 0 {
+		interval = o.pollInterval
+	}
+	cols := strings.Join([]string{q("id"), q("aggregate"), q("key"), q("op"), q("payload"), q("created_at")}, ", ")
+	where := fmt.Sprintf("WHERE %s IS NULL ORDER BY %s", q("processed_at"), q("id"))
+	sel := fmt.Sprintf("SELECT %s FROM %s %s LIMIT %d", cols, q(table), where, batch)
+	if dialect == "mssql" {
+		sel = fmt.Sprintf("SELECT TOP (%d) %s FROM %s %s", batch, cols, q(table), where)
+	}
+	return &OutboxPoller{
+		db:       db,
+		table:    table,
+		handler:  handler,
+		interval: interval,
+		selSQL:   sel,
+		markSQL: db.Rebind(fmt.Sprintf("UPDATE %s SET %s = CURRENT_TIMESTAMP WHERE %s = ?",
+			q(table), q("processed_at"), q("id"))),
+	}
+}
+
+// Poll delivers one batch of unprocessed records and returns how many were
+// processed. It stops at the first handler error, returning the count so
+// far and the error; the failed record is delivered again by the next Poll.
+func (p *OutboxPoller) Poll(ctx context.Context) (int, error) {
+	var batch []*OutboxRecord
+	if err := sqlx.SelectContext(ctx, p.db, &batch, p.selSQL); err != nil {
+		return 0, shared.WrapError(KindDatabase, "outbox_poll", p.table, "", err)
+	}
+	for n, rec := range batch {
+		if err := p.handler(ctx, rec); err != nil {
+			return n, shared.WrapError(KindDatabase, "outbox_handle", p.table, strconv.FormatInt(rec.ID, 10), err)
+		}
+		if _, err := p.db.ExecContext(ctx, p.markSQL, rec.ID); err != nil {
+			return n, shared.WrapError(KindDatabase, "outbox_mark", p.table, strconv.FormatInt(rec.ID, 10), err)
+		}
+	}
+	return len(batch), nil
+}
+
+// Run polls until ctx is done, waiting the poll interval whenever a poll
+// finds no records. It returns ctx's error, or the first handler or database
+// error; call Run again to resume from the failed record.
+func (p *OutboxPoller) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		n, err := p.Poll(ctx)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+				return ctxErr
+			}
+			return err
+		}
+		wait := p.interval
+		if n > 0 {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
@@ -0,0 +1,262 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+var userColumns = []string{"id", "email", "name", "age"}
+
+// outboxInserts returns the captured inserts into the outbox table.
+func outboxInserts(capture *mockdb.Capture) []mockdb.CapturedQuery {
+	var inserts []mockdb.CapturedQuery
+	for _, q := range capture.Queries {
+		if strings.HasPrefix(q.Query, `INSERT INTO "outbox"`) {
+			inserts = append(inserts, q)
+		}
+	}
+	return inserts
+}
+
+func TestDatabase_Outbox(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("set records the change in its transaction", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRows(userColumns, []driver.Value{int64(1), "a@example.com", "Alice", nil})
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithOutbox[TestDBUser]("outbox", nil))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if err := db.Set(ctx, "1", &TestDBUser{ID: 1, Email: "a@example.com", Name: "Alice"}); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		assertTxEvents(t, capture, "BEGIN", "COMMIT")
+		inserts := outboxInserts(capture)
+		if len(inserts) != 1 {
+			t.Fatalf("expected one outbox row, got %d", len(inserts))
+		}
+		args := inserts[0].Args
+		if args[0] != "test_users" || args[1] != "1" || args[2] != "set" {
+			t.Errorf("unexpected outbox args %v", args[:3])
+		}
+		if payload, _ := args[3].([]byte); !strings.Contains(string(payload), `"Name":"Alice"`) {
+			t.Errorf("expected the record as JSON, got %s", args[3])
+		}
+	})
+
+	t.Run("payload error rolls the write back", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRows(userColumns, []driver.Value{int64(1), "a@example.com", "Alice", nil})
+		payloadErr := errors.New("encode failed")
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer,
+			WithOutbox("outbox", func(string, string, *TestDBUser) ([]byte, error) { return nil, payloadErr }))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if err := db.Set(ctx, "1", &TestDBUser{ID: 1}); !errors.Is(err, payloadErr) {
+			t.Fatalf("expected payload error, got %v", err)
+		}
+		assertTxEvents(t, capture, "BEGIN", "ROLLBACK")
+	})
+
+	t.Run("delete records a delete", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		var gotOp string
+		var gotRec *TestDBUser
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer,
+			WithOutbox("outbox", func(op, _ string, rec *TestDBUser) ([]byte, error) {
+				gotOp, gotRec = op, rec
+				return []byte(op), nil
+			}))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if err := db.Delete(ctx, "7"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if gotOp != "delete" || gotRec != nil {
+			t.Errorf("expected delete with no record, got %q %v", gotOp, gotRec)
+		}
+		inserts := outboxInserts(capture)
+		if len(inserts) != 1 || inserts[0].Args[1] != "7" {
+			t.Errorf("expected one outbox row for key 7, got %v", inserts)
+		}
+		assertTxEvents(t, capture, "BEGIN", "COMMIT")
+	})
+
+	t.Run("insert returning records a create", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRows(userColumns, []driver.Value{int64(9), "a@example.com", "Alice", nil})
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithOutbox[TestDBUser]("outbox", nil))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if _, err := db.InsertReturning(ctx, &TestDBUser{Email: "a@example.com", Name: "Alice"}); err != nil {
+			t.Fatalf("InsertReturning failed: %v", err)
+		}
+		assertTxEvents(t, capture, "BEGIN", "COMMIT")
+		inserts := outboxInserts(capture)
+		if len(inserts) != 1 || inserts[0].Args[1] != "9" || inserts[0].Args[2] != "create" {
+			t.Errorf("expected one create row for key 9, got %v", inserts)
+		}
+	})
+
+	t.Run("tx forms join the caller's transaction", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithOutbox[TestDBUser]("outbox", nil))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		tx, err := mockDB.Beginx()
+		if err != nil {
+			t.Fatalf("Beginx failed: %v", err)
+		}
+		if err := db.DeleteTx(ctx, tx, "7"); err != nil {
+			t.Fatalf("DeleteTx failed: %v", err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("Rollback failed: %v", err)
+		}
+		if inserts := outboxInserts(capture); len(inserts) != 1 || inserts[0].Args[2] != "delete" {
+			t.Errorf("expected the outbox row in the caller's transaction, got %v", inserts)
+		}
+		assertTxEvents(t, capture, "BEGIN", "ROLLBACK")
+	})
+
+	t.Run("not found records nothing", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRowsAffected(0)
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithOutbox[TestDBUser]("outbox", nil))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if err := db.Delete(ctx, "7"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		if inserts := outboxInserts(capture); len(inserts) != 0 {
+			t.Errorf("expected no outbox row, got %v", inserts)
+		}
+		assertTxEvents(t, capture, "BEGIN", "ROLLBACK")
+	})
+
+	t.Run("payload for another type", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		_, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithOutbox[testRecord]("outbox", nil))
+		if err == nil || !strings.Contains(err.Error(), "WithOutbox") {
+			t.Errorf("expected a WithOutbox type error, got %v", err)
+		}
+	})
+}
+
+func TestDatabase_EnsureOutbox(t *testing.T) {
+	ctx := context.Background()
+
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithOutbox[TestDBUser]("outbox", nil))
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	if err := db.EnsureOutbox(ctx); err != nil {
+		t.Fatalf("EnsureOutbox failed: %v", err)
+	}
+	if len(capture.Queries) != 2 ||
+		!strings.HasPrefix(capture.Queries[0].Query, `CREATE TABLE IF NOT EXISTS "outbox" ("id" INTEGER PRIMARY KEY AUTOINCREMENT`) ||
+		!strings.HasPrefix(capture.Queries[1].Query, `CREATE INDEX IF NOT EXISTS "outbox_pending"`) {
+		t.Errorf("unexpected DDL: %v", capture.Queries)
+	}
+
+	plain, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	if err := plain.EnsureOutbox(ctx); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported without WithOutbox, got %v", err)
+	}
+}
+
+func TestOutboxPoller(t *testing.T) {
+	ctx := context.Background()
+	columns := []string{"id", "aggregate", "key", "op", "payload", "created_at"}
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("delivers in order and marks processed", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetQueryRows("SELECT", columns,
+			[]driver.Value{int64(1), "users", "a", "set", []byte("{}"), created},
+			[]driver.Value{int64(2), "users", "b", "delete", nil, created})
+		var got []string
+		p := NewOutboxPoller(mockDB, "outbox", func(_ context.Context, rec *OutboxRecord) error {
+			got = append(got, rec.Key+":"+rec.Op)
+			return nil
+		}, WithMaxBatchSize(10))
+
+		n, err := p.Poll(ctx)
+		if err != nil || n != 2 {
+			t.Fatalf("expected 2 processed, got %d, %v", n, err)
+		}
+		if strings.Join(got, ",") != "a:set,b:delete" {
+			t.Errorf("unexpected delivery order %v", got)
+		}
+		if !strings.Contains(capture.Queries[0].Query, `WHERE "processed_at" IS NULL ORDER BY "id" LIMIT 10`) {
+			t.Errorf("unexpected select: %s", capture.Queries[0].Query)
+		}
+		var marked []any
+		for _, q := range capture.Queries {
+			if strings.HasPrefix(q.Query, `UPDATE "outbox" SET "processed_at"`) {
+				marked = append(marked, q.Args[0])
+			}
+		}
+		if len(marked) != 2 || marked[0] != int64(1) || marked[1] != int64(2) {
+			t.Errorf("expected records 1 and 2 marked, got %v", marked)
+		}
+	})
+
+	t.Run("handler error stops the batch", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetQueryRows("SELECT", columns,
+			[]driver.Value{int64(1), "users", "a", "set", nil, created},
+			[]driver.Value{int64(2), "users", "b", "set", nil, created})
+		handlerErr := errors.New("downstream unavailable")
+		p := NewOutboxPoller(mockDB, "outbox", func(_ context.Context, rec *OutboxRecord) error {
+			if rec.ID == 2 {
+				return handlerErr
+			}
+			return nil
+		})
+		n, err := p.Poll(ctx)
+		if n != 1 || !errors.Is(err, handlerErr) {
+			t.Fatalf("expected 1 processed and the handler error, got %d, %v", n, err)
+		}
+		var updates int
+		for _, q := range capture.Queries {
+			if strings.HasPrefix(q.Query, "UPDATE") {
+				updates++
+			}
+		}
+		if updates != 1 {
+			t.Errorf("expected only the first record marked, got %d updates", updates)
+		}
+	})
+
+	t.Run("run stops with the context", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		cfg.SetQueryRows("SELECT", columns)
+		cctx, cancel := context.WithCancel(ctx)
+		p := NewOutboxPoller(mockDB, "outbox", func(context.Context, *OutboxRecord) error { return nil },
+			WithPollInterval(time.Millisecond))
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}()
+		if err := p.Run(cctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package grub
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zoobzio/edamame"
+)
+
+// sqlParamPattern matches named placeholders (:name) in rendered SQL while
+// skipping PostgreSQL casts (::type).
+var sqlParamPattern = regexp.MustCompile(`(?:^|[^:]):([A-Za-z_][A-Za-z0-9_]*)`)
+
+// statement is the part of an edamame statement that params validation needs.
+type statement interface {
+	Name() string
+	Params() []edamame.ParamSpec
+}
+
+// sqlParams adds to declared the placeholders in query that edamame does not
+// declare, such as select expression params, marking them required.
+func sqlParams(declared []edamame.ParamSpec, query string) []edamame.ParamSpec {
+	specs := append([]edamame.ParamSpec(nil), declared...)
+	seen := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		seen[spec.Name] = true
+	}
+	for _, match := range sqlParamPattern.FindAllStringSubmatch(query, -1) {
+		if name := match[1]; !seen[name] {
+			seen[name] = true
+			specs = append(specs, edamame.ParamSpec{Name: name, Type: "any", Required: true})
+		}
+	}
+	return specs
+}
+
+// checkParams validates params against the statement's params before
+// execution, so a misspelled name fails with a descriptive error instead of
+// matching no rows or reaching the driver. The statement is rendered only
+// when params holds a name edamame does not declare, to see whether the SQL
+// uses it anyway.
+func checkParams(stmt statement, params map[string]any, render func() (string, error)) error {
+	specs := jsonParams(stmt.Params())
+	for key := range params {
+		if !declares(specs, key) {
+			if query, err := render(); err == nil {
+				specs = sqlParams(specs, query)
+			}
+			break
+		}
+	}
+	return validateParams(stmt.Name(), specs, params)
+}
+
+func declares(specs []edamame.ParamSpec, name string) bool {
+	for _, spec := range specs {
+		if spec.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateParams reports required params absent from params and params no
+// spec declares. Returns an error wrapping ErrInvalidParams listing both.
+func validateParams(name string, specs []edamame.ParamSpec, params map[string]any) error {
+	known := make(map[string]bool, len(specs))
+	var problems []string
+	for _, spec := range specs {
+		known[spec.Name] = true
+		if _, ok := params[spec.Name]; spec.Required && !ok {
+			problems = append(problems, fmt.Sprintf("missing param %q", spec.Name))
+		}
+	}
+	var unexpected []string
+	for key := range params {
+		if !known[key] {
+			unexpected = append(unexpected, key)
+		}
+	}
+	sort.Strings(unexpected)
+	for _, key := range unexpected {
+		problems = append(problems, fmt.Sprintf("unexpected param %q", key))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: statement %q: %s", ErrInvalidParams, name, strings.Join(problems, "; "))
+}
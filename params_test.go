@@ -0,0 +1,141 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestValidateParams(t *testing.T) {
+	specs := []edamame.ParamSpec{
+		{Name: "min_age", Required: true},
+		{Name: "limit", Required: false},
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]any
+		want   string
+	}{
+		{"valid", map[string]any{"min_age": 18, "limit": 10}, ""},
+		{"optional omitted", map[string]any{"min_age": 18}, ""},
+		{"missing", map[string]any{}, `statement "by-min-age": missing param "min_age"`},
+		{"unexpected", map[string]any{"min_age": 18, "offset": 5}, `statement "by-min-age": unexpected param "offset"`},
+		{
+			"missing and unexpected",
+			map[string]any{"minAge": 18, "limt": 10},
+			`statement "by-min-age": missing param "min_age"; unexpected param "limt"; unexpected param "minAge"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateParams("by-min-age", specs, tt.params)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidParams) {
+				t.Fatalf("expected ErrInvalidParams, got %v", err)
+			}
+			if !strings.HasSuffix(err.Error(), tt.want) {
+				t.Errorf("expected message ending %q, got %q", tt.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestDatabase_ExecParamsValidation(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	byMinAge := edamame.NewQueryStatement("by-min-age", "", edamame.QuerySpec{
+		Where:       []edamame.ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		LimitParam:  "limit",
+		OffsetParam: "offset",
+	})
+	byEmail := edamame.NewSelectStatement("by-email", "", edamame.SelectSpec{
+		Where: []edamame.ConditionSpec{{Field: "email", Operator: "=", Param: "email"}},
+	})
+	rename := edamame.NewUpdateStatement("rename", "", edamame.UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	countOlder := edamame.NewAggregateStatement("count-older", "", edamame.AggCount, edamame.AggregateSpec{
+		Where: []edamame.ConditionSpec{{Field: "age", Operator: ">", Param: "min_age"}},
+	})
+	wrong := map[string]any{"minAge": 18}
+
+	t.Run("mismatches rejected before the database", func(t *testing.T) {
+		tx, err := mockDB.Beginx()
+		if err != nil {
+			t.Fatalf("Beginx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		calls := map[string]func() error{
+			"exec_query": func() error { _, err := db.ExecQuery(ctx, byMinAge, wrong); return err },
+			"exec_select": func() error {
+				_, err := db.ExecSelect(ctx, byEmail, map[string]any{"email": "a@example.com", "name": "A"})
+				return err
+			},
+			"exec_update": func() error {
+				_, err := db.ExecUpdate(ctx, rename, map[string]any{"id": 1})
+				return err
+			},
+			"exec_aggregate":    func() error { _, err := db.ExecAggregate(ctx, countOlder, wrong); return err },
+			"exec_query_tx":     func() error { _, err := db.ExecQueryTx(ctx, tx, byMinAge, wrong); return err },
+			"exec_select_tx":    func() error { _, err := db.ExecSelectTx(ctx, tx, byEmail, nil); return err },
+			"exec_update_tx":    func() error { _, err := db.ExecUpdateTx(ctx, tx, rename, wrong); return err },
+			"exec_aggregate_tx": func() error { _, err := db.ExecAggregateTx(ctx, tx, countOlder, wrong); return err },
+		}
+		for op, call := range calls {
+			capture.Reset()
+			err := call()
+			if !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("%s: expected ErrInvalidParams, got %v", op, err)
+				continue
+			}
+			var gErr *Error
+			if !errors.As(err, &gErr) || gErr.Op != op {
+				t.Errorf("%s: expected op %q, got %v", op, op, err)
+			}
+			if len(capture.Queries) != 0 {
+				t.Errorf("%s: expected no query, got %d", op, len(capture.Queries))
+			}
+		}
+	})
+
+	t.Run("valid params reach the database", func(t *testing.T) {
+		capture.Reset()
+		if _, err := db.ExecQuery(ctx, byMinAge, map[string]any{"min_age": 18, "limit": 10, "offset": 0}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		query, ok := capture.Last()
+		if !ok || !strings.Contains(query.Query, `"age" >= ?`) {
+			t.Errorf("expected age condition in query, got %+v", query)
+		}
+	})
+
+	t.Run("select expression params accepted", func(t *testing.T) {
+		withDefault := edamame.NewQueryStatement("with-default", "", edamame.QuerySpec{
+			SelectExprs: []edamame.SelectExprSpec{{Func: "coalesce", Params: []string{"fallback", "other"}, Alias: "n"}},
+		})
+		if _, err := db.ExecQuery(ctx, withDefault, map[string]any{"fallback": "x", "other": "y"}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		_, err := db.ExecQuery(ctx, withDefault, map[string]any{"fallback": "x"})
+		if !errors.Is(err, ErrInvalidParams) || !strings.Contains(err.Error(), `missing param "other"`) {
+			t.Errorf("expected missing param error, got %v", err)
+		}
+	})
+}
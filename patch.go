@@ -0,0 +1,233 @@
+package grub
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Patch updates only the named columns of the record at key and returns the
+// updated row with AfterLoad applied. fields maps db column names to their
+// new values; a nil value stores NULL. Names are checked against T's db tags
+// before anything runs: an unknown column, the primary key, or an empty map
+// fails with ErrInvalidParams. Returns ErrNotFound if no row has key.
+// T's updated time column is set to now as well, unless fields names it or
+// WithoutTimestamps is set.
+//
+// The row is read back with RETURNING where the dialect supports it on
+// UPDATE, and otherwise by a SELECT on the same connection or transaction.
+// BeforeSave and AfterSave do not run, since there is no whole record to
+// pass them; use Set when they must. WithOutbox records the change as a
+// "set" of the updated row. Transient conflicts are retried under
+// WithWriteRetry.
+//
+//	user, err := db.Patch(ctx, "42", map[string]any{"name": "New", "age": 31})
+func (d *Database[T]) Patch(ctx context.Context, key string, fields map[string]any) (*T, error) {
+	if d.readOnly {
+		return nil, d.wrapErr("patch", key, ErrReadOnly)
+	}
+	var record *T
+	err := d.retry.do(ctx, func() error {
+		var err error
+		record, err = d.patch(ctx, "patch", nil, key, fields)
+		return err
+	})
+	return record, err
+}
+
+// PatchTx is Patch within a transaction.
+func (d *Database[T]) PatchTx(ctx context.Context, tx *sqlx.Tx, key string, fields map[string]any) (*T, error) {
+	if d.readOnly {
+		return nil, d.wrapErr("patch_tx", key, ErrReadOnly)
+	}
+	if tx == nil {
+		return nil, d.wrapErr("patch_tx", key, ErrNilTransaction)
+	}
+	return d.patch(ctx, "patch_tx", tx, key, fields)
+}
+
+// PatchStruct is Patch with the columns taken from patch, a struct or
+// pointer to one whose fields carry db tags, typically pointers:
+//
+//	type UserPatch struct {
+//	    Name *string `db:"name"`
+//	    Age  *int    `db:"age"`
+//	}
+//	user, err := db.PatchStruct(ctx, "42", UserPatch{Name: &name})
+//
+// A nil pointer field is left unchanged and a non-nil one sets the column
+// to the value it points at. Fields that are not pointers are always set.
+// Fields without a db tag, or tagged "-", are ignored.
+func (d *Database[T]) PatchStruct(ctx context.Context, key string, patch any) (*T, error) {
+	if d.readOnly {
+		return nil, d.wrapErr("patch_struct", key, ErrReadOnly)
+	}
+	fields, err := patchFields(patch)
+	if err != nil {
+		return nil, d.wrapErr("patch_struct", key, err)
+	}
+	var record *T
+	err = d.retry.do(ctx, func() error {
+		var err error
+		record, err = d.patch(ctx, "patch_struct", nil, key, fields)
+		return err
+	})
+	return record, err
+}
+
+// PatchStructTx is PatchStruct within a transaction.
+func (d *Database[T]) PatchStructTx(ctx context.Context, tx *sqlx.Tx, key string, patch any) (*T, error) {
+	if d.readOnly {
+		return nil, d.wrapErr("patch_struct_tx", key, ErrReadOnly)
+	}
+	if tx == nil {
+		return nil, d.wrapErr("patch_struct_tx", key, ErrNilTransaction)
+	}
+	fields, err := patchFields(patch)
+	if err != nil {
+		return nil, d.wrapErr("patch_struct_tx", key, err)
+	}
+	return d.patch(ctx, "patch_struct_tx", tx, key, fields)
+}
+
+// patch implements the Patch methods, running outside a transaction when tx
+// is nil unless WithOutbox needs one.
+func (d *Database[T]) patch(ctx context.Context, op string, tx *sqlx.Tx, key string, fields map[string]any) (*T, error) {
+	query, params, err := d.patchQuery(key, fields)
+	if err != nil {
+		return nil, d.wrapErr(op, key, err)
+	}
+	if tx == nil && d.outbox != nil {
+		var record *T
+		err := d.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			var err error
+			record, err = d.patch(ctx, op, tx, key, fields)
+			return err
+		}, nil)
+		return record, err
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	record, err := d.runPatch(callCtx, d.primary().execer(tx), query, params)
+	d.invalidateCache(ctx, tx)
+	if err == nil {
+		err = d.outbox.record(callCtx, tx, "set", key, record)
+	}
+	if err != nil {
+		return nil, d.wrapErr(op, key, err)
+	}
+	return record, nil
+}
+
+// patchQuery checks fields against T's columns and renders the UPDATE
+// setting them, in column order so the SQL is stable, along with the
+// updated time column unless fields sets it. Each column binds to a
+// set_<column> param.
+func (d *Database[T]) patchQuery(key string, fields map[string]any) (string, map[string]any, error) {
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("%w: no columns to patch", ErrInvalidParams)
+	}
+	columns := make(map[string]bool)
+	for _, field := range d.executor.Soy().Metadata().Fields {
+		if col := field.Tags["db"]; col != "" && col != "-" {
+			columns[col] = true
+		}
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		switch {
+		case name == d.keyCol:
+			return "", nil, fmt.Errorf("%w: cannot patch primary key %q", ErrInvalidParams, name)
+		case !columns[name]:
+			return "", nil, fmt.Errorf("%w: unknown column %q", ErrInvalidParams, name)
+		}
+		names = append(names, name)
+	}
+	stamped, now := d.stamps.updatedColumn()
+	if _, set := fields[stamped]; columns[stamped] && !set {
+		names = append(names, stamped)
+	} else {
+		stamped = ""
+	}
+	sort.Strings(names)
+
+	update := d.executor.Soy().Modify()
+	params := map[string]any{"key": key}
+	for _, name := range names {
+		value := fields[name]
+		if name == stamped {
+			value = now
+		}
+		update = update.Set(name, "set_"+name)
+		params["set_"+name] = value
+	}
+	result, err := update.Where(d.keyCol, "=", "key").Render()
+	if err != nil {
+		return "", nil, err
+	}
+	return result.SQL, params, nil
+}
+
+// runPatch runs the UPDATE and returns the updated row, from its RETURNING
+// clause where the dialect has one and otherwise by selecting it by key.
+func (d *Database[T]) runPatch(ctx context.Context, execer sqlx.ExtContext, query string, params map[string]any) (*T, error) {
+	var records []*T
+	if d.renderer.Capabilities().ReturningOnUpdate {
+		rows, err := sqlx.NamedQueryContext(ctx, execer, query, params)
+		if err != nil {
+			return nil, err
+		}
+		if records, err = scanRows(ctx, rows, d.afterLoad); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := sqlx.NamedExecContext(ctx, execer, query, params); err != nil {
+			return nil, err
+		}
+		sel, err := d.executor.Soy().Select().Where(d.keyCol, "=", "key").Render()
+		if err != nil {
+			return nil, err
+		}
+		if records, err = scanRecords(ctx, execer, sel.SQL, map[string]any{"key": params["key"]}, d.afterLoad); err != nil {
+			return nil, err
+		}
+	}
+	if len(records) == 0 {
+		return nil, ErrNotFound
+	}
+	return records[0], nil
+}
+
+// patchFields returns the columns PatchStruct sets from patch.
+func patchFields(patch any) (map[string]any, error) {
+	v := reflect.ValueOf(patch)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, fmt.Errorf("%w: nil patch", ErrInvalidParams)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: patch must be a struct or a pointer to one", ErrInvalidParams)
+	}
+	fields := make(map[string]any)
+	for i := range v.NumField() {
+		f := v.Type().Field(i)
+		col := f.Tag.Get("db")
+		if col == "" || col == "-" || !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		fields[col] = fv.Interface()
+	}
+	return fields, nil
+}
@@ -0,0 +1,155 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	astqlmariadb "github.com/zoobzio/astql/mariadb"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestDatabase_Patch(t *testing.T) {
+	ctx := context.Background()
+	alice := []driver.Value{int64(1), "a@example.com", "Alicia", int64(31)}
+	newDB := func(t *testing.T) (*Database[callerDBUser], *mockdb.Capture, *mockdb.Config) {
+		t.Helper()
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		db, err := NewDatabase[callerDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		return db, capture, cfg
+	}
+
+	t.Run("sets only the named columns", func(t *testing.T) {
+		db, capture, cfg := newDB(t)
+		cfg.SetRows(userColumns, alice)
+		user, err := db.Patch(ctx, "1", map[string]any{"name": "Alicia", "age": 31})
+		if err != nil {
+			t.Fatalf("Patch failed: %v", err)
+		}
+		if user.Name != "Alicia" || user.loads != 1 {
+			t.Errorf("expected the returned row with AfterLoad applied, got %+v", user)
+		}
+		query, _ := capture.Last()
+		set := query.Query[:strings.Index(query.Query, " WHERE ")]
+		if set != `UPDATE "test_users" SET "age" = ?, "name" = ?` {
+			t.Errorf("expected only age and name in SET, got: %s", query.Query)
+		}
+		if !strings.Contains(query.Query, `WHERE "id" = ? RETURNING`) {
+			t.Errorf("expected the update to return the row, got: %s", query.Query)
+		}
+		if len(capture.Queries) != 1 {
+			t.Errorf("expected a single statement, got %d", len(capture.Queries))
+		}
+	})
+
+	t.Run("rejects bad columns before running", func(t *testing.T) {
+		db, capture, _ := newDB(t)
+		for name, fields := range map[string]map[string]any{
+			"unknown": {"name": "A", "nickname": "a"},
+			"key":     {"id": 2},
+			"empty":   {},
+		} {
+			if _, err := db.Patch(ctx, "1", fields); !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("%s: expected ErrInvalidParams, got %v", name, err)
+			}
+		}
+		if len(capture.Queries) != 0 {
+			t.Errorf("expected no statements, got %v", capture.Queries)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		db, _, _ := newDB(t)
+		_, err := db.Patch(ctx, "9", map[string]any{"name": "A"})
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		var gErr *Error
+		if !errors.As(err, &gErr) || gErr.Op != "patch" || gErr.Key != "9" {
+			t.Errorf("expected the error to name patch and the key, got %v", err)
+		}
+	})
+
+	t.Run("struct skips nil pointers", func(t *testing.T) {
+		db, capture, cfg := newDB(t)
+		cfg.SetRows(userColumns, alice)
+		type userPatch struct {
+			Name  *string `db:"name"`
+			Age   *int    `db:"age"`
+			Notes string
+		}
+		name := "Alicia"
+		if _, err := db.PatchStruct(ctx, "1", &userPatch{Name: &name, Notes: "ignored"}); err != nil {
+			t.Fatalf("PatchStruct failed: %v", err)
+		}
+		query, _ := capture.Last()
+		if !strings.HasPrefix(query.Query, `UPDATE "test_users" SET "name" = ? WHERE`) {
+			t.Errorf("expected only name in SET, got: %s", query.Query)
+		}
+		if _, err := db.PatchStruct(ctx, "1", "name"); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("expected a non-struct patch to fail with ErrInvalidParams, got %v", err)
+		}
+	})
+
+	t.Run("selects the row without returning", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		db, err := NewDatabase[callerDBUser](mockDB, "test_users", astqlmariadb.New())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		cfg.SetRows(userColumns, alice)
+		user, err := db.Patch(ctx, "1", map[string]any{"name": "Alicia"})
+		if err != nil {
+			t.Fatalf("Patch failed: %v", err)
+		}
+		if user.Name != "Alicia" || user.loads != 1 {
+			t.Errorf("expected the selected row with AfterLoad applied, got %+v", user)
+		}
+		if len(capture.Queries) != 2 || !strings.HasPrefix(capture.Queries[0].Query, "UPDATE") ||
+			strings.Contains(capture.Queries[0].Query, "RETURNING") || !strings.HasPrefix(capture.Queries[1].Query, "SELECT") {
+			t.Errorf("expected an UPDATE then a SELECT, got %v", capture.Queries)
+		}
+	})
+
+	t.Run("outbox records a set", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithOutbox[TestDBUser]("outbox", nil))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		cfg.SetRows(userColumns, alice)
+		if _, err := db.Patch(ctx, "1", map[string]any{"name": "Alicia"}); err != nil {
+			t.Fatalf("Patch failed: %v", err)
+		}
+		if inserts := outboxInserts(capture); len(inserts) != 1 || inserts[0].Args[1] != "1" || inserts[0].Args[2] != "set" {
+			t.Errorf("expected one set row for key 1, got %v", inserts)
+		}
+	})
+
+	t.Run("tx", func(t *testing.T) {
+		db, capture, cfg := newDB(t)
+		cfg.SetRows(userColumns, alice)
+		tx, err := db.DB().BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTxx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		if _, err := db.PatchTx(ctx, tx, "1", map[string]any{"name": "Alicia"}); err != nil {
+			t.Fatalf("PatchTx failed: %v", err)
+		}
+		if query, ok := capture.Last(); !ok || !strings.HasPrefix(query.Query, "UPDATE") {
+			t.Errorf("expected the update on the transaction, got %+v", query)
+		}
+		if _, err := db.PatchTx(ctx, nil, "1", map[string]any{"name": "A"}); !errors.Is(err, ErrNilTransaction) {
+			t.Errorf("expected ErrNilTransaction, got %v", err)
+		}
+		if _, err := db.PatchStructTx(ctx, nil, "1", struct{}{}); !errors.Is(err, ErrNilTransaction) {
+			t.Errorf("expected ErrNilTransaction, got %v", err)
+		}
+	})
+}
@@ -8,13 +8,21 @@ import (
 	"github.com/google/uuid"
 	"github.com/pinecone-io/go-pinecone/v2/pinecone"
 	"github.com/zoobzio/grub"
+	"github.com/zoobzio/grub/internal/shared"
 	"github.com/zoobzio/vecna"
 )
 
 // Config holds configuration for the Pinecone provider.
 type Config struct {
-	// Namespace is the Pinecone namespace for vector operations.
+	// Namespace is the Pinecone namespace for vector operations. When set,
+	// it replaces the namespace the index connection was opened with;
+	// empty keeps the connection's own.
 	Namespace string
+
+	// Metric is the metric the index was created with, reported by
+	// ScoreKind. Defaults to cosine. Pinecone scores cosine and dotproduct
+	// as similarities and euclidean as the squared distance.
+	Metric grub.DistanceMetric
 }
 
 // Provider implements grub.VectorProvider for Pinecone.
@@ -24,13 +32,47 @@ type Provider struct {
 }
 
 // New creates a Pinecone provider with the given index connection and config.
+// A Config.Namespace is applied to a copy of the connection, which shares
+// its underlying gRPC connection, so one connection can serve providers for
+// several namespaces; closing any of them closes it for all.
 func New(index *pinecone.IndexConnection, config Config) *Provider {
+	if index != nil && config.Namespace != "" && index.Namespace != config.Namespace {
+		scoped := *index
+		scoped.Namespace = config.Namespace
+		index = &scoped
+	}
 	return &Provider{
 		index:  index,
 		config: config,
 	}
 }
 
+// maxBatchSize is Pinecone's cap on the vectors in one upsert and the IDs
+// in one fetch or delete.
+const maxBatchSize = 1000
+
+// MaxBatchSize reports the provider's batch limit, so the Index splits
+// larger batches. It implements grub.BatchLimiter.
+func (p *Provider) MaxBatchSize() int {
+	return maxBatchSize
+}
+
+// ScoreKind reports how search scores are measured for Config.Metric.
+// It implements grub.VectorScorer.
+func (p *Provider) ScoreKind() grub.ScoreKind {
+	metric := p.config.Metric
+	if metric == "" {
+		metric = grub.DistanceCosine
+	}
+	switch metric {
+	case grub.DistanceCosine, grub.DistanceInnerProduct:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreSimilarity}
+	case grub.DistanceL2:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreSquaredDistance}
+	}
+	return grub.ScoreKind{}
+}
+
 // Upsert stores or updates a vector with associated metadata.
 func (p *Provider) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata []byte) error {
 	metaStruct, err := bytesToStruct(metadata)
@@ -78,7 +120,7 @@ func (p *Provider) Get(ctx context.Context, id uuid.UUID) ([]float32, *grub.Vect
 	idStr := id.String()
 	resp, err := p.index.FetchVectors(ctx, []string{idStr})
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, shared.CtxErr(ctx, err)
 	}
 
 	vec, ok := resp.Vectors[idStr]
@@ -144,7 +186,7 @@ func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter m
 
 	resp, err := p.index.QueryByVectorValues(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, shared.CtxErr(ctx, err)
 	}
 
 	results := make([]grub.VectorResult, len(resp.Matches))
@@ -187,7 +229,7 @@ func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *v
 
 	resp, err := p.index.QueryByVectorValues(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, shared.CtxErr(ctx, err)
 	}
 
 	results := make([]grub.VectorResult, len(resp.Matches))
@@ -216,28 +258,45 @@ func (p *Provider) Filter(_ context.Context, _ *vecna.Filter, _ int) ([]grub.Vec
 	return nil, grub.ErrFilterNotSupported
 }
 
-// List returns vector IDs.
+// listPageSize is the most IDs Pinecone returns per list page.
+const listPageSize = 100
+
+// List returns vector IDs, following list pagination tokens until limit IDs
+// are collected or the namespace is exhausted. Limit of 0 means no limit.
+// Cancellation is checked between pages.
 func (p *Provider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
 	req := &pinecone.ListVectorsRequest{}
-	if limit > 0 {
-		l := uint32(limit)
-		req.Limit = &l
-	}
-
-	resp, err := p.index.ListVectors(ctx, req)
-	if err != nil {
-		return nil, err
-	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page := uint32(listPageSize)
+		if limit > 0 {
+			page = uint32(min(limit-len(ids), listPageSize))
+		}
+		req.Limit = &page
 
-	ids := make([]uuid.UUID, len(resp.VectorIds))
-	for i, idPtr := range resp.VectorIds {
-		id, err := uuid.Parse(*idPtr)
+		resp, err := p.index.ListVectors(ctx, req)
 		if err != nil {
-			return nil, err
+			return nil, shared.CtxErr(ctx, err)
+		}
+		for _, idPtr := range resp.VectorIds {
+			id, err := uuid.Parse(*idPtr)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+
+		if resp.NextPaginationToken == nil || *resp.NextPaginationToken == "" || len(resp.VectorIds) == 0 {
+			return ids, nil
+		}
+		if limit > 0 && len(ids) >= limit {
+			return ids[:limit], nil
 		}
-		ids[i] = id
+		req.PaginationToken = resp.NextPaginationToken
 	}
-	return ids, nil
 }
 
 // Exists checks whether a vector ID exists.
@@ -245,7 +304,7 @@ func (p *Provider) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
 	idStr := id.String()
 	resp, err := p.index.FetchVectors(ctx, []string{idStr})
 	if err != nil {
-		return false, err
+		return false, shared.CtxErr(ctx, err)
 	}
 	_, ok := resp.Vectors[idStr]
 	return ok, nil
@@ -2,6 +2,9 @@ package pinecone
 
 import (
 	"testing"
+
+	"github.com/pinecone-io/go-pinecone/v2/pinecone"
+	"github.com/zoobzio/grub"
 )
 
 func TestNew(t *testing.T) {
@@ -13,3 +16,39 @@ func TestNew(t *testing.T) {
 		t.Errorf("expected namespace 'test', got %q", p.config.Namespace)
 	}
 }
+
+func TestScoreKind(t *testing.T) {
+	for metric, want := range map[grub.DistanceMetric]grub.ScoreKind{
+		"":                        {Metric: grub.DistanceCosine, Measure: grub.ScoreSimilarity},
+		grub.DistanceInnerProduct: {Metric: grub.DistanceInnerProduct, Measure: grub.ScoreSimilarity},
+		grub.DistanceL2:           {Metric: grub.DistanceL2, Measure: grub.ScoreSquaredDistance},
+		"hamming":                 {},
+	} {
+		if got := New(nil, Config{Metric: metric}).ScoreKind(); got != want {
+			t.Errorf("%q: expected %+v, got %+v", metric, want, got)
+		}
+	}
+}
+
+func TestMaxBatchSize(t *testing.T) {
+	var _ grub.BatchLimiter = (*Provider)(nil)
+	if got := New(nil, Config{Namespace: "test"}).MaxBatchSize(); got != maxBatchSize {
+		t.Errorf("expected %d, got %d", maxBatchSize, got)
+	}
+}
+
+func TestNew_Namespace(t *testing.T) {
+	conn := &pinecone.IndexConnection{Namespace: "shared"}
+
+	p := New(conn, Config{Namespace: "tenant-a"})
+	if p.index.Namespace != "tenant-a" {
+		t.Errorf("expected the provider to use namespace 'tenant-a', got %q", p.index.Namespace)
+	}
+	if conn.Namespace != "shared" {
+		t.Errorf("expected the caller's connection to keep its namespace, got %q", conn.Namespace)
+	}
+
+	if p := New(conn, Config{}); p.index != conn {
+		t.Error("expected an empty Namespace to use the connection as is")
+	}
+}
@@ -0,0 +1,208 @@
+package grub
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// Builder is a query builder that can render itself to SQL. The builders
+// returned by Database's Query, Select, Insert, InsertFull, Modify, and
+// Remove all satisfy it.
+type Builder interface {
+	Render() (*astql.QueryResult, error)
+}
+
+// PreviewQuery returns the SQL and positional args ExecQuery would run for
+// stmt and params, without executing anything. Params are validated and
+// slice params bound to IN conditions expanded exactly as ExecQuery does,
+// and placeholders are rebound to the driver's bindvar style.
+func (d *Database[T]) PreviewQuery(stmt edamame.QueryStatement, params map[string]any) (string, []any, error) {
+	render := func() (string, error) { return d.executor.RenderQuery(stmt) }
+	if err := checkParams(stmt, params, render); err != nil {
+		return "", nil, d.wrapErr("preview_query", "", err)
+	}
+	query, err := render()
+	if err != nil {
+		return "", nil, d.wrapErr("preview_query", "", err)
+	}
+	if expanded, args, ok := expandIn(query, params); ok {
+		query, params = expanded, args
+	}
+	sql, args, err := d.db.BindNamed(query, params)
+	if err != nil {
+		return "", nil, d.wrapErr("preview_query", "", err)
+	}
+	return sql, args, nil
+}
+
+// PreviewBuilder returns the SQL and positional args b would run with
+// params, without executing anything. The builders are soy types, so the
+// preview lives here rather than as a method on each of them. Builders
+// bind slice params as a single value, unlike ExecQuery, and the preview
+// matches. An update that renders no RETURNING clause is followed at
+// execution by a SELECT of the updated row, which the preview omits.
+//
+//	sql, args, err := users.PreviewBuilder(
+//	    users.Query().Where("age", ">=", "min_age"),
+//	    map[string]any{"min_age": 18},
+//	)
+func (d *Database[T]) PreviewBuilder(b Builder, params map[string]any) (string, []any, error) {
+	result, err := b.Render()
+	if err != nil {
+		return "", nil, d.wrapErr("preview_builder", "", err)
+	}
+	sql, args, err := d.db.BindNamed(result.SQL, params)
+	if err != nil {
+		return "", nil, d.wrapErr("preview_builder", "", err)
+	}
+	return sql, args, nil
+}
+
+// Preview describes the request an operation would send, built without
+// sending it. Hooks do not run and timestamps are not stamped, so values a
+// BeforeSave hook would change appear as given.
+type Preview struct {
+	// Op is the operation previewed (e.g. "set", "delete").
+	Op string
+
+	// Target is the table, or the index name set with WithName. Empty for
+	// Store and Bucket, which do not know their backend's name.
+	Target string
+
+	// Key is the key the provider would receive, after the key policy and
+	// namespace, or the vector ID.
+	Key string
+
+	// SQL is the statement a Database would run, with the driver's bindvars.
+	SQL string
+
+	// Params are the named params SQL binds, and Args their values in
+	// bind order.
+	Params []string
+	Args   []any
+
+	// Size is the encoded payload a Store, Bucket, or Index would send.
+	Size int
+}
+
+// PreviewGet returns the SELECT Get would run for key.
+func (d *Database[T]) PreviewGet(key string) (*Preview, error) {
+	sel := d.executor.Soy().Select().Where(d.keyCol, "=", "key")
+	return d.previewStatement("get", key, sel, map[string]any{"key": key})
+}
+
+// PreviewSet returns the upsert Set would run for record. With WithOutbox,
+// Set also inserts an outbox row, which the preview omits; a record whose
+// key WithKeyGenerator would fill is previewed with its key as given.
+func (d *Database[T]) PreviewSet(key string, record *T) (*Preview, error) {
+	if record == nil {
+		return nil, d.wrapErr("set", key, errors.New("nil record"))
+	}
+	return d.previewStatement("set", key, d.upsert(), record)
+}
+
+// PreviewDelete returns the DELETE Delete would run for key.
+func (d *Database[T]) PreviewDelete(key string) (*Preview, error) {
+	remove, params := d.removeKey(key)
+	return d.previewStatement("delete", key, remove, params)
+}
+
+// previewStatement renders b and binds arg, a params map or record, the way
+// its Exec would.
+func (d *Database[T]) previewStatement(op, key string, b Builder, arg any) (*Preview, error) {
+	result, err := b.Render()
+	if err != nil {
+		return nil, d.wrapErr(op, key, err)
+	}
+	sql, args, err := d.db.BindNamed(result.SQL, arg)
+	if err != nil {
+		return nil, d.wrapErr(op, key, err)
+	}
+	return &Preview{
+		Op:     op,
+		Target: d.tableName,
+		Key:    key,
+		SQL:    sql,
+		Params: result.RequiredParams,
+		Args:   args,
+	}, nil
+}
+
+// PreviewSet returns the provider Set that Set would make for value.
+func (s *Store[T]) PreviewSet(key string, value *T) (*Preview, error) {
+	key, err := s.providerKey(key)
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "set", "", key, err)
+	}
+	data, err := s.codec.Encode(value)
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "set", "", key, err)
+	}
+	return &Preview{Op: "set", Key: key, Size: len(data)}, nil
+}
+
+// PreviewDelete returns the provider Delete that Delete would make.
+func (s *Store[T]) PreviewDelete(key string) (*Preview, error) {
+	key, err := s.providerKey(key)
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "delete", "", key, err)
+	}
+	return &Preview{Op: "delete", Key: key}, nil
+}
+
+// providerKey returns the key the provider receives for key.
+func (s *Store[T]) providerKey(key string) (string, error) {
+	key, err := s.keys.apply(key)
+	if err != nil {
+		return key, err
+	}
+	if ns, ok := s.provider.(*namespacedStore); ok {
+		key = ns.ns + key
+	}
+	return key, nil
+}
+
+// PreviewPut returns the provider Put that Put would make for obj.
+func (b *Bucket[T]) PreviewPut(obj *Object[T]) (*Preview, error) {
+	key, err := b.keys.apply(obj.Key)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "put", "", key, err)
+	}
+	data, err := b.codec.Encode(obj.Data)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "put", "", key, err)
+	}
+	return &Preview{Op: "put", Key: key, Size: len(data)}, nil
+}
+
+// PreviewDelete returns the provider Delete that Delete would make.
+func (b *Bucket[T]) PreviewDelete(key string) (*Preview, error) {
+	key, err := b.keys.apply(key)
+	if err != nil {
+		return nil, shared.WrapError(KindBucket, "delete", "", key, err)
+	}
+	return &Preview{Op: "delete", Key: key}, nil
+}
+
+// PreviewUpsert returns the provider Upsert that Upsert would make. The
+// vector is checked against the index dimension as Upsert checks it; Size
+// counts the encoded metadata.
+func (i *Index[T]) PreviewUpsert(id uuid.UUID, vector []float32, metadata *T) (*Preview, error) {
+	if err := i.checkDimension(vector); err != nil {
+		return nil, i.wrapErr("upsert", id.String(), err)
+	}
+	m, err := i.encodeMetadata(metadata)
+	if err != nil {
+		return nil, i.wrapErr("upsert", id.String(), err)
+	}
+	return &Preview{Op: "upsert", Target: i.name, Key: id.String(), Size: len(m)}, nil
+}
+
+// PreviewDelete returns the provider Delete that Delete would make.
+func (i *Index[T]) PreviewDelete(id uuid.UUID) *Preview {
+	return &Preview{Op: "delete", Target: i.name, Key: id.String()}
+}
@@ -0,0 +1,284 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestDatabase_PreviewQuery(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("matches execution", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		stmt := edamame.NewQueryStatement("adults", "", edamame.QuerySpec{
+			Where: []edamame.ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		})
+		params := map[string]any{"min_age": 18}
+
+		sql, args, err := db.PreviewQuery(stmt, params)
+		if err != nil {
+			t.Fatalf("PreviewQuery failed: %v", err)
+		}
+		if _, ok := capture.Last(); ok {
+			t.Fatal("PreviewQuery executed a statement")
+		}
+		if _, err := db.ExecQuery(ctx, stmt, params); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		sent, _ := capture.Last()
+		if sql != sent.Query || fmt.Sprint(args) != fmt.Sprint(sent.Args) {
+			t.Errorf("preview %q %v, executed %q %v", sql, args, sent.Query, sent.Args)
+		}
+	})
+
+	t.Run("expands IN lists", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		stmt := edamame.NewQueryStatement("by-ids", "", edamame.QuerySpec{
+			Where: []edamame.ConditionSpec{{Field: "id", Operator: "IN", Param: "ids"}},
+		})
+		params := map[string]any{"ids": []int{1, 2, 3}}
+
+		sql, args, err := db.PreviewQuery(stmt, params)
+		if err != nil {
+			t.Fatalf("PreviewQuery failed: %v", err)
+		}
+		if !reflect.DeepEqual(args, []any{1, 2, 3}) {
+			t.Errorf("expected expanded args, got %v", args)
+		}
+		if _, err := db.ExecQuery(ctx, stmt, params); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		sent, _ := capture.Last()
+		if sql != sent.Query {
+			t.Errorf("preview %q, executed %q", sql, sent.Query)
+		}
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		_, _, err = db.PreviewQuery(QueryAll, map[string]any{"typo": 1})
+		if !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("expected ErrInvalidParams, got %v", err)
+		}
+	})
+}
+
+func TestDatabase_PreviewBuilder(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	t.Run("query", func(t *testing.T) {
+		capture.Reset()
+		q := db.Query().Where("age", ">=", "min_age").OrderBy("name", "ASC")
+		params := map[string]any{"min_age": 21}
+		sql, args, err := db.PreviewBuilder(q, params)
+		if err != nil {
+			t.Fatalf("PreviewBuilder failed: %v", err)
+		}
+		if _, ok := capture.Last(); ok {
+			t.Fatal("PreviewBuilder executed a statement")
+		}
+		if _, err := q.Exec(ctx, params); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+		sent, _ := capture.Last()
+		if sql != sent.Query || fmt.Sprint(args) != fmt.Sprint(sent.Args) {
+			t.Errorf("preview %q %v, executed %q %v", sql, args, sent.Query, sent.Args)
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		capture.Reset()
+		r := db.Remove().Where("id", "=", "id")
+		params := map[string]any{"id": 9}
+		sql, args, err := db.PreviewBuilder(r, params)
+		if err != nil {
+			t.Fatalf("PreviewBuilder failed: %v", err)
+		}
+		if _, err := r.Exec(ctx, params); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+		sent, _ := capture.Last()
+		if sql != sent.Query || fmt.Sprint(args) != fmt.Sprint(sent.Args) {
+			t.Errorf("preview %q %v, executed %q %v", sql, args, sent.Query, sent.Args)
+		}
+	})
+
+	t.Run("modify", func(t *testing.T) {
+		m := db.Modify().Set("name", "name").Where("id", "=", "id")
+		sql, args, err := db.PreviewBuilder(m, map[string]any{"name": "Renamed", "id": 3})
+		if err != nil {
+			t.Fatalf("PreviewBuilder failed: %v", err)
+		}
+		if !strings.HasPrefix(sql, "UPDATE") || !reflect.DeepEqual(args, []any{"Renamed", 3}) {
+			t.Errorf("got %q %v", sql, args)
+		}
+	})
+
+	t.Run("missing param", func(t *testing.T) {
+		_, _, err := db.PreviewBuilder(db.Select().Where("id", "=", "id"), nil)
+		if err == nil {
+			t.Error("expected an error for an unbound param")
+		}
+	})
+}
+
+func TestDatabase_PreviewWrites(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture, cfg := mockdb.NewWithConfig()
+	cfg.SetRows(userColumns, []driver.Value{int64(1), "a@example.com", "Alice", nil})
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	user := &TestDBUser{ID: 1, Email: "a@example.com", Name: "Alice"}
+
+	previews := map[string]struct {
+		preview func() (*Preview, error)
+		exec    func() error
+	}{
+		"set": {
+			func() (*Preview, error) { return db.PreviewSet("1", user) },
+			func() error { return db.Set(ctx, "1", user) },
+		},
+		"delete": {
+			func() (*Preview, error) { return db.PreviewDelete("1") },
+			func() error { return db.Delete(ctx, "1") },
+		},
+	}
+	for op, tc := range previews {
+		t.Run(op, func(t *testing.T) {
+			capture.Reset()
+			p, err := tc.preview()
+			if err != nil {
+				t.Fatalf("preview failed: %v", err)
+			}
+			if _, ok := capture.Last(); ok {
+				t.Fatal("preview executed a statement")
+			}
+			if p.Op != op || p.Target != "test_users" || p.Key != "1" || len(p.Params) == 0 {
+				t.Errorf("unexpected preview %+v", p)
+			}
+			if err := tc.exec(); err != nil {
+				t.Fatalf("exec failed: %v", err)
+			}
+			sent, _ := capture.Last()
+			if p.SQL != sent.Query || fmt.Sprint(p.Args) != fmt.Sprint(sent.Args) {
+				t.Errorf("preview %q %v, executed %q %v", p.SQL, p.Args, sent.Query, sent.Args)
+			}
+		})
+	}
+
+	t.Run("get", func(t *testing.T) {
+		p, err := db.PreviewGet("7")
+		if err != nil {
+			t.Fatalf("PreviewGet failed: %v", err)
+		}
+		if !strings.HasPrefix(p.SQL, "SELECT") || !reflect.DeepEqual(p.Args, []any{"7"}) {
+			t.Errorf("got %q %v", p.SQL, p.Args)
+		}
+	})
+
+	t.Run("hooks do not run", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		hooked, err := NewDatabase[failingBeforeSaveDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if _, err := hooked.PreviewSet("1", &failingBeforeSaveDBUser{ID: 1}); err != nil {
+			t.Fatalf("expected BeforeSave to be skipped, got %v", err)
+		}
+		if _, ok := capture.Last(); ok {
+			t.Error("PreviewSet executed a statement")
+		}
+	})
+
+	t.Run("nil record", func(t *testing.T) {
+		if _, err := db.PreviewSet("1", nil); err == nil {
+			t.Error("expected an error for a nil record")
+		}
+	})
+}
+
+func TestStore_PreviewWrites(t *testing.T) {
+	store := NewStore[hookedRecord](untouchableStore{}, WithKeyNamespace("rec:"),
+		WithKeyPolicy(KeyPolicy{Pattern: regexp.MustCompile(`^[a-z]+$`)}))
+	rec := &hookedRecord{ID: 1, Name: "a"}
+
+	p, err := store.PreviewSet("k", rec)
+	if err != nil {
+		t.Fatalf("PreviewSet failed: %v", err)
+	}
+	want, _ := json.Marshal(rec)
+	if p.Op != "set" || p.Key != "rec:k" || p.Size != len(want) {
+		t.Errorf("unexpected preview %+v, want size %d", p, len(want))
+	}
+	if rec.beforeSaveCalled {
+		t.Error("expected no hooks to run")
+	}
+	if p, err := store.PreviewDelete("k"); err != nil || p.Key != "rec:k" {
+		t.Errorf("PreviewDelete: got %+v, %v", p, err)
+	}
+	if _, err := store.PreviewSet("K1", rec); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+}
+
+func TestBucket_PreviewWrites(t *testing.T) {
+	bucket := NewBucket[testPayload](untouchableBucket{})
+	p, err := bucket.PreviewPut(&Object[testPayload]{Key: "a/b.json", Data: testPayload{Field1: "x"}})
+	if err != nil {
+		t.Fatalf("PreviewPut failed: %v", err)
+	}
+	if p.Op != "put" || p.Key != "a/b.json" || p.Size == 0 {
+		t.Errorf("unexpected preview %+v", p)
+	}
+	if p, err := bucket.PreviewDelete("a/b.json"); err != nil || p.Op != "delete" {
+		t.Errorf("PreviewDelete: got %+v, %v", p, err)
+	}
+}
+
+func TestIndex_PreviewWrites(t *testing.T) {
+	index := NewIndex[testMetadata](untouchableVector{}, WithName("docs"), WithDimension(2))
+	id := uuid.New()
+	p, err := index.PreviewUpsert(id, []float32{1, 0}, &testMetadata{Category: "a"})
+	if err != nil {
+		t.Fatalf("PreviewUpsert failed: %v", err)
+	}
+	if p.Target != "docs" || p.Key != id.String() || p.Size == 0 {
+		t.Errorf("unexpected preview %+v", p)
+	}
+	if _, err := index.PreviewUpsert(id, []float32{1}, nil); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("expected ErrDimensionMismatch, got %v", err)
+	}
+	if p := index.PreviewDelete(id); p.Op != "delete" || p.Target != "docs" {
+		t.Errorf("unexpected preview %+v", p)
+	}
+}
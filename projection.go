@@ -0,0 +1,80 @@
+package grub
+
+import (
+	"reflect"
+	"strings"
+)
+
+// projection zeroes the fields of T left out by WithSearchFields.
+// A nil *projection is valid and does nothing.
+type projection struct {
+	fields []string // requested JSON names
+	index  [][]int  // field indexes to zero
+}
+
+// newProjection locates the top-level fields of T (including those promoted
+// from non-pointer embedded structs) whose names under tag are not in
+// WithSearchFields. Returns nil unless the option is set and T is a struct.
+func newProjection[T any](o options, tag string) *projection {
+	if len(o.searchFields) == 0 {
+		return nil
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	keep := make(map[string]bool, len(o.searchFields))
+	for _, name := range o.searchFields {
+		keep[name] = true
+	}
+	p := &projection{fields: o.searchFields}
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous || throughPointer(t, f.Index) {
+			continue
+		}
+		if name, ok := taggedName(f, tag); !ok || !keep[name] {
+			p.index = append(p.index, f.Index)
+		}
+	}
+	return p
+}
+
+// jsonName returns the key f is encoded under by encoding/json, or false if
+// it is skipped.
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return f.Name, true
+}
+
+// provider returns the view of provider searches should go through: the
+// projected view when provider implements VectorProjector.
+func (p *projection) provider(provider VectorProvider) VectorProvider {
+	if p == nil {
+		return provider
+	}
+	if projector, ok := provider.(VectorProjector); ok {
+		return projector.Project(p.fields)
+	}
+	return provider
+}
+
+// apply zeroes the non-projected fields of value.
+func (p *projection) apply(value any) {
+	if p == nil {
+		return
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return
+	}
+	v = v.Elem()
+	for _, index := range p.index {
+		v.FieldByIndex(index).SetZero()
+	}
+}
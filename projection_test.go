@@ -0,0 +1,136 @@
+package grub
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type articleBase struct {
+	Author string `json:"author"`
+}
+
+type article struct {
+	articleBase
+	Title    string   `json:"title"`
+	Category string   `json:"category,omitempty"`
+	Body     string   `json:"body"`
+	Tags     []string // encoded as "Tags"
+	Internal string   `json:"-"`
+}
+
+func TestNewProjection(t *testing.T) {
+	if p := newProjection[article](options{}, "json"); p != nil {
+		t.Error("expected nil projection without WithSearchFields")
+	}
+	if p := newProjection[map[string]any](options{searchFields: []string{"title"}}, "json"); p != nil {
+		t.Error("expected nil projection for a non-struct type")
+	}
+
+	p := newProjection[article](options{searchFields: []string{"title", "category", "author", "Tags"}}, "json")
+	in := article{
+		articleBase: articleBase{Author: "ann"},
+		Title:       "t",
+		Category:    "c",
+		Body:        "b",
+		Tags:        []string{"x"},
+		Internal:    "i",
+	}
+	out := in
+	p.apply(&out)
+	want := in
+	want.Body, want.Internal = "", ""
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("expected %+v, got %+v", want, out)
+	}
+
+	var nilProjection *projection
+	nilProjection.apply(&out) // must not panic
+}
+
+// projectingVectorProvider trims search metadata to the projected fields,
+// as a provider implementing VectorProjector would.
+type projectingVectorProvider struct {
+	*mockVectorProvider
+	fields []string
+}
+
+func (p *projectingVectorProvider) Project(fields []string) VectorProvider {
+	return &projectingVectorProvider{mockVectorProvider: p.mockVectorProvider, fields: fields}
+}
+
+func (p *projectingVectorProvider) Search(ctx context.Context, vector []float32, k int, filter map[string]any) ([]VectorResult, error) {
+	results, err := p.mockVectorProvider.Search(ctx, vector, k, filter)
+	if err != nil || p.fields == nil {
+		return results, err
+	}
+	codec := JSONCodec{}
+	for n := range results {
+		var full map[string]any
+		if err := codec.Decode(results[n].Metadata, &full); err != nil {
+			return nil, err
+		}
+		trimmed := make(map[string]any)
+		for _, f := range p.fields {
+			if v, ok := full[f]; ok {
+				trimmed[f] = v
+			}
+		}
+		if results[n].Metadata, err = codec.Encode(trimmed); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func TestIndex_SearchFields(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	meta := &testMetadata{Category: "tech", Score: 9}
+
+	for name, provider := range map[string]VectorProvider{
+		"fallback":  newMockVectorProvider(),
+		"projected": &projectingVectorProvider{mockVectorProvider: newMockVectorProvider()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			index := NewIndex[testMetadata](provider, WithSearchFields("category"))
+			if err := index.Upsert(ctx, id, []float32{1, 0}, meta); err != nil {
+				t.Fatalf("Upsert failed: %v", err)
+			}
+
+			results, err := index.Search(ctx, []float32{1, 0}, 1, nil)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(results))
+			}
+			if got := results[0].Metadata; got.Category != "tech" || got.Score != 0 {
+				t.Errorf("expected only category populated, got %+v", got)
+			}
+
+			full, err := index.Get(ctx, id)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			if full.Metadata != *meta {
+				t.Errorf("expected Get to return full metadata, got %+v", full.Metadata)
+			}
+		})
+	}
+
+	t.Run("provider projected", func(t *testing.T) {
+		provider := &projectingVectorProvider{mockVectorProvider: newMockVectorProvider()}
+		index := NewIndex[testMetadata](provider, WithSearchFields("category", "score"))
+		searcher, ok := index.searcher.(*projectingVectorProvider)
+		if !ok || !slices.Equal(searcher.fields, []string{"category", "score"}) {
+			t.Errorf("expected searches through the projected provider, got %#v", index.searcher)
+		}
+		if index.provider != VectorProvider(provider) {
+			t.Error("expected writes and Get through the original provider")
+		}
+	})
+}
@@ -4,11 +4,16 @@ package qdrant
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/qdrant/go-client/qdrant"
 	"github.com/zoobzio/grub"
+	"github.com/zoobzio/grub/internal/shared"
 	"github.com/zoobzio/vecna"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // uuidToPointID converts a uuid.UUID to a qdrant PointId.
@@ -20,12 +25,30 @@ func uuidToPointID(id uuid.UUID) *qdrant.PointId {
 type Config struct {
 	// Collection is the name of the Qdrant collection.
 	Collection string
+
+	// VectorName selects a named vector in a collection configured with
+	// several. Empty uses the collection's default unnamed vector. Point
+	// payloads and IDs are shared, so Upsert keeps the point's other named
+	// vectors and Delete removes the whole point.
+	VectorName string
+
+	// Dimension is the vector size EnsureCollection creates the collection
+	// (or VectorName) with.
+	Dimension int
+
+	// Metric is the distance the collection (or VectorName) was created
+	// with, used by EnsureCollection and reported by ScoreKind. Defaults to
+	// cosine. Qdrant scores cosine and dot product as similarities and
+	// Euclid as a distance.
+	Metric grub.DistanceMetric
 }
 
 // Provider implements grub.VectorProvider for Qdrant.
 type Provider struct {
 	client *qdrant.Client
 	config Config
+
+	payloadFields []string // set by Project
 }
 
 // New creates a Qdrant provider with the given client and config.
@@ -36,6 +59,99 @@ func New(client *qdrant.Client, config Config) *Provider {
 	}
 }
 
+// Project returns a provider whose searches, Recommend, and Filter return
+// only the named payload keys. It implements grub.VectorProjector; the
+// receiver is not modified and Get still returns the whole payload.
+func (p *Provider) Project(fields []string) grub.VectorProvider {
+	return &Provider{client: p.client, config: p.config, payloadFields: fields}
+}
+
+// OrderByID implements grub.VectorIDOrderer. Scroll already returns points
+// in ascending ID order, so the provider is its own ordered view.
+func (p *Provider) OrderByID() grub.VectorProvider {
+	return p
+}
+
+// maxBatchSize is the most points sent in one upsert or delete. Qdrant
+// recommends batches of about 100 points; larger ones risk request size
+// limits and timeouts.
+const maxBatchSize = 100
+
+// MaxBatchSize reports the provider's batch limit, so the Index splits
+// larger batches. It implements grub.BatchLimiter.
+func (p *Provider) MaxBatchSize() int {
+	return maxBatchSize
+}
+
+// ScoreKind reports how search scores are measured for Config.Metric.
+// It implements grub.VectorScorer.
+func (p *Provider) ScoreKind() grub.ScoreKind {
+	switch metric := p.metric(); metric {
+	case grub.DistanceCosine, grub.DistanceInnerProduct:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreSimilarity}
+	case grub.DistanceL2:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreDistance}
+	}
+	return grub.ScoreKind{}
+}
+
+// metric returns Config.Metric, defaulting to cosine.
+func (p *Provider) metric() grub.DistanceMetric {
+	if p.config.Metric == "" {
+		return grub.DistanceCosine
+	}
+	return p.config.Metric
+}
+
+// distance maps Config.Metric to its Qdrant distance.
+func (p *Provider) distance() (qdrant.Distance, error) {
+	switch p.metric() {
+	case grub.DistanceCosine:
+		return qdrant.Distance_Cosine, nil
+	case grub.DistanceL2:
+		return qdrant.Distance_Euclid, nil
+	case grub.DistanceInnerProduct:
+		return qdrant.Distance_Dot, nil
+	}
+	return qdrant.Distance_UnknownDistance, fmt.Errorf("qdrant: unsupported metric %q", p.config.Metric)
+}
+
+// EnsureCollection creates the collection with a vector of Config.Dimension
+// and Config.Metric, named Config.VectorName if set. An existing collection
+// is left as is, even if its vectors differ.
+func (p *Provider) EnsureCollection(ctx context.Context) error {
+	create, err := p.createCollection()
+	if err != nil {
+		return err
+	}
+	exists, err := p.client.CollectionExists(ctx, p.config.Collection)
+	if err != nil || exists {
+		return err
+	}
+	err = p.client.CreateCollection(ctx, create)
+	if status.Code(err) == codes.AlreadyExists {
+		return nil
+	}
+	return err
+}
+
+// createCollection returns the request EnsureCollection sends.
+func (p *Provider) createCollection() (*qdrant.CreateCollection, error) {
+	if p.config.Dimension <= 0 {
+		return nil, errors.New("qdrant: EnsureCollection requires Config.Dimension")
+	}
+	distance, err := p.distance()
+	if err != nil {
+		return nil, err
+	}
+	params := &qdrant.VectorParams{Size: uint64(p.config.Dimension), Distance: distance}
+	vectors := qdrant.NewVectorsConfig(params)
+	if p.config.VectorName != "" {
+		vectors = qdrant.NewVectorsConfigMap(map[string]*qdrant.VectorParams{p.config.VectorName: params})
+	}
+	return &qdrant.CreateCollection{CollectionName: p.config.Collection, VectorsConfig: vectors}, nil
+}
+
 // Upsert stores or updates a vector with associated metadata.
 func (p *Provider) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata []byte) error {
 	payload, err := bytesToPayload(metadata)
@@ -46,10 +162,13 @@ func (p *Provider) Upsert(ctx context.Context, id uuid.UUID, vector []float32, m
 	points := []*qdrant.PointStruct{
 		{
 			Id:      uuidToPointID(id),
-			Vectors: qdrant.NewVectors(vector...),
+			Vectors: p.vectors(vector),
 			Payload: payload,
 		},
 	}
+	if err := p.keepOtherVectors(ctx, points); err != nil {
+		return err
+	}
 
 	_, err = p.client.Upsert(ctx, &qdrant.UpsertPoints{
 		CollectionName: p.config.Collection,
@@ -73,10 +192,13 @@ func (p *Provider) UpsertBatch(ctx context.Context, vectors []grub.VectorRecord)
 		}
 		points[i] = &qdrant.PointStruct{
 			Id:      uuidToPointID(v.ID),
-			Vectors: qdrant.NewVectors(v.Vector...),
+			Vectors: p.vectors(v.Vector),
 			Payload: payload,
 		}
 	}
+	if err := p.keepOtherVectors(ctx, points); err != nil {
+		return err
+	}
 
 	_, err := p.client.Upsert(ctx, &qdrant.UpsertPoints{
 		CollectionName: p.config.Collection,
@@ -91,11 +213,11 @@ func (p *Provider) Get(ctx context.Context, id uuid.UUID) ([]float32, *grub.Vect
 	resp, err := p.client.Get(ctx, &qdrant.GetPoints{
 		CollectionName: p.config.Collection,
 		Ids:            []*qdrant.PointId{uuidToPointID(id)},
-		WithVectors:    qdrant.NewWithVectors(true),
+		WithVectors:    p.withVectors(),
 		WithPayload:    qdrant.NewWithPayload(true),
 	})
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, shared.CtxErr(ctx, err)
 	}
 
 	if len(resp) == 0 {
@@ -103,7 +225,7 @@ func (p *Provider) Get(ctx context.Context, id uuid.UUID) ([]float32, *grub.Vect
 	}
 
 	point := resp[0]
-	vector := point.Vectors.GetVector().Data
+	vector := p.vectorData(point.Vectors)
 	metadata, err := payloadToBytes(point.Payload)
 	if err != nil {
 		return nil, nil, err
@@ -160,12 +282,21 @@ func (p *Provider) DeleteBatch(ctx context.Context, ids []uuid.UUID) error {
 
 // Search performs similarity search and returns the k nearest neighbors.
 func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter map[string]any) ([]grub.VectorResult, error) {
+	return p.SearchPage(ctx, vector, k, 0, filter)
+}
+
+// SearchPage performs similarity search, skipping the first offset results.
+func (p *Provider) SearchPage(ctx context.Context, vector []float32, k, offset int, filter map[string]any) ([]grub.VectorResult, error) {
 	req := &qdrant.QueryPoints{
 		CollectionName: p.config.Collection,
 		Query:          qdrant.NewQuery(vector...),
+		Using:          p.using(),
 		Limit:          qdrant.PtrOf(uint64(k)),
-		WithVectors:    qdrant.NewWithVectors(true),
-		WithPayload:    qdrant.NewWithPayload(true),
+		WithVectors:    p.withVectors(),
+		WithPayload:    p.withPayload(),
+	}
+	if offset > 0 {
+		req.Offset = qdrant.PtrOf(uint64(offset))
 	}
 
 	if len(filter) > 0 {
@@ -174,42 +305,108 @@ func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter m
 
 	resp, err := p.client.Query(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, shared.CtxErr(ctx, err)
 	}
 
-	results := make([]grub.VectorResult, len(resp))
-	for i, scored := range resp {
-		id, err := uuid.Parse(scored.Id.GetUuid())
+	return p.scoredResults(resp)
+}
+
+// Query performs similarity search with vecna filter support.
+func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	return p.QueryPage(ctx, vector, k, 0, filter)
+}
+
+// QueryPage performs similarity search with vecna filter support, skipping
+// the first offset results.
+func (p *Provider) QueryPage(ctx context.Context, vector []float32, k, offset int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	req := &qdrant.QueryPoints{
+		CollectionName: p.config.Collection,
+		Query:          qdrant.NewQuery(vector...),
+		Using:          p.using(),
+		Limit:          qdrant.PtrOf(uint64(k)),
+		WithVectors:    p.withVectors(),
+		WithPayload:    p.withPayload(),
+	}
+	if offset > 0 {
+		req.Offset = qdrant.PtrOf(uint64(offset))
+	}
+
+	if filter != nil {
+		translated, err := translateFilter(filter)
 		if err != nil {
 			return nil, err
 		}
-		metadata, err := payloadToBytes(scored.Payload)
+		req.Filter = translated
+	}
+
+	resp, err := p.client.Query(ctx, req)
+	if err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+
+	return p.scoredResults(resp)
+}
+
+// SearchBatch runs one similarity search per query in a single QueryBatch
+// round-trip. Qdrant fails the batch as a whole, so errors do not identify
+// the failing query.
+func (p *Provider) SearchBatch(ctx context.Context, queries [][]float32, k int, filter *vecna.Filter) ([][]grub.VectorResult, error) {
+	var translated *qdrant.Filter
+	if filter != nil {
+		var err error
+		translated, err = translateFilter(filter)
 		if err != nil {
 			return nil, err
 		}
-		var vec []float32
-		if scored.Vectors != nil {
-			vec = scored.Vectors.GetVector().Data
-		}
-		results[i] = grub.VectorResult{
-			ID:       id,
-			Vector:   vec,
-			Metadata: metadata,
-			Score:    scored.Score,
+	}
+
+	points := make([]*qdrant.QueryPoints, len(queries))
+	for i, vector := range queries {
+		points[i] = &qdrant.QueryPoints{
+			CollectionName: p.config.Collection,
+			Query:          qdrant.NewQuery(vector...),
+			Using:          p.using(),
+			Limit:          qdrant.PtrOf(uint64(k)),
+			Filter:         translated,
+			WithVectors:    p.withVectors(),
+			WithPayload:    p.withPayload(),
 		}
 	}
 
-	return results, nil
+	resp, err := p.client.QueryBatch(ctx, &qdrant.QueryBatchPoints{
+		CollectionName: p.config.Collection,
+		QueryPoints:    points,
+	})
+	if err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+
+	batches := make([][]grub.VectorResult, len(resp))
+	for i, batch := range resp {
+		results, err := p.scoredResults(batch.GetResult())
+		if err != nil {
+			return nil, &grub.BatchQueryError{Index: i, Err: err}
+		}
+		batches[i] = results
+	}
+	return batches, nil
 }
 
-// Query performs similarity search with vecna filter support.
-func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+// Recommend returns up to k vectors closest to the average of the stored
+// vectors at ids, using Qdrant's recommend query. Qdrant excludes the seeds
+// from the results.
+func (p *Provider) Recommend(ctx context.Context, ids []uuid.UUID, k int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	positive := make([]*qdrant.VectorInput, len(ids))
+	for i, id := range ids {
+		positive[i] = qdrant.NewVectorInputID(uuidToPointID(id))
+	}
 	req := &qdrant.QueryPoints{
 		CollectionName: p.config.Collection,
-		Query:          qdrant.NewQuery(vector...),
+		Query:          qdrant.NewQueryRecommend(&qdrant.RecommendInput{Positive: positive}),
+		Using:          p.using(),
 		Limit:          qdrant.PtrOf(uint64(k)),
-		WithVectors:    qdrant.NewWithVectors(true),
-		WithPayload:    qdrant.NewWithPayload(true),
+		WithVectors:    p.withVectors(),
+		WithPayload:    p.withPayload(),
 	}
 
 	if filter != nil {
@@ -222,9 +419,23 @@ func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *v
 
 	resp, err := p.client.Query(ctx, req)
 	if err != nil {
-		return nil, err
+		// Qdrant rejects a missing seed with a generic error; report it as
+		// ErrNotFound when a seed is in fact absent.
+		if found, existsErr := p.ExistsBatch(ctx, ids); existsErr == nil {
+			for _, id := range ids {
+				if !found[id] {
+					return nil, grub.ErrNotFound
+				}
+			}
+		}
+		return nil, shared.CtxErr(ctx, err)
 	}
 
+	return p.scoredResults(resp)
+}
+
+// scoredResults converts Qdrant scored points to grub results.
+func (p *Provider) scoredResults(resp []*qdrant.ScoredPoint) ([]grub.VectorResult, error) {
 	results := make([]grub.VectorResult, len(resp))
 	for i, scored := range resp {
 		id, err := uuid.Parse(scored.Id.GetUuid())
@@ -235,23 +446,18 @@ func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *v
 		if err != nil {
 			return nil, err
 		}
-		var vec []float32
-		if scored.Vectors != nil {
-			vec = scored.Vectors.GetVector().Data
-		}
 		results[i] = grub.VectorResult{
 			ID:       id,
-			Vector:   vec,
+			Vector:   p.vectorData(scored.Vectors),
 			Metadata: metadata,
 			Score:    scored.Score,
 		}
 	}
-
 	return results, nil
 }
 
 // Filter returns vectors matching the metadata filter without similarity search.
-// Uses Scroll API with filter, returning results in storage order.
+// Uses Scroll API with filter, returning results in ascending ID order.
 func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int) ([]grub.VectorResult, error) {
 	var pageLimit uint32 = 100
 	if limit > 0 && limit < 100 {
@@ -271,18 +477,21 @@ func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int)
 	var offset *qdrant.PointId
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		req := &qdrant.ScrollPoints{
 			CollectionName: p.config.Collection,
 			Limit:          qdrant.PtrOf(pageLimit),
-			WithVectors:    qdrant.NewWithVectors(true),
-			WithPayload:    qdrant.NewWithPayload(true),
+			WithVectors:    p.withVectors(),
+			WithPayload:    p.withPayload(),
 			Offset:         offset,
 			Filter:         qdrantFilter,
 		}
 
 		resp, err := p.client.Scroll(ctx, req)
 		if err != nil {
-			return nil, err
+			return nil, shared.CtxErr(ctx, err)
 		}
 
 		if len(resp) == 0 {
@@ -298,13 +507,9 @@ func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int)
 			if err != nil {
 				return nil, err
 			}
-			var vec []float32
-			if point.Vectors != nil {
-				vec = point.Vectors.GetVector().Data
-			}
 			results = append(results, grub.VectorResult{
 				ID:       id,
-				Vector:   vec,
+				Vector:   p.vectorData(point.Vectors),
 				Metadata: metadata,
 			})
 			if limit > 0 && len(results) >= limit {
@@ -333,6 +538,9 @@ func (p *Provider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
 	var offset *qdrant.PointId
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		req := &qdrant.ScrollPoints{
 			CollectionName: p.config.Collection,
 			Limit:          qdrant.PtrOf(pageLimit),
@@ -343,7 +551,7 @@ func (p *Provider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
 
 		resp, err := p.client.Scroll(ctx, req)
 		if err != nil {
-			return nil, err
+			return nil, shared.CtxErr(ctx, err)
 		}
 
 		if len(resp) == 0 {
@@ -380,11 +588,118 @@ func (p *Provider) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
 		WithPayload:    qdrant.NewWithPayload(false),
 	})
 	if err != nil {
-		return false, err
+		return false, shared.CtxErr(ctx, err)
 	}
 	return len(resp) > 0, nil
 }
 
+// ExistsBatch checks many vector IDs in a single request.
+func (p *Provider) ExistsBatch(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	pointIDs := make([]*qdrant.PointId, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = uuidToPointID(id)
+	}
+
+	resp, err := p.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: p.config.Collection,
+		Ids:            pointIDs,
+		WithVectors:    qdrant.NewWithVectors(false),
+		WithPayload:    qdrant.NewWithPayload(false),
+	})
+	if err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+
+	result := make(map[uuid.UUID]bool, len(ids))
+	for _, point := range resp {
+		id, err := uuid.Parse(point.Id.GetUuid())
+		if err != nil {
+			return nil, err
+		}
+		result[id] = true
+	}
+	return result, nil
+}
+
+// vectors wraps vector for a point write, under VectorName if set.
+func (p *Provider) vectors(vector []float32) *qdrant.Vectors {
+	if p.config.VectorName == "" {
+		return qdrant.NewVectors(vector...)
+	}
+	return qdrant.NewVectorsMap(map[string]*qdrant.Vector{
+		p.config.VectorName: qdrant.NewVector(vector...),
+	})
+}
+
+// vectorData extracts the configured vector from a returned point.
+func (p *Provider) vectorData(v *qdrant.Vectors) []float32 {
+	if p.config.VectorName == "" {
+		return v.GetVector().GetData()
+	}
+	return v.GetVectors().GetVectors()[p.config.VectorName].GetData()
+}
+
+// using names the vector a query searches, or nil for the default.
+func (p *Provider) using() *string {
+	if p.config.VectorName == "" {
+		return nil
+	}
+	return qdrant.PtrOf(p.config.VectorName)
+}
+
+// withPayload selects the payload returned with search results: the keys
+// given to Project, or all of it.
+func (p *Provider) withPayload() *qdrant.WithPayloadSelector {
+	if len(p.payloadFields) == 0 {
+		return qdrant.NewWithPayload(true)
+	}
+	return qdrant.NewWithPayloadInclude(p.payloadFields...)
+}
+
+// withVectors selects the configured vector for return with each point.
+func (p *Provider) withVectors() *qdrant.WithVectorsSelector {
+	if p.config.VectorName == "" {
+		return qdrant.NewWithVectors(true)
+	}
+	return qdrant.NewWithVectorsInclude(p.config.VectorName)
+}
+
+// keepOtherVectors copies each existing point's other named vectors into
+// points, since Qdrant's upsert replaces a point whole. It is a no-op
+// without VectorName. The read and the following upsert are not atomic, so
+// a concurrent write to another named vector of the same point may be lost.
+func (p *Provider) keepOtherVectors(ctx context.Context, points []*qdrant.PointStruct) error {
+	if p.config.VectorName == "" {
+		return nil
+	}
+	ids := make([]*qdrant.PointId, len(points))
+	for i, point := range points {
+		ids[i] = point.Id
+	}
+	existing, err := p.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: p.config.Collection,
+		Ids:            ids,
+		WithVectors:    qdrant.NewWithVectors(true),
+		WithPayload:    qdrant.NewWithPayload(false),
+	})
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]map[string]*qdrant.Vector, len(existing))
+	for _, point := range existing {
+		stored[point.Id.GetUuid()] = point.Vectors.GetVectors().GetVectors()
+	}
+	for _, point := range points {
+		named := point.Vectors.GetVectors().GetVectors()
+		for name, vector := range stored[point.Id.GetUuid()] {
+			if _, ok := named[name]; !ok {
+				named[name] = vector
+			}
+		}
+	}
+	return nil
+}
+
 // toPayload converts map[string]any to qdrant payload.
 func toPayload(m map[string]any) map[string]*qdrant.Value {
 	payload := make(map[string]*qdrant.Value, len(m))
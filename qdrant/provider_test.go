@@ -1,7 +1,11 @@
 package qdrant
 
 import (
+	"slices"
 	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/zoobzio/grub"
 )
 
 func TestNew(t *testing.T) {
@@ -13,3 +17,131 @@ func TestNew(t *testing.T) {
 		t.Errorf("expected collection 'test', got %q", p.config.Collection)
 	}
 }
+
+func TestNamedVector(t *testing.T) {
+	vector := []float32{0.1, 0.2, 0.3}
+
+	t.Run("default", func(t *testing.T) {
+		p := New(nil, Config{Collection: "test"})
+		if p.using() != nil {
+			t.Errorf("expected no using, got %q", *p.using())
+		}
+		v := p.vectors(vector)
+		if v.GetVectors() != nil {
+			t.Error("expected an unnamed vector")
+		}
+		if got := p.vectorData(v); !slices.Equal(got, vector) {
+			t.Errorf("expected %v, got %v", vector, got)
+		}
+	})
+
+	t.Run("named", func(t *testing.T) {
+		p := New(nil, Config{Collection: "test", VectorName: "title"})
+		if using := p.using(); using == nil || *using != "title" {
+			t.Errorf("expected using 'title', got %v", using)
+		}
+		v := p.vectors(vector)
+		if _, ok := v.GetVectors().GetVectors()["title"]; !ok {
+			t.Errorf("expected vector named 'title', got %v", v)
+		}
+		if got := p.vectorData(v); !slices.Equal(got, vector) {
+			t.Errorf("expected %v, got %v", vector, got)
+		}
+		include := p.withVectors().GetInclude().GetNames()
+		if !slices.Equal(include, []string{"title"}) {
+			t.Errorf("expected only 'title' returned, got %v", include)
+		}
+
+		other := New(nil, Config{Collection: "test", VectorName: "body"})
+		if got := other.vectorData(v); got != nil {
+			t.Errorf("expected no data for another name, got %v", got)
+		}
+	})
+}
+
+func TestProject(t *testing.T) {
+	p := New(nil, Config{Collection: "test", VectorName: "title"})
+	if !p.withPayload().GetEnable() {
+		t.Error("expected whole payload without projection")
+	}
+
+	projected, ok := p.Project([]string{"title", "category"}).(*Provider)
+	if !ok {
+		t.Fatal("Project did not return a *Provider")
+	}
+	if projected.config != p.config {
+		t.Errorf("expected config preserved, got %+v", projected.config)
+	}
+	include := projected.withPayload().GetInclude().GetFields()
+	if !slices.Equal(include, []string{"title", "category"}) {
+		t.Errorf("expected payload include [title category], got %v", include)
+	}
+	if p.payloadFields != nil {
+		t.Errorf("original provider modified: %v", p.payloadFields)
+	}
+}
+
+func TestOrderByID(t *testing.T) {
+	p := New(nil, Config{Collection: "test"})
+	if ordered, ok := p.OrderByID().(*Provider); !ok || ordered != p {
+		t.Error("expected the provider to be its own ordered view")
+	}
+}
+
+func TestScoreKind(t *testing.T) {
+	for metric, want := range map[grub.DistanceMetric]grub.ScoreKind{
+		"":                        {Metric: grub.DistanceCosine, Measure: grub.ScoreSimilarity},
+		grub.DistanceCosine:       {Metric: grub.DistanceCosine, Measure: grub.ScoreSimilarity},
+		grub.DistanceInnerProduct: {Metric: grub.DistanceInnerProduct, Measure: grub.ScoreSimilarity},
+		grub.DistanceL2:           {Metric: grub.DistanceL2, Measure: grub.ScoreDistance},
+		"manhattan":               {},
+	} {
+		if got := New(nil, Config{Collection: "test", Metric: metric}).ScoreKind(); got != want {
+			t.Errorf("%q: expected %+v, got %+v", metric, want, got)
+		}
+	}
+}
+
+func TestMaxBatchSize(t *testing.T) {
+	var _ grub.BatchLimiter = (*Provider)(nil)
+	if got := New(nil, Config{Collection: "test"}).MaxBatchSize(); got != maxBatchSize {
+		t.Errorf("expected %d, got %d", maxBatchSize, got)
+	}
+}
+
+func TestCreateCollection(t *testing.T) {
+	t.Run("default vector", func(t *testing.T) {
+		p := New(nil, Config{Collection: "docs", Dimension: 3, Metric: grub.DistanceL2})
+		req, err := p.createCollection()
+		if err != nil {
+			t.Fatalf("createCollection failed: %v", err)
+		}
+		params := req.GetVectorsConfig().GetParams()
+		if req.GetCollectionName() != "docs" || params.GetSize() != 3 || params.GetDistance() != qdrant.Distance_Euclid {
+			t.Errorf("unexpected request %v", req)
+		}
+	})
+
+	t.Run("named vector", func(t *testing.T) {
+		p := New(nil, Config{Collection: "docs", VectorName: "title", Dimension: 3})
+		req, err := p.createCollection()
+		if err != nil {
+			t.Fatalf("createCollection failed: %v", err)
+		}
+		params := req.GetVectorsConfig().GetParamsMap().GetMap()["title"]
+		if params.GetSize() != 3 || params.GetDistance() != qdrant.Distance_Cosine {
+			t.Errorf("expected a cosine vector named 'title', got %v", req.GetVectorsConfig())
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		for name, config := range map[string]Config{
+			"no dimension":   {Collection: "docs"},
+			"unknown metric": {Collection: "docs", Dimension: 3, Metric: "manhattan"},
+		} {
+			if _, err := New(nil, config).createCollection(); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
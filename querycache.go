@@ -0,0 +1,263 @@
+package grub
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// queryCache stores Database statement results in a StoreProvider.
+//
+// Entries are keyed by table, generation token, statement kind and name, and
+// a hash of the rendered SQL and the params, so two statements sharing a
+// name never share entries. Invalidation replaces the table's generation token,
+// orphaning every entry cached under the old one at once; orphans expire with
+// their TTL. A nil *queryCache is valid and caches nothing.
+type queryCache struct {
+	store  StoreProvider
+	ttl    time.Duration
+	prefix string
+}
+
+func newQueryCache(store StoreProvider, ttl time.Duration, table string) *queryCache {
+	return &queryCache{
+		store:  store,
+		ttl:    ttl,
+		prefix: "grub:qc:" + table + ":",
+	}
+}
+
+// lookup returns the cache key for a statement call and its cached payload,
+// if any. render returns the statement's SQL; it is only called with the
+// cache on. An empty key means the call must bypass the cache: caching is
+// disabled, the generation token could not be read, or the statement cannot
+// be rendered or its params hashed.
+func (c *queryCache) lookup(ctx context.Context, kind, name string, render func() (string, error), params map[string]any) (key string, data []byte, hit bool) {
+	if c == nil {
+		return "", nil, false
+	}
+	gen, err := c.store.Get(ctx, c.prefix+"gen")
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return "", nil, false
+	}
+	query, err := render()
+	if err != nil {
+		return "", nil, false
+	}
+	// encoding/json sorts map keys, so equal params hash equally.
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return "", nil, false
+	}
+	h := sha256.New()
+	h.Write([]byte(query))
+	h.Write([]byte{0})
+	h.Write(encoded)
+	sum := h.Sum(nil)
+	key = c.prefix + string(gen) + ":" + kind + ":" + name + ":" + hex.EncodeToString(sum[:16])
+
+	data, err = c.store.Get(ctx, key)
+	if err != nil {
+		return key, nil, false
+	}
+	return key, data, true
+}
+
+// put caches v under key as JSON. Failures are ignored; the next call
+// misses and queries the database.
+func (c *queryCache) put(ctx context.Context, key string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	c.putData(ctx, key, data)
+}
+
+// putData caches data under key. Failures are ignored, as in put.
+func (c *queryCache) putData(ctx context.Context, key string, data []byte) {
+	if c == nil || key == "" {
+		return
+	}
+	_ = c.store.Set(ctx, key, data, c.ttl)
+}
+
+// invalidate orphans every entry cached for the table. It runs detached from
+// ctx's cancellation, since a write may have reached the database even when
+// the caller's context expired.
+func (c *queryCache) invalidate(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	_ = c.store.Set(context.WithoutCancel(ctx), c.prefix+"gen", []byte(uuid.NewString()), 0)
+}
+
+// invalidateCache orphans the table's cached entries after a write made in
+// tx, or outside a transaction when tx is nil. A write in a transaction
+// begun by WithTx, made with the context WithTx passed its fn, is
+// invalidated once that transaction commits, so a concurrent read cannot
+// re-cache the rows it replaces. Other writes invalidate at once; in a
+// transaction the caller began and commits itself, a read between the write
+// and the commit may re-cache the old rows until their TTL.
+func (d *Database[T]) invalidateCache(ctx context.Context, tx *sqlx.Tx) {
+	if d.cache == nil || (tx != nil && invalidateOnCommit(ctx, tx, d.cache)) {
+		return
+	}
+	d.cache.invalidate(ctx)
+}
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	valuerType  = reflect.TypeFor[driver.Valuer]()
+)
+
+func init() {
+	// Valuer columns such as sql.NullTime cache their driver value, which
+	// gob carries as an interface.
+	gob.Register(time.Time{})
+}
+
+// cacheRows caches records under key by their db-mapped fields, the
+// fields a scan fills, so a hit returns the same records a miss does: json
+// tags play no part and unmapped fields stay zero. Records gob cannot
+// encode are not cached.
+func (d *Database[T]) cacheRows(ctx context.Context, key string, records []*T) {
+	if key == "" {
+		return
+	}
+	data, err := d.encodeRows(records)
+	if err != nil {
+		return
+	}
+	d.cache.putData(ctx, key, data)
+}
+
+// encodeRows encodes records by their db-mapped fields. Fields
+// implementing sql.Scanner and driver.Valuer are encoded as their driver
+// value. Decoding the result with decodeRows yields records sharing no
+// memory with the originals.
+func (d *Database[T]) encodeRows(records []*T) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(len(records)); err != nil {
+		return nil, err
+	}
+	fields := d.cacheFields()
+	for _, rec := range records {
+		v := reflect.ValueOf(rec).Elem()
+		for _, index := range fields {
+			if err := encodeField(enc, v, index); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeRows decodes records encoded by encodeRows.
+func (d *Database[T]) decodeRows(data []byte) ([]*T, error) {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	var n int
+	if err := dec.Decode(&n); err != nil {
+		return nil, err
+	}
+	fields := d.cacheFields()
+	records := make([]*T, n)
+	for i := range records {
+		records[i] = new(T)
+		v := reflect.ValueOf(records[i]).Elem()
+		for _, index := range fields {
+			if err := decodeField(dec, v, index); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return records, nil
+}
+
+// cacheFields returns the index of each field of T mapped to a column.
+func (d *Database[T]) cacheFields() [][]int {
+	var fields [][]int
+	for _, field := range d.executor.Soy().Metadata().Fields {
+		if col := field.Tags["db"]; col != "" && col != "-" {
+			fields = append(fields, field.Index)
+		}
+	}
+	return fields
+}
+
+// scanned reports whether values of t are scanned through sql.Scanner and
+// read back through driver.Valuer, looking through one pointer.
+func scanned(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return reflect.PointerTo(t).Implements(scannerType) && t.Implements(valuerType)
+}
+
+// encodeField writes whether v's field at index is set, then its value.
+func encodeField(enc *gob.Encoder, v reflect.Value, index []int) error {
+	f, err := v.FieldByIndexErr(index)
+	set := err == nil && !isNil(f)
+	if err := enc.Encode(set); err != nil || !set {
+		return err
+	}
+	if !scanned(f.Type()) {
+		return enc.EncodeValue(f)
+	}
+	value, err := reflect.Indirect(f).Interface().(driver.Valuer).Value()
+	if err != nil {
+		return err
+	}
+	return enc.Encode(&value)
+}
+
+// decodeField reads a field written by encodeField into v, allocating
+// embedded pointers on the way.
+func decodeField(dec *gob.Decoder, v reflect.Value, index []int) error {
+	var set bool
+	if err := dec.Decode(&set); err != nil || !set {
+		return err
+	}
+	f := v
+	for n, i := range index {
+		if n > 0 && f.Kind() == reflect.Pointer {
+			if f.IsNil() {
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+			f = f.Elem()
+		}
+		f = f.Field(i)
+	}
+	if !scanned(f.Type()) {
+		return dec.DecodeValue(f.Addr())
+	}
+	var value driver.Value
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+	if f.Kind() == reflect.Pointer {
+		f.Set(reflect.New(f.Type().Elem()))
+		f = f.Elem()
+	}
+	return f.Addr().Interface().(sql.Scanner).Scan(value)
+}
+
+// isNil reports whether v is a nil pointer, map, slice, or interface.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,310 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// loadedDBUser records AfterLoad in an unexported field the cache drops.
+type loadedDBUser struct {
+	ID     int    `db:"id" constraints:"primarykey"`
+	Email  string `db:"email" constraints:"notnull,unique"`
+	Name   string `db:"name" constraints:"notnull"`
+	Age    *int   `db:"age"`
+	loaded bool
+}
+
+func (u *loadedDBUser) AfterLoad(_ context.Context) error {
+	u.loaded = true
+	return nil
+}
+
+// countedDBUser counts AfterLoad calls, so a hook that ran twice on a hit
+// would show.
+type countedDBUser struct {
+	ID    int    `db:"id" constraints:"primarykey"`
+	Email string `db:"email" constraints:"notnull,unique"`
+	Name  string `db:"name" constraints:"notnull"`
+	Age   *int   `db:"age"`
+	Loads int    `db:"-"`
+}
+
+// hiddenDBUser maps columns that JSON would drop or mangle.
+type hiddenDBUser struct {
+	ID   int                  `db:"id" constraints:"primarykey"`
+	Hash string               `db:"hash" json:"-"`
+	Nick sql.NullString       `db:"nick"`
+	Seen *time.Time           `db:"seen"`
+	Tags JSON[map[string]any] `db:"tags"`
+}
+
+func (u *countedDBUser) AfterLoad(_ context.Context) error {
+	u.Loads++
+	return nil
+}
+
+func newCachedDatabase[T any](t *testing.T) (*Database[T], *mockdb.Capture, *mockStoreProvider) {
+	t.Helper()
+	mockDB, capture := mockdb.New()
+	store := newMockStoreProvider()
+	db, err := NewDatabase[T](mockDB, "test_users", testDBRenderer, WithQueryCache(store, time.Minute))
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	return db, capture, store
+}
+
+func TestDatabase_QueryCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("second identical query hits cache", func(t *testing.T) {
+		db, capture, _ := newCachedDatabase[TestDBUser](t)
+		byMinAge := edamame.NewQueryStatement("by-min-age", "", edamame.QuerySpec{
+			Where: []edamame.ConditionSpec{{Field: "age", Operator: ">=", Param: "min_age"}},
+		})
+
+		if _, err := db.ExecQuery(ctx, byMinAge, map[string]any{"min_age": 10}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		queries := len(capture.Queries)
+		if queries == 0 {
+			t.Fatal("expected the first call to query the database")
+		}
+		if _, err := db.ExecQuery(ctx, byMinAge, map[string]any{"min_age": 10}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		if len(capture.Queries) != queries {
+			t.Errorf("expected cache hit, got %d new queries", len(capture.Queries)-queries)
+		}
+
+		if _, err := db.ExecQuery(ctx, byMinAge, map[string]any{"min_age": 20}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		if len(capture.Queries) == queries {
+			t.Error("expected different params to miss the cache")
+		}
+	})
+
+	t.Run("statements sharing a name miss each other", func(t *testing.T) {
+		db, capture, _ := newCachedDatabase[TestDBUser](t)
+		byAge := edamame.NewQueryStatement("list", "", edamame.QuerySpec{
+			Where: []edamame.ConditionSpec{{Field: "age", Operator: "=", Param: "v"}},
+		})
+		byName := edamame.NewQueryStatement("list", "", edamame.QuerySpec{
+			Where: []edamame.ConditionSpec{{Field: "name", Operator: "=", Param: "v"}},
+		})
+
+		if _, err := db.ExecQuery(ctx, byAge, map[string]any{"v": 1}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		queries := len(capture.Queries)
+		if _, err := db.ExecQuery(ctx, byName, map[string]any{"v": 1}); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		if len(capture.Queries) == queries {
+			t.Error("expected a different statement with the same name to miss the cache")
+		}
+	})
+
+	t.Run("Set invalidates", func(t *testing.T) {
+		db, capture, _ := newCachedDatabase[TestDBUser](t)
+
+		if _, err := db.ExecQuery(ctx, QueryAll, nil); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		// The mock driver returns no rows for INSERT ... RETURNING, so Set
+		// fails; the write was still attempted and must invalidate.
+		_ = db.Set(ctx, "1", &TestDBUser{ID: 1, Email: "a@example.com", Name: "A"})
+		queries := len(capture.Queries)
+
+		if _, err := db.ExecQuery(ctx, QueryAll, nil); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		if len(capture.Queries) == queries {
+			t.Error("expected Set to invalidate the cached query")
+		}
+	})
+
+	t.Run("Tx writes invalidate at commit", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		store := newMockStoreProvider()
+		users, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		cached, err := NewDatabase[TestDBUser](mockDB, "cached_users", testDBRenderer, WithQueryCache(store, time.Minute))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		const gen = "grub:qc:cached_users:gen"
+
+		err = users.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			// The mock returns no rows for the upsert; only the
+			// invalidation matters here.
+			_ = cached.SetTx(ctx, tx, "1", &TestDBUser{ID: 1, Email: "a@example.com", Name: "A"})
+			if _, ok := store.data[gen]; ok {
+				t.Error("expected no invalidation before the commit")
+			}
+			return nil
+		}, nil)
+		if err != nil {
+			t.Fatalf("WithTx failed: %v", err)
+		}
+		if _, ok := store.data[gen]; !ok {
+			t.Error("expected the commit to invalidate the other table's cache")
+		}
+
+		tx, err := mockDB.Beginx()
+		if err != nil {
+			t.Fatalf("Beginx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		delete(store.data, gen)
+		_ = cached.SetTx(ctx, tx, "1", &TestDBUser{ID: 1, Email: "a@example.com", Name: "A"})
+		if _, ok := store.data[gen]; !ok {
+			t.Error("expected a caller-managed Tx write to invalidate at once")
+		}
+	})
+
+	t.Run("Tx queries bypass cache", func(t *testing.T) {
+		db, capture, store := newCachedDatabase[TestDBUser](t)
+
+		err := db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			for range 2 {
+				if _, err := db.ExecQueryTx(ctx, tx, QueryAll, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil)
+		if err != nil {
+			t.Fatalf("WithTx failed: %v", err)
+		}
+
+		var selects int
+		for _, q := range capture.Queries {
+			if q.Query != "BEGIN" && q.Query != "COMMIT" {
+				selects++
+			}
+		}
+		if selects != 2 {
+			t.Errorf("expected both Tx queries to reach the database, got %d", selects)
+		}
+		for key := range store.data {
+			if key != "grub:qc:test_users:gen" {
+				t.Errorf("expected no cached entries, found %q", key)
+			}
+		}
+	})
+
+	t.Run("hits are decoded afresh and run AfterLoad", func(t *testing.T) {
+		db, _, store := newCachedDatabase[loadedDBUser](t)
+
+		render := func() (string, error) { return db.executor.RenderQuery(QueryAll) }
+		key, _, _ := db.cache.lookup(ctx, "query", QueryAll.Name(), render, nil)
+		db.cacheRows(ctx, key, []*loadedDBUser{{ID: 1, Email: "a@example.com", Name: "A"}})
+		if _, ok := store.data[key]; !ok {
+			t.Fatal("expected the rows cached")
+		}
+
+		first, err := db.ExecQuery(ctx, QueryAll, nil)
+		if err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		if len(first) != 1 || !first[0].loaded {
+			t.Fatalf("expected one record with AfterLoad applied, got %+v", first)
+		}
+		first[0].Name = "mutated"
+
+		second, err := db.ExecQuery(ctx, QueryAll, nil)
+		if err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		if second[0].Name != "A" {
+			t.Errorf("expected caller mutation not to leak into the cache, got %q", second[0].Name)
+		}
+	})
+
+	t.Run("hits return the scanned record", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		seen := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		cfg.SetRows([]string{"id", "hash", "nick", "seen", "tags"},
+			[]driver.Value{int64(1), "h4sh", "al", seen, []byte(`{"a":{"b":[1,"x"]}}`)})
+		db, err := NewDatabase[hiddenDBUser](mockDB, "test_users", testDBRenderer, WithQueryCache(newMockStoreProvider(), time.Minute))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		miss, err := db.ExecQuery(ctx, QueryAll, nil)
+		if err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		hit, err := db.ExecQuery(ctx, QueryAll, nil)
+		if err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		if len(capture.Queries) != 1 {
+			t.Fatalf("expected the second call to hit the cache, got %d queries", len(capture.Queries))
+		}
+		if len(miss) != 1 || len(hit) != 1 || !reflect.DeepEqual(miss[0], hit[0]) {
+			t.Errorf("expected the hit to match the miss:\n%+v\n%+v", miss, hit)
+		}
+		if hit[0].Hash != "h4sh" || !hit[0].Nick.Valid || hit[0].Seen == nil || !hit[0].Seen.Equal(seen) {
+			t.Errorf("expected columns kept on a hit, got %+v", hit[0])
+		}
+	})
+
+	t.Run("AfterLoad runs once per returned record", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRows(userColumns, []driver.Value{int64(1), "a@example.com", "A", nil})
+		db, err := NewDatabase[countedDBUser](mockDB, "test_users", testDBRenderer, WithQueryCache(newMockStoreProvider(), time.Minute))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		byID := edamame.NewSelectStatement("by-id", "", edamame.SelectSpec{
+			Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+		})
+		for call := range 2 {
+			records, err := db.ExecQuery(ctx, QueryAll, nil)
+			if err != nil {
+				t.Fatalf("ExecQuery failed: %v", err)
+			}
+			if len(records) != 1 || records[0].Loads != 1 {
+				t.Errorf("query call %d: expected AfterLoad once, got %+v", call, records)
+			}
+			record, err := db.ExecSelect(ctx, byID, map[string]any{"id": 1})
+			if err != nil {
+				t.Fatalf("ExecSelect failed: %v", err)
+			}
+			if record.Loads != 1 {
+				t.Errorf("select call %d: expected AfterLoad once, got %d", call, record.Loads)
+			}
+		}
+		if len(capture.Queries) != 2 {
+			t.Errorf("expected the second calls to hit the cache, got %d queries", len(capture.Queries))
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		for range 2 {
+			if _, err := db.ExecQuery(ctx, QueryAll, nil); err != nil {
+				t.Fatalf("ExecQuery failed: %v", err)
+			}
+		}
+		if len(capture.Queries) != 2 {
+			t.Errorf("expected 2 queries without a cache, got %d", len(capture.Queries))
+		}
+	})
+}
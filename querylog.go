@@ -0,0 +1,289 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// statementKey carries the statementLabel of the call a statement runs for.
+type statementKey struct{}
+
+// statementLabel names the edamame statement a Database call executes and
+// the params it was given.
+type statementLabel struct {
+	name   string
+	params []string
+}
+
+// withStatement labels ctx with the statement name and the names of params,
+// for QueryInfo. Without WithQueryLogger it returns ctx unchanged.
+func (d *Database[T]) withStatement(ctx context.Context, name string, params map[string]any) context.Context {
+	if !d.logQueries {
+		return ctx
+	}
+	return context.WithValue(ctx, statementKey{}, statementLabel{name: name, params: slices.Sorted(maps.Keys(params))})
+}
+
+// queryReporter reports statements to a QueryLogger.
+type queryReporter struct {
+	fn     QueryLogger
+	params bool // report bound values; set by WithParamLogging
+}
+
+// report passes the statement run under ctx to the logger. Values are
+// dropped unless WithParamLogging is set.
+func (l queryReporter) report(ctx context.Context, query string, values []any, start time.Time, rows int64, err error) {
+	info := QueryInfo{SQL: query, Duration: time.Since(start), Rows: rows, Err: err}
+	if label, ok := ctx.Value(statementKey{}).(statementLabel); ok {
+		info.Statement, info.ParamNames = label.name, label.params
+	}
+	if l.params {
+		info.Params = values
+	}
+	l.fn(ctx, info)
+}
+
+// loggedDB returns a *sqlx.DB that runs every statement through db and
+// reports it to log. Each connection of the returned pool borrows one from
+// db for the duration of a call or transaction, so db's pool settings still
+// bound the connections in use. Transactions begun on db itself bypass the
+// logger; begin them through the Database to have them logged.
+func loggedDB(db *sqlx.DB, log queryReporter) *sqlx.DB {
+	pool := sql.OpenDB(&logConnector{db: db.DB, log: log})
+	pool.SetMaxIdleConns(0)
+	wrapped := sqlx.NewDb(pool, db.DriverName())
+	wrapped.Mapper = db.Mapper
+	return wrapped
+}
+
+// logConnector hands out logConns backed by connections of db.
+type logConnector struct {
+	db  *sql.DB
+	log queryReporter
+}
+
+func (c *logConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &logConn{conn: conn, log: c.log}, nil
+}
+
+func (c *logConnector) Driver() driver.Driver {
+	return c.db.Driver()
+}
+
+// runner is satisfied by both *sql.Conn and *sql.Tx.
+type runner interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// logConn forwards statements to a borrowed connection, or to the open
+// transaction on it, and logs each one once it completes.
+type logConn struct {
+	conn *sql.Conn
+	tx   *sql.Tx
+	log  queryReporter
+}
+
+func (c *logConn) runner() runner {
+	if c.tx != nil {
+		return c.tx
+	}
+	return c.conn
+}
+
+// CheckNamedValue accepts every argument as is, leaving conversion to the
+// underlying driver.
+func (*logConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *logConn) QueryContext(ctx context.Context, query string, named []driver.NamedValue) (driver.Rows, error) {
+	args, values := bindArgs(named)
+	start := time.Now()
+	rows, err := c.runner().QueryContext(ctx, query, args...)
+	if err != nil {
+		c.log.report(ctx, query, values, start, 0, err)
+		return nil, err
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		c.log.report(ctx, query, values, start, 0, err)
+		return nil, err
+	}
+	// The statement is logged when the rows are closed, so the duration
+	// covers reading the results and iteration errors are reported.
+	return &logRows{rows: rows, cols: cols, done: func(read int64, err error) {
+		c.log.report(ctx, query, values, start, read, err)
+	}}, nil
+}
+
+func (c *logConn) ExecContext(ctx context.Context, query string, named []driver.NamedValue) (driver.Result, error) {
+	args, values := bindArgs(named)
+	start := time.Now()
+	res, err := c.runner().ExecContext(ctx, query, args...)
+	var affected int64
+	if err == nil {
+		affected, _ = res.RowsAffected()
+	}
+	c.log.report(ctx, query, values, start, affected, err)
+	return res, err
+}
+
+func (c *logConn) Prepare(query string) (driver.Stmt, error) {
+	return &connStmt{conn: c, query: query}, nil
+}
+
+func (c *logConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *logConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.conn.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.IsolationLevel(opts.Isolation),
+		ReadOnly:  opts.ReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.tx = tx
+	return &logTx{conn: c}, nil
+}
+
+func (c *logConn) Close() error {
+	if c.tx != nil {
+		_ = c.tx.Rollback()
+		c.tx = nil
+	}
+	return c.conn.Close()
+}
+
+// bindArgs converts driver arguments back into call arguments for the
+// underlying connection, and into the plain values reported to the logger.
+func bindArgs(named []driver.NamedValue) (args, values []any) {
+	args = make([]any, len(named))
+	values = make([]any, len(named))
+	for i, nv := range named {
+		values[i] = nv.Value
+		args[i] = nv.Value
+		if nv.Name != "" {
+			args[i] = sql.Named(nv.Name, nv.Value)
+		}
+	}
+	return args, values
+}
+
+// logTx ends the transaction open on conn.
+type logTx struct {
+	conn *logConn
+}
+
+func (t *logTx) Commit() error {
+	err := t.conn.tx.Commit()
+	t.conn.tx = nil
+	return err
+}
+
+func (t *logTx) Rollback() error {
+	err := t.conn.tx.Rollback()
+	t.conn.tx = nil
+	return err
+}
+
+// queryConn runs statements directly, without preparing them.
+type queryConn interface {
+	driver.QueryerContext
+	driver.ExecerContext
+}
+
+// connStmt runs an explicitly prepared statement as a plain query on conn.
+type connStmt struct {
+	conn  queryConn
+	query string
+}
+
+func (*connStmt) Close() error  { return nil }
+func (*connStmt) NumInput() int { return -1 }
+
+func (s *connStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *connStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *connStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *connStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+// logRows reads through rows, reporting the rows read to done exactly once
+// on close.
+type logRows struct {
+	rows *sql.Rows
+	cols []string
+	read int64
+	done func(read int64, err error)
+}
+
+func (r *logRows) Columns() []string {
+	return r.cols
+}
+
+func (r *logRows) Next(dest []driver.Value) error {
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	values := make([]any, len(dest))
+	ptrs := make([]any, len(dest))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := r.rows.Scan(ptrs...); err != nil {
+		return err
+	}
+	for i, v := range values {
+		dest[i] = v
+	}
+	r.read++
+	return nil
+}
+
+func (r *logRows) Close() error {
+	err := r.rows.Close()
+	if r.done != nil {
+		if iterErr := r.rows.Err(); iterErr != nil {
+			r.done(r.read, iterErr)
+		} else {
+			r.done(r.read, err)
+		}
+		r.done = nil
+	}
+	return err
+}
@@ -0,0 +1,202 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// queryLog records every statement reported to its logger.
+type queryLog struct {
+	entries []QueryInfo
+}
+
+func (l *queryLog) logger() Option {
+	return WithQueryLogger(func(_ context.Context, info QueryInfo) {
+		l.entries = append(l.entries, info)
+	})
+}
+
+func (l *queryLog) last(t *testing.T) QueryInfo {
+	t.Helper()
+	if len(l.entries) == 0 {
+		t.Fatal("no statement logged")
+	}
+	return l.entries[len(l.entries)-1]
+}
+
+func TestWithQueryLogger(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("query", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		var log queryLog
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, log.logger(), WithParamLogging())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		_, err = db.Get(ctx, "42")
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		got := log.last(t)
+		sent, _ := capture.Last()
+		if got.SQL != sent.Query || !strings.Contains(got.SQL, "SELECT") {
+			t.Errorf("logged %q, driver saw %q", got.SQL, sent.Query)
+		}
+		if !reflect.DeepEqual(got.Params, sent.Args) {
+			t.Errorf("logged args %v, driver saw %v", got.Params, sent.Args)
+		}
+		if got.Err != nil || got.Duration <= 0 || got.Rows != 0 {
+			t.Errorf("unexpected err %v, duration %v, or rows %d", got.Err, got.Duration, got.Rows)
+		}
+	})
+
+	t.Run("exec", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		var log queryLog
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, log.logger())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		if err := db.Delete(ctx, "7"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		got := log.last(t)
+		sent, _ := capture.Last()
+		if got.SQL != sent.Query || !strings.Contains(got.SQL, "DELETE") {
+			t.Errorf("logged %q, driver saw %q", got.SQL, sent.Query)
+		}
+		if got.Rows != 1 {
+			t.Errorf("expected 1 row affected, got %d", got.Rows)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		boom := errors.New("boom")
+		cfg.SetExecErr(boom)
+		var log queryLog
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, log.logger())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		if err := db.Delete(ctx, "7"); !errors.Is(err, boom) {
+			t.Fatalf("expected boom, got %v", err)
+		}
+		if got := log.last(t); !errors.Is(got.Err, boom) {
+			t.Errorf("expected logged error boom, got %v", got.Err)
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		var log queryLog
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, log.logger())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			return db.DeleteTx(ctx, tx, "7")
+		}, nil)
+		if err != nil {
+			t.Fatalf("WithTx failed: %v", err)
+		}
+		if got := log.last(t); !strings.Contains(got.SQL, "DELETE") {
+			t.Errorf("expected the tx DELETE to be logged, got %q", got.SQL)
+		}
+	})
+
+	t.Run("builder", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		var log queryLog
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, log.logger(), WithParamLogging())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		if _, err := db.Query().Where("age", ">=", "min").Exec(ctx, map[string]any{"min": 18}); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+		got := log.last(t)
+		if !reflect.DeepEqual(got.Params, []any{18}) {
+			t.Errorf("expected bound arg 18, got %v", got.Params)
+		}
+	})
+}
+
+func TestWithQueryLogger_Scrubbing(t *testing.T) {
+	ctx := context.Background()
+	mockDB, _, cfg := mockdb.NewWithConfig()
+	cfg.SetRows(userColumns, []driver.Value{int64(1), "a@example.com", "Alice", nil})
+	var log queryLog
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, log.logger())
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	byEmail := edamame.NewQueryStatement("by-email", "", edamame.QuerySpec{
+		Where: []edamame.ConditionSpec{
+			{Field: "email", Operator: "=", Param: "email"},
+			{Field: "age", Operator: ">=", Param: "age"},
+		},
+	})
+	rename := edamame.NewUpdateStatement("rename", "", edamame.UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+
+	calls := []struct {
+		name      string
+		call      func() error
+		statement string
+		params    []string
+	}{
+		{"get", func() error { _, err := db.Get(ctx, "1"); return err }, "", nil},
+		{"set", func() error {
+			return db.Set(ctx, "1", &TestDBUser{ID: 1, Email: "secret@example.com", Name: "Alice"})
+		}, "", nil},
+		{"exec query", func() error {
+			_, err := db.ExecQuery(ctx, byEmail, map[string]any{"email": "secret@example.com", "age": 18})
+			return err
+		}, "by-email", []string{"age", "email"}},
+		{"exec update tx", func() error {
+			return db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+				_, err := db.ExecUpdateTx(ctx, tx, rename, map[string]any{"id": 1, "name": "secret"})
+				return err
+			}, nil)
+		}, "rename", []string{"id", "name"}},
+		{"atomic get", func() error { _, err := db.Atomic().Get(ctx, "1"); return err }, "", nil},
+	}
+	for _, c := range calls {
+		t.Run(c.name, func(t *testing.T) {
+			log.entries = nil
+			if err := c.call(); err != nil {
+				t.Fatalf("call failed: %v", err)
+			}
+			if len(log.entries) != 1 {
+				t.Fatalf("expected one statement logged, got %d", len(log.entries))
+			}
+			got := log.entries[0]
+			if got.Params != nil {
+				t.Errorf("expected no values without WithParamLogging, got %v", got.Params)
+			}
+			if got.Statement != c.statement || !reflect.DeepEqual(got.ParamNames, c.params) {
+				t.Errorf("expected statement %q with params %v, got %q %v", c.statement, c.params, got.Statement, got.ParamNames)
+			}
+			if got.Rows != 1 {
+				t.Errorf("expected 1 row, got %d", got.Rows)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecRaw runs query with positional args and scans each row into T by db
+// tag, running AfterLoad on each record. It is an escape hatch for SQL the
+// statement and builder APIs cannot express, such as window functions or
+// dialect-specific syntax: the query is not registered, rendered, or
+// validated, and placeholders must use the driver's own bindvar style.
+// Columns without a matching field fail the scan. No rows yields an empty
+// result; a driver that reports sql.ErrNoRows instead gets ErrNotFound.
+// Writes made through ExecRaw do not invalidate WithQueryCache.
+//
+//	ranked, err := users.ExecRaw(ctx, `
+//	    SELECT id, email, name, age FROM (
+//	        SELECT *, ROW_NUMBER() OVER (PARTITION BY age ORDER BY id) AS rn FROM users
+//	    ) WHERE rn = 1`)
+func (d *Database[T]) ExecRaw(ctx context.Context, query string, args ...any) ([]*T, error) {
+	if d.readOnly {
+		return nil, d.wrapErr("exec_raw", "", ErrReadOnly)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	result, err := d.execRaw(callCtx, d.db, query, args)
+	if err != nil {
+		return nil, d.wrapErr("exec_raw", "", err)
+	}
+	return result, nil
+}
+
+// ExecRawTx is ExecRaw within a transaction.
+func (d *Database[T]) ExecRawTx(ctx context.Context, tx *sqlx.Tx, query string, args ...any) ([]*T, error) {
+	if d.readOnly {
+		return nil, d.wrapErr("exec_raw_tx", "", ErrReadOnly)
+	}
+	if tx == nil {
+		return nil, d.wrapErr("exec_raw_tx", "", ErrNilTransaction)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+	defer cancel()
+	result, err := d.execRaw(callCtx, tx, query, args)
+	if err != nil {
+		return nil, d.wrapErr("exec_raw_tx", "", err)
+	}
+	return result, nil
+}
+
+func (d *Database[T]) execRaw(ctx context.Context, execer sqlx.QueryerContext, query string, args []any) ([]*T, error) {
+	rows, err := execer.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, rawErr(err)
+	}
+	records, err := scanRows(ctx, rows, d.afterLoad)
+	if err != nil {
+		return nil, rawErr(err)
+	}
+	return records, nil
+}
+
+// rawErr maps sql.ErrNoRows to ErrNotFound.
+func rawErr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,167 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// failingAfterLoadDBUser is a Database-compatible model whose AfterLoad fails.
+type failingAfterLoadDBUser struct {
+	ID   int    `db:"id" constraints:"primarykey"`
+	Name string `db:"name"`
+}
+
+func (*failingAfterLoadDBUser) AfterLoad(_ context.Context) error { return errHook }
+
+const rankedQuery = `SELECT id, email, name, age FROM (SELECT *, ROW_NUMBER() OVER (PARTITION BY age ORDER BY id) AS rn FROM test_users) WHERE rn = ?`
+
+func rawUserRows(cfg *mockdb.Config) {
+	cfg.SetRows([]string{"id", "email", "name", "age"},
+		[]driver.Value{int64(1), "a@example.com", "A", int64(30)},
+		[]driver.Value{int64(2), "b@example.com", "B", nil},
+	)
+}
+
+func TestDatabase_ExecRaw(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("scans rows and runs AfterLoad", func(t *testing.T) {
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		rawUserRows(cfg)
+		db, err := NewDatabase[loadedDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		users, err := db.ExecRaw(ctx, rankedQuery, 1)
+		if err != nil {
+			t.Fatalf("ExecRaw failed: %v", err)
+		}
+		sent, _ := capture.Last()
+		if sent.Query != rankedQuery || len(sent.Args) != 1 || sent.Args[0] != int64(1) {
+			t.Errorf("unexpected query sent: %q %v", sent.Query, sent.Args)
+		}
+		if len(users) != 2 {
+			t.Fatalf("expected 2 users, got %d", len(users))
+		}
+		if users[0].Email != "a@example.com" || users[0].Age == nil || *users[0].Age != 30 || users[1].Age != nil {
+			t.Errorf("unexpected records: %+v %+v", users[0], users[1])
+		}
+		for i, u := range users {
+			if !u.loaded {
+				t.Errorf("AfterLoad not called on record %d", i)
+			}
+		}
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		mockDB, _ := mockdb.New()
+		db, err := NewDatabase[loadedDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		users, err := db.ExecRaw(ctx, "SELECT * FROM test_users WHERE 1 = 0")
+		if err != nil || len(users) != 0 {
+			t.Errorf("expected empty result, got %v, %v", users, err)
+		}
+	})
+
+	t.Run("AfterLoad error", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		cfg.SetRows([]string{"id", "name"}, []driver.Value{int64(1), "a"})
+		db, err := NewDatabase[failingAfterLoadDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		_, err = db.ExecRaw(ctx, "SELECT id, name FROM test_users")
+		if !errors.Is(err, errHook) {
+			t.Errorf("expected hook error, got %v", err)
+		}
+	})
+
+	t.Run("wraps errors", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		boom := errors.New("boom")
+		cfg.SetQueryErr(boom)
+		db, err := NewDatabase[loadedDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		_, err = db.ExecRaw(ctx, "SELECT 1")
+		var gerr *Error
+		if !errors.As(err, &gerr) || gerr.Op != "exec_raw" || gerr.Table != "test_users" || !errors.Is(err, boom) {
+			t.Errorf("expected wrapped exec_raw error, got %v", err)
+		}
+	})
+
+	t.Run("unmapped column", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		cfg.SetRows([]string{"id", "rn"}, []driver.Value{int64(1), int64(1)})
+		db, err := NewDatabase[loadedDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		if _, err := db.ExecRaw(ctx, "SELECT id, rn FROM test_users"); err == nil {
+			t.Error("expected a scan error for a column T does not map")
+		}
+	})
+}
+
+func TestDatabase_ExecRawTx(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture, cfg := mockdb.NewWithConfig()
+	rawUserRows(cfg)
+	db, err := NewDatabase[loadedDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	if _, err := db.ExecRawTx(ctx, nil, "SELECT 1"); !errors.Is(err, ErrNilTransaction) {
+		t.Errorf("expected ErrNilTransaction, got %v", err)
+	}
+
+	var users []*loadedDBUser
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		users, err = db.ExecRawTx(ctx, tx, rankedQuery, 1)
+		return err
+	}, nil)
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	if len(users) != 2 || !users[0].loaded {
+		t.Errorf("unexpected records: %v", users)
+	}
+	if last, _ := capture.Last(); last.Query != "COMMIT" {
+		t.Errorf("expected commit after the raw query, got %q", last.Query)
+	}
+}
+
+func TestDatabase_AtomicExecRaw(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture, cfg := mockdb.NewWithConfig()
+	rawUserRows(cfg)
+	db, err := NewDatabase[loadedDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	atoms, err := db.Atomic().ExecRaw(ctx, rankedQuery, 1)
+	if err != nil {
+		t.Fatalf("ExecRaw failed: %v", err)
+	}
+	if sent, _ := capture.Last(); sent.Query != rankedQuery {
+		t.Errorf("unexpected query sent: %q", sent.Query)
+	}
+	if len(atoms) != 2 {
+		t.Fatalf("expected 2 atoms, got %d", len(atoms))
+	}
+	if atoms[0].Strings["Email"] != "a@example.com" || atoms[1].Strings["Name"] != "B" {
+		t.Errorf("unexpected atoms: %v %v", atoms[0].Strings, atoms[1].Strings)
+	}
+}
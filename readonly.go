@@ -0,0 +1,47 @@
+package grub
+
+import (
+	"sync"
+
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/soy"
+)
+
+// ReadOnly returns a view of this database for code that must never write.
+// Set, SetBatch, SetBatchPartial, SetIfChanged, Delete, Create,
+// InsertReturning, ExecUpdate, ExecRaw, their Tx variants, EnsureHistory,
+// and EnsureOutbox fail with an error matching ErrReadOnly and naming the
+// operation, before hooks run or a statement is sent. Statements built with
+// Insert, InsertFull, Modify, and Remove fail the same way on Exec and
+// ExecTx. Reads behave as on d and share its connection, hooks, and options,
+// as does the view's Atomic, whose writes are rejected too.
+//
+// The Executor and DB escape hatches are not guarded.
+func (d *Database[T]) ReadOnly() *Database[T] {
+	view := *d
+	view.readOnly = true
+	view.atomic = nil
+	view.atomicOnce = new(sync.Once)
+	writes, err := soy.New[T](d.db, d.tableName, readOnlyRenderer{d.renderer})
+	if err != nil {
+		// Unreachable: NewDatabase built an instance from the same inputs.
+		panic("grub: read-only builders: " + err.Error())
+	}
+	view.writes = writes
+	return &view
+}
+
+// readOnlyRenderer renders no statements, so builders of a ReadOnly
+// Database fail with ErrReadOnly before reaching the pool or the
+// transaction they are given.
+type readOnlyRenderer struct {
+	astql.Renderer
+}
+
+func (readOnlyRenderer) Render(*astql.AST) (*astql.QueryResult, error) {
+	return nil, ErrReadOnly
+}
+
+func (readOnlyRenderer) RenderCompound(*astql.CompoundQuery) (*astql.QueryResult, error) {
+	return nil, ErrReadOnly
+}
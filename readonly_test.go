@@ -0,0 +1,266 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// Untouchable providers embed a nil interface, so any call reaching them
+// panics and fails the test.
+type (
+	untouchableStore  struct{ StoreProvider }
+	untouchableBucket struct{ BucketProvider }
+	untouchableVector struct{ VectorProvider }
+)
+
+// assertReadOnly checks that err matches ErrReadOnly and names op.
+func assertReadOnly(t *testing.T, op string, err error) {
+	t.Helper()
+	if !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("%s: expected ErrReadOnly, got %v", op, err)
+	}
+	var gerr *shared.Error
+	if errors.As(err, &gerr) && gerr.Op != op {
+		t.Errorf("expected the error to name %q, got %q", op, gerr.Op)
+	}
+}
+
+func TestStore_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("writes are rejected", func(t *testing.T) {
+		view := NewStore[hookedRecord](untouchableStore{}).ReadOnly()
+		rec := &hookedRecord{ID: 1}
+		writes := map[string]func() error{
+			"set":           func() error { return view.Set(ctx, "k", rec, 0) },
+			"set_batch":     func() error { return view.SetBatch(ctx, map[string]*hookedRecord{"k": rec}, 0) },
+			"update":        func() error { return view.Update(ctx, "k", func(*hookedRecord) error { return nil }, 0) },
+			"delete":        func() error { return view.Delete(ctx, "k") },
+			"delete_prefix": func() error { _, err := view.DeletePrefix(ctx, "p:"); return err },
+			"atomic set":    func() error { return view.Atomic().Set(ctx, "k", nil, 0) },
+			"atomic delete": func() error { return view.Atomic().Delete(ctx, "k") },
+		}
+		for name, write := range writes {
+			t.Run(name, func(t *testing.T) {
+				if err := write(); !errors.Is(err, ErrReadOnly) {
+					t.Fatalf("expected ErrReadOnly, got %v", err)
+				}
+			})
+		}
+		assertReadOnly(t, "set", view.Set(ctx, "k", rec, 0))
+		if rec.beforeSaveCalled {
+			t.Error("expected no hooks to run")
+		}
+	})
+
+	t.Run("reads share the store", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		store := NewStore[testRecord](provider)
+		if err := store.Set(ctx, "k", &testRecord{ID: 1, Name: "a"}, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		view := store.ReadOnly()
+		got, err := view.Get(ctx, "k")
+		if err != nil || got.Name != "a" {
+			t.Fatalf("expected the stored record, got %+v, %v", got, err)
+		}
+		if _, err := view.Atomic().Get(ctx, "k"); err != nil {
+			t.Errorf("atomic Get failed: %v", err)
+		}
+		if err := store.Delete(ctx, "k"); err != nil {
+			t.Errorf("expected the original to stay writable, got %v", err)
+		}
+	})
+}
+
+func TestBucket_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("writes are rejected", func(t *testing.T) {
+		view := NewBucket[testPayload](untouchableBucket{}).ReadOnly()
+		obj := &Object[testPayload]{Key: "k"}
+		writes := map[string]func() error{
+			"put":              func() error { return view.Put(ctx, obj) },
+			"put_info":         func() error { _, err := view.PutInfo(ctx, obj); return err },
+			"delete":           func() error { return view.Delete(ctx, "k") },
+			"delete_prefix":    func() error { _, err := view.DeletePrefix(ctx, "p/"); return err },
+			"create_multipart": func() error { _, err := view.NewMultipartUpload(ctx, "k", ""); return err },
+			"resume_multipart": func() error { _, err := view.ResumeMultipartUpload("k", "id", nil); return err },
+			"atomic put":       func() error { return view.Atomic().Put(ctx, "k", &shared.AtomicObject{}) },
+			"atomic delete":    func() error { return view.Atomic().Delete(ctx, "k") },
+		}
+		for name, write := range writes {
+			t.Run(name, func(t *testing.T) {
+				if err := write(); !errors.Is(err, ErrReadOnly) {
+					t.Fatalf("expected ErrReadOnly, got %v", err)
+				}
+			})
+		}
+		assertReadOnly(t, "create_multipart", writes["create_multipart"]())
+	})
+
+	t.Run("reads share the bucket", func(t *testing.T) {
+		bucket := NewBucket[testPayload](newMockBucketProvider())
+		if err := bucket.Put(ctx, &Object[testPayload]{Key: "k", Data: testPayload{Field1: "a"}}); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		got, err := bucket.ReadOnly().Get(ctx, "k")
+		if err != nil || got.Data.Field1 != "a" {
+			t.Fatalf("expected the stored object, got %+v, %v", got, err)
+		}
+	})
+}
+
+func TestIndex_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	t.Run("writes are rejected", func(t *testing.T) {
+		view := NewIndex[testMetadata](untouchableVector{}).ReadOnly()
+		embed := func(context.Context, Vector[testMetadata]) ([]float32, error) { return nil, nil }
+		writes := map[string]func() error{
+			"upsert": func() error { return view.Upsert(ctx, id, []float32{1}, &testMetadata{}) },
+			"upsert_batch": func() error {
+				return view.UpsertBatch(ctx, []Vector[testMetadata]{{ID: id, Vector: []float32{1}}})
+			},
+			"delete":        func() error { return view.Delete(ctx, id) },
+			"delete_batch":  func() error { return view.DeleteBatch(ctx, []uuid.UUID{id}) },
+			"reindex":       func() error { return view.Reindex(ctx, embed, 10, nil) },
+			"atomic upsert": func() error { return view.Atomic().Upsert(ctx, id, []float32{1}, nil) },
+			"atomic delete": func() error { return view.Atomic().Delete(ctx, id) },
+		}
+		for name, write := range writes {
+			t.Run(name, func(t *testing.T) {
+				if err := write(); !errors.Is(err, ErrReadOnly) {
+					t.Fatalf("expected ErrReadOnly, got %v", err)
+				}
+			})
+		}
+		assertReadOnly(t, "reindex", writes["reindex"]())
+	})
+
+	t.Run("reads share the index", func(t *testing.T) {
+		index := NewIndex[testMetadata](newMockVectorProvider())
+		if err := index.Upsert(ctx, id, []float32{1, 0}, &testMetadata{Category: "a"}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		got, err := index.ReadOnly().Get(ctx, id)
+		if err != nil || got.Metadata.Category != "a" {
+			t.Fatalf("expected the stored vector, got %+v, %v", got, err)
+		}
+	})
+}
+
+func TestDatabase_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithHistory(HistoryShadow),
+		WithOutbox[TestDBUser]("outbox", nil))
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	rename := edamame.NewUpdateStatement("rename", "", edamame.UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	if err := db.RegisterUpdate(rename); err != nil {
+		t.Fatalf("RegisterUpdate failed: %v", err)
+	}
+	tx, err := mockDB.BeginTxx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTxx failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	view := db.ReadOnly()
+	user := &TestDBUser{ID: 1, Email: "a@example.com", Name: "Alice"}
+	params := map[string]any{"id": 1, "name": "Bob"}
+	writes := map[string]func() error{
+		"set":          func() error { return view.Set(ctx, "1", user) },
+		"set_tx":       func() error { return view.SetTx(ctx, tx, "1", user) },
+		"set_batch":    func() error { return view.SetBatch(ctx, map[string]*TestDBUser{"1": user}) },
+		"set_batch_tx": func() error { return view.SetBatchTx(ctx, tx, map[string]*TestDBUser{"1": user}) },
+		"set_batch_partial": func() error {
+			_, err := view.SetBatchPartial(ctx, map[string]*TestDBUser{"1": user})
+			return err
+		},
+		"set_if_changed":      func() error { _, err := view.SetIfChanged(ctx, "1", user); return err },
+		"set_if_changed_tx":   func() error { _, err := view.SetIfChangedTx(ctx, tx, "1", user); return err },
+		"patch":               func() error { _, err := view.Patch(ctx, "1", params); return err },
+		"patch_tx":            func() error { _, err := view.PatchTx(ctx, tx, "1", params); return err },
+		"patch_struct":        func() error { _, err := view.PatchStruct(ctx, "1", user); return err },
+		"patch_struct_tx":     func() error { _, err := view.PatchStructTx(ctx, tx, "1", user); return err },
+		"delete":              func() error { return view.Delete(ctx, "1") },
+		"delete_tx":           func() error { return view.DeleteTx(ctx, tx, "1") },
+		"create":              func() error { _, _, err := view.Create(ctx, user); return err },
+		"create_tx":           func() error { _, _, err := view.CreateTx(ctx, tx, user); return err },
+		"insert_returning":    func() error { _, err := view.InsertReturning(ctx, user); return err },
+		"insert_returning_tx": func() error { _, err := view.InsertReturningTx(ctx, tx, user); return err },
+		"exec_update":         func() error { _, err := view.ExecUpdate(ctx, rename, params); return err },
+		"exec_update_tx":      func() error { _, err := view.ExecUpdateTx(ctx, tx, rename, params); return err },
+		"exec_named":          func() error { _, err := view.ExecNamed(ctx, "rename", params); return err },
+		"ensure_history":      func() error { return view.EnsureHistory(ctx) },
+		"ensure_outbox":       func() error { return view.EnsureOutbox(ctx) },
+		"insert builder":      func() error { _, err := view.Insert().Exec(ctx, user); return err },
+		"insert full builder": func() error { _, err := view.InsertFull().Exec(ctx, user); return err },
+		"modify builder": func() error {
+			_, err := view.Modify().Set("name", "name").Where("id", "=", "id").Exec(ctx, params)
+			return err
+		},
+		"remove builder":    func() error { _, err := view.Remove().Where("id", "=", "id").Exec(ctx, params); return err },
+		"insert builder tx": func() error { _, err := view.Insert().ExecTx(ctx, tx, user); return err },
+		"modify builder tx": func() error {
+			_, err := view.Modify().Set("name", "name").Where("id", "=", "id").ExecTx(ctx, tx, params)
+			return err
+		},
+		"remove builder tx": func() error {
+			_, err := view.Remove().Where("id", "=", "id").ExecTx(ctx, tx, params)
+			return err
+		},
+		"exec raw":             func() error { _, err := view.ExecRaw(ctx, "DELETE FROM test_users"); return err },
+		"exec raw tx":          func() error { _, err := view.ExecRawTx(ctx, tx, "DELETE FROM test_users"); return err },
+		"atomic set":           func() error { return view.Atomic().Set(ctx, "1", nil) },
+		"atomic delete":        func() error { return view.Atomic().Delete(ctx, "1") },
+		"set with ctx tx":      func() error { return view.Set(ContextWithTx(ctx, tx), "1", user) },
+		"set batch with no tx": func() error { return view.SetBatchTx(ctx, nil, nil) },
+	}
+	before := len(capture.Queries)
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			if err := write(); !errors.Is(err, ErrReadOnly) {
+				t.Fatalf("expected ErrReadOnly, got %v", err)
+			}
+		})
+	}
+	if n := len(capture.Queries) - before; n != 0 {
+		t.Errorf("expected no statements, got %d: %v", n, capture.Queries[before:])
+	}
+	assertReadOnly(t, "set_if_changed_tx", writes["set_if_changed_tx"]())
+
+	t.Run("reads and the original are unaffected", func(t *testing.T) {
+		if _, err := view.Exists(ctx, "1"); err != nil {
+			t.Errorf("Exists failed: %v", err)
+		}
+		if got := len(view.Statements()); got != len(db.Statements()) {
+			t.Errorf("expected the view to share statements, got %d and %d", got, len(db.Statements()))
+		}
+		if err := db.Delete(ctx, "1"); err != nil {
+			t.Errorf("expected the original to stay writable, got %v", err)
+		}
+	})
+}
+
+func TestStore_ReadOnlyKeepsOptions(t *testing.T) {
+	view := NewStore[testRecord](&blockingStoreProvider{mockStoreProvider: newMockStoreProvider()},
+		WithDefaultTimeout(10*time.Millisecond)).ReadOnly()
+	if _, err := view.Get(context.Background(), "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the source's timeout, got %v", err)
+	}
+}
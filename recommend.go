@@ -0,0 +1,103 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/vecna"
+)
+
+// Recommend returns up to k vectors most similar to the stored vector at id,
+// closest first. The seed itself never appears in the results. Uses the
+// provider's by-ID search through VectorRecommender when available;
+// otherwise fetches the seed vector and runs Query with it. Returns
+// ErrNotFound if id does not exist.
+func (i *Index[T]) Recommend(ctx context.Context, id uuid.UUID, k int, filter *vecna.Filter) ([]*Vector[T], error) {
+	return i.recommend(ctx, "recommend", []uuid.UUID{id}, k, filter)
+}
+
+// RecommendMulti returns up to k vectors most similar to the average of the
+// stored vectors at ids, closest first. No seed appears in the results.
+// Returns ErrNotFound if any seed does not exist.
+func (i *Index[T]) RecommendMulti(ctx context.Context, ids []uuid.UUID, k int, filter *vecna.Filter) ([]*Vector[T], error) {
+	return i.recommend(ctx, "recommend_multi", ids, k, filter)
+}
+
+func (i *Index[T]) recommend(ctx context.Context, op string, ids []uuid.UUID, k int, filter *vecna.Filter) ([]*Vector[T], error) {
+	if len(ids) == 0 {
+		return nil, i.wrapErr(op, "", fmt.Errorf("%w: no seed IDs", ErrInvalidKey))
+	}
+	start := i.emitSearchStarted(ctx, op, k)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	var results []VectorResult
+	var err error
+	if recommender, ok := i.searcher.(VectorRecommender); ok {
+		results, err = recommender.Recommend(callCtx, ids, k, filter)
+		if errors.Is(err, ErrUnsupported) {
+			results, err = i.recommendByQuery(callCtx, ids, k, filter)
+		}
+	} else {
+		results, err = i.recommendByQuery(callCtx, ids, k, filter)
+	}
+	if err != nil {
+		i.emitSearchFailed(ctx, op, k, start, err)
+		return nil, i.wrapErr(op, seedKey(ids), classifyDimension(err))
+	}
+	results = excludeSeeds(results, ids, k)
+	i.emitSearchCompleted(ctx, op, k, start, len(results))
+	return i.decodeResults(ctx, results)
+}
+
+// recommendByQuery fetches the seed vectors and queries with their average,
+// asking for enough extra results to cover the seeds being dropped.
+func (i *Index[T]) recommendByQuery(ctx context.Context, ids []uuid.UUID, k int, filter *vecna.Filter) ([]VectorResult, error) {
+	var sum []float32
+	for _, id := range ids {
+		vector, _, err := i.provider.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if sum == nil {
+			sum = make([]float32, len(vector))
+		} else if len(vector) != len(sum) {
+			return nil, fmt.Errorf("%w: seed %s has dimension %d, want %d", ErrDimensionMismatch, id, len(vector), len(sum))
+		}
+		for n, v := range vector {
+			sum[n] += v
+		}
+	}
+	for n := range sum {
+		sum[n] /= float32(len(ids))
+	}
+	return i.searcher.Query(ctx, sum, k+len(ids), filter)
+}
+
+// excludeSeeds drops any seed from results and truncates them to k.
+func excludeSeeds(results []VectorResult, ids []uuid.UUID, k int) []VectorResult {
+	seeds := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		seeds[id] = struct{}{}
+	}
+	out := results[:0]
+	for _, r := range results {
+		if _, ok := seeds[r.ID]; ok {
+			continue
+		}
+		out = append(out, r)
+	}
+	if k > 0 && len(out) > k {
+		out = out[:k]
+	}
+	return out
+}
+
+// seedKey renders the seed IDs for error context.
+func seedKey(ids []uuid.UUID) string {
+	if len(ids) == 1 {
+		return ids[0].String()
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/vecna"
+)
+
+// recommendingVectorProvider is a mockVectorProvider that also implements
+// VectorRecommender, handling a single seed natively.
+type recommendingVectorProvider struct {
+	*mockVectorProvider
+	calls int
+}
+
+func (p *recommendingVectorProvider) Recommend(ctx context.Context, ids []uuid.UUID, k int, filter *vecna.Filter) ([]VectorResult, error) {
+	p.calls++
+	if len(ids) != 1 {
+		return nil, ErrUnsupported
+	}
+	entry, ok := p.vectors[ids[0]]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	// includes the seed, as a nearest-object search would
+	return p.Query(ctx, entry.vector, k+1, filter)
+}
+
+func TestIndex_Recommend(t *testing.T) {
+	ctx := context.Background()
+
+	// five vectors along a line; each one's neighbours are the adjacent points
+	seed := func(m *mockVectorProvider) []uuid.UUID {
+		ids := make([]uuid.UUID, 5)
+		for n := range ids {
+			ids[n] = uuid.New()
+			m.vectors[ids[n]] = vectorEntry{
+				vector:   []float32{float32(n), 0},
+				metadata: []byte(`{"category": "line"}`),
+			}
+		}
+		return ids
+	}
+
+	fallback := newMockVectorProvider()
+	native := &recommendingVectorProvider{mockVectorProvider: newMockVectorProvider()}
+	for _, tc := range []struct {
+		name     string
+		mock     *mockVectorProvider
+		provider VectorProvider
+	}{
+		{"fallback", fallback, fallback},
+		{"recommender", native.mockVectorProvider, native},
+	} {
+		ids := seed(tc.mock)
+		index := NewIndex[testMetadata](tc.provider)
+
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := index.Recommend(ctx, ids[0], 3, nil)
+			if err != nil {
+				t.Fatalf("Recommend failed: %v", err)
+			}
+			if len(results) != 3 {
+				t.Fatalf("expected 3 results, got %d", len(results))
+			}
+			for n, r := range results {
+				if r.ID != ids[n+1] {
+					t.Errorf("result %d: expected %s, got %s", n, ids[n+1], r.ID)
+				}
+			}
+
+			// the average of points 1 and 3 is point 2
+			results, err = index.RecommendMulti(ctx, []uuid.UUID{ids[1], ids[3]}, 3, nil)
+			if err != nil {
+				t.Fatalf("RecommendMulti failed: %v", err)
+			}
+			if len(results) != 3 || results[0].ID != ids[2] {
+				t.Fatalf("expected point 2 first of 3 results, got %v", results)
+			}
+			for _, r := range results {
+				if r.ID == ids[1] || r.ID == ids[3] {
+					t.Errorf("seed %s returned in its own results", r.ID)
+				}
+			}
+
+			if _, err := index.Recommend(ctx, uuid.New(), 3, nil); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound for a missing seed, got %v", err)
+			}
+			if _, err := index.RecommendMulti(ctx, []uuid.UUID{ids[0], uuid.New()}, 3, nil); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound for a missing seed, got %v", err)
+			}
+			if _, err := index.RecommendMulti(ctx, nil, 3, nil); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("expected ErrInvalidKey for no seeds, got %v", err)
+			}
+		})
+	}
+	if native.calls == 0 {
+		t.Error("expected the VectorRecommender implementation to be used")
+	}
+}
+
+func TestIndex_Recommend_DimensionMismatch(t *testing.T) {
+	provider := newMockVectorProvider()
+	index := NewIndex[testMetadata](provider)
+	a, b := uuid.New(), uuid.New()
+	provider.vectors[a] = vectorEntry{vector: []float32{1, 0}}
+	provider.vectors[b] = vectorEntry{vector: []float32{1, 0, 0}}
+
+	_, err := index.RecommendMulti(context.Background(), []uuid.UUID{a, b}, 1, nil)
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("expected ErrDimensionMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package grub
+
+import (
+	"reflect"
+
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// redaction zeroes the fields of T tagged grub:"redact" on read.
+// A nil *redaction is valid and does nothing.
+type redaction struct {
+	index      [][]int  // field indexes, for typed values
+	names      []string // Go field names, as keyed in atoms
+	allowEmpty []bool   // fields tagged grub:"redact,allowempty", by index
+}
+
+// newRedaction locates T's redacted fields. Only top-level fields (including
+// those promoted from non-pointer embedded structs) are considered. Returns
+// nil unless WithRedaction is set and T has at least one such field.
+func newRedaction[T any](o options) *redaction {
+	if !o.redact {
+		return nil
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	r := &redaction{}
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous || throughPointer(t, f.Index) || !shared.HasGrubTag(f, "redact") {
+			continue
+		}
+		r.index = append(r.index, f.Index)
+		r.names = append(r.names, f.Name)
+		r.allowEmpty = append(r.allowEmpty, shared.HasGrubTag(f, "allowempty"))
+	}
+	if len(r.index) == 0 {
+		return nil
+	}
+	return r
+}
+
+// fields returns the redacted field names, or nil.
+func (r *redaction) fields() []string {
+	if r == nil {
+		return nil
+	}
+	return r.names
+}
+
+// apply zeroes the redacted fields of value.
+func (r *redaction) apply(value any) {
+	if r == nil {
+		return
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return
+	}
+	v = v.Elem()
+	for _, index := range r.index {
+		v.FieldByIndex(index).SetZero()
+	}
+}
+
+// restore copies each redacted field of src into value where value's is
+// still zero, so a record edited from a redacted read keeps the stored
+// secrets it was never shown.
+func (r *redaction) restore(value, src any) {
+	if r == nil {
+		return
+	}
+	v, sv := reflect.ValueOf(value), reflect.ValueOf(src)
+	if v.Kind() != reflect.Pointer || v.IsNil() || sv.Kind() != reflect.Pointer || sv.IsNil() {
+		return
+	}
+	v, sv = v.Elem(), sv.Elem()
+	for _, index := range r.index {
+		if f := v.FieldByIndex(index); f.IsZero() {
+			f.Set(sv.FieldByIndex(index))
+		}
+	}
+}
+
+// check returns ErrRedacted if any redacted field of value is zero. Saves
+// through a redacting facade call it after BeforeSave, so a record read with
+// its secrets zeroed cannot be written back over the stored ones. Fields
+// tagged grub:"redact,allowempty" may be saved zero.
+func (r *redaction) check(value any) error {
+	if r == nil {
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil
+	}
+	v = v.Elem()
+	for n, index := range r.index {
+		if !r.allowEmpty[n] && v.FieldByIndex(index).IsZero() {
+			return ErrRedacted
+		}
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+type secretRecord struct {
+	Name     string `json:"name"`
+	Password string `json:"password" grub:"redact"`
+	Token    string `json:"token" grub:"omitempty,redact"`
+	Key      []byte `json:"key,omitempty" grub:"redact"`
+
+	tokenAtLoad string
+}
+
+func (s *secretRecord) AfterLoad(_ context.Context) error {
+	s.tokenAtLoad = s.Token
+	return nil
+}
+
+func checkRedacted(t *testing.T, path string, rec *secretRecord) {
+	t.Helper()
+	if rec.Password != "" || rec.Token != "" || rec.Key != nil {
+		t.Errorf("%s: fields not redacted: %q / %q / %q", path, rec.Password, rec.Token, rec.Key)
+	}
+	if rec.tokenAtLoad != "" {
+		t.Errorf("%s: AfterLoad saw unredacted token %q", path, rec.tokenAtLoad)
+	}
+	if rec.Name != "alice" {
+		t.Errorf("%s: unredacted field lost: %q", path, rec.Name)
+	}
+}
+
+func newSecret() *secretRecord {
+	return &secretRecord{Name: "alice", Password: "hunter2", Token: "t0k", Key: []byte("k3y")}
+}
+
+func TestNewRedaction(t *testing.T) {
+	if r := newRedaction[secretRecord](options{}); r != nil {
+		t.Error("expected nil redaction without WithRedaction")
+	}
+	if r := newRedaction[stampedRecord](options{redact: true}); r != nil {
+		t.Error("expected nil redaction for a type without redacted fields")
+	}
+	r := newRedaction[secretRecord](options{redact: true})
+	if got := r.fields(); !reflect.DeepEqual(got, []string{"Password", "Token", "Key"}) {
+		t.Errorf("fields: got %v", got)
+	}
+}
+
+func TestRedaction_Store(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockStoreProvider()
+	plain := NewStore[secretRecord](provider)
+	store := NewStore[secretRecord](provider, WithRedaction())
+
+	if err := plain.Set(ctx, "a", newSecret(), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := plain.Set(ctx, "b", newSecret(), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		rec, err := store.Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		checkRedacted(t, "Get", rec)
+	})
+
+	t.Run("GetBatch", func(t *testing.T) {
+		recs, err := store.GetBatch(ctx, []string{"a", "b"})
+		if err != nil {
+			t.Fatalf("GetBatch failed: %v", err)
+		}
+		checkRedacted(t, "GetBatch", recs["a"])
+		checkRedacted(t, "GetBatch", recs["b"])
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		it, err := store.Scan(ctx)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		n := 0
+		for it.Next() {
+			checkRedacted(t, "Scan", it.Value())
+			n++
+		}
+		if it.Err() != nil || n != 2 {
+			t.Errorf("Scan: got %d records, err %v", n, it.Err())
+		}
+	})
+
+	t.Run("Atomic", func(t *testing.T) {
+		a, err := store.Atomic().Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("Atomic Get failed: %v", err)
+		}
+		if a.Strings["Password"] != "" || a.Strings["Token"] != "" || a.Strings["Name"] != "alice" {
+			t.Errorf("atomic view not masked: %v", a.Strings)
+		}
+	})
+
+	t.Run("unredacted view", func(t *testing.T) {
+		rec, err := plain.Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if rec.Password != "hunter2" || rec.Token != "t0k" {
+			t.Errorf("plain view redacted: %+v", rec)
+		}
+	})
+
+	t.Run("write back rejected", func(t *testing.T) {
+		before := string(provider.data["a"])
+		rec, err := store.Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if err := store.Set(ctx, "a", rec, 0); !errors.Is(err, ErrRedacted) {
+			t.Errorf("Set: expected ErrRedacted, got %v", err)
+		}
+		if err := store.SetBatch(ctx, map[string]*secretRecord{"a": rec}, 0); !errors.Is(err, ErrRedacted) {
+			t.Errorf("SetBatch: expected ErrRedacted, got %v", err)
+		}
+		a, err := store.Atomic().Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("Atomic Get failed: %v", err)
+		}
+		if err := store.Atomic().Set(ctx, "a", a, 0); !errors.Is(err, ErrRedacted) {
+			t.Errorf("Atomic Set: expected ErrRedacted, got %v", err)
+		}
+		if got := string(provider.data["a"]); got != before {
+			t.Errorf("stored value changed: %s", got)
+		}
+	})
+
+	t.Run("complete write accepted", func(t *testing.T) {
+		if err := store.Set(ctx, "c", newSecret(), 0); err != nil {
+			t.Errorf("Set failed: %v", err)
+		}
+	})
+}
+
+func TestRedaction_Bucket(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockBucketProvider()
+	plain := NewBucket[secretRecord](provider)
+	bucket := NewBucket[secretRecord](provider, WithRedaction())
+
+	if err := plain.Put(ctx, &Object[secretRecord]{Key: "a", ContentType: "application/json", Data: *newSecret()}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	obj, err := bucket.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	checkRedacted(t, "Get", &obj.Data)
+
+	a, err := bucket.Atomic().Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("Atomic Get failed: %v", err)
+	}
+	if a.Data.Strings["Password"] != "" || a.Data.Strings["Name"] != "alice" {
+		t.Errorf("atomic view not masked: %v", a.Data.Strings)
+	}
+
+	before := string(provider.data["a"])
+	if err := bucket.Put(ctx, obj); !errors.Is(err, ErrRedacted) {
+		t.Errorf("Put: expected ErrRedacted, got %v", err)
+	}
+	if got := string(provider.data["a"]); got != before {
+		t.Errorf("stored value changed: %s", got)
+	}
+
+	unredacted, err := plain.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if unredacted.Data.Password != "hunter2" {
+		t.Errorf("plain view redacted: %+v", unredacted.Data)
+	}
+}
+
+func TestRedaction_Index(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockVectorProvider()
+	plain := NewIndex[secretRecord](provider)
+	index := NewIndex[secretRecord](provider, WithRedaction())
+
+	id := uuid.New()
+	if err := plain.Upsert(ctx, id, []float32{1, 0}, newSecret()); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	v, err := index.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	checkRedacted(t, "Get", &v.Metadata)
+
+	results, err := index.Search(ctx, []float32{1, 0}, 1, nil)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Search: got %d results, err %v", len(results), err)
+	}
+	checkRedacted(t, "Search", &results[0].Metadata)
+
+	results, err = index.Filter(ctx, nil, 0)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Filter: got %d results, err %v", len(results), err)
+	}
+	checkRedacted(t, "Filter", &results[0].Metadata)
+
+	a, err := index.Atomic().Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Atomic Get failed: %v", err)
+	}
+	if a.Metadata.Strings["Token"] != "" || a.Metadata.Strings["Name"] != "alice" {
+		t.Errorf("atomic view not masked: %v", a.Metadata.Strings)
+	}
+
+	before := string(provider.vectors[id].metadata)
+	if err := index.Upsert(ctx, id, []float32{1, 0}, &v.Metadata); !errors.Is(err, ErrRedacted) {
+		t.Errorf("Upsert: expected ErrRedacted, got %v", err)
+	}
+	if err := index.UpsertBatch(ctx, []Vector[secretRecord]{*v}); !errors.Is(err, ErrRedacted) {
+		t.Errorf("UpsertBatch: expected ErrRedacted, got %v", err)
+	}
+	if got := string(provider.vectors[id].metadata); got != before {
+		t.Errorf("stored metadata changed: %s", got)
+	}
+
+	unredacted, err := plain.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if unredacted.Metadata.Token != "t0k" {
+		t.Errorf("plain view redacted: %+v", unredacted.Metadata)
+	}
+}
+
+type secretDBRecord struct {
+	ID       int    `db:"id" constraints:"primarykey"`
+	Password string `db:"password" grub:"redact"`
+	Key      []byte `db:"key" grub:"redact"`
+	Hint     string `db:"hint" grub:"redact,allowempty"`
+}
+
+func TestRedaction_Database(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[secretDBRecord](mockDB, "records", testDBRenderer, WithRedaction())
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	err = db.Set(ctx, "1", &secretDBRecord{ID: 1})
+	if !errors.Is(err, ErrRedacted) {
+		t.Errorf("Set: expected ErrRedacted, got %v", err)
+	}
+	if _, ok := capture.Last(); ok {
+		t.Error("expected no query for a rejected write")
+	}
+
+	err = db.Set(ctx, "1", &secretDBRecord{ID: 1, Key: []byte("k3y")})
+	if !errors.Is(err, ErrRedacted) {
+		t.Errorf("Set: expected ErrRedacted for an empty password, got %v", err)
+	}
+
+	// Hint is tagged allowempty. The mock returns no rows, so the write
+	// itself fails; only the guard matters here.
+	err = db.Set(ctx, "1", &secretDBRecord{ID: 1, Password: "hunter2", Key: []byte("k3y")})
+	if errors.Is(err, ErrRedacted) {
+		t.Errorf("Set: expected an empty allowempty field to be accepted, got %v", err)
+	}
+}
@@ -4,6 +4,9 @@ package redis
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -49,6 +52,83 @@ func (p *Provider) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// globEscaper escapes the SCAN MATCH metacharacters in a literal prefix.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// DeletePrefix removes every key under prefix, walking the keyspace with
+// SCAN and unlinking each batch in one UNLINK, which frees memory off the
+// main thread. Cancellation is checked between batches. As with SCAN, keys
+// written under prefix during the walk may survive it.
+func (p *Provider) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	pattern := globEscaper.Replace(prefix) + "*"
+	var deleted int64
+	var cursor uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		batch, next, err := p.client.Scan(ctx, cursor, pattern, 1000).Result()
+		if err != nil {
+			return deleted, err
+		}
+		if len(batch) > 0 {
+			n, err := p.client.Unlink(ctx, batch...).Result()
+			if err != nil {
+				return deleted, err
+			}
+			deleted += n
+		}
+		if next == 0 {
+			return deleted, nil
+		}
+		cursor = next
+	}
+}
+
+// compareAndSwap sets KEYS[1] to ARGV[2] only if it currently holds ARGV[1],
+// with a TTL of ARGV[3] milliseconds when positive. It returns 1 on a swap.
+var compareAndSwap = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+else
+	redis.call('SET', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// CompareAndSwap stores value at key only if the stored value equals old,
+// using a Lua script so the compare and the set run atomically on the
+// server.
+func (p *Provider) CompareAndSwap(ctx context.Context, key string, old, value []byte, ttl time.Duration) (bool, error) {
+	n, err := compareAndSwap.Run(ctx, p.client, []string{key}, old, value, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// TTL returns the time left before key expires, or 0 if it does not
+// expire. A key about to expire reports a millisecond rather than 0, which
+// would read as no expiry.
+func (p *Provider) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := p.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case ttl == -2:
+		return 0, grub.ErrNotFound
+	case ttl < 0:
+		return 0, nil
+	case ttl == 0:
+		return time.Millisecond, nil
+	}
+	return ttl, nil
+}
+
 // Exists checks whether a key exists.
 func (p *Provider) Exists(ctx context.Context, key string) (bool, error) {
 	result, err := p.client.Exists(ctx, key).Result()
@@ -65,6 +145,9 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]string
 	pattern := prefix + "*"
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		var batch []string
 		var err error
 		batch, cursor, err = p.client.Scan(ctx, cursor, pattern, 100).Result()
@@ -83,6 +166,65 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]string
 	return keys, nil
 }
 
+// ListMatch returns up to limit keys matching the glob pattern using SCAN
+// MATCH. The returned cursor records the SCAN cursor and how many keys of
+// its batch were already returned, so a page may end mid-batch. As with
+// SCAN, a key may be returned more than once if the keyspace is resized
+// between pages.
+func (p *Provider) ListMatch(ctx context.Context, pattern, cursor string, limit int) ([]string, string, error) {
+	scan, skip, err := parseCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	var keys []string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+		batch, next, err := p.client.Scan(ctx, scan, pattern, 100).Result()
+		if err != nil {
+			return nil, "", err
+		}
+		offset := min(skip, len(batch))
+		batch = batch[offset:]
+		skip = 0
+		if limit > 0 && len(keys)+len(batch) > limit {
+			n := limit - len(keys)
+			keys = append(keys, batch[:n]...)
+			return keys, strconv.FormatUint(scan, 10) + ":" + strconv.Itoa(offset+n), nil
+		}
+		keys = append(keys, batch...)
+		if next == 0 {
+			return keys, "", nil
+		}
+		scan = next
+		if limit > 0 && len(keys) == limit {
+			return keys, strconv.FormatUint(scan, 10), nil
+		}
+	}
+}
+
+// parseCursor splits a ListMatch cursor into its SCAN cursor and the number
+// of keys to skip from that cursor's batch.
+func parseCursor(cursor string) (uint64, int, error) {
+	if cursor == "" {
+		return 0, 0, nil
+	}
+	scanPart, skipPart, hasSkip := strings.Cut(cursor, ":")
+	scan, err := strconv.ParseUint(scanPart, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	if !hasSkip {
+		return scan, 0, nil
+	}
+	skip, err := strconv.Atoi(skipPart)
+	if err != nil || skip < 0 {
+		return 0, 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return scan, skip, nil
+}
+
 // GetBatch retrieves multiple values by key.
 func (p *Provider) GetBatch(ctx context.Context, keys []string) (map[string][]byte, error) {
 	if len(keys) == 0 {
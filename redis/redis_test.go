@@ -240,6 +240,124 @@ func TestProvider_List(t *testing.T) {
 	})
 }
 
+func TestProvider_DeletePrefix(t *testing.T) {
+	clearRedis(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2500; i++ {
+		_ = testClient.Set(ctx, fmt.Sprintf("tmp/2023-%04d", i), "v", 0).Err()
+	}
+	_ = testClient.Set(ctx, "tmp/2024-0001", "v", 0).Err()
+	_ = testClient.Set(ctx, "glob*/a", "v", 0).Err()
+	_ = testClient.Set(ctx, "globx/a", "v", 0).Err()
+
+	n, err := testProvider.DeletePrefix(ctx, "tmp/2023-")
+	if err != nil {
+		t.Fatalf("DeletePrefix failed: %v", err)
+	}
+	if n != 2500 {
+		t.Errorf("expected 2500 deleted, got %d", n)
+	}
+
+	// The prefix is literal: "glob*" must not match "globx".
+	if n, err := testProvider.DeletePrefix(ctx, "glob*"); err != nil || n != 1 {
+		t.Errorf("expected 1 deleted for a literal *, got %d, %v", n, err)
+	}
+	keys, _ := testProvider.List(ctx, "", 0)
+	if len(keys) != 2 {
+		t.Errorf("expected 2 keys left, got %v", keys)
+	}
+}
+
+func TestProvider_ListMatch(t *testing.T) {
+	clearRedis(t)
+	ctx := context.Background()
+
+	for i := 0; i < 250; i++ {
+		_ = testClient.Set(ctx, fmt.Sprintf("user:%d:name", i), "n", 0).Err()
+		_ = testClient.Set(ctx, fmt.Sprintf("user:%d:email", i), "e", 0).Err()
+	}
+
+	t.Run("all pages", func(t *testing.T) {
+		seen := make(map[string]bool)
+		cursor := ""
+		for {
+			keys, next, err := testProvider.ListMatch(ctx, "user:*:name", cursor, 7)
+			if err != nil {
+				t.Fatalf("ListMatch failed: %v", err)
+			}
+			if len(keys) > 7 {
+				t.Fatalf("expected at most 7 keys, got %d", len(keys))
+			}
+			for _, k := range keys {
+				seen[k] = true
+			}
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		if len(seen) != 250 {
+			t.Errorf("expected 250 distinct keys, got %d", len(seen))
+		}
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		keys, next, err := testProvider.ListMatch(ctx, "user:1?:email", "", 0)
+		if err != nil {
+			t.Fatalf("ListMatch failed: %v", err)
+		}
+		if len(keys) != 10 || next != "" {
+			t.Errorf("expected 10 keys and no cursor, got %d %q", len(keys), next)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		if _, _, err := testProvider.ListMatch(ctx, "*", "nope", 1); err == nil {
+			t.Error("expected error for invalid cursor")
+		}
+	})
+}
+
+func TestProvider_CompareAndSwap(t *testing.T) {
+	clearRedis(t)
+	ctx := context.Background()
+	_ = testClient.Set(ctx, "key", "v1", 0).Err()
+
+	if ok, err := testProvider.CompareAndSwap(ctx, "key", []byte("stale"), []byte("v2"), 0); err != nil || ok {
+		t.Errorf("expected no swap for a stale value, got %v, %v", ok, err)
+	}
+	if ok, err := testProvider.CompareAndSwap(ctx, "key", []byte("v1"), []byte("v2"), time.Hour); err != nil || !ok {
+		t.Errorf("expected a swap, got %v, %v", ok, err)
+	}
+	if data, _ := testProvider.Get(ctx, "key"); string(data) != "v2" {
+		t.Errorf("expected v2, got %q", data)
+	}
+	if ttl := testClient.TTL(ctx, "key").Val(); ttl <= 0 {
+		t.Errorf("expected the TTL to be set, got %v", ttl)
+	}
+	if ok, err := testProvider.CompareAndSwap(ctx, "missing", nil, []byte("v"), 0); err != nil || ok {
+		t.Errorf("expected no swap for a missing key, got %v, %v", ok, err)
+	}
+}
+
+func TestProvider_TTL(t *testing.T) {
+	clearRedis(t)
+	ctx := context.Background()
+	_ = testClient.Set(ctx, "forever", "v", 0).Err()
+	_ = testClient.Set(ctx, "expiring", "v", time.Hour).Err()
+
+	if ttl, err := testProvider.TTL(ctx, "forever"); err != nil || ttl != 0 {
+		t.Errorf("expected no expiry, got %v, %v", ttl, err)
+	}
+	if ttl, err := testProvider.TTL(ctx, "expiring"); err != nil || ttl <= 0 || ttl > time.Hour {
+		t.Errorf("expected up to an hour, got %v, %v", ttl, err)
+	}
+	if _, err := testProvider.TTL(ctx, "missing"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
 func TestProvider_GetBatch(t *testing.T) {
 	clearRedis(t)
 	ctx := context.Background()
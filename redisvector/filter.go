@@ -0,0 +1,223 @@
+package redisvector
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/vecna"
+)
+
+// translateFilter converts a vecna.Filter to a RediSearch query. Strings
+// and booleans match TAG fields and numbers match NUMERIC fields; a nil
+// filter matches everything.
+//
+// Note: RediSearch does not support the Like operator.
+func translateFilter(f *vecna.Filter) (string, error) {
+	if f == nil {
+		return "*", nil
+	}
+
+	if err := f.Err(); err != nil {
+		return "", fmt.Errorf("%w: %v", grub.ErrInvalidQuery, err)
+	}
+
+	return translateNode(f)
+}
+
+// translateNode recursively translates a filter node.
+func translateNode(f *vecna.Filter) (string, error) {
+	switch f.Op() {
+	case vecna.And:
+		return translateLogical(f.Children(), " ")
+	case vecna.Or:
+		return translateLogical(f.Children(), " | ")
+	case vecna.Not:
+		children := f.Children()
+		if len(children) != 1 {
+			return "", fmt.Errorf("%w: NOT requires exactly one child", grub.ErrInvalidQuery)
+		}
+		child, err := translateNode(children[0])
+		if err != nil {
+			return "", err
+		}
+		return "-(" + child + ")", nil
+	default:
+		return translateCondition(f)
+	}
+}
+
+// translateLogical joins the translated children with sep, which is a
+// space for AND and a pipe for OR.
+func translateLogical(children []*vecna.Filter, sep string) (string, error) {
+	if len(children) == 0 {
+		return "", fmt.Errorf("%w: logical operator requires at least one condition", grub.ErrInvalidQuery)
+	}
+	parts := make([]string, len(children))
+	for i, child := range children {
+		part, err := translateNode(child)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = part
+	}
+	return "(" + strings.Join(parts, sep) + ")", nil
+}
+
+// translateCondition translates a field condition.
+func translateCondition(f *vecna.Filter) (string, error) {
+	field := f.Field()
+	value := f.Value()
+
+	switch f.Op() {
+	case vecna.Eq:
+		return translateMatch(field, value)
+	case vecna.Ne:
+		match, err := translateMatch(field, value)
+		if err != nil {
+			return "", err
+		}
+		return "-" + match, nil
+	case vecna.Gt, vecna.Gte, vecna.Lt, vecna.Lte:
+		return translateRange(field, value, f.Op())
+	case vecna.In:
+		return translateIn(field, value)
+	case vecna.Nin:
+		in, err := translateIn(field, value)
+		if err != nil {
+			return "", err
+		}
+		return "-" + in, nil
+	case vecna.Contains:
+		// a TAG field indexed from an array matches any of its elements
+		return translateMatch(field, value)
+	case vecna.Like:
+		return "", fmt.Errorf("%w: RediSearch does not support Like operator", grub.ErrOperatorNotSupported)
+	default:
+		return "", fmt.Errorf("%w: %s", grub.ErrOperatorNotSupported, f.Op())
+	}
+}
+
+// translateMatch translates an equality match: a tag match for strings and
+// booleans, a single-value range for numbers.
+func translateMatch(field string, value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("@%s:{%s}", field, escapeTag(v)), nil
+	case bool:
+		return fmt.Sprintf("@%s:{%t}", field, v), nil
+	}
+	n, ok := toNumber(value)
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported value type %T for Eq", grub.ErrInvalidQuery, value)
+	}
+	return fmt.Sprintf("@%s:[%s %s]", field, n, n), nil
+}
+
+// translateRange translates a numeric range condition; "(" marks an
+// exclusive bound.
+func translateRange(field string, value any, op vecna.Op) (string, error) {
+	n, ok := toNumber(value)
+	if !ok {
+		return "", fmt.Errorf("%w: range operators require numeric value, got %T", grub.ErrInvalidQuery, value)
+	}
+	var lo, hi string
+	switch op {
+	case vecna.Gt:
+		lo, hi = "("+n, "+inf"
+	case vecna.Gte:
+		lo, hi = n, "+inf"
+	case vecna.Lt:
+		lo, hi = "-inf", "("+n
+	default:
+		lo, hi = "-inf", n
+	}
+	return fmt.Sprintf("@%s:[%s %s]", field, lo, hi), nil
+}
+
+// translateIn translates an IN condition: one tag match with alternatives
+// for strings, a union of single-value ranges for numbers.
+func translateIn(field string, value any) (string, error) {
+	slice, ok := value.([]any)
+	if !ok {
+		return "", fmt.Errorf("%w: In requires slice value", grub.ErrInvalidQuery)
+	}
+	if len(slice) == 0 {
+		return "", fmt.Errorf("%w: In requires at least one value", grub.ErrInvalidQuery)
+	}
+
+	if _, ok := slice[0].(string); ok {
+		tags := make([]string, len(slice))
+		for i, v := range slice {
+			s, ok := v.(string)
+			if !ok {
+				return "", fmt.Errorf("%w: In values must be same type", grub.ErrInvalidQuery)
+			}
+			tags[i] = escapeTag(s)
+		}
+		return fmt.Sprintf("@%s:{%s}", field, strings.Join(tags, " | ")), nil
+	}
+
+	ranges := make([]string, len(slice))
+	for i, v := range slice {
+		n, ok := toNumber(v)
+		if !ok {
+			return "", fmt.Errorf("%w: unsupported value type %T for In", grub.ErrInvalidQuery, v)
+		}
+		ranges[i] = fmt.Sprintf("@%s:[%s %s]", field, n, n)
+	}
+	return "(" + strings.Join(ranges, " | ") + ")", nil
+}
+
+// translateMap converts a Search equality filter to a RediSearch query
+// matching every key, in key order. A nil or empty filter matches
+// everything.
+func translateMap(filter map[string]any) (string, error) {
+	if len(filter) == 0 {
+		return "*", nil
+	}
+	fields := make([]string, 0, len(filter))
+	for field := range filter {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		match, err := translateMatch(field, filter[field])
+		if err != nil {
+			return "", err
+		}
+		parts[i] = match
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// toNumber formats a numeric filter value for a RediSearch range.
+func toNumber(value any) (string, bool) {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	}
+	return "", false
+}
+
+// escapeTag backslash-escapes the characters RediSearch treats as syntax
+// inside a tag value, which is every character other than letters, digits,
+// and underscores.
+func escapeTag(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
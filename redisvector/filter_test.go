@@ -0,0 +1,141 @@
+package redisvector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/vecna"
+)
+
+// Test metadata types
+type testMeta struct {
+	Category string
+	Score    int
+	Status   string
+	Tags     []string
+	Deleted  bool
+	Data     any
+}
+
+func mustBuilder(t *testing.T) *vecna.Builder[testMeta] {
+	t.Helper()
+	b, err := vecna.New[testMeta]()
+	if err != nil {
+		t.Fatalf("failed to create builder: %v", err)
+	}
+	return b
+}
+
+func TestTranslateFilter_Nil(t *testing.T) {
+	result, err := translateFilter(nil)
+	if err != nil || result != "*" {
+		t.Errorf("expected a match-all query, got %q, %v", result, err)
+	}
+}
+
+func TestTranslateFilter_InvalidFilter(t *testing.T) {
+	b := mustBuilder(t)
+	f := b.Where("").Eq("test") // Empty field name causes error
+
+	if _, err := translateFilter(f); !errors.Is(err, grub.ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery, got %v", err)
+	}
+}
+
+func TestTranslateFilter_Conditions(t *testing.T) {
+	b := mustBuilder(t)
+
+	tests := []struct {
+		name   string
+		filter *vecna.Filter
+		want   string
+	}{
+		{"eq string", b.Where("Category").Eq("test"), "@Category:{test}"},
+		{"eq escaped", b.Where("Category").Eq("a-b c"), `@Category:{a\-b\ c}`},
+		{"eq int", b.Where("Score").Eq(42), "@Score:[42 42]"},
+		{"eq float", b.Where("Score").Eq(3.5), "@Score:[3.5 3.5]"},
+		{"eq bool", b.Where("Deleted").Eq(true), "@Deleted:{true}"},
+		{"ne", b.Where("Status").Ne("archived"), "-@Status:{archived}"},
+		{"gt", b.Where("Score").Gt(50), "@Score:[(50 +inf]"},
+		{"gte", b.Where("Score").Gte(50), "@Score:[50 +inf]"},
+		{"lt", b.Where("Score").Lt(50), "@Score:[-inf (50]"},
+		{"lte", b.Where("Score").Lte(50), "@Score:[-inf 50]"},
+		{"in strings", b.Where("Status").In("a", "b"), "@Status:{a | b}"},
+		{"in numbers", b.Where("Score").In(1, 2), "(@Score:[1 1] | @Score:[2 2])"},
+		{"nin", b.Where("Status").Nin("a", "b"), "-@Status:{a | b}"},
+		{"contains", b.Where("Tags").Contains("alpha"), "@Tags:{alpha}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := translateFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("translateFilter failed: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestTranslateFilter_Logical(t *testing.T) {
+	b := mustBuilder(t)
+
+	// (category = "test" AND score > 50) OR NOT deleted = true
+	f := b.Or(
+		b.And(
+			b.Where("Category").Eq("test"),
+			b.Where("Score").Gt(50),
+		),
+		b.Not(b.Where("Deleted").Eq(true)),
+	)
+
+	result, err := translateFilter(f)
+	if err != nil {
+		t.Fatalf("translateFilter failed: %v", err)
+	}
+	want := "((@Category:{test} @Score:[(50 +inf]) | -(@Deleted:{true}))"
+	if result != want {
+		t.Errorf("expected %q, got %q", want, result)
+	}
+}
+
+func TestTranslateFilter_LikeNotSupported(t *testing.T) {
+	b := mustBuilder(t)
+	f := b.Where("Category").Like("te%")
+
+	if _, err := translateFilter(f); !errors.Is(err, grub.ErrOperatorNotSupported) {
+		t.Errorf("expected ErrOperatorNotSupported, got %v", err)
+	}
+}
+
+func TestTranslateFilter_InvalidValues(t *testing.T) {
+	b := mustBuilder(t)
+
+	for name, f := range map[string]*vecna.Filter{
+		"unsupported type": b.Where("Data").Eq(struct{}{}),
+		"range of string":  b.Where("Category").Gt("a"),
+		"in mixed types":   b.Where("Status").In("a", 1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := translateFilter(f); !errors.Is(err, grub.ErrInvalidQuery) {
+				t.Errorf("expected ErrInvalidQuery, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTranslateMap(t *testing.T) {
+	result, err := translateMap(map[string]any{"category": "docs", "score": 2.0})
+	if err != nil {
+		t.Fatalf("translateMap failed: %v", err)
+	}
+	if want := "@category:{docs} @score:[2 2]"; result != want {
+		t.Errorf("expected %q, got %q", want, result)
+	}
+	if result, _ := translateMap(nil); result != "*" {
+		t.Errorf("expected a match-all query for no filter, got %q", result)
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/zoobzio/grub/redisvector
+
+go 1.24.0
+
+require (
+	github.com/redis/go-redis/v9 v9.17.2
+	github.com/zoobzio/grub v0.0.0
+)
@@ -0,0 +1,662 @@
+// Package redisvector provides a grub VectorProvider implementation for
+// Redis Stack, storing vectors as hashes or JSON documents indexed by a
+// RediSearch index with a VECTOR field.
+//
+// The client must speak RESP2 (redis.Options{Protocol: 2}); go-redis does
+// not parse RediSearch replies over RESP3.
+package redisvector
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/grub/internal/shared"
+	"github.com/zoobzio/vecna"
+)
+
+// Storage selects how each vector is stored in Redis.
+type Storage string
+
+const (
+	// StorageHash stores each vector as a hash holding the vector as packed
+	// little-endian float32s, the metadata as JSON, and each indexed field
+	// as its own hash field.
+	StorageHash Storage = "HASH"
+
+	// StorageJSON stores each vector as a JSON document with vector and
+	// metadata members. Requires the RedisJSON module.
+	StorageJSON Storage = "JSON"
+)
+
+// Algorithm selects the vector index algorithm.
+type Algorithm string
+
+const (
+	// AlgorithmHNSW builds an approximate nearest neighbor graph.
+	AlgorithmHNSW Algorithm = "HNSW"
+
+	// AlgorithmFlat searches by brute force: exact, but linear in the
+	// number of vectors.
+	AlgorithmFlat Algorithm = "FLAT"
+)
+
+// FieldType is the RediSearch type of an indexed metadata field.
+type FieldType string
+
+const (
+	// FieldTag indexes strings, booleans, and arrays of strings for exact
+	// matching.
+	FieldTag FieldType = "TAG"
+
+	// FieldNumeric indexes numbers for equality and range matching.
+	FieldNumeric FieldType = "NUMERIC"
+)
+
+// Field declares a top-level metadata property to index. RediSearch can
+// only filter on indexed properties.
+type Field struct {
+	Name string
+	Type FieldType
+}
+
+// Config holds configuration for the Redis vector provider.
+type Config struct {
+	// Index is the name of the RediSearch index.
+	Index string
+
+	// Prefix is prepended to each vector's ID to form its key, and is the
+	// key prefix the index covers. Defaults to Index + ":".
+	Prefix string
+
+	// Storage selects hash or JSON documents. Defaults to StorageHash.
+	Storage Storage
+
+	// Dimension is the vector dimensionality EnsureCollection creates the
+	// index with.
+	Dimension int
+
+	// Metric is the distance the index was created with, used by
+	// EnsureCollection and reported by ScoreKind. Defaults to cosine.
+	// Redis scores every metric as a distance; see ScoreKind.
+	Metric grub.DistanceMetric
+
+	// Algorithm is the vector index algorithm EnsureCollection creates the
+	// index with. Defaults to AlgorithmHNSW.
+	Algorithm Algorithm
+
+	// Fields are the metadata properties the index covers, and so the ones
+	// Search, Query, and Filter can filter on.
+	Fields []Field
+}
+
+const (
+	// vectorField holds the vector in each document.
+	vectorField = "vector"
+	// metadataField holds the metadata JSON in each document.
+	metadataField = "metadata"
+	// scoreField is the alias KNN queries give the distance.
+	scoreField = "__vector_score"
+	// pageSize is how many documents List and Filter read per cursor page.
+	pageSize = 1000
+)
+
+// Provider implements grub.VectorProvider for Redis Stack.
+type Provider struct {
+	client *redis.Client
+	config Config
+}
+
+// New creates a Redis vector provider with the given client and config.
+func New(client *redis.Client, config Config) *Provider {
+	if config.Prefix == "" {
+		config.Prefix = config.Index + ":"
+	}
+	if config.Storage == "" {
+		config.Storage = StorageHash
+	}
+	if config.Metric == "" {
+		config.Metric = grub.DistanceCosine
+	}
+	if config.Algorithm == "" {
+		config.Algorithm = AlgorithmHNSW
+	}
+	return &Provider{client: client, config: config}
+}
+
+// ScoreKind reports how search scores are measured for Config.Metric.
+// It implements grub.VectorScorer. Redis reports distances for every
+// metric: cosine as 1 - cos, L2 as the squared Euclidean distance, and
+// inner product as 1 - dot, which the provider shifts to -dot so it matches
+// the other providers' inner product distance.
+func (p *Provider) ScoreKind() grub.ScoreKind {
+	switch metric := p.config.Metric; metric {
+	case grub.DistanceCosine, grub.DistanceInnerProduct:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreDistance}
+	case grub.DistanceL2:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreSquaredDistance}
+	}
+	return grub.ScoreKind{}
+}
+
+// distanceMetric maps Config.Metric to its RediSearch DISTANCE_METRIC.
+func (p *Provider) distanceMetric() (string, error) {
+	switch p.config.Metric {
+	case grub.DistanceCosine:
+		return "COSINE", nil
+	case grub.DistanceL2:
+		return "L2", nil
+	case grub.DistanceInnerProduct:
+		return "IP", nil
+	}
+	return "", fmt.Errorf("redisvector: unsupported metric %q", p.config.Metric)
+}
+
+// EnsureCollection creates the index over Config.Prefix with a FLOAT32
+// vector field of Config.Dimension, Config.Metric, and Config.Algorithm,
+// plus a field per Config.Fields. An existing index is left as is, even if
+// its schema differs.
+func (p *Provider) EnsureCollection(ctx context.Context) error {
+	if p.config.Dimension <= 0 {
+		return errors.New("redisvector: EnsureCollection requires Config.Dimension")
+	}
+	metric, err := p.distanceMetric()
+	if err != nil {
+		return err
+	}
+
+	vector := &redis.FieldSchema{FieldName: vectorField, FieldType: redis.SearchFieldTypeVector}
+	if p.config.Algorithm == AlgorithmFlat {
+		vector.VectorArgs = &redis.FTVectorArgs{FlatOptions: &redis.FTFlatOptions{
+			Type: "FLOAT32", Dim: p.config.Dimension, DistanceMetric: metric,
+		}}
+	} else {
+		vector.VectorArgs = &redis.FTVectorArgs{HNSWOptions: &redis.FTHNSWOptions{
+			Type: "FLOAT32", Dim: p.config.Dimension, DistanceMetric: metric,
+		}}
+	}
+	schema := []*redis.FieldSchema{vector}
+	for _, f := range p.config.Fields {
+		field := &redis.FieldSchema{FieldName: f.Name, FieldType: redis.SearchFieldTypeTag}
+		if f.Type == FieldNumeric {
+			field.FieldType = redis.SearchFieldTypeNumeric
+		}
+		schema = append(schema, field)
+	}
+
+	opts := &redis.FTCreateOptions{Prefix: []any{p.config.Prefix}}
+	if p.config.Storage == StorageJSON {
+		opts.OnJSON = true
+		for _, field := range schema {
+			if field.FieldName == vectorField {
+				field.FieldName, field.As = "$."+vectorField, vectorField
+			} else {
+				field.FieldName, field.As = "$."+metadataField+"."+field.FieldName, field.FieldName
+			}
+		}
+	} else {
+		opts.OnHash = true
+	}
+
+	err = p.client.FTCreate(ctx, p.config.Index, opts, schema...).Err()
+	if err != nil && strings.Contains(err.Error(), "Index already exists") {
+		return nil
+	}
+	return err
+}
+
+// key returns the Redis key for id.
+func (p *Provider) key(id uuid.UUID) string {
+	return p.config.Prefix + id.String()
+}
+
+// parseKey returns the ID stored at key.
+func (p *Provider) parseKey(key string) (uuid.UUID, error) {
+	return uuid.Parse(strings.TrimPrefix(key, p.config.Prefix))
+}
+
+// Upsert stores or updates a vector with associated metadata.
+func (p *Provider) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata []byte) error {
+	return p.UpsertBatch(ctx, []grub.VectorRecord{{ID: id, Vector: vector, Metadata: metadata}})
+}
+
+// UpsertBatch stores or updates multiple vectors in one MULTI/EXEC
+// pipeline. Each vector replaces the whole document, so indexed fields
+// dropped from its metadata are dropped from the index.
+func (p *Provider) UpsertBatch(ctx context.Context, vectors []grub.VectorRecord) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+	pipe := p.client.TxPipeline()
+	for _, v := range vectors {
+		key := p.key(v.ID)
+		if p.config.Storage == StorageJSON {
+			doc, err := json.Marshal(jsonDocument{Vector: v.Vector, Metadata: v.Metadata})
+			if err != nil {
+				return err
+			}
+			pipe.JSONSet(ctx, key, "$", doc)
+			continue
+		}
+		values, err := p.hashValues(v.Vector, v.Metadata)
+		if err != nil {
+			return err
+		}
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, values)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return shared.CtxErr(ctx, err)
+	}
+	return nil
+}
+
+// jsonDocument is a vector stored under StorageJSON.
+type jsonDocument struct {
+	Vector   []float32       `json:"vector"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+}
+
+// hashValues returns the hash fields for a vector stored under
+// StorageHash: the packed vector, the metadata, and the indexed fields
+// present in the metadata.
+func (p *Provider) hashValues(vector []float32, metadata []byte) (map[string]any, error) {
+	values := map[string]any{vectorField: encodeVector(vector)}
+	if metadata == nil {
+		return values, nil
+	}
+	values[metadataField] = metadata
+	if len(p.config.Fields) == 0 {
+		return values, nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(metadata, &m); err != nil {
+		return nil, err
+	}
+	for _, f := range p.config.Fields {
+		if value, ok := hashFieldValue(m[f.Name]); ok {
+			values[f.Name] = value
+		}
+	}
+	return values, nil
+}
+
+// hashFieldValue formats a metadata value as RediSearch indexes it in a
+// hash. Arrays of strings become comma-separated tags; values that cannot
+// be indexed are skipped.
+func hashFieldValue(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case []any:
+		tags := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return "", false
+			}
+			tags = append(tags, s)
+		}
+		return strings.Join(tags, ","), true
+	}
+	return "", false
+}
+
+// encodeVector packs vector as little-endian float32s, the layout
+// RediSearch reads from hash fields and query parameters.
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// decodeVector unpacks a vector packed by encodeVector.
+func decodeVector(data []byte) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("redisvector: vector of %d bytes is not a float32 array", len(data))
+	}
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return vector, nil
+}
+
+// Get retrieves a vector by ID.
+func (p *Provider) Get(ctx context.Context, id uuid.UUID) ([]float32, *grub.VectorInfo, error) {
+	docs, err := p.fetch(ctx, []string{p.key(id)})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(docs) == 0 {
+		return nil, nil, grub.ErrNotFound
+	}
+	doc := docs[0]
+	return doc.Vector, &grub.VectorInfo{
+		ID:        id,
+		Dimension: len(doc.Vector),
+		Metadata:  doc.Metadata,
+	}, nil
+}
+
+// fetch reads the documents at keys in one pipeline, skipping keys that
+// no longer exist.
+func (p *Provider) fetch(ctx context.Context, keys []string) ([]grub.VectorResult, error) {
+	pipe := p.client.Pipeline()
+	hashCmds := make([]*redis.SliceCmd, len(keys))
+	jsonCmds := make([]*redis.JSONCmd, len(keys))
+	for i, key := range keys {
+		if p.config.Storage == StorageJSON {
+			jsonCmds[i] = pipe.JSONGet(ctx, key)
+		} else {
+			hashCmds[i] = pipe.HMGet(ctx, key, vectorField, metadataField)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, shared.CtxErr(ctx, err)
+	}
+
+	results := make([]grub.VectorResult, 0, len(keys))
+	for i, key := range keys {
+		id, err := p.parseKey(key)
+		if err != nil {
+			return nil, err
+		}
+		result := grub.VectorResult{ID: id}
+		if p.config.Storage == StorageJSON {
+			data, err := jsonCmds[i].Result()
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			var doc jsonDocument
+			if err := json.Unmarshal([]byte(data), &doc); err != nil {
+				return nil, err
+			}
+			result.Vector, result.Metadata = doc.Vector, nullAsNil(doc.Metadata)
+		} else {
+			values, err := hashCmds[i].Result()
+			if err != nil {
+				return nil, err
+			}
+			packed, ok := values[0].(string)
+			if !ok {
+				continue
+			}
+			if result.Vector, err = decodeVector([]byte(packed)); err != nil {
+				return nil, err
+			}
+			if metadata, ok := values[1].(string); ok {
+				result.Metadata = []byte(metadata)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// nullAsNil returns nil for absent or null JSON metadata.
+func nullAsNil(data json.RawMessage) []byte {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+// Delete removes a vector by ID.
+func (p *Provider) Delete(ctx context.Context, id uuid.UUID) error {
+	n, err := p.client.Del(ctx, p.key(id)).Result()
+	if err != nil {
+		return shared.CtxErr(ctx, err)
+	}
+	if n == 0 {
+		return grub.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteBatch removes multiple vectors by ID in one DEL.
+func (p *Provider) DeleteBatch(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = p.key(id)
+	}
+	if err := p.client.Del(ctx, keys...).Err(); err != nil {
+		return shared.CtxErr(ctx, err)
+	}
+	return nil
+}
+
+// Search performs similarity search and returns the k nearest neighbors.
+func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter map[string]any) ([]grub.VectorResult, error) {
+	return p.SearchPage(ctx, vector, k, 0, filter)
+}
+
+// SearchPage performs similarity search, skipping the first offset results.
+func (p *Provider) SearchPage(ctx context.Context, vector []float32, k, offset int, filter map[string]any) ([]grub.VectorResult, error) {
+	query, err := translateMap(filter)
+	if err != nil {
+		return nil, err
+	}
+	return p.knn(ctx, vector, k, offset, query)
+}
+
+// Query performs similarity search with vecna filter support.
+func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	return p.QueryPage(ctx, vector, k, 0, filter)
+}
+
+// QueryPage performs filtered similarity search, skipping the first offset
+// results.
+func (p *Provider) QueryPage(ctx context.Context, vector []float32, k, offset int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	query, err := translateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return p.knn(ctx, vector, k, offset, query)
+}
+
+// knn runs an FT.SEARCH KNN query for the offset+k nearest neighbors
+// matching the prefilter query and returns the last k, nearest first.
+func (p *Provider) knn(ctx context.Context, vector []float32, k, offset int, query string) ([]grub.VectorResult, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	metadata := redis.FTSearchReturn{FieldName: metadataField}
+	if p.config.Storage == StorageJSON {
+		metadata = redis.FTSearchReturn{FieldName: "$." + metadataField, As: metadataField}
+	}
+	if query != "*" {
+		query = "(" + query + ")"
+	}
+	q := fmt.Sprintf("%s=>[KNN %d @%s $vec AS %s]", query, offset+k, vectorField, scoreField)
+	res, err := p.client.FTSearchWithArgs(ctx, p.config.Index, q, &redis.FTSearchOptions{
+		Return:         []redis.FTSearchReturn{{FieldName: scoreField}, metadata},
+		SortBy:         []redis.FTSearchSortBy{{FieldName: scoreField, Asc: true}},
+		LimitOffset:    offset,
+		Limit:          k,
+		Params:         map[string]any{"vec": encodeVector(vector)},
+		DialectVersion: 2,
+	}).Result()
+	if err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+
+	results := make([]grub.VectorResult, 0, len(res.Docs))
+	for _, doc := range res.Docs {
+		id, err := p.parseKey(doc.ID)
+		if err != nil {
+			return nil, err
+		}
+		score, err := strconv.ParseFloat(doc.Fields[scoreField], 32)
+		if err != nil {
+			return nil, fmt.Errorf("redisvector: parsing %s: %w", scoreField, err)
+		}
+		result := grub.VectorResult{ID: id, Score: p.score(score)}
+		if data, ok := doc.Fields[metadataField]; ok {
+			result.Metadata = nullAsNil(json.RawMessage(data))
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// score converts a RediSearch distance to the provider's ScoreKind.
+func (p *Provider) score(distance float64) float32 {
+	if p.config.Metric == grub.DistanceInnerProduct {
+		// Redis reports 1 - dot; the canonical inner product distance is -dot
+		return float32(distance - 1)
+	}
+	return float32(distance)
+}
+
+// Filter returns vectors matching the metadata filter without similarity
+// search, reading matching keys through an FT.AGGREGATE cursor and the
+// documents in a pipeline per page. Limit of 0 returns all matches.
+func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int) ([]grub.VectorResult, error) {
+	query, err := translateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	var results []grub.VectorResult
+	err = p.scan(ctx, query, limit, func(keys []string) error {
+		docs, err := p.fetch(ctx, keys)
+		results = append(results, docs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// List returns vector IDs, read through an FT.AGGREGATE cursor.
+// Limit of 0 means no limit.
+func (p *Provider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := p.scan(ctx, "*", limit, func(keys []string) error {
+		for _, key := range keys {
+			id, err := p.parseKey(key)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// scan passes the keys of documents matching query to visit a page at a
+// time, up to limit keys (0 for all). FT.SEARCH has no cursor and caps
+// offsets at MAXSEARCHRESULTS, so scan pages with FT.AGGREGATE WITHCURSOR
+// instead. Cancellation is checked between pages; a cursor abandoned early
+// is deleted.
+func (p *Provider) scan(ctx context.Context, query string, limit int, visit func(keys []string) error) error {
+	count := pageSize
+	if limit > 0 && limit < count {
+		count = limit
+	}
+	reply, err := p.client.Do(ctx, "FT.AGGREGATE", p.config.Index, query,
+		"LOAD", 1, "@__key", "WITHCURSOR", "COUNT", count, "DIALECT", 2).Result()
+	seen := 0
+	for {
+		if err != nil {
+			return shared.CtxErr(ctx, err)
+		}
+		keys, cursor, err := parseCursorReply(reply)
+		if err != nil {
+			return err
+		}
+		if limit > 0 && seen+len(keys) > limit {
+			keys = keys[:limit-seen]
+		}
+		seen += len(keys)
+		if err := visit(keys); err != nil {
+			p.closeCursor(cursor)
+			return err
+		}
+		if cursor == 0 {
+			return nil
+		}
+		if limit > 0 && seen >= limit {
+			p.closeCursor(cursor)
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			p.closeCursor(cursor)
+			return err
+		}
+		reply, err = p.client.Do(ctx, "FT.CURSOR", "READ", p.config.Index, cursor, "COUNT", count).Result()
+	}
+}
+
+// closeCursor releases a cursor scan stopped reading. It runs without the
+// caller's context, which may be cancelled; a cursor left behind expires
+// on its own.
+func (p *Provider) closeCursor(cursor int64) {
+	if cursor != 0 {
+		_ = p.client.Do(context.Background(), "FT.CURSOR", "DEL", p.config.Index, cursor).Err()
+	}
+}
+
+// parseCursorReply reads the keys and next cursor from a RESP2
+// FT.AGGREGATE WITHCURSOR or FT.CURSOR READ reply, shaped
+// [[total, [__key, key], ...], cursor].
+func parseCursorReply(reply any) ([]string, int64, error) {
+	outer, ok := reply.([]any)
+	if !ok || len(outer) != 2 {
+		return nil, 0, fmt.Errorf("redisvector: unexpected cursor reply %T", reply)
+	}
+	rows, ok := outer[0].([]any)
+	cursor, cok := outer[1].(int64)
+	if !ok || !cok || len(rows) == 0 {
+		return nil, 0, fmt.Errorf("redisvector: unexpected cursor reply %v", reply)
+	}
+	keys := make([]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		fields, ok := row.([]any)
+		if !ok {
+			return nil, 0, fmt.Errorf("redisvector: unexpected cursor row %T", row)
+		}
+		for i := 0; i+1 < len(fields); i += 2 {
+			if name, _ := fields[i].(string); name == "__key" {
+				if key, ok := fields[i+1].(string); ok {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	return keys, cursor, nil
+}
+
+// Exists checks whether a vector ID exists.
+func (p *Provider) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	n, err := p.client.Exists(ctx, p.key(id)).Result()
+	if err != nil {
+		return false, shared.CtxErr(ctx, err)
+	}
+	return n > 0, nil
+}
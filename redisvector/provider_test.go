@@ -0,0 +1,111 @@
+package redisvector
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/zoobzio/grub"
+)
+
+func TestNew(t *testing.T) {
+	p := New(nil, Config{Index: "docs"})
+	if p == nil {
+		t.Fatal("New returned nil")
+	}
+	want := Config{Index: "docs", Prefix: "docs:", Storage: StorageHash, Metric: grub.DistanceCosine, Algorithm: AlgorithmHNSW}
+	if !reflect.DeepEqual(p.config, want) {
+		t.Errorf("expected defaults %+v, got %+v", want, p.config)
+	}
+}
+
+func TestScoreKind(t *testing.T) {
+	tests := []struct {
+		metric grub.DistanceMetric
+		want   grub.ScoreKind
+	}{
+		{grub.DistanceCosine, grub.ScoreKind{Metric: grub.DistanceCosine, Measure: grub.ScoreDistance}},
+		{grub.DistanceInnerProduct, grub.ScoreKind{Metric: grub.DistanceInnerProduct, Measure: grub.ScoreDistance}},
+		{grub.DistanceL2, grub.ScoreKind{Metric: grub.DistanceL2, Measure: grub.ScoreSquaredDistance}},
+		{"hamming", grub.ScoreKind{}},
+	}
+	for _, tt := range tests {
+		if got := New(nil, Config{Index: "docs", Metric: tt.metric}).ScoreKind(); got != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.metric, tt.want, got)
+		}
+	}
+}
+
+func TestScore_InnerProduct(t *testing.T) {
+	// Redis reports 1 - dot; a dot product of 0.75 should score -0.75
+	p := New(nil, Config{Index: "docs", Metric: grub.DistanceInnerProduct})
+	if got := p.score(0.25); got != -0.75 {
+		t.Errorf("expected -0.75, got %v", got)
+	}
+	if got := New(nil, Config{Index: "docs"}).score(0.25); got != 0.25 {
+		t.Errorf("expected cosine distances unchanged, got %v", got)
+	}
+}
+
+func TestVectorEncoding(t *testing.T) {
+	vector := []float32{0.5, -1.25, 3}
+	packed := encodeVector(vector)
+	if len(packed) != 12 {
+		t.Fatalf("expected 4 bytes per component, got %d", len(packed))
+	}
+	decoded, err := decodeVector(packed)
+	if err != nil || !slices.Equal(decoded, vector) {
+		t.Errorf("expected %v, got %v, %v", vector, decoded, err)
+	}
+	if _, err := decodeVector(packed[:5]); err == nil {
+		t.Error("expected an error for a truncated vector")
+	}
+}
+
+func TestHashValues(t *testing.T) {
+	p := New(nil, Config{Index: "docs", Fields: []Field{
+		{Name: "category", Type: FieldTag},
+		{Name: "tags", Type: FieldTag},
+		{Name: "score", Type: FieldNumeric},
+		{Name: "missing", Type: FieldTag},
+	}})
+	metadata := []byte(`{"category":"docs","tags":["a","b"],"score":2.5,"other":{"x":1}}`)
+
+	values, err := p.hashValues([]float32{1}, metadata)
+	if err != nil {
+		t.Fatalf("hashValues failed: %v", err)
+	}
+	want := map[string]any{
+		vectorField:   encodeVector([]float32{1}),
+		metadataField: metadata,
+		"category":    "docs",
+		"tags":        "a,b",
+		"score":       "2.5",
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("expected %v, got %v", want, values)
+	}
+
+	values, err = p.hashValues([]float32{1}, nil)
+	if err != nil || len(values) != 1 {
+		t.Errorf("expected only the vector without metadata, got %v, %v", values, err)
+	}
+}
+
+func TestParseCursorReply(t *testing.T) {
+	reply := []any{
+		[]any{int64(2), []any{"__key", "docs:a"}, []any{"__key", "docs:b"}},
+		int64(42),
+	}
+	keys, cursor, err := parseCursorReply(reply)
+	if err != nil {
+		t.Fatalf("parseCursorReply failed: %v", err)
+	}
+	if !slices.Equal(keys, []string{"docs:a", "docs:b"}) || cursor != 42 {
+		t.Errorf("expected two keys and cursor 42, got %v and %d", keys, cursor)
+	}
+
+	if _, _, err := parseCursorReply([]any{int64(0)}); err == nil {
+		t.Error("expected an error for a reply without a cursor")
+	}
+}
@@ -0,0 +1,119 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultReindexBatch is the Reindex batch size used when batchSize is not
+// positive.
+const defaultReindexBatch = 100
+
+// Reindex recomputes every stored vector with embed, keeping each ID and
+// its metadata. Vectors are listed up front, fetched one by one, and
+// written back through the provider's UpsertBatch batchSize at a time
+// (100 if batchSize is not positive). embed receives each vector as Get
+// returns it; the stored metadata is written back byte for byte, so
+// BeforeSave hooks, timestamps, and redaction do not apply. Vectors
+// deleted after the listing are skipped, as are those whose metadata
+// cannot be decoded when the DecodeErrorHandler returns nil. WithDimension
+// and WithNormalization apply to the new vectors. If progress is non-nil
+// it is called after each batch is written with the number of vectors
+// processed so far and the number listed. Batches already written are not
+// rolled back when a later one fails.
+func (i *Index[T]) Reindex(ctx context.Context, embed func(ctx context.Context, v Vector[T]) ([]float32, error), batchSize int, progress func(done, total int)) error {
+	if i.readOnly {
+		return i.wrapErr("reindex", "", ErrReadOnly)
+	}
+	if batchSize <= 0 {
+		batchSize = defaultReindexBatch
+	}
+	listCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	ids, err := i.lister.List(listCtx, 0)
+	cancel()
+	if err != nil {
+		return i.wrapErr("reindex", "", err)
+	}
+
+	records := make([]VectorRecord, 0, batchSize)
+	for n, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record, ok, err := i.reembed(ctx, embed, id)
+		if err != nil {
+			return err
+		}
+		if ok {
+			records = append(records, record)
+		}
+		if done := n + 1; done%batchSize == 0 || done == len(ids) {
+			if err := i.writeReindexed(ctx, records); err != nil {
+				return err
+			}
+			records = records[:0]
+			if progress != nil {
+				progress(done, len(ids))
+			}
+		}
+	}
+	return nil
+}
+
+// writeReindexed writes one Reindex batch through the provider.
+func (i *Index[T]) writeReindexed(ctx context.Context, records []VectorRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	start := time.Now()
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	if err := i.provider.UpsertBatch(callCtx, records); err != nil {
+		i.emitWriteFailed(ctx, IndexUpsertFailed, "reindex", len(records), start, err)
+		return i.wrapErr("reindex", "", classifyDimension(err))
+	}
+	i.emitWriteCompleted(ctx, IndexUpsertCompleted, "reindex", len(records), start)
+	return nil
+}
+
+// reembed fetches the vector at id and recomputes it with embed. ok is false
+// when the vector should be left as is: deleted since List, or undecodable
+// and skipped by the DecodeErrorHandler.
+func (i *Index[T]) reembed(ctx context.Context, embed func(ctx context.Context, v Vector[T]) ([]float32, error), id uuid.UUID) (VectorRecord, bool, error) {
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	stored, info, err := i.provider.Get(callCtx, id)
+	cancel()
+	if errors.Is(err, ErrNotFound) {
+		return VectorRecord{}, false, nil
+	}
+	if err != nil {
+		return VectorRecord{}, false, i.wrapErr("reindex", id.String(), err)
+	}
+
+	var metadata T
+	if err := i.decodeMetadata(info.Metadata, &metadata); err != nil {
+		if err := handleDecodeErr(i.onDecodeErr, id.String(), info.Metadata, err); err != nil {
+			return VectorRecord{}, false, err
+		}
+		return VectorRecord{}, false, nil
+	}
+	i.redact.apply(&metadata)
+	if err := callAfterLoad(ctx, &metadata); err != nil {
+		return VectorRecord{}, false, err
+	}
+
+	vector, err := embed(ctx, Vector[T]{ID: id, Vector: stored, Metadata: metadata})
+	if err != nil {
+		return VectorRecord{}, false, i.wrapErr("reindex", id.String(), err)
+	}
+	if err := i.checkDimension(vector); err != nil {
+		return VectorRecord{}, false, i.wrapErr("reindex", id.String(), err)
+	}
+	if vector, err = i.normalize(vector); err != nil {
+		return VectorRecord{}, false, i.wrapErr("reindex", id.String(), err)
+	}
+	return VectorRecord{ID: id, Vector: vector, Metadata: info.Metadata}, true, nil
+}
@@ -0,0 +1,126 @@
+package grub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// batchCountingVectorProvider records the size of each UpsertBatch call.
+type batchCountingVectorProvider struct {
+	*mockVectorProvider
+	batches []int
+}
+
+func (p *batchCountingVectorProvider) UpsertBatch(ctx context.Context, vectors []VectorRecord) error {
+	p.batches = append(p.batches, len(vectors))
+	return p.mockVectorProvider.UpsertBatch(ctx, vectors)
+}
+
+func TestIndex_Reindex(t *testing.T) {
+	ctx := context.Background()
+	seed := func() *batchCountingVectorProvider {
+		provider := &batchCountingVectorProvider{mockVectorProvider: newMockVectorProvider()}
+		for n := 0; n < 5; n++ {
+			provider.vectors[uuid.New()] = vectorEntry{
+				vector:   []float32{float32(n), 0},
+				metadata: []byte(fmt.Sprintf(`{"category":"tech","score":%d}`, n)),
+			}
+		}
+		return provider
+	}
+	embed := func(_ context.Context, v Vector[testMetadata]) ([]float32, error) {
+		return []float32{float32(v.Metadata.Score), 1, 1}, nil
+	}
+
+	t.Run("recomputes vectors", func(t *testing.T) {
+		provider := seed()
+		before := make(map[uuid.UUID][]byte)
+		for id, entry := range provider.vectors {
+			before[id] = entry.metadata
+		}
+		index := NewIndex[testMetadata](provider)
+
+		var calls [][2]int
+		err := index.Reindex(ctx, embed, 2, func(done, total int) { calls = append(calls, [2]int{done, total}) })
+		if err != nil {
+			t.Fatalf("Reindex failed: %v", err)
+		}
+		for id, entry := range provider.vectors {
+			if string(entry.metadata) != string(before[id]) {
+				t.Errorf("%s: metadata changed: %s", id, entry.metadata)
+			}
+			var meta testMetadata
+			if err := json.Unmarshal(entry.metadata, &meta); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(entry.vector, []float32{float32(meta.Score), 1, 1}) {
+				t.Errorf("%s: expected re-embedded vector, got %v", id, entry.vector)
+			}
+		}
+		if !slices.Equal(provider.batches, []int{2, 2, 1}) {
+			t.Errorf("expected batches [2 2 1], got %v", provider.batches)
+		}
+		if !slices.Equal(calls, [][2]int{{2, 5}, {4, 5}, {5, 5}}) {
+			t.Errorf("unexpected progress calls %v", calls)
+		}
+	})
+
+	t.Run("default batch size", func(t *testing.T) {
+		provider := seed()
+		if err := NewIndex[testMetadata](provider).Reindex(ctx, embed, 0, nil); err != nil {
+			t.Fatalf("Reindex failed: %v", err)
+		}
+		if !slices.Equal(provider.batches, []int{5}) {
+			t.Errorf("expected a single batch of 5, got %v", provider.batches)
+		}
+	})
+
+	t.Run("embed error", func(t *testing.T) {
+		provider := seed()
+		boom := errors.New("model unavailable")
+		err := NewIndex[testMetadata](provider).Reindex(ctx, func(context.Context, Vector[testMetadata]) ([]float32, error) {
+			return nil, boom
+		}, 2, nil)
+		if !errors.Is(err, boom) {
+			t.Errorf("expected embed error, got %v", err)
+		}
+		if len(provider.batches) != 0 {
+			t.Errorf("expected nothing written, got %v", provider.batches)
+		}
+	})
+
+	t.Run("dimension checked", func(t *testing.T) {
+		provider := seed()
+		err := NewIndex[testMetadata](provider, WithDimension(2)).Reindex(ctx, embed, 2, nil)
+		if !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("expected ErrDimensionMismatch, got %v", err)
+		}
+	})
+
+	t.Run("undecodable skipped", func(t *testing.T) {
+		provider := seed()
+		bad := uuid.New()
+		provider.vectors[bad] = vectorEntry{vector: []float32{9, 9}, metadata: []byte(`{`)}
+		index := NewIndex[testMetadata](provider, WithDecodeErrorHandler(func(string, []byte, error) error { return nil }))
+		if err := index.Reindex(ctx, embed, 10, nil); err != nil {
+			t.Fatalf("Reindex failed: %v", err)
+		}
+		if got := provider.vectors[bad].vector; len(got) != 2 {
+			t.Errorf("expected undecodable vector left as is, got %v", got)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		if err := NewIndex[testMetadata](seed()).Reindex(canceled, embed, 2, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
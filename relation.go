@@ -0,0 +1,77 @@
+package grub
+
+import "context"
+
+// LoadBelongsTo resolves a many-to-one relation for a slice of records.
+// foreignKey returns the parent key each child refers to (empty for none);
+// the distinct keys are fetched from parents with a single GetBatch and
+// passed to assign. Children whose parent does not exist are left untouched,
+// so the relation field stays nil. Children sharing a parent receive the same
+// pointer.
+//
+//	err := grub.LoadBelongsTo(ctx, orders, users,
+//	    func(o *Order) string { return strconv.Itoa(o.UserID) },
+//	    func(o *Order, u *User) { o.User = u })
+func LoadBelongsTo[C, P any](ctx context.Context, children []*C, parents *Database[P], foreignKey func(*C) string, assign func(*C, *P)) error {
+	keys := make([]string, 0, len(children))
+	for _, c := range children {
+		if c == nil {
+			continue
+		}
+		if k := foreignKey(c); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	found, err := parents.GetBatch(ctx, keys)
+	if err != nil {
+		return err
+	}
+	for _, c := range children {
+		if c == nil {
+			continue
+		}
+		if p, ok := found[foreignKey(c)]; ok {
+			assign(c, p)
+		}
+	}
+	return nil
+}
+
+// LoadHasMany resolves a one-to-many relation for a slice of records.
+// Children are fetched from children in a single query matching column
+// against every parent's key, grouped by parentOf, and passed to assign.
+// assign is called once per parent, with nil when a parent has no children.
+//
+//	err := grub.LoadHasMany(ctx, users, orders, "user_id",
+//	    func(u *User) string { return strconv.Itoa(u.ID) },
+//	    func(o *Order) string { return strconv.Itoa(o.UserID) },
+//	    func(u *User, os []*Order) { u.Orders = os })
+func LoadHasMany[P, C any](ctx context.Context, parents []*P, children *Database[C], column string, key func(*P) string, parentOf func(*C) string, assign func(*P, []*C)) error {
+	keys := make([]string, 0, len(parents))
+	for _, p := range parents {
+		if p != nil {
+			keys = append(keys, key(p))
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	rows, err := children.selectIn(ctx, "load_has_many", column, keys)
+	if err != nil {
+		return err
+	}
+	grouped := make(map[string][]*C, len(keys))
+	for _, c := range rows {
+		k := parentOf(c)
+		grouped[k] = append(grouped[k], c)
+	}
+	for _, p := range parents {
+		if p != nil {
+			assign(p, grouped[key(p)])
+		}
+	}
+	return nil
+}
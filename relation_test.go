@@ -0,0 +1,140 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// testDBOrder belongs to a TestDBUser via UserID.
+type testDBOrder struct {
+	ID     int         `db:"id" constraints:"primarykey"`
+	UserID int         `db:"user_id"`
+	User   *TestDBUser `db:"-"`
+}
+
+func selectCount(capture *mockdb.Capture) int {
+	n := 0
+	for _, q := range capture.Queries {
+		if strings.HasPrefix(q.Query, "SELECT") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLoadBelongsTo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("one select for distinct keys", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		users, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		orders := make([]*testDBOrder, 50)
+		for i := range orders {
+			orders[i] = &testDBOrder{ID: i, UserID: i % 10}
+		}
+
+		err = LoadBelongsTo(ctx, orders, users,
+			func(o *testDBOrder) string { return strconv.Itoa(o.UserID) },
+			func(o *testDBOrder, u *TestDBUser) { o.User = u })
+		if err != nil {
+			t.Fatalf("LoadBelongsTo failed: %v", err)
+		}
+		if n := selectCount(capture); n != 1 {
+			t.Fatalf("expected exactly one SELECT, got %d", n)
+		}
+		query, _ := capture.Last()
+		if !strings.Contains(query.Query, `"test_users"`) {
+			t.Errorf("expected parent table in query, got: %s", query.Query)
+		}
+		if len(query.Args) != 10 {
+			t.Errorf("expected 10 distinct key params, got %d", len(query.Args))
+		}
+		// The mock returns no rows: missing parents leave the field nil.
+		for _, o := range orders {
+			if o.User != nil {
+				t.Fatal("expected User to stay nil for missing parent")
+			}
+		}
+	})
+
+	t.Run("no keys issues no query", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		users, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		orders := []*testDBOrder{nil, {ID: 1}}
+		err = LoadBelongsTo(ctx, orders, users,
+			func(*testDBOrder) string { return "" },
+			func(*testDBOrder, *TestDBUser) { t.Error("unexpected assign") })
+		if err != nil {
+			t.Fatalf("LoadBelongsTo failed: %v", err)
+		}
+		if len(capture.Queries) != 0 {
+			t.Errorf("expected no queries, got %d", len(capture.Queries))
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		users, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		queryErr := errors.New("connection reset")
+		cfg.SetQueryErr(queryErr)
+		defer cfg.Reset()
+
+		err = LoadBelongsTo(ctx, []*testDBOrder{{UserID: 1}}, users,
+			func(o *testDBOrder) string { return strconv.Itoa(o.UserID) },
+			func(*testDBOrder, *TestDBUser) {})
+		if !errors.Is(err, queryErr) {
+			t.Errorf("expected query error, got %v", err)
+		}
+	})
+}
+
+func TestLoadHasMany(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	orders, err := NewDatabase[testDBOrder](mockDB, "test_orders", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	users := make([]*TestDBUser, 25)
+	for i := range users {
+		users[i] = &TestDBUser{ID: i}
+	}
+
+	assigned := 0
+	err = LoadHasMany(ctx, users, orders, "user_id",
+		func(u *TestDBUser) string { return strconv.Itoa(u.ID) },
+		func(o *testDBOrder) string { return strconv.Itoa(o.UserID) },
+		func(_ *TestDBUser, os []*testDBOrder) {
+			assigned++
+			if os != nil {
+				t.Error("expected nil children from empty result")
+			}
+		})
+	if err != nil {
+		t.Fatalf("LoadHasMany failed: %v", err)
+	}
+	if n := selectCount(capture); n != 1 {
+		t.Fatalf("expected exactly one SELECT, got %d", n)
+	}
+	query, _ := capture.Last()
+	if !strings.Contains(query.Query, `"user_id"`) || !strings.Contains(query.Query, `"test_orders"`) {
+		t.Errorf("expected child table and foreign key in query, got: %s", query.Query)
+	}
+	if assigned != len(users) {
+		t.Errorf("expected assign per parent, got %d", assigned)
+	}
+}
@@ -0,0 +1,98 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	"github.com/zoobzio/edamame"
+)
+
+// conn is a connection pool and the executor bound to it.
+type conn[T any] struct {
+	db       *sqlx.DB
+	executor *edamame.Executor[T]
+	stmts    *stmtCache // nil unless WithStatementCache is set
+}
+
+// execer returns tx if set, otherwise the pool.
+func (c conn[T]) execer(tx *sqlx.Tx) sqlx.ExtContext {
+	if tx != nil {
+		return tx
+	}
+	return c.db
+}
+
+// replicaSet holds the read replicas of a Database, picked round-robin.
+type replicaSet[T any] struct {
+	conns []conn[T]
+	next  atomic.Uint64
+}
+
+// newReplicaSet builds an executor for each replica, running afterLoad on
+// every scanned record as the primary does.
+func newReplicaSet[T any](dbs []*sqlx.DB, table string, renderer astql.Renderer, o options, afterLoad func(context.Context, *T) error) (*replicaSet[T], error) {
+	set := &replicaSet[T]{conns: make([]conn[T], len(dbs))}
+	for n, db := range dbs {
+		if err := checkDialect(db, renderer); err != nil {
+			return nil, err
+		}
+		db, stmts := wrapPool(db, o)
+		exec, err := edamame.New[T](db, table, inRenderer{renderer})
+		if err != nil {
+			return nil, err
+		}
+		exec.Soy().OnScan(afterLoad)
+		set.conns[n] = conn[T]{db: db, executor: exec, stmts: stmts}
+	}
+	return set, nil
+}
+
+// pick returns the next replica in round-robin order.
+func (s *replicaSet[T]) pick() conn[T] {
+	n := s.next.Add(1) - 1
+	return s.conns[n%uint64(len(s.conns))]
+}
+
+type primaryReadsKey struct{}
+
+// WithPrimaryReads returns a context under which Database reads go to the
+// primary rather than a read replica, so a read following a write sees it
+// despite replication lag.
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// primary returns the primary connection.
+func (d *Database[T]) primary() conn[T] {
+	return conn[T]{db: d.db, executor: d.executor, stmts: d.stmts}
+}
+
+// read runs fn on the next read replica, retrying on the primary if the
+// replica fails with a connection error. Without replicas, or when ctx
+// carries WithPrimaryReads, fn runs on the primary.
+func read[T, R any](ctx context.Context, d *Database[T], fn func(c conn[T]) (R, error)) (R, error) {
+	if d.replicas == nil || ctx.Value(primaryReadsKey{}) != nil {
+		return fn(d.primary())
+	}
+	result, err := fn(d.replicas.pick())
+	if err != nil && ctx.Err() == nil && isConnError(err) {
+		return fn(d.primary())
+	}
+	return result, err
+}
+
+// isConnError reports whether err means the database could not be reached,
+// as opposed to a failure of the statement itself.
+func isConnError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
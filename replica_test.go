@@ -0,0 +1,146 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// replicaFixture is a Database over one primary and two replicas, each a
+// separate mockdb whose captured queries show which connection served a call.
+type replicaFixture struct {
+	db       *Database[TestDBUser]
+	primary  *mockdb.Capture
+	replicas [2]*mockdb.Capture
+	configs  [3]*mockdb.Config // primary, replica 0, replica 1
+}
+
+func newReplicaFixture(t *testing.T) *replicaFixture {
+	t.Helper()
+	f := &replicaFixture{}
+	var dbs [3]*sqlx.DB
+	captures := [3]*mockdb.Capture{}
+	for n := range dbs {
+		dbs[n], captures[n], f.configs[n] = mockdb.NewIsolated()
+		f.configs[n].SetRows([]string{"id", "email", "name", "age"},
+			[]driver.Value{int64(1), "a@example.com", "Alice", nil})
+		f.configs[n].SetQueryRows("EXISTS", []string{"exists"}, []driver.Value{true})
+	}
+	f.primary, f.replicas = captures[0], [2]*mockdb.Capture{captures[1], captures[2]}
+	db, err := NewDatabase[TestDBUser](dbs[0], "test_users", testDBRenderer, WithReadReplicas(dbs[1], dbs[2]))
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	f.db = db
+	return f
+}
+
+// counts returns how many queries the primary and each replica served.
+func (f *replicaFixture) counts() [3]int {
+	return [3]int{len(f.primary.Queries), len(f.replicas[0].Queries), len(f.replicas[1].Queries)}
+}
+
+func TestDatabase_ReadReplicas(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reads round-robin", func(t *testing.T) {
+		f := newReplicaFixture(t)
+		reads := []func() error{
+			func() error { _, err := f.db.Get(ctx, "1"); return err },
+			func() error { _, err := f.db.Exists(ctx, "1"); return err },
+			func() error { _, err := f.db.ExecQuery(ctx, QueryAll, nil); return err },
+			func() error { _, err := f.db.GetByKey(ctx, 1); return err },
+		}
+		for _, read := range reads {
+			if err := read(); err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+		}
+		if got := f.counts(); got != [3]int{0, 2, 2} {
+			t.Errorf("expected reads split across replicas, got primary/replica counts %v", got)
+		}
+	})
+
+	t.Run("writes and transactions use primary", func(t *testing.T) {
+		f := newReplicaFixture(t)
+		if err := f.db.Set(ctx, "1", &TestDBUser{ID: 1, Email: "a@example.com", Name: "Alice"}); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if err := f.db.Delete(ctx, "1"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		err := f.db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			if _, err := f.db.GetTx(ctx, tx, "1"); err != nil {
+				return err
+			}
+			_, err := f.db.ExecQueryTx(ctx, tx, QueryAll, nil)
+			return err
+		}, nil)
+		if err != nil {
+			t.Fatalf("transaction failed: %v", err)
+		}
+		if got := f.counts(); got[0] == 0 || got[1] != 0 || got[2] != 0 {
+			t.Errorf("expected everything on the primary, got primary/replica counts %v", got)
+		}
+	})
+
+	t.Run("primary reads", func(t *testing.T) {
+		f := newReplicaFixture(t)
+		if _, err := f.db.Get(WithPrimaryReads(ctx), "1"); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if _, err := f.db.ExecQuery(WithPrimaryReads(ctx), QueryAll, nil); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		if got := f.counts(); got != [3]int{2, 0, 0} {
+			t.Errorf("expected reads on the primary, got primary/replica counts %v", got)
+		}
+	})
+
+	t.Run("fallback on connection failure", func(t *testing.T) {
+		f := newReplicaFixture(t)
+		f.configs[1].SetQueryErr(&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET})
+		for range 2 {
+			if _, err := f.db.Get(ctx, "1"); err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+		}
+		if got := f.counts(); got != [3]int{1, 1, 1} {
+			t.Errorf("expected the failed replica read retried on the primary, got primary/replica counts %v", got)
+		}
+	})
+
+	t.Run("no fallback on query error", func(t *testing.T) {
+		f := newReplicaFixture(t)
+		queryErr := errors.New("syntax error")
+		f.configs[1].SetQueryErr(queryErr)
+		if _, err := f.db.Get(ctx, "1"); !errors.Is(err, queryErr) {
+			t.Errorf("expected the replica's error, got %v", err)
+		}
+		if got := f.counts(); got != [3]int{0, 1, 0} {
+			t.Errorf("expected no retry, got primary/replica counts %v", got)
+		}
+	})
+}
+
+func TestIsConnError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{driver.ErrBadConn, true},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{errors.New("relation does not exist"), false},
+		{context.Canceled, false},
+	} {
+		if got := isConnError(tc.err); got != tc.want {
+			t.Errorf("%v: expected %v, got %v", tc.err, tc.want, got)
+		}
+	}
+}
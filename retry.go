@@ -0,0 +1,65 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// IsRetryable reports whether err is a transient conflict a write may be
+// retried after: a serialization failure or deadlock (Postgres 40001 and
+// 40P01, SQL Server 1205 and 3960, MySQL 1213). It is the default check of
+// WithWriteRetry.
+func IsRetryable(err error) bool {
+	return errors.Is(classifySerialization(err), ErrSerializationFailure)
+}
+
+// writeRetry retries a Database write that failed with a transient
+// conflict.
+type writeRetry struct {
+	attempts  int
+	backoff   time.Duration
+	retryable func(error) bool
+}
+
+// newWriteRetry returns the retry configured by WithWriteRetry, or nil
+// without it.
+func newWriteRetry(o options) *writeRetry {
+	if o.writeAttempts <= 1 {
+		return nil
+	}
+	retryable := o.retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	return &writeRetry{attempts: o.writeAttempts, backoff: o.writeBackoff, retryable: retryable}
+}
+
+// do runs write until it succeeds, fails with an error retryable rejects,
+// or the attempts are spent, returning its last error. The wait starts at
+// the backoff and doubles after each attempt. A nil retry, or a ctx
+// carrying a transaction, runs write once: the conflict aborted that
+// transaction, so only retrying all of it can help.
+func (r *writeRetry) do(ctx context.Context, write func() error) error {
+	if r == nil {
+		return write()
+	}
+	if _, ok := TxFromContext(ctx); ok {
+		return write()
+	}
+	wait := r.backoff
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if err == nil || attempt >= r.attempts || !r.retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
@@ -0,0 +1,154 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+var errDeadlock = &fakePQError{code: "40P01"}
+
+// execCount counts the captured statements beginning with prefix.
+func execCount(capture *mockdb.Capture, prefix string) int {
+	var n int
+	for _, q := range capture.Queries {
+		if strings.HasPrefix(q.Query, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"postgres deadlock", errDeadlock, true},
+		{"postgres serialization", &fakePQError{code: "40001"}, true},
+		{"mssql deadlock victim", fakeMSSQLError{number: 1205, msg: "deadlock"}, true},
+		{"mysql deadlock", errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), true},
+		{"wrapped", ErrSerializationFailure, true},
+		{"unique violation", &fakePQError{code: "23505"}, false},
+		{"other", errors.New("connection refused"), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDatabase_WriteRetry(t *testing.T) {
+	ctx := context.Background()
+	user := &TestDBUser{ID: 1, Email: "a@example.com", Name: "Alice"}
+	// newRetryDB returns a Database whose upserts return user once the
+	// configured query errors are spent.
+	newRetryDB := func(t *testing.T, opts ...Option) (*Database[TestDBUser], *mockdb.Capture, *mockdb.Config) {
+		t.Helper()
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRows(userColumns, []driver.Value{int64(1), "a@example.com", "Alice", nil})
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, opts...)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		return db, capture, cfg
+	}
+
+	t.Run("set retries a deadlock", func(t *testing.T) {
+		db, capture, cfg := newRetryDB(t, WithWriteRetry(3, time.Millisecond))
+		cfg.SetQueryErrTimes(errDeadlock, 2)
+		if err := db.Set(ctx, "1", user); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if n := execCount(capture, "INSERT"); n != 3 {
+			t.Errorf("expected 3 attempts, got %d", n)
+		}
+	})
+
+	t.Run("gives up after the attempts", func(t *testing.T) {
+		db, capture, cfg := newRetryDB(t, WithWriteRetry(3, time.Millisecond))
+		cfg.SetQueryErr(errDeadlock)
+		if err := db.Set(ctx, "1", user); !errors.Is(err, ErrSerializationFailure) {
+			t.Fatalf("expected ErrSerializationFailure, got %v", err)
+		}
+		if n := execCount(capture, "INSERT"); n != 3 {
+			t.Errorf("expected 3 attempts, got %d", n)
+		}
+	})
+
+	t.Run("non-transient errors surface immediately", func(t *testing.T) {
+		db, capture, cfg := newRetryDB(t, WithWriteRetry(3, time.Millisecond))
+		cfg.SetQueryErr(&fakePQError{code: "23505", constraint: "users_email_key"})
+		if err := db.Set(ctx, "1", user); !errors.Is(err, ErrDuplicate) {
+			t.Fatalf("expected ErrDuplicate, got %v", err)
+		}
+		if n := execCount(capture, "INSERT"); n != 1 {
+			t.Errorf("expected 1 attempt, got %d", n)
+		}
+	})
+
+	t.Run("delete retries", func(t *testing.T) {
+		db, capture, cfg := newRetryDB(t, WithWriteRetry(2, time.Millisecond))
+		cfg.SetExecErrTimes(errDeadlock, 1)
+		if err := db.Delete(ctx, "1"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if n := execCount(capture, "DELETE"); n != 2 {
+			t.Errorf("expected 2 attempts, got %d", n)
+		}
+	})
+
+	t.Run("custom retryable", func(t *testing.T) {
+		errBusy := errors.New("database is locked")
+		db, capture, cfg := newRetryDB(t, WithWriteRetry(2, time.Millisecond),
+			WithRetryable(func(err error) bool { return errors.Is(err, errBusy) }))
+		cfg.SetQueryErrTimes(errBusy, 1)
+		if err := db.Set(ctx, "1", user); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if n := execCount(capture, "INSERT"); n != 2 {
+			t.Errorf("expected 2 attempts, got %d", n)
+		}
+	})
+
+	t.Run("transactions are not retried", func(t *testing.T) {
+		db, capture, cfg := newRetryDB(t, WithWriteRetry(3, time.Millisecond))
+		cfg.SetQueryErr(errDeadlock)
+		err := db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			if err := db.SetTx(ctx, tx, "1", user); err == nil {
+				t.Error("expected SetTx to fail")
+			}
+			return db.Set(ContextWithTx(ctx, tx), "1", user)
+		}, nil)
+		if !errors.Is(err, ErrSerializationFailure) {
+			t.Fatalf("expected ErrSerializationFailure, got %v", err)
+		}
+		if n := execCount(capture, "INSERT"); n != 2 {
+			t.Errorf("expected one attempt each, got %d", n)
+		}
+	})
+
+	t.Run("stops with the context", func(t *testing.T) {
+		db, capture, cfg := newRetryDB(t, WithWriteRetry(5, time.Hour))
+		cfg.SetQueryErr(errDeadlock)
+		cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		if err := db.Set(cctx, "1", user); !errors.Is(err, ErrSerializationFailure) {
+			t.Fatalf("expected the last write error, got %v", err)
+		}
+		if n := execCount(capture, "INSERT"); n != 1 {
+			t.Errorf("expected 1 attempt, got %d", n)
+		}
+	})
+}
@@ -5,7 +5,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -19,6 +25,12 @@ type Provider struct {
 	bucket string
 }
 
+// ExpiryTag is the object tag Put sets from ObjectInfo.ExpiresAt to the
+// whole days, rounded up, until the object should expire. S3 only deletes
+// objects by lifecycle rule, so the bucket needs a rule for every day count
+// in use; ExpiryRules builds them.
+const ExpiryTag = "grub-expire-days"
+
 // New creates an S3 provider with the given client and bucket name.
 func New(client *s3.Client, bucket string) *Provider {
 	return &Provider{
@@ -60,11 +72,188 @@ func (p *Provider) Get(ctx context.Context, key string) ([]byte, *grub.ObjectInf
 
 // Put stores data at key with associated metadata.
 func (p *Provider) Put(ctx context.Context, key string, data []byte, info *grub.ObjectInfo) error {
+	_, err := p.PutInfo(ctx, key, data, info)
+	return err
+}
+
+// PutInfo stores data at key and returns the ETag and version ID from the
+// PutObject response. S3 does not report a last-modified time on put.
+func (p *Provider) PutInfo(ctx context.Context, key string, data []byte, info *grub.ObjectInfo) (*grub.ObjectInfo, error) {
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(p.bucket),
 		Key:    aws.String(key),
 		Body:   bytes.NewReader(data),
 	}
+	result := &grub.ObjectInfo{Key: key, Size: int64(len(data))}
+	if info != nil {
+		if info.ContentType != "" {
+			input.ContentType = aws.String(info.ContentType)
+		}
+		if len(info.Metadata) > 0 {
+			input.Metadata = info.Metadata
+		}
+		if !info.ExpiresAt.IsZero() {
+			input.Tagging = aws.String(url.Values{ExpiryTag: {strconv.Itoa(expiryDays(info.ExpiresAt))}}.Encode())
+		}
+		result.ContentType = info.ContentType
+		result.Metadata = info.Metadata
+		result.ExpiresAt = info.ExpiresAt
+	}
+	output, err := p.client.PutObject(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	result.ETag = aws.ToString(output.ETag)
+	result.VersionID = aws.ToString(output.VersionId)
+	return result, nil
+}
+
+// ExpiryPrecision implements grub.BucketExpirer. Lifecycle rules count
+// whole days and run once a day, so an object may outlive its ExpiresAt by
+// up to two days.
+func (p *Provider) ExpiryPrecision() time.Duration {
+	return 48 * time.Hour
+}
+
+// ExpiryRules returns the lifecycle rules deleting objects tagged by Put
+// with ExpiresAt up to maxDays away, one rule per day count. Add them to the
+// bucket's lifecycle configuration; objects expiring further out are kept
+// until a rule for their day count exists.
+func ExpiryRules(maxDays int) []types.LifecycleRule {
+	rules := make([]types.LifecycleRule, 0, maxDays)
+	for days := 1; days <= maxDays; days++ {
+		rules = append(rules, types.LifecycleRule{
+			ID:     aws.String(fmt.Sprintf("%s-%d", ExpiryTag, days)),
+			Status: types.ExpirationStatusEnabled,
+			Filter: &types.LifecycleRuleFilter{
+				Tag: &types.Tag{Key: aws.String(ExpiryTag), Value: aws.String(strconv.Itoa(days))},
+			},
+			Expiration: &types.LifecycleExpiration{Days: aws.Int32(int32(days))},
+		})
+	}
+	return rules
+}
+
+// expiryDays returns the whole days, at least one, from now until at.
+func expiryDays(at time.Time) int {
+	return max(int(math.Ceil(time.Until(at).Hours()/24)), 1)
+}
+
+// ListVersions returns every version of key, including delete markers,
+// newest first. The bucket must have versioning enabled; an unversioned
+// bucket reports a single version with the ID "null".
+func (p *Provider) ListVersions(ctx context.Context, key string) ([]grub.ObjectVersion, error) {
+	var results []grub.ObjectVersion
+	var keyMarker, versionMarker *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		output, err := p.client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+			Bucket:          aws.String(p.bucket),
+			Prefix:          aws.String(key),
+			KeyMarker:       keyMarker,
+			VersionIdMarker: versionMarker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Prefix also matches longer keys; keep only exact matches.
+		for _, v := range output.Versions {
+			if aws.ToString(v.Key) != key {
+				continue
+			}
+			results = append(results, grub.ObjectVersion{
+				Key:          key,
+				VersionID:    aws.ToString(v.VersionId),
+				Size:         aws.ToInt64(v.Size),
+				ETag:         aws.ToString(v.ETag),
+				LastModified: aws.ToTime(v.LastModified),
+				IsLatest:     aws.ToBool(v.IsLatest),
+			})
+		}
+		for _, m := range output.DeleteMarkers {
+			if aws.ToString(m.Key) != key {
+				continue
+			}
+			results = append(results, grub.ObjectVersion{
+				Key:            key,
+				VersionID:      aws.ToString(m.VersionId),
+				LastModified:   aws.ToTime(m.LastModified),
+				IsLatest:       aws.ToBool(m.IsLatest),
+				IsDeleteMarker: true,
+			})
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			break
+		}
+		keyMarker = output.NextKeyMarker
+		versionMarker = output.NextVersionIdMarker
+	}
+
+	if len(results) == 0 {
+		return nil, grub.ErrNotFound
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].LastModified.After(results[j].LastModified)
+	})
+	return results, nil
+}
+
+// GetVersion retrieves a specific version of the blob at key.
+func (p *Provider) GetVersion(ctx context.Context, key, versionID string) ([]byte, *grub.ObjectInfo, error) {
+	output, err := p.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:    aws.String(p.bucket),
+		Key:       aws.String(key),
+		VersionId: aws.String(versionID),
+	})
+	if err != nil {
+		if isMissingVersion(err) {
+			return nil, nil, grub.ErrNotFound
+		}
+		return nil, nil, err
+	}
+	defer func() { _ = output.Body.Close() }()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &grub.ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(output.ContentLength),
+		ContentType:  aws.ToString(output.ContentType),
+		ETag:         aws.ToString(output.ETag),
+		Metadata:     output.Metadata,
+		VersionID:    aws.ToString(output.VersionId),
+		LastModified: aws.ToTime(output.LastModified),
+	}
+
+	return data, info, nil
+}
+
+// isMissingVersion reports whether err means the key or version does not
+// exist. S3 has no modeled error type for NoSuchVersion, so the API error
+// code is checked directly.
+func isMissingVersion(err error) bool {
+	var nsk *types.NoSuchKey
+	if errors.As(err, &nsk) {
+		return true
+	}
+	var coded interface{ ErrorCode() string }
+	return errors.As(err, &coded) && coded.ErrorCode() == "NoSuchVersion"
+}
+
+// CreateMultipartUpload starts a multipart upload to key.
+func (p *Provider) CreateMultipartUpload(ctx context.Context, key string, info *grub.ObjectInfo) (string, error) {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(p.bucket),
+		Key:    aws.String(key),
+	}
 	if info != nil {
 		if info.ContentType != "" {
 			input.ContentType = aws.String(info.ContentType)
@@ -73,10 +262,80 @@ func (p *Provider) Put(ctx context.Context, key string, data []byte, info *grub.
 			input.Metadata = info.Metadata
 		}
 	}
-	_, err := p.client.PutObject(ctx, input)
+	output, err := p.client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.UploadId), nil
+}
+
+// UploadPart uploads part n of an upload. The part is buffered in memory so
+// the SDK can sign a seekable body.
+func (p *Provider) UploadPart(ctx context.Context, key, uploadID string, n int, r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	output, err := p.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(p.bucket),
+		Key:        aws.String(key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(int32(n)), //nolint:gosec // part numbers are bounded to 1-10000 by grub
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.ETag), nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the object.
+func (p *Provider) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []grub.CompletedPart) (*grub.ObjectInfo, error) {
+	completed := make([]types.CompletedPart, len(parts))
+	for i, part := range parts {
+		completed[i] = types.CompletedPart{
+			PartNumber: aws.Int32(int32(part.Number)), //nolint:gosec // part numbers are bounded to 1-10000 by grub
+			ETag:       aws.String(part.ETag),
+		}
+	}
+	output, err := p.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(p.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		if isMissingUpload(err) {
+			return nil, grub.ErrNotFound
+		}
+		return nil, err
+	}
+	return &grub.ObjectInfo{
+		Key:       key,
+		ETag:      aws.ToString(output.ETag),
+		VersionID: aws.ToString(output.VersionId),
+	}, nil
+}
+
+// AbortMultipartUpload discards an upload and its stored parts.
+func (p *Provider) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := p.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(p.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil && isMissingUpload(err) {
+		return grub.ErrNotFound
+	}
 	return err
 }
 
+// isMissingUpload reports whether err means the multipart upload does not exist.
+func isMissingUpload(err error) bool {
+	var nsu *types.NoSuchUpload
+	return errors.As(err, &nsu)
+}
+
 // Delete removes the blob at key.
 func (p *Provider) Delete(ctx context.Context, key string) error {
 	// S3 DeleteObject doesn't return an error if the key doesn't exist.
@@ -96,6 +355,51 @@ func (p *Provider) Delete(ctx context.Context, key string) error {
 	return err
 }
 
+// DeletePrefix removes every object under prefix with DeleteObjects, one
+// call per listing page of up to 1000 keys (the DeleteObjects maximum).
+// Cancellation is checked between pages. A key S3 fails to delete stops
+// the deletion; the count covers the keys removed before it.
+func (p *Provider) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var deleted int64
+	var continuationToken *string
+	for {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		output, err := p.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(p.bucket),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return deleted, err
+		}
+		if len(output.Contents) > 0 {
+			ids := make([]types.ObjectIdentifier, len(output.Contents))
+			for n, obj := range output.Contents {
+				ids[n] = types.ObjectIdentifier{Key: obj.Key}
+			}
+			result, err := p.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: aws.String(p.bucket),
+				Delete: &types.Delete{Objects: ids, Quiet: aws.Bool(true)},
+			})
+			if err != nil {
+				return deleted, err
+			}
+			deleted += int64(len(ids) - len(result.Errors))
+			if len(result.Errors) > 0 {
+				failed := result.Errors[0]
+				return deleted, fmt.Errorf("s3: deleting %q: %s: %s",
+					aws.ToString(failed.Key), aws.ToString(failed.Code), aws.ToString(failed.Message))
+			}
+		}
+		if !aws.ToBool(output.IsTruncated) {
+			return deleted, nil
+		}
+		continuationToken = output.NextContinuationToken
+	}
+}
+
 // Exists checks whether a key exists.
 func (p *Provider) Exists(ctx context.Context, key string) (bool, error) {
 	_, err := p.client.HeadObject(ctx, &s3.HeadObjectInput{
@@ -112,12 +416,40 @@ func (p *Provider) Exists(ctx context.Context, key string) (bool, error) {
 	return true, nil
 }
 
+// Head returns the info for key, including its user metadata, without
+// downloading the body.
+func (p *Provider) Head(ctx context.Context, key string) (*grub.ObjectInfo, error) {
+	output, err := p.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(p.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var nf *types.NotFound
+		if errors.As(err, &nf) {
+			return nil, grub.ErrNotFound
+		}
+		return nil, err
+	}
+	return &grub.ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(output.ContentLength),
+		ContentType:  aws.ToString(output.ContentType),
+		ETag:         aws.ToString(output.ETag),
+		Metadata:     output.Metadata,
+		VersionID:    aws.ToString(output.VersionId),
+		LastModified: aws.ToTime(output.LastModified),
+	}, nil
+}
+
 // List returns object info for keys matching the given prefix.
 func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.ObjectInfo, error) {
 	var results []grub.ObjectInfo
 	var continuationToken *string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		input := &s3.ListObjectsV2Input{
 			Bucket: aws.String(p.bucket),
 			Prefix: aws.String(prefix),
@@ -136,11 +468,7 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.O
 		}
 
 		for _, obj := range output.Contents {
-			results = append(results, grub.ObjectInfo{
-				Key:  aws.ToString(obj.Key),
-				Size: aws.ToInt64(obj.Size),
-				ETag: aws.ToString(obj.ETag),
-			})
+			results = append(results, objectInfo(obj))
 			if limit > 0 && len(results) >= limit {
 				return results, nil
 			}
@@ -154,3 +482,43 @@ func (p *Provider) List(ctx context.Context, prefix string, limit int) ([]grub.O
 
 	return results, nil
 }
+
+// ListPage returns up to limit objects under prefix, resuming from an S3
+// continuation token. Pages are capped at 1000 objects, the ListObjectsV2
+// maximum; the next cursor is empty once the listing is not truncated.
+func (p *Provider) ListPage(ctx context.Context, prefix string, limit int, cursor string) ([]grub.ObjectInfo, string, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(p.bucket),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int32(int32(limit)), //nolint:gosec // bounded to [1, 1000]
+	}
+	if cursor != "" {
+		input.ContinuationToken = aws.String(cursor)
+	}
+	output, err := p.client.ListObjectsV2(ctx, input)
+	if err != nil {
+		return nil, "", err
+	}
+	results := make([]grub.ObjectInfo, 0, len(output.Contents))
+	for _, obj := range output.Contents {
+		results = append(results, objectInfo(obj))
+	}
+	if !aws.ToBool(output.IsTruncated) {
+		return results, "", nil
+	}
+	return results, aws.ToString(output.NextContinuationToken), nil
+}
+
+// objectInfo converts a listed object. Listings carry no content type or
+// user metadata; those need a HeadObject per key.
+func objectInfo(obj types.Object) grub.ObjectInfo {
+	return grub.ObjectInfo{
+		Key:          aws.ToString(obj.Key),
+		Size:         aws.ToInt64(obj.Size),
+		ETag:         aws.ToString(obj.ETag),
+		LastModified: aws.ToTime(obj.LastModified),
+	}
+}
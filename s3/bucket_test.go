@@ -3,15 +3,23 @@ package s3
 import (
 	"bytes"
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"github.com/zoobzio/grub"
@@ -195,6 +203,159 @@ func TestProvider_Put(t *testing.T) {
 	})
 }
 
+func TestProvider_PutInfo(t *testing.T) {
+	clearBucket(t)
+	ctx := context.Background()
+
+	data := []byte("receipt content")
+	info, err := testProvider.PutInfo(ctx, "put-info", data, &grub.ObjectInfo{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("PutInfo failed: %v", err)
+	}
+	if info.Key != "put-info" {
+		t.Errorf("unexpected key: %q", info.Key)
+	}
+	if info.Size != int64(len(data)) {
+		t.Errorf("expected size %d, got %d", len(data), info.Size)
+	}
+	if info.ETag == "" {
+		t.Error("expected ETag to be reported")
+	}
+}
+
+func TestProvider_PutExpiresAt(t *testing.T) {
+	clearBucket(t)
+	ctx := context.Background()
+
+	expires := time.Now().Add(36 * time.Hour)
+	if err := testProvider.Put(ctx, "expiring", []byte("tmp"), &grub.ObjectInfo{ExpiresAt: expires}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	tagging, err := testS3Client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(testBucket),
+		Key:    aws.String("expiring"),
+	})
+	if err != nil {
+		t.Fatalf("GetObjectTagging failed: %v", err)
+	}
+	if len(tagging.TagSet) != 1 || aws.ToString(tagging.TagSet[0].Key) != ExpiryTag || aws.ToString(tagging.TagSet[0].Value) != "2" {
+		t.Errorf("expected %s=2, got %v", ExpiryTag, tagging.TagSet)
+	}
+
+	rules := ExpiryRules(3)
+	if len(rules) != 3 || aws.ToInt32(rules[1].Expiration.Days) != 2 || aws.ToString(rules[1].Filter.Tag.Value) != "2" {
+		t.Errorf("unexpected rules %+v", rules)
+	}
+	_, err = testS3Client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(testBucket),
+		LifecycleConfiguration: &types.BucketLifecycleConfiguration{Rules: rules},
+	})
+	if err != nil {
+		t.Errorf("expected the rules to be accepted, got %v", err)
+	}
+}
+
+func TestProvider_Versions(t *testing.T) {
+	ctx := context.Background()
+	const versionedBucket = "test-versioned"
+
+	_, err := testS3Client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(versionedBucket)})
+	if err != nil {
+		t.Fatalf("failed to create bucket: %v", err)
+	}
+	_, err = testS3Client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket:                  aws.String(versionedBucket),
+		VersioningConfiguration: &types.VersioningConfiguration{Status: types.BucketVersioningStatusEnabled},
+	})
+	if err != nil {
+		t.Fatalf("failed to enable versioning: %v", err)
+	}
+	provider := New(testS3Client, versionedBucket)
+
+	var ids []string
+	for _, body := range []string{"first", "second"} {
+		info, err := provider.PutInfo(ctx, "doc", []byte(body), nil)
+		if err != nil {
+			t.Fatalf("PutInfo failed: %v", err)
+		}
+		ids = append(ids, info.VersionID)
+	}
+
+	versions, err := provider.ListVersions(ctx, "doc")
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(versions))
+	}
+
+	data, info, err := provider.GetVersion(ctx, "doc", ids[0])
+	if err != nil {
+		t.Fatalf("GetVersion failed: %v", err)
+	}
+	if string(data) != "first" || info.VersionID != ids[0] {
+		t.Errorf("expected first version, got %q (%s)", data, info.VersionID)
+	}
+
+	if _, err := provider.ListVersions(ctx, "missing"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestProvider_MultipartUpload(t *testing.T) {
+	clearBucket(t)
+	ctx := context.Background()
+
+	uploadID, err := testProvider.CreateMultipartUpload(ctx, "multipart", &grub.ObjectInfo{ContentType: "text/plain"})
+	if err != nil {
+		t.Fatalf("CreateMultipartUpload failed: %v", err)
+	}
+
+	// Every part but the last must be at least 5 MiB.
+	first := bytes.Repeat([]byte("a"), 5<<20)
+	etag1, err := testProvider.UploadPart(ctx, "multipart", uploadID, 1, bytes.NewReader(first))
+	if err != nil {
+		t.Fatalf("UploadPart 1 failed: %v", err)
+	}
+	etag2, err := testProvider.UploadPart(ctx, "multipart", uploadID, 2, bytes.NewReader([]byte("tail")))
+	if err != nil {
+		t.Fatalf("UploadPart 2 failed: %v", err)
+	}
+
+	info, err := testProvider.CompleteMultipartUpload(ctx, "multipart", uploadID, []grub.CompletedPart{
+		{Number: 1, ETag: etag1},
+		{Number: 2, ETag: etag2},
+	})
+	if err != nil {
+		t.Fatalf("CompleteMultipartUpload failed: %v", err)
+	}
+	if info.ETag == "" {
+		t.Error("expected ETag to be reported")
+	}
+
+	data, _, err := testProvider.Get(ctx, "multipart")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(data) != len(first)+4 || string(data[len(first):]) != "tail" {
+		t.Errorf("unexpected assembled object of %d bytes", len(data))
+	}
+
+	t.Run("abort", func(t *testing.T) {
+		id, err := testProvider.CreateMultipartUpload(ctx, "aborted", nil)
+		if err != nil {
+			t.Fatalf("CreateMultipartUpload failed: %v", err)
+		}
+		if err := testProvider.AbortMultipartUpload(ctx, "aborted", id); err != nil {
+			t.Fatalf("AbortMultipartUpload failed: %v", err)
+		}
+		_, err = testProvider.CompleteMultipartUpload(ctx, "aborted", id, nil)
+		if !errors.Is(err, grub.ErrNotFound) {
+			t.Errorf("expected ErrNotFound after abort, got %v", err)
+		}
+	})
+}
+
 func TestProvider_Delete(t *testing.T) {
 	clearBucket(t)
 	ctx := context.Background()
@@ -221,6 +382,95 @@ func TestProvider_Delete(t *testing.T) {
 	})
 }
 
+// fakeS3 serves the ListObjectsV2 and DeleteObjects calls DeletePrefix
+// makes, recording the size of each delete batch.
+type fakeS3 struct {
+	mu      sync.Mutex
+	keys    map[string]bool
+	batches []int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodGet && q.Get("list-type") == "2":
+		type content struct {
+			Key  string
+			Size int64
+		}
+		type listResult struct {
+			XMLName               xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+			Contents              []content
+			IsTruncated           bool
+			NextContinuationToken string `xml:",omitempty"`
+		}
+		var keys []string
+		for key := range f.keys {
+			if strings.HasPrefix(key, q.Get("prefix")) && key > q.Get("continuation-token") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		var result listResult
+		if len(keys) > 1000 {
+			keys = keys[:1000]
+			result.IsTruncated = true
+			result.NextContinuationToken = keys[999]
+		}
+		for _, key := range keys {
+			result.Contents = append(result.Contents, content{Key: key, Size: 1})
+		}
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodPost && q.Has("delete"):
+		var req struct {
+			Objects []struct{ Key string } `xml:"Object"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.batches = append(f.batches, len(req.Objects))
+		for _, obj := range req.Objects {
+			delete(f.keys, obj.Key)
+		}
+		_, _ = w.Write([]byte(`<DeleteResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></DeleteResult>`))
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestProvider_DeletePrefix(t *testing.T) {
+	fake := &fakeS3{keys: make(map[string]bool)}
+	for i := 0; i < 2500; i++ {
+		fake.keys[fmt.Sprintf("tmp/exports/2023-%04d", i)] = true
+	}
+	fake.keys["tmp/exports/2024-0001"] = true
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	client := s3.New(s3.Options{
+		BaseEndpoint: aws.String(srv.URL),
+		Region:       "us-east-1",
+		UsePathStyle: true,
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	n, err := New(client, testBucket).DeletePrefix(context.Background(), "tmp/exports/2023-")
+	if err != nil {
+		t.Fatalf("DeletePrefix failed: %v", err)
+	}
+	if n != 2500 {
+		t.Errorf("expected 2500 deleted, got %d", n)
+	}
+	if fmt.Sprint(fake.batches) != "[1000 1000 500]" {
+		t.Errorf("expected batches of at most 1000, got %v", fake.batches)
+	}
+	if len(fake.keys) != 1 || !fake.keys["tmp/exports/2024-0001"] {
+		t.Errorf("expected only the other prefix left, got %d keys", len(fake.keys))
+	}
+}
+
 func TestProvider_Exists(t *testing.T) {
 	clearBucket(t)
 	ctx := context.Background()
@@ -0,0 +1,255 @@
+package grub
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// MetadataKind is the JSON kind of a metadata field.
+type MetadataKind string
+
+// Metadata kinds reported in a MetadataSchema.
+const (
+	MetadataString  MetadataKind = "string"
+	MetadataInteger MetadataKind = "integer"
+	MetadataNumber  MetadataKind = "number"
+	MetadataBool    MetadataKind = "bool"
+	MetadataTime    MetadataKind = "time" // RFC 3339 string
+	MetadataArray   MetadataKind = "array"
+	MetadataObject  MetadataKind = "object"
+	MetadataAny     MetadataKind = "any" // custom JSON encoding; not checked
+)
+
+// MetadataField describes one top-level field of encoded metadata.
+type MetadataField struct {
+	Name     string       // JSON key
+	Kind     MetadataKind // kind of the value
+	Elem     MetadataKind // element kind when Kind is MetadataArray
+	Nullable bool         // may encode as null (pointer, slice, or map)
+	Optional bool         // may be absent (omitempty)
+}
+
+// MetadataSchema is the set of fields metadata of type T encodes to, derived
+// from T's exported fields and json tags. Providers can use it to create
+// payload indexes or class properties.
+type MetadataSchema struct {
+	Fields []MetadataField
+}
+
+// Field returns the field encoded under name.
+func (s MetadataSchema) Field(name string) (MetadataField, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return MetadataField{}, false
+}
+
+// Schema returns the metadata schema derived from T. It is empty when T is
+// not a struct.
+func (i *Index[T]) Schema() MetadataSchema {
+	return i.schema
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// deriveSchema builds the MetadataSchema of T, naming fields by tag.
+func deriveSchema[T any](tag string) MetadataSchema {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return MetadataSchema{}
+	}
+	var s MetadataSchema
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name, ok := taggedName(f, tag)
+		if !ok {
+			continue
+		}
+		optional := hasTagOption(f, "json", "omitempty") || hasTagOption(f, tag, "omitempty")
+		field := MetadataField{Name: name, Optional: optional}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			field.Nullable = true
+			ft = ft.Elem()
+		}
+		if throughPointer(t, f.Index) {
+			field.Optional = true
+		}
+		field.Kind, field.Elem = schemaKind(ft)
+		if ft.Kind() == reflect.Slice || ft.Kind() == reflect.Map {
+			field.Nullable = true
+		}
+		s.Fields = append(s.Fields, field)
+	}
+	return s
+}
+
+// schemaKind returns the metadata kind values of t encode to, and the
+// element kind for arrays.
+func schemaKind(t reflect.Type) (MetadataKind, MetadataKind) {
+	switch {
+	case t == timeType:
+		return MetadataTime, ""
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return MetadataAny, ""
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return MetadataString, ""
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return MetadataString, ""
+	case reflect.Bool:
+		return MetadataBool, ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return MetadataInteger, ""
+	case reflect.Float32, reflect.Float64:
+		return MetadataNumber, ""
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return MetadataString, "" // base64
+		}
+		elem := t.Elem()
+		if elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		kind, _ := schemaKind(elem)
+		return MetadataArray, kind
+	case reflect.Map, reflect.Struct:
+		return MetadataObject, ""
+	}
+	return MetadataAny, ""
+}
+
+// checkSchema validates encoded metadata against the schema under
+// WithMetadataSchema, naming the first field that does not match.
+func (i *Index[T]) checkSchema(data []byte) error {
+	if !i.strictSchema || data == nil {
+		return nil
+	}
+	fields, err := decodeFields(data)
+	if err != nil {
+		return fmt.Errorf("%w: metadata is not a JSON object: %v", ErrSchemaMismatch, err)
+	}
+	for _, f := range i.schema.Fields {
+		value, ok := fields[f.Name]
+		if !ok {
+			if f.Optional {
+				continue
+			}
+			return fmt.Errorf("%w: field %q: expected %s, got missing", ErrSchemaMismatch, f.Name, f.Kind)
+		}
+		if value == nil {
+			if f.Nullable {
+				continue
+			}
+			return fmt.Errorf("%w: field %q: expected %s, got null", ErrSchemaMismatch, f.Name, f.Kind)
+		}
+		if err := checkKind(f.Name, f.Kind, value); err != nil {
+			return err
+		}
+		if f.Kind != MetadataArray || f.Elem == "" {
+			continue
+		}
+		for n, elem := range value.([]any) {
+			if elem == nil {
+				continue
+			}
+			if err := checkKind(f.Name+"["+strconv.Itoa(n)+"]", f.Elem, elem); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		if _, ok := i.schema.Field(name); !ok {
+			return fmt.Errorf("%w: field %q: not in the schema, got %s", ErrSchemaMismatch, name, valueKind(fields[name]))
+		}
+	}
+	return nil
+}
+
+// checkKind reports whether value, decoded from JSON, is of kind want.
+func checkKind(name string, want MetadataKind, value any) error {
+	got := valueKind(value)
+	switch {
+	case want == MetadataAny || got == want:
+		return nil
+	case want == MetadataNumber && got == MetadataInteger:
+		return nil
+	case want == MetadataTime && got == MetadataString:
+		if _, err := time.Parse(time.RFC3339Nano, value.(string)); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: field %q: expected %s, got %s", ErrSchemaMismatch, name, want, got)
+}
+
+// valueKind returns the kind of a value decoded with json.Decoder.UseNumber,
+// or "null".
+func valueKind(value any) MetadataKind {
+	switch v := value.(type) {
+	case string:
+		return MetadataString
+	case bool:
+		return MetadataBool
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return MetadataInteger
+		}
+		return MetadataNumber
+	case []any:
+		return MetadataArray
+	case map[string]any:
+		return MetadataObject
+	}
+	return "null"
+}
+
+// decodeFields decodes JSON object data, keeping numbers as json.Number.
+func decodeFields(data []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// checkDrift emits IndexSchemaDrift for each field of stored metadata that
+// the schema does not know under WithMetadataSchema. Stored metadata may
+// come from a newer writer, so drift is reported rather than rejected.
+func (i *Index[T]) checkDrift(ctx context.Context, data []byte) {
+	if !i.strictSchema || data == nil {
+		return
+	}
+	fields, err := decodeFields(data)
+	if err != nil {
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		if _, ok := i.schema.Field(name); !ok {
+			capitan.Warn(ctx, IndexSchemaDrift,
+				CollectionKey.Field(i.name),
+				FieldKey.Field(name),
+			)
+		}
+	}
+}
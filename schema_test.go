@@ -0,0 +1,163 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/capitan"
+)
+
+type schemaMetadata struct {
+	Title     string    `json:"title"`
+	Views     int       `json:"views"`
+	Rating    float64   `json:"rating"`
+	Draft     bool      `json:"draft"`
+	Published time.Time `json:"published"`
+	Tags      []string  `json:"tags"`
+	Owner     *string   `json:"owner"`
+	Note      string    `json:"note,omitempty"`
+	ID        uuid.UUID `json:"id"`
+	Extra     struct {
+		Source string `json:"source"`
+	} `json:"extra"`
+	Raw    JSON[map[string]int] `json:"raw"`
+	Hidden string               `json:"-"`
+}
+
+// fixedCodec encodes every value as data, standing in for a codec or
+// writer whose output drifted from the metadata type.
+type fixedCodec struct {
+	JSONCodec
+	data string
+}
+
+func (c fixedCodec) Encode(any) ([]byte, error) { return []byte(c.data), nil }
+
+func TestIndex_Schema(t *testing.T) {
+	schema := NewIndex[schemaMetadata](newMockVectorProvider()).Schema()
+	want := []MetadataField{
+		{Name: "title", Kind: MetadataString},
+		{Name: "views", Kind: MetadataInteger},
+		{Name: "rating", Kind: MetadataNumber},
+		{Name: "draft", Kind: MetadataBool},
+		{Name: "published", Kind: MetadataTime},
+		{Name: "tags", Kind: MetadataArray, Elem: MetadataString, Nullable: true},
+		{Name: "owner", Kind: MetadataString, Nullable: true},
+		{Name: "note", Kind: MetadataString, Optional: true},
+		{Name: "id", Kind: MetadataString},
+		{Name: "extra", Kind: MetadataObject},
+		{Name: "raw", Kind: MetadataAny},
+	}
+	if len(schema.Fields) != len(want) {
+		t.Fatalf("expected %d fields, got %+v", len(want), schema.Fields)
+	}
+	for n, f := range want {
+		if schema.Fields[n] != f {
+			t.Errorf("field %d: expected %+v, got %+v", n, f, schema.Fields[n])
+		}
+	}
+	if _, ok := schema.Field("Hidden"); ok {
+		t.Error("expected json:\"-\" field excluded")
+	}
+	if got := NewIndex[map[string]any](newMockVectorProvider()).Schema(); len(got.Fields) != 0 {
+		t.Errorf("expected empty schema for a map, got %+v", got)
+	}
+}
+
+func TestIndex_CheckSchema(t *testing.T) {
+	index := NewIndex[schemaMetadata](newMockVectorProvider(), WithMetadataSchema())
+	valid := `"title":"a","views":1,"rating":2,"draft":false,"published":"2024-01-02T03:04:05Z","tags":["x"],"owner":null,"id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","extra":{},"raw":[1]`
+
+	tests := []struct {
+		name string
+		data string
+		want string // substring of the error; empty for a match
+	}{
+		{"valid", "{" + valid + "}", ""},
+		{"integer as number", "{" + strings.Replace(valid, `"rating":2`, `"rating":2.5`, 1) + "}", ""},
+		{"optional present", "{" + valid + `,"note":"n"}`, ""},
+		{"missing", "{" + strings.Replace(valid, `"title":"a",`, "", 1) + "}", `field "title": expected string, got missing`},
+		{"null", "{" + strings.Replace(valid, `"views":1`, `"views":null`, 1) + "}", `field "views": expected integer, got null`},
+		{"wrong kind", "{" + strings.Replace(valid, `"draft":false`, `"draft":"no"`, 1) + "}", `field "draft": expected bool, got string`},
+		{"fractional integer", "{" + strings.Replace(valid, `"views":1`, `"views":1.5`, 1) + "}", `field "views": expected integer, got number`},
+		{"bad time", "{" + strings.Replace(valid, `"2024-01-02T03:04:05Z"`, `"yesterday"`, 1) + "}", `field "published": expected time, got string`},
+		{"array element", "{" + strings.Replace(valid, `["x"]`, `["x",2]`, 1) + "}", `field "tags[1]": expected string, got integer`},
+		{"unknown field", "{" + valid + `,"legacy":true}`, `field "legacy": not in the schema, got bool`},
+		{"not an object", `[1]`, "not a JSON object"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := index.checkSchema([]byte(tc.data))
+			if tc.want == "" {
+				if err != nil {
+					t.Errorf("expected a match, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrSchemaMismatch) || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected ErrSchemaMismatch with %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestIndex_MetadataSchema(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	drifted := fixedCodec{data: `{"category":"tech","score":1,"legacy":"x"}`}
+
+	t.Run("upsert rejected", func(t *testing.T) {
+		provider := newMockVectorProvider()
+		index := NewIndexWithCodec[testMetadata](provider, drifted, WithMetadataSchema())
+		err := index.Upsert(ctx, id, []float32{1, 0}, &testMetadata{Category: "tech"})
+		if !errors.Is(err, ErrSchemaMismatch) || !strings.Contains(err.Error(), `"legacy"`) {
+			t.Errorf("expected ErrSchemaMismatch naming legacy, got %v", err)
+		}
+		err = index.UpsertBatch(ctx, []Vector[testMetadata]{{ID: id, Vector: []float32{1, 0}}})
+		if !errors.Is(err, ErrSchemaMismatch) {
+			t.Errorf("expected ErrSchemaMismatch from UpsertBatch, got %v", err)
+		}
+		if len(provider.vectors) != 0 {
+			t.Errorf("expected nothing written, got %d vectors", len(provider.vectors))
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		index := NewIndexWithCodec[testMetadata](newMockVectorProvider(), drifted)
+		if err := index.Upsert(ctx, id, []float32{1, 0}, &testMetadata{}); err != nil {
+			t.Errorf("expected no check without WithMetadataSchema, got %v", err)
+		}
+	})
+
+	t.Run("drift on read", func(t *testing.T) {
+		events := make(chan *capitan.Event, 4)
+		listener := capitan.Hook(IndexSchemaDrift, func(_ context.Context, e *capitan.Event) { events <- e })
+		defer listener.Close()
+
+		provider := newMockVectorProvider()
+		provider.vectors[id] = vectorEntry{vector: []float32{1, 0}, metadata: []byte(drifted.data)}
+		index := NewIndex[testMetadata](provider, WithMetadataSchema(), WithName("docs"))
+		got, err := index.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("expected drifted metadata to decode, got %v", err)
+		}
+		if got.Metadata.Category != "tech" {
+			t.Errorf("unexpected metadata %+v", got.Metadata)
+		}
+		select {
+		case e := <-events:
+			if field, _ := FieldKey.From(e); field != "legacy" {
+				t.Errorf("expected drift on legacy, got %q", field)
+			}
+			if name, _ := CollectionKey.From(e); name != "docs" {
+				t.Errorf("expected collection docs, got %q", name)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for IndexSchemaDrift")
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package grub
+
+import (
+	"fmt"
+	"math"
+)
+
+// ScoreKind reports what Vector.Score measures in results from Search,
+// SearchPage, Query, QueryPage, SearchBatch, and Recommend. With
+// WithNormalizedScores it is the canonical distance for the provider's
+// metric; otherwise it is whatever the provider reports. The zero ScoreKind
+// means the provider does not implement VectorScorer.
+func (i *Index[T]) ScoreKind() ScoreKind {
+	kind := providerScoreKind(i.provider)
+	if i.normScores && kind.Metric != "" {
+		kind.Measure = ScoreDistance
+	}
+	return kind
+}
+
+// providerScoreKind asks provider how its scores are measured.
+func providerScoreKind(provider VectorProvider) ScoreKind {
+	if scorer, ok := provider.(VectorScorer); ok {
+		return scorer.ScoreKind()
+	}
+	return ScoreKind{}
+}
+
+// normalizeScores applies WithNormalizedScores to results in place. It
+// fails with ErrUnsupported if the provider's scores cannot be converted,
+// even when there are no results.
+func (i *Index[T]) normalizeScores(results []VectorResult) error {
+	if !i.normScores {
+		return nil
+	}
+	toDistance, err := canonicalDistance(providerScoreKind(i.provider))
+	if err != nil {
+		return err
+	}
+	for n := range results {
+		results[n].Score = toDistance(results[n].Score)
+	}
+	return nil
+}
+
+// canonicalDistance returns the conversion from scores of the given kind to
+// the canonical distance for its metric, where lower is closer:
+//
+//	metric         similarity s   distance d   squared distance d²
+//	cosine         1 - s          d            -
+//	inner_product  -s             d            -
+//	l2             -              d            sqrt(d²)
+//
+// Providers report cosine and inner product distances as 1 - s and -s, so
+// distances pass through. The arithmetic is done in float64.
+func canonicalDistance(kind ScoreKind) (func(float32) float32, error) {
+	switch {
+	case kind.Metric == "":
+		return nil, fmt.Errorf("%w: provider does not report its score kind", ErrUnsupported)
+	case kind.Measure == ScoreDistance:
+		return func(d float32) float32 { return d }, nil
+	case kind.Measure == ScoreSimilarity && kind.Metric == DistanceCosine:
+		return func(s float32) float32 { return float32(1 - float64(s)) }, nil
+	case kind.Measure == ScoreSimilarity && kind.Metric == DistanceInnerProduct:
+		return func(s float32) float32 { return -s }, nil
+	case kind.Measure == ScoreSquaredDistance && kind.Metric == DistanceL2:
+		return func(d float32) float32 { return float32(math.Sqrt(math.Max(float64(d), 0))) }, nil
+	}
+	return nil, fmt.Errorf("%w: cannot normalize %s %s scores", ErrUnsupported, kind.Metric, kind.Measure)
+}
+
+// DistanceToSimilarity converts a canonical distance for metric, as in
+// results under WithNormalizedScores, to a similarity in [0, 1] where
+// higher is closer, for display:
+//
+//	metric         distance d   similarity
+//	cosine         1 - cos      1 - d/2
+//	inner_product  -dot         (1 - d) / 2
+//	l2             |a - b|      1 / (1 + d)
+//
+// The inner product mapping assumes unit vectors (see WithNormalization),
+// for which it matches the cosine one. Results are clamped to [0, 1], so
+// rounding and non-unit vectors cannot push them outside it. An unknown
+// metric yields 0.
+func DistanceToSimilarity(metric DistanceMetric, score float32) float32 {
+	d := float64(score)
+	var s float64
+	switch metric {
+	case DistanceCosine:
+		s = 1 - d/2
+	case DistanceInnerProduct:
+		s = (1 - d) / 2
+	case DistanceL2:
+		s = 1 / (1 + math.Max(d, 0))
+	default:
+		return 0
+	}
+	return float32(math.Min(math.Max(s, 0), 1))
+}
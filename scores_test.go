@@ -0,0 +1,184 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/vecna"
+)
+
+func TestCanonicalDistance(t *testing.T) {
+	for _, tc := range []struct {
+		kind  ScoreKind
+		score float32
+		want  float32
+	}{
+		{ScoreKind{Metric: DistanceCosine, Measure: ScoreSimilarity}, 1, 0},
+		{ScoreKind{Metric: DistanceCosine, Measure: ScoreSimilarity}, 0.25, 0.75},
+		{ScoreKind{Metric: DistanceCosine, Measure: ScoreSimilarity}, -1, 2},
+		{ScoreKind{Metric: DistanceCosine, Measure: ScoreDistance}, 0.75, 0.75},
+		{ScoreKind{Metric: DistanceInnerProduct, Measure: ScoreSimilarity}, 3.5, -3.5},
+		{ScoreKind{Metric: DistanceInnerProduct, Measure: ScoreSimilarity}, -2, 2},
+		{ScoreKind{Metric: DistanceInnerProduct, Measure: ScoreDistance}, -3.5, -3.5},
+		{ScoreKind{Metric: DistanceL2, Measure: ScoreDistance}, 5, 5},
+		{ScoreKind{Metric: DistanceL2, Measure: ScoreSquaredDistance}, 25, 5},
+		{ScoreKind{Metric: DistanceL2, Measure: ScoreSquaredDistance}, 2, float32(math.Sqrt2)},
+		{ScoreKind{Metric: DistanceL2, Measure: ScoreSquaredDistance}, -1e-7, 0}, // rounding below zero
+	} {
+		toDistance, err := canonicalDistance(tc.kind)
+		if err != nil {
+			t.Fatalf("%+v: canonicalDistance failed: %v", tc.kind, err)
+		}
+		if got := toDistance(tc.score); got != tc.want {
+			t.Errorf("%+v of %v: expected %v, got %v", tc.kind, tc.score, tc.want, got)
+		}
+	}
+
+	for _, kind := range []ScoreKind{
+		{},
+		{Metric: DistanceL2, Measure: ScoreSimilarity},
+		{Metric: DistanceCosine, Measure: ScoreSquaredDistance},
+		{Metric: "hamming", Measure: ScoreSimilarity},
+	} {
+		if _, err := canonicalDistance(kind); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("%+v: expected ErrUnsupported, got %v", kind, err)
+		}
+	}
+}
+
+func TestDistanceToSimilarity(t *testing.T) {
+	for _, tc := range []struct {
+		metric DistanceMetric
+		score  float32
+		want   float32
+	}{
+		{DistanceCosine, 0, 1},
+		{DistanceCosine, 1, 0.5},
+		{DistanceCosine, 2, 0},
+		{DistanceCosine, -1e-7, 1}, // rounding below zero
+		{DistanceInnerProduct, -1, 1},
+		{DistanceInnerProduct, 0, 0.5},
+		{DistanceInnerProduct, 1, 0},
+		{DistanceInnerProduct, -3, 1}, // non-unit vectors clamp
+		{DistanceL2, 0, 1},
+		{DistanceL2, 1, 0.5},
+		{DistanceL2, 3, 0.25},
+		{"hamming", 0, 0},
+	} {
+		if got := DistanceToSimilarity(tc.metric, tc.score); got != tc.want {
+			t.Errorf("%s distance %v: expected %v, got %v", tc.metric, tc.score, tc.want, got)
+		}
+	}
+
+	// Closer is always more similar.
+	for _, metric := range []DistanceMetric{DistanceCosine, DistanceInnerProduct, DistanceL2} {
+		if DistanceToSimilarity(metric, 0.2) <= DistanceToSimilarity(metric, 0.4) {
+			t.Errorf("%s: similarity does not fall as distance grows", metric)
+		}
+	}
+}
+
+// kindVectorProvider reports a fixed ScoreKind.
+type kindVectorProvider struct {
+	VectorProvider
+	kind ScoreKind
+}
+
+func (p *kindVectorProvider) ScoreKind() ScoreKind { return p.kind }
+
+// squaredVectorProvider scores Query results by squared L2 distance, as
+// Milvus and Pinecone do.
+type squaredVectorProvider struct {
+	*mockVectorProvider
+}
+
+func (p *squaredVectorProvider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]VectorResult, error) {
+	results, err := p.mockVectorProvider.Query(ctx, vector, k, filter)
+	for n := range results {
+		results[n].Score *= results[n].Score
+	}
+	return results, err
+}
+
+func TestIndex_NormalizedScores(t *testing.T) {
+	ctx := context.Background()
+	dataset := map[uuid.UUID][]float32{
+		uuid.New(): {1, 0},
+		uuid.New(): {0.8, 0.6},
+		uuid.New(): {0, 1},
+		uuid.New(): {-0.6, 0.8},
+		uuid.New(): {-1, 0},
+	}
+	query := []float32{0.6, 0.8}
+
+	// Unit vectors, so dot product is cosine similarity.
+	cosine := &kindVectorProvider{
+		VectorProvider: &dotVectorProvider{newMockVectorProvider()},
+		kind:           ScoreKind{Metric: DistanceCosine, Measure: ScoreSimilarity},
+	}
+	l2 := &kindVectorProvider{
+		VectorProvider: &squaredVectorProvider{newMockVectorProvider()},
+		kind:           ScoreKind{Metric: DistanceL2, Measure: ScoreSquaredDistance},
+	}
+
+	var orders [][]*Vector[testMetadata]
+	for _, provider := range []*kindVectorProvider{cosine, l2} {
+		index := NewIndex[testMetadata](provider, WithNormalizedScores())
+		if got := index.ScoreKind(); got != (ScoreKind{Metric: provider.kind.Metric, Measure: ScoreDistance}) {
+			t.Errorf("expected canonical distance kind, got %+v", got)
+		}
+		for id, v := range dataset {
+			if err := index.Upsert(ctx, id, v, nil); err != nil {
+				t.Fatalf("Upsert failed: %v", err)
+			}
+		}
+		results, err := index.Query(ctx, query, len(dataset), nil)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		for n := 1; n < len(results); n++ {
+			if results[n].Score < results[n-1].Score {
+				t.Errorf("%s: scores not ascending at %d: %v < %v", provider.kind.Metric, n, results[n].Score, results[n-1].Score)
+			}
+		}
+		orders = append(orders, results)
+	}
+
+	for n := range orders[0] {
+		c, e := orders[0][n], orders[1][n]
+		if c.ID != e.ID {
+			t.Fatalf("result %d: cosine and l2 orders differ: %v vs %v", n, dataset[c.ID], dataset[e.ID])
+		}
+		// For unit vectors, Euclidean distance is sqrt(2 * cosine distance).
+		if want := math.Sqrt(2 * float64(c.Score)); math.Abs(float64(e.Score)-want) > 1e-6 {
+			t.Errorf("result %d: expected l2 distance %v for cosine distance %v, got %v", n, want, c.Score, e.Score)
+		}
+	}
+
+	t.Run("raw", func(t *testing.T) {
+		index := NewIndex[testMetadata](cosine)
+		if got := index.ScoreKind(); got != cosine.kind {
+			t.Errorf("expected provider kind, got %+v", got)
+		}
+		results, err := index.Query(ctx, query, 1, nil)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if math.Abs(float64(results[0].Score)-0.96) > 1e-6 {
+			t.Errorf("expected raw similarity 0.96, got %v", results[0].Score)
+		}
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		index := NewIndex[testMetadata](newMockVectorProvider(), WithNormalizedScores())
+		if got := index.ScoreKind(); got != (ScoreKind{}) {
+			t.Errorf("expected zero kind, got %+v", got)
+		}
+		if _, err := index.Search(ctx, query, 1, nil); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/zoobzio/vecna"
+)
+
+// searchBatchConcurrency bounds the concurrent Query calls made by
+// SearchBatch when the provider has no native batch search.
+const searchBatchConcurrency = 8
+
+// SearchBatch performs one similarity search per query vector and returns
+// the k nearest neighbors of each, aligned positionally with queries. Uses
+// the provider's native batch search through VectorBatchSearcher when
+// available; otherwise runs Query for each vector with bounded concurrency.
+// The call fails as a whole if any query fails; errors.As with a
+// *BatchQueryError reports which one.
+// Returns ErrDimensionMismatch if any query does not match the index dimension.
+func (i *Index[T]) SearchBatch(ctx context.Context, queries [][]float32, k int, filter *vecna.Filter) ([][]*Vector[T], error) {
+	for n, vector := range queries {
+		if err := i.checkDimension(vector); err != nil {
+			return nil, i.wrapErr("search_batch", "", &BatchQueryError{Index: n, Err: err})
+		}
+	}
+	if len(queries) == 0 {
+		return [][]*Vector[T]{}, nil
+	}
+	queries, err := i.normalizeQueries(queries)
+	if err != nil {
+		return nil, i.wrapErr("search_batch", "", err)
+	}
+	start := i.emitSearchStarted(ctx, "search_batch", k)
+	callCtx, cancel := withDefaultTimeout(ctx, i.timeout)
+	defer cancel()
+	var batches [][]VectorResult
+	if searcher, ok := i.searcher.(VectorBatchSearcher); ok {
+		batches, err = searcher.SearchBatch(callCtx, queries, k, filter)
+		if err == nil && len(batches) != len(queries) {
+			err = errors.New("grub: provider returned mismatched batch size")
+		}
+	} else {
+		batches, err = i.queryEach(callCtx, queries, k, filter)
+	}
+	if err != nil {
+		i.emitSearchFailed(ctx, "search_batch", k, start, err)
+		return nil, i.wrapErr("search_batch", "", classifyBatchQuery(err))
+	}
+	count := 0
+	for _, results := range batches {
+		count += len(results)
+	}
+	i.emitSearchCompleted(ctx, "search_batch", k, start, count)
+
+	out := make([][]*Vector[T], len(batches))
+	for n, results := range batches {
+		vectors, err := i.decodeResults(ctx, results)
+		if err != nil {
+			return nil, &BatchQueryError{Index: n, Err: err}
+		}
+		out[n] = vectors
+	}
+	return out, nil
+}
+
+// queryEach calls provider.Query for each vector concurrently.
+// The first error cancels outstanding queries and is returned.
+func (i *Index[T]) queryEach(ctx context.Context, queries [][]float32, k int, filter *vecna.Filter) ([][]VectorResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		batches  = make([][]VectorResult, len(queries))
+		sem      = make(chan struct{}, searchBatchConcurrency)
+	)
+	for n, vector := range queries {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(n int, vector []float32) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results, err := i.searcher.Query(ctx, vector, k, filter)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = &BatchQueryError{Index: n, Err: err}
+					cancel()
+				}
+				return
+			}
+			batches[n] = results
+		}(n, vector)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// classifyBatchQuery applies classifyDimension to the failing query's error.
+func classifyBatchQuery(err error) error {
+	var bqe *BatchQueryError
+	if errors.As(err, &bqe) {
+		bqe.Err = classifyDimension(bqe.Err)
+		return err
+	}
+	return classifyDimension(err)
+}
@@ -0,0 +1,134 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/vecna"
+)
+
+// batchSearchingVectorProvider is a mockVectorProvider that also implements
+// VectorBatchSearcher.
+type batchSearchingVectorProvider struct {
+	*mockVectorProvider
+	calls int
+}
+
+func (p *batchSearchingVectorProvider) SearchBatch(ctx context.Context, queries [][]float32, k int, filter *vecna.Filter) ([][]VectorResult, error) {
+	p.calls++
+	batches := make([][]VectorResult, len(queries))
+	for n, vector := range queries {
+		results, err := p.Query(ctx, vector, k, filter)
+		if err != nil {
+			return nil, &BatchQueryError{Index: n, Err: err}
+		}
+		batches[n] = results
+	}
+	return batches, nil
+}
+
+// rejectingVectorProvider fails any query whose first component is reject.
+type rejectingVectorProvider struct {
+	*mockVectorProvider
+	reject float32
+}
+
+func (p *rejectingVectorProvider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]VectorResult, error) {
+	if vector[0] == p.reject {
+		return nil, errors.New("rejected")
+	}
+	return p.mockVectorProvider.Query(ctx, vector, k, filter)
+}
+
+func TestIndex_SearchBatch(t *testing.T) {
+	ctx := context.Background()
+
+	// five vectors along a line; query n is nearest to point n
+	seed := func(m *mockVectorProvider) []uuid.UUID {
+		ids := make([]uuid.UUID, 5)
+		for n := range ids {
+			ids[n] = uuid.New()
+			m.vectors[ids[n]] = vectorEntry{
+				vector:   []float32{float32(n), 0},
+				metadata: []byte(`{"category": "line"}`),
+			}
+		}
+		return ids
+	}
+	queries := [][]float32{{4, 0}, {0, 0}, {2.1, 0}}
+	nearest := []int{4, 0, 2}
+
+	fallback := newMockVectorProvider()
+	native := &batchSearchingVectorProvider{mockVectorProvider: newMockVectorProvider()}
+	for _, tc := range []struct {
+		name     string
+		mock     *mockVectorProvider
+		provider VectorProvider
+	}{
+		{"fallback", fallback, fallback},
+		{"batch searcher", native.mockVectorProvider, native},
+	} {
+		ids := seed(tc.mock)
+		index := NewIndex[testMetadata](tc.provider)
+
+		t.Run(tc.name, func(t *testing.T) {
+			batches, err := index.SearchBatch(ctx, queries, 2, nil)
+			if err != nil {
+				t.Fatalf("SearchBatch failed: %v", err)
+			}
+			if len(batches) != len(queries) {
+				t.Fatalf("expected %d batches, got %d", len(queries), len(batches))
+			}
+			for n, results := range batches {
+				if len(results) != 2 {
+					t.Fatalf("query %d: expected 2 results, got %d", n, len(results))
+				}
+				if results[0].ID != ids[nearest[n]] {
+					t.Errorf("query %d: expected nearest %s, got %s", n, ids[nearest[n]], results[0].ID)
+				}
+				if results[0].Metadata.Category != "line" {
+					t.Errorf("query %d: expected decoded metadata, got %+v", n, results[0].Metadata)
+				}
+			}
+		})
+	}
+	if native.calls != 1 {
+		t.Errorf("expected 1 native batch call, got %d", native.calls)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		batches, err := NewIndex[testMetadata](fallback).SearchBatch(ctx, nil, 2, nil)
+		if err != nil {
+			t.Fatalf("SearchBatch failed: %v", err)
+		}
+		if len(batches) != 0 {
+			t.Errorf("expected no batches, got %d", len(batches))
+		}
+	})
+
+	t.Run("failing query", func(t *testing.T) {
+		provider := &rejectingVectorProvider{mockVectorProvider: fallback, reject: 2.1}
+		_, err := NewIndex[testMetadata](provider).SearchBatch(ctx, queries, 2, nil)
+		var bqe *BatchQueryError
+		if !errors.As(err, &bqe) {
+			t.Fatalf("expected BatchQueryError, got %v", err)
+		}
+		if bqe.Index != 2 {
+			t.Errorf("expected failing index 2, got %d", bqe.Index)
+		}
+	})
+
+	t.Run("dimension mismatch", func(t *testing.T) {
+		index := NewIndex[testMetadata](fallback, WithDimension(2))
+		_, err := index.SearchBatch(ctx, [][]float32{{1, 0}, {1, 0, 0}}, 2, nil)
+		if !errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("expected ErrDimensionMismatch, got %v", err)
+		}
+		var bqe *BatchQueryError
+		if !errors.As(err, &bqe) || bqe.Index != 1 {
+			t.Errorf("expected failing index 1, got %v", err)
+		}
+	})
+}
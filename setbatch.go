@@ -0,0 +1,249 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultSetBatchRows is the SetBatch chunk size without WithMaxBatchSize.
+const defaultSetBatchRows = 500
+
+// dialectMaxParams is the most bind parameters one statement may carry.
+var dialectMaxParams = map[string]int{
+	"postgres": 65535,
+	"mariadb":  65535,
+	"sqlite":   999,
+	"mssql":    2100,
+}
+
+// batchColumn is a db column of T and the index of its field.
+type batchColumn struct {
+	name  string
+	index []int
+}
+
+// SetBatch upserts every record in items with one multi-row statement per
+// chunk: INSERT ... ON CONFLICT DO UPDATE on Postgres and SQLite, INSERT ...
+// ON DUPLICATE KEY UPDATE on MariaDB, and MERGE on SQL Server. Each record
+// is written under its own primary key field; the map keys order the batch
+// and label errors. Chunks hold up to 500 rows, or WithMaxBatchSize, fewer
+// when the dialect's bind parameter limit requires. BeforeSave runs on every
+// record before the first statement and AfterSave after the last. If a chunk
+// fails, the chunks before it stay written and, when the batch was split,
+// the error is a *BatchChunkError naming it; use SetBatchTx to write all or
+// nothing. Returns ErrUnsupported for a custom renderer.
+func (d *Database[T]) SetBatch(ctx context.Context, items map[string]*T) error {
+	if d.readOnly {
+		return d.wrapErr("set_batch", "", ErrReadOnly)
+	}
+	return d.setBatch(ctx, "set_batch", nil, items)
+}
+
+// SetBatchTx is SetBatch within a transaction.
+func (d *Database[T]) SetBatchTx(ctx context.Context, tx *sqlx.Tx, items map[string]*T) error {
+	if d.readOnly {
+		return d.wrapErr("set_batch_tx", "", ErrReadOnly)
+	}
+	if tx == nil {
+		return d.wrapErr("set_batch_tx", "", ErrNilTransaction)
+	}
+	return d.setBatch(ctx, "set_batch_tx", tx, items)
+}
+
+// BatchResult is the outcome of one record of SetBatchPartial.
+type BatchResult struct {
+	Key string
+	Err error // nil when the record was written
+}
+
+// SetBatchPartial upserts every record in items on its own, in key order,
+// so one record's failure does not stop the rest: a constraint violation,
+// a BeforeSave error, or a nil record is reported in that record's result
+// and the remaining records are still written. Each record is written as
+// Set writes it, hooks, outbox, and WithWriteRetry included, with one
+// statement per record rather than SetBatch's multi-row statements. The
+// returned error is reserved for failures no later record could survive:
+// ctx ending or the connection being lost. The results then cover only
+// the records attempted before it.
+func (d *Database[T]) SetBatchPartial(ctx context.Context, items map[string]*T) ([]BatchResult, error) {
+	const op = "set_batch_partial"
+	if d.readOnly {
+		return nil, d.wrapErr(op, "", ErrReadOnly)
+	}
+	results := make([]BatchResult, 0, len(items))
+	for _, key := range slices.Sorted(maps.Keys(items)) {
+		rec := items[key]
+		var err error
+		if rec == nil {
+			err = d.wrapErr(op, key, errors.New("nil record"))
+		} else {
+			err = d.retry.do(ctx, func() error {
+				return d.set(ctx, op, nil, key, rec)
+			})
+		}
+		if err != nil && isTransportErr(ctx, err) {
+			return results, err
+		}
+		results = append(results, BatchResult{Key: key, Err: err})
+	}
+	return results, nil
+}
+
+// isTransportErr reports whether err means the connection, not the record,
+// failed: ctx has ended or the driver lost its connection.
+func isTransportErr(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone)
+}
+
+// setBatch implements SetBatch and SetBatchTx, running outside a
+// transaction when tx is nil unless WithOutbox needs one.
+func (d *Database[T]) setBatch(ctx context.Context, op string, tx *sqlx.Tx, items map[string]*T) error {
+	if len(items) == 0 {
+		return nil
+	}
+	maxParams, ok := dialectMaxParams[d.dialect]
+	if !ok {
+		return d.wrapErr(op, "", fmt.Errorf("%w: multi-row upsert for a custom renderer", ErrUnsupported))
+	}
+	if tx == nil && d.outbox != nil {
+		return d.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			return d.setBatch(ctx, op, tx, items)
+		}, nil)
+	}
+
+	keys := slices.Sorted(maps.Keys(items))
+	records := make([]*T, len(keys))
+	for n, key := range keys {
+		if items[key] == nil {
+			return d.wrapErr(op, key, errors.New("nil record"))
+		}
+		if err := d.beforeSave(ctx, items[key]); err != nil {
+			return d.wrapErr(op, key, err)
+		}
+		records[n] = items[key]
+	}
+
+	cols := d.batchColumns()
+	rows := d.batchRows
+	if rows <= 0 {
+		rows = defaultSetBatchRows
+	}
+	rows = max(min(rows, maxParams/len(cols)), 1)
+
+	err := inChunks(len(records), rows, func(start, end int) error {
+		query, args := d.upsertRows(cols, records[start:end])
+		callCtx, cancel := withDefaultTimeout(ctx, d.timeout)
+		defer cancel()
+		var err error
+		if tx != nil {
+			_, err = tx.ExecContext(callCtx, tx.Rebind(query), args...)
+		} else {
+			_, err = d.db.ExecContext(callCtx, d.db.Rebind(query), args...)
+		}
+		if err != nil {
+			return err
+		}
+		for _, rec := range records[start:end] {
+			if err := d.outbox.record(callCtx, tx, "set", d.columnValue(rec, d.keyCol), rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	d.invalidateCache(ctx, tx)
+	if err != nil {
+		return d.wrapErr(op, "", err)
+	}
+	for _, rec := range records {
+		if err := callAfterSave(ctx, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchColumns returns T's db columns in field order.
+func (d *Database[T]) batchColumns() []batchColumn {
+	var cols []batchColumn
+	for _, field := range d.executor.Soy().Metadata().Fields {
+		col := field.Tags["db"]
+		if col == "" || col == "-" {
+			continue
+		}
+		cols = append(cols, batchColumn{name: col, index: field.Index})
+	}
+	return cols
+}
+
+// upsertRows renders the multi-row upsert of records with ? placeholders
+// and returns it with its args, row by row.
+func (d *Database[T]) upsertRows(cols []batchColumn, records []*T) (string, []any) {
+	names := make([]string, len(cols))
+	var updates []string
+	for n, c := range cols {
+		names[n] = d.quote(c.name)
+		if c.name == d.keyCol {
+			continue
+		}
+		switch d.dialect {
+		case "mariadb":
+			updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", names[n], names[n]))
+		case "mssql":
+			updates = append(updates, fmt.Sprintf("target.%s = source.%s", names[n], names[n]))
+		default:
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", names[n], names[n]))
+		}
+	}
+
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ") + ")"
+	values := make([]string, len(records))
+	args := make([]any, 0, len(records)*len(cols))
+	for n, rec := range records {
+		values[n] = row
+		v := reflect.ValueOf(rec).Elem()
+		for _, c := range cols {
+			args = append(args, v.FieldByIndex(c.index).Interface())
+		}
+	}
+
+	table, key := d.quote(d.tableName), d.quote(d.keyCol)
+	columns := strings.Join(names, ", ")
+	rowsSQL := strings.Join(values, ", ")
+	var b strings.Builder
+	switch d.dialect {
+	case "mssql":
+		sources := make([]string, len(names))
+		for n, name := range names {
+			sources[n] = "source." + name
+		}
+		fmt.Fprintf(&b, "MERGE INTO %s WITH (HOLDLOCK) AS target USING (VALUES %s) AS source (%s) ON target.%s = source.%s",
+			table, rowsSQL, columns, key, key)
+		if len(updates) > 0 {
+			fmt.Fprintf(&b, " WHEN MATCHED THEN UPDATE SET %s", strings.Join(updates, ", "))
+		}
+		fmt.Fprintf(&b, " WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);", columns, strings.Join(sources, ", "))
+	case "mariadb":
+		if len(updates) == 0 {
+			updates = []string{fmt.Sprintf("%s = VALUES(%s)", key, key)}
+		}
+		fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s",
+			table, columns, rowsSQL, strings.Join(updates, ", "))
+	default:
+		fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES %s ON CONFLICT (%s) ", table, columns, rowsSQL, key)
+		if len(updates) == 0 {
+			b.WriteString("DO NOTHING")
+		} else {
+			b.WriteString("DO UPDATE SET " + strings.Join(updates, ", "))
+		}
+	}
+	return b.String(), args
+}
@@ -0,0 +1,287 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/astql"
+	astqlmariadb "github.com/zoobzio/astql/mariadb"
+	astqlmssql "github.com/zoobzio/astql/mssql"
+	astqlpostgres "github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// batchUsers returns n users keyed by their id.
+func batchUsers(n int) map[string]*TestDBUser {
+	items := make(map[string]*TestDBUser, n)
+	for i := 1; i <= n; i++ {
+		items[fmt.Sprint(i)] = &TestDBUser{ID: i, Email: fmt.Sprintf("u%d@example.com", i), Name: fmt.Sprintf("User %d", i)}
+	}
+	return items
+}
+
+func TestDatabase_SetBatch(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		want     string
+	}{
+		{"postgres", astqlpostgres.New(),
+			`INSERT INTO "test_users" ("id", "email", "name", "age") VALUES (?, ?, ?, ?), (?, ?, ?, ?) ON CONFLICT ("id") DO UPDATE SET "email" = EXCLUDED."email", "name" = EXCLUDED."name", "age" = EXCLUDED."age"`},
+		{"sqlite", testDBRenderer,
+			`INSERT INTO "test_users" ("id", "email", "name", "age") VALUES (?, ?, ?, ?), (?, ?, ?, ?) ON CONFLICT ("id") DO UPDATE SET`},
+		{"mariadb", astqlmariadb.New(),
+			"INSERT INTO `test_users` (`id`, `email`, `name`, `age`) VALUES (?, ?, ?, ?), (?, ?, ?, ?) ON DUPLICATE KEY UPDATE `email` = VALUES(`email`), `name` = VALUES(`name`), `age` = VALUES(`age`)"},
+		{"mssql", astqlmssql.New(),
+			`MERGE INTO [test_users] WITH (HOLDLOCK) AS target USING (VALUES (?, ?, ?, ?), (?, ?, ?, ?)) AS source ([id], [email], [name], [age]) ON target.[id] = source.[id] WHEN MATCHED THEN UPDATE SET target.[email] = source.[email], target.[name] = source.[name], target.[age] = source.[age] WHEN NOT MATCHED THEN INSERT ([id], [email], [name], [age]) VALUES (source.[id], source.[email], source.[name], source.[age]);`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, capture := mockdb.New()
+			db, err := NewDatabase[TestDBUser](mockDB, "test_users", tt.renderer)
+			if err != nil {
+				t.Fatalf("NewDatabase failed: %v", err)
+			}
+			if err := db.SetBatch(ctx, batchUsers(2)); err != nil {
+				t.Fatalf("SetBatch failed: %v", err)
+			}
+			if len(capture.Queries) != 1 {
+				t.Fatalf("expected one statement, got %d", len(capture.Queries))
+			}
+			q := capture.Queries[0]
+			if !strings.HasPrefix(q.Query, tt.want) {
+				t.Errorf("expected %s, got: %s", tt.want, q.Query)
+			}
+			if len(q.Args) != 8 || q.Args[0] != int64(1) || q.Args[4] != int64(2) || q.Args[6] != "User 2" {
+				t.Errorf("expected two rows of args in key order, got %v", q.Args)
+			}
+		})
+	}
+}
+
+func TestDatabase_SetBatch_Chunks(t *testing.T) {
+	ctx := context.Background()
+
+	// rowCounts returns the rows in each captured statement.
+	rowCounts := func(capture *mockdb.Capture) []int {
+		var counts []int
+		for _, q := range capture.Queries {
+			counts = append(counts, len(q.Args)/len(userColumns))
+		}
+		return counts
+	}
+
+	tests := []struct {
+		name     string
+		renderer astql.Renderer
+		opts     []Option
+		items    int
+		want     []int
+	}{
+		{"default 500 rows", astqlpostgres.New(), nil, 1200, []int{500, 500, 200}},
+		{"max batch size", astqlpostgres.New(), []Option{WithMaxBatchSize(300)}, 700, []int{300, 300, 100}},
+		{"sqlite parameter limit", testDBRenderer, nil, 1000, []int{249, 249, 249, 249, 4}},
+		{"mssql parameter limit", astqlmssql.New(), nil, 1100, []int{500, 500, 100}},
+		{"mssql parameter limit over max batch size", astqlmssql.New(), []Option{WithMaxBatchSize(1000)}, 1100, []int{525, 525, 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, capture := mockdb.New()
+			db, err := NewDatabase[TestDBUser](mockDB, "test_users", tt.renderer, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewDatabase failed: %v", err)
+			}
+			if err := db.SetBatch(ctx, batchUsers(tt.items)); err != nil {
+				t.Fatalf("SetBatch failed: %v", err)
+			}
+			if got := rowCounts(capture); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("expected chunks of %v rows, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("reports the failing chunk", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		cfg.SetExecErr(errors.New("disk full"))
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", astqlpostgres.New(), WithMaxBatchSize(2))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		err = db.SetBatch(ctx, batchUsers(5))
+		var chunkErr *BatchChunkError
+		if !errors.As(err, &chunkErr) {
+			t.Fatalf("expected a BatchChunkError, got %v", err)
+		}
+		if chunkErr.Chunk != 0 || chunkErr.Start != 0 || chunkErr.Size != 2 {
+			t.Errorf("expected chunk 0 at item 0 of size 2, got %+v", chunkErr)
+		}
+	})
+}
+
+// batchHookedUser is a Database-compatible model counting its save hooks.
+type batchHookedUser struct {
+	ID          int    `db:"id" constraints:"primarykey"`
+	Name        string `db:"name"`
+	beforeSaves int
+	afterSaves  int
+}
+
+func (u *batchHookedUser) BeforeSave(_ context.Context) error {
+	u.beforeSaves++
+	u.Name = strings.ToUpper(u.Name)
+	return nil
+}
+
+func (u *batchHookedUser) AfterSave(_ context.Context) error {
+	u.afterSaves++
+	return nil
+}
+
+func TestDatabase_SetBatch_Hooks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("before and after every record", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[batchHookedUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		items := map[string]*batchHookedUser{"1": {ID: 1, Name: "alice"}, "2": {ID: 2, Name: "bob"}}
+		if err := db.SetBatch(ctx, items); err != nil {
+			t.Fatalf("SetBatch failed: %v", err)
+		}
+		for key, u := range items {
+			if u.beforeSaves != 1 || u.afterSaves != 1 {
+				t.Errorf("%s: expected each hook once, got %d before and %d after", key, u.beforeSaves, u.afterSaves)
+			}
+		}
+		last, _ := capture.Last()
+		if last.Args[1] != "ALICE" || last.Args[3] != "BOB" {
+			t.Errorf("expected BeforeSave changes in the statement, got %v", last.Args)
+		}
+	})
+
+	t.Run("before save error writes nothing", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[failingBeforeSaveDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		err = db.SetBatch(ctx, map[string]*failingBeforeSaveDBUser{"1": {ID: 1}})
+		if !errors.Is(err, errHook) {
+			t.Fatalf("expected hook error, got %v", err)
+		}
+		if len(capture.Queries) != 0 {
+			t.Errorf("expected no statements, got %d", len(capture.Queries))
+		}
+	})
+}
+
+func TestDatabase_SetBatchTx(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	if err := db.SetBatchTx(ctx, nil, batchUsers(1)); !errors.Is(err, ErrNilTransaction) {
+		t.Errorf("expected ErrNilTransaction, got %v", err)
+	}
+
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return db.SetBatchTx(ctx, tx, batchUsers(300))
+	}, nil)
+	if err != nil {
+		t.Fatalf("SetBatchTx failed: %v", err)
+	}
+	assertTxEvents(t, capture, "BEGIN", "COMMIT")
+}
+
+func TestDatabase_SetBatch_Unsupported(t *testing.T) {
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", customRenderer{testDBRenderer})
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	if err := db.SetBatch(context.Background(), batchUsers(1)); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+	if err := db.SetBatch(context.Background(), nil); err != nil {
+		t.Errorf("expected an empty batch to succeed, got %v", err)
+	}
+	if len(capture.Queries) != 0 {
+		t.Errorf("expected no statements, got %d", len(capture.Queries))
+	}
+}
+
+func TestDatabase_SetBatchPartial(t *testing.T) {
+	ctx := context.Background()
+	newDB := func(t *testing.T) (*Database[TestDBUser], *mockdb.Config) {
+		t.Helper()
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		cfg.SetRows(userColumns, []driver.Value{int64(1), "u@example.com", "User", nil})
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		return db, cfg
+	}
+
+	t.Run("reports each record's error", func(t *testing.T) {
+		db, cfg := newDB(t)
+		cfg.SetQueryErrTimes(&fakeSQLiteError{code: 2067, msg: "constraint failed: UNIQUE constraint failed: test_users.email (2067)"}, 1)
+		items := batchUsers(3)
+		items["4"] = nil
+		results, err := db.SetBatchPartial(ctx, items)
+		if err != nil {
+			t.Fatalf("SetBatchPartial failed: %v", err)
+		}
+		if len(results) != 4 {
+			t.Fatalf("expected a result per record, got %+v", results)
+		}
+		for i, r := range results {
+			if want := fmt.Sprint(i + 1); r.Key != want {
+				t.Errorf("result %d: expected key %s, got %s", i, want, r.Key)
+			}
+		}
+		if !errors.Is(results[0].Err, ErrUniqueViolation) {
+			t.Errorf("expected a unique violation for 1, got %v", results[0].Err)
+		}
+		if results[1].Err != nil || results[2].Err != nil {
+			t.Errorf("expected 2 and 3 to be written, got %v, %v", results[1].Err, results[2].Err)
+		}
+		var gerr *Error
+		if !errors.As(results[3].Err, &gerr) || gerr.Key != "4" || gerr.Op != "set_batch_partial" {
+			t.Errorf("expected an error naming 4, got %v", results[3].Err)
+		}
+	})
+
+	t.Run("a lost connection stops the batch", func(t *testing.T) {
+		db, cfg := newDB(t)
+		cfg.SetQueryErr(driver.ErrBadConn)
+		results, err := db.SetBatchPartial(ctx, batchUsers(3))
+		if !errors.Is(err, driver.ErrBadConn) {
+			t.Fatalf("expected ErrBadConn, got %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("expected no results, got %+v", results)
+		}
+	})
+
+	t.Run("a cancelled context stops the batch", func(t *testing.T) {
+		db, _ := newDB(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := db.SetBatchPartial(cancelled, batchUsers(2)); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package grub
+
+import "github.com/zoobzio/capitan"
+
+// Vector index signals.
+var (
+	// IndexSearchStarted is emitted when a Search, Query, or Filter begins.
+	// Fields: CollectionKey, OperationKey, KKey.
+	IndexSearchStarted = capitan.NewSignal("grub.index.search.started", "Vector index search started")
+
+	// IndexSearchCompleted is emitted when a Search, Query, or Filter succeeds.
+	// Fields: CollectionKey, OperationKey, KKey, DurationMsKey, ResultCountKey.
+	IndexSearchCompleted = capitan.NewSignal("grub.index.search.completed", "Vector index search completed successfully")
+
+	// IndexSearchFailed is emitted when a Search, Query, or Filter fails.
+	// Fields: CollectionKey, OperationKey, KKey, DurationMsKey, ErrorKey.
+	IndexSearchFailed = capitan.NewSignal("grub.index.search.failed", "Vector index search failed with error")
+
+	// IndexUpsertCompleted is emitted when an Upsert or UpsertBatch succeeds.
+	// Fields: CollectionKey, OperationKey, DurationMsKey, BatchSizeKey.
+	IndexUpsertCompleted = capitan.NewSignal("grub.index.upsert.completed", "Vector index upsert completed successfully")
+
+	// IndexUpsertFailed is emitted when an Upsert or UpsertBatch fails.
+	// Fields: CollectionKey, OperationKey, DurationMsKey, BatchSizeKey, ErrorKey.
+	IndexUpsertFailed = capitan.NewSignal("grub.index.upsert.failed", "Vector index upsert failed with error")
+
+	// IndexDeleteCompleted is emitted when a Delete or DeleteBatch succeeds.
+	// Fields: CollectionKey, OperationKey, DurationMsKey, BatchSizeKey.
+	IndexDeleteCompleted = capitan.NewSignal("grub.index.delete.completed", "Vector index delete completed successfully")
+
+	// IndexDeleteFailed is emitted when a Delete or DeleteBatch fails.
+	// Fields: CollectionKey, OperationKey, DurationMsKey, BatchSizeKey, ErrorKey.
+	IndexDeleteFailed = capitan.NewSignal("grub.index.delete.failed", "Vector index delete failed with error")
+
+	// IndexClientFilterUsed is emitted when a Query or Filter falls back to
+	// evaluating its filter client-side. Fields: CollectionKey, OperationKey,
+	// ScannedCountKey.
+	IndexClientFilterUsed = capitan.NewSignal("grub.index.filter.client_side", "Vector index filter evaluated client-side")
+
+	// IndexSchemaDrift is emitted under WithMetadataSchema when stored
+	// metadata read back holds a field outside the schema. Fields:
+	// CollectionKey, FieldKey.
+	IndexSchemaDrift = capitan.NewSignal("grub.index.schema.drift", "Vector index metadata field outside the schema")
+)
+
+// Event field keys for grub signals.
+var (
+	// CollectionKey identifies the collection being operated on (set via WithName).
+	CollectionKey = capitan.NewStringKey("collection")
+
+	// OperationKey identifies the operation (search, query, filter, upsert, upsert_batch, etc).
+	OperationKey = capitan.NewStringKey("operation")
+
+	// KKey contains the requested number of results (k for similarity search, limit for Filter).
+	KKey = capitan.NewIntKey("k")
+
+	// ResultCountKey contains the number of results returned.
+	ResultCountKey = capitan.NewIntKey("result_count")
+
+	// BatchSizeKey contains the number of records written or deleted.
+	BatchSizeKey = capitan.NewIntKey("batch_size")
+
+	// ScannedCountKey contains the number of vectors fetched for client-side filtering.
+	ScannedCountKey = capitan.NewIntKey("scanned_count")
+
+	// DurationMsKey contains the operation duration in milliseconds.
+	DurationMsKey = capitan.NewInt64Key("duration_ms")
+
+	// FieldKey contains the metadata field name.
+	FieldKey = capitan.NewStringKey("field")
+
+	// ErrorKey contains the error message when an operation fails.
+	ErrorKey = capitan.NewStringKey("error")
+)
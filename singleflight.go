@@ -0,0 +1,109 @@
+package grub
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup coalesces concurrent calls for the same key into one fetch
+// whose result every caller shares. The zero value is ready to use.
+type flightGroup[R any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[R]
+}
+
+// flightCall is a fetch in progress and the callers waiting on it.
+type flightCall[R any] struct {
+	done    chan struct{}
+	val     R
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do returns the result of fn for key, joining a fetch already in flight
+// for it. The fetch runs on a context carrying the first caller's values
+// but not its cancellation: a caller whose ctx ends gets ctx.Err() and
+// stops waiting, and the fetch is cancelled only once every caller has
+// gone. Results, errors included, are shared; nothing is kept after the
+// fetch returns.
+func (g *flightGroup[R]) do(ctx context.Context, key string, fn func(ctx context.Context) (R, error)) (R, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[R])
+	}
+	c, ok := g.calls[key]
+	if !ok {
+		fetchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &flightCall[R]{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = c
+		go g.fetch(fetchCtx, key, c, fn)
+	}
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			g.forget(key, c)
+		}
+		g.mu.Unlock()
+		var zero R
+		return zero, ctx.Err()
+	}
+}
+
+// fetch runs fn for c and releases its waiters.
+func (g *flightGroup[R]) fetch(ctx context.Context, key string, c *flightCall[R], fn func(ctx context.Context) (R, error)) {
+	c.val, c.err = fn(ctx)
+	c.cancel()
+	g.mu.Lock()
+	g.forget(key, c)
+	g.mu.Unlock()
+	close(c.done)
+}
+
+// forget removes c from the group, so later callers start a fresh fetch
+// rather than join a finished or abandoned one. g.mu must be held.
+func (g *flightGroup[R]) forget(key string, c *flightCall[R]) {
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+}
+
+// dbFlights holds a Database's coalesced reads, one group per operation so
+// a Get and an Exists for the same key never share a result. Get shares
+// the encoded record, so each caller decodes its own copy.
+type dbFlights struct {
+	get    flightGroup[[]byte]
+	exists flightGroup[bool]
+}
+
+// storeFlights holds a Store's coalesced reads. Get shares the encoded
+// payload, so each caller decodes its own value.
+type storeFlights struct {
+	get    flightGroup[[]byte]
+	exists flightGroup[bool]
+}
+
+type sharedLoadKey struct{}
+
+// isSharedLoad reports whether ctx belongs to a coalesced Database fetch,
+// whose record is copied to each caller and passed through AfterLoad there
+// rather than when it is scanned.
+func isSharedLoad(ctx context.Context) bool {
+	return ctx.Value(sharedLoadKey{}) != nil
+}
+
+// coalesce reports whether a Database read under ctx may join other
+// callers' reads: singleflight is on and ctx asks neither for primary
+// reads, whose callers expect to see their own preceding writes, nor for
+// the record as stored.
+func (d *Database[T]) coalesce(ctx context.Context) bool {
+	return d.flights != nil && ctx.Value(primaryReadsKey{}) == nil && !isRawLoad(ctx)
+}
@@ -0,0 +1,386 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// waitForWaiters blocks until n callers are waiting on the fetch for key.
+func waitForWaiters[R any](t *testing.T, g *flightGroup[R], key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		c := g.calls[key]
+		joined := c != nil && c.waiters == n
+		g.mu.Unlock()
+		if joined {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d callers on %q", n, key)
+}
+
+func TestFlightGroup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("shares one fetch", func(t *testing.T) {
+		var g flightGroup[int]
+		gate := make(chan struct{})
+		var calls int
+		var wg sync.WaitGroup
+		results := make([]int, 10)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = g.do(ctx, "k", func(context.Context) (int, error) {
+					calls++
+					<-gate
+					return 42, nil
+				})
+			}()
+		}
+		waitForWaiters(t, &g, "k", len(results))
+		close(gate)
+		wg.Wait()
+		if calls != 1 {
+			t.Errorf("expected one fetch, got %d", calls)
+		}
+		for i, r := range results {
+			if r != 42 {
+				t.Errorf("caller %d: expected 42, got %d", i, r)
+			}
+		}
+		if len(g.calls) != 0 {
+			t.Errorf("expected no fetches kept, got %d", len(g.calls))
+		}
+	})
+
+	t.Run("a leaving caller does not cancel the others", func(t *testing.T) {
+		var g flightGroup[int]
+		gate := make(chan struct{})
+		fetch := func(fetchCtx context.Context) (int, error) {
+			select {
+			case <-gate:
+				return 42, nil
+			case <-fetchCtx.Done():
+				return 0, fetchCtx.Err()
+			}
+		}
+		leaveCtx, leave := context.WithCancel(ctx)
+		left := make(chan error)
+		go func() {
+			_, err := g.do(leaveCtx, "k", fetch)
+			left <- err
+		}()
+		waitForWaiters(t, &g, "k", 1)
+		stayed := make(chan int)
+		go func() {
+			v, _ := g.do(ctx, "k", fetch)
+			stayed <- v
+		}()
+		waitForWaiters(t, &g, "k", 2)
+		leave()
+		if err := <-left; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the leaving caller to get context.Canceled, got %v", err)
+		}
+		close(gate)
+		if v := <-stayed; v != 42 {
+			t.Errorf("expected the remaining caller to get 42, got %d", v)
+		}
+	})
+
+	t.Run("the last caller leaving cancels the fetch", func(t *testing.T) {
+		var g flightGroup[int]
+		cancelled := make(chan struct{})
+		callCtx, cancel := context.WithCancel(ctx)
+		go func() {
+			waitForWaiters(t, &g, "k", 1)
+			cancel()
+		}()
+		_, err := g.do(callCtx, "k", func(fetchCtx context.Context) (int, error) {
+			<-fetchCtx.Done()
+			close(cancelled)
+			return 0, fetchCtx.Err()
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		select {
+		case <-cancelled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the fetch to be cancelled")
+		}
+	})
+}
+
+// callerKey carries the caller's name into AfterLoad.
+type callerKey struct{}
+
+// callerDBUser is a Database-compatible model recording which caller's
+// AfterLoad ran on it.
+type callerDBUser struct {
+	ID     int    `db:"id" constraints:"primarykey"`
+	Email  string `db:"email"`
+	Name   string `db:"name"`
+	Age    *int   `db:"age"`
+	caller string
+	loads  int
+}
+
+func (u *callerDBUser) AfterLoad(ctx context.Context) error {
+	u.caller, _ = ctx.Value(callerKey{}).(string)
+	u.loads++
+	return nil
+}
+
+func TestDatabase_Singleflight(t *testing.T) {
+	ctx := context.Background()
+	// newFlightDB returns a singleflight Database whose queries wait on the
+	// returned gate.
+	newFlightDB := func(t *testing.T) (*Database[callerDBUser], *mockdb.Capture, *mockdb.Config, chan struct{}) {
+		t.Helper()
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRows(userColumns, []driver.Value{int64(1), "a@example.com", "Alice", nil})
+		gate := make(chan struct{})
+		cfg.SetQueryGate(gate)
+		db, err := NewDatabase[callerDBUser](mockDB, "test_users", testDBRenderer, WithSingleflight())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		return db, capture, cfg, gate
+	}
+	selects := func(capture *mockdb.Capture) int { return execCount(capture, "SELECT") }
+
+	t.Run("concurrent gets share one query", func(t *testing.T) {
+		db, capture, _, gate := newFlightDB(t)
+		const n = 25
+		users := make([]*callerDBUser, n)
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				callCtx := context.WithValue(ctx, callerKey{}, string(rune('a'+i)))
+				users[i], errs[i] = db.Get(callCtx, "1")
+			}()
+		}
+		waitForWaiters(t, &db.flights.get, "1", n)
+		close(gate)
+		wg.Wait()
+
+		if q := selects(capture); q != 1 {
+			t.Errorf("expected one query, got %d", q)
+		}
+		seen := make(map[*callerDBUser]bool)
+		for i, u := range users {
+			if errs[i] != nil {
+				t.Fatalf("caller %d: Get failed: %v", i, errs[i])
+			}
+			if seen[u] {
+				t.Errorf("caller %d: shares its record with another caller", i)
+			}
+			seen[u] = true
+			if u.Name != "Alice" || u.caller != string(rune('a'+i)) || u.loads != 1 {
+				t.Errorf("caller %d: expected its own AfterLoad once, got %+v", i, *u)
+			}
+		}
+	})
+
+	t.Run("callers share no memory", func(t *testing.T) {
+		db, capture, cfg, gate := newFlightDB(t)
+		cfg.SetRows(userColumns, []driver.Value{int64(1), "a@example.com", "Alice", int64(30)})
+		users := make([]*callerDBUser, 2)
+		var wg sync.WaitGroup
+		for i := range users {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var err error
+				if users[i], err = db.Get(ctx, "1"); err != nil {
+					t.Errorf("Get failed: %v", err)
+				}
+			}()
+		}
+		waitForWaiters(t, &db.flights.get, "1", len(users))
+		close(gate)
+		wg.Wait()
+		if t.Failed() {
+			return
+		}
+		if q := selects(capture); q != 1 {
+			t.Errorf("expected one query, got %d", q)
+		}
+		if users[0].Age == nil || users[1].Age == nil || users[0].Age == users[1].Age {
+			t.Fatalf("expected each caller its own Age, got %v and %v", users[0].Age, users[1].Age)
+		}
+		*users[0].Age = 31
+		if *users[1].Age != 30 {
+			t.Errorf("expected the other caller's Age to stay 30, got %d", *users[1].Age)
+		}
+	})
+
+	t.Run("not found is shared", func(t *testing.T) {
+		db, capture, cfg, gate := newFlightDB(t)
+		cfg.SetRows(userColumns)
+		const n = 5
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = db.Get(ctx, "1")
+			}()
+		}
+		waitForWaiters(t, &db.flights.get, "1", n)
+		close(gate)
+		wg.Wait()
+		if q := selects(capture); q != 1 {
+			t.Errorf("expected one query, got %d", q)
+		}
+		for i, err := range errs {
+			if !errors.Is(err, ErrNotFound) {
+				t.Errorf("caller %d: expected ErrNotFound, got %v", i, err)
+			}
+		}
+	})
+
+	t.Run("get and exists do not share", func(t *testing.T) {
+		db, capture, cfg, gate := newFlightDB(t)
+		cfg.SetQueryRows("EXISTS", []string{"exists"}, []driver.Value{true})
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := db.Get(ctx, "1"); err != nil {
+				t.Errorf("Get failed: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if ok, err := db.Exists(ctx, "1"); err != nil || !ok {
+				t.Errorf("expected Exists to be true, got %v, %v", ok, err)
+			}
+		}()
+		waitForWaiters(t, &db.flights.get, "1", 1)
+		waitForWaiters(t, &db.flights.exists, "1", 1)
+		close(gate)
+		wg.Wait()
+		if q := selects(capture); q != 2 {
+			t.Errorf("expected a query each, got %d", q)
+		}
+	})
+
+	t.Run("a cancelled caller leaves the others a result", func(t *testing.T) {
+		db, capture, _, gate := newFlightDB(t)
+		leaveCtx, leave := context.WithCancel(ctx)
+		left := make(chan error)
+		go func() {
+			_, err := db.Get(leaveCtx, "1")
+			left <- err
+		}()
+		waitForWaiters(t, &db.flights.get, "1", 1)
+		stayed := make(chan error)
+		go func() {
+			_, err := db.Get(ctx, "1")
+			stayed <- err
+		}()
+		waitForWaiters(t, &db.flights.get, "1", 2)
+		leave()
+		if err := <-left; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		close(gate)
+		if err := <-stayed; err != nil {
+			t.Errorf("expected the remaining caller to succeed, got %v", err)
+		}
+		if q := selects(capture); q != 1 {
+			t.Errorf("expected one query, got %d", q)
+		}
+	})
+
+	t.Run("primary reads are not coalesced", func(t *testing.T) {
+		db, capture, cfg, _ := newFlightDB(t)
+		cfg.SetQueryGate(nil)
+		for range 2 {
+			if _, err := db.Get(WithPrimaryReads(ctx), "1"); err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+		}
+		if q := selects(capture); q != 2 {
+			t.Errorf("expected two queries, got %d", q)
+		}
+		if len(db.flights.get.calls) != 0 {
+			t.Error("expected primary reads to bypass the group")
+		}
+	})
+}
+
+// gatedStoreProvider counts Gets and holds them until its gate is closed.
+type gatedStoreProvider struct {
+	*mockStoreProvider
+	gate chan struct{}
+	mu   sync.Mutex
+	gets int
+}
+
+func (g *gatedStoreProvider) Get(ctx context.Context, key string) ([]byte, error) {
+	g.mu.Lock()
+	g.gets++
+	g.mu.Unlock()
+	<-g.gate
+	return g.mockStoreProvider.Get(ctx, key)
+}
+
+func TestStore_Singleflight(t *testing.T) {
+	ctx := context.Background()
+	provider := &gatedStoreProvider{mockStoreProvider: newMockStoreProvider(), gate: make(chan struct{})}
+	provider.data["hot"] = []byte(`{"id":1,"name":"Hot"}`)
+	store := NewStore[hookedRecord](provider, WithSingleflight())
+
+	const n = 10
+	records := make([]*hookedRecord, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if records[i], err = store.Get(ctx, "hot"); err != nil {
+				t.Errorf("Get failed: %v", err)
+			}
+		}()
+	}
+	waitForWaiters(t, &store.flights.get, "hot", n)
+	close(provider.gate)
+	wg.Wait()
+
+	if provider.gets != 1 {
+		t.Errorf("expected one provider read, got %d", provider.gets)
+	}
+	for i, rec := range records {
+		if rec == nil || !rec.afterLoadCalled {
+			t.Fatalf("caller %d: expected a decoded record with AfterLoad run, got %+v", i, rec)
+		}
+		for j := range i {
+			if records[j] == rec {
+				t.Errorf("callers %d and %d share a record", j, i)
+			}
+		}
+	}
+
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected ErrNotFound for missing, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package grub
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/edamame"
+)
+
+// Snapshot keeps the result of a query statement in memory and refreshes
+// it every interval in the background, for reads that can afford to be up
+// to interval stale, such as dashboards over heavy aggregate queries.
+//
+// A failed refresh leaves the last good result in place; LastError reports
+// the failure and LastRefreshed how old the result is. Refreshes swap the
+// result whole, so Get never sees a partly refreshed one, and AfterLoad
+// runs once per row per refresh rather than on every Get.
+type Snapshot[T any] struct {
+	db       *Database[T]
+	stmt     edamame.QueryStatement
+	params   map[string]any
+	interval time.Duration
+	now      func() time.Time
+
+	current atomic.Pointer[snapshotResult[T]]
+	sem     chan struct{} // held by the refresh in progress
+
+	mu        sync.Mutex
+	attempted time.Time // start of the last refresh, successful or not
+	lastErr   error
+
+	stop      context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// snapshotResult is one successful refresh.
+type snapshotResult[T any] struct {
+	records   []*T
+	refreshed time.Time
+}
+
+// NewSnapshot returns a Snapshot of db.ExecQuery(stmt, params), refreshed
+// every interval until Close. The first result is loaded by the first Get
+// or Refresh. A non-positive interval disables background and lazy
+// refreshes, leaving only Refresh. Honours WithClock, which times
+// expiry but not the background refresher.
+func NewSnapshot[T any](db *Database[T], stmt edamame.QueryStatement, params map[string]any, interval time.Duration, opts ...Option) *Snapshot[T] {
+	o := applyOptions(opts)
+	now := o.now
+	if now == nil {
+		now = time.Now
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	s := &Snapshot[T]{
+		db:       db,
+		stmt:     stmt,
+		params:   params,
+		interval: interval,
+		now:      now,
+		sem:      make(chan struct{}, 1),
+		stop:     stop,
+		done:     make(chan struct{}),
+	}
+	if interval > 0 {
+		go s.run(ctx)
+	} else {
+		close(s.done)
+	}
+	return s
+}
+
+// Get returns the snapshot's records, loading them first if no refresh has
+// succeeded yet. When the last refresh started more than interval ago,
+// which happens only if the background refresher is closed or falling
+// behind, Get refreshes first and falls back to the last good records if
+// that fails. Each caller gets its own slice of shallow copies, so
+// reassigning fields is safe but maps, slices, and pointers inside a
+// record are shared and must not be modified.
+func (s *Snapshot[T]) Get(ctx context.Context) ([]*T, error) {
+	cur := s.current.Load()
+	if cur == nil || s.expired() {
+		if err := s.refresh(ctx, true); err != nil {
+			if cur = s.current.Load(); cur == nil {
+				return nil, err
+			}
+		}
+		cur = s.current.Load()
+	}
+	records := make([]*T, len(cur.records))
+	for i, rec := range cur.records {
+		c := *rec
+		records[i] = &c
+	}
+	return records, nil
+}
+
+// Refresh runs the statement now and, on success, replaces the snapshot's
+// records. It waits for a refresh already in progress to finish first. On
+// failure the previous records stay in place and the error is both
+// returned and kept for LastError.
+func (s *Snapshot[T]) Refresh(ctx context.Context) error {
+	return s.refresh(ctx, false)
+}
+
+// refresh runs one refresh at a time. A lazy refresh is skipped when the
+// one it waited on left a result that has not expired, so callers that
+// find the snapshot expired together run a single query.
+func (s *Snapshot[T]) refresh(ctx context.Context, lazy bool) error {
+	select {
+	case s.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-s.sem }()
+	if lazy && s.current.Load() != nil && !s.expired() {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.attempted = s.now()
+	s.mu.Unlock()
+	records, err := s.db.ExecQuery(ctx, s.stmt, s.params)
+	s.mu.Lock()
+	s.lastErr = err
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.current.Store(&snapshotResult[T]{records: records, refreshed: s.now()})
+	return nil
+}
+
+// LastError returns the error of the most recent refresh, or nil if it
+// succeeded or none has run.
+func (s *Snapshot[T]) LastError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// LastRefreshed returns when the records being served were loaded, or the
+// zero time if no refresh has succeeded.
+func (s *Snapshot[T]) LastRefreshed() time.Time {
+	if cur := s.current.Load(); cur != nil {
+		return cur.refreshed
+	}
+	return time.Time{}
+}
+
+// Close stops the background refresher, cancelling a refresh it has in
+// progress, and waits for it to return. Get and Refresh keep working
+// afterwards. Close is safe to call more than once.
+func (s *Snapshot[T]) Close() {
+	s.closeOnce.Do(s.stop)
+	<-s.done
+}
+
+// expired reports whether the last refresh started interval or more ago.
+func (s *Snapshot[T]) expired() bool {
+	if s.interval <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.now().Before(s.attempted.Add(s.interval))
+}
+
+// run refreshes the snapshot every interval until ctx is done. Failures
+// are kept for LastError.
+func (s *Snapshot[T]) run(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.Refresh(ctx)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+var allUsers = edamame.NewQueryStatement("all", "", edamame.QuerySpec{})
+
+// fakeClock is a settable clock for WithClock.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// waitForRefresh blocks until snap has a refresh in progress.
+func waitForRefresh[T any](t *testing.T, snap *Snapshot[T]) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(snap.sem) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a refresh")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	alice := []driver.Value{int64(1), "a@example.com", "Alice", nil}
+	bob := []driver.Value{int64(2), "b@example.com", "Bob", nil}
+	// newSnapshot returns a Snapshot of allUsers over a mock returning Alice.
+	newSnapshot := func(t *testing.T, interval time.Duration, opts ...Option) (*Snapshot[callerDBUser], *mockdb.Capture, *mockdb.Config) {
+		t.Helper()
+		mockDB, capture, cfg := mockdb.NewWithConfig()
+		cfg.SetRows(userColumns, alice)
+		db, err := NewDatabase[callerDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		snap := NewSnapshot(db, allUsers, nil, interval, opts...)
+		t.Cleanup(snap.Close)
+		return snap, capture, cfg
+	}
+	names := func(t *testing.T, snap *Snapshot[callerDBUser]) string {
+		t.Helper()
+		users, err := snap.Get(ctx)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		var out string
+		for _, u := range users {
+			out += u.Name
+		}
+		return out
+	}
+	selects := func(capture *mockdb.Capture) int { return execCount(capture, "SELECT") }
+
+	t.Run("serves the cached result within the interval", func(t *testing.T) {
+		clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+		snap, capture, cfg := newSnapshot(t, time.Minute, WithClock(clock.now))
+		if got := names(t, snap); got != "Alice" {
+			t.Fatalf("expected Alice, got %q", got)
+		}
+		if !snap.LastRefreshed().Equal(clock.now()) {
+			t.Errorf("expected LastRefreshed %v, got %v", clock.now(), snap.LastRefreshed())
+		}
+		cfg.SetRows(userColumns, bob)
+		clock.advance(59 * time.Second)
+		if got := names(t, snap); got != "Alice" {
+			t.Errorf("expected the cached Alice, got %q", got)
+		}
+		if q := selects(capture); q != 1 {
+			t.Errorf("expected one query, got %d", q)
+		}
+		clock.advance(time.Second)
+		if got := names(t, snap); got != "Bob" {
+			t.Errorf("expected an expired snapshot to refresh, got %q", got)
+		}
+		if q := selects(capture); q != 2 {
+			t.Errorf("expected two queries, got %d", q)
+		}
+	})
+
+	t.Run("runs AfterLoad once per refresh", func(t *testing.T) {
+		snap, _, _ := newSnapshot(t, time.Minute)
+		for range 3 {
+			users, err := snap.Get(ctx)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			if users[0].loads != 1 {
+				t.Errorf("expected AfterLoad once, got %d", users[0].loads)
+			}
+			users[0].Name = "changed"
+		}
+		if got := names(t, snap); got != "Alice" {
+			t.Errorf("expected callers' changes not to reach the snapshot, got %q", got)
+		}
+	})
+
+	t.Run("a failed refresh keeps the last good result", func(t *testing.T) {
+		clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+		snap, _, cfg := newSnapshot(t, time.Minute, WithClock(clock.now))
+		names(t, snap)
+		refreshed := snap.LastRefreshed()
+
+		boom := errors.New("connection reset")
+		cfg.SetQueryErr(boom)
+		if err := snap.Refresh(ctx); !errors.Is(err, boom) {
+			t.Fatalf("expected Refresh to fail with %v, got %v", boom, err)
+		}
+		clock.advance(2 * time.Minute)
+		if got := names(t, snap); got != "Alice" {
+			t.Errorf("expected the last good Alice, got %q", got)
+		}
+		if !errors.Is(snap.LastError(), boom) {
+			t.Errorf("expected LastError %v, got %v", boom, snap.LastError())
+		}
+		if !snap.LastRefreshed().Equal(refreshed) {
+			t.Errorf("expected LastRefreshed to stay %v, got %v", refreshed, snap.LastRefreshed())
+		}
+
+		cfg.SetQueryErr(nil)
+		cfg.SetRows(userColumns, bob)
+		if err := snap.Refresh(ctx); err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+		if snap.LastError() != nil {
+			t.Errorf("expected LastError to clear, got %v", snap.LastError())
+		}
+		if got := names(t, snap); got != "Bob" {
+			t.Errorf("expected Bob, got %q", got)
+		}
+	})
+
+	t.Run("the first load reports its error", func(t *testing.T) {
+		snap, _, cfg := newSnapshot(t, time.Minute)
+		cfg.SetQueryErrTimes(errors.New("down"), 1)
+		if _, err := snap.Get(ctx); err == nil {
+			t.Fatal("expected the first Get to fail")
+		}
+		if got := names(t, snap); got != "Alice" {
+			t.Errorf("expected the next Get to retry, got %q", got)
+		}
+	})
+
+	t.Run("gets during a refresh see the previous result", func(t *testing.T) {
+		snap, _, cfg := newSnapshot(t, time.Minute)
+		names(t, snap)
+		gate := make(chan struct{})
+		cfg.SetQueryGate(gate)
+		cfg.SetRows(userColumns, bob)
+		refreshed := make(chan error)
+		go func() { refreshed <- snap.Refresh(ctx) }()
+		waitForRefresh(t, snap)
+		if got := names(t, snap); got != "Alice" {
+			t.Errorf("expected Alice while refreshing, got %q", got)
+		}
+		close(gate)
+		if err := <-refreshed; err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+		if got := names(t, snap); got != "Bob" {
+			t.Errorf("expected Bob after the refresh, got %q", got)
+		}
+	})
+
+	t.Run("refreshes in the background", func(t *testing.T) {
+		snap, _, cfg := newSnapshot(t, 5*time.Millisecond)
+		names(t, snap)
+		cfg.SetRows(userColumns, bob)
+		deadline := time.Now().Add(5 * time.Second)
+		for snap.LastError() != nil || names(t, snap) != "Bob" {
+			if time.Now().After(deadline) {
+				t.Fatal("timed out waiting for a background refresh")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	t.Run("close stops the refresher", func(t *testing.T) {
+		snap, capture, cfg := newSnapshot(t, time.Millisecond)
+		cfg.SetQueryGate(make(chan struct{}))
+		waitForRefresh(t, snap)
+		closed := make(chan struct{})
+		go func() {
+			snap.Close()
+			close(closed)
+		}()
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected Close to cancel the refresh in progress")
+		}
+		before := selects(capture)
+		time.Sleep(20 * time.Millisecond)
+		if q := selects(capture); q != before {
+			t.Errorf("expected no refreshes after Close, got %d", q-before)
+		}
+		snap.Close()
+	})
+}
@@ -0,0 +1,252 @@
+package sqlitevector
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/vecna"
+)
+
+// translateFilter converts a vecna.Filter to a SQL WHERE expression over
+// the metadata column and its arguments. A nil filter matches everything.
+//
+// Every condition evaluates to 0 or 1, never NULL, so NOT and the negated
+// operators see a missing field the way grub.MatchFilter does: Ne and Nin
+// match it and every other operator does not.
+func translateFilter(f *vecna.Filter) (string, []any, error) {
+	if f == nil {
+		return "1", nil, nil
+	}
+
+	if err := f.Err(); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", grub.ErrInvalidQuery, err)
+	}
+
+	var args []any
+	where, err := translateNode(f, &args)
+	if err != nil {
+		return "", nil, err
+	}
+	return where, args, nil
+}
+
+// translateNode recursively translates a filter node, appending its
+// arguments to args.
+func translateNode(f *vecna.Filter, args *[]any) (string, error) {
+	switch f.Op() {
+	case vecna.And:
+		return translateLogical(f.Children(), " AND ", args)
+	case vecna.Or:
+		return translateLogical(f.Children(), " OR ", args)
+	case vecna.Not:
+		children := f.Children()
+		if len(children) != 1 {
+			return "", fmt.Errorf("%w: NOT requires exactly one child", grub.ErrInvalidQuery)
+		}
+		child, err := translateNode(children[0], args)
+		if err != nil {
+			return "", err
+		}
+		return "NOT " + child, nil
+	default:
+		cond, err := translateCondition(f, args)
+		if err != nil {
+			return "", err
+		}
+		return "COALESCE(" + cond + ", 0)", nil
+	}
+}
+
+// translateLogical joins the translated children with sep.
+func translateLogical(children []*vecna.Filter, sep string, args *[]any) (string, error) {
+	if len(children) == 0 {
+		return "", fmt.Errorf("%w: logical operator requires at least one condition", grub.ErrInvalidQuery)
+	}
+	parts := make([]string, len(children))
+	for i, child := range children {
+		part, err := translateNode(child, args)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = part
+	}
+	return "(" + strings.Join(parts, sep) + ")", nil
+}
+
+// translateCondition translates a field condition. The result may be NULL
+// when the field is missing; translateNode coalesces it to 0.
+func translateCondition(f *vecna.Filter, args *[]any) (string, error) {
+	path := jsonPath(f.Field())
+	typ := "json_type(metadata, " + path + ")"
+	val := "json_extract(metadata, " + path + ")"
+	value := f.Value()
+
+	switch f.Op() {
+	case vecna.Eq:
+		return translateEq(typ, val, value, args)
+	case vecna.Ne:
+		eq, err := translateEq(typ, val, value, args)
+		if err != nil {
+			return "", err
+		}
+		return "NOT COALESCE(" + eq + ", 0)", nil
+	case vecna.Gt, vecna.Gte, vecna.Lt, vecna.Lte:
+		n, ok := toFloat(value)
+		if !ok {
+			// a range against a non-numeric value never matches
+			return "0", nil
+		}
+		*args = append(*args, n)
+		return fmt.Sprintf("(%s IN ('integer', 'real') AND %s %s ?)", typ, val, rangeOps[f.Op()]), nil
+	case vecna.In:
+		return translateIn(typ, val, value, args)
+	case vecna.Nin:
+		in, err := translateIn(typ, val, value, args)
+		if err != nil {
+			return "", err
+		}
+		return "NOT COALESCE(" + in + ", 0)", nil
+	case vecna.Like:
+		pattern, _ := value.(string)
+		*args = append(*args, likeToGlob(pattern))
+		return fmt.Sprintf("(%s = 'text' AND %s GLOB ?)", typ, val), nil
+	case vecna.Contains:
+		eq, err := translateEq("type", "value", value, args)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s = 'array' AND EXISTS (SELECT 1 FROM json_each(metadata, %s) WHERE %s))", typ, path, eq), nil
+	default:
+		return "", fmt.Errorf("%w: %s", grub.ErrOperatorNotSupported, f.Op())
+	}
+}
+
+// rangeOps maps the range operators to SQL.
+var rangeOps = map[vecna.Op]string{
+	vecna.Gt:  ">",
+	vecna.Gte: ">=",
+	vecna.Lt:  "<",
+	vecna.Lte: "<=",
+}
+
+// translateEq translates an equality test of the JSON value with type typ
+// and SQL value val. Numbers of any type compare numerically; strings,
+// booleans, and null match only values of the same JSON type.
+func translateEq(typ, val string, value any, args *[]any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return fmt.Sprintf("(%s = 'null')", typ), nil
+	case bool:
+		return fmt.Sprintf("(%s = '%t')", typ, v), nil
+	case string:
+		*args = append(*args, v)
+		return fmt.Sprintf("(%s = 'text' AND %s = ?)", typ, val), nil
+	}
+	n, ok := toFloat(value)
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported value type %T", grub.ErrInvalidQuery, value)
+	}
+	*args = append(*args, n)
+	return fmt.Sprintf("(%s IN ('integer', 'real') AND %s = ?)", typ, val), nil
+}
+
+// translateIn translates an IN condition as a disjunction of equality
+// tests. An empty list never matches.
+func translateIn(typ, val string, value any, args *[]any) (string, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "0", nil
+	}
+	if rv.Len() == 0 {
+		return "0", nil
+	}
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		eq, err := translateEq(typ, val, rv.Index(i).Interface(), args)
+		if err != nil {
+			return "", err
+		}
+		parts[i] = eq
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", nil
+}
+
+// translateMap converts a Search equality filter to a WHERE expression
+// matching every key, in key order. A nil or empty filter matches
+// everything.
+func translateMap(filter map[string]any) (string, []any, error) {
+	if len(filter) == 0 {
+		return "1", nil, nil
+	}
+	fields := make([]string, 0, len(filter))
+	for field := range filter {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	var args []any
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		path := jsonPath(field)
+		eq, err := translateEq("json_type(metadata, "+path+")", "json_extract(metadata, "+path+")", filter[field], &args)
+		if err != nil {
+			return "", nil, err
+		}
+		parts[i] = "COALESCE(" + eq + ", 0)"
+	}
+	return strings.Join(parts, " AND "), args, nil
+}
+
+// jsonPath returns a SQL string literal holding the JSON path of a
+// top-level metadata field, quoted so any field name is a single label.
+func jsonPath(field string) string {
+	label := `"` + strings.ReplaceAll(field, `"`, `\"`) + `"`
+	return "'$." + strings.ReplaceAll(label, "'", "''") + "'"
+}
+
+// likeToGlob converts a LIKE pattern to a case-sensitive GLOB pattern: %
+// becomes *, _ becomes ?, and GLOB's own metacharacters are bracketed so
+// they match literally.
+func likeToGlob(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteByte('*')
+		case '_':
+			b.WriteByte('?')
+		case '*', '?', '[':
+			b.WriteByte('[')
+			b.WriteRune(r)
+			b.WriteByte(']')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// toFloat converts any Go numeric value or json.Number to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case nil, bool, string:
+		return 0, false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,208 @@
+package sqlitevector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/vecna"
+)
+
+// Test metadata types
+type testMeta struct {
+	Category string
+	Score    int
+	Status   string
+	Tags     []string
+	Deleted  bool
+	Data     any
+}
+
+func mustBuilder(t *testing.T) *vecna.Builder[testMeta] {
+	t.Helper()
+	b, err := vecna.New[testMeta]()
+	if err != nil {
+		t.Fatalf("failed to create builder: %v", err)
+	}
+	return b
+}
+
+func TestTranslateFilter_Nil(t *testing.T) {
+	where, args, err := translateFilter(nil)
+	if err != nil || where != "1" || args != nil {
+		t.Errorf("expected a match-all expression, got %q, %v, %v", where, args, err)
+	}
+}
+
+func TestTranslateFilter_InvalidFilter(t *testing.T) {
+	b := mustBuilder(t)
+	f := b.Where("").Eq("test") // Empty field name causes error
+
+	if _, _, err := translateFilter(f); !errors.Is(err, grub.ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery, got %v", err)
+	}
+}
+
+func TestTranslateFilter_Conditions(t *testing.T) {
+	b := mustBuilder(t)
+
+	tests := []struct {
+		name   string
+		filter *vecna.Filter
+		want   string
+		args   []any
+	}{
+		{
+			"eq string", b.Where("Category").Eq("test"),
+			`COALESCE((json_type(metadata, '$."Category"') = 'text' AND json_extract(metadata, '$."Category"') = ?), 0)`,
+			[]any{"test"},
+		},
+		{
+			"eq bool", b.Where("Deleted").Eq(true),
+			`COALESCE((json_type(metadata, '$."Deleted"') = 'true'), 0)`,
+			nil,
+		},
+		{
+			"gt", b.Where("Score").Gt(50),
+			`COALESCE((json_type(metadata, '$."Score"') IN ('integer', 'real') AND json_extract(metadata, '$."Score"') > ?), 0)`,
+			[]any{50.0},
+		},
+		{
+			"like", b.Where("Category").Like("a_*%"),
+			`COALESCE((json_type(metadata, '$."Category"') = 'text' AND json_extract(metadata, '$."Category"') GLOB ?), 0)`,
+			[]any{"a?[*]*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args, err := translateFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("translateFilter failed: %v", err)
+			}
+			if where != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, where)
+			}
+			if !slices.Equal(args, tt.args) {
+				t.Errorf("expected args %v, got %v", tt.args, args)
+			}
+		})
+	}
+}
+
+func TestTranslateFilter_UnsupportedValue(t *testing.T) {
+	b := mustBuilder(t)
+	f := b.Where("Data").Eq(struct{}{})
+
+	if _, _, err := translateFilter(f); !errors.Is(err, grub.ErrInvalidQuery) {
+		t.Errorf("expected ErrInvalidQuery, got %v", err)
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	if got, want := jsonPath(`it's "x"`), `'$."it''s \"x\""'`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+// TestTranslateFilter_MatchesMatchFilter checks that every operator selects
+// exactly the rows grub.MatchFilter accepts, including rows where the field
+// is missing, null, or of another type.
+func TestTranslateFilter_MatchesMatchFilter(t *testing.T) {
+	ctx := context.Background()
+	p, err := Open(ctx, Config{})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = p.Close() }()
+
+	docs := []string{
+		`{"Category":"test","Score":60,"Status":"active","Tags":["alpha","beta"],"Deleted":false}`,
+		`{"Category":"Test","Score":50.5,"Status":"archived","Tags":["gamma"],"Deleted":true}`,
+		`{"Category":"testing","Score":"60","Tags":[1,2],"Data":null}`,
+		`{"Category":"a*b","Score":-1,"Tags":"alpha","Data":{"x":1}}`,
+		`{"Category":"x_y","Status":"active","Data":[1]}`,
+		`{}`,
+	}
+	records := make([]grub.VectorRecord, len(docs))
+	metadata := make(map[uuid.UUID]map[string]any, len(docs))
+	for i, doc := range docs {
+		id := uuid.New()
+		records[i] = grub.VectorRecord{ID: id, Vector: []float32{1, 0, 0}, Metadata: []byte(doc)}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(doc), &m); err != nil {
+			t.Fatal(err)
+		}
+		metadata[id] = m
+	}
+	if err := p.UpsertBatch(ctx, records); err != nil {
+		t.Fatalf("UpsertBatch failed: %v", err)
+	}
+	// a vector without metadata matches only the negated operators
+	bare := uuid.New()
+	if err := p.Upsert(ctx, bare, []float32{1, 0, 0}, nil); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	metadata[bare] = nil
+
+	b := mustBuilder(t)
+	filters := []*vecna.Filter{
+		b.Where("Category").Eq("test"),
+		b.Where("Category").Ne("test"),
+		b.Where("Score").Eq(60),
+		b.Where("Score").Eq(50.5),
+		b.Where("Score").Gt(50),
+		b.Where("Score").Gte(60),
+		b.Where("Score").Lt(0),
+		b.Where("Score").Lte(50.5),
+		b.Where("Status").In("active", "pending"),
+		b.Where("Status").Nin("active"),
+		b.Where("Score").In(60, -1),
+		b.Where("Category").Like("test%"),
+		b.Where("Category").Like("_est"),
+		b.Where("Category").Like("a*b"),
+		b.Where("Category").Like("x_y"),
+		b.Where("Tags").Contains("alpha"),
+		b.Where("Tags").Contains(2),
+		b.Where("Deleted").Eq(true),
+		b.Where("Deleted").Ne(true),
+		b.Where("Data").Eq(nil),
+		b.Not(b.Where("Status").Eq("active")),
+		b.And(b.Where("Category").Like("test%"), b.Where("Score").Gte(60)),
+		b.Or(b.Where("Status").Eq("archived"), b.Not(b.Where("Tags").Contains("alpha"))),
+	}
+
+	for i, f := range filters {
+		t.Run(fmt.Sprintf("%d_%s", i, f.Op()), func(t *testing.T) {
+			var want []uuid.UUID
+			for id, m := range metadata {
+				ok, err := grub.MatchFilter(f, m)
+				if err != nil {
+					t.Fatalf("MatchFilter failed: %v", err)
+				}
+				if ok {
+					want = append(want, id)
+				}
+			}
+			results, err := p.Filter(ctx, f, 0)
+			if err != nil {
+				t.Fatalf("Filter failed: %v", err)
+			}
+			var got []uuid.UUID
+			for _, r := range results {
+				got = append(got, r.ID)
+			}
+			cmp := func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) }
+			slices.SortFunc(want, cmp)
+			slices.SortFunc(got, cmp)
+			if !slices.Equal(got, want) {
+				t.Errorf("expected %d matches %v, got %d %v", len(want), want, len(got), got)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/zoobzio/grub/sqlitevector
+
+go 1.24.0
+
+require (
+	github.com/zoobzio/grub v0.0.0
+	modernc.org/sqlite v1.42.2
+)
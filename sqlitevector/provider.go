@@ -0,0 +1,516 @@
+// Package sqlitevector provides a grub VectorProvider implementation backed
+// by SQLite, for tests and small deployments that want vector search
+// without running a vector database.
+//
+// Vectors are stored as little-endian float32 BLOBs and metadata as JSON
+// text in a single table. Searches scan every row matching the filter and
+// rank them in Go, so they are exact but linear in the size of the table.
+// The provider uses the pure-Go modernc.org/sqlite driver, which cannot
+// load native extensions such as sqlite-vec.
+//
+// Filters are translated to SQL over the metadata column with the same
+// semantics as grub.MatchFilter, so the provider supports every vecna
+// operator and serves as the reference for how operators behave.
+package sqlitevector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/grub/internal/shared"
+	"github.com/zoobzio/vecna"
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// Config holds configuration for the SQLite vector provider.
+type Config struct {
+	// Path is the database file, created if it does not exist. Defaults to
+	// ":memory:", which keeps vectors only until the provider is closed.
+	Path string
+
+	// Table is the table vectors are stored in. Defaults to "vectors".
+	Table string
+
+	// Dimension, if set, is the only vector length Upsert accepts.
+	Dimension int
+
+	// Metric is the distance searches rank by: cosine, L2, or inner
+	// product. Defaults to cosine.
+	Metric grub.DistanceMetric
+}
+
+// Provider implements grub.VectorProvider for SQLite.
+type Provider struct {
+	db     *sql.DB
+	config Config
+	owned  bool // db was opened by Open and is closed by Close
+}
+
+// Open opens the database at config.Path and creates the table if it does
+// not exist. The provider holds a single connection, which keeps an
+// in-memory database alive and serializes writes; call Close to release
+// it.
+func Open(ctx context.Context, config Config) (*Provider, error) {
+	if config.Path == "" {
+		config.Path = ":memory:"
+	}
+	db, err := sql.Open("sqlite", config.Path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	p := New(db, config)
+	p.owned = true
+	if err := p.EnsureCollection(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// New creates a SQLite vector provider over an open database. The table
+// must already exist; see EnsureCollection. config.Path is ignored.
+func New(db *sql.DB, config Config) *Provider {
+	if config.Table == "" {
+		config.Table = "vectors"
+	}
+	if config.Metric == "" {
+		config.Metric = grub.DistanceCosine
+	}
+	return &Provider{db: db, config: config}
+}
+
+// Close closes the database if the provider opened it.
+func (p *Provider) Close() error {
+	if !p.owned {
+		return nil
+	}
+	return p.db.Close()
+}
+
+// ScoreKind reports how search scores are measured for Config.Metric.
+// It implements grub.VectorScorer. Scores are distances: 1 - cos for
+// cosine, the Euclidean distance for L2, and -dot for inner product.
+func (p *Provider) ScoreKind() grub.ScoreKind {
+	switch metric := p.config.Metric; metric {
+	case grub.DistanceCosine, grub.DistanceL2, grub.DistanceInnerProduct:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreDistance}
+	}
+	return grub.ScoreKind{}
+}
+
+// OrderByID implements grub.VectorIDOrderer. List and Filter already
+// return vectors in ascending ID order, so the provider is its own ordered
+// view.
+func (p *Provider) OrderByID() grub.VectorProvider {
+	return p
+}
+
+// EnsureCollection creates the table if it does not exist.
+func (p *Provider) EnsureCollection(ctx context.Context) error {
+	if _, err := p.distanceFunc(); err != nil {
+		return err
+	}
+	_, err := p.db.ExecContext(ctx, fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s (id TEXT PRIMARY KEY, vector BLOB NOT NULL, metadata TEXT)`,
+		p.table()))
+	return err
+}
+
+// table returns the quoted table name.
+func (p *Provider) table() string {
+	return `"` + strings.ReplaceAll(p.config.Table, `"`, `""`) + `"`
+}
+
+// Upsert stores or updates a vector with associated metadata.
+func (p *Provider) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata []byte) error {
+	return p.UpsertBatch(ctx, []grub.VectorRecord{{ID: id, Vector: vector, Metadata: metadata}})
+}
+
+// UpsertBatch stores or updates multiple vectors in one transaction.
+func (p *Provider) UpsertBatch(ctx context.Context, vectors []grub.VectorRecord) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+	for _, v := range vectors {
+		if p.config.Dimension > 0 && len(v.Vector) != p.config.Dimension {
+			return fmt.Errorf("%w: expected %d, got %d", grub.ErrDimensionMismatch, p.config.Dimension, len(v.Vector))
+		}
+		if v.Metadata != nil && !json.Valid(v.Metadata) {
+			return fmt.Errorf("sqlitevector: metadata for %s is not valid JSON", v.ID)
+		}
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (id, vector, metadata) VALUES (?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET vector = excluded.vector, metadata = excluded.metadata`,
+		p.table()))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, v := range vectors {
+		var metadata any
+		if v.Metadata != nil {
+			metadata = string(v.Metadata)
+		}
+		if _, err := stmt.ExecContext(ctx, v.ID.String(), encodeVector(v.Vector), metadata); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// encodeVector packs vector as little-endian float32s.
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// decodeVector unpacks a vector packed by encodeVector.
+func decodeVector(data []byte) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("sqlitevector: vector of %d bytes is not a float32 array", len(data))
+	}
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return vector, nil
+}
+
+// Get retrieves a vector by ID.
+func (p *Provider) Get(ctx context.Context, id uuid.UUID) ([]float32, *grub.VectorInfo, error) {
+	var data, metadata []byte
+	err := p.db.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT vector, metadata FROM %s WHERE id = ?`, p.table()),
+		id.String(),
+	).Scan(&data, &metadata)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, grub.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, shared.CtxErr(ctx, err)
+	}
+	vector, err := decodeVector(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return vector, &grub.VectorInfo{
+		ID:        id,
+		Dimension: len(vector),
+		Metadata:  metadata,
+	}, nil
+}
+
+// Delete removes a vector by ID.
+func (p *Provider) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, p.table()), id.String())
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return grub.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteBatch removes multiple vectors by ID in one statement. Missing IDs
+// are ignored.
+func (p *Provider) DeleteBatch(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := p.db.ExecContext(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE id IN (SELECT value FROM json_each(?))`, p.table()),
+		idList(ids))
+	return err
+}
+
+// idList encodes ids as a JSON array, bound as a single parameter and
+// expanded with json_each so a batch is never limited by SQLite's
+// parameter count.
+func idList(ids []uuid.UUID) string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	data, _ := json.Marshal(strs)
+	return string(data)
+}
+
+// Search performs similarity search and returns the k nearest neighbors.
+func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter map[string]any) ([]grub.VectorResult, error) {
+	return p.SearchPage(ctx, vector, k, 0, filter)
+}
+
+// SearchPage performs similarity search, skipping the first offset results.
+func (p *Provider) SearchPage(ctx context.Context, vector []float32, k, offset int, filter map[string]any) ([]grub.VectorResult, error) {
+	where, args, err := translateMap(filter)
+	if err != nil {
+		return nil, err
+	}
+	return p.knn(ctx, vector, k, offset, where, args)
+}
+
+// Query performs similarity search with vecna filter support.
+func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	return p.QueryPage(ctx, vector, k, 0, filter)
+}
+
+// QueryPage performs filtered similarity search, skipping the first offset
+// results.
+func (p *Provider) QueryPage(ctx context.Context, vector []float32, k, offset int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	where, args, err := translateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return p.knn(ctx, vector, k, offset, where, args)
+}
+
+// knn scans the rows matching where, ranks them by distance to vector, and
+// returns up to k after skipping offset, nearest first. Ties are broken by
+// ID so pages are stable.
+func (p *Provider) knn(ctx context.Context, vector []float32, k, offset int, where string, args []any) ([]grub.VectorResult, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	distance, err := p.distanceFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT id, vector, metadata FROM %s WHERE %s`, p.table(), where),
+		args...)
+	if err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []grub.VectorResult
+	for rows.Next() {
+		var id string
+		var data, metadata []byte
+		if err := rows.Scan(&id, &data, &metadata); err != nil {
+			return nil, err
+		}
+		stored, err := decodeVector(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(stored) != len(vector) {
+			return nil, fmt.Errorf("%w: expected %d, got %d", grub.ErrDimensionMismatch, len(stored), len(vector))
+		}
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, grub.VectorResult{
+			ID:       parsed,
+			Score:    float32(distance(vector, stored)),
+			Metadata: metadata,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score < results[j].Score
+		}
+		return results[i].ID.String() < results[j].ID.String()
+	})
+	if offset >= len(results) {
+		return nil, nil
+	}
+	results = results[offset:]
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results, nil
+}
+
+// distanceFunc returns the distance function for Config.Metric.
+func (p *Provider) distanceFunc() (func(a, b []float32) float64, error) {
+	switch p.config.Metric {
+	case grub.DistanceCosine:
+		return cosineDistance, nil
+	case grub.DistanceL2:
+		return l2Distance, nil
+	case grub.DistanceInnerProduct:
+		return innerProductDistance, nil
+	}
+	return nil, fmt.Errorf("sqlitevector: unsupported metric %q", p.config.Metric)
+}
+
+// cosineDistance returns 1 - cos(a, b); a zero vector is treated as
+// orthogonal to everything.
+func cosineDistance(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 1
+	}
+	return 1 - dot/(math.Sqrt(na)*math.Sqrt(nb))
+}
+
+// l2Distance returns the Euclidean distance between a and b.
+func l2Distance(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// innerProductDistance returns -dot(a, b), so larger products rank first.
+func innerProductDistance(a, b []float32) float64 {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return -dot
+}
+
+// Filter returns vectors matching the metadata filter without similarity
+// search, in ascending ID order. Limit of 0 returns all matches.
+func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int) ([]grub.VectorResult, error) {
+	where, args, err := translateFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`SELECT id, vector, metadata FROM %s WHERE %s ORDER BY id`, p.table(), where)
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []grub.VectorResult
+	for rows.Next() {
+		var id string
+		var data, metadata []byte
+		if err := rows.Scan(&id, &data, &metadata); err != nil {
+			return nil, err
+		}
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		vector, err := decodeVector(data)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, grub.VectorResult{ID: parsed, Vector: vector, Metadata: metadata})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+	return results, nil
+}
+
+// List returns vector IDs in ascending order. Limit of 0 means no limit.
+func (p *Provider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	query := fmt.Sprintf(`SELECT id FROM %s ORDER BY id`, p.table())
+	var args []any
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	return p.queryIDs(ctx, query, args...)
+}
+
+// Exists checks whether a vector ID exists.
+func (p *Provider) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var one int
+	err := p.db.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT 1 FROM %s WHERE id = ?`, p.table()),
+		id.String(),
+	).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, shared.CtxErr(ctx, err)
+	}
+	return true, nil
+}
+
+// ExistsBatch checks many vector IDs in a single query.
+func (p *Provider) ExistsBatch(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	result := make(map[uuid.UUID]bool, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	found, err := p.queryIDs(ctx,
+		fmt.Sprintf(`SELECT id FROM %s WHERE id IN (SELECT value FROM json_each(?))`, p.table()),
+		idList(ids))
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range found {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// queryIDs runs a query selecting a single id column.
+func (p *Provider) queryIDs(ctx context.Context, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, parsed)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,177 @@
+package sqlitevector
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub"
+)
+
+func mustOpen(t *testing.T, config Config) *Provider {
+	t.Helper()
+	p, err := Open(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func TestOpen_Defaults(t *testing.T) {
+	p := mustOpen(t, Config{})
+	want := Config{Path: ":memory:", Table: "vectors", Metric: grub.DistanceCosine}
+	if p.config != want {
+		t.Errorf("expected defaults %+v, got %+v", want, p.config)
+	}
+}
+
+func TestOpen_UnsupportedMetric(t *testing.T) {
+	if _, err := Open(context.Background(), Config{Metric: "hamming"}); err == nil {
+		t.Error("expected an error for an unsupported metric")
+	}
+}
+
+func TestScoreKind(t *testing.T) {
+	for _, metric := range []grub.DistanceMetric{grub.DistanceCosine, grub.DistanceL2, grub.DistanceInnerProduct} {
+		want := grub.ScoreKind{Metric: metric, Measure: grub.ScoreDistance}
+		if got := New(nil, Config{Metric: metric}).ScoreKind(); got != want {
+			t.Errorf("%s: expected %+v, got %+v", metric, want, got)
+		}
+	}
+	if got := New(nil, Config{Metric: "hamming"}).ScoreKind(); got != (grub.ScoreKind{}) {
+		t.Errorf("expected the zero ScoreKind for an unknown metric, got %+v", got)
+	}
+}
+
+func TestDistances(t *testing.T) {
+	a, b := []float32{1, 0}, []float32{3, 4}
+	tests := []struct {
+		name string
+		fn   func(a, b []float32) float64
+		want float64
+	}{
+		{"cosine", cosineDistance, 0.4},
+		{"l2", l2Distance, math.Sqrt(20)},
+		{"inner product", innerProductDistance, -3},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(a, b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+	if got := cosineDistance([]float32{0, 0}, b); got != 1 {
+		t.Errorf("expected a zero vector to be orthogonal, got %v", got)
+	}
+}
+
+func TestVectorEncoding(t *testing.T) {
+	vector := []float32{0.5, -1.25, 3}
+	decoded, err := decodeVector(encodeVector(vector))
+	if err != nil || !slices.Equal(decoded, vector) {
+		t.Errorf("expected %v, got %v, %v", vector, decoded, err)
+	}
+	if _, err := decodeVector(make([]byte, 5)); err == nil {
+		t.Error("expected an error for a truncated vector")
+	}
+}
+
+func TestUpsert_DimensionMismatch(t *testing.T) {
+	p := mustOpen(t, Config{Dimension: 3})
+	err := p.Upsert(context.Background(), uuid.New(), []float32{1, 2}, nil)
+	if !errors.Is(err, grub.ErrDimensionMismatch) {
+		t.Errorf("expected ErrDimensionMismatch, got %v", err)
+	}
+}
+
+func TestUpsert_InvalidMetadata(t *testing.T) {
+	p := mustOpen(t, Config{})
+	if err := p.Upsert(context.Background(), uuid.New(), []float32{1}, []byte("{")); err == nil {
+		t.Error("expected an error for metadata that is not JSON")
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	ctx := context.Background()
+	p := mustOpen(t, Config{Metric: grub.DistanceL2})
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for i, id := range ids {
+		if err := p.Upsert(ctx, id, []float32{float32(i), 0}, nil); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+	}
+
+	results, err := p.SearchPage(ctx, []float32{0, 0}, 1, 1, nil)
+	if err != nil {
+		t.Fatalf("SearchPage failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != ids[1] || results[0].Score != 1 {
+		t.Errorf("expected the second nearest at distance 1, got %+v", results)
+	}
+	if results, _ := p.SearchPage(ctx, []float32{0, 0}, 5, 3, nil); len(results) != 0 {
+		t.Errorf("expected no results past the end, got %d", len(results))
+	}
+	if _, err := p.Search(ctx, []float32{0, 0, 0}, 1, nil); !errors.Is(err, grub.ErrDimensionMismatch) {
+		t.Errorf("expected ErrDimensionMismatch, got %v", err)
+	}
+}
+
+func TestDeleteAndExistsBatch(t *testing.T) {
+	ctx := context.Background()
+	p := mustOpen(t, Config{})
+	a, b, missing := uuid.New(), uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{a, b} {
+		if err := p.Upsert(ctx, id, []float32{1}, nil); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+	}
+
+	exists, err := p.ExistsBatch(ctx, []uuid.UUID{a, b, missing})
+	if err != nil {
+		t.Fatalf("ExistsBatch failed: %v", err)
+	}
+	if !exists[a] || !exists[b] || exists[missing] {
+		t.Errorf("expected a and b to exist, got %v", exists)
+	}
+
+	if err := p.Delete(ctx, missing); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := p.DeleteBatch(ctx, []uuid.UUID{a, missing}); err != nil {
+		t.Fatalf("DeleteBatch failed: %v", err)
+	}
+	ids, err := p.List(ctx, 0)
+	if err != nil || !slices.Equal(ids, []uuid.UUID{b}) {
+		t.Errorf("expected only b to remain, got %v, %v", ids, err)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	ctx := context.Background()
+	config := Config{Path: filepath.Join(t.TempDir(), "vectors.db"), Table: "docs"}
+	id := uuid.New()
+
+	p, err := Open(ctx, config)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := p.Upsert(ctx, id, []float32{1, 2, 3}, []byte(`{"category":"a"}`)); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	p = mustOpen(t, config)
+	vector, info, err := p.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get after reopening failed: %v", err)
+	}
+	if !slices.Equal(vector, []float32{1, 2, 3}) || string(info.Metadata) != `{"category":"a"}` {
+		t.Errorf("expected the stored vector and metadata, got %v and %s", vector, info.Metadata)
+	}
+}
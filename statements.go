@@ -0,0 +1,170 @@
+package grub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zoobzio/edamame"
+)
+
+// StatementKind identifies which Exec method a registered statement runs through.
+type StatementKind string
+
+// Statement kinds.
+const (
+	StatementQuery     StatementKind = "query"
+	StatementSelect    StatementKind = "select"
+	StatementUpdate    StatementKind = "update"
+	StatementAggregate StatementKind = "aggregate"
+)
+
+// StatementInfo describes a registered statement.
+type StatementInfo struct {
+	Name        string
+	Description string
+	Kind        StatementKind
+	Params      []edamame.ParamSpec
+	Tags        []string
+}
+
+// StatementResult holds the outcome of ExecNamed. Which field is set depends
+// on Kind: Records for queries, Record for selects and updates, and Value
+// for aggregates.
+type StatementResult[T any] struct {
+	Kind    StatementKind
+	Records []*T
+	Record  *T
+	Value   float64
+}
+
+// statementRegistry holds a Database's named statements.
+type statementRegistry struct {
+	mu      sync.RWMutex
+	entries map[string]registeredStatement
+}
+
+type registeredStatement struct {
+	info StatementInfo
+	stmt any
+}
+
+// RegisterQuery registers a query statement under its name for ExecNamed.
+// The statement is rendered immediately, so unknown fields fail here rather
+// than at first execution. Returns ErrDuplicate if the name is taken.
+// StatementInfo.Params lists every param ExecNamed accepts.
+func (d *Database[T]) RegisterQuery(stmt edamame.QueryStatement) error {
+	query, err := d.executor.RenderQuery(stmt)
+	return d.register(StatementInfo{
+		Name:        stmt.Name(),
+		Description: stmt.Description(),
+		Kind:        StatementQuery,
+		Params:      sqlParams(stmt.Params(), query),
+		Tags:        stmt.Tags(),
+	}, stmt, err)
+}
+
+// RegisterSelect registers a select statement under its name for ExecNamed.
+// See RegisterQuery for validation.
+func (d *Database[T]) RegisterSelect(stmt edamame.SelectStatement) error {
+	query, err := d.executor.RenderSelect(stmt)
+	return d.register(StatementInfo{
+		Name:        stmt.Name(),
+		Description: stmt.Description(),
+		Kind:        StatementSelect,
+		Params:      sqlParams(stmt.Params(), query),
+		Tags:        stmt.Tags(),
+	}, stmt, err)
+}
+
+// RegisterUpdate registers an update statement under its name for ExecNamed.
+// See RegisterQuery for validation.
+func (d *Database[T]) RegisterUpdate(stmt edamame.UpdateStatement) error {
+	query, err := d.executor.RenderUpdate(stmt)
+	return d.register(StatementInfo{
+		Name:        stmt.Name(),
+		Description: stmt.Description(),
+		Kind:        StatementUpdate,
+		Params:      sqlParams(stmt.Params(), query),
+		Tags:        stmt.Tags(),
+	}, stmt, err)
+}
+
+// RegisterAggregate registers an aggregate statement under its name for
+// ExecNamed. See RegisterQuery for validation.
+func (d *Database[T]) RegisterAggregate(stmt edamame.AggregateStatement) error {
+	query, err := d.executor.RenderAggregate(stmt)
+	return d.register(StatementInfo{
+		Name:        stmt.Name(),
+		Description: stmt.Description(),
+		Kind:        StatementAggregate,
+		Params:      sqlParams(stmt.Params(), query),
+		Tags:        stmt.Tags(),
+	}, stmt, err)
+}
+
+// register stores stmt unless rendering failed or the name is taken.
+func (d *Database[T]) register(info StatementInfo, stmt any, renderErr error) error {
+	if renderErr != nil {
+		return d.wrapErr("register_statement", info.Name, renderErr)
+	}
+	d.statements.mu.Lock()
+	defer d.statements.mu.Unlock()
+	if _, ok := d.statements.entries[info.Name]; ok {
+		return d.wrapErr("register_statement", info.Name, ErrDuplicate)
+	}
+	if d.statements.entries == nil {
+		d.statements.entries = make(map[string]registeredStatement)
+	}
+	d.statements.entries[info.Name] = registeredStatement{info: info, stmt: stmt}
+	return nil
+}
+
+// ExecNamed runs the statement registered under name through the Exec method
+// matching its kind. QueryAll ("query") and CountAll ("count") are registered
+// by default. Returns ErrStatementNotFound, listing the registered names, if
+// name is unknown.
+func (d *Database[T]) ExecNamed(ctx context.Context, name string, params map[string]any) (*StatementResult[T], error) {
+	d.statements.mu.RLock()
+	entry, ok := d.statements.entries[name]
+	d.statements.mu.RUnlock()
+	if !ok {
+		known := make([]string, 0)
+		for _, info := range d.Statements() {
+			known = append(known, info.Name)
+		}
+		return nil, d.wrapErr("exec_named", name,
+			fmt.Errorf("%w: %q (known: %s)", ErrStatementNotFound, name, strings.Join(known, ", ")))
+	}
+
+	result := &StatementResult[T]{Kind: entry.info.Kind}
+	var err error
+	switch stmt := entry.stmt.(type) {
+	case edamame.QueryStatement:
+		result.Records, err = d.ExecQuery(ctx, stmt, params)
+	case edamame.SelectStatement:
+		result.Record, err = d.ExecSelect(ctx, stmt, params)
+	case edamame.UpdateStatement:
+		result.Record, err = d.ExecUpdate(ctx, stmt, params)
+	case edamame.AggregateStatement:
+		result.Value, err = d.ExecAggregate(ctx, stmt, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Statements returns the registered statements sorted by name.
+func (d *Database[T]) Statements() []StatementInfo {
+	d.statements.mu.RLock()
+	defer d.statements.mu.RUnlock()
+	infos := make([]StatementInfo, 0, len(d.statements.entries))
+	for _, e := range d.statements.entries {
+		infos = append(infos, e.info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
@@ -0,0 +1,145 @@
+package grub
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestDatabase_RegisterStatements(t *testing.T) {
+	mockDB, _ := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	t.Run("defaults registered", func(t *testing.T) {
+		infos := db.Statements()
+		if len(infos) != 2 || infos[0].Name != "count" || infos[1].Name != "query" {
+			t.Fatalf("expected count and query, got %+v", infos)
+		}
+		if infos[0].Kind != StatementAggregate || infos[1].Kind != StatementQuery {
+			t.Errorf("unexpected kinds %+v", infos)
+		}
+	})
+
+	t.Run("unknown field fails fast", func(t *testing.T) {
+		stmts := []func() error{
+			func() error {
+				return db.RegisterQuery(edamame.NewQueryStatement("bad-query", "", edamame.QuerySpec{
+					Where: []edamame.ConditionSpec{{Field: "emial", Operator: "=", Param: "email"}},
+				}))
+			},
+			func() error {
+				return db.RegisterSelect(edamame.NewSelectStatement("bad-select", "", edamame.SelectSpec{
+					Where: []edamame.ConditionSpec{{Field: "emial", Operator: "=", Param: "email"}},
+				}))
+			},
+			func() error {
+				return db.RegisterUpdate(edamame.NewUpdateStatement("bad-update", "", edamame.UpdateSpec{
+					Set:   map[string]string{"nmae": "name"},
+					Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+				}))
+			},
+			func() error {
+				return db.RegisterAggregate(edamame.NewAggregateStatement("bad-aggregate", "", edamame.AggSum, edamame.AggregateSpec{
+					Field: "aeg",
+				}))
+			},
+		}
+		for i, register := range stmts {
+			err := register()
+			if err == nil {
+				t.Errorf("statement %d: expected validation error", i)
+				continue
+			}
+			var gErr *Error
+			if !errors.As(err, &gErr) || gErr.Op != "register_statement" {
+				t.Errorf("statement %d: expected register_statement error, got %v", i, err)
+			}
+		}
+		if len(db.Statements()) != 2 {
+			t.Errorf("invalid statements must not be registered, got %+v", db.Statements())
+		}
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := db.RegisterQuery(edamame.NewQueryStatement("query", "", edamame.QuerySpec{}))
+		if !errors.Is(err, ErrDuplicate) {
+			t.Errorf("expected ErrDuplicate, got %v", err)
+		}
+	})
+}
+
+func TestDatabase_ExecNamed(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	byEmail := edamame.NewSelectStatement("by-email", "User by email", edamame.SelectSpec{
+		Where: []edamame.ConditionSpec{{Field: "email", Operator: "=", Param: "email"}},
+	})
+	rename := edamame.NewUpdateStatement("rename", "Rename user", edamame.UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	maxAge := edamame.NewAggregateStatement("max-age", "Oldest age", edamame.AggMax, edamame.AggregateSpec{Field: "age"})
+	for _, err := range []error{db.RegisterSelect(byEmail), db.RegisterUpdate(rename), db.RegisterAggregate(maxAge)} {
+		if err != nil {
+			t.Fatalf("register failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]any
+		kind   StatementKind
+		sql    string
+	}{
+		{"query", nil, StatementQuery, "SELECT"},
+		{"by-email", map[string]any{"email": "a@example.com"}, StatementSelect, `"email" = ?`},
+		{"rename", map[string]any{"id": 1, "name": "A"}, StatementUpdate, "UPDATE"},
+		{"max-age", nil, StatementAggregate, `MAX("age")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture.Reset()
+			result, err := db.ExecNamed(ctx, tt.name, tt.params)
+			// The mock driver returns no rows, so only queries succeed.
+			if tt.kind == StatementQuery {
+				if err != nil {
+					t.Fatalf("ExecNamed failed: %v", err)
+				}
+				if result.Kind != StatementQuery {
+					t.Errorf("expected query result, got %s", result.Kind)
+				}
+			}
+			query, ok := capture.Last()
+			if !ok {
+				t.Fatal("no query captured")
+			}
+			if !strings.Contains(query.Query, tt.sql) {
+				t.Errorf("expected %q in query, got: %s", tt.sql, query.Query)
+			}
+		})
+	}
+
+	t.Run("unknown name lists known", func(t *testing.T) {
+		_, err := db.ExecNamed(ctx, "by-emial", nil)
+		if !errors.Is(err, ErrStatementNotFound) {
+			t.Fatalf("expected ErrStatementNotFound, got %v", err)
+		}
+		for _, name := range []string{"by-email", "count", "max-age", "query", "rename"} {
+			if !strings.Contains(err.Error(), name) {
+				t.Errorf("expected %q in error, got: %v", name, err)
+			}
+		}
+	})
+}
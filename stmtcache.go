@@ -0,0 +1,291 @@
+package grub
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultStatementCacheSize is the number of prepared statements
+// WithStatementCache keeps when size is not positive.
+const defaultStatementCacheSize = 256
+
+// StatementCacheStats reports prepared statement reuse under
+// WithStatementCache.
+type StatementCacheStats struct {
+	Hits      uint64 // statements run from the cache
+	Misses    uint64 // statements prepared because they were not cached
+	Evictions uint64 // statements closed to keep the cache within its size
+	Size      int    // statements currently cached
+}
+
+// StatementCacheStats reports prepared statement reuse across the primary
+// and any read replicas. It is zero unless WithStatementCache is set.
+func (d *Database[T]) StatementCacheStats() StatementCacheStats {
+	var total StatementCacheStats
+	add := func(c conn[T]) {
+		if c.stmts == nil {
+			return
+		}
+		s := c.stmts.stats()
+		total.Hits += s.Hits
+		total.Misses += s.Misses
+		total.Evictions += s.Evictions
+		total.Size += s.Size
+	}
+	add(d.primary())
+	if d.replicas != nil {
+		for _, c := range d.replicas.conns {
+			add(c)
+		}
+	}
+	return total
+}
+
+// preparedDB returns a *sqlx.DB that runs every statement outside a
+// transaction through a prepared statement held in cache, preparing it on
+// first use. database/sql prepares a statement lazily on each pooled
+// connection it runs on, so connections the pool recycles are re-prepared
+// transparently. Statements inside a transaction are prepared on it and
+// closed when it ends; they never enter cache.
+func preparedDB(db *sqlx.DB, size int) (*sqlx.DB, *stmtCache) {
+	cache := newStmtCache(db.DB, size)
+	wrapped := sqlx.NewDb(sql.OpenDB(&prepConnector{cache: cache}), db.DriverName())
+	wrapped.Mapper = db.Mapper
+	return wrapped, cache
+}
+
+// stmtCache is a bounded, least-recently-used set of statements prepared
+// on db, safe for concurrent use.
+type stmtCache struct {
+	db      *sql.DB
+	size    int
+	mu      sync.Mutex
+	entries map[string]*list.Element // values are *stmtEntry
+	order   *list.List               // most recently used first
+
+	hits, misses, evictions atomic.Uint64
+}
+
+// stmtEntry is a cached statement. An evicted entry is closed once the
+// last caller using it releases it.
+type stmtEntry struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+func newStmtCache(db *sql.DB, size int) *stmtCache {
+	if size <= 0 {
+		size = defaultStatementCacheSize
+	}
+	return &stmtCache{db: db, size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// acquire returns the cached statement for query, preparing it on a miss.
+// The caller must release it after running it.
+func (c *stmtCache) acquire(ctx context.Context, query string) (*stmtEntry, error) {
+	if e := c.lookup(query); e != nil {
+		c.hits.Add(1)
+		return e, nil
+	}
+	c.misses.Add(1)
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[query]; ok {
+		// Prepared concurrently by another caller; keep theirs.
+		_ = stmt.Close()
+		e := el.Value.(*stmtEntry)
+		e.refs++
+		c.order.MoveToFront(el)
+		return e, nil
+	}
+	e := &stmtEntry{query: query, stmt: stmt, refs: 1}
+	c.entries[query] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		evicted := oldest.Value.(*stmtEntry)
+		delete(c.entries, evicted.query)
+		evicted.evicted = true
+		if evicted.refs == 0 {
+			_ = evicted.stmt.Close()
+		}
+		c.evictions.Add(1)
+	}
+	return e, nil
+}
+
+// lookup returns the cached entry for query, acquired, or nil.
+func (c *stmtCache) lookup(query string) *stmtEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[query]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*stmtEntry)
+	e.refs++
+	c.order.MoveToFront(el)
+	return e
+}
+
+// release returns an entry obtained from acquire, closing it if it was
+// evicted meanwhile. Rows still open on the statement keep it usable until
+// they are closed.
+func (c *stmtCache) release(e *stmtEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.refs--
+	if e.evicted && e.refs == 0 {
+		_ = e.stmt.Close()
+	}
+}
+
+// stats reports the cache counters.
+func (c *stmtCache) stats() StatementCacheStats {
+	c.mu.Lock()
+	size := c.order.Len()
+	c.mu.Unlock()
+	return StatementCacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      size,
+	}
+}
+
+// prepConnector hands out prepConns sharing cache.
+type prepConnector struct {
+	cache *stmtCache
+}
+
+func (c *prepConnector) Connect(context.Context) (driver.Conn, error) {
+	return &prepConn{cache: c.cache}, nil
+}
+
+func (c *prepConnector) Driver() driver.Driver {
+	return c.cache.db.Driver()
+}
+
+// prepConn runs statements through the shared cache, or through statements
+// prepared on its open transaction. Outside a transaction it holds no
+// connection of its own.
+type prepConn struct {
+	cache   *stmtCache
+	tx      *sql.Tx
+	txStmts map[string]*sql.Stmt
+}
+
+// CheckNamedValue accepts every argument as is, leaving conversion to the
+// underlying driver.
+func (*prepConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+// run calls fn with the prepared statement for query.
+func (c *prepConn) run(ctx context.Context, query string, fn func(stmt *sql.Stmt) error) error {
+	if c.tx != nil {
+		stmt, ok := c.txStmts[query]
+		if !ok {
+			var err error
+			if stmt, err = c.tx.PrepareContext(ctx, query); err != nil {
+				return err
+			}
+			c.txStmts[query] = stmt
+		}
+		return fn(stmt)
+	}
+	e, err := c.cache.acquire(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer c.cache.release(e)
+	return fn(e.stmt)
+}
+
+func (c *prepConn) QueryContext(ctx context.Context, query string, named []driver.NamedValue) (driver.Rows, error) {
+	args, _ := bindArgs(named)
+	var rows *sql.Rows
+	err := c.run(ctx, query, func(stmt *sql.Stmt) (err error) {
+		rows, err = stmt.QueryContext(ctx, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	return &logRows{rows: rows, cols: cols}, nil
+}
+
+func (c *prepConn) ExecContext(ctx context.Context, query string, named []driver.NamedValue) (driver.Result, error) {
+	args, _ := bindArgs(named)
+	var res sql.Result
+	err := c.run(ctx, query, func(stmt *sql.Stmt) (err error) {
+		res, err = stmt.ExecContext(ctx, args...)
+		return err
+	})
+	return res, err
+}
+
+func (c *prepConn) Prepare(query string) (driver.Stmt, error) {
+	return &connStmt{conn: c, query: query}, nil
+}
+
+func (c *prepConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *prepConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.cache.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.IsolationLevel(opts.Isolation),
+		ReadOnly:  opts.ReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.tx = tx
+	c.txStmts = make(map[string]*sql.Stmt)
+	return &prepTx{conn: c}, nil
+}
+
+func (c *prepConn) Close() error {
+	if c.tx != nil {
+		_ = c.tx.Rollback()
+		c.tx, c.txStmts = nil, nil
+	}
+	return nil
+}
+
+// prepTx ends the transaction open on conn. database/sql closes the
+// statements prepared on it.
+type prepTx struct {
+	conn *prepConn
+}
+
+func (t *prepTx) Commit() error {
+	err := t.conn.tx.Commit()
+	t.conn.tx, t.conn.txStmts = nil, nil
+	return err
+}
+
+func (t *prepTx) Rollback() error {
+	err := t.conn.tx.Rollback()
+	t.conn.tx, t.conn.txStmts = nil, nil
+	return err
+}
@@ -0,0 +1,167 @@
+package grub
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func newStmtCacheDB(t *testing.T, opts ...Option) (*Database[TestDBUser], *mockdb.Capture) {
+	t.Helper()
+	db, capture, config := mockdb.NewIsolated()
+	config.SetRows([]string{"id", "email", "name", "age"},
+		[]driver.Value{int64(1), "a@example.com", "Alice", nil})
+	config.SetQueryRows("EXISTS", []string{"exists"}, []driver.Value{true})
+	d, err := NewDatabase[TestDBUser](db, "test_users", testDBRenderer, opts...)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	return d, capture
+}
+
+func TestDatabase_StatementCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reuses prepared statements", func(t *testing.T) {
+		d, capture := newStmtCacheDB(t, WithStatementCache(0))
+		for n := 0; n < 3; n++ {
+			if _, err := d.Get(ctx, "1"); err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+		}
+		if got := capture.Prepared(); len(got) != 1 {
+			t.Errorf("expected one prepare for repeated Gets, got %v", got)
+		}
+		if len(capture.Queries) != 3 {
+			t.Errorf("expected 3 executions, got %d", len(capture.Queries))
+		}
+		stats := d.StatementCacheStats()
+		if stats.Hits != 2 || stats.Misses != 1 || stats.Size != 1 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		d, capture := newStmtCacheDB(t, WithStatementCache(1))
+		if _, err := d.Get(ctx, "1"); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if _, err := d.Exists(ctx, "1"); err != nil {
+			t.Fatalf("Exists failed: %v", err)
+		}
+		if _, err := d.Get(ctx, "1"); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		stats := d.StatementCacheStats()
+		if stats.Size != 1 || stats.Evictions != 2 || stats.Misses != 3 {
+			t.Errorf("unexpected stats %+v", stats)
+		}
+		if got := capture.Prepared(); len(got) != 3 {
+			t.Errorf("expected the evicted Get to be prepared again, got %v", got)
+		}
+	})
+
+	t.Run("transactions stay out of the cache", func(t *testing.T) {
+		d, capture := newStmtCacheDB(t, WithStatementCache(0))
+		err := d.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			for n := 0; n < 2; n++ {
+				if _, err := d.ExecQueryTx(ctx, tx, QueryAll, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil)
+		if err != nil {
+			t.Fatalf("WithTx failed: %v", err)
+		}
+		if got := capture.Prepared(); len(got) != 1 {
+			t.Errorf("expected one prepare on the transaction, got %v", got)
+		}
+		if stats := d.StatementCacheStats(); stats != (StatementCacheStats{}) {
+			t.Errorf("expected an untouched cache, got %+v", stats)
+		}
+		if _, err := d.ExecQuery(ctx, QueryAll, nil); err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		if got := capture.Prepared(); len(got) != 2 {
+			t.Errorf("expected the pool to prepare its own statement, got %v", got)
+		}
+	})
+
+	t.Run("query logger", func(t *testing.T) {
+		var mu sync.Mutex
+		var logged []string
+		d, capture := newStmtCacheDB(t, WithStatementCache(0), WithQueryLogger(func(_ context.Context, info QueryInfo) {
+			mu.Lock()
+			logged = append(logged, info.SQL)
+			mu.Unlock()
+		}))
+		for n := 0; n < 2; n++ {
+			if _, err := d.Get(ctx, "1"); err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(logged) != 2 {
+			t.Errorf("expected both Gets logged, got %v", logged)
+		}
+		if got := capture.Prepared(); len(got) != 1 {
+			t.Errorf("expected one prepare, got %v", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		d, capture := newStmtCacheDB(t)
+		if _, err := d.Get(ctx, "1"); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if got := capture.Prepared(); len(got) != 0 {
+			t.Errorf("expected no prepares, got %v", got)
+		}
+		if stats := d.StatementCacheStats(); stats != (StatementCacheStats{}) {
+			t.Errorf("expected zero stats, got %+v", stats)
+		}
+	})
+}
+
+// TestDatabase_StatementCacheParallel runs mixed reads against a cache
+// small enough to evict constantly; run it with -race.
+func TestDatabase_StatementCacheParallel(t *testing.T) {
+	ctx := context.Background()
+	d, _ := newStmtCacheDB(t, WithStatementCache(1))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				var err error
+				if (g+n)%2 == 0 {
+					_, err = d.Get(ctx, "1")
+				} else {
+					_, err = d.Exists(ctx, "1")
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("parallel read failed: %v", err)
+	}
+	stats := d.StatementCacheStats()
+	if stats.Hits+stats.Misses != 16*50 || stats.Size != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
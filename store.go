@@ -2,49 +2,79 @@ package grub
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/zoobzio/atom"
 	atomic "github.com/zoobzio/grub/internal/atomic"
+	"github.com/zoobzio/grub/internal/shared"
 )
 
 // Store provides type-safe key-value storage operations for T.
 // Wraps a StoreProvider, handling serialization of T to/from bytes.
 type Store[T any] struct {
-	provider   StoreProvider
-	codec      Codec
-	atomic     *atomic.Store[T]
-	atomicOnce sync.Once
+	provider    StoreProvider
+	codec       Codec
+	timeout     time.Duration
+	onDecodeErr DecodeErrorHandler
+	stamps      *timestamps
+	redact      *redaction
+	keys        *KeyPolicy
+	retries     int
+	atomic      *atomic.Store[T]
+	atomicOnce  *sync.Once
+	flights     *storeFlights // nil unless WithSingleflight is set
+	readOnly    bool
 }
 
 // NewStore creates a Store for type T backed by the given provider.
 // Uses JSON codec by default.
-func NewStore[T any](provider StoreProvider) *Store[T] {
-	return &Store[T]{
-		provider: provider,
-		codec:    JSONCodec{},
-	}
+func NewStore[T any](provider StoreProvider, opts ...Option) *Store[T] {
+	return NewStoreWithCodec[T](provider, JSONCodec{}, opts...)
 }
 
 // NewStoreWithCodec creates a Store for type T with a custom codec.
-func NewStoreWithCodec[T any](provider StoreProvider, codec Codec) *Store[T] {
-	return &Store[T]{
-		provider: provider,
-		codec:    codec,
+func NewStoreWithCodec[T any](provider StoreProvider, codec Codec, opts ...Option) *Store[T] {
+	o := applyOptions(opts)
+	s := &Store[T]{
+		provider:    namespaceStore(provider, o.keyNamespace),
+		codec:       codec,
+		timeout:     o.timeout,
+		onDecodeErr: o.onDecodeErr,
+		stamps:      newTimestamps[T](o),
+		redact:      newRedaction[T](o),
+		keys:        o.keyPolicy,
+		retries:     o.updateRetries,
+		atomicOnce:  new(sync.Once),
+	}
+	if o.singleflight {
+		s.flights = &storeFlights{}
 	}
+	return s
 }
 
 // Get retrieves the value at key as T.
+// With WithSingleflight, concurrent Gets for key share one provider read
+// and each decode their own value.
 func (s *Store[T]) Get(ctx context.Context, key string) (*T, error) {
-	data, err := s.provider.Get(ctx, key)
+	key, err := s.keys.apply(key)
 	if err != nil {
-		return nil, err
+		return nil, shared.WrapError(KindStore, "get", "", key, err)
+	}
+	data, err := s.fetch(ctx, key)
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "get", "", key, err)
 	}
 	var value T
 	if err := s.codec.Decode(data, &value); err != nil {
 		return nil, err
 	}
+	if isRawLoad(ctx) {
+		return &value, nil
+	}
+	s.redact.apply(&value)
 	if err := callAfterLoad(ctx, &value); err != nil {
 		return nil, err
 	}
@@ -54,54 +84,336 @@ func (s *Store[T]) Get(ctx context.Context, key string) (*T, error) {
 // Set stores value at key with optional TTL.
 // TTL of 0 means no expiration.
 func (s *Store[T]) Set(ctx context.Context, key string, value *T, ttl time.Duration) error {
+	if s.readOnly {
+		return shared.WrapError(KindStore, "set", "", key, ErrReadOnly)
+	}
+	key, err := s.keys.apply(key)
+	if err != nil {
+		return shared.WrapError(KindStore, "set", "", key, err)
+	}
+	s.stamps.stamp(value)
 	if err := callBeforeSave(ctx, value); err != nil {
 		return err
 	}
+	if err := s.redact.check(value); err != nil {
+		return shared.WrapError(KindStore, "set", "", key, err)
+	}
 	data, err := s.codec.Encode(value)
 	if err != nil {
 		return err
 	}
-	if err := s.provider.Set(ctx, key, data, ttl); err != nil {
-		return err
+	callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := s.provider.Set(callCtx, key, data, ttl); err != nil {
+		return shared.WrapError(KindStore, "set", "", key, err)
 	}
 	return callAfterSave(ctx, value)
 }
 
 // Delete removes the value at key.
 func (s *Store[T]) Delete(ctx context.Context, key string) error {
+	if s.readOnly {
+		return shared.WrapError(KindStore, "delete", "", key, ErrReadOnly)
+	}
+	key, err := s.keys.apply(key)
+	if err != nil {
+		return shared.WrapError(KindStore, "delete", "", key, err)
+	}
 	if err := callBeforeDelete[T](ctx); err != nil {
 		return err
 	}
-	if err := s.provider.Delete(ctx, key); err != nil {
-		return err
+	callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := s.provider.Delete(callCtx, key); err != nil {
+		return shared.WrapError(KindStore, "delete", "", key, err)
 	}
 	return callAfterDelete[T](ctx)
 }
 
 // Exists checks whether a key exists.
 func (s *Store[T]) Exists(ctx context.Context, key string) (bool, error) {
-	return s.provider.Exists(ctx, key)
+	key, err := s.keys.apply(key)
+	if err != nil {
+		return false, shared.WrapError(KindStore, "exists", "", key, err)
+	}
+	read := func(ctx context.Context) (bool, error) {
+		callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+		defer cancel()
+		return s.provider.Exists(callCtx, key)
+	}
+	var exists bool
+	if s.flights != nil {
+		exists, err = s.flights.exists.do(ctx, key, read)
+	} else {
+		exists, err = read(ctx)
+	}
+	if err != nil {
+		return false, shared.WrapError(KindStore, "exists", "", key, err)
+	}
+	return exists, nil
+}
+
+// TTL returns how long key has left before it expires, or 0 if it does not
+// expire. Returns ErrNotFound if the key does not exist, and ErrUnsupported
+// if the provider does not implement StoreTTLReader.
+func (s *Store[T]) TTL(ctx context.Context, key string) (time.Duration, error) {
+	key, err := s.keys.apply(key)
+	if err != nil {
+		return 0, shared.WrapError(KindStore, "ttl", "", key, err)
+	}
+	reader, ok := storeTTLReader(s.provider)
+	if !ok {
+		return 0, shared.WrapError(KindStore, "ttl", "", key, ErrUnsupported)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+	defer cancel()
+	ttl, err := reader.TTL(callCtx, key)
+	if err != nil {
+		return 0, shared.WrapError(KindStore, "ttl", "", key, err)
+	}
+	return ttl, nil
+}
+
+// fetch reads the payload at key, sharing the read with concurrent callers
+// under WithSingleflight.
+func (s *Store[T]) fetch(ctx context.Context, key string) ([]byte, error) {
+	read := func(ctx context.Context) ([]byte, error) {
+		callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+		defer cancel()
+		return s.provider.Get(callCtx, key)
+	}
+	if s.flights == nil {
+		return read(ctx)
+	}
+	return s.flights.get.do(ctx, key, read)
+}
+
+// DeletePrefix removes every key under prefix and returns how many were
+// removed. A provider implementing StorePrefixDeleter deletes natively;
+// otherwise keys are listed and deleted a page at a time. BeforeDelete and
+// AfterDelete hooks do not run: they take no key, so there is nothing to
+// call them with per record. The empty prefix is rejected with
+// ErrInvalidKey rather than emptying the store.
+//
+// On error the count covers the keys removed so far; calling again resumes
+// with the keys that remain. The default timeout applies to each provider
+// call rather than the whole deletion.
+func (s *Store[T]) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	if s.readOnly {
+		return 0, shared.WrapError(KindStore, "delete_prefix", "", prefix, ErrReadOnly)
+	}
+	if err := checkDeletePrefix(prefix); err != nil {
+		return 0, shared.WrapError(KindStore, "delete_prefix", "", prefix, err)
+	}
+	prefix, err := s.keys.applyPrefix(prefix)
+	if err != nil {
+		return 0, shared.WrapError(KindStore, "delete_prefix", "", prefix, err)
+	}
+	if deleter, ok := storePrefixDeleter(s.provider); ok {
+		n, err := deleter.DeletePrefix(ctx, prefix)
+		if err != nil {
+			return n, shared.WrapError(KindStore, "delete_prefix", "", prefix, err)
+		}
+		return n, nil
+	}
+	return deletePaged(ctx, KindStore, prefix, s.timeout, s.provider.List, s.provider.Delete)
+}
+
+// ExistsBatch checks whether each key exists using a single provider GetBatch.
+// The result has an entry for every input key.
+func (s *Store[T]) ExistsBatch(ctx context.Context, keys []string) (map[string]bool, error) {
+	normalized, err := s.keys.applyAll(keys)
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "exists_batch", "", "", err)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+	defer cancel()
+	raw, err := s.provider.GetBatch(callCtx, normalized)
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "exists_batch", "", "", err)
+	}
+	result := make(map[string]bool, len(keys))
+	for n, k := range keys {
+		_, result[k] = raw[normalized[n]]
+	}
+	return result, nil
 }
 
 // List returns keys matching the given prefix.
 // Limit of 0 means no limit.
 func (s *Store[T]) List(ctx context.Context, prefix string, limit int) ([]string, error) {
-	return s.provider.List(ctx, prefix, limit)
+	prefix, err := s.keys.applyPrefix(prefix)
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "list", "", prefix, err)
+	}
+	callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+	defer cancel()
+	keys, err := s.provider.List(callCtx, prefix, limit)
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "list", "", prefix, err)
+	}
+	return keys, nil
+}
+
+// scanPageSize is the number of records a RecordIterator fetches per GetBatch.
+const scanPageSize = 100
+
+// Scan returns an iterator over every record in the store.
+// When the provider implements StoreMatcher (redis), keys are listed a page
+// at a time through its cursor as the iterator advances; as with SCAN, a key
+// may be yielded more than once if the keyspace changes mid-scan. Other
+// providers have no cursor, so their keys are listed once up front. Either
+// way records are fetched in pages via GetBatch and decoded as the iterator
+// advances. Keys removed between listing and fetching are skipped.
+// AfterLoad runs on each record before it is yielded.
+// The default timeout does not apply; bound a scan through ctx instead.
+func (s *Store[T]) Scan(ctx context.Context) (*RecordIterator[T], error) {
+	it := &RecordIterator[T]{ctx: ctx, store: s}
+	if m, ok := storeMatcher(s.provider); ok {
+		it.matcher = m
+		if err := it.list(); err != nil {
+			return nil, err
+		}
+		return it, nil
+	}
+	keys, err := s.provider.List(ctx, "", 0)
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "scan", "", "", err)
+	}
+	it.keys = keys
+	return it, nil
+}
+
+// RecordIterator streams decoded records from a Store.
+// Call Next to advance, Value and Key to read the current record, and Err
+// once Next returns false to distinguish exhaustion from failure.
+type RecordIterator[T any] struct {
+	ctx     context.Context
+	store   *Store[T]
+	matcher StoreMatcher // nil when keys were listed up front
+	cursor  string       // next ListMatch cursor; "" once the listing is done
+	keys    []string
+	pos     int
+	page    []string
+	batch   map[string][]byte
+	key     string
+	value   *T
+	err     error
+}
+
+// list replaces keys with the next page of keys from the matcher.
+func (it *RecordIterator[T]) list() error {
+	keys, next, err := it.matcher.ListMatch(it.ctx, "*", it.cursor, scanPageSize)
+	if err != nil {
+		return shared.WrapError(KindStore, "scan", "", "", err)
+	}
+	it.keys, it.pos, it.cursor = keys, 0, next
+	return nil
+}
+
+// Next advances to the next record, fetching a new page when the current
+// one is exhausted. Returns false when the store is exhausted or an error
+// occurred.
+func (it *RecordIterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for {
+		for len(it.page) > 0 {
+			key := it.page[0]
+			it.page = it.page[1:]
+			data, ok := it.batch[key]
+			if !ok {
+				continue
+			}
+			var value T
+			if err := it.store.codec.Decode(data, &value); err != nil {
+				if err := handleDecodeErr(it.store.onDecodeErr, key, data, err); err != nil {
+					it.err = err
+					return false
+				}
+				continue
+			}
+			it.store.redact.apply(&value)
+			if err := callAfterLoad(it.ctx, &value); err != nil {
+				it.err = err
+				return false
+			}
+			it.key, it.value = key, &value
+			return true
+		}
+		if it.pos >= len(it.keys) && it.cursor == "" {
+			it.key, it.value = "", nil
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		if it.pos >= len(it.keys) {
+			if it.err = it.list(); it.err != nil {
+				return false
+			}
+			continue
+		}
+		end := min(it.pos+scanPageSize, len(it.keys))
+		it.page = it.keys[it.pos:end]
+		it.pos = end
+		batch, err := it.store.provider.GetBatch(it.ctx, it.page)
+		if err != nil {
+			it.err = shared.WrapError(KindStore, "scan", "", "", err)
+			return false
+		}
+		it.batch = batch
+	}
+}
+
+// Key returns the key of the current record.
+func (it *RecordIterator[T]) Key() string {
+	return it.key
+}
+
+// Value returns the current record.
+func (it *RecordIterator[T]) Value() *T {
+	return it.value
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *RecordIterator[T]) Err() error {
+	return it.err
 }
 
 // GetBatch retrieves multiple values by key.
-// Missing keys are omitted from the result.
+// Missing keys are omitted from the result, as are undecodable values when a
+// DecodeErrorHandler elects to skip them. Keys beyond the provider's
+// BatchLimiter limit are fetched in sequential chunks.
 func (s *Store[T]) GetBatch(ctx context.Context, keys []string) (map[string]*T, error) {
-	raw, err := s.provider.GetBatch(ctx, keys)
+	normalized, err := s.keys.applyAll(keys)
 	if err != nil {
-		return nil, err
+		return nil, shared.WrapError(KindStore, "get_batch", "", "", err)
+	}
+	raw := make(map[string][]byte, len(normalized))
+	err = inChunks(len(normalized), batchLimit(s.provider), func(from, to int) error {
+		callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+		defer cancel()
+		chunk, err := s.provider.GetBatch(callCtx, normalized[from:to])
+		maps.Copy(raw, chunk)
+		return err
+	})
+	if err != nil {
+		return nil, shared.WrapError(KindStore, "get_batch", "", "", err)
 	}
+	raw = originalKeys(raw, keys, normalized)
 	result := make(map[string]*T, len(raw))
 	for k, data := range raw {
 		var value T
 		if err := s.codec.Decode(data, &value); err != nil {
-			return nil, err
+			if err := handleDecodeErr(s.onDecodeErr, k, data, err); err != nil {
+				return nil, err
+			}
+			continue
 		}
+		s.redact.apply(&value)
 		if err := callAfterLoad(ctx, &value); err != nil {
 			return nil, err
 		}
@@ -111,21 +423,35 @@ func (s *Store[T]) GetBatch(ctx context.Context, keys []string) (map[string]*T,
 }
 
 // SetBatch stores multiple key-value pairs with optional TTL.
-// TTL of 0 means no expiration.
+// TTL of 0 means no expiration. More items than the provider's BatchLimiter
+// limit are written in sequential chunks of keys in sorted order, each with
+// its own timeout; if one fails, the chunks before it stay written and the
+// error is a *BatchChunkError naming it.
 func (s *Store[T]) SetBatch(ctx context.Context, items map[string]*T, ttl time.Duration) error {
+	if s.readOnly {
+		return shared.WrapError(KindStore, "set_batch", "", "", ErrReadOnly)
+	}
+	items, err := applyMap(s.keys, items)
+	if err != nil {
+		return shared.WrapError(KindStore, "set_batch", "", "", err)
+	}
 	raw := make(map[string][]byte, len(items))
 	for k, v := range items {
+		s.stamps.stamp(v)
 		if err := callBeforeSave(ctx, v); err != nil {
 			return err
 		}
+		if err := s.redact.check(v); err != nil {
+			return shared.WrapError(KindStore, "set_batch", "", k, err)
+		}
 		data, err := s.codec.Encode(v)
 		if err != nil {
 			return err
 		}
 		raw[k] = data
 	}
-	if err := s.provider.SetBatch(ctx, raw, ttl); err != nil {
-		return err
+	if err := s.setBatch(ctx, raw, ttl); err != nil {
+		return shared.WrapError(KindStore, "set_batch", "", "", err)
 	}
 	for _, v := range items {
 		if err := callAfterSave(ctx, v); err != nil {
@@ -135,6 +461,50 @@ func (s *Store[T]) SetBatch(ctx context.Context, items map[string]*T, ttl time.D
 	return nil
 }
 
+// setBatch writes raw through the provider, split into sequential chunks of
+// keys in sorted order when the provider declares a BatchLimiter limit.
+func (s *Store[T]) setBatch(ctx context.Context, raw map[string][]byte, ttl time.Duration) error {
+	limit := batchLimit(s.provider)
+	if limit == 0 || len(raw) <= limit {
+		callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+		defer cancel()
+		return s.provider.SetBatch(callCtx, raw, ttl)
+	}
+	keys := slices.Sorted(maps.Keys(raw))
+	return inChunks(len(keys), limit, func(from, to int) error {
+		chunk := make(map[string][]byte, to-from)
+		for _, k := range keys[from:to] {
+			chunk[k] = raw[k]
+		}
+		callCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+		defer cancel()
+		return s.provider.SetBatch(callCtx, chunk, ttl)
+	})
+}
+
+// Provider returns the StoreProvider passed to NewStore, so callers can
+// type-assert to the concrete provider for features grub does not cover.
+// Keys used on it directly are not namespaced or normalized.
+func (s *Store[T]) Provider() StoreProvider {
+	if ns, ok := s.provider.(*namespacedStore); ok {
+		return ns.inner
+	}
+	return s.provider
+}
+
+// ReadOnly returns a view of this store whose writes (Set, SetBatch,
+// Update, Delete, DeletePrefix) fail with ErrReadOnly, naming the
+// operation, before hooks run or the provider is reached. Reads behave as
+// on s and share its provider, codec, and options. The view's Atomic
+// rejects writes the same way.
+func (s *Store[T]) ReadOnly() *Store[T] {
+	view := *s
+	view.readOnly = true
+	view.atomic = nil
+	view.atomicOnce = new(sync.Once)
+	return &view
+}
+
 // Atomic returns an atom-based view of this store.
 // The returned atomic.Store satisfies the AtomicStore interface.
 // The instance is created once and cached for subsequent calls.
@@ -145,7 +515,17 @@ func (s *Store[T]) Atomic() *atomic.Store[T] {
 		if err != nil {
 			panic("grub: invalid type for atomization: " + err.Error())
 		}
-		s.atomic = atomic.NewStore[T](s.provider, s.codec, atomizer.Spec())
+		s.atomic = atomic.NewStore[T](s.provider, s.codec, atomizer.Spec()).Redact(s.redact.fields()).Keys(s.keys.apply)
+		if s.readOnly {
+			s.atomic = s.atomic.ReadOnly()
+		}
 	})
 	return s.atomic
 }
+
+// Spec returns the atom spec of T: its type name and each field's name,
+// Go type, kind, and struct tags, as carried by Atomic's atoms. Panics if
+// T is not atomizable, like Atomic.
+func (s *Store[T]) Spec() atom.Spec {
+	return s.Atomic().Spec()
+}
@@ -3,18 +3,21 @@ package grub
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"testing"
 	"time"
 )
 
 // mockStoreProvider implements StoreProvider for testing.
 type mockStoreProvider struct {
-	data      map[string][]byte
-	getErr    error
-	setErr    error
-	deleteErr error
-	existsErr error
-	listErr   error
+	data        map[string][]byte
+	getErr      error
+	setErr      error
+	deleteErr   error
+	existsErr   error
+	listErr     error
+	getBatchErr error
 }
 
 func newMockStoreProvider() *mockStoreProvider {
@@ -78,6 +81,9 @@ func (m *mockStoreProvider) List(_ context.Context, prefix string, limit int) ([
 }
 
 func (m *mockStoreProvider) GetBatch(_ context.Context, keys []string) (map[string][]byte, error) {
+	if m.getBatchErr != nil {
+		return nil, m.getBatchErr
+	}
 	result := make(map[string][]byte)
 	for _, k := range keys {
 		if v, ok := m.data[k]; ok {
@@ -338,6 +344,314 @@ func TestStore_List(t *testing.T) {
 	})
 }
 
+// pagingStoreProvider serves ListMatch from the mock's sorted keys, using
+// the last key of each page as the cursor.
+type pagingStoreProvider struct {
+	*mockStoreProvider
+	calls int
+}
+
+func (p *pagingStoreProvider) ListMatch(_ context.Context, _, cursor string, limit int) ([]string, string, error) {
+	p.calls++
+	var all []string
+	for key := range p.data {
+		if key > cursor {
+			all = append(all, key)
+		}
+	}
+	sort.Strings(all)
+	if len(all) <= limit {
+		return all, "", nil
+	}
+	return all[:limit], all[limit-1], nil
+}
+
+func TestStore_Scan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("yields every record across pages", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		store := NewStore[testRecord](provider)
+		total := scanPageSize*2 + 5
+		for i := 0; i < total; i++ {
+			data, _ := JSONCodec{}.Encode(&testRecord{ID: i})
+			provider.data[fmt.Sprintf("rec/%03d", i)] = data
+		}
+
+		it, err := store.Scan(ctx)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		seen := make(map[int]bool)
+		for it.Next() {
+			want := fmt.Sprintf("rec/%03d", it.Value().ID)
+			if it.Key() != want {
+				t.Errorf("key %q does not match value %q", it.Key(), want)
+			}
+			seen[it.Value().ID] = true
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("unexpected iteration error: %v", err)
+		}
+		if len(seen) != total {
+			t.Errorf("expected %d records, got %d", total, len(seen))
+		}
+	})
+
+	t.Run("pages keys through the matcher cursor", func(t *testing.T) {
+		provider := &pagingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		total := scanPageSize*2 + 5
+		for i := 0; i < total; i++ {
+			data, _ := JSONCodec{}.Encode(&testRecord{ID: i})
+			provider.data[fmt.Sprintf("rec/%03d", i)] = data
+		}
+		provider.listErr = errors.New("scan must not list every key")
+
+		it, err := NewStore[testRecord](provider).Scan(ctx)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if provider.calls != 1 {
+			t.Errorf("expected one page listed before iterating, got %d", provider.calls)
+		}
+		var n int
+		for it.Next() {
+			n++
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("unexpected iteration error: %v", err)
+		}
+		if n != total {
+			t.Errorf("expected %d records, got %d", total, n)
+		}
+		if provider.calls != 3 {
+			t.Errorf("expected three pages, got %d", provider.calls)
+		}
+	})
+
+	t.Run("empty store", func(t *testing.T) {
+		it, err := NewStore[testRecord](newMockStoreProvider()).Scan(ctx)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if it.Next() {
+			t.Error("expected no records")
+		}
+		if it.Err() != nil {
+			t.Errorf("unexpected error: %v", it.Err())
+		}
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		provider.listErr = errors.New("list failed")
+		_, err := NewStore[testRecord](provider).Scan(ctx)
+		if !errors.Is(err, provider.listErr) {
+			t.Errorf("expected list error, got %v", err)
+		}
+	})
+
+	t.Run("fetch error stops iteration", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		provider.data["a"] = []byte(`{}`)
+		provider.getBatchErr = errors.New("batch failed")
+		it, err := NewStore[testRecord](provider).Scan(ctx)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if it.Next() {
+			t.Error("expected Next to return false")
+		}
+		if !errors.Is(it.Err(), provider.getBatchErr) {
+			t.Errorf("expected batch error, got %v", it.Err())
+		}
+	})
+
+	t.Run("decode error stops iteration", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		provider.data["a"] = []byte(`not json`)
+		it, err := NewStore[testRecord](provider).Scan(ctx)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if it.Next() {
+			t.Error("expected Next to return false")
+		}
+		if it.Err() == nil {
+			t.Error("expected decode error")
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		provider.data["a"] = []byte(`{}`)
+		cctx, cancel := context.WithCancel(ctx)
+		it, err := NewStore[testRecord](provider).Scan(cctx)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		cancel()
+		if it.Next() {
+			t.Error("expected Next to return false")
+		}
+		if !errors.Is(it.Err(), context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", it.Err())
+		}
+	})
+}
+
+func TestStore_DecodeErrorHandler(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockStoreProvider()
+	provider.data["good"] = []byte(`{"id":1,"name":"good"}`)
+	provider.data["bad"] = []byte(`not json`)
+
+	var skipped []string
+	skip := WithDecodeErrorHandler(func(key string, raw []byte, err error) error {
+		if err == nil || string(raw) != "not json" {
+			t.Errorf("unexpected handler call for %s: %q, %v", key, raw, err)
+		}
+		skipped = append(skipped, key)
+		return nil
+	})
+
+	t.Run("GetBatch skips", func(t *testing.T) {
+		skipped = nil
+		store := NewStore[testRecord](provider, skip)
+		result, err := store.GetBatch(ctx, []string{"good", "bad"})
+		if err != nil {
+			t.Fatalf("GetBatch failed: %v", err)
+		}
+		if len(result) != 1 || result["good"] == nil {
+			t.Errorf("expected only the good record, got %v", result)
+		}
+		if len(skipped) != 1 || skipped[0] != "bad" {
+			t.Errorf("expected bad key to be reported, got %v", skipped)
+		}
+	})
+
+	t.Run("Scan skips", func(t *testing.T) {
+		skipped = nil
+		it, err := NewStore[testRecord](provider, skip).Scan(ctx)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		var keys []string
+		for it.Next() {
+			keys = append(keys, it.Key())
+		}
+		if it.Err() != nil {
+			t.Fatalf("unexpected iteration error: %v", it.Err())
+		}
+		if len(keys) != 1 || keys[0] != "good" {
+			t.Errorf("expected only the good record, got %v", keys)
+		}
+		if len(skipped) != 1 {
+			t.Errorf("expected one skipped record, got %v", skipped)
+		}
+	})
+
+	t.Run("returned error aborts", func(t *testing.T) {
+		abort := errors.New("abort")
+		store := NewStore[testRecord](provider, WithDecodeErrorHandler(func(string, []byte, error) error {
+			return abort
+		}))
+		if _, err := store.GetBatch(ctx, []string{"good", "bad"}); !errors.Is(err, abort) {
+			t.Errorf("expected handler error, got %v", err)
+		}
+	})
+
+	t.Run("Get unaffected", func(t *testing.T) {
+		store := NewStore[testRecord](provider, skip)
+		if _, err := store.Get(ctx, "bad"); err == nil {
+			t.Error("expected Get to fail on undecodable value")
+		}
+	})
+}
+
+// blockingStoreProvider blocks Get until the context is done.
+type blockingStoreProvider struct {
+	*mockStoreProvider
+}
+
+func (b *blockingStoreProvider) Get(ctx context.Context, _ string) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestStore_DefaultTimeout(t *testing.T) {
+	provider := &blockingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+
+	t.Run("bounds call without deadline", func(t *testing.T) {
+		store := NewStore[testRecord](provider, WithDefaultTimeout(20*time.Millisecond))
+
+		start := time.Now()
+		_, err := store.Get(context.Background(), "slow")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected Get to return near the 20ms bound, took %v", elapsed)
+		}
+	})
+
+	t.Run("caller deadline wins", func(t *testing.T) {
+		store := NewStore[testRecord](provider, WithDefaultTimeout(time.Hour))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		if _, err := store.Get(ctx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
+
+func TestStore_ExistsBatch(t *testing.T) {
+	provider := newMockStoreProvider()
+	store := NewStore[testRecord](provider)
+	ctx := context.Background()
+
+	provider.data["a"] = []byte(`{"id":1}`)
+	provider.data["b"] = []byte(`{"id":2}`)
+
+	t.Run("entry for every key", func(t *testing.T) {
+		result, err := store.ExistsBatch(ctx, []string{"a", "b", "missing"})
+		if err != nil {
+			t.Fatalf("ExistsBatch failed: %v", err)
+		}
+		if len(result) != 3 {
+			t.Fatalf("expected 3 entries, got %d", len(result))
+		}
+		if !result["a"] || !result["b"] {
+			t.Error("expected existing keys to be true")
+		}
+		if v, ok := result["missing"]; !ok || v {
+			t.Error("expected missing key to be present and false")
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		result, err := store.ExistsBatch(ctx, nil)
+		if err != nil {
+			t.Fatalf("ExistsBatch failed: %v", err)
+		}
+		if len(result) != 0 {
+			t.Errorf("expected empty result, got %d", len(result))
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		provider.getBatchErr = errors.New("unavailable")
+		defer func() { provider.getBatchErr = nil }()
+
+		_, err := store.ExistsBatch(ctx, []string{"a"})
+		var gerr *Error
+		if !errors.As(err, &gerr) || gerr.Op != "exists_batch" {
+			t.Errorf("expected *Error with op 'exists_batch', got %v", err)
+		}
+	})
+}
+
 func TestStore_GetBatch(t *testing.T) {
 	provider := newMockStoreProvider()
 	store := NewStore[testRecord](provider)
@@ -461,6 +775,48 @@ func TestStore_RoundTrip(t *testing.T) {
 	}
 }
 
+// ttlStoreProvider adds TTLs to the mock, keeping the TTL of each Set.
+type ttlStoreProvider struct {
+	*mockStoreProvider
+	ttls map[string]time.Duration
+}
+
+func newTTLStoreProvider() *ttlStoreProvider {
+	return &ttlStoreProvider{mockStoreProvider: newMockStoreProvider(), ttls: make(map[string]time.Duration)}
+}
+
+func (p *ttlStoreProvider) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	p.ttls[key] = ttl
+	return p.mockStoreProvider.Set(ctx, key, value, ttl)
+}
+
+func (p *ttlStoreProvider) TTL(_ context.Context, key string) (time.Duration, error) {
+	if _, ok := p.data[key]; !ok {
+		return 0, ErrNotFound
+	}
+	return p.ttls[key], nil
+}
+
+func TestStore_TTL(t *testing.T) {
+	ctx := context.Background()
+	provider := newTTLStoreProvider()
+	store := NewStore[testRecord](provider, WithKeyNamespace("ns:"))
+	if err := store.Set(ctx, "k", &testRecord{ID: 1}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if ttl, err := store.TTL(ctx, "k"); err != nil || ttl != time.Minute {
+		t.Errorf("expected a minute, got %v, %v", ttl, err)
+	}
+	if _, err := store.TTL(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	plain := NewStore[testRecord](newMockStoreProvider(), WithKeyNamespace("ns:"))
+	if _, err := plain.TTL(ctx, "k"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
+
 func TestStore_WithGobCodec(t *testing.T) {
 	provider := newMockStoreProvider()
 	store := NewStoreWithCodec[testRecord](provider, GobCodec{})
@@ -482,6 +838,24 @@ func TestStore_WithGobCodec(t *testing.T) {
 	}
 }
 
+func TestStore_Provider(t *testing.T) {
+	provider := newMockStoreProvider()
+
+	for _, opts := range [][]Option{nil, {WithKeyNamespace("tenant:")}} {
+		store := NewStore[testRecord](provider, opts...)
+		if p, ok := store.Provider().(*mockStoreProvider); !ok || p != provider {
+			t.Errorf("expected the provider passed to NewStore, got %T", store.Provider())
+		}
+	}
+}
+
+func TestStore_Spec(t *testing.T) {
+	spec := NewStore[testRecord](newMockStoreProvider()).Spec()
+	if spec.TypeName != "testRecord" || len(spec.Fields) != 2 || spec.Fields[1].Tags["atom"] != "name" {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+}
+
 func TestStore_Atomic(t *testing.T) {
 	provider := newMockStoreProvider()
 	store := NewStore[testRecord](provider)
@@ -513,3 +887,22 @@ func TestStore_Atomic(t *testing.T) {
 		t.Errorf("unexpected Name: %q", a.Strings["Name"])
 	}
 }
+
+func TestStore_ErrorContext(t *testing.T) {
+	provider := newMockStoreProvider()
+	store := NewStore[testRecord](provider)
+	ctx := context.Background()
+
+	_, err := store.Get(ctx, "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	var gerr *Error
+	if !errors.As(err, &gerr) {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if gerr.Kind != KindStore || gerr.Op != "get" || gerr.Key != "missing" {
+		t.Errorf("unexpected context: kind=%q op=%q key=%q", gerr.Kind, gerr.Op, gerr.Key)
+	}
+}
@@ -0,0 +1,99 @@
+package benchmarks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	astqlsqlite "github.com/zoobzio/astql/sqlite"
+	"github.com/zoobzio/grub"
+	_ "modernc.org/sqlite"
+)
+
+// BenchUser is the model used for Database benchmarks.
+type BenchUser struct {
+	ID    int    `db:"id" constraints:"primarykey"`
+	Email string `db:"email" constraints:"notnull"`
+	Name  string `db:"name" constraints:"notnull"`
+}
+
+// newBenchDatabase opens an in-memory SQLite database holding one user.
+// SQLite parses every unprepared statement afresh, so it shows what
+// WithStatementCache saves without a network round-trip to hide it.
+func newBenchDatabase(b *testing.B, opts ...grub.Option) *grub.Database[BenchUser] {
+	b.Helper()
+	db, err := sqlx.Connect("sqlite", ":memory:")
+	if err != nil {
+		b.Fatalf("failed to open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1) // each connection is its own :memory: database
+	b.Cleanup(func() { _ = db.Close() })
+	if _, err := db.Exec(`CREATE TABLE bench_users (id INTEGER PRIMARY KEY, email TEXT NOT NULL, name TEXT NOT NULL)`); err != nil {
+		b.Fatalf("failed to create table: %v", err)
+	}
+	d, err := grub.NewDatabase[BenchUser](db, "bench_users", astqlsqlite.New(), opts...)
+	if err != nil {
+		b.Fatalf("NewDatabase failed: %v", err)
+	}
+	if err := d.Set(context.Background(), "1", &BenchUser{ID: 1, Email: "bench@example.com", Name: "Bench"}); err != nil {
+		b.Fatalf("Set failed: %v", err)
+	}
+	return d
+}
+
+// statementCacheModes runs a benchmark with and without WithStatementCache.
+func statementCacheModes(b *testing.B, fn func(b *testing.B, d *grub.Database[BenchUser])) {
+	b.Run("unprepared", func(b *testing.B) { fn(b, newBenchDatabase(b)) })
+	b.Run("prepared", func(b *testing.B) { fn(b, newBenchDatabase(b, grub.WithStatementCache(0))) })
+}
+
+func BenchmarkDatabase_Get(b *testing.B) {
+	ctx := context.Background()
+	statementCacheModes(b, func(b *testing.B, d *grub.Database[BenchUser]) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := d.Get(ctx, "1"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDatabase_Set(b *testing.B) {
+	ctx := context.Background()
+	user := &BenchUser{ID: 1, Email: "bench@example.com", Name: "Bench"}
+	statementCacheModes(b, func(b *testing.B, d *grub.Database[BenchUser]) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if err := d.Set(ctx, "1", user); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDatabase_Exists(b *testing.B) {
+	ctx := context.Background()
+	statementCacheModes(b, func(b *testing.B, d *grub.Database[BenchUser]) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := d.Exists(ctx, "1"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDatabase_GetParallel(b *testing.B) {
+	ctx := context.Background()
+	statementCacheModes(b, func(b *testing.B, d *grub.Database[BenchUser]) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := d.Get(ctx, "1"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
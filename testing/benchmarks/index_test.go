@@ -0,0 +1,133 @@
+package benchmarks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub"
+	"github.com/zoobzio/vecna"
+)
+
+// roundTrip is the simulated network latency of one provider call.
+const roundTrip = 200 * time.Microsecond
+
+// batchQueries is the number of query vectors per SearchBatch.
+const batchQueries = 32
+
+// BenchMetadata is the vector metadata used for benchmarks.
+type BenchMetadata struct {
+	Category string `json:"category"`
+}
+
+// latencyVectorProvider answers every search with a fixed result after
+// sleeping for one round-trip, isolating call count from search cost.
+type latencyVectorProvider struct {
+	result []grub.VectorResult
+}
+
+func newLatencyVectorProvider() *latencyVectorProvider {
+	return &latencyVectorProvider{result: []grub.VectorResult{
+		{ID: uuid.New(), Vector: []float32{1, 0, 0}, Metadata: []byte(`{"category":"bench"}`), Score: 1},
+	}}
+}
+
+func (m *latencyVectorProvider) Upsert(context.Context, uuid.UUID, []float32, []byte) error {
+	return nil
+}
+
+func (m *latencyVectorProvider) UpsertBatch(context.Context, []grub.VectorRecord) error {
+	return nil
+}
+
+func (m *latencyVectorProvider) Get(context.Context, uuid.UUID) ([]float32, *grub.VectorInfo, error) {
+	return nil, nil, grub.ErrNotFound
+}
+
+func (m *latencyVectorProvider) Delete(context.Context, uuid.UUID) error {
+	return grub.ErrNotFound
+}
+
+func (m *latencyVectorProvider) DeleteBatch(context.Context, []uuid.UUID) error {
+	return nil
+}
+
+func (m *latencyVectorProvider) Search(context.Context, []float32, int, map[string]any) ([]grub.VectorResult, error) {
+	time.Sleep(roundTrip)
+	return m.result, nil
+}
+
+func (m *latencyVectorProvider) Query(context.Context, []float32, int, *vecna.Filter) ([]grub.VectorResult, error) {
+	time.Sleep(roundTrip)
+	return m.result, nil
+}
+
+func (m *latencyVectorProvider) Filter(context.Context, *vecna.Filter, int) ([]grub.VectorResult, error) {
+	return nil, grub.ErrFilterNotSupported
+}
+
+func (m *latencyVectorProvider) List(context.Context, int) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (m *latencyVectorProvider) Exists(context.Context, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+// batchLatencyVectorProvider answers a whole SearchBatch in one round-trip.
+type batchLatencyVectorProvider struct {
+	*latencyVectorProvider
+}
+
+func (m *batchLatencyVectorProvider) SearchBatch(_ context.Context, queries [][]float32, _ int, _ *vecna.Filter) ([][]grub.VectorResult, error) {
+	time.Sleep(roundTrip)
+	batches := make([][]grub.VectorResult, len(queries))
+	for i := range batches {
+		batches[i] = m.result
+	}
+	return batches, nil
+}
+
+func benchQueries() [][]float32 {
+	queries := make([][]float32, batchQueries)
+	for i := range queries {
+		queries[i] = []float32{1, 0, 0}
+	}
+	return queries
+}
+
+func BenchmarkIndex_QuerySequential(b *testing.B) {
+	index := grub.NewIndex[BenchMetadata](newLatencyVectorProvider())
+	ctx := context.Background()
+	queries := benchQueries()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, q := range queries {
+			_, _ = index.Query(ctx, q, 10, nil)
+		}
+	}
+}
+
+func BenchmarkIndex_SearchBatchFallback(b *testing.B) {
+	index := grub.NewIndex[BenchMetadata](newLatencyVectorProvider())
+	ctx := context.Background()
+	queries := benchQueries()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = index.SearchBatch(ctx, queries, 10, nil)
+	}
+}
+
+func BenchmarkIndex_SearchBatchNative(b *testing.B) {
+	index := grub.NewIndex[BenchMetadata](&batchLatencyVectorProvider{newLatencyVectorProvider()})
+	ctx := context.Background()
+	queries := benchQueries()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = index.SearchBatch(ctx, queries, 10, nil)
+	}
+}
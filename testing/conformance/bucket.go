@@ -0,0 +1,51 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/zoobzio/grub"
+)
+
+// RunBucketProviderConformance checks provider against the BucketProvider
+// error contract.
+func RunBucketProviderConformance(t *testing.T, provider grub.BucketProvider) {
+	t.Helper()
+	prefix := runPrefix()
+
+	t.Run("GetMissing", func(t *testing.T) {
+		_, _, err := provider.Get(callCtx(t), prefix+"missing")
+		expectNotFound(t, "Get", err)
+	})
+
+	t.Run("DeleteMissing", func(t *testing.T) {
+		expectNotFound(t, "Delete", provider.Delete(callCtx(t), prefix+"missing"))
+	})
+
+	t.Run("ExistsMissing", func(t *testing.T) {
+		exists, err := provider.Exists(callCtx(t), prefix+"missing")
+		expectMissing(t, exists, err)
+	})
+
+	t.Run("DeleteTwice", func(t *testing.T) {
+		key := prefix + "deleted"
+		info := &grub.ObjectInfo{Key: key, ContentType: "application/json"}
+		if err := provider.Put(callCtx(t), key, []byte(`{}`), info); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if err := provider.Delete(callCtx(t), key); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		_, _, err := provider.Get(callCtx(t), key)
+		expectNotFound(t, "Get after Delete", err)
+		expectNotFound(t, "Delete after Delete", provider.Delete(callCtx(t), key))
+		exists, err := provider.Exists(callCtx(t), key)
+		expectMissing(t, exists, err)
+	})
+
+	t.Run("ListEmptyPrefix", func(t *testing.T) {
+		infos, err := provider.List(callCtx(t), prefix+"none/", 0)
+		if err != nil || len(infos) != 0 {
+			t.Errorf("List on an unused prefix: expected no objects, got %v, %v", infos, err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+// Package conformance provides test suites that check a provider against
+// the error contract of the grub provider interfaces.
+//
+// Every shipped provider runs these suites from its integration tests, and
+// third-party providers can run them the same way:
+//
+//	func TestMyProvider_Conformance(t *testing.T) {
+//	    conformance.RunStoreProviderConformance(t, myprovider.New(client))
+//	}
+//
+// The contract is the same for every storage kind: Get and Delete on a
+// missing key return an error satisfying errors.Is(err, grub.ErrNotFound),
+// Exists reports false without an error, batch deletes ignore missing keys,
+// and batch calls with no input are no-ops. The suites only touch keys
+// under a random per-run prefix and remove what they write.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub"
+)
+
+// callTimeout bounds each provider call made by the suites.
+const callTimeout = 30 * time.Second
+
+// runPrefix returns a key prefix unique to one suite run.
+func runPrefix() string {
+	return "conformance/" + uuid.NewString() + "/"
+}
+
+// callCtx returns a context for a single provider call.
+func callCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// expectNotFound fails the test unless err satisfies grub.ErrNotFound.
+func expectNotFound(t *testing.T, op string, err error) {
+	t.Helper()
+	if !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("%s on a missing key: expected an error matching grub.ErrNotFound, got %v", op, err)
+	}
+}
+
+// expectMissing fails the test unless exists is false with a nil error.
+func expectMissing(t *testing.T, exists bool, err error) {
+	t.Helper()
+	if err != nil {
+		t.Errorf("Exists on a missing key: expected no error, got %v", err)
+	}
+	if exists {
+		t.Error("Exists on a missing key: expected false, got true")
+	}
+}
@@ -0,0 +1,97 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zoobzio/grub"
+)
+
+// RunStoreProviderConformance checks provider against the StoreProvider
+// error contract, and against the StoreSwapper contract when provider
+// implements it.
+func RunStoreProviderConformance(t *testing.T, provider grub.StoreProvider) {
+	t.Helper()
+	prefix := runPrefix()
+
+	t.Run("GetMissing", func(t *testing.T) {
+		_, err := provider.Get(callCtx(t), prefix+"missing")
+		expectNotFound(t, "Get", err)
+	})
+
+	t.Run("DeleteMissing", func(t *testing.T) {
+		expectNotFound(t, "Delete", provider.Delete(callCtx(t), prefix+"missing"))
+	})
+
+	t.Run("ExistsMissing", func(t *testing.T) {
+		exists, err := provider.Exists(callCtx(t), prefix+"missing")
+		expectMissing(t, exists, err)
+	})
+
+	t.Run("DeleteTwice", func(t *testing.T) {
+		key := prefix + "deleted"
+		if err := provider.Set(callCtx(t), key, []byte(`{}`), 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if err := provider.Delete(callCtx(t), key); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		_, err := provider.Get(callCtx(t), key)
+		expectNotFound(t, "Get after Delete", err)
+		expectNotFound(t, "Delete after Delete", provider.Delete(callCtx(t), key))
+	})
+
+	t.Run("GetBatchMissing", func(t *testing.T) {
+		present := prefix + "present"
+		if err := provider.Set(callCtx(t), present, []byte(`{}`), 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		t.Cleanup(func() { _ = provider.Delete(context.Background(), present) })
+
+		got, err := provider.GetBatch(callCtx(t), []string{present, prefix + "missing"})
+		if err != nil {
+			t.Fatalf("GetBatch with a missing key: expected no error, got %v", err)
+		}
+		if len(got) != 1 || got[present] == nil {
+			t.Errorf("GetBatch: expected only the present key, got %d entries", len(got))
+		}
+	})
+
+	t.Run("EmptyBatches", func(t *testing.T) {
+		got, err := provider.GetBatch(callCtx(t), nil)
+		if err != nil || len(got) != 0 {
+			t.Errorf("GetBatch(nil): expected an empty result, got %v, %v", got, err)
+		}
+		if err := provider.SetBatch(callCtx(t), nil, 0); err != nil {
+			t.Errorf("SetBatch(nil): expected no error, got %v", err)
+		}
+	})
+
+	t.Run("ListEmptyPrefix", func(t *testing.T) {
+		keys, err := provider.List(callCtx(t), prefix+"none/", 0)
+		if err != nil || len(keys) != 0 {
+			t.Errorf("List on an unused prefix: expected no keys, got %v, %v", keys, err)
+		}
+	})
+	if swapper, ok := provider.(grub.StoreSwapper); ok {
+		t.Run("CompareAndSwap", func(t *testing.T) {
+			key := prefix + "cas"
+			if ok, err := swapper.CompareAndSwap(callCtx(t), key, nil, []byte(`1`), 0); err != nil || ok {
+				t.Errorf("CompareAndSwap on a missing key: expected no swap, got %v, %v", ok, err)
+			}
+			if err := provider.Set(callCtx(t), key, []byte(`1`), 0); err != nil {
+				t.Fatalf("Set failed: %v", err)
+			}
+			t.Cleanup(func() { _ = provider.Delete(context.Background(), key) })
+			if ok, err := swapper.CompareAndSwap(callCtx(t), key, []byte(`0`), []byte(`2`), 0); err != nil || ok {
+				t.Errorf("CompareAndSwap with a stale value: expected no swap, got %v, %v", ok, err)
+			}
+			if ok, err := swapper.CompareAndSwap(callCtx(t), key, []byte(`1`), []byte(`2`), 0); err != nil || !ok {
+				t.Errorf("CompareAndSwap with the current value: expected a swap, got %v, %v", ok, err)
+			}
+			if got, err := provider.Get(callCtx(t), key); err != nil || string(got) != `2` {
+				t.Errorf("Get after CompareAndSwap: expected 2, got %q, %v", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub"
+)
+
+// RunVectorProviderConformance checks provider against the VectorProvider
+// error contract. dimension is the vector size the provider's collection
+// accepts.
+func RunVectorProviderConformance(t *testing.T, provider grub.VectorProvider, dimension int) {
+	t.Helper()
+	vector := make([]float32, dimension)
+	vector[0] = 1
+
+	upsert := func(t *testing.T) uuid.UUID {
+		t.Helper()
+		id := uuid.New()
+		if err := provider.Upsert(callCtx(t), id, vector, []byte(`{"category":"conformance"}`)); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		return id
+	}
+
+	t.Run("GetMissing", func(t *testing.T) {
+		_, _, err := provider.Get(callCtx(t), uuid.New())
+		expectNotFound(t, "Get", err)
+	})
+
+	t.Run("DeleteMissing", func(t *testing.T) {
+		expectNotFound(t, "Delete", provider.Delete(callCtx(t), uuid.New()))
+	})
+
+	t.Run("ExistsMissing", func(t *testing.T) {
+		exists, err := provider.Exists(callCtx(t), uuid.New())
+		expectMissing(t, exists, err)
+	})
+
+	t.Run("DeleteTwice", func(t *testing.T) {
+		id := upsert(t)
+		if err := provider.Delete(callCtx(t), id); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		_, _, err := provider.Get(callCtx(t), id)
+		expectNotFound(t, "Get after Delete", err)
+		expectNotFound(t, "Delete after Delete", provider.Delete(callCtx(t), id))
+	})
+
+	t.Run("DeleteBatchMissing", func(t *testing.T) {
+		if err := provider.DeleteBatch(callCtx(t), []uuid.UUID{uuid.New(), uuid.New()}); err != nil {
+			t.Errorf("DeleteBatch of missing IDs: expected no error, got %v", err)
+		}
+	})
+
+	t.Run("DeleteBatchMixed", func(t *testing.T) {
+		id := upsert(t)
+		if err := provider.DeleteBatch(callCtx(t), []uuid.UUID{id, uuid.New()}); err != nil {
+			t.Fatalf("DeleteBatch with a missing ID: expected no error, got %v", err)
+		}
+		exists, err := provider.Exists(callCtx(t), id)
+		if err != nil || exists {
+			t.Errorf("DeleteBatch: expected the present ID deleted, got %v, %v", exists, err)
+		}
+	})
+
+	t.Run("EmptyBatches", func(t *testing.T) {
+		if err := provider.UpsertBatch(callCtx(t), nil); err != nil {
+			t.Errorf("UpsertBatch(nil): expected no error, got %v", err)
+		}
+		if err := provider.DeleteBatch(callCtx(t), nil); err != nil {
+			t.Errorf("DeleteBatch(nil): expected no error, got %v", err)
+		}
+	})
+}
@@ -33,7 +33,7 @@ require (
 	github.com/testcontainers/testcontainers-go/modules/weaviate v0.40.0
 	github.com/weaviate/weaviate v1.29.0
 	github.com/weaviate/weaviate-go-client/v5 v5.0.2
-	github.com/zoobzio/astql v1.0.5
+	github.com/zoobzio/astql v1.0.6
 	github.com/zoobzio/atom v1.0.0
 	github.com/zoobzio/edamame v1.0.1
 	github.com/zoobzio/grub v0.0.0
@@ -46,7 +46,9 @@ require (
 	github.com/zoobzio/grub/pinecone v0.0.0
 	github.com/zoobzio/grub/qdrant v0.0.0
 	github.com/zoobzio/grub/redis v0.0.0
+	github.com/zoobzio/grub/redisvector v0.0.0
 	github.com/zoobzio/grub/s3 v0.0.0
+	github.com/zoobzio/grub/sqlitevector v0.0.0
 	github.com/zoobzio/grub/weaviate v0.0.0
 	github.com/zoobzio/sentinel v1.0.2
 	github.com/zoobzio/vecna v0.0.2
@@ -188,7 +190,7 @@ require (
 	github.com/yusufpapurcu/wmi v1.2.4 // indirect
 	github.com/zoobzio/capitan v1.0.0 // indirect
 	github.com/zoobzio/dbml v1.0.0 // indirect
-	github.com/zoobzio/soy v1.0.3 // indirect
+	github.com/zoobzio/soy v1.0.5 // indirect
 	go.mongodb.org/mongo-driver v1.14.0 // indirect
 	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
 	go.opentelemetry.io/contrib/detectors/gcp v1.38.0 // indirect
@@ -233,7 +235,9 @@ replace (
 	github.com/zoobzio/grub/postgres v0.0.0 => ../postgres
 	github.com/zoobzio/grub/qdrant v0.0.0 => ../qdrant
 	github.com/zoobzio/grub/redis v0.0.0 => ../redis
+	github.com/zoobzio/grub/redisvector v0.0.0 => ../redisvector
 	github.com/zoobzio/grub/s3 v0.0.0 => ../s3
 	github.com/zoobzio/grub/sqlite v0.0.0 => ../sqlite
+	github.com/zoobzio/grub/sqlitevector v0.0.0 => ../sqlitevector
 	github.com/zoobzio/grub/weaviate v0.0.0 => ../weaviate
 )
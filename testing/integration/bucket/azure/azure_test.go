@@ -11,6 +11,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	grubazure "github.com/zoobzio/grub/azure"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/bucket"
 )
 
@@ -102,6 +103,10 @@ func TestAzure_CRUD(t *testing.T) {
 	bucket.RunCRUDTests(t, tc)
 }
 
+func TestAzure_Conformance(t *testing.T) {
+	conformance.RunBucketProviderConformance(t, tc.Provider)
+}
+
 func TestAzure_Metadata(t *testing.T) {
 	// ContentType works, but custom metadata has known issues with Azurite emulator
 	// See: https://github.com/Azure/Azurite/issues/591
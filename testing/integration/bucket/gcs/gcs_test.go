@@ -10,6 +10,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	grubgcs "github.com/zoobzio/grub/gcs"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/bucket"
 	"google.golang.org/api/option"
 )
@@ -89,6 +90,10 @@ func TestGCS_CRUD(t *testing.T) {
 	bucket.RunCRUDTests(t, tc)
 }
 
+func TestGCS_Conformance(t *testing.T) {
+	conformance.RunBucketProviderConformance(t, tc.Provider)
+}
+
 func TestGCS_Metadata(t *testing.T) {
 	bucket.RunMetadataTests(t, tc)
 }
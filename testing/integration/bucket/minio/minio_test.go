@@ -9,6 +9,7 @@ import (
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	minioc "github.com/testcontainers/testcontainers-go/modules/minio"
 	grubminio "github.com/zoobzio/grub/minio"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/bucket"
 )
 
@@ -66,6 +67,10 @@ func TestMinio_CRUD(t *testing.T) {
 	bucket.RunCRUDTests(t, tc)
 }
 
+func TestMinio_Conformance(t *testing.T) {
+	conformance.RunBucketProviderConformance(t, tc.Provider)
+}
+
 func TestMinio_Metadata(t *testing.T) {
 	bucket.RunMetadataTests(t, tc)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/localstack"
 	grubs3 "github.com/zoobzio/grub/s3"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/bucket"
 )
 
@@ -104,6 +105,10 @@ func TestS3_CRUD(t *testing.T) {
 	bucket.RunCRUDTests(t, tc)
 }
 
+func TestS3_Conformance(t *testing.T) {
+	conformance.RunBucketProviderConformance(t, tc.Provider)
+}
+
 func TestS3_Metadata(t *testing.T) {
 	bucket.RunMetadataTests(t, tc)
 }
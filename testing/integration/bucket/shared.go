@@ -58,6 +58,7 @@ func RunAtomicTests(t *testing.T, tc *TestContext) {
 func RunListTests(t *testing.T, tc *TestContext) {
 	t.Run("List", func(t *testing.T) { testList(t, tc) })
 	t.Run("ListWithLimit", func(t *testing.T) { testListWithLimit(t, tc) })
+	t.Run("ListPage", func(t *testing.T) { testListPage(t, tc) })
 }
 
 // HookedPayload is a model with lifecycle hooks for integration testing.
@@ -508,3 +509,63 @@ func testListWithLimit(t *testing.T, tc *TestContext) {
 		t.Errorf("expected 3 objects with limit, got %d", len(infos))
 	}
 }
+
+func testListPage(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	bucket := grub.NewBucket[TestPayload](tc.Provider)
+
+	// Seven objects in pages of three: 3 + 3 + 1.
+	want := make([]string, 7)
+	for i := range want {
+		key := "page-prefix-" + string(rune('a'+i))
+		want[i] = key
+		obj := &grub.Object[TestPayload]{
+			Key:         key,
+			ContentType: "application/json",
+			Data:        TestPayload{ID: key, Name: "Page Value", Count: i},
+		}
+		if err := bucket.Put(ctx, obj); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	var got []string
+	seen := make(map[string]bool)
+	cursor := ""
+	for page := 1; ; page++ {
+		if page > 3 {
+			t.Fatalf("expected the listing to end after 3 pages, got cursor %q", cursor)
+		}
+		infos, next, err := bucket.ListPage(ctx, "page-prefix-", 3, cursor)
+		if err != nil {
+			t.Fatalf("ListPage %d failed: %v", page, err)
+		}
+		if wantLen := min(3, len(want)-len(got)); len(infos) != wantLen {
+			t.Errorf("page %d: expected %d objects, got %d", page, wantLen, len(infos))
+		}
+		for _, info := range infos {
+			if seen[info.Key] {
+				t.Errorf("page %d: duplicate key %q", page, info.Key)
+			}
+			seen[info.Key] = true
+			got = append(got, info.Key)
+			if info.Size == 0 || info.LastModified.IsZero() {
+				t.Errorf("%s: expected Size and LastModified, got %+v", info.Key, info)
+			}
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d keys, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected keys in order %v, got %v", want, got)
+			break
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/mariadb"
 	"github.com/testcontainers/testcontainers-go/wait"
 	astqlmariadb "github.com/zoobzio/astql/mariadb"
+	"github.com/zoobzio/grub"
 	"github.com/zoobzio/grub/testing/integration/database"
 )
 
@@ -35,7 +36,7 @@ func TestMain(m *testing.M) {
 		panic("failed to start mariadb container: " + err.Error())
 	}
 
-	connStr, err := mariadbContainer.ConnectionString(ctx, "multiStatements=true")
+	connStr, err := mariadbContainer.ConnectionString(ctx, "multiStatements=true", "parseTime=true")
 	if err != nil {
 		panic("failed to get connection string: " + err.Error())
 	}
@@ -66,6 +67,38 @@ func TestMain(m *testing.M) {
 			)
 		`,
 		InsertUserSQL: `INSERT INTO test_users (id, email, name, age) VALUES (?, ?, ?, ?)`,
+		NullableSQL: `
+			DROP TABLE IF EXISTS test_nullables;
+			CREATE TABLE test_nullables (
+				id INT PRIMARY KEY,
+				label VARCHAR(255),
+				qty INT,
+				units BIGINT UNSIGNED,
+				ratio DOUBLE,
+				active BOOLEAN,
+				created_at DATETIME NOT NULL,
+				expires_at DATETIME
+			)
+		`,
+		HistorySQL: `
+			DROP TABLE IF EXISTS test_history;
+			CREATE TABLE test_history (
+				id INT PRIMARY KEY,
+				email VARCHAR(255) NOT NULL,
+				name VARCHAR(255) NOT NULL,
+				age INT
+			)
+		`,
+		History: grub.HistoryNative,
+		BatchSQL: `
+			DROP TABLE IF EXISTS test_batch;
+			CREATE TABLE test_batch (
+				id INT PRIMARY KEY,
+				email VARCHAR(255) NOT NULL UNIQUE,
+				name VARCHAR(255) NOT NULL,
+				age INT
+			)
+		`,
 	}
 
 	code := m.Run()
@@ -91,3 +124,19 @@ func TestMariaDB_Transaction(t *testing.T) {
 func TestMariaDB_Hooks(t *testing.T) {
 	database.RunHookTests(t, tc)
 }
+
+func TestMariaDB_Constraints(t *testing.T) {
+	database.RunConstraintTests(t, tc)
+}
+
+func TestMariaDB_Nullable(t *testing.T) {
+	database.RunNullableTests(t, tc)
+}
+
+func TestMariaDB_History(t *testing.T) {
+	database.RunHistoryTests(t, tc)
+}
+
+func TestMariaDB_Batch(t *testing.T) {
+	database.RunBatchTests(t, tc)
+}
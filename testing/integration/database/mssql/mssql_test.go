@@ -12,6 +12,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/mssql"
 	"github.com/testcontainers/testcontainers-go/wait"
 	astqlmssql "github.com/zoobzio/astql/mssql"
+	"github.com/zoobzio/grub"
 	"github.com/zoobzio/grub/testing/integration/database"
 )
 
@@ -68,6 +69,42 @@ func TestMain(m *testing.M) {
 			INSERT INTO test_users (id, email, name, age) VALUES (@p1, @p2, @p3, @p4);
 			SET IDENTITY_INSERT test_users OFF;
 		`,
+		NullableSQL: `
+			IF OBJECT_ID('test_nullables', 'U') IS NOT NULL DROP TABLE test_nullables;
+			CREATE TABLE test_nullables (
+				id INT PRIMARY KEY,
+				label NVARCHAR(255),
+				qty INT,
+				units BIGINT,
+				ratio FLOAT,
+				active BIT,
+				created_at DATETIME2 NOT NULL,
+				expires_at DATETIME2
+			)
+		`,
+		HistorySQL: `
+			IF OBJECT_ID('test_history', 'U') IS NOT NULL
+				AND OBJECTPROPERTY(OBJECT_ID('test_history'), 'TableTemporalType') = 2
+				ALTER TABLE test_history SET (SYSTEM_VERSIONING = OFF);
+			IF OBJECT_ID('test_history', 'U') IS NOT NULL DROP TABLE test_history;
+			IF OBJECT_ID('test_history_history', 'U') IS NOT NULL DROP TABLE test_history_history;
+			CREATE TABLE test_history (
+				id INT PRIMARY KEY,
+				email NVARCHAR(255) NOT NULL,
+				name NVARCHAR(255) NOT NULL,
+				age INT
+			)
+		`,
+		History: grub.HistoryNative,
+		BatchSQL: `
+			IF OBJECT_ID('test_batch', 'U') IS NOT NULL DROP TABLE test_batch;
+			CREATE TABLE test_batch (
+				id INT PRIMARY KEY,
+				email NVARCHAR(255) NOT NULL UNIQUE,
+				name NVARCHAR(255) NOT NULL,
+				age INT
+			)
+		`,
 	}
 
 	code := m.Run()
@@ -93,3 +130,19 @@ func TestMSSQL_Transaction(t *testing.T) {
 func TestMSSQL_Hooks(t *testing.T) {
 	database.RunHookTests(t, tc)
 }
+
+func TestMSSQL_Constraints(t *testing.T) {
+	database.RunConstraintTests(t, tc)
+}
+
+func TestMSSQL_Nullable(t *testing.T) {
+	database.RunNullableTests(t, tc)
+}
+
+func TestMSSQL_History(t *testing.T) {
+	database.RunHistoryTests(t, tc)
+}
+
+func TestMSSQL_Batch(t *testing.T) {
+	database.RunBatchTests(t, tc)
+}
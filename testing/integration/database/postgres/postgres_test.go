@@ -12,6 +12,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
 	astqlpg "github.com/zoobzio/astql/postgres"
+	"github.com/zoobzio/grub"
 	"github.com/zoobzio/grub/testing/integration/database"
 )
 
@@ -58,6 +59,45 @@ func TestMain(m *testing.M) {
 			)
 		`,
 		InsertUserSQL: `INSERT INTO test_users (id, email, name, age) VALUES ($1, $2, $3, $4)`,
+		NullableSQL: `
+			DROP TABLE IF EXISTS test_nullables;
+			CREATE TABLE test_nullables (
+				id INTEGER PRIMARY KEY,
+				label TEXT,
+				qty INTEGER,
+				units BIGINT,
+				ratio DOUBLE PRECISION,
+				active BOOLEAN,
+				created_at TIMESTAMPTZ NOT NULL,
+				expires_at TIMESTAMPTZ
+			)
+		`,
+		Outbox:      true,
+		ArrayParams: true,
+		HistorySQL: `
+			DROP TABLE IF EXISTS test_history_history;
+			DROP TABLE IF EXISTS test_history;
+			CREATE TABLE test_history (
+				id INTEGER PRIMARY KEY,
+				email TEXT NOT NULL,
+				name TEXT NOT NULL,
+				age INTEGER
+			)
+		`,
+		History: grub.HistoryShadow,
+		BatchSQL: `
+			DROP TABLE IF EXISTS test_batch;
+			CREATE TABLE test_batch (
+				id INTEGER PRIMARY KEY,
+				email TEXT NOT NULL UNIQUE,
+				name TEXT NOT NULL,
+				age INTEGER
+			)
+		`,
+		JSONSQL: `
+			DROP TABLE IF EXISTS test_settings;
+			CREATE TABLE test_settings (id INTEGER PRIMARY KEY, settings JSONB)
+		`,
 	}
 
 	code := m.Run()
@@ -83,3 +123,27 @@ func TestPostgres_Transaction(t *testing.T) {
 func TestPostgres_Hooks(t *testing.T) {
 	database.RunHookTests(t, tc)
 }
+
+func TestPostgres_Constraints(t *testing.T) {
+	database.RunConstraintTests(t, tc)
+}
+
+func TestPostgres_Nullable(t *testing.T) {
+	database.RunNullableTests(t, tc)
+}
+
+func TestPostgres_History(t *testing.T) {
+	database.RunHistoryTests(t, tc)
+}
+
+func TestPostgres_Outbox(t *testing.T) {
+	database.RunOutboxTests(t, tc)
+}
+
+func TestPostgres_Batch(t *testing.T) {
+	database.RunBatchTests(t, tc)
+}
+
+func TestPostgres_JSON(t *testing.T) {
+	database.RunJSONTests(t, tc)
+}
@@ -4,9 +4,15 @@ package database
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/zoobzio/astql"
 	"github.com/zoobzio/atom"
 	"github.com/zoobzio/edamame"
@@ -33,12 +39,37 @@ type TestUser struct {
 	Age   *int   `db:"age"`
 }
 
+// NullableRecord covers every nullable kind the atomic view maps.
+type NullableRecord struct {
+	ID        int        `db:"id" constraints:"primarykey"`
+	Label     *string    `db:"label"`
+	Qty       *int       `db:"qty"`
+	Units     *uint      `db:"units"`
+	Ratio     *float64   `db:"ratio"`
+	Active    *bool      `db:"active"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt *time.Time `db:"expires_at"`
+}
+
+// SettingsRecord keeps free-form settings in a JSON column.
+type SettingsRecord struct {
+	ID       int                       `db:"id" constraints:"primarykey"`
+	Settings grub.JSON[map[string]any] `db:"settings"`
+}
+
 // TestContext holds shared test resources for a dialect.
 type TestContext struct {
 	DB            *sqlx.DB
 	Renderer      astql.Renderer
 	ResetSQL      string // SQL to drop/recreate test_users table
 	InsertUserSQL string // SQL to insert a user with explicit ID (for MSSQL IDENTITY_INSERT)
+	NullableSQL   string // SQL to drop/recreate test_nullables for NullableRecord
+	HistorySQL    string // SQL to drop/recreate test_history and its history table
+	History       grub.History
+	Outbox        bool   // run the outbox suite against test_outbox
+	BatchSQL      string // SQL to drop/recreate test_batch, a test_users without generated keys
+	JSONSQL       string // SQL to drop/recreate test_settings for SettingsRecord
+	ArrayParams   bool   // IN binds pq.Array params as one array (PostgreSQL)
 }
 
 // Reset drops and recreates the test_users table.
@@ -67,12 +98,17 @@ func (tc *TestContext) InsertUser(t *testing.T, id int, email, name string, age
 // RunCRUDTests runs the core CRUD test suite against the given context.
 func RunCRUDTests(t *testing.T, tc *TestContext) {
 	t.Run("Get", func(t *testing.T) { testGet(t, tc) })
+	t.Run("GetByKey", func(t *testing.T) { testGetByKey(t, tc) })
 	t.Run("GetAtom", func(t *testing.T) { testGetAtom(t, tc) })
 	t.Run("Set", func(t *testing.T) { testSet(t, tc) })
 	t.Run("SetUpdate", func(t *testing.T) { testSetUpdate(t, tc) })
 	t.Run("SetAtom", func(t *testing.T) { testSetAtom(t, tc) })
+	t.Run("SetIfChanged", func(t *testing.T) { testSetIfChanged(t, tc) })
+	t.Run("InsertReturning", func(t *testing.T) { testInsertReturning(t, tc) })
+	t.Run("Patch", func(t *testing.T) { testPatch(t, tc) })
 	t.Run("Delete", func(t *testing.T) { testDelete(t, tc) })
 	t.Run("DeleteNotFound", func(t *testing.T) { testDeleteNotFound(t, tc) })
+	t.Run("Exists", func(t *testing.T) { testExists(t, tc) })
 }
 
 // RunQueryTests runs the query engine test suite.
@@ -85,7 +121,206 @@ func RunQueryTests(t *testing.T, tc *TestContext) {
 	t.Run("Update", func(t *testing.T) { testUpdate(t, tc) })
 	t.Run("Aggregate", func(t *testing.T) { testAggregate(t, tc) })
 	t.Run("AggregateSum", func(t *testing.T) { testAggregateSum(t, tc) })
+	t.Run("AggregateMinMaxAvg", func(t *testing.T) { testAggregateMinMaxAvg(t, tc) })
+	t.Run("MultiAggregate", func(t *testing.T) { testMultiAggregate(t, tc) })
+	t.Run("AggregateGroup", func(t *testing.T) { testAggregateGroup(t, tc) })
+	t.Run("WhereIn", func(t *testing.T) { testWhereIn(t, tc) })
+	t.Run("WhereNull", func(t *testing.T) { testWhereNull(t, tc) })
 	t.Run("QueryPagination", func(t *testing.T) { testQueryPagination(t, tc) })
+	t.Run("QueryAfter", func(t *testing.T) { testQueryAfter(t, tc) })
+	t.Run("QueryLogger", func(t *testing.T) { testQueryLogger(t, tc) })
+	t.Run("StatementCache", func(t *testing.T) { testStatementCache(t, tc) })
+}
+
+// RunNullableTests runs the nullable round trip suite between the typed and
+// atomic APIs. Skipped when the context has no NullableSQL.
+func RunNullableTests(t *testing.T, tc *TestContext) {
+	if tc.NullableSQL == "" {
+		t.Skip("no test_nullables table for this dialect")
+	}
+	t.Run("TypedToAtomic", func(t *testing.T) { testNullableTypedToAtomic(t, tc) })
+	t.Run("AtomicToTyped", func(t *testing.T) { testNullableAtomicToTyped(t, tc) })
+}
+
+// RunHistoryTests runs the time-travel read suite against test_history.
+// Skipped when the context has no HistorySQL.
+func RunHistoryTests(t *testing.T, tc *TestContext) {
+	if tc.HistorySQL == "" {
+		t.Skip("no time-travel reads for this dialect")
+	}
+	t.Run("AsOf", func(t *testing.T) { testHistoryAsOf(t, tc) })
+}
+
+// RunOutboxTests runs the transactional outbox suite. Skipped unless the
+// context sets Outbox.
+func RunOutboxTests(t *testing.T, tc *TestContext) {
+	if !tc.Outbox {
+		t.Skip("outbox suite not enabled for this dialect")
+	}
+	t.Run("CommitsWithData", func(t *testing.T) { testOutboxCommit(t, tc) })
+	t.Run("RollsBackWithData", func(t *testing.T) { testOutboxRollback(t, tc) })
+	t.Run("Poller", func(t *testing.T) { testOutboxPoller(t, tc) })
+}
+
+// RunBatchTests runs the bulk upsert suite against test_batch. Skipped when
+// the context has no BatchSQL.
+func RunBatchTests(t *testing.T, tc *TestContext) {
+	if tc.BatchSQL == "" {
+		t.Skip("no test_batch table for this dialect")
+	}
+	t.Run("SetBatch", func(t *testing.T) { testSetBatch(t, tc) })
+	t.Run("SetBatchPartial", func(t *testing.T) { testSetBatchPartial(t, tc) })
+}
+
+// RunJSONTests runs the JSON column test suite against test_settings.
+func RunJSONTests(t *testing.T, tc *TestContext) {
+	if tc.JSONSQL == "" {
+		t.Skip("no test_settings table for this dialect")
+	}
+	t.Run("RoundTrip", func(t *testing.T) { testJSONRoundTrip(t, tc) })
+	t.Run("WhereJSON", func(t *testing.T) { testWhereJSON(t, tc) })
+}
+
+// RunConstraintTests runs the constraint violation classification suite.
+func RunConstraintTests(t *testing.T, tc *TestContext) {
+	t.Run("UniqueViolation", func(t *testing.T) { testUniqueViolation(t, tc) })
+}
+
+// nullableTime is second-precision so every dialect stores it exactly.
+var nullableTime = time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+func populatedNullable(id int) *NullableRecord {
+	label, qty, units, ratio, active := "x", 3, uint(4), 1.5, true
+	expires := nullableTime.Add(time.Hour)
+	return &NullableRecord{
+		ID: id, Label: &label, Qty: &qty, Units: &units, Ratio: &ratio,
+		Active: &active, CreatedAt: nullableTime, ExpiresAt: &expires,
+	}
+}
+
+func newNullableDatabase(t *testing.T, tc *TestContext) *grub.Database[NullableRecord] {
+	t.Helper()
+	if _, err := tc.DB.Exec(tc.NullableSQL); err != nil {
+		t.Fatalf("failed to reset test_nullables: %v", err)
+	}
+	db, err := grub.NewDatabase[NullableRecord](tc.DB, "test_nullables", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	return db
+}
+
+func testNullableTypedToAtomic(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	db := newNullableDatabase(t, tc)
+
+	if err := db.Set(ctx, "1", populatedNullable(1)); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := db.Set(ctx, "2", &NullableRecord{ID: 2, CreatedAt: nullableTime}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	a, err := db.Atomic().Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("atomic Get failed: %v", err)
+	}
+	if p := a.StringPtrs["Label"]; p == nil || *p != "x" {
+		t.Errorf("Label: got %v", p)
+	}
+	if p := a.IntPtrs["Qty"]; p == nil || *p != 3 {
+		t.Errorf("Qty: got %v", p)
+	}
+	if p := a.UintPtrs["Units"]; p == nil || *p != 4 {
+		t.Errorf("Units: got %v", p)
+	}
+	if p := a.FloatPtrs["Ratio"]; p == nil || *p != 1.5 {
+		t.Errorf("Ratio: got %v", p)
+	}
+	if p := a.BoolPtrs["Active"]; p == nil || !*p {
+		t.Errorf("Active: got %v", p)
+	}
+	if !a.Times["CreatedAt"].Equal(nullableTime) {
+		t.Errorf("CreatedAt: got %v", a.Times["CreatedAt"])
+	}
+	if p := a.TimePtrs["ExpiresAt"]; p == nil || !p.Equal(nullableTime.Add(time.Hour)) {
+		t.Errorf("ExpiresAt: got %v", p)
+	}
+
+	a, err = db.Atomic().Get(ctx, "2")
+	if err != nil {
+		t.Fatalf("atomic Get failed: %v", err)
+	}
+	if a.StringPtrs["Label"] != nil || a.IntPtrs["Qty"] != nil || a.UintPtrs["Units"] != nil ||
+		a.FloatPtrs["Ratio"] != nil || a.BoolPtrs["Active"] != nil || a.TimePtrs["ExpiresAt"] != nil {
+		t.Errorf("expected NULL columns to read as nil pointers, got %+v", a)
+	}
+}
+
+func testNullableAtomicToTyped(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	db := newNullableDatabase(t, tc)
+	atomizer, err := atom.Use[NullableRecord]()
+	if err != nil {
+		t.Fatalf("atom.Use failed: %v", err)
+	}
+
+	if err := db.Atomic().Set(ctx, "1", atomizer.Atomize(populatedNullable(1))); err != nil {
+		t.Fatalf("atomic Set failed: %v", err)
+	}
+	if err := db.Atomic().Set(ctx, "2", atomizer.Atomize(&NullableRecord{ID: 2, CreatedAt: nullableTime})); err != nil {
+		t.Fatalf("atomic Set failed: %v", err)
+	}
+
+	got, err := db.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	want := populatedNullable(1)
+	if got.Label == nil || *got.Label != *want.Label || got.Qty == nil || *got.Qty != *want.Qty ||
+		got.Units == nil || *got.Units != *want.Units || got.Ratio == nil || *got.Ratio != *want.Ratio ||
+		got.Active == nil || *got.Active != *want.Active {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ExpiresAt == nil || !got.ExpiresAt.Equal(*want.ExpiresAt) {
+		t.Errorf("times: got %v, %v", got.CreatedAt, got.ExpiresAt)
+	}
+
+	got, err = db.Get(ctx, "2")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Label != nil || got.Qty != nil || got.Units != nil || got.Ratio != nil || got.Active != nil || got.ExpiresAt != nil {
+		t.Errorf("expected nil pointers written as NULL, got %+v", got)
+	}
+}
+
+func testUniqueViolation(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	tc.InsertUser(t, 1, "dup@example.com", "First", 30)
+
+	err = db.Set(ctx, "2", &TestUser{ID: 2, Email: "dup@example.com", Name: "Second"})
+	if !errors.Is(err, grub.ErrUniqueViolation) {
+		t.Fatalf("expected ErrUniqueViolation, got %v", err)
+	}
+	if !errors.Is(err, grub.ErrConstraint) {
+		t.Errorf("expected ErrConstraint to match, got %v", err)
+	}
+
+	var ce *grub.ConstraintError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected *grub.ConstraintError, got %T", err)
+	}
+	if ce.Err == nil {
+		t.Error("expected original driver error to be preserved")
+	}
 }
 
 // HookedUser is a model with lifecycle hooks for integration testing.
@@ -126,6 +361,169 @@ func RunHookTests(t *testing.T, tc *TestContext) {
 	t.Run("AfterLoadOnExecSelect", func(t *testing.T) { testHookAfterLoadExecSelect(t, tc) })
 	t.Run("BeforeSaveOnSet", func(t *testing.T) { testHookBeforeSaveSet(t, tc) })
 	t.Run("BeforeSaveErrorAborts", func(t *testing.T) { testHookBeforeSaveError(t, tc) })
+	t.Run("SetIfChangedSkipsHooks", func(t *testing.T) { testHookSetIfChangedUnchanged(t, tc) })
+	t.Run("RedactionOnReads", func(t *testing.T) { testRedactionReads(t, tc) })
+	t.Run("RedactionGuardsWrites", func(t *testing.T) { testRedactionWrites(t, tc) })
+}
+
+// RedactedUser maps test_users with Email and Age marked for redaction.
+type RedactedUser struct {
+	ID    int    `db:"id" constraints:"primarykey"`
+	Email string `db:"email" constraints:"notnull,unique" grub:"redact"`
+	Name  string `db:"name" constraints:"notnull"`
+	Age   *int   `db:"age" grub:"redact"`
+
+	emailAtLoad string
+}
+
+func (r *RedactedUser) AfterLoad(_ context.Context) error {
+	r.emailAtLoad = r.Email
+	return nil
+}
+
+func testRedactionReads(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	tc.InsertUser(t, 1, "a@example.com", "A", 20)
+	tc.InsertUser(t, 2, "b@example.com", "B", 30)
+
+	redacted, err := grub.NewDatabase[RedactedUser](tc.DB, "test_users", tc.Renderer, grub.WithRedaction())
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	plain, err := grub.NewDatabase[RedactedUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	check := func(path string, users ...*RedactedUser) {
+		t.Helper()
+		if len(users) == 0 {
+			t.Errorf("%s: no records", path)
+		}
+		for _, u := range users {
+			if u.Email != "" || u.emailAtLoad != "" {
+				t.Errorf("%s: email not redacted before AfterLoad: %q / %q", path, u.Email, u.emailAtLoad)
+			}
+			if u.Age != nil {
+				t.Errorf("%s: age not redacted: %d", path, *u.Age)
+			}
+			if u.Name == "" {
+				t.Errorf("%s: unredacted field lost", path)
+			}
+		}
+	}
+
+	user, err := redacted.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	check("Get", user)
+
+	batch, err := redacted.GetBatch(ctx, []string{"1", "2"})
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	check("GetBatch", batch["1"], batch["2"])
+
+	all, err := redacted.ExecQuery(ctx, grub.QueryAll, nil)
+	if err != nil {
+		t.Fatalf("ExecQuery failed: %v", err)
+	}
+	check("ExecQuery", all...)
+
+	one, err := redacted.ExecSelect(ctx, edamame.NewSelectStatement("by_email", "Select by email", edamame.SelectSpec{
+		Where: []edamame.ConditionSpec{
+			{Field: "email", Operator: "=", Param: "email"},
+		},
+	}), map[string]any{"email": "b@example.com"})
+	if err != nil {
+		t.Fatalf("ExecSelect failed: %v", err)
+	}
+	check("ExecSelect", one)
+
+	built, err := redacted.Query().Exec(ctx, nil)
+	if err != nil {
+		t.Fatalf("Query builder failed: %v", err)
+	}
+	check("Query builder", built...)
+
+	a, err := redacted.Atomic().Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Atomic Get failed: %v", err)
+	}
+	if a.Strings["Email"] != "" || a.Strings["Name"] != "A" {
+		t.Errorf("atomic view: got Email %q Name %q", a.Strings["Email"], a.Strings["Name"])
+	}
+
+	unredacted, err := plain.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if unredacted.Email != "a@example.com" {
+		t.Errorf("plain view: expected email, got %q", unredacted.Email)
+	}
+}
+
+func testRedactionWrites(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	tc.InsertUser(t, 1, "a@example.com", "A", 20)
+
+	redacted, err := grub.NewDatabase[RedactedUser](tc.DB, "test_users", tc.Renderer, grub.WithRedaction())
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	user, err := redacted.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	user.Name = "Renamed"
+	if err := redacted.Set(ctx, "1", user); !errors.Is(err, grub.ErrRedacted) {
+		t.Fatalf("expected ErrRedacted writing back a redacted read, got %v", err)
+	}
+	if err := redacted.Atomic().Set(ctx, "1", mustAtomize(t, user)); !errors.Is(err, grub.ErrRedacted) {
+		t.Fatalf("expected ErrRedacted from atomic Set, got %v", err)
+	}
+
+	plain, err := grub.NewDatabase[RedactedUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	stored, err := plain.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if stored.Email != "a@example.com" || stored.Name != "A" {
+		t.Errorf("stored row changed: %+v", stored)
+	}
+
+	age := 21
+	user.Email = "new@example.com"
+	user.Age = &age
+	if err := redacted.Set(ctx, "1", user); err != nil {
+		t.Fatalf("Set with the redacted fields filled failed: %v", err)
+	}
+
+	// An empty string would overwrite the stored email just the same.
+	user.Email = ""
+	if err := redacted.Set(ctx, "1", user); !errors.Is(err, grub.ErrRedacted) {
+		t.Fatalf("expected ErrRedacted for an empty redacted email, got %v", err)
+	}
+	if stored, err := plain.Get(ctx, "1"); err != nil || stored.Email != "new@example.com" {
+		t.Errorf("expected the stored email kept, got %+v, %v", stored, err)
+	}
+}
+
+func mustAtomize(t *testing.T, u *RedactedUser) *atom.Atom {
+	t.Helper()
+	atomizer, err := atom.Use[RedactedUser]()
+	if err != nil {
+		t.Fatalf("atom.Use failed: %v", err)
+	}
+	return atomizer.Atomize(u)
 }
 
 func testHookAfterLoadGet(t *testing.T, tc *TestContext) {
@@ -256,6 +654,34 @@ func testHookBeforeSaveError(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testHookSetIfChangedUnchanged(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	tc.InsertUser(t, 1, "same@example.com", "Same", 30)
+
+	db, err := grub.NewDatabase[FailingBeforeSaveUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	// BeforeSave always fails, so any write attempt would surface errTestHook.
+	user := &FailingBeforeSaveUser{ID: 1, Email: "same@example.com", Name: "Same", Age: intPtr(30)}
+	wrote, err := db.SetIfChanged(ctx, "1", user)
+	if err != nil {
+		t.Fatalf("SetIfChanged failed: %v", err)
+	}
+	if wrote {
+		t.Error("expected no write for unchanged record")
+	}
+
+	user.Name = "Changed"
+	_, err = db.SetIfChanged(ctx, "1", user)
+	if !errors.Is(err, errTestHook) {
+		t.Errorf("expected hook error for changed record, got: %v", err)
+	}
+}
+
 // --- CRUD Tests ---
 
 func testGet(t *testing.T, tc *TestContext) {
@@ -277,14 +703,143 @@ func testGet(t *testing.T, tc *TestContext) {
 		t.Fatalf("Get failed: %v", err)
 	}
 
-	if user.Email != "test@example.com" {
-		t.Errorf("expected email 'test@example.com', got %q", user.Email)
+	if user.Email != "test@example.com" {
+		t.Errorf("expected email 'test@example.com', got %q", user.Email)
+	}
+	if user.Name != "Test User" {
+		t.Errorf("expected name 'Test User', got %q", user.Name)
+	}
+	if user.Age == nil || *user.Age != 25 {
+		t.Errorf("expected age 25, got %v", user.Age)
+	}
+}
+
+func testGetByKey(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	_, err := tc.DB.Exec(`INSERT INTO test_users (email, name, age) VALUES ('test@example.com', 'Test User', 25)`)
+	if err != nil {
+		t.Fatalf("failed to insert test record: %v", err)
+	}
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	for _, key := range []any{1, int64(1), "1", "001"} {
+		user, err := db.GetByKey(ctx, key)
+		if err != nil {
+			t.Fatalf("GetByKey(%#v) failed: %v", key, err)
+		}
+		if user.Email != "test@example.com" {
+			t.Errorf("GetByKey(%#v): expected email 'test@example.com', got %q", key, user.Email)
+		}
+	}
+
+	if _, err := db.GetByKey(ctx, "one"); !errors.Is(err, grub.ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+	if _, err := db.GetByKey(ctx, 2); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testQueryLogger(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	var logged []string
+	var last grub.QueryInfo
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer, grub.WithParamLogging(),
+		grub.WithQueryLogger(func(_ context.Context, info grub.QueryInfo) {
+			if info.Err != nil {
+				t.Errorf("statement failed: %q: %v", info.SQL, info.Err)
+			}
+			logged = append(logged, info.SQL)
+			last = info
+		}))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	if err := db.Set(ctx, "1", &TestUser{ID: 1, Email: "log@example.com", Name: "Logged", Age: intPtr(41)}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return db.SetTx(ctx, tx, "2", &TestUser{ID: 2, Email: "tx@example.com", Name: "Tx"})
+	}, nil)
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+
+	user, err := db.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if user.Email != "log@example.com" || user.Age == nil || *user.Age != 41 {
+		t.Errorf("round trip through the logger changed the record: %+v", user)
+	}
+	if len(last.Params) == 0 || last.Rows != 1 {
+		t.Errorf("expected the Get args and one row to be logged, got %+v", last)
+	}
+
+	users, err := db.ExecQuery(ctx, grub.QueryAll, nil)
+	if err != nil {
+		t.Fatalf("ExecQuery failed: %v", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("expected 2 users, got %d", len(users))
+	}
+	if last.Statement != grub.QueryAll.Name() || last.Rows != 2 {
+		t.Errorf("expected the QueryAll statement and 2 rows to be logged, got %+v", last)
+	}
+	if len(logged) != 4 {
+		t.Errorf("expected 4 statements logged, got %d: %q", len(logged), logged)
+	}
+}
+
+func testStatementCache(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer, grub.WithStatementCache(0))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	for n := 1; n <= 3; n++ {
+		key := strconv.Itoa(n)
+		if err := db.Set(ctx, key, &TestUser{ID: n, Email: key + "@example.com", Name: "Cached"}); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		user, err := db.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if user.Email != key+"@example.com" {
+			t.Errorf("expected %s@example.com, got %q", key, user.Email)
+		}
+	}
+	exists, err := db.Exists(ctx, "2")
+	if err != nil || !exists {
+		t.Errorf("expected Exists true, got %v, %v", exists, err)
+	}
+
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return db.SetTx(ctx, tx, "4", &TestUser{ID: 4, Email: "tx@example.com", Name: "Tx"})
+	}, nil)
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
 	}
-	if user.Name != "Test User" {
-		t.Errorf("expected name 'Test User', got %q", user.Name)
+	if _, err := db.Get(ctx, "4"); err != nil {
+		t.Errorf("expected the transaction's write, got %v", err)
 	}
-	if user.Age == nil || *user.Age != 25 {
-		t.Errorf("expected age 25, got %v", user.Age)
+
+	stats := db.StatementCacheStats()
+	if stats.Misses != 3 || stats.Hits != 5 || stats.Size != 3 {
+		t.Errorf("expected Set, Get, and Exists prepared once each, got %+v", stats)
 	}
 }
 
@@ -354,6 +909,45 @@ func testSet(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testInsertReturning(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	first, err := db.InsertReturning(ctx, &TestUser{Email: "first@example.com", Name: "First", Age: intPtr(20)})
+	if err != nil {
+		t.Fatalf("InsertReturning failed: %v", err)
+	}
+	var second *TestUser
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		second, err = db.InsertReturningTx(ctx, tx, &TestUser{Email: "second@example.com", Name: "Second"})
+		return err
+	}, nil)
+	if err != nil {
+		t.Fatalf("InsertReturningTx failed: %v", err)
+	}
+	if first.ID == 0 || second.ID == 0 || first.ID == second.ID {
+		t.Fatalf("expected distinct generated IDs, got %d and %d", first.ID, second.ID)
+	}
+
+	for _, inserted := range []*TestUser{first, second} {
+		got, err := db.Get(ctx, strconv.Itoa(inserted.ID))
+		if err != nil {
+			t.Fatalf("Get(%d) failed: %v", inserted.ID, err)
+		}
+		if got.Email != inserted.Email || got.Name != inserted.Name {
+			t.Errorf("expected %+v, got %+v", inserted, got)
+		}
+	}
+	if first.Age == nil || *first.Age != 20 || second.Age != nil {
+		t.Errorf("expected returned ages 20 and nil, got %v and %v", first.Age, second.Age)
+	}
+}
+
 func testSetUpdate(t *testing.T, tc *TestContext) {
 	tc.Reset(t)
 	ctx := context.Background()
@@ -392,6 +986,83 @@ func testSetUpdate(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testSetIfChanged(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	tc.InsertUser(t, 1, "same@example.com", "Same", 30)
+
+	user := &TestUser{ID: 1, Email: "same@example.com", Name: "Same", Age: intPtr(30)}
+	wrote, err := db.SetIfChanged(ctx, "1", user)
+	if err != nil {
+		t.Fatalf("SetIfChanged (unchanged) failed: %v", err)
+	}
+	if wrote {
+		t.Error("expected no write for unchanged record")
+	}
+
+	user.Age = intPtr(31)
+	wrote, err = db.SetIfChanged(ctx, "1", user)
+	if err != nil {
+		t.Fatalf("SetIfChanged (changed) failed: %v", err)
+	}
+	if !wrote {
+		t.Error("expected write for changed record")
+	}
+
+	got, err := db.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Age == nil || *got.Age != 31 {
+		t.Errorf("expected age 31, got %v", got.Age)
+	}
+}
+
+func testPatch(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	tc.InsertUser(t, 1, "patch@example.com", "Before", 30)
+
+	got, err := db.Patch(ctx, "1", map[string]any{"name": "After", "age": 31})
+	if err != nil {
+		t.Fatalf("Patch failed: %v", err)
+	}
+	if got.Name != "After" || got.Age == nil || *got.Age != 31 || got.Email != "patch@example.com" {
+		t.Errorf("expected the patched row, got %+v", got)
+	}
+
+	name := "Struct"
+	if _, err := db.PatchStruct(ctx, "1", struct {
+		Name *string `db:"name"`
+		Age  *int    `db:"age"`
+	}{Name: &name}); err != nil {
+		t.Fatalf("PatchStruct failed: %v", err)
+	}
+	stored, err := db.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if stored.Name != "Struct" || stored.Age == nil || *stored.Age != 31 || stored.Email != "patch@example.com" {
+		t.Errorf("expected only name to change, got %+v", stored)
+	}
+
+	if _, err := db.Patch(ctx, "999", map[string]any{"name": "Nobody"}); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
 func testSetAtom(t *testing.T, tc *TestContext) {
 	tc.Reset(t)
 	ctx := context.Background()
@@ -470,6 +1141,37 @@ func testDeleteNotFound(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testExists(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+	tc.InsertUser(t, 1, "exists@example.com", "Exists", 30)
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	exists, err := db.Exists(ctx, "1")
+	if err != nil || !exists {
+		t.Errorf("expected Exists true for a stored key, got %v, %v", exists, err)
+	}
+	exists, err = db.Exists(ctx, "999")
+	if err != nil || exists {
+		t.Errorf("expected Exists false for a missing key, got %v, %v", exists, err)
+	}
+
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		exists, err := db.ExistsTx(ctx, tx, "1")
+		if err != nil || !exists {
+			t.Errorf("expected ExistsTx true, got %v, %v", exists, err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+}
+
 // --- Query Tests ---
 
 func testQuery(t *testing.T, tc *TestContext) {
@@ -720,6 +1422,14 @@ func testAggregate(t *testing.T, tc *TestContext) {
 	if count != 3 {
 		t.Errorf("expected count 3, got %v", count)
 	}
+
+	n, err := db.CountAllInt(ctx)
+	if err != nil {
+		t.Fatalf("CountAllInt failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected CountAllInt 3, got %d", n)
+	}
 }
 
 func testAggregateSum(t *testing.T, tc *TestContext) {
@@ -745,13 +1455,337 @@ func testAggregateSum(t *testing.T, tc *TestContext) {
 		Field: "age",
 	})
 
-	sum, err := db.ExecAggregate(ctx, stmt, nil)
+	sum, err := db.ExecAggregate(ctx, stmt, nil)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+
+	if sum != 60 {
+		t.Errorf("expected sum 60, got %v", sum)
+	}
+
+	// SUM is a DECIMAL on MariaDB and a NUMERIC or BIGINT on PostgreSQL;
+	// each must come back as the same int64.
+	total, err := db.ExecAggregateInt(ctx, stmt, nil)
+	if err != nil {
+		t.Fatalf("ExecAggregateInt failed: %v", err)
+	}
+	if total != 60 {
+		t.Errorf("expected ExecAggregateInt 60, got %d", total)
+	}
+
+	avg := edamame.NewAggregateStatement("avg-age", "", edamame.AggAvg, edamame.AggregateSpec{Field: "age"})
+	if mean, err := db.ExecAggregateInt(ctx, avg, nil); err != nil || mean != 20 {
+		t.Errorf("expected a whole AVG of 20, got %d, %v", mean, err)
+	}
+}
+
+func testAggregateMinMaxAvg(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	_, err := tc.DB.Exec(`
+		INSERT INTO test_users (email, name, age) VALUES
+		('a@example.com', 'A', 10),
+		('b@example.com', 'B', 20),
+		('c@example.com', 'C', 25)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test records: %v", err)
+	}
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	// AVG over an integer column must keep its fractional part.
+	cases := []struct {
+		fn   edamame.AggregateFunc
+		want float64
+	}{
+		{edamame.AggMin, 10},
+		{edamame.AggMax, 25},
+		{edamame.AggAvg, 55.0 / 3},
+	}
+	for _, c := range cases {
+		stmt := edamame.NewAggregateStatement(string(c.fn)+"-age", "Aggregate of ages", c.fn, edamame.AggregateSpec{
+			Field: "age",
+		})
+
+		got, err := db.ExecAggregate(ctx, stmt, nil)
+		if err != nil {
+			t.Fatalf("%s failed: %v", c.fn, err)
+		}
+		if math.Abs(got-c.want) > 1e-6 {
+			t.Errorf("expected %s %v, got %v", c.fn, c.want, got)
+		}
+
+		err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			got, err = db.ExecAggregateTx(ctx, tx, stmt, nil)
+			return err
+		}, nil)
+		if err != nil {
+			t.Fatalf("%s in tx failed: %v", c.fn, err)
+		}
+		if math.Abs(got-c.want) > 1e-6 {
+			t.Errorf("expected %s %v in tx, got %v", c.fn, c.want, got)
+		}
+	}
+}
+
+func testWhereIn(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	_, err := tc.DB.Exec(`
+		INSERT INTO test_users (email, name, age) VALUES
+		('a@example.com', 'A', 10),
+		('b@example.com', 'B', 20),
+		('c@example.com', 'C', 30)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test records: %v", err)
+	}
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	byNames := edamame.NewQueryStatement("by-names", "", edamame.QuerySpec{
+		Where:   []edamame.ConditionSpec{{Field: "name", Operator: "IN", Param: "names"}},
+		OrderBy: []edamame.OrderBySpec{{Field: "name", Direction: "asc"}},
+	})
+	notNames := edamame.NewAggregateStatement("count-not-names", "", edamame.AggCount, edamame.AggregateSpec{
+		Where: []edamame.ConditionSpec{{Field: "name", Operator: "NOT IN", Param: "names"}},
+	})
+
+	tests := []struct {
+		names []string
+		want  []string
+	}{
+		{[]string{"A", "C", "Z"}, []string{"A", "C"}},
+		{[]string{"B"}, []string{"B"}},
+		{[]string{}, nil},
+	}
+	for _, tt := range tests {
+		params := map[string]any{"names": tt.names}
+		records, err := db.ExecQuery(ctx, byNames, params)
+		if err != nil {
+			t.Fatalf("ExecQuery(%v) failed: %v", tt.names, err)
+		}
+		var got []string
+		for _, r := range records {
+			got = append(got, r.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("IN %v: expected %v, got %v", tt.names, tt.want, got)
+		}
+
+		count, err := db.ExecAggregate(ctx, notNames, params)
+		if err != nil {
+			t.Fatalf("ExecAggregate(%v) failed: %v", tt.names, err)
+		}
+		if int(count) != 3-len(tt.want) {
+			t.Errorf("NOT IN %v: expected %d, got %v", tt.names, 3-len(tt.want), count)
+		}
+	}
+
+	// A scalar binds as a single value, and on PostgreSQL an array binds
+	// as one param to = ANY.
+	param := any("B")
+	if tc.ArrayParams {
+		param = pq.Array([]string{"B"})
+	}
+	records, err := db.ExecQuery(ctx, byNames, map[string]any{"names": param})
+	if err != nil {
+		t.Fatalf("ExecQuery failed: %v", err)
+	}
+	if len(records) != 1 || records[0].Name != "B" {
+		t.Errorf("expected B, got %v", records)
+	}
+}
+
+func testWhereNull(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	_, err := tc.DB.Exec(`
+		INSERT INTO test_users (email, name, age) VALUES
+		('a@example.com', 'A', 10),
+		('b@example.com', 'B', NULL),
+		('c@example.com', 'C', NULL)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test records: %v", err)
+	}
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	noAge := edamame.NewQueryStatement("no-age", "", edamame.QuerySpec{
+		Where:   []edamame.ConditionSpec{grub.IsNull("age")},
+		OrderBy: []edamame.OrderBySpec{{Field: "name", Direction: "asc"}},
+	})
+	records, err := db.ExecQuery(ctx, noAge, nil)
+	if err != nil {
+		t.Fatalf("ExecQuery failed: %v", err)
+	}
+	if len(records) != 2 || records[0].Name != "B" || records[1].Name != "C" || records[0].Age != nil {
+		t.Errorf("expected B and C with no age, got %v", records)
+	}
+
+	withAge := edamame.NewAggregateStatement("count-with-age", "", edamame.AggCount, edamame.AggregateSpec{
+		Where: []edamame.ConditionSpec{grub.IsNotNull("age")},
+	})
+	count, err := db.ExecAggregate(ctx, withAge, nil)
+	if err != nil {
+		t.Fatalf("ExecAggregate failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 user with an age, got %v", count)
+	}
+
+	// Mixed with a bound condition in an OR group.
+	noAgeOrNamed := edamame.NewQueryStatement("no-age-or-named", "", edamame.QuerySpec{
+		Where: []edamame.ConditionSpec{{Logic: "OR", Group: []edamame.ConditionSpec{
+			grub.IsNull("age"),
+			{Field: "name", Operator: "=", Param: "name"},
+		}}},
+	})
+	records, err = db.ExecQuery(ctx, noAgeOrNamed, map[string]any{"name": "A"})
+	if err != nil {
+		t.Fatalf("ExecQuery failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("expected all 3 users, got %d", len(records))
+	}
+}
+
+func testMultiAggregate(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	_, err := tc.DB.Exec(`
+		INSERT INTO test_users (email, name, age) VALUES
+		('a@example.com', 'A', 10),
+		('b@example.com', 'B', 20),
+		('c@example.com', 'C', 25),
+		('d@example.com', 'D', NULL)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test records: %v", err)
+	}
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	specs := []grub.AggregateSpec{
+		{Alias: "rows", Func: edamame.AggCount},
+		{Alias: "with_age", Func: edamame.AggCount, Field: "age"},
+		{Alias: "total", Func: edamame.AggSum, Field: "age"},
+		{Alias: "mean", Func: edamame.AggAvg, Field: "age"},
+		{Alias: "youngest", Func: edamame.AggMin, Field: "age"},
+		{Alias: "oldest", Func: edamame.AggMax, Field: "age"},
+	}
+	want := map[string]float64{
+		"rows": 4, "with_age": 3, "total": 55, "mean": 55.0 / 3, "youngest": 10, "oldest": 25,
+	}
+	got, err := db.ExecMultiAggregate(ctx, specs, nil, nil)
+	if err != nil {
+		t.Fatalf("ExecMultiAggregate failed: %v", err)
+	}
+	for alias, w := range want {
+		if math.Abs(got[alias]-w) > 1e-6 {
+			t.Errorf("expected %s %v, got %v", alias, w, got[alias])
+		}
+	}
+
+	// Filtered to nothing: COUNT is 0 and the NULL aggregates read as 0.
+	where := []edamame.ConditionSpec{{Field: "age", Operator: ">", Param: "min_age"}}
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		got, err = db.ExecMultiAggregateTx(ctx, tx, specs, where, map[string]any{"min_age": 100})
+		return err
+	}, nil)
+	if err != nil {
+		t.Fatalf("ExecMultiAggregateTx failed: %v", err)
+	}
+	for alias := range want {
+		if got[alias] != 0 {
+			t.Errorf("expected %s 0 over no rows, got %v", alias, got[alias])
+		}
+	}
+}
+
+func testAggregateGroup(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	_, err := tc.DB.Exec(`
+		INSERT INTO test_users (email, name, age) VALUES
+		('a@example.com', 'A', 20),
+		('b@example.com', 'B', 20),
+		('c@example.com', 'C', 30),
+		('d@example.com', 'D', 30),
+		('e@example.com', 'E', 40)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert test records: %v", err)
+	}
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	// Users per age, busiest first and then oldest first, leaving out
+	// ages held by a single user.
+	perAge := grub.AggregateGroupSpec{
+		GroupBy:    []string{"age"},
+		Func:       edamame.AggCount,
+		Having:     []edamame.HavingAggSpec{{Func: "count", Operator: ">=", Param: "min_count"}},
+		OrderBy:    []edamame.OrderBySpec{{Field: "age", Direction: "desc"}},
+		ValueOrder: "desc",
+	}
+	rows, err := db.ExecAggregateGroup(ctx, perAge, map[string]any{"min_count": 2})
 	if err != nil {
-		t.Fatalf("Aggregate failed: %v", err)
+		t.Fatalf("ExecAggregateGroup failed: %v", err)
+	}
+	// The driver decides the group value's type, so compare it as text.
+	got := make([]string, len(rows))
+	for i, row := range rows {
+		got[i] = fmt.Sprintf("%v:%v", row.Group["age"], row.Value)
+	}
+	if want := []string{"30:2", "20:2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected groups %v, got %v", want, got)
 	}
 
-	if sum != 60 {
-		t.Errorf("expected sum 60, got %v", sum)
+	// Summed per name within a transaction, ordered by the group column.
+	perName := grub.AggregateGroupSpec{
+		GroupBy: []string{"name"},
+		Func:    edamame.AggSum,
+		Field:   "age",
+		Where:   []edamame.ConditionSpec{{Field: "age", Operator: ">", Param: "min_age"}},
+		OrderBy: []edamame.OrderBySpec{{Field: "name", Direction: "desc"}},
+	}
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		rows, err = db.ExecAggregateGroupTx(ctx, tx, perName, map[string]any{"min_age": 25})
+		return err
+	}, nil)
+	if err != nil {
+		t.Fatalf("ExecAggregateGroupTx failed: %v", err)
+	}
+	got = got[:0]
+	for _, row := range rows {
+		got = append(got, fmt.Sprintf("%v:%v", row.Group["name"], row.Value))
+	}
+	if want := []string{"E:40", "D:30", "C:30"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected groups %v, got %v", want, got)
 	}
 }
 
@@ -807,6 +1841,42 @@ func testQueryPagination(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testQueryAfter(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+	for id := 1; id <= 5; id++ {
+		tc.InsertUser(t, id, "user"+strconv.Itoa(id)+"@example.com", "User "+strconv.Itoa(id), 20+id)
+	}
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	var names []string
+	var after any
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("expected pagination to end after 3 pages")
+		}
+		rows, next, err := db.QueryAfter(ctx, "age", after, 2)
+		if err != nil {
+			t.Fatalf("QueryAfter failed: %v", err)
+		}
+		for _, r := range rows {
+			names = append(names, r.Name)
+		}
+		if next == nil {
+			break
+		}
+		after = next
+	}
+	want := []string{"User 1", "User 2", "User 3", "User 4", "User 5"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected %v in age order, got %v", want, names)
+	}
+}
+
 // RunTransactionTests runs the transaction test suite.
 func RunTransactionTests(t *testing.T, tc *TestContext) {
 	t.Run("GetTx", func(t *testing.T) { testGetTx(t, tc) })
@@ -814,6 +1884,10 @@ func RunTransactionTests(t *testing.T, tc *TestContext) {
 	t.Run("DeleteTx", func(t *testing.T) { testDeleteTx(t, tc) })
 	t.Run("TransactionCommit", func(t *testing.T) { testTransactionCommit(t, tc) })
 	t.Run("TransactionRollback", func(t *testing.T) { testTransactionRollback(t, tc) })
+	t.Run("WithTxCommit", func(t *testing.T) { testWithTxCommit(t, tc) })
+	t.Run("WithTxRollback", func(t *testing.T) { testWithTxRollback(t, tc) })
+	t.Run("UnitCompensation", func(t *testing.T) { testUnitCompensation(t, tc) })
+	t.Run("UnitExecuteTx", func(t *testing.T) { testUnitExecuteTx(t, tc) })
 	t.Run("QueryTx", func(t *testing.T) { testQueryTx(t, tc) })
 	t.Run("UpdateTx", func(t *testing.T) { testUpdateTx(t, tc) })
 	t.Run("AggregateTx", func(t *testing.T) { testAggregateTx(t, tc) })
@@ -1010,6 +2084,124 @@ func testTransactionRollback(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testWithTxCommit(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	user := &TestUser{ID: 1, Email: "withtx@example.com", Name: "WithTx User", Age: intPtr(33)}
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		return db.SetTx(ctx, tx, "1", user)
+	}, nil)
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+
+	got, err := db.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get after WithTx failed: %v", err)
+	}
+	if got.Email != "withtx@example.com" {
+		t.Errorf("expected email 'withtx@example.com', got %q", got.Email)
+	}
+}
+
+func testWithTxRollback(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	errAbort := errors.New("abort")
+	user := &TestUser{ID: 1, Email: "withtx-rb@example.com", Name: "WithTx Rollback", Age: intPtr(34)}
+	err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := db.SetTx(ctx, tx, "1", user); err != nil {
+			return err
+		}
+		return errAbort
+	}, nil)
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected abort error, got: %v", err)
+	}
+
+	_, err = db.Get(ctx, "1")
+	if !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after rollback, got: %v", err)
+	}
+}
+
+func testUnitCompensation(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	tc.InsertUser(t, 1, "before@example.com", "Before", 20)
+
+	errAbort := errors.New("abort")
+	err = grub.NewUnit().
+		Add(grub.DatabaseSetStep(db, "1", &TestUser{ID: 1, Email: "after@example.com", Name: "After", Age: intPtr(21)})).
+		Add(grub.DatabaseSetStep(db, "2", &TestUser{ID: 2, Email: "new@example.com", Name: "New", Age: intPtr(22)})).
+		Add(func(context.Context) error { return errAbort }, nil).
+		Execute(ctx)
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected abort error, got: %v", err)
+	}
+
+	got, err := db.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Email != "before@example.com" {
+		t.Errorf("expected existing row restored, got email %q", got.Email)
+	}
+	if _, err := db.Get(ctx, "2"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected inserted row removed, got: %v", err)
+	}
+}
+
+func testUnitExecuteTx(t *testing.T, tc *TestContext) {
+	tc.Reset(t)
+	ctx := context.Background()
+
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+
+	errAbort := errors.New("abort")
+	err = grub.NewUnit().
+		Add(grub.DatabaseSetStep(db, "1", &TestUser{ID: 1, Email: "unit@example.com", Name: "Unit", Age: intPtr(30)})).
+		Add(func(context.Context) error { return errAbort }, nil).
+		ExecuteTx(ctx, db, nil)
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected abort error, got: %v", err)
+	}
+	if _, err := db.Get(ctx, "1"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after rollback, got: %v", err)
+	}
+
+	err = grub.NewUnit().
+		Add(grub.DatabaseSetStep(db, "1", &TestUser{ID: 1, Email: "unit@example.com", Name: "Unit", Age: intPtr(30)})).
+		ExecuteTx(ctx, db, nil)
+	if err != nil {
+		t.Fatalf("ExecuteTx failed: %v", err)
+	}
+	if _, err := db.Get(ctx, "1"); err != nil {
+		t.Errorf("expected committed row, got: %v", err)
+	}
+}
+
 func testQueryTx(t *testing.T, tc *TestContext) {
 	tc.Reset(t)
 	ctx := context.Background()
@@ -1129,4 +2321,405 @@ func testAggregateTx(t *testing.T, tc *TestContext) {
 	if count != 2 {
 		t.Errorf("expected count 2, got %v", count)
 	}
+
+	n, err := db.ExecAggregateIntTx(ctx, tx, grub.CountAll, nil)
+	if err != nil {
+		t.Fatalf("ExecAggregateIntTx failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected ExecAggregateIntTx 2, got %d", n)
+	}
+}
+
+func testHistoryAsOf(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	if _, err := tc.DB.Exec(tc.HistorySQL); err != nil {
+		t.Fatalf("failed to reset test_history: %v", err)
+	}
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_history", tc.Renderer, grub.WithHistory(tc.History))
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	if err := db.EnsureHistory(ctx); err != nil {
+		t.Fatalf("EnsureHistory failed: %v", err)
+	}
+	if err := db.EnsureHistory(ctx); err != nil {
+		t.Fatalf("EnsureHistory is not repeatable: %v", err)
+	}
+
+	// Pause either side of each checkpoint so the row versions are
+	// clearly ordered around it.
+	checkpoint := func() time.Time {
+		time.Sleep(200 * time.Millisecond)
+		at := time.Now()
+		time.Sleep(200 * time.Millisecond)
+		return at
+	}
+	// Plain statements rather than Set, which cannot upsert on SQL Server.
+	write := func(name, query string, args ...any) {
+		t.Helper()
+		if _, err := tc.DB.ExecContext(ctx, tc.DB.Rebind(query), args...); err != nil {
+			t.Fatalf("writing %s failed: %v", name, err)
+		}
+	}
+	set := func(name string) {
+		t.Helper()
+		write(name, "UPDATE test_history SET name = ? WHERE id = ?", name, 1)
+	}
+
+	before := checkpoint()
+	write("v1", "INSERT INTO test_history (id, email, name, age) VALUES (?, ?, ?, ?)", 1, "h@example.com", "v1", 30)
+	t1 := checkpoint()
+	set("v2")
+	t2 := checkpoint()
+	set("v3")
+
+	for _, tt := range []struct {
+		at   time.Time
+		want string
+	}{{t1, "v1"}, {t2, "v2"}, {time.Now().Add(time.Hour), "v3"}} {
+		got, err := db.GetAsOf(ctx, "1", tt.at)
+		if err != nil {
+			t.Fatalf("GetAsOf %s failed: %v", tt.want, err)
+		}
+		if got.Name != tt.want {
+			t.Errorf("expected %s, got %s", tt.want, got.Name)
+		}
+	}
+	if _, err := db.GetAsOf(ctx, "1", before); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound before the insert, got %v", err)
+	}
+
+	users, err := db.ExecQueryAsOf(ctx, grub.QueryAll, nil, t2)
+	if err != nil {
+		t.Fatalf("ExecQueryAsOf failed: %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "v2" {
+		t.Errorf("expected [v2], got %v", users)
+	}
+
+	current, err := db.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if current.Name != "v3" {
+		t.Errorf("expected the current row to be v3, got %s", current.Name)
+	}
+}
+
+func newOutboxDatabase(t *testing.T, tc *TestContext, opts ...grub.Option) *grub.Database[TestUser] {
+	t.Helper()
+	tc.Reset(t)
+	if _, err := tc.DB.Exec("DROP TABLE IF EXISTS test_outbox"); err != nil {
+		t.Fatalf("failed to drop test_outbox: %v", err)
+	}
+	opts = append([]grub.Option{grub.WithOutbox[TestUser]("test_outbox", nil)}, opts...)
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer, opts...)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	for range 2 {
+		if err := db.EnsureOutbox(context.Background()); err != nil {
+			t.Fatalf("EnsureOutbox failed: %v", err)
+		}
+	}
+	return db
+}
+
+func outboxCount(t *testing.T, tc *TestContext) int {
+	t.Helper()
+	var n int
+	if err := tc.DB.Get(&n, "SELECT COUNT(*) FROM test_outbox"); err != nil {
+		t.Fatalf("failed to count outbox rows: %v", err)
+	}
+	return n
+}
+
+func testOutboxCommit(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	db := newOutboxDatabase(t, tc)
+
+	if err := db.Set(ctx, "1", &TestUser{ID: 1, Email: "o@example.com", Name: "Outbox", Age: intPtr(30)}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if n := outboxCount(t, tc); n != 1 {
+		t.Fatalf("expected 1 outbox row after Set, got %d", n)
+	}
+	if err := db.Delete(ctx, "1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if n := outboxCount(t, tc); n != 2 {
+		t.Fatalf("expected 2 outbox rows after Delete, got %d", n)
+	}
+	if err := db.Delete(ctx, "1"); !errors.Is(err, grub.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if n := outboxCount(t, tc); n != 2 {
+		t.Errorf("expected a missing row to record nothing, got %d outbox rows", n)
+	}
+}
+
+func testOutboxRollback(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	db := newOutboxDatabase(t, tc)
+
+	forced := errors.New("forced after write")
+	err := db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := db.SetTx(ctx, tx, "2", &TestUser{ID: 2, Email: "r@example.com", Name: "Rolled"}); err != nil {
+			return err
+		}
+		return forced
+	}, nil)
+	if !errors.Is(err, forced) {
+		t.Fatalf("expected the forced error, got %v", err)
+	}
+	if _, err := db.Get(ctx, "2"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected the row rolled back, got %v", err)
+	}
+	if n := outboxCount(t, tc); n != 0 {
+		t.Errorf("expected the outbox row rolled back, got %d", n)
+	}
+
+	// A failing payload aborts Set after the upsert has run.
+	failing, err := grub.NewDatabase[TestUser](tc.DB, "test_users", tc.Renderer,
+		grub.WithOutbox("test_outbox", func(string, string, *TestUser) ([]byte, error) { return nil, forced }))
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	if err := failing.Set(ctx, "3", &TestUser{ID: 3, Email: "p@example.com", Name: "Payload"}); !errors.Is(err, forced) {
+		t.Fatalf("expected the payload error, got %v", err)
+	}
+	if _, err := db.Get(ctx, "3"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected the row rolled back, got %v", err)
+	}
+	if n := outboxCount(t, tc); n != 0 {
+		t.Errorf("expected no outbox rows, got %d", n)
+	}
+}
+
+func testOutboxPoller(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	db := newOutboxDatabase(t, tc)
+	for id := 1; id <= 3; id++ {
+		key := strconv.Itoa(id)
+		if err := db.Set(ctx, key, &TestUser{ID: id, Email: key + "@example.com", Name: "User " + key}); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if err := db.Delete(ctx, "2"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	var got []string
+	fail := true
+	poller := grub.NewOutboxPoller(tc.DB, "test_outbox", func(_ context.Context, rec *grub.OutboxRecord) error {
+		if rec.Aggregate != "test_users" {
+			t.Errorf("expected aggregate test_users, got %q", rec.Aggregate)
+		}
+		if rec.Key == "3" && fail {
+			fail = false
+			return errors.New("transient")
+		}
+		got = append(got, rec.Op+":"+rec.Key)
+		return nil
+	}, grub.WithMaxBatchSize(10))
+
+	if n, err := poller.Poll(ctx); n != 2 || err == nil {
+		t.Fatalf("expected the handler error after 2 records, got %d, %v", n, err)
+	}
+	if n, err := poller.Poll(ctx); n != 2 || err != nil {
+		t.Fatalf("expected the remaining 2 records, got %d, %v", n, err)
+	}
+	if n, err := poller.Poll(ctx); n != 0 || err != nil {
+		t.Fatalf("expected nothing left, got %d, %v", n, err)
+	}
+	want := []string{"set:1", "set:2", "set:3", "delete:2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func testSetBatch(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	if _, err := tc.DB.Exec(tc.BatchSQL); err != nil {
+		t.Fatalf("failed to reset test_batch: %v", err)
+	}
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_batch", tc.Renderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	const total = 2000
+	items := make(map[string]*TestUser, total)
+	for i := 1; i <= total; i++ {
+		items[strconv.Itoa(i)] = &TestUser{ID: i, Email: "batch" + strconv.Itoa(i) + "@example.com", Name: "Batch User", Age: intPtr(i % 90)}
+	}
+	if err := db.SetBatch(ctx, items); err != nil {
+		t.Fatalf("SetBatch failed: %v", err)
+	}
+
+	var count int
+	if err := tc.DB.GetContext(ctx, &count, "SELECT COUNT(*) FROM test_batch"); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if count != total {
+		t.Fatalf("expected %d rows, got %d", total, count)
+	}
+	for _, id := range []int{1, 777, total} {
+		got, err := db.Get(ctx, strconv.Itoa(id))
+		if err != nil {
+			t.Fatalf("Get %d failed: %v", id, err)
+		}
+		if got.Email != items[strconv.Itoa(id)].Email || got.Age == nil || *got.Age != id%90 {
+			t.Errorf("row %d: unexpected contents %+v", id, got)
+		}
+	}
+
+	// A second batch updates existing rows and inserts new ones.
+	update := map[string]*TestUser{
+		"5":    {ID: 5, Email: "batch5@example.com", Name: "Renamed"},
+		"2001": {ID: 2001, Email: "batch2001@example.com", Name: "Added"},
+	}
+	if err := db.SetBatch(ctx, update); err != nil {
+		t.Fatalf("SetBatch update failed: %v", err)
+	}
+	got, err := db.Get(ctx, "5")
+	if err != nil {
+		t.Fatalf("Get 5 failed: %v", err)
+	}
+	if got.Name != "Renamed" || got.Age != nil {
+		t.Errorf("expected row 5 replaced, got %+v", got)
+	}
+	if _, err := db.Get(ctx, "2001"); err != nil {
+		t.Errorf("expected row 2001 inserted, got %v", err)
+	}
+}
+
+func testSetBatchPartial(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	if _, err := tc.DB.Exec(tc.BatchSQL); err != nil {
+		t.Fatalf("failed to reset test_batch: %v", err)
+	}
+	db, err := grub.NewDatabase[TestUser](tc.DB, "test_batch", tc.Renderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	if err := db.Set(ctx, "1", &TestUser{ID: 1, Email: "taken@example.com", Name: "Existing"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	results, err := db.SetBatchPartial(ctx, map[string]*TestUser{
+		"2": {ID: 2, Email: "two@example.com", Name: "Two"},
+		"3": {ID: 3, Email: "taken@example.com", Name: "Duplicate"},
+		"4": {ID: 4, Email: "four@example.com", Name: "Four"},
+	})
+	if err != nil {
+		t.Fatalf("SetBatchPartial failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected three results, got %+v", results)
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("expected 2 and 4 to be written, got %v, %v", results[0].Err, results[2].Err)
+	}
+	if results[1].Key != "3" || !errors.Is(results[1].Err, grub.ErrUniqueViolation) {
+		t.Errorf("expected a unique violation for 3, got %+v", results[1])
+	}
+	for _, key := range []string{"2", "4"} {
+		if _, err := db.Get(ctx, key); err != nil {
+			t.Errorf("expected %s to be written, got %v", key, err)
+		}
+	}
+	if _, err := db.Get(ctx, "3"); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected 3 to be skipped, got %v", err)
+	}
+}
+
+// newSettingsDB resets test_settings and seeds it with three records.
+func newSettingsDB(t *testing.T, tc *TestContext) *grub.Database[SettingsRecord] {
+	t.Helper()
+	if _, err := tc.DB.Exec(tc.JSONSQL); err != nil {
+		t.Fatalf("failed to reset test_settings: %v", err)
+	}
+	db, err := grub.NewDatabase[SettingsRecord](tc.DB, "test_settings", tc.Renderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	seed := []map[string]any{
+		{"theme": "dark", "address": map[string]any{"city": "Berlin"}, "tags": []any{"admin", "beta"}, "labels": map[string]any{"team": "core"}},
+		{"theme": "light", "address": map[string]any{"city": "Bern"}},
+		{"theme": "dark", "address": map[string]any{"city": "Oslo"}},
+	}
+	for i, settings := range seed {
+		rec := &SettingsRecord{ID: i + 1}
+		rec.Settings.V = settings
+		if err := db.Set(context.Background(), strconv.Itoa(i+1), rec); err != nil {
+			t.Fatalf("Set %d failed: %v", i+1, err)
+		}
+	}
+	return db
+}
+
+func testJSONRoundTrip(t *testing.T, tc *TestContext) {
+	db := newSettingsDB(t, tc)
+	got, err := db.Get(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	want := map[string]any{
+		"theme":   "dark",
+		"address": map[string]any{"city": "Berlin"},
+		"tags":    []any{"admin", "beta"},
+		"labels":  map[string]any{"team": "core"},
+	}
+	if !reflect.DeepEqual(got.Settings.V, want) {
+		t.Errorf("expected %+v, got %+v", want, got.Settings.V)
+	}
+}
+
+func testWhereJSON(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	db := newSettingsDB(t, tc)
+	ids := func(records []*SettingsRecord) []int {
+		out := make([]int, 0, len(records))
+		for _, r := range records {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	dark, err := db.Query().
+		WhereAnd(grub.JSONCondition("settings", "$.theme", "=", "theme")).
+		OrderBy("id", "ASC").
+		Exec(ctx, map[string]any{"theme": "dark"})
+	if err != nil {
+		t.Fatalf("Query by theme failed: %v", err)
+	}
+	if got := ids(dark); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("expected records 1 and 3, got %v", got)
+	}
+
+	bern, err := db.Query().
+		WhereAnd(
+			grub.JSONCondition("settings", "$.address.city", "LIKE", "city"),
+			grub.JSONCondition("settings", "$.theme", "!=", "theme"),
+		).
+		Exec(ctx, map[string]any{"city": "Ber%", "theme": "dark"})
+	if err != nil {
+		t.Fatalf("Query by city failed: %v", err)
+	}
+	if got := ids(bern); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("expected record 2, got %v", got)
+	}
+
+	byTag := edamame.NewQueryStatement("by-first-tag", "", edamame.QuerySpec{
+		Where: []edamame.ConditionSpec{grub.WhereJSON("settings", "$.tags[0]", "=", "tag")},
+	})
+	admin, err := db.ExecQuery(ctx, byTag, map[string]any{"tag": "admin"})
+	if err != nil {
+		t.Fatalf("Query by tag failed: %v", err)
+	}
+	if got := ids(admin); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("expected record 1, got %v", got)
+	}
 }
@@ -1,11 +1,15 @@
 package sqlite
 
 import (
+	"context"
+	"errors"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/jmoiron/sqlx"
 	astqlsqlite "github.com/zoobzio/astql/sqlite"
+	"github.com/zoobzio/grub"
 	"github.com/zoobzio/grub/testing/integration/database"
 	_ "modernc.org/sqlite"
 )
@@ -31,6 +35,33 @@ func TestMain(m *testing.M) {
 			)
 		`,
 		InsertUserSQL: `INSERT INTO test_users (id, email, name, age) VALUES (?, ?, ?, ?)`,
+		NullableSQL: `
+			DROP TABLE IF EXISTS test_nullables;
+			CREATE TABLE test_nullables (
+				id INTEGER PRIMARY KEY,
+				label TEXT,
+				qty INTEGER,
+				units INTEGER,
+				ratio REAL,
+				active BOOLEAN,
+				created_at TIMESTAMP NOT NULL,
+				expires_at TIMESTAMP
+			)
+		`,
+		Outbox: true,
+		BatchSQL: `
+			DROP TABLE IF EXISTS test_batch;
+			CREATE TABLE test_batch (
+				id INTEGER PRIMARY KEY,
+				email TEXT NOT NULL UNIQUE,
+				name TEXT NOT NULL,
+				age INTEGER
+			)
+		`,
+		JSONSQL: `
+			DROP TABLE IF EXISTS test_settings;
+			CREATE TABLE test_settings (id INTEGER PRIMARY KEY, settings TEXT)
+		`,
 	}
 
 	code := m.Run()
@@ -55,3 +86,242 @@ func TestSQLite_Transaction(t *testing.T) {
 func TestSQLite_Hooks(t *testing.T) {
 	database.RunHookTests(t, tc)
 }
+
+func TestSQLite_Constraints(t *testing.T) {
+	database.RunConstraintTests(t, tc)
+}
+
+func TestSQLite_Nullable(t *testing.T) {
+	database.RunNullableTests(t, tc)
+}
+
+// tag has a TEXT primary key, which SQLite leaves NULL when omitted.
+type tag struct {
+	Name  string `db:"name" constraints:"primarykey"`
+	Color string `db:"color"`
+}
+
+func TestSQLite_InsertReturningKeyNotGenerated(t *testing.T) {
+	ctx := context.Background()
+	if _, err := tc.DB.Exec(`
+		DROP TABLE IF EXISTS test_tags;
+		CREATE TABLE test_tags (name TEXT PRIMARY KEY, color TEXT NOT NULL)
+	`); err != nil {
+		t.Fatalf("failed to create tags table: %v", err)
+	}
+	tags, err := grub.NewDatabase[tag](tc.DB, "test_tags", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create tags database: %v", err)
+	}
+	_, err = tags.InsertReturning(ctx, &tag{Name: "ignored", Color: "red"})
+	if !errors.Is(err, grub.ErrKeyNotGenerated) {
+		t.Errorf("expected ErrKeyNotGenerated, got %v", err)
+	}
+}
+
+func TestSQLite_KeyGenerator(t *testing.T) {
+	ctx := context.Background()
+	if _, err := tc.DB.Exec(`
+		DROP TABLE IF EXISTS test_tags;
+		CREATE TABLE test_tags (name TEXT PRIMARY KEY, color TEXT NOT NULL)
+	`); err != nil {
+		t.Fatalf("failed to create tags table: %v", err)
+	}
+	next := grub.GenerateULID()
+	tags, err := grub.NewDatabase[tag](tc.DB, "test_tags", tc.Renderer,
+		grub.WithKeyGenerator(func() string { return next }))
+	if err != nil {
+		t.Fatalf("failed to create tags database: %v", err)
+	}
+
+	first := &tag{Color: "red"}
+	if err := tags.Set(ctx, "", first); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if first.Name != next {
+		t.Errorf("expected generated key %q, got %q", next, first.Name)
+	}
+	if got, err := tags.Get(ctx, next); err != nil || got.Color != "red" {
+		t.Fatalf("expected stored row, got %+v, %v", got, err)
+	}
+
+	// The generator repeats itself: the insert must fail, not overwrite.
+	if err := tags.Set(ctx, "", &tag{Color: "blue"}); !errors.Is(err, grub.ErrDuplicate) {
+		t.Errorf("expected ErrDuplicate, got %v", err)
+	}
+	if _, _, err := tags.Create(ctx, &tag{Color: "green"}); !errors.Is(err, grub.ErrDuplicate) {
+		t.Errorf("expected ErrDuplicate from Create, got %v", err)
+	}
+	if got, err := tags.Get(ctx, next); err != nil || got.Color != "red" {
+		t.Errorf("expected original row intact, got %+v, %v", got, err)
+	}
+
+	next = grub.GenerateULID()
+	created, key, err := tags.Create(ctx, &tag{Color: "green"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if key != next || created.Name != next || created.Color != "green" {
+		t.Errorf("expected created row under %q, got %q and %+v", next, key, created)
+	}
+}
+
+type profileSettings struct {
+	Theme  string            `json:"theme"`
+	Labels map[string]string `json:"labels"`
+}
+
+// profile stores its settings as JSON and a copy gob-encoded in a BLOB.
+type profile struct {
+	ID       int                                          `db:"id" constraints:"primarykey"`
+	Settings grub.JSON[profileSettings]                   `db:"settings"`
+	Backup   grub.Encoded[profileSettings, grub.GobCodec] `db:"backup"`
+}
+
+func TestSQLite_EncodedColumns(t *testing.T) {
+	ctx := context.Background()
+	if _, err := tc.DB.Exec(`
+		DROP TABLE IF EXISTS test_profiles;
+		CREATE TABLE test_profiles (id INTEGER PRIMARY KEY, settings TEXT, backup BLOB)
+	`); err != nil {
+		t.Fatalf("failed to create profiles table: %v", err)
+	}
+	profiles, err := grub.NewDatabase[profile](tc.DB, "test_profiles", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create profiles database: %v", err)
+	}
+
+	settings := profileSettings{Theme: "dark", Labels: map[string]string{"team": "core"}}
+	in := &profile{ID: 1}
+	in.Settings.V = settings
+	in.Backup.V = settings
+	if err := profiles.Set(ctx, "1", in); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	var raw string
+	if err := tc.DB.Get(&raw, `SELECT settings FROM test_profiles WHERE id = 1`); err != nil {
+		t.Fatalf("failed to read raw column: %v", err)
+	}
+	if raw != `{"theme":"dark","labels":{"team":"core"}}` {
+		t.Errorf("expected JSON text in column, got %q", raw)
+	}
+
+	got, err := profiles.Get(ctx, "1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	for name, s := range map[string]profileSettings{"settings": got.Settings.V, "backup": got.Backup.V} {
+		if s.Theme != "dark" || s.Labels["team"] != "core" {
+			t.Errorf("%s: expected decoded settings, got %+v", name, s)
+		}
+	}
+
+	if _, err := tc.DB.Exec(`INSERT INTO test_profiles (id, settings, backup) VALUES (2, NULL, NULL)`); err != nil {
+		t.Fatalf("failed to insert NULL row: %v", err)
+	}
+	empty, err := profiles.Get(ctx, "2")
+	if err != nil {
+		t.Fatalf("Get NULL row failed: %v", err)
+	}
+	if empty.Settings.V.Theme != "" || empty.Backup.V.Labels != nil {
+		t.Errorf("expected zero settings for NULL columns, got %+v", empty)
+	}
+}
+
+// order references database.TestUser through UserID.
+type order struct {
+	ID     int                `db:"id" constraints:"primarykey"`
+	UserID int                `db:"user_id"`
+	Total  int                `db:"total"`
+	User   *database.TestUser `db:"-"`
+}
+
+func TestSQLite_Relations(t *testing.T) {
+	tc.Reset(t)
+	ctx := context.Background()
+	if _, err := tc.DB.Exec(`
+		DROP TABLE IF EXISTS test_orders;
+		CREATE TABLE test_orders (
+			id INTEGER PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			total INTEGER NOT NULL
+		)
+	`); err != nil {
+		t.Fatalf("failed to create orders table: %v", err)
+	}
+	tc.InsertUser(t, 1, "alice@example.com", "Alice", 30)
+	tc.InsertUser(t, 2, "bob@example.com", "Bob", 40)
+	tc.InsertUser(t, 3, "carol@example.com", "Carol", 50)
+	for _, o := range [][3]int{{1, 1, 10}, {2, 1, 20}, {3, 2, 30}, {4, 99, 40}} {
+		if _, err := tc.DB.Exec(`INSERT INTO test_orders (id, user_id, total) VALUES (?, ?, ?)`, o[0], o[1], o[2]); err != nil {
+			t.Fatalf("failed to insert order: %v", err)
+		}
+	}
+
+	users, err := grub.NewDatabase[database.TestUser](tc.DB, "test_users", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create users database: %v", err)
+	}
+	orders, err := grub.NewDatabase[order](tc.DB, "test_orders", tc.Renderer)
+	if err != nil {
+		t.Fatalf("failed to create orders database: %v", err)
+	}
+
+	t.Run("BelongsTo", func(t *testing.T) {
+		all, err := orders.ExecQuery(ctx, grub.QueryAll, nil)
+		if err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		err = grub.LoadBelongsTo(ctx, all, users,
+			func(o *order) string { return strconv.Itoa(o.UserID) },
+			func(o *order, u *database.TestUser) { o.User = u })
+		if err != nil {
+			t.Fatalf("LoadBelongsTo failed: %v", err)
+		}
+		for _, o := range all {
+			switch o.UserID {
+			case 99:
+				if o.User != nil {
+					t.Errorf("order %d: expected nil user for missing parent", o.ID)
+				}
+			default:
+				if o.User == nil || o.User.ID != o.UserID {
+					t.Errorf("order %d: expected user %d, got %+v", o.ID, o.UserID, o.User)
+				}
+			}
+		}
+	})
+
+	t.Run("HasMany", func(t *testing.T) {
+		all, err := users.ExecQuery(ctx, grub.QueryAll, nil)
+		if err != nil {
+			t.Fatalf("ExecQuery failed: %v", err)
+		}
+		byUser := make(map[int][]*order)
+		err = grub.LoadHasMany(ctx, all, orders, "user_id",
+			func(u *database.TestUser) string { return strconv.Itoa(u.ID) },
+			func(o *order) string { return strconv.Itoa(o.UserID) },
+			func(u *database.TestUser, os []*order) { byUser[u.ID] = os })
+		if err != nil {
+			t.Fatalf("LoadHasMany failed: %v", err)
+		}
+		for id, want := range map[int]int{1: 2, 2: 1, 3: 0} {
+			if got := len(byUser[id]); got != want {
+				t.Errorf("user %d: expected %d orders, got %d", id, want, got)
+			}
+		}
+	})
+}
+
+func TestSQLite_Outbox(t *testing.T) {
+	database.RunOutboxTests(t, tc)
+}
+
+func TestSQLite_Batch(t *testing.T) {
+	database.RunBatchTests(t, tc)
+}
+
+func TestSQLite_JSON(t *testing.T) {
+	database.RunJSONTests(t, tc)
+}
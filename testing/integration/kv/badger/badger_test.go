@@ -6,6 +6,7 @@ import (
 
 	"github.com/dgraph-io/badger/v4"
 	grubbadger "github.com/zoobzio/grub/badger"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/kv"
 )
 
@@ -44,6 +45,10 @@ func TestBadger_CRUD(t *testing.T) {
 	kv.RunCRUDTests(t, tc)
 }
 
+func TestBadger_Conformance(t *testing.T) {
+	conformance.RunStoreProviderConformance(t, tc.Provider)
+}
+
 func TestBadger_Atomic(t *testing.T) {
 	kv.RunAtomicTests(t, tc)
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	grubbolt "github.com/zoobzio/grub/bolt"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/kv"
 	"go.etcd.io/bbolt"
 )
@@ -43,6 +44,10 @@ func TestBolt_CRUD(t *testing.T) {
 	kv.RunCRUDTests(t, tc)
 }
 
+func TestBolt_Conformance(t *testing.T) {
+	conformance.RunStoreProviderConformance(t, tc.Provider)
+}
+
 func TestBolt_Atomic(t *testing.T) {
 	kv.RunAtomicTests(t, tc)
 }
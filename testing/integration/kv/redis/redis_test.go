@@ -11,6 +11,7 @@ import (
 	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
 	"github.com/testcontainers/testcontainers-go/wait"
 	grubredis "github.com/zoobzio/grub/redis"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/kv"
 )
 
@@ -61,6 +62,10 @@ func TestRedis_CRUD(t *testing.T) {
 	kv.RunCRUDTests(t, tc)
 }
 
+func TestRedis_Conformance(t *testing.T) {
+	conformance.RunStoreProviderConformance(t, tc.Provider)
+}
+
 func TestRedis_Atomic(t *testing.T) {
 	kv.RunAtomicTests(t, tc)
 }
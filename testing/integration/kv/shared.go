@@ -53,7 +53,9 @@ func RunTTLTests(t *testing.T, tc *TestContext) {
 func RunBatchTests(t *testing.T, tc *TestContext) {
 	t.Run("List", func(t *testing.T) { testList(t, tc) })
 	t.Run("ListWithLimit", func(t *testing.T) { testListWithLimit(t, tc) })
+	t.Run("Scan", func(t *testing.T) { testScan(t, tc) })
 	t.Run("GetBatch", func(t *testing.T) { testGetBatch(t, tc) })
+	t.Run("ExistsBatch", func(t *testing.T) { testExistsBatch(t, tc) })
 	t.Run("SetBatch", func(t *testing.T) { testSetBatch(t, tc) })
 }
 
@@ -420,6 +422,43 @@ func testList(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testScan(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	store := grub.NewStore[TestValue](tc.Provider)
+
+	want := make(map[string]int)
+	for i := 0; i < 5; i++ {
+		key := "scan-prefix-" + string(rune('a'+i))
+		value := &TestValue{ID: key, Name: "Scan Value", Count: i}
+		if err := store.Set(ctx, key, value, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		want[key] = i
+	}
+
+	it, err := store.Scan(ctx)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	found := 0
+	for it.Next() {
+		count, ok := want[it.Key()]
+		if !ok {
+			continue // records left by other tests
+		}
+		found++
+		if it.Value().ID != it.Key() || it.Value().Count != count {
+			t.Errorf("unexpected value for %s: %+v", it.Key(), it.Value())
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Scan iteration failed: %v", err)
+	}
+	if found != len(want) {
+		t.Errorf("expected %d scanned records, got %d", len(want), found)
+	}
+}
+
 func testListWithLimit(t *testing.T, tc *TestContext) {
 	ctx := context.Background()
 	store := grub.NewStore[TestValue](tc.Provider)
@@ -483,6 +522,32 @@ func testGetBatch(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testExistsBatch(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	store := grub.NewStore[TestValue](tc.Provider)
+
+	for _, k := range []string{"exists-batch-1", "exists-batch-2"} {
+		if err := store.Set(ctx, k, &TestValue{ID: k, Name: "Exists Batch"}, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	keys := []string{"exists-batch-1", "exists-batch-2", "exists-batch-missing"}
+	result, err := store.ExistsBatch(ctx, keys)
+	if err != nil {
+		t.Fatalf("ExistsBatch failed: %v", err)
+	}
+	if len(result) != len(keys) {
+		t.Errorf("expected %d entries, got %d", len(keys), len(result))
+	}
+	if !result["exists-batch-1"] || !result["exists-batch-2"] {
+		t.Error("expected stored keys to exist")
+	}
+	if v, ok := result["exists-batch-missing"]; !ok || v {
+		t.Error("expected missing key to be reported as not existing")
+	}
+}
+
 func testSetBatch(t *testing.T, tc *TestContext) {
 	ctx := context.Background()
 	store := grub.NewStore[TestValue](tc.Provider)
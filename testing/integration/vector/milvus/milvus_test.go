@@ -6,16 +6,27 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/milvus-io/milvus-sdk-go/v2/client"
 	"github.com/milvus-io/milvus-sdk-go/v2/entity"
 	"github.com/testcontainers/testcontainers-go"
 	tcmilvus "github.com/testcontainers/testcontainers-go/modules/milvus"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/zoobzio/grub"
 	grubmilvus "github.com/zoobzio/grub/milvus"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/vector"
 )
 
-var tc *vector.TestContext
+var (
+	tc *vector.TestContext
+
+	// milvusClient is shared by the providers built per test.
+	milvusClient client.Client
+
+	// withVectors shares tc's collection but requests vectors with search hits.
+	withVectors *grubmilvus.Provider
+)
 
 const collectionName = "test_vectors"
 
@@ -38,7 +49,7 @@ func TestMain(m *testing.M) {
 		panic("failed to get connection string: " + err.Error())
 	}
 
-	milvusClient, err := client.NewClient(ctx, client.Config{
+	milvusClient, err = client.NewClient(ctx, client.Config{
 		Address: endpoint,
 	})
 	if err != nil {
@@ -56,6 +67,10 @@ func TestMain(m *testing.M) {
 		VectorField:   "embedding",
 		MetadataField: "metadata",
 	})
+	withVectors = grubmilvus.New(milvusClient, grubmilvus.Config{
+		Collection:    collectionName,
+		ReturnVectors: true,
+	})
 
 	tc = &vector.TestContext{
 		Provider: provider,
@@ -133,6 +148,10 @@ func TestMilvus_CRUD(t *testing.T) {
 	vector.RunCRUDTests(t, tc)
 }
 
+func TestMilvus_Conformance(t *testing.T) {
+	conformance.RunVectorProviderConformance(t, tc.Provider, 3)
+}
+
 func TestMilvus_Search(t *testing.T) {
 	vector.RunSearchTests(t, tc)
 }
@@ -157,6 +176,125 @@ func TestMilvus_Filter(t *testing.T) {
 	vector.RunFilterTests(t, tc, true)
 }
 
+func TestMilvus_ClientSideFilter(t *testing.T) {
+	vector.RunClientSideFilterTests(t, tc, vector.QueryOperators{
+		Range:    true,
+		Like:     true,
+		Contains: true,
+	})
+}
+
+func TestMilvus_ReturnVectors(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	vec := []float32{0.25, 0.5, 0.75}
+	if err := withVectors.Upsert(ctx, id, vec, []byte(`{"category":"vectors"}`)); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	results, err := withVectors.Search(ctx, vec, 1, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != id {
+		t.Fatalf("expected to find %s, got %v", id, results)
+	}
+	if len(results[0].Vector) != len(vec) {
+		t.Errorf("expected vector of length %d, got %v", len(vec), results[0].Vector)
+	}
+
+	results, err = tc.Provider.Search(ctx, vec, 1, nil)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) == 1 && results[0].Vector != nil {
+		t.Errorf("expected no vector by default, got %v", results[0].Vector)
+	}
+}
+
+func TestMilvus_NormalizedScores(t *testing.T) {
+	vector.RunNormalizedScoreTests(t, tc)
+}
+
 func TestMilvus_Hooks(t *testing.T) {
 	vector.RunHookTests(t, tc)
 }
+
+func TestMilvus_Cancellation(t *testing.T) {
+	vector.RunCancellationTests(t, tc, true)
+}
+
+func TestMilvus_ConsistencyModes(t *testing.T) {
+	ctx := context.Background()
+	for _, mode := range []grubmilvus.ConsistencyMode{grubmilvus.ConsistencyEventual, grubmilvus.ConsistencyManual} {
+		provider := grubmilvus.New(milvusClient, grubmilvus.Config{
+			Collection:      collectionName,
+			ConsistencyMode: mode,
+			ReadConsistency: entity.ClStrong,
+		})
+		records := make([]grub.VectorRecord, 10)
+		for i := range records {
+			records[i] = grub.VectorRecord{
+				ID:       uuid.New(),
+				Vector:   []float32{float32(mode), float32(i), 1},
+				Metadata: []byte(`{"category":"consistency"}`),
+			}
+		}
+		if err := provider.UpsertBatch(ctx, records); err != nil {
+			t.Fatalf("UpsertBatch failed: %v", err)
+		}
+		if err := provider.Flush(ctx); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		for _, rec := range records {
+			results, err := tc.Provider.Search(ctx, rec.Vector, 1, nil)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(results) != 1 || results[0].ID != rec.ID {
+				t.Errorf("mode %d: expected %s to be searchable after Flush, got %v", mode, rec.ID, results)
+			}
+		}
+		ids := make([]uuid.UUID, len(records))
+		for i, rec := range records {
+			ids[i] = rec.ID
+		}
+		if err := provider.DeleteBatch(ctx, ids); err != nil {
+			t.Fatalf("DeleteBatch failed: %v", err)
+		}
+		if err := provider.Flush(ctx); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkMilvus_Upsert compares single-record ingestion with a flush per
+// write against flushing once at the end.
+func BenchmarkMilvus_Upsert(b *testing.B) {
+	ctx := context.Background()
+	for _, bench := range []struct {
+		name string
+		mode grubmilvus.ConsistencyMode
+	}{
+		{"immediate", grubmilvus.ConsistencyImmediate},
+		{"manual", grubmilvus.ConsistencyManual},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			provider := grubmilvus.New(milvusClient, grubmilvus.Config{
+				Collection:      collectionName,
+				ConsistencyMode: bench.mode,
+			})
+			vec := []float32{0.1, 0.2, 0.3}
+			meta := []byte(`{"category":"bench"}`)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := provider.Upsert(ctx, uuid.New(), vec, meta); err != nil {
+					b.Fatalf("Upsert failed: %v", err)
+				}
+			}
+			if err := provider.Flush(ctx); err != nil {
+				b.Fatalf("Flush failed: %v", err)
+			}
+		})
+	}
+}
@@ -7,17 +7,25 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/pinecone-io/go-pinecone/v2/pinecone"
 	"github.com/testcontainers/testcontainers-go"
 	tcpinecone "github.com/testcontainers/testcontainers-go/modules/pinecone"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/zoobzio/grub"
 	grubpinecone "github.com/zoobzio/grub/pinecone"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/vector"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-var tc *vector.TestContext
+var (
+	tc *vector.TestContext
+
+	// indexConn is shared by the providers built per test.
+	indexConn *pinecone.IndexConnection
+)
 
 const indexName = "test-vectors"
 
@@ -77,14 +85,14 @@ func TestMain(m *testing.M) {
 	}
 
 	// Connect to the index using the mapped gRPC endpoint with insecure credentials
-	indexConn, err := client.Index(pinecone.NewIndexConnParams{
+	indexConn, err = client.Index(pinecone.NewIndexConnParams{
 		Host: grpcEndpoint,
 	}, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		panic("failed to connect to index: " + err.Error())
 	}
 
-	provider := grubpinecone.New(indexConn, grubpinecone.Config{})
+	provider := grubpinecone.New(indexConn, grubpinecone.Config{Metric: grub.DistanceL2})
 
 	tc = &vector.TestContext{
 		Provider: provider,
@@ -106,6 +114,10 @@ func TestPinecone_CRUD(t *testing.T) {
 	vector.RunCRUDTests(t, tc)
 }
 
+func TestPinecone_Conformance(t *testing.T) {
+	conformance.RunVectorProviderConformance(t, tc.Provider, 3)
+}
+
 func TestPinecone_Search(t *testing.T) {
 	vector.RunSearchTests(t, tc)
 }
@@ -132,6 +144,55 @@ func TestPinecone_Filter(t *testing.T) {
 	vector.RunFilterTests(t, tc, false)
 }
 
+func TestPinecone_NormalizedScores(t *testing.T) {
+	vector.RunNormalizedScoreTests(t, tc)
+}
+
 func TestPinecone_Hooks(t *testing.T) {
 	vector.RunHookTests(t, tc)
 }
+
+func TestPinecone_Cancellation(t *testing.T) {
+	vector.RunCancellationTests(t, tc, false)
+}
+
+func TestPinecone_Namespace(t *testing.T) {
+	ctx := context.Background()
+	tenant := grubpinecone.New(indexConn, grubpinecone.Config{Namespace: "tenant-a", Metric: grub.DistanceL2})
+	id := uuid.New()
+	if err := tenant.Upsert(ctx, id, []float32{0.9, 0.1, 0.4}, []byte(`{"category":"tenant"}`)); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	t.Cleanup(func() { _ = tenant.Delete(context.Background(), id) })
+
+	if exists, err := tenant.Exists(ctx, id); err != nil || !exists {
+		t.Errorf("expected the vector in its namespace, got %v, %v", exists, err)
+	}
+	if exists, err := tc.Provider.Exists(ctx, id); err != nil || exists {
+		t.Errorf("expected the default namespace not to see it, got %v, %v", exists, err)
+	}
+}
+
+func TestPinecone_ListPages(t *testing.T) {
+	ctx := context.Background()
+	lister := grubpinecone.New(indexConn, grubpinecone.Config{Namespace: "list-pages", Metric: grub.DistanceL2})
+	records := make([]grub.VectorRecord, 250)
+	ids := make([]uuid.UUID, len(records))
+	for i := range records {
+		ids[i] = uuid.New()
+		records[i] = grub.VectorRecord{ID: ids[i], Vector: []float32{float32(i), 1, 1}, Metadata: []byte(`{}`)}
+	}
+	if err := lister.UpsertBatch(ctx, records); err != nil {
+		t.Fatalf("UpsertBatch failed: %v", err)
+	}
+	t.Cleanup(func() { _ = lister.DeleteBatch(context.Background(), ids) })
+
+	all, err := lister.List(ctx, 0)
+	if err != nil || len(all) != len(records) {
+		t.Errorf("expected all %d IDs across pages, got %d, %v", len(records), len(all), err)
+	}
+	some, err := lister.List(ctx, 150)
+	if err != nil || len(some) != 150 {
+		t.Errorf("expected 150 IDs, got %d, %v", len(some), err)
+	}
+}
@@ -8,17 +8,26 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/qdrant/go-client/qdrant"
 	"github.com/testcontainers/testcontainers-go"
 	tcqdrant "github.com/testcontainers/testcontainers-go/modules/qdrant"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/zoobzio/grub"
 	grubqdrant "github.com/zoobzio/grub/qdrant"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/vector"
 )
 
-var tc *vector.TestContext
+var (
+	tc     *vector.TestContext
+	client *qdrant.Client
+)
 
-const collectionName = "test_vectors"
+const (
+	collectionName      = "test_vectors"
+	namedCollectionName = "test_named_vectors"
+)
 
 func TestMain(m *testing.M) {
 	ctx := context.Background()
@@ -48,7 +57,7 @@ func TestMain(m *testing.M) {
 		port, _ = strconv.Atoi(parts[1])
 	}
 
-	client, err := qdrant.NewClient(&qdrant.Config{
+	client, err = qdrant.NewClient(&qdrant.Config{
 		Host: host,
 		Port: port,
 	})
@@ -57,13 +66,15 @@ func TestMain(m *testing.M) {
 	}
 
 	// Create test collection
-	if err := setupCollection(ctx, client); err != nil {
-		panic("failed to setup collection: " + err.Error())
-	}
-
+	_ = client.DeleteCollection(ctx, collectionName)
 	provider := grubqdrant.New(client, grubqdrant.Config{
 		Collection: collectionName,
+		Dimension:  3, // 3-dimensional vectors for tests
+		Metric:     grub.DistanceL2,
 	})
+	if err := provider.EnsureCollection(ctx); err != nil {
+		panic("failed to setup collection: " + err.Error())
+	}
 
 	tc = &vector.TestContext{
 		Provider: provider,
@@ -80,24 +91,82 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
-func setupCollection(ctx context.Context, client *qdrant.Client) error {
-	// Delete if exists
-	_ = client.DeleteCollection(ctx, collectionName)
+func setupNamedCollection(ctx context.Context) error {
+	_ = client.DeleteCollection(ctx, namedCollectionName)
 
-	// Create collection with vector config
+	params := &qdrant.VectorParams{Size: 3, Distance: qdrant.Distance_Euclid}
 	return client.CreateCollection(ctx, &qdrant.CreateCollection{
-		CollectionName: collectionName,
-		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
-			Size:     3, // 3-dimensional vectors for tests
-			Distance: qdrant.Distance_Euclid,
+		CollectionName: namedCollectionName,
+		VectorsConfig: qdrant.NewVectorsConfigMap(map[string]*qdrant.VectorParams{
+			"title": params,
+			"body":  params,
 		}),
 	})
 }
 
+func TestQdrant_EnsureCollection(t *testing.T) {
+	ctx := context.Background()
+	const name = "test_vectors_ensured"
+	_ = client.DeleteCollection(ctx, name)
+	provider := grubqdrant.New(client, grubqdrant.Config{Collection: name, VectorName: "title", Dimension: 3})
+	if err := provider.EnsureCollection(ctx); err != nil {
+		t.Fatalf("EnsureCollection failed: %v", err)
+	}
+	if err := provider.EnsureCollection(ctx); err != nil {
+		t.Fatalf("expected EnsureCollection to keep an existing collection, got %v", err)
+	}
+	t.Run("CRUD", func(t *testing.T) { vector.RunCRUDTests(t, &vector.TestContext{Provider: provider}) })
+}
+
+func TestQdrant_NamedVectors(t *testing.T) {
+	ctx := context.Background()
+	if err := setupNamedCollection(ctx); err != nil {
+		t.Fatalf("failed to setup named collection: %v", err)
+	}
+	title := grubqdrant.New(client, grubqdrant.Config{Collection: namedCollectionName, VectorName: "title"})
+	body := grubqdrant.New(client, grubqdrant.Config{Collection: namedCollectionName, VectorName: "body"})
+
+	named := &vector.TestContext{Provider: title}
+	t.Run("CRUD", func(t *testing.T) { vector.RunCRUDTests(t, named) })
+	t.Run("Search", func(t *testing.T) { vector.RunSearchTests(t, named) })
+
+	t.Run("Independent", func(t *testing.T) {
+		titles := grub.NewIndex[vector.TestMetadata](title)
+		bodies := grub.NewIndex[vector.TestMetadata](body)
+		id := uuid.New()
+		meta := &vector.TestMetadata{Category: "named"}
+		if err := titles.Upsert(ctx, id, []float32{1, 0, 0}, meta); err != nil {
+			t.Fatalf("title Upsert failed: %v", err)
+		}
+		if err := bodies.Upsert(ctx, id, []float32{0, 0, 1}, meta); err != nil {
+			t.Fatalf("body Upsert failed: %v", err)
+		}
+
+		got, err := titles.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("title Get failed: %v", err)
+		}
+		if got.Vector[0] != 1 {
+			t.Errorf("expected title vector kept after body upsert, got %v", got.Vector)
+		}
+		results, err := bodies.Search(ctx, []float32{0, 0, 1}, 1, nil)
+		if err != nil {
+			t.Fatalf("body Search failed: %v", err)
+		}
+		if len(results) != 1 || results[0].ID != id {
+			t.Errorf("expected body search to find %s, got %v", id, results)
+		}
+	})
+}
+
 func TestQdrant_CRUD(t *testing.T) {
 	vector.RunCRUDTests(t, tc)
 }
 
+func TestQdrant_Conformance(t *testing.T) {
+	conformance.RunVectorProviderConformance(t, tc.Provider, 3)
+}
+
 func TestQdrant_Search(t *testing.T) {
 	vector.RunSearchTests(t, tc)
 }
@@ -122,6 +191,22 @@ func TestQdrant_Filter(t *testing.T) {
 	vector.RunFilterTests(t, tc, true)
 }
 
+func TestQdrant_ClientSideFilter(t *testing.T) {
+	vector.RunClientSideFilterTests(t, tc, vector.QueryOperators{
+		Range:    true,
+		Like:     false,
+		Contains: true,
+	})
+}
+
+func TestQdrant_NormalizedScores(t *testing.T) {
+	vector.RunNormalizedScoreTests(t, tc)
+}
+
 func TestQdrant_Hooks(t *testing.T) {
 	vector.RunHookTests(t, tc)
 }
+
+func TestQdrant_Cancellation(t *testing.T) {
+	vector.RunCancellationTests(t, tc, true)
+}
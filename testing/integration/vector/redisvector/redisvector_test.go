@@ -0,0 +1,159 @@
+package redisvector
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/zoobzio/grub"
+	grubredisvector "github.com/zoobzio/grub/redisvector"
+	"github.com/zoobzio/grub/testing/conformance"
+	"github.com/zoobzio/grub/testing/integration/vector"
+)
+
+var (
+	tc     *vector.TestContext
+	client *redis.Client
+)
+
+// fields indexes the properties of vector.TestMetadata and
+// vector.HookedMetadata that the shared suites filter on.
+var fields = []grubredisvector.Field{
+	{Name: "category", Type: grubredisvector.FieldTag},
+	{Name: "tags", Type: grubredisvector.FieldTag},
+	{Name: "score", Type: grubredisvector.FieldNumeric},
+}
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	redisContainer, err := tcredis.Run(ctx,
+		"redis/redis-stack-server:7.4.0-v1",
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("Ready to accept connections").
+				WithStartupTimeout(60*time.Second),
+		),
+	)
+	if err != nil {
+		panic("failed to start redis container: " + err.Error())
+	}
+
+	connStr, err := redisContainer.ConnectionString(ctx)
+	if err != nil {
+		panic("failed to get connection string: " + err.Error())
+	}
+
+	opts, err := redis.ParseURL(connStr)
+	if err != nil {
+		panic("failed to parse redis URL: " + err.Error())
+	}
+	opts.Protocol = 2
+	client = redis.NewClient(opts)
+
+	provider := grubredisvector.New(client, grubredisvector.Config{
+		Index:     "test_vectors",
+		Dimension: 3,
+		Metric:    grub.DistanceL2,
+		Fields:    fields,
+	})
+	if err := provider.EnsureCollection(ctx); err != nil {
+		panic("failed to create index: " + err.Error())
+	}
+
+	tc = &vector.TestContext{
+		Provider: provider,
+		Cleanup: func() {
+			_ = client.Close()
+			_ = redisContainer.Terminate(ctx)
+		},
+	}
+
+	code := m.Run()
+
+	tc.Cleanup()
+
+	os.Exit(code)
+}
+
+func TestRedisVector_CRUD(t *testing.T) {
+	vector.RunCRUDTests(t, tc)
+}
+
+func TestRedisVector_Conformance(t *testing.T) {
+	conformance.RunVectorProviderConformance(t, tc.Provider, 3)
+}
+
+func TestRedisVector_Search(t *testing.T) {
+	vector.RunSearchTests(t, tc)
+}
+
+func TestRedisVector_Batch(t *testing.T) {
+	vector.RunBatchTests(t, tc)
+}
+
+func TestRedisVector_Atomic(t *testing.T) {
+	vector.RunAtomicTests(t, tc)
+}
+
+func TestRedisVector_Query(t *testing.T) {
+	vector.RunQueryTests(t, tc, vector.QueryOperators{
+		Range:    true,
+		Like:     false,
+		Contains: true,
+	})
+}
+
+func TestRedisVector_Filter(t *testing.T) {
+	vector.RunFilterTests(t, tc, true)
+}
+
+func TestRedisVector_ClientSideFilter(t *testing.T) {
+	vector.RunClientSideFilterTests(t, tc, vector.QueryOperators{
+		Range:    true,
+		Like:     false,
+		Contains: true,
+	})
+}
+
+func TestRedisVector_NormalizedScores(t *testing.T) {
+	vector.RunNormalizedScoreTests(t, tc)
+}
+
+func TestRedisVector_Hooks(t *testing.T) {
+	vector.RunHookTests(t, tc)
+}
+
+func TestRedisVector_Cancellation(t *testing.T) {
+	vector.RunCancellationTests(t, tc, true)
+}
+
+func TestRedisVector_JSON(t *testing.T) {
+	ctx := context.Background()
+	provider := grubredisvector.New(client, grubredisvector.Config{
+		Index:     "test_vectors_json",
+		Storage:   grubredisvector.StorageJSON,
+		Dimension: 3,
+		Metric:    grub.DistanceCosine,
+		Algorithm: grubredisvector.AlgorithmFlat,
+		Fields:    fields,
+	})
+	if err := provider.EnsureCollection(ctx); err != nil {
+		t.Fatalf("EnsureCollection failed: %v", err)
+	}
+	if err := provider.EnsureCollection(ctx); err != nil {
+		t.Fatalf("expected EnsureCollection to keep an existing index, got %v", err)
+	}
+	jsonTC := &vector.TestContext{Provider: provider}
+
+	t.Run("CRUD", func(t *testing.T) { vector.RunCRUDTests(t, jsonTC) })
+	t.Run("Search", func(t *testing.T) { vector.RunSearchTests(t, jsonTC) })
+	t.Run("Query", func(t *testing.T) {
+		vector.RunQueryTests(t, jsonTC, vector.QueryOperators{Range: true, Contains: true})
+	})
+	t.Run("Filter", func(t *testing.T) { vector.RunFilterTests(t, jsonTC, true) })
+}
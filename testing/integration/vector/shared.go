@@ -53,12 +53,16 @@ func RunSearchTests(t *testing.T, tc *TestContext) {
 	t.Run("SearchWithFilter", func(t *testing.T) { testSearchWithFilter(t, tc) })
 	t.Run("ScoreOrdering", func(t *testing.T) { testScoreOrdering(t, tc) })
 	t.Run("ExactMatch", func(t *testing.T) { testExactMatch(t, tc) })
+	t.Run("SearchPage", func(t *testing.T) { testSearchPage(t, tc) })
+	t.Run("Recommend", func(t *testing.T) { testRecommend(t, tc) })
+	t.Run("SearchBatch", func(t *testing.T) { testSearchBatch(t, tc) })
 }
 
 // RunBatchTests runs the batch operation test suite.
 func RunBatchTests(t *testing.T, tc *TestContext) {
 	t.Run("UpsertBatch", func(t *testing.T) { testUpsertBatch(t, tc) })
 	t.Run("DeleteBatch", func(t *testing.T) { testDeleteBatch(t, tc) })
+	t.Run("ExistsBatch", func(t *testing.T) { testExistsBatch(t, tc) })
 	t.Run("List", func(t *testing.T) { testList(t, tc) })
 	t.Run("ListWithLimit", func(t *testing.T) { testListWithLimit(t, tc) })
 }
@@ -113,6 +117,20 @@ func RunQueryTests(t *testing.T, tc *TestContext, supportedOps QueryOperators) {
 	}
 }
 
+// RunCancellationTests verifies that reads made with a cancelled context
+// report context.Canceled rather than a transport error. Pass
+// supportsFilter=false for providers whose Filter returns
+// ErrFilterNotSupported.
+func RunCancellationTests(t *testing.T, tc *TestContext, supportsFilter bool) {
+	t.Run("CancelledGet", func(t *testing.T) { testCancelledGet(t, tc) })
+	t.Run("CancelledSearch", func(t *testing.T) { testCancelledSearch(t, tc) })
+	t.Run("CancelledQuery", func(t *testing.T) { testCancelledQuery(t, tc) })
+	t.Run("CancelledList", func(t *testing.T) { testCancelledList(t, tc) })
+	if supportsFilter {
+		t.Run("CancelledFilter", func(t *testing.T) { testCancelledFilter(t, tc) })
+	}
+}
+
 // QueryOperators indicates which operators a provider supports.
 type QueryOperators struct {
 	Range    bool // Gt, Gte, Lt, Lte
@@ -454,6 +472,143 @@ func testSearchWithLimit(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testSearchPage(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	index := grub.NewIndex[TestMetadata](tc.Provider)
+
+	// Ten vectors at increasing distance from query [1,0,0], isolated by category.
+	uniqueCategory := testID().String()
+	ids := make([]uuid.UUID, 10)
+	for i := range ids {
+		ids[i] = testID()
+		vec := []float32{1.0 - float32(i)*0.1, float32(i) * 0.1, 0.0}
+		if err := index.Upsert(ctx, ids[i], vec, &TestMetadata{Category: uniqueCategory, Score: float64(i)}); err != nil {
+			t.Fatalf("Upsert %s failed: %v", ids[i], err)
+		}
+	}
+
+	query := []float32{1.0, 0.0, 0.0}
+	filter := &TestMetadata{Category: uniqueCategory}
+	var got []*grub.Vector[TestMetadata]
+	for page, offset := 0, 0; page < 3; page, offset = page+1, offset+4 {
+		results, err := index.SearchPage(ctx, query, 4, offset, filter)
+		if err != nil {
+			t.Fatalf("SearchPage(offset=%d) failed: %v", offset, err)
+		}
+		if want := min(4, len(ids)-offset); len(results) != want {
+			t.Fatalf("page %d: expected %d results, got %d", page, want, len(results))
+		}
+		got = append(got, results...)
+	}
+
+	for i, r := range got {
+		if r.ID != ids[i] {
+			t.Errorf("result %d across pages: expected %s, got %s", i, ids[i], r.ID)
+		}
+		if i > 0 && r.Score < got[i-1].Score {
+			t.Errorf("results not ordered across pages: result[%d].Score=%f < result[%d].Score=%f",
+				i, r.Score, i-1, got[i-1].Score)
+		}
+	}
+
+	results, err := index.SearchPage(ctx, query, 4, 20, filter)
+	if err != nil {
+		t.Fatalf("SearchPage past the end failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results past the end, got %d", len(results))
+	}
+}
+
+func testRecommend(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	index := grub.NewIndex[TestMetadata](tc.Provider)
+
+	// Five vectors fanning away from [1,0,0], isolated by category.
+	uniqueCategory := testID().String()
+	ids := make([]uuid.UUID, 5)
+	for i := range ids {
+		ids[i] = testID()
+		vec := []float32{1.0 - float32(i)*0.2, float32(i) * 0.2, 0.0}
+		if err := index.Upsert(ctx, ids[i], vec, &TestMetadata{Category: uniqueCategory, Score: float64(i)}); err != nil {
+			t.Fatalf("Upsert %s failed: %v", ids[i], err)
+		}
+	}
+	filter := mustQueryBuilder(t).Where("category").Eq(uniqueCategory)
+
+	results, err := index.Recommend(ctx, ids[0], 3, filter)
+	if err != nil {
+		t.Fatalf("Recommend failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if r.ID == ids[0] {
+			t.Fatalf("seed returned in its own results at position %d", i)
+		}
+		if r.ID != ids[i+1] {
+			t.Errorf("result %d: expected %s, got %s", i, ids[i+1], r.ID)
+		}
+	}
+
+	results, err = index.RecommendMulti(ctx, []uuid.UUID{ids[1], ids[3]}, 3, filter)
+	if err != nil {
+		t.Fatalf("RecommendMulti failed: %v", err)
+	}
+	for _, r := range results {
+		if r.ID == ids[1] || r.ID == ids[3] {
+			t.Errorf("seed %s returned in its own results", r.ID)
+		}
+	}
+	if len(results) == 0 || results[0].ID != ids[2] {
+		t.Errorf("expected the vector between the seeds first, got %v", results)
+	}
+
+	if _, err := index.Recommend(ctx, testID(), 3, nil); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing seed, got %v", err)
+	}
+}
+
+func testSearchBatch(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	index := grub.NewIndex[TestMetadata](tc.Provider)
+
+	// One vector per axis, isolated by category.
+	uniqueCategory := testID().String()
+	axes := [][]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	ids := make([]uuid.UUID, len(axes))
+	for i, vec := range axes {
+		ids[i] = testID()
+		if err := index.Upsert(ctx, ids[i], vec, &TestMetadata{Category: uniqueCategory, Score: float64(i)}); err != nil {
+			t.Fatalf("Upsert %s failed: %v", ids[i], err)
+		}
+	}
+	filter := mustQueryBuilder(t).Where("category").Eq(uniqueCategory)
+
+	// Queries in a different order from the upserts; results must follow the queries.
+	queries := [][]float32{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}}
+	nearest := []uuid.UUID{ids[2], ids[0], ids[1]}
+	batches, err := index.SearchBatch(ctx, queries, 2, filter)
+	if err != nil {
+		t.Fatalf("SearchBatch failed: %v", err)
+	}
+	if len(batches) != len(queries) {
+		t.Fatalf("expected %d result sets, got %d", len(queries), len(batches))
+	}
+	for i, results := range batches {
+		if len(results) != 2 {
+			t.Fatalf("query %d: expected 2 results, got %d", i, len(results))
+		}
+		if results[0].ID != nearest[i] {
+			t.Errorf("query %d: expected nearest %s, got %s", i, nearest[i], results[0].ID)
+		}
+		if results[0].Metadata.Category != uniqueCategory {
+			t.Errorf("query %d: expected metadata category %q, got %q", i, uniqueCategory, results[0].Metadata.Category)
+		}
+	}
+}
+
 func testSearchWithFilter(t *testing.T, tc *TestContext) {
 	ctx := context.Background()
 	index := grub.NewIndex[TestMetadata](tc.Provider)
@@ -559,6 +714,36 @@ func testDeleteBatch(t *testing.T, tc *TestContext) {
 	}
 }
 
+func testExistsBatch(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	index := grub.NewIndex[TestMetadata](tc.Provider)
+
+	present := []uuid.UUID{testID(), testID()}
+	for _, id := range present {
+		err := index.Upsert(ctx, id, []float32{0.0, 1.0, 0.0}, &TestMetadata{Category: "exists-batch"})
+		if err != nil {
+			t.Fatalf("Upsert %s failed: %v", id, err)
+		}
+	}
+	absent := testID()
+
+	result, err := index.ExistsBatch(ctx, append(present, absent))
+	if err != nil {
+		t.Fatalf("ExistsBatch failed: %v", err)
+	}
+	if len(result) != 3 {
+		t.Errorf("expected 3 entries, got %d", len(result))
+	}
+	for _, id := range present {
+		if !result[id] {
+			t.Errorf("expected %s to exist", id)
+		}
+	}
+	if v, ok := result[absent]; !ok || v {
+		t.Errorf("expected %s to be reported as not existing", absent)
+	}
+}
+
 func testList(t *testing.T, tc *TestContext) {
 	ctx := context.Background()
 	index := grub.NewIndex[TestMetadata](tc.Provider)
@@ -1283,3 +1468,184 @@ func testFilterNoMatches(t *testing.T, tc *TestContext) {
 		t.Errorf("expected 0 results for non-existent category, got %d", len(results))
 	}
 }
+
+// --- Client-Side Filter Tests ---
+
+// filterlessProvider hides a provider's filtering, rejecting Filter and any
+// filtered Query the way Pinecone does.
+type filterlessProvider struct {
+	grub.VectorProvider
+}
+
+func (p filterlessProvider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	if filter != nil {
+		return nil, grub.ErrOperatorNotSupported
+	}
+	return p.VectorProvider.Query(ctx, vector, k, nil)
+}
+
+func (filterlessProvider) Filter(context.Context, *vecna.Filter, int) ([]grub.VectorResult, error) {
+	return nil, grub.ErrFilterNotSupported
+}
+
+// RunClientSideFilterTests checks that WithClientSideFilter returns the same
+// vectors as the provider's own server-side Filter on the same dataset.
+// Only operators in supportedOps are compared.
+func RunClientSideFilterTests(t *testing.T, tc *TestContext, supportedOps QueryOperators) {
+	ctx := context.Background()
+	server := grub.NewIndex[TestMetadata](tc.Provider)
+	client := grub.NewIndex[TestMetadata](filterlessProvider{tc.Provider}, grub.WithClientSideFilter())
+	b := mustQueryBuilder(t)
+
+	uniqueCategory := setupQueryTestData(t, tc)
+	scoped := func(f *vecna.Filter) *vecna.Filter {
+		return b.And(b.Where("category").Eq(uniqueCategory), f)
+	}
+	filters := map[string]*vecna.Filter{
+		"Eq":  b.Where("category").Eq(uniqueCategory),
+		"Ne":  b.And(b.Where("category").Ne("other"), b.Where("category").Ne(uniqueCategory)),
+		"In":  scoped(b.Where("score").In(10.0, 25.0)),
+		"Nin": scoped(b.Where("score").Nin(10.0, 25.0)),
+		"Or":  scoped(b.Or(b.Where("score").Eq(10.0), b.Where("score").Eq(20.0))),
+		"Not": scoped(b.Not(b.Where("score").Eq(15.0))),
+	}
+	if supportedOps.Range {
+		filters["Range"] = scoped(b.And(b.Where("score").Gt(10.0), b.Where("score").Lte(20.0)))
+	}
+	if supportedOps.Like {
+		filters["Like"] = b.Where("category").Like(uniqueCategory[:len(uniqueCategory)-5] + "%")
+	}
+	if supportedOps.Contains {
+		filters["Contains"] = scoped(b.Where("tags").Contains("alpha"))
+	}
+
+	for name, filter := range filters {
+		t.Run(name, func(t *testing.T) {
+			want, err := server.Filter(ctx, filter, 0)
+			if err != nil {
+				t.Fatalf("server-side Filter failed: %v", err)
+			}
+			got, err := client.Filter(ctx, filter, 0)
+			if err != nil {
+				t.Fatalf("client-side Filter failed: %v", err)
+			}
+			wantIDs := make(map[uuid.UUID]bool, len(want))
+			for _, r := range want {
+				wantIDs[r.ID] = true
+			}
+			if len(got) != len(want) {
+				t.Fatalf("expected %d results as server-side, got %d", len(want), len(got))
+			}
+			for _, r := range got {
+				if !wantIDs[r.ID] {
+					t.Errorf("client-side result %s not returned server-side", r.ID)
+				}
+			}
+		})
+	}
+}
+
+// RunNormalizedScoreTests checks that WithNormalizedScores turns the
+// provider's scores into the canonical distance for its metric, so every
+// provider ranks the same dataset in the same order with the same scores.
+func RunNormalizedScoreTests(t *testing.T, tc *TestContext) {
+	ctx := context.Background()
+	index := grub.NewIndex[TestMetadata](tc.Provider, grub.WithNormalizedScores())
+	kind := index.ScoreKind()
+	if kind.Measure != grub.ScoreDistance {
+		t.Fatalf("expected normalized scores to be distances, got %+v", kind)
+	}
+
+	uniqueCategory := testID().String()
+	query := []float32{1.0, 0.0, 0.0}
+	vectors := [][]float32{
+		{0.99, 0.01, 0.0},
+		{0.5, 0.5, 0.0},
+		{0.0, 1.0, 0.0},
+	}
+	ids := make([]uuid.UUID, len(vectors))
+	for n, v := range vectors {
+		ids[n] = testID()
+		if err := index.Upsert(ctx, ids[n], v, &TestMetadata{Category: uniqueCategory}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+	}
+
+	results, err := index.Search(ctx, query, 10, &TestMetadata{Category: uniqueCategory})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != len(vectors) {
+		t.Fatalf("expected %d results, got %d", len(vectors), len(results))
+	}
+	for n, r := range results {
+		if r.ID != ids[n] {
+			t.Errorf("result %d: expected %s, got %s", n, ids[n], r.ID)
+			continue
+		}
+		var want float32
+		switch kind.Metric {
+		case grub.DistanceL2:
+			want = L2Distance(query, vectors[n])
+		case grub.DistanceCosine:
+			want = 1 - CosineSimilarity(query, vectors[n])
+		case grub.DistanceInnerProduct:
+			for i := range query {
+				want -= query[i] * vectors[n][i]
+			}
+		}
+		if math.Abs(float64(r.Score-want)) > 1e-3 {
+			t.Errorf("result %d: expected %s distance %f, got %f", n, kind.Metric, want, r.Score)
+		}
+	}
+}
+
+// cancelledIndex seeds a few vectors and returns an index over tc with a
+// context that is already cancelled.
+func cancelledIndex(t *testing.T, tc *TestContext) (context.Context, *grub.Index[TestMetadata]) {
+	t.Helper()
+	index := grub.NewIndex[TestMetadata](tc.Provider)
+	for i := 0; i < 3; i++ {
+		if err := index.Upsert(context.Background(), testID(), []float32{float32(i) * 0.1, 0.5, 0.5}, &TestMetadata{Category: "cancel"}); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx, index
+}
+
+func testCancelledGet(t *testing.T, tc *TestContext) {
+	ctx, index := cancelledIndex(t, tc)
+	if _, err := index.Get(ctx, testID()); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func testCancelledSearch(t *testing.T, tc *TestContext) {
+	ctx, index := cancelledIndex(t, tc)
+	if _, err := index.Search(ctx, []float32{0.1, 0.5, 0.5}, 3, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func testCancelledQuery(t *testing.T, tc *TestContext) {
+	ctx, index := cancelledIndex(t, tc)
+	if _, err := index.Query(ctx, []float32{0.1, 0.5, 0.5}, 3, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func testCancelledList(t *testing.T, tc *TestContext) {
+	ctx, index := cancelledIndex(t, tc)
+	if _, err := index.List(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func testCancelledFilter(t *testing.T, tc *TestContext) {
+	ctx, index := cancelledIndex(t, tc)
+	if _, err := index.Filter(ctx, nil, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package sqlitevector
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/zoobzio/grub"
+	grubsqlitevector "github.com/zoobzio/grub/sqlitevector"
+	"github.com/zoobzio/grub/testing/conformance"
+	"github.com/zoobzio/grub/testing/integration/vector"
+)
+
+var tc *vector.TestContext
+
+// allOperators enables every optional operator: the provider evaluates
+// filters with the same semantics as grub.MatchFilter.
+var allOperators = vector.QueryOperators{
+	Range:    true,
+	Like:     true,
+	Contains: true,
+}
+
+func TestMain(m *testing.M) {
+	provider, err := grubsqlitevector.Open(context.Background(), grubsqlitevector.Config{
+		Dimension: 3,
+		Metric:    grub.DistanceL2,
+	})
+	if err != nil {
+		panic("failed to open sqlite: " + err.Error())
+	}
+
+	tc = &vector.TestContext{
+		Provider: provider,
+		Cleanup: func() {
+			_ = provider.Close()
+		},
+	}
+
+	code := m.Run()
+
+	tc.Cleanup()
+
+	os.Exit(code)
+}
+
+func TestSQLiteVector_CRUD(t *testing.T) {
+	vector.RunCRUDTests(t, tc)
+}
+
+func TestSQLiteVector_Conformance(t *testing.T) {
+	conformance.RunVectorProviderConformance(t, tc.Provider, 3)
+}
+
+func TestSQLiteVector_Search(t *testing.T) {
+	vector.RunSearchTests(t, tc)
+}
+
+func TestSQLiteVector_Batch(t *testing.T) {
+	vector.RunBatchTests(t, tc)
+}
+
+func TestSQLiteVector_Atomic(t *testing.T) {
+	vector.RunAtomicTests(t, tc)
+}
+
+func TestSQLiteVector_Query(t *testing.T) {
+	vector.RunQueryTests(t, tc, allOperators)
+}
+
+func TestSQLiteVector_Filter(t *testing.T) {
+	vector.RunFilterTests(t, tc, true)
+}
+
+func TestSQLiteVector_ClientSideFilter(t *testing.T) {
+	vector.RunClientSideFilterTests(t, tc, allOperators)
+}
+
+func TestSQLiteVector_NormalizedScores(t *testing.T) {
+	vector.RunNormalizedScoreTests(t, tc)
+}
+
+func TestSQLiteVector_Hooks(t *testing.T) {
+	vector.RunHookTests(t, tc)
+}
+
+func TestSQLiteVector_Cancellation(t *testing.T) {
+	vector.RunCancellationTests(t, tc, true)
+}
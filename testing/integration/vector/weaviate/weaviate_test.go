@@ -12,7 +12,9 @@ import (
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/grpc"
 	"github.com/weaviate/weaviate/entities/models"
+	"github.com/zoobzio/grub"
 	grubweaviate "github.com/zoobzio/grub/weaviate"
+	"github.com/zoobzio/grub/testing/conformance"
 	"github.com/zoobzio/grub/testing/integration/vector"
 )
 
@@ -66,6 +68,7 @@ func TestMain(m *testing.M) {
 	provider := grubweaviate.New(client, grubweaviate.Config{
 		Class:      className,
 		Properties: []string{"category", "score", "tags"},
+		Metric:     grub.DistanceL2,
 	})
 
 	tc = &vector.TestContext{
@@ -124,6 +127,10 @@ func TestWeaviate_CRUD(t *testing.T) {
 	vector.RunCRUDTests(t, tc)
 }
 
+func TestWeaviate_Conformance(t *testing.T) {
+	conformance.RunVectorProviderConformance(t, tc.Provider, 3)
+}
+
 func TestWeaviate_Search(t *testing.T) {
 	vector.RunSearchTests(t, tc)
 }
@@ -148,6 +155,22 @@ func TestWeaviate_Filter(t *testing.T) {
 	vector.RunFilterTests(t, tc, true)
 }
 
+func TestWeaviate_ClientSideFilter(t *testing.T) {
+	vector.RunClientSideFilterTests(t, tc, vector.QueryOperators{
+		Range:    true,
+		Like:     true,
+		Contains: true,
+	})
+}
+
+func TestWeaviate_NormalizedScores(t *testing.T) {
+	vector.RunNormalizedScoreTests(t, tc)
+}
+
 func TestWeaviate_Hooks(t *testing.T) {
 	vector.RunHookTests(t, tc)
 }
+
+func TestWeaviate_Cancellation(t *testing.T) {
+	vector.RunCancellationTests(t, tc, true)
+}
@@ -0,0 +1,127 @@
+package grub
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+var timePtrType = reflect.TypeOf((*time.Time)(nil))
+
+// timestamps maintains the created and updated time fields of T on save.
+// A nil *timestamps is valid and does nothing.
+type timestamps struct {
+	now     func() time.Time
+	created []int  // field index of the created time, nil if absent
+	updated []int  // field index of the updated time, nil if absent
+	column  string // db column of the updated time, "" if absent or unmapped
+}
+
+// newTimestamps locates T's timestamp fields. A field tagged
+// grub:"created_at" or grub:"updated_at" takes precedence over one named
+// CreatedAt or UpdatedAt; either must be a time.Time or *time.Time. Returns
+// nil when T has neither field or timestamps are disabled.
+func newTimestamps[T any](o options) *timestamps {
+	if o.noTimestamps {
+		return nil
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	ts := &timestamps{
+		now:     o.now,
+		created: timestampField(t, "created_at", "CreatedAt"),
+		updated: timestampField(t, "updated_at", "UpdatedAt"),
+	}
+	if ts.created == nil && ts.updated == nil {
+		return nil
+	}
+	if ts.now == nil {
+		ts.now = time.Now
+	}
+	if ts.updated != nil {
+		if col := t.FieldByIndex(ts.updated).Tag.Get("db"); col != "-" {
+			ts.column = col
+		}
+	}
+	return ts
+}
+
+// updatedColumn returns the db column of the updated time and the time to
+// store in it, for writes such as Patch that set columns rather than save a
+// record. col is "" when there is none.
+func (ts *timestamps) updatedColumn() (col string, now time.Time) {
+	if ts == nil || ts.column == "" {
+		return "", time.Time{}
+	}
+	return ts.column, ts.now()
+}
+
+// timestampField returns the index of the field tagged grub:"tag", falling
+// back to the field called name.
+func timestampField(t reflect.Type, tag, name string) []int {
+	var byName []int
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || (f.Type != timeType && f.Type != timePtrType) || throughPointer(t, f.Index) {
+			continue
+		}
+		if shared.HasGrubTag(f, tag) {
+			return f.Index
+		}
+		if f.Name == name && byName == nil {
+			byName = f.Index
+		}
+	}
+	return byName
+}
+
+// throughPointer reports whether reaching the field at index passes through
+// an embedded pointer, which may be nil.
+func throughPointer(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		t = t.Field(i).Type
+		if t.Kind() == reflect.Pointer {
+			return true
+		}
+	}
+	return false
+}
+
+// stamp sets the updated field of value to now, and the created field too
+// when it is zero or nil.
+func (ts *timestamps) stamp(value any) {
+	if ts == nil {
+		return
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return
+	}
+	v = v.Elem()
+	now := ts.now()
+	if ts.updated != nil {
+		setTime(v.FieldByIndex(ts.updated), now)
+	}
+	if ts.created != nil {
+		if f := v.FieldByIndex(ts.created); timeIsZero(f) {
+			setTime(f, now)
+		}
+	}
+}
+
+func timeIsZero(f reflect.Value) bool {
+	if f.Kind() == reflect.Pointer {
+		return f.IsNil() || f.Elem().Interface().(time.Time).IsZero()
+	}
+	return f.Interface().(time.Time).IsZero()
+}
+
+func setTime(f reflect.Value, now time.Time) {
+	if f.Kind() == reflect.Pointer {
+		f.Set(reflect.ValueOf(&now))
+		return
+	}
+	f.Set(reflect.ValueOf(now))
+}
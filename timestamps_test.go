@@ -0,0 +1,300 @@
+package grub
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+var (
+	clockT0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clockT1 = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// fixedClock returns a clock reading *now, so tests can advance it.
+func fixedClock(now *time.Time) Option {
+	return WithClock(func() time.Time { return *now })
+}
+
+type stampedRecord struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type stampedPtrRecord struct {
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type taggedStampRecord struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"` // ignored in favour of the tag
+	Born      time.Time `json:"born" grub:"created_at"`
+	Touched   time.Time `json:"touched" grub:"updated_at"`
+}
+
+// hookedStampRecord overrides UpdatedAt in BeforeSave.
+type hookedStampRecord struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (h *hookedStampRecord) BeforeSave(_ context.Context) error {
+	if h.CreatedAt.IsZero() {
+		panic("BeforeSave ran before timestamps were set")
+	}
+	h.UpdatedAt = h.UpdatedAt.Add(time.Hour)
+	return nil
+}
+
+func TestTimestamps_Store(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create then update", func(t *testing.T) {
+		now := clockT0
+		store := NewStore[stampedRecord](newMockStoreProvider(), fixedClock(&now))
+
+		rec := &stampedRecord{Name: "a"}
+		if err := store.Set(ctx, "k", rec, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if !rec.CreatedAt.Equal(clockT0) || !rec.UpdatedAt.Equal(clockT0) {
+			t.Errorf("after create: got %v / %v", rec.CreatedAt, rec.UpdatedAt)
+		}
+
+		now = clockT1
+		if err := store.Set(ctx, "k", rec, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if !rec.CreatedAt.Equal(clockT0) {
+			t.Errorf("CreatedAt overwritten: got %v", rec.CreatedAt)
+		}
+		if !rec.UpdatedAt.Equal(clockT1) {
+			t.Errorf("UpdatedAt not advanced: got %v", rec.UpdatedAt)
+		}
+
+		stored, err := store.Get(ctx, "k")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if !stored.CreatedAt.Equal(clockT0) || !stored.UpdatedAt.Equal(clockT1) {
+			t.Errorf("stored: got %v / %v", stored.CreatedAt, stored.UpdatedAt)
+		}
+	})
+
+	t.Run("pointer fields", func(t *testing.T) {
+		now := clockT0
+		store := NewStore[stampedPtrRecord](newMockStoreProvider(), fixedClock(&now))
+
+		rec := &stampedPtrRecord{}
+		if err := store.Set(ctx, "k", rec, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if rec.CreatedAt == nil || !rec.CreatedAt.Equal(clockT0) || rec.UpdatedAt == nil || !rec.UpdatedAt.Equal(clockT0) {
+			t.Fatalf("after create: got %v / %v", rec.CreatedAt, rec.UpdatedAt)
+		}
+		if rec.CreatedAt == rec.UpdatedAt {
+			t.Error("CreatedAt and UpdatedAt share a pointer")
+		}
+
+		now = clockT1
+		if err := store.Set(ctx, "k", rec, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if !rec.CreatedAt.Equal(clockT0) || !rec.UpdatedAt.Equal(clockT1) {
+			t.Errorf("after update: got %v / %v", *rec.CreatedAt, *rec.UpdatedAt)
+		}
+
+		zero := time.Time{}
+		rec = &stampedPtrRecord{CreatedAt: &zero}
+		if err := store.Set(ctx, "k2", rec, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if !rec.CreatedAt.Equal(clockT1) {
+			t.Errorf("pointer to zero time not filled: got %v", *rec.CreatedAt)
+		}
+	})
+
+	t.Run("tags take precedence", func(t *testing.T) {
+		now := clockT0
+		store := NewStore[taggedStampRecord](newMockStoreProvider(), fixedClock(&now))
+
+		rec := &taggedStampRecord{}
+		if err := store.Set(ctx, "k", rec, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if !rec.Born.Equal(clockT0) || !rec.Touched.Equal(clockT0) {
+			t.Errorf("tagged fields: got %v / %v", rec.Born, rec.Touched)
+		}
+		if !rec.CreatedAt.IsZero() {
+			t.Errorf("untagged CreatedAt set: got %v", rec.CreatedAt)
+		}
+	})
+
+	t.Run("BeforeSave runs after", func(t *testing.T) {
+		now := clockT0
+		store := NewStore[hookedStampRecord](newMockStoreProvider(), fixedClock(&now))
+
+		rec := &hookedStampRecord{}
+		if err := store.Set(ctx, "k", rec, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if !rec.UpdatedAt.Equal(clockT0.Add(time.Hour)) {
+			t.Errorf("BeforeSave override lost: got %v", rec.UpdatedAt)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		store := NewStore[stampedRecord](newMockStoreProvider(), WithoutTimestamps())
+
+		rec := &stampedRecord{}
+		if err := store.Set(ctx, "k", rec, 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if !rec.CreatedAt.IsZero() || !rec.UpdatedAt.IsZero() {
+			t.Errorf("timestamps set despite opt-out: %v / %v", rec.CreatedAt, rec.UpdatedAt)
+		}
+	})
+
+	t.Run("SetBatch", func(t *testing.T) {
+		now := clockT0
+		store := NewStore[stampedRecord](newMockStoreProvider(), fixedClock(&now))
+
+		a, b := &stampedRecord{}, &stampedRecord{CreatedAt: clockT1}
+		if err := store.SetBatch(ctx, map[string]*stampedRecord{"a": a, "b": b}, 0); err != nil {
+			t.Fatalf("SetBatch failed: %v", err)
+		}
+		if !a.CreatedAt.Equal(clockT0) || !b.CreatedAt.Equal(clockT1) || !b.UpdatedAt.Equal(clockT0) {
+			t.Errorf("got a=%v b=%v/%v", a.CreatedAt, b.CreatedAt, b.UpdatedAt)
+		}
+	})
+}
+
+func TestTimestamps_Bucket(t *testing.T) {
+	ctx := context.Background()
+	now := clockT0
+	bucket := NewBucket[stampedRecord](newMockBucketProvider(), fixedClock(&now))
+
+	obj := &Object[stampedRecord]{Key: "k", ContentType: "application/json"}
+	if err := bucket.Put(ctx, obj); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if !obj.Data.CreatedAt.Equal(clockT0) || !obj.Data.UpdatedAt.Equal(clockT0) {
+		t.Errorf("got %v / %v", obj.Data.CreatedAt, obj.Data.UpdatedAt)
+	}
+}
+
+func TestTimestamps_Index(t *testing.T) {
+	ctx := context.Background()
+	now := clockT0
+	provider := newMockVectorProvider()
+	index := NewIndex[stampedRecord](provider, fixedClock(&now))
+
+	id := uuid.New()
+	rec := &stampedRecord{}
+	if err := index.Upsert(ctx, id, []float32{1}, rec); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	var stored stampedRecord
+	if err := json.Unmarshal(provider.vectors[id].metadata, &stored); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if !stored.CreatedAt.Equal(clockT0) || !stored.UpdatedAt.Equal(clockT0) {
+		t.Errorf("stored: got %v / %v", stored.CreatedAt, stored.UpdatedAt)
+	}
+
+	now = clockT1
+	batch := []Vector[stampedRecord]{{ID: id, Vector: []float32{1}, Metadata: stored}}
+	if err := index.UpsertBatch(ctx, batch); err != nil {
+		t.Fatalf("UpsertBatch failed: %v", err)
+	}
+	if !batch[0].Metadata.CreatedAt.Equal(clockT0) || !batch[0].Metadata.UpdatedAt.Equal(clockT1) {
+		t.Errorf("batch: got %v / %v", batch[0].Metadata.CreatedAt, batch[0].Metadata.UpdatedAt)
+	}
+}
+
+type stampedDBRecord struct {
+	ID        int       `db:"id" constraints:"primarykey"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func TestTimestamps_Database(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Set", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		now := clockT0
+		db, err := NewDatabase[stampedDBRecord](mockDB, "records", testDBRenderer, fixedClock(&now))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		rec := &stampedDBRecord{ID: 1}
+		// The mock driver returns no rows, so only the bound values are checked.
+		_ = db.Set(ctx, "1", rec)
+		if !rec.CreatedAt.Equal(clockT0) || !rec.UpdatedAt.Equal(clockT0) {
+			t.Errorf("got %v / %v", rec.CreatedAt, rec.UpdatedAt)
+		}
+		query, ok := capture.Last()
+		if !ok {
+			t.Fatal("no query captured")
+		}
+		for _, arg := range query.Args {
+			if v, ok := arg.(time.Time); ok && !v.Equal(clockT0) {
+				t.Errorf("expected timestamps bound as %v, got args %v", clockT0, query.Args)
+			}
+		}
+	})
+
+	t.Run("Patch", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		now := clockT1
+		db, err := NewDatabase[stampedDBRecord](mockDB, "records", testDBRenderer, fixedClock(&now))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		_, _ = db.Patch(ctx, "1", map[string]any{"created_at": clockT0})
+		query, ok := capture.Last()
+		if !ok {
+			t.Fatal("no query captured")
+		}
+		if !strings.Contains(query.Query, `SET "created_at" = ?, "updated_at" = ?`) {
+			t.Fatalf("expected updated_at in SET, got: %s", query.Query)
+		}
+		if len(query.Args) < 2 || query.Args[1] != clockT1 {
+			t.Errorf("expected updated_at bound as %v, got args %v", clockT1, query.Args)
+		}
+
+		// An explicit updated_at wins over the clock.
+		_, _ = db.Patch(ctx, "1", map[string]any{"updated_at": clockT0})
+		query, _ = capture.Last()
+		if !strings.HasPrefix(query.Query, `UPDATE "records" SET "updated_at" = ? WHERE`) || query.Args[0] != clockT0 {
+			t.Errorf("expected the caller's updated_at, got: %s %v", query.Query, query.Args)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[stampedDBRecord](mockDB, "records", testDBRenderer, WithoutTimestamps())
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		rec := &stampedDBRecord{ID: 1}
+		_ = db.Set(ctx, "1", rec)
+		if !rec.CreatedAt.IsZero() || !rec.UpdatedAt.IsZero() {
+			t.Errorf("timestamps set despite opt-out: %v / %v", rec.CreatedAt, rec.UpdatedAt)
+		}
+		_, _ = db.Patch(ctx, "1", map[string]any{"created_at": clockT0})
+		if query, _ := capture.Last(); !strings.HasPrefix(query.Query, `UPDATE "records" SET "created_at" = ? WHERE`) {
+			t.Errorf("Patch set updated_at despite opt-out: %s", query.Query)
+		}
+	})
+}
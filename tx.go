@@ -0,0 +1,119 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// txKey is the context key under which a grub-managed transaction is stored.
+type txKey struct{}
+
+// commitKey is the context key under which WithTx stores the query caches
+// to invalidate once its transaction commits.
+type commitKey struct{}
+
+// commitInvalidations collects the query caches written to in one WithTx
+// transaction.
+type commitInvalidations struct {
+	tx     *sqlx.Tx
+	mu     sync.Mutex
+	caches map[*queryCache]struct{}
+}
+
+// invalidateOnCommit defers invalidating c until tx commits, reporting
+// whether it could: tx must have been begun by a WithTx whose context ctx
+// descends from.
+func invalidateOnCommit(ctx context.Context, tx *sqlx.Tx, c *queryCache) bool {
+	pending, ok := ctx.Value(commitKey{}).(*commitInvalidations)
+	if !ok || pending.tx != tx {
+		return false
+	}
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	pending.caches[c] = struct{}{}
+	return true
+}
+
+// invalidate orphans each collected cache.
+func (p *commitInvalidations) invalidate(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for c := range p.caches {
+		c.invalidate(ctx)
+	}
+}
+
+// ContextWithTx returns a copy of ctx carrying tx.
+// WithTx calls made with the returned context join tx instead of beginning
+// a new transaction. WithTx passes fn such a context already.
+func ContextWithTx(ctx context.Context, tx *sqlx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx, if any.
+func TxFromContext(ctx context.Context) (*sqlx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sqlx.Tx)
+	return tx, ok && tx != nil
+}
+
+// WithTx runs fn inside a transaction, passing it the transaction and a
+// context carrying it, so WithTx calls, Unit steps, and other ctx-driven
+// writes made with that context join the transaction.
+// The transaction is committed if fn returns nil and rolled back if fn returns
+// an error or panics; panics are re-raised after the rollback. A driver error
+// reporting a serialization failure or deadlock, whether returned by fn or
+// by the commit, matches ErrSerializationFailure; the caller may retry the
+// whole transaction (see WithTxIsolation). If ctx already
+// carries a transaction (see ContextWithTx), fn runs in it directly and opts
+// are ignored; the outermost WithTx owns commit and rollback. The default
+// timeout does not apply to the transaction as a whole.
+func (d *Database[T]) WithTx(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error, opts *sql.TxOptions) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return fn(ctx, tx)
+	}
+
+	tx, err := d.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return d.wrapErr("begin_tx", "", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	pending := &commitInvalidations{tx: tx, caches: map[*queryCache]struct{}{}}
+	txCtx := context.WithValue(ContextWithTx(ctx, tx), commitKey{}, pending)
+	if err := fn(txCtx, tx); err != nil {
+		err = classifySerialization(err)
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return errors.Join(err, d.wrapErr("rollback_tx", "", rbErr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return d.wrapErr("commit_tx", "", err)
+	}
+	// Writes made in the transaction deferred their cache invalidations to
+	// now, so no concurrent read re-caches the rows the commit replaced.
+	// Builder and Atomic writes do not register, so d's cache is always
+	// included.
+	pending.invalidate(ctx)
+	d.cache.invalidate(ctx)
+	return nil
+}
+
+// WithTxIsolation runs fn inside a transaction at the given isolation level.
+// See WithTx for commit, rollback, and nesting behaviour. At
+// sql.LevelSerializable or sql.LevelRepeatableRead the database may abort
+// the transaction with ErrSerializationFailure; retry by calling
+// WithTxIsolation again so fn re-reads what it depends on.
+func (d *Database[T]) WithTxIsolation(ctx context.Context, level sql.IsolationLevel, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	return d.WithTx(ctx, fn, &sql.TxOptions{Isolation: level})
+}
@@ -0,0 +1,295 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/zoobzio/edamame"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+// txEvents returns the BEGIN/COMMIT/ROLLBACK statements captured so far.
+func txEvents(capture *mockdb.Capture) []string {
+	var events []string
+	for _, q := range capture.Queries {
+		switch q.Query {
+		case "BEGIN", "COMMIT", "ROLLBACK":
+			events = append(events, q.Query)
+		}
+	}
+	return events
+}
+
+func assertTxEvents(t *testing.T, capture *mockdb.Capture, want ...string) {
+	t.Helper()
+	got := txEvents(capture)
+	if len(got) != len(want) {
+		t.Fatalf("expected tx events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected tx events %v, got %v", want, got)
+		}
+	}
+}
+
+func TestDatabase_WithTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("commits on success", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		var got *sqlx.Tx
+		err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			got = tx
+			return nil
+		}, nil)
+		if err != nil {
+			t.Fatalf("WithTx failed: %v", err)
+		}
+		if got == nil {
+			t.Fatal("fn did not receive a transaction")
+		}
+		assertTxEvents(t, capture, "BEGIN", "COMMIT")
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		fnErr := errors.New("fn failed")
+		err = db.WithTx(ctx, func(context.Context, *sqlx.Tx) error { return fnErr }, nil)
+		if !errors.Is(err, fnErr) {
+			t.Fatalf("expected fn error, got %v", err)
+		}
+		assertTxEvents(t, capture, "BEGIN", "ROLLBACK")
+	})
+
+	t.Run("rolls back and re-panics", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("expected re-panic with boom, got %v", p)
+			}
+			assertTxEvents(t, capture, "BEGIN", "ROLLBACK")
+		}()
+		_ = db.WithTx(ctx, func(context.Context, *sqlx.Tx) error { panic("boom") }, nil)
+		t.Error("expected panic")
+	})
+
+	t.Run("commit error", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		commitErr := errors.New("serialization failure")
+		cfg.SetCommitErr(commitErr)
+		defer cfg.Reset()
+
+		err = db.WithTx(ctx, func(context.Context, *sqlx.Tx) error { return nil }, nil)
+		if !errors.Is(err, commitErr) {
+			t.Fatalf("expected commit error, got %v", err)
+		}
+		var gErr *Error
+		if !errors.As(err, &gErr) || gErr.Op != "commit_tx" {
+			t.Errorf("expected *Error with op commit_tx, got %v", err)
+		}
+	})
+
+	t.Run("serialization failure", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		pqErr := &fakePQError{code: "40001"}
+		cfg.SetCommitErr(pqErr)
+		defer cfg.Reset()
+
+		err = db.WithTx(ctx, func(context.Context, *sqlx.Tx) error { return nil }, nil)
+		if !errors.Is(err, ErrSerializationFailure) || !errors.Is(err, pqErr) {
+			t.Errorf("expected commit to fail with ErrSerializationFailure, got %v", err)
+		}
+
+		cfg.SetCommitErr(nil)
+		err = db.WithTx(ctx, func(context.Context, *sqlx.Tx) error { return pqErr }, nil)
+		if !errors.Is(err, ErrSerializationFailure) {
+			t.Errorf("expected fn error to match ErrSerializationFailure, got %v", err)
+		}
+	})
+
+	t.Run("nested call joins the outer tx", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		err = db.WithTx(ctx, func(ctx context.Context, outer *sqlx.Tx) error {
+			return db.WithTx(ctx, func(_ context.Context, inner *sqlx.Tx) error {
+				if inner != outer {
+					t.Error("expected nested call to reuse outer tx")
+				}
+				return nil
+			}, nil)
+		}, nil)
+		if err != nil {
+			t.Fatalf("WithTx failed: %v", err)
+		}
+		assertTxEvents(t, capture, "BEGIN", "COMMIT")
+	})
+
+	t.Run("nested writes roll back with the outer tx", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		// WithOutbox makes Delete run its own WithTx, which must join fn's.
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer, WithOutbox[TestDBUser]("outbox", nil))
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		fnErr := errors.New("outer failed")
+		err = db.WithTx(ctx, func(ctx context.Context, _ *sqlx.Tx) error {
+			if err := db.Delete(ctx, "1"); err != nil {
+				return err
+			}
+			return fnErr
+		}, nil)
+		if !errors.Is(err, fnErr) {
+			t.Fatalf("expected %v, got %v", fnErr, err)
+		}
+		assertTxEvents(t, capture, "BEGIN", "ROLLBACK")
+		if n := execCount(capture, "DELETE"); n != 1 {
+			t.Errorf("expected the nested delete inside the outer tx, got %d", n)
+		}
+	})
+
+	t.Run("tx methods run inside", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		err = db.WithTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+			_, err := db.ExistsTx(ctx, tx, "1")
+			return err
+		}, nil)
+		if err != nil {
+			t.Fatalf("WithTx failed: %v", err)
+		}
+		if len(capture.Queries) != 3 {
+			t.Fatalf("expected BEGIN, query, COMMIT; got %d statements", len(capture.Queries))
+		}
+		assertTxEvents(t, capture, "BEGIN", "COMMIT")
+	})
+}
+
+func TestDatabase_WithTxIsolation(t *testing.T) {
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+
+	err = db.WithTxIsolation(context.Background(), sql.LevelSerializable, func(context.Context, *sqlx.Tx) error { return nil })
+	if err != nil {
+		t.Fatalf("WithTxIsolation failed: %v", err)
+	}
+	begin := capture.Queries[0]
+	if begin.Query != "BEGIN" || len(begin.Args) != 1 || begin.Args[0] != driver.IsolationLevel(sql.LevelSerializable) {
+		t.Errorf("expected serializable BEGIN, got %+v", begin)
+	}
+}
+
+func TestTxFromContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := TxFromContext(ctx); ok {
+		t.Error("expected no tx in empty context")
+	}
+	if _, ok := TxFromContext(ContextWithTx(ctx, nil)); ok {
+		t.Error("expected nil tx to be ignored")
+	}
+
+	mockDB, _ := mockdb.New()
+	tx, err := mockDB.Beginx()
+	if err != nil {
+		t.Fatalf("Beginx failed: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	got, ok := TxFromContext(ContextWithTx(ctx, tx))
+	if !ok || got != tx {
+		t.Error("expected tx to round-trip through context")
+	}
+}
+
+func TestDatabase_NilTx(t *testing.T) {
+	ctx := context.Background()
+	mockDB, capture := mockdb.New()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	rename := edamame.NewUpdateStatement("rename", "", edamame.UpdateSpec{
+		Set:   map[string]string{"name": "name"},
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	byID := edamame.NewSelectStatement("by-id", "", edamame.SelectSpec{
+		Where: []edamame.ConditionSpec{{Field: "id", Operator: "=", Param: "id"}},
+	})
+	user := &TestDBUser{ID: 1, Email: "a@example.com", Name: "A"}
+	params := map[string]any{"id": 1, "name": "B"}
+
+	calls := map[string]func() error{
+		"get_tx": func() error { _, err := db.GetTx(ctx, nil, "1"); return err },
+		"set_tx": func() error { return db.SetTx(ctx, nil, "1", user) },
+		"insert_returning_tx": func() error {
+			_, err := db.InsertReturningTx(ctx, nil, user)
+			return err
+		},
+		"set_if_changed_tx": func() error { _, err := db.SetIfChangedTx(ctx, nil, "1", user); return err },
+		"delete_tx":         func() error { return db.DeleteTx(ctx, nil, "1") },
+		"exists_tx":         func() error { _, err := db.ExistsTx(ctx, nil, "1"); return err },
+		"exec_query_tx":     func() error { _, err := db.ExecQueryTx(ctx, nil, QueryAll, nil); return err },
+		"exec_select_tx":    func() error { _, err := db.ExecSelectTx(ctx, nil, byID, params); return err },
+		"exec_update_tx":    func() error { _, err := db.ExecUpdateTx(ctx, nil, rename, params); return err },
+		"exec_aggregate_tx": func() error { _, err := db.ExecAggregateTx(ctx, nil, CountAll, nil); return err },
+		"exec_multi_aggregate_tx": func() error {
+			_, err := db.ExecMultiAggregateTx(ctx, nil, []AggregateSpec{{Alias: "n", Func: edamame.AggCount}}, nil, nil)
+			return err
+		},
+	}
+	for op, call := range calls {
+		t.Run(op, func(t *testing.T) {
+			capture.Reset()
+			err := call()
+			if !errors.Is(err, ErrNilTransaction) {
+				t.Fatalf("expected ErrNilTransaction, got %v", err)
+			}
+			var gErr *Error
+			if !errors.As(err, &gErr) || gErr.Op != op {
+				t.Errorf("expected op %q, got %v", op, err)
+			}
+			if len(capture.Queries) != 0 {
+				t.Errorf("expected no queries, got %v", capture.Queries)
+			}
+		})
+	}
+}
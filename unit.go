@@ -0,0 +1,400 @@
+package grub
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// StepFunc is one half of a Unit step: the forward action or its compensation.
+type StepFunc func(ctx context.Context) error
+
+// TxRunner begins transactions for Unit.ExecuteTx. *Database[T] satisfies it
+// for any T.
+type TxRunner interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context, tx *sqlx.Tx) error, opts *sql.TxOptions) error
+}
+
+// Unit coordinates writes across facades as an ordered sequence of steps.
+// Each step pairs a forward action with a compensating undo. When a step
+// fails, the undos of all completed steps run in reverse order.
+//
+// Effects form the unit's outbox: side effects such as cache invalidation
+// that run only once every step has succeeded, and under ExecuteTx only
+// once the transaction has committed.
+//
+// Units are not safe for concurrent use.
+type Unit struct {
+	steps   []unitStep
+	effects []unitStep
+}
+
+type unitStep struct {
+	do   StepFunc
+	undo StepFunc
+}
+
+// NewUnit creates an empty Unit.
+func NewUnit() *Unit {
+	return &Unit{}
+}
+
+// Add appends a step. undo may be nil for steps that need no compensation.
+// Returns the Unit for chaining; typed step constructors such as
+// DatabaseSetStep can be passed directly: u.Add(DatabaseSetStep(db, k, rec)).
+func (u *Unit) Add(do, undo StepFunc) *Unit {
+	u.steps = append(u.steps, unitStep{do: do, undo: undo})
+	return u
+}
+
+// Effect appends a side effect to the unit's outbox. Effects run in the
+// order added, after every step has succeeded, whatever order Add and
+// Effect were called in. Under Execute a failed effect compensates the
+// effects before it and every step. Under ExecuteTx effects run only after
+// the transaction commits, so a concurrent reader cannot re-cache the old
+// row between an invalidation and the commit. The writes are durable by
+// then, so a failed effect is reported, the effects after it are skipped,
+// and nothing is compensated. undo may be nil, as it usually is for an
+// invalidation the next read repairs.
+func (u *Unit) Effect(apply, undo StepFunc) *Unit {
+	u.effects = append(u.effects, unitStep{do: apply, undo: undo})
+	return u
+}
+
+// Execute runs the steps in order, then the effects. If a step fails, the undos of previously
+// completed steps run in reverse and a *UnitError describing the failure and
+// any compensation failures is returned.
+func (u *Unit) Execute(ctx context.Context) error {
+	done, err := u.run(ctx, 0, len(u.steps)+len(u.effects))
+	if err == nil {
+		return nil
+	}
+	return u.compensate(ctx, done, err)
+}
+
+// ExecuteTx runs the steps inside a single transaction begun by db. Database
+// steps built with DatabaseSetStep write through the shared transaction and
+// are undone by its rollback; other steps are compensated as in Execute. A
+// commit failure compensates the non-transactional steps and is reported
+// with Step set to -1. Effects run once the commit succeeds, outside the
+// transaction; a failed effect is reported as described at Effect.
+func (u *Unit) ExecuteTx(ctx context.Context, db TxRunner, opts *sql.TxOptions) error {
+	var done int
+	var stepErr error
+	err := db.WithTx(ctx, func(txCtx context.Context, _ *sqlx.Tx) error {
+		done, stepErr = u.run(txCtx, 0, len(u.steps))
+		return stepErr
+	}, opts)
+	if err != nil {
+		if stepErr != nil {
+			return u.compensate(ctx, done, stepErr)
+		}
+		return u.compensate(ctx, done, &UnitError{Step: -1, Err: err})
+	}
+	_, err = u.run(ctx, len(u.steps), len(u.steps)+len(u.effects))
+	return err
+}
+
+// run executes positions start through end-1 of the sequence until one
+// fails, returning the position it stopped at.
+func (u *Unit) run(ctx context.Context, start, end int) (int, error) {
+	seq := u.sequence()
+	for idx := start; idx < end; idx++ {
+		s := seq[idx]
+		if err := ctx.Err(); err != nil {
+			return idx, u.failure(idx, err)
+		}
+		if s.do == nil {
+			continue
+		}
+		if err := s.do(ctx); err != nil {
+			return idx, u.failure(idx, err)
+		}
+	}
+	return end, nil
+}
+
+// sequence returns the steps followed by the effects.
+func (u *Unit) sequence() []unitStep {
+	return append(u.steps[:len(u.steps):len(u.steps)], u.effects...)
+}
+
+// failure reports err at position idx of the sequence.
+func (u *Unit) failure(idx int, err error) *UnitError {
+	if idx >= len(u.steps) {
+		return &UnitError{Step: idx - len(u.steps), Effect: true, Err: err}
+	}
+	return &UnitError{Step: idx, Err: err}
+}
+
+// compensate runs the undos of the first done steps in reverse, recording
+// failures on err. Undos receive a context detached from ctx's cancellation
+// so compensation still runs after the caller gives up.
+func (u *Unit) compensate(ctx context.Context, done int, err error) error {
+	var uErr *UnitError
+	if !errors.As(err, &uErr) {
+		uErr = &UnitError{Step: -1, Err: err}
+	}
+	undoCtx := context.WithoutCancel(ctx)
+	seq := u.sequence()
+	for idx := done - 1; idx >= 0; idx-- {
+		undo := seq[idx].undo
+		if undo == nil {
+			continue
+		}
+		if cErr := undo(undoCtx); cErr != nil {
+			cf := u.failure(idx, cErr)
+			uErr.Compensations = append(uErr.Compensations, &CompensationError{Step: cf.Step, Effect: cf.Effect, Err: cErr})
+		}
+	}
+	return uErr
+}
+
+// UnitError reports a failed Unit execution.
+// Use errors.Is/As to match the step failure or any compensation failure.
+type UnitError struct {
+	// Step is the index of the failed step or effect, or -1 if the shared
+	// transaction could not begin or commit.
+	Step int
+	// Effect reports that Step indexes the unit's effects.
+	Effect bool
+	// Err is the failure that stopped the unit.
+	Err error
+	// Compensations holds undos that failed, in the order they ran.
+	Compensations []*CompensationError
+}
+
+// Error implements error.
+func (e *UnitError) Error() string {
+	var b strings.Builder
+	b.WriteString("grub: unit ")
+	if e.Step >= 0 {
+		b.WriteString(stepNoun(e.Effect) + " " + strconv.Itoa(e.Step) + " ")
+	} else {
+		b.WriteString("transaction ")
+	}
+	b.WriteString("failed: ")
+	b.WriteString(e.Err.Error())
+	if len(e.Compensations) > 0 {
+		b.WriteString(" (" + strconv.Itoa(len(e.Compensations)) + " compensation failures)")
+	}
+	return b.String()
+}
+
+// Unwrap returns the step failure followed by the compensation failures.
+func (e *UnitError) Unwrap() []error {
+	errs := make([]error, 0, 1+len(e.Compensations))
+	errs = append(errs, e.Err)
+	for _, c := range e.Compensations {
+		errs = append(errs, c)
+	}
+	return errs
+}
+
+// CompensationError reports an undo that failed during Unit compensation.
+type CompensationError struct {
+	Step   int
+	Effect bool // Step indexes the unit's effects
+	Err    error
+}
+
+// Error implements error.
+func (e *CompensationError) Error() string {
+	return fmt.Sprintf("grub: undo %s %d: %v", stepNoun(e.Effect), e.Step, e.Err)
+}
+
+// stepNoun names what a Unit error's index counts.
+func stepNoun(effect bool) string {
+	if effect {
+		return "effect"
+	}
+	return "step"
+}
+
+// Unwrap returns the undo's error.
+func (e *CompensationError) Unwrap() error {
+	return e.Err
+}
+
+// DatabaseSetStep returns a step that stores record at key. The current row
+// is read from the primary first, as stored, without redaction or
+// AfterLoad, so the undo can restore it, or delete the row if there was
+// none. When the step runs under ExecuteTx the write joins the shared
+// transaction and the undo is left to its rollback.
+func DatabaseSetStep[T any](db *Database[T], key string, record *T) (do, undo StepFunc) {
+	var prev *T
+	var inTx bool
+	do = func(ctx context.Context) error {
+		if tx, ok := TxFromContext(ctx); ok {
+			inTx = true
+			return db.SetTx(ctx, tx, key, record)
+		}
+		current, err := db.Get(withRawLoad(WithPrimaryReads(ctx)), key)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		prev = current
+		return db.Set(ctx, key, record)
+	}
+	undo = func(ctx context.Context) error {
+		if inTx {
+			return nil
+		}
+		if prev != nil {
+			return db.Set(ctx, key, prev)
+		}
+		return db.Delete(ctx, key)
+	}
+	return do, undo
+}
+
+// StoreSetStep returns a step that stores value at key with ttl. The current
+// value, as stored, and the time it had left are read first so the undo can
+// restore both, or delete the key if there was none. ttl stands in for the
+// time left when the provider does not implement StoreTTLReader.
+func StoreSetStep[T any](store *Store[T], key string, value *T, ttl time.Duration) (do, undo StepFunc) {
+	var prev *T
+	var prevTTL time.Duration
+	do = func(ctx context.Context) error {
+		prev = nil
+		current, err := store.Get(withRawLoad(ctx), key)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if current != nil {
+			left, err := store.TTL(ctx, key)
+			switch {
+			case errors.Is(err, ErrUnsupported):
+				left = ttl
+			case errors.Is(err, ErrNotFound):
+				current = nil
+			case err != nil:
+				return err
+			}
+			prev, prevTTL = current, left
+		}
+		return store.Set(ctx, key, value, ttl)
+	}
+	undo = func(ctx context.Context) error {
+		if prev != nil {
+			return store.Set(ctx, key, prev, prevTTL)
+		}
+		return store.Delete(ctx, key)
+	}
+	return do, undo
+}
+
+// IndexUpsertStep returns a step that upserts a vector. The current entry is
+// read first, as stored, so the undo can restore it, or delete the vector if
+// there was none.
+func IndexUpsertStep[T any](index *Index[T], id uuid.UUID, vector []float32, metadata *T) (do, undo StepFunc) {
+	var prev *Vector[T]
+	do = func(ctx context.Context) error {
+		current, err := index.Get(withRawLoad(ctx), id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		prev = current
+		return index.Upsert(ctx, id, vector, metadata)
+	}
+	undo = func(ctx context.Context) error {
+		if prev != nil {
+			return index.Upsert(ctx, id, prev.Vector, &prev.Metadata)
+		}
+		return index.Delete(ctx, id)
+	}
+	return do, undo
+}
+
+// StoreDeleteStep returns a step that deletes key, typically passed to
+// Effect to invalidate a cache entry. The current value and the time it had
+// left are read first, as stored, so the undo can restore both; ttl stands
+// in for the time left when the provider does not implement
+// StoreTTLReader. A key that does not exist is left alone.
+func StoreDeleteStep[T any](store *Store[T], key string, ttl time.Duration) (do, undo StepFunc) {
+	var prev *T
+	var prevTTL time.Duration
+	do = func(ctx context.Context) error {
+		prev = nil
+		current, err := store.Get(withRawLoad(ctx), key)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		left, err := store.TTL(ctx, key)
+		switch {
+		case errors.Is(err, ErrUnsupported):
+			left = ttl
+		case errors.Is(err, ErrNotFound):
+			return nil
+		case err != nil:
+			return err
+		}
+		prev, prevTTL = current, left
+		return store.Delete(ctx, key)
+	}
+	undo = func(ctx context.Context) error {
+		if prev == nil {
+			return nil
+		}
+		return store.Set(ctx, key, prev, prevTTL)
+	}
+	return do, undo
+}
+
+// BucketPutStep returns a step that stores obj. The current object is read
+// first, as stored, so the undo can restore it, or delete the object if
+// there was none.
+func BucketPutStep[T any](bucket *Bucket[T], obj *Object[T]) (do, undo StepFunc) {
+	var prev *Object[T]
+	do = func(ctx context.Context) error {
+		current, err := bucket.Get(withRawLoad(ctx), obj.Key)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		prev = current
+		return bucket.Put(ctx, obj)
+	}
+	undo = func(ctx context.Context) error {
+		if prev != nil {
+			return bucket.Put(ctx, prev)
+		}
+		return bucket.Delete(ctx, obj.Key)
+	}
+	return do, undo
+}
+
+// BucketDeleteStep returns a step that deletes the object at key. The
+// current object is read first, as stored, so the undo can restore it; a
+// key that does not exist is left alone.
+func BucketDeleteStep[T any](bucket *Bucket[T], key string) (do, undo StepFunc) {
+	var prev *Object[T]
+	do = func(ctx context.Context) error {
+		current, err := bucket.Get(withRawLoad(ctx), key)
+		if errors.Is(err, ErrNotFound) {
+			prev = nil
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		prev = current
+		return bucket.Delete(ctx, key)
+	}
+	undo = func(ctx context.Context) error {
+		if prev == nil {
+			return nil
+		}
+		return bucket.Put(ctx, prev)
+	}
+	return do, undo
+}
@@ -0,0 +1,523 @@
+package grub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/grub/internal/mockdb"
+)
+
+func TestUnit_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all steps succeed", func(t *testing.T) {
+		var ran []int
+		u := NewUnit()
+		for n := range 3 {
+			u.Add(func(context.Context) error { ran = append(ran, n); return nil }, nil)
+		}
+		if err := u.Execute(ctx); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if len(ran) != 3 || ran[0] != 0 || ran[2] != 2 {
+			t.Errorf("expected steps to run in order, got %v", ran)
+		}
+	})
+
+	t.Run("step 3 fails and index upsert is compensated", func(t *testing.T) {
+		vectors := newMockVectorProvider()
+		index := NewIndex[testMetadata](vectors)
+		cacheProvider := newMockStoreProvider()
+		cache := NewStore[testMetadata](cacheProvider)
+		cacheErr := errors.New("cache unavailable")
+		cacheProvider.setErr = cacheErr
+
+		var undone []string
+		id := uuid.New()
+		meta := &testMetadata{Category: "doc", Score: 1}
+
+		doIndex, undoIndex := IndexUpsertStep(index, id, []float32{1, 2}, meta)
+		u := NewUnit().
+			Add(func(context.Context) error { return nil },
+				func(context.Context) error { undone = append(undone, "row"); return nil }).
+			Add(doIndex, func(ctx context.Context) error {
+				undone = append(undone, "index")
+				return undoIndex(ctx)
+			}).
+			Add(StoreSetStep(cache, "doc:"+id.String(), meta, 0))
+
+		err := u.Execute(ctx)
+		if !errors.Is(err, cacheErr) {
+			t.Fatalf("expected cache error, got %v", err)
+		}
+		var uErr *UnitError
+		if !errors.As(err, &uErr) || uErr.Step != 2 {
+			t.Fatalf("expected *UnitError for step 2, got %v", err)
+		}
+		if len(uErr.Compensations) != 0 {
+			t.Errorf("unexpected compensation failures: %v", uErr.Compensations)
+		}
+		if len(undone) != 2 || undone[0] != "index" || undone[1] != "row" {
+			t.Errorf("expected undo in reverse order, got %v", undone)
+		}
+		if _, ok := vectors.vectors[id]; ok {
+			t.Error("expected index upsert to be compensated with a delete")
+		}
+	})
+
+	t.Run("compensation failures are reported", func(t *testing.T) {
+		undoErr := errors.New("undo failed")
+		stepErr := errors.New("step failed")
+		u := NewUnit().
+			Add(func(context.Context) error { return nil }, func(context.Context) error { return undoErr }).
+			Add(func(context.Context) error { return stepErr }, nil)
+
+		err := u.Execute(ctx)
+		if !errors.Is(err, stepErr) || !errors.Is(err, undoErr) {
+			t.Fatalf("expected both step and undo errors, got %v", err)
+		}
+		var cErr *CompensationError
+		if !errors.As(err, &cErr) || cErr.Step != 0 {
+			t.Errorf("expected *CompensationError for step 0, got %v", err)
+		}
+	})
+
+	t.Run("undo runs after cancellation", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		var undoCtxErr error
+		u := NewUnit().
+			Add(func(context.Context) error { cancel(); return nil },
+				func(c context.Context) error { undoCtxErr = c.Err(); return nil }).
+			Add(func(context.Context) error { return nil }, nil)
+
+		err := u.Execute(cctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if undoCtxErr != nil {
+			t.Errorf("expected undo context to be live, got %v", undoCtxErr)
+		}
+	})
+}
+
+func TestUnit_ExecuteTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rolls back and compensates on failure", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+
+		var sawTx, undone bool
+		stepErr := errors.New("step failed")
+		u := NewUnit().
+			Add(func(c context.Context) error { _, sawTx = TxFromContext(c); return nil },
+				func(context.Context) error { undone = true; return nil }).
+			Add(func(context.Context) error { return stepErr }, nil)
+
+		if err := u.ExecuteTx(ctx, db, nil); !errors.Is(err, stepErr) {
+			t.Fatalf("expected step error, got %v", err)
+		}
+		if !sawTx {
+			t.Error("expected steps to receive the transaction")
+		}
+		if !undone {
+			t.Error("expected non-SQL step to be compensated")
+		}
+		assertTxEvents(t, capture, "BEGIN", "ROLLBACK")
+	})
+
+	t.Run("commit failure compensates every step", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		commitErr := errors.New("commit failed")
+		cfg.SetCommitErr(commitErr)
+		defer cfg.Reset()
+
+		var undone bool
+		u := NewUnit().Add(func(context.Context) error { return nil },
+			func(context.Context) error { undone = true; return nil })
+
+		err = u.ExecuteTx(ctx, db, nil)
+		var uErr *UnitError
+		if !errors.As(err, &uErr) || uErr.Step != -1 || !errors.Is(err, commitErr) {
+			t.Fatalf("expected transaction *UnitError, got %v", err)
+		}
+		if !undone {
+			t.Error("expected step to be compensated after commit failure")
+		}
+	})
+
+	t.Run("database step defers to rollback", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		tx, err := mockDB.Beginx()
+		if err != nil {
+			t.Fatalf("Beginx failed: %v", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		do, undo := DatabaseSetStep(db, "1", &TestDBUser{ID: 1})
+		// The mock returns no rows, so SetTx itself errors; only the
+		// statements issued matter here.
+		_ = do(ContextWithTx(ctx, tx))
+		issued := len(capture.Queries)
+		if err := undo(ctx); err != nil {
+			t.Fatalf("undo failed: %v", err)
+		}
+		if len(capture.Queries) != issued {
+			t.Errorf("expected undo to leave the write to rollback, got %q", capture.Queries[issued:])
+		}
+		for _, q := range capture.Queries {
+			if strings.HasPrefix(q.Query, "SELECT") {
+				t.Errorf("unexpected snapshot read inside tx: %s", q.Query)
+			}
+		}
+	})
+}
+
+func TestUnit_Effect(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("effects run after every step", func(t *testing.T) {
+		var ran []string
+		record := func(name string) StepFunc {
+			return func(context.Context) error { ran = append(ran, name); return nil }
+		}
+		u := NewUnit().
+			Add(record("row"), nil).
+			Effect(record("invalidate"), nil).
+			Add(record("index"), nil)
+		if err := u.Execute(ctx); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if strings.Join(ran, ",") != "row,index,invalidate" {
+			t.Errorf("expected effects last, got %v", ran)
+		}
+	})
+
+	t.Run("step failure skips effects", func(t *testing.T) {
+		var applied bool
+		stepErr := errors.New("step failed")
+		u := NewUnit().
+			Effect(func(context.Context) error { applied = true; return nil }, nil).
+			Add(func(context.Context) error { return stepErr }, nil)
+		if err := u.Execute(ctx); !errors.Is(err, stepErr) {
+			t.Fatalf("expected step error, got %v", err)
+		}
+		if applied {
+			t.Error("expected effects to be skipped")
+		}
+	})
+
+	t.Run("effect failure compensates effects and steps", func(t *testing.T) {
+		var undone []string
+		undo := func(name string) StepFunc {
+			return func(context.Context) error { undone = append(undone, name); return nil }
+		}
+		effectErr := errors.New("cache unavailable")
+		u := NewUnit().
+			Add(func(context.Context) error { return nil }, undo("row")).
+			Effect(func(context.Context) error { return nil }, undo("first")).
+			Effect(func(context.Context) error { return effectErr }, undo("second"))
+
+		err := u.Execute(ctx)
+		var uErr *UnitError
+		if !errors.As(err, &uErr) || !uErr.Effect || uErr.Step != 1 || !errors.Is(err, effectErr) {
+			t.Fatalf("expected *UnitError for effect 1, got %v", err)
+		}
+		if strings.Join(undone, ",") != "first,row" {
+			t.Errorf("expected undo in reverse order, got %v", undone)
+		}
+	})
+
+	t.Run("under ExecuteTx effects run after the commit", func(t *testing.T) {
+		mockDB, capture := mockdb.New()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		var events []string
+		var effectTx, undone bool
+		effectErr := errors.New("cache unavailable")
+		u := NewUnit().
+			Add(func(context.Context) error { return nil },
+				func(context.Context) error { undone = true; return nil }).
+			Effect(func(c context.Context) error {
+				_, effectTx = TxFromContext(c)
+				events = txEvents(capture)
+				return effectErr
+			}, func(context.Context) error { undone = true; return nil }).
+			Effect(func(context.Context) error { t.Error("expected later effects to be skipped"); return nil }, nil)
+
+		err = u.ExecuteTx(ctx, db, nil)
+		var uErr *UnitError
+		if !errors.As(err, &uErr) || !uErr.Effect || uErr.Step != 0 || !errors.Is(err, effectErr) {
+			t.Fatalf("expected *UnitError for effect 0, got %v", err)
+		}
+		if strings.Join(events, ",") != "BEGIN,COMMIT" {
+			t.Errorf("expected the effect to run after the commit, saw %v", events)
+		}
+		if effectTx {
+			t.Error("expected effects to run without the transaction")
+		}
+		if undone {
+			t.Error("expected nothing to be compensated after the commit")
+		}
+	})
+
+	t.Run("under ExecuteTx a failed commit skips effects", func(t *testing.T) {
+		mockDB, _, cfg := mockdb.NewWithConfig()
+		db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+		if err != nil {
+			t.Fatalf("NewDatabase failed: %v", err)
+		}
+		commitErr := errors.New("commit failed")
+		cfg.SetCommitErr(commitErr)
+		defer cfg.Reset()
+
+		var applied bool
+		u := NewUnit().Effect(func(context.Context) error { applied = true; return nil }, nil)
+		if err := u.ExecuteTx(ctx, db, nil); !errors.Is(err, commitErr) {
+			t.Fatalf("expected commit error, got %v", err)
+		}
+		if applied {
+			t.Error("expected effects to be skipped after the commit failed")
+		}
+	})
+}
+
+func TestDatabaseSetStep_ReadError(t *testing.T) {
+	mockDB, capture, cfg := mockdb.NewWithConfig()
+	db, err := NewDatabase[TestDBUser](mockDB, "test_users", testDBRenderer)
+	if err != nil {
+		t.Fatalf("NewDatabase failed: %v", err)
+	}
+	readErr := errors.New("connection reset")
+	cfg.SetQueryErr(readErr)
+	defer cfg.Reset()
+
+	do, _ := DatabaseSetStep(db, "1", &TestDBUser{ID: 1})
+	if err := do(context.Background()); !errors.Is(err, readErr) {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	if len(capture.Queries) != 1 {
+		t.Errorf("expected only the snapshot read, got %d statements", len(capture.Queries))
+	}
+}
+
+func TestDatabaseSetStep_ReadsPrimary(t *testing.T) {
+	f := newReplicaFixture(t)
+	do, _ := DatabaseSetStep(f.db, "1", &TestDBUser{ID: 1, Email: "a@example.com", Name: "Alice"})
+	if err := do(context.Background()); err != nil {
+		t.Fatalf("do failed: %v", err)
+	}
+	if got := f.counts(); got[1] != 0 || got[2] != 0 {
+		t.Errorf("expected the snapshot read on the primary, got primary/replica counts %v", got)
+	}
+}
+
+func TestStoreSetStep_RestoresPrevious(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockStoreProvider()
+	store := NewStore[testRecord](provider)
+	provider.data["k"] = []byte(`{"id":1,"name":"old"}`)
+
+	do, undo := StoreSetStep(store, "k", &testRecord{ID: 1, Name: "new"}, 0)
+	if err := do(ctx); err != nil {
+		t.Fatalf("do failed: %v", err)
+	}
+	if err := undo(ctx); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	got, err := store.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Name != "old" {
+		t.Errorf("expected previous value restored, got %q", got.Name)
+	}
+}
+
+func TestStoreDeleteStep(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockStoreProvider()
+	store := NewStore[testRecord](provider)
+	provider.data["k"] = []byte(`{"id":1,"name":"old"}`)
+
+	do, undo := StoreDeleteStep(store, "k", 0)
+	if err := do(ctx); err != nil {
+		t.Fatalf("do failed: %v", err)
+	}
+	if _, ok := provider.data["k"]; ok {
+		t.Fatal("expected the key to be deleted")
+	}
+	if err := undo(ctx); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if got, err := store.Get(ctx, "k"); err != nil || got.Name != "old" {
+		t.Errorf("expected previous value restored, got %v, %v", got, err)
+	}
+
+	do, undo = StoreDeleteStep(store, "missing", 0)
+	if err := do(ctx); err != nil {
+		t.Errorf("expected a missing key to be left alone, got %v", err)
+	}
+	if err := undo(ctx); err != nil {
+		t.Errorf("undo failed: %v", err)
+	}
+}
+
+func TestStoreDeleteStep_RestoresTTL(t *testing.T) {
+	ctx := context.Background()
+	provider := newTTLStoreProvider()
+	store := NewStore[testRecord](provider)
+	if err := store.Set(ctx, "k", &testRecord{ID: 1, Name: "old"}, time.Hour); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	do, undo := StoreDeleteStep(store, "k", time.Minute)
+	if err := do(ctx); err != nil {
+		t.Fatalf("do failed: %v", err)
+	}
+	if err := undo(ctx); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if ttl := provider.ttls["k"]; ttl != time.Hour {
+		t.Errorf("expected the original TTL restored, got %v", ttl)
+	}
+}
+
+func TestStoreSetStep_RestoresTTL(t *testing.T) {
+	ctx := context.Background()
+	provider := newTTLStoreProvider()
+	store := NewStore[testRecord](provider)
+	if err := store.Set(ctx, "k", &testRecord{ID: 1, Name: "old"}, time.Hour); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	do, undo := StoreSetStep(store, "k", &testRecord{ID: 1, Name: "new"}, time.Minute)
+	if err := do(ctx); err != nil {
+		t.Fatalf("do failed: %v", err)
+	}
+	if err := undo(ctx); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if ttl := provider.ttls["k"]; ttl != time.Hour {
+		t.Errorf("expected the original TTL restored, got %v", ttl)
+	}
+}
+
+func TestStoreSetStep_RestoresRedactedFields(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockStoreProvider()
+	store := NewStore[secretRecord](provider, WithRedaction())
+	if err := store.Set(ctx, "k", newSecret(), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	next := newSecret()
+	next.Password = "changed"
+	do, undo := StoreSetStep(store, "k", next, 0)
+	if err := do(ctx); err != nil {
+		t.Fatalf("do failed: %v", err)
+	}
+	if err := undo(ctx); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	var stored secretRecord
+	if err := json.Unmarshal(provider.data["k"], &stored); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if stored.Password != "hunter2" || stored.Token != "t0k" {
+		t.Errorf("expected the stored secrets restored, got %+v", stored)
+	}
+}
+
+func TestBucketSteps(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockBucketProvider()
+	bucket := NewBucket[testPayload](provider)
+	old := &Object[testPayload]{Key: "k", ContentType: "application/json", Data: testPayload{Field1: "old"}}
+	if err := bucket.Put(ctx, old); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	do, undo := BucketPutStep(bucket, &Object[testPayload]{Key: "k", ContentType: "application/json", Data: testPayload{Field1: "new"}})
+	if err := do(ctx); err != nil {
+		t.Fatalf("put do failed: %v", err)
+	}
+	if err := undo(ctx); err != nil {
+		t.Fatalf("put undo failed: %v", err)
+	}
+	if got, err := bucket.Get(ctx, "k"); err != nil || got.Data.Field1 != "old" {
+		t.Errorf("expected previous object restored, got %v, %v", got, err)
+	}
+
+	do, undo = BucketDeleteStep(bucket, "k")
+	if err := do(ctx); err != nil {
+		t.Fatalf("delete do failed: %v", err)
+	}
+	if _, ok := provider.data["k"]; ok {
+		t.Fatal("expected the object to be deleted")
+	}
+	if err := undo(ctx); err != nil {
+		t.Fatalf("delete undo failed: %v", err)
+	}
+	if got, err := bucket.Get(ctx, "k"); err != nil || got.Data.Field1 != "old" {
+		t.Errorf("expected deleted object restored, got %v, %v", got, err)
+	}
+}
+
+func TestIndexUpsertStep_RestoresPrevious(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockVectorProvider()
+	index := NewIndex[testMetadata](provider)
+	id := uuid.New()
+	if err := index.Upsert(ctx, id, []float32{1, 2}, &testMetadata{Category: "old"}); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+
+	do, undo := IndexUpsertStep(index, id, []float32{3, 4}, &testMetadata{Category: "new"})
+	if err := do(ctx); err != nil {
+		t.Fatalf("do failed: %v", err)
+	}
+	if err := undo(ctx); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	got, err := index.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.Metadata.Category != "old" || got.Vector[0] != 1 {
+		t.Errorf("expected previous entry restored, got %+v", got)
+	}
+}
+
+func TestUnitError_Error(t *testing.T) {
+	err := &UnitError{Step: 1, Err: errors.New("boom"), Compensations: []*CompensationError{{Step: 0, Err: errors.New("x")}}}
+	if got := err.Error(); got != "grub: unit step 1 failed: boom (1 compensation failures)" {
+		t.Errorf("unexpected message %q", got)
+	}
+	effectErr := &UnitError{Step: 0, Effect: true, Err: errors.New("boom")}
+	if got := effectErr.Error(); got != "grub: unit effect 0 failed: boom" {
+		t.Errorf("unexpected message %q", got)
+	}
+	txErr := &UnitError{Step: -1, Err: errors.New("commit")}
+	if got := txErr.Error(); got != "grub: unit transaction failed: commit" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
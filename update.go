@@ -0,0 +1,96 @@
+package grub
+
+import (
+	"context"
+	"time"
+
+	"github.com/zoobzio/grub/internal/shared"
+)
+
+// Update applies mutate to the value at key and stores the result with
+// optional TTL, as a read-modify-write that cannot overwrite a concurrent
+// change. The new value is written with CompareAndSwap against the bytes
+// that were read; if another writer changed the value in between, Update
+// reads it again and calls mutate on the fresh value. After the retries set
+// by WithUpdateRetries (10 by default) it gives up with ErrConflict, so
+// mutate may run more than once and should not have side effects. An error
+// from mutate aborts the update and is returned as is.
+//
+// mutate sees the value as Get returns it, AfterLoad hook included. Saving
+// follows Set: timestamps are stamped and BeforeSave and AfterSave run.
+// Under WithRedaction, redacted fields mutate leaves zero keep their stored
+// values. Returns ErrNotFound if key does not exist and ErrUnsupported
+// unless the provider implements StoreSwapper.
+func (s *Store[T]) Update(ctx context.Context, key string, mutate func(*T) error, ttl time.Duration) error {
+	if s.readOnly {
+		return shared.WrapError(KindStore, "update", "", key, ErrReadOnly)
+	}
+	key, err := s.keys.apply(key)
+	if err != nil {
+		return shared.WrapError(KindStore, "update", "", key, err)
+	}
+	swapper, ok := storeSwapper(s.provider)
+	if !ok {
+		return shared.WrapError(KindStore, "update", "", key, ErrUnsupported)
+	}
+	retries := s.retries
+	if retries <= 0 {
+		retries = defaultUpdateRetries
+	}
+	for range retries + 1 {
+		value, swapped, err := s.tryUpdate(ctx, swapper, key, mutate, ttl)
+		if err != nil {
+			return err
+		}
+		if swapped {
+			return callAfterSave(ctx, value)
+		}
+	}
+	return shared.WrapError(KindStore, "update", "", key, ErrConflict)
+}
+
+// tryUpdate makes one read-modify-write attempt, reporting whether the swap
+// won. On success it returns the value written.
+func (s *Store[T]) tryUpdate(ctx context.Context, swapper StoreSwapper, key string, mutate func(*T) error, ttl time.Duration) (*T, bool, error) {
+	readCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+	old, err := s.provider.Get(readCtx, key)
+	cancel()
+	if err != nil {
+		return nil, false, shared.WrapError(KindStore, "update", "", key, err)
+	}
+	value := new(T)
+	if err := s.codec.Decode(old, value); err != nil {
+		return nil, false, err
+	}
+	var stored *T
+	if s.redact != nil {
+		// a second decode keeps the secrets apart from what mutate edits
+		stored = new(T)
+		if err := s.codec.Decode(old, stored); err != nil {
+			return nil, false, err
+		}
+		s.redact.apply(value)
+	}
+	if err := callAfterLoad(ctx, value); err != nil {
+		return nil, false, err
+	}
+	if err := mutate(value); err != nil {
+		return nil, false, err
+	}
+	s.redact.restore(value, stored)
+	s.stamps.stamp(value)
+	if err := callBeforeSave(ctx, value); err != nil {
+		return nil, false, err
+	}
+	data, err := s.codec.Encode(value)
+	if err != nil {
+		return nil, false, err
+	}
+	writeCtx, cancel := withDefaultTimeout(ctx, s.timeout)
+	defer cancel()
+	swapped, err := swapper.CompareAndSwap(writeCtx, key, old, data, ttl)
+	if err != nil {
+		return nil, false, shared.WrapError(KindStore, "update", "", key, err)
+	}
+	return value, swapped, nil
+}
@@ -0,0 +1,147 @@
+package grub
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// swappingStoreProvider adds CompareAndSwap to the mock. Before each swap it
+// runs interfere, standing in for a concurrent writer.
+type swappingStoreProvider struct {
+	*mockStoreProvider
+	swaps     int
+	interfere func()
+}
+
+func (p *swappingStoreProvider) CompareAndSwap(_ context.Context, key string, old, value []byte, _ time.Duration) (bool, error) {
+	p.swaps++
+	if p.interfere != nil {
+		p.interfere()
+	}
+	current, ok := p.data[key]
+	if !ok || !bytes.Equal(current, old) {
+		return false, nil
+	}
+	p.data[key] = value
+	return true, nil
+}
+
+func TestStore_Update(t *testing.T) {
+	ctx := context.Background()
+	rename := func(r *testRecord) error {
+		r.Name = "bob"
+		return nil
+	}
+
+	t.Run("swaps", func(t *testing.T) {
+		provider := &swappingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		provider.data["k"] = []byte(`{"id":1,"name":"alice"}`)
+		store := NewStore[testRecord](provider)
+		if err := store.Update(ctx, "k", rename, 0); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		got, _ := store.Get(ctx, "k")
+		if got.ID != 1 || got.Name != "bob" {
+			t.Errorf("expected the mutated record, got %+v", got)
+		}
+	})
+
+	t.Run("retries on conflict", func(t *testing.T) {
+		provider := &swappingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		provider.data["k"] = []byte(`{"id":1,"name":"alice"}`)
+		provider.interfere = func() {
+			if provider.swaps == 1 {
+				provider.data["k"] = []byte(`{"id":2,"name":"carol"}`)
+			}
+		}
+		var seen []int
+		err := NewStore[testRecord](provider).Update(ctx, "k", func(r *testRecord) error {
+			seen = append(seen, r.ID)
+			r.Name = "bob"
+			return nil
+		}, 0)
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if len(seen) != 2 || seen[1] != 2 {
+			t.Errorf("expected mutate to rerun on the concurrent write, saw IDs %v", seen)
+		}
+		if string(provider.data["k"]) != `{"id":2,"name":"bob"}` {
+			t.Errorf("expected the concurrent write to be kept, got %s", provider.data["k"])
+		}
+	})
+
+	t.Run("gives up with ErrConflict", func(t *testing.T) {
+		provider := &swappingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		provider.data["k"] = []byte(`{"id":1}`)
+		provider.interfere = func() {
+			provider.data["k"] = []byte(`{"id":` + strconv.Itoa(provider.swaps+1) + `}`)
+		}
+		err := NewStore[testRecord](provider, WithUpdateRetries(2)).Update(ctx, "k", rename, 0)
+		if !errors.Is(err, ErrConflict) {
+			t.Fatalf("expected ErrConflict, got %v", err)
+		}
+		if provider.swaps != 3 {
+			t.Errorf("expected 3 attempts, got %d", provider.swaps)
+		}
+	})
+
+	t.Run("mutate error aborts", func(t *testing.T) {
+		provider := &swappingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		provider.data["k"] = []byte(`{"id":1}`)
+		boom := errors.New("boom")
+		err := NewStore[testRecord](provider).Update(ctx, "k", func(*testRecord) error { return boom }, 0)
+		if !errors.Is(err, boom) || provider.swaps != 0 {
+			t.Errorf("expected the mutate error and no swap, got %v after %d swaps", err, provider.swaps)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		provider := &swappingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		if err := NewStore[testRecord](provider).Update(ctx, "k", rename, 0); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		provider := newMockStoreProvider()
+		provider.data["k"] = []byte(`{"id":1}`)
+		if err := NewStore[testRecord](provider).Update(ctx, "k", rename, 0); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		provider := &swappingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		provider.data["user:k"] = []byte(`{"id":1}`)
+		if err := NewStore[testRecord](provider, WithKeyNamespace("user:")).Update(ctx, "k", rename, 0); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if string(provider.data["user:k"]) != `{"id":1,"name":"bob"}` {
+			t.Errorf("expected the namespaced key to be updated, got %s", provider.data["user:k"])
+		}
+	})
+
+	t.Run("keeps redacted fields", func(t *testing.T) {
+		provider := &swappingStoreProvider{mockStoreProvider: newMockStoreProvider()}
+		provider.data["k"] = []byte(`{"name":"alice","password":"hunter2","token":"t"}`)
+		store := NewStore[secretRecord](provider, WithRedaction())
+		err := store.Update(ctx, "k", func(r *secretRecord) error {
+			if r.Password != "" {
+				t.Error("expected mutate to see the redacted view")
+			}
+			r.Name = "bob"
+			return nil
+		}, 0)
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if want := `{"name":"bob","password":"hunter2","token":"t"}`; string(provider.data["k"]) != want {
+			t.Errorf("expected %s, got %s", want, provider.data["k"])
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package weaviate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/weaviate/weaviate-go-client/v5/weaviate/filters"
+	"github.com/zoobzio/grub/internal/shared"
+	"github.com/zoobzio/vecna"
+)
+
+// defaultDeleteBatchSize matches Weaviate's default QUERY_MAXIMUM_RESULTS,
+// the most objects one batch delete removes.
+const defaultDeleteBatchSize = 10000
+
+// DeleteResult reports the outcome of a batch delete.
+type DeleteResult struct {
+	// Matched is the number of objects the filter selected.
+	Matched int64
+	// Deleted is the number of objects removed; zero on a dry run.
+	Deleted int64
+	// Failed is the number of matched objects Weaviate could not remove.
+	Failed int64
+}
+
+// DeleteIDs removes the objects with the given IDs using batch deletes of
+// up to Config.DeleteBatchSize IDs each. IDs that do not exist are not
+// matched and not counted. With dryRun, nothing is removed and the result
+// reports how many objects would be. Objects that fail to delete are
+// reported in the result and in the returned error, which joins one error
+// per failed object; the remaining chunks still run. Cancellation is checked
+// between chunks.
+func (p *Provider) DeleteIDs(ctx context.Context, ids []uuid.UUID, dryRun bool) (DeleteResult, error) {
+	var total DeleteResult
+	var failures []error
+	size := p.deleteBatchSize()
+	for start := 0; start < len(ids); start += size {
+		if err := ctx.Err(); err != nil {
+			return total, errors.Join(append(failures, err)...)
+		}
+		chunk := ids[start:min(start+size, len(ids))]
+		values := make([]string, len(chunk))
+		for i, id := range chunk {
+			values[i] = id.String()
+		}
+		where := filters.Where().
+			WithPath([]string{"id"}).
+			WithOperator(filters.ContainsAny).
+			WithValueText(values...)
+		res, errs, _, err := p.batchDelete(ctx, where, dryRun)
+		total.add(res)
+		failures = append(failures, errs...)
+		if err != nil {
+			return total, errors.Join(append(failures, err)...)
+		}
+	}
+	return total, deleteFailures(total, failures)
+}
+
+// DeleteWhere removes every object matching filter in batch deletes,
+// repeating while more objects match than one request may remove. With
+// dryRun, nothing is removed and the result reports how many objects would
+// be. A nil filter is rejected rather than deleting the whole class.
+func (p *Provider) DeleteWhere(ctx context.Context, filter *vecna.Filter, dryRun bool) (DeleteResult, error) {
+	if filter == nil {
+		return DeleteResult{}, errors.New("weaviate: DeleteWhere requires a filter")
+	}
+	where, err := translateFilter(filter)
+	if err != nil {
+		return DeleteResult{}, err
+	}
+	var total DeleteResult
+	var failures []error
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, errors.Join(append(failures, err)...)
+		}
+		res, errs, limit, err := p.batchDelete(ctx, where, dryRun)
+		total.add(res)
+		failures = append(failures, errs...)
+		if err != nil {
+			return total, errors.Join(append(failures, err)...)
+		}
+		// stop unless the server capped the request and made progress
+		if dryRun || res.Matched <= limit || res.Deleted == 0 {
+			break
+		}
+	}
+	return total, deleteFailures(total, failures)
+}
+
+// batchDelete runs one batch delete and returns its counts, an error per
+// object that failed to delete, and the server's per-request object cap.
+func (p *Provider) batchDelete(ctx context.Context, where *filters.WhereBuilder, dryRun bool) (DeleteResult, []error, int64, error) {
+	resp, err := p.client.Batch().ObjectsBatchDeleter().
+		WithClassName(p.config.Class).
+		WithWhere(where).
+		WithOutput("verbose").
+		WithDryRun(dryRun).
+		Do(ctx)
+	if err != nil {
+		return DeleteResult{}, nil, 0, shared.CtxErr(ctx, err)
+	}
+	if resp.Results == nil {
+		return DeleteResult{}, nil, 0, nil
+	}
+	r := resp.Results
+	var errs []error
+	for _, obj := range r.Objects {
+		if obj == nil || obj.Status == nil || *obj.Status != "FAILED" {
+			continue
+		}
+		msg := "unknown error"
+		if obj.Errors != nil && len(obj.Errors.Error) > 0 && obj.Errors.Error[0] != nil {
+			msg = obj.Errors.Error[0].Message
+		}
+		errs = append(errs, fmt.Errorf("weaviate: deleting %s: %s", obj.ID, msg))
+	}
+	return DeleteResult{Matched: r.Matches, Deleted: r.Successful, Failed: r.Failed}, errs, r.Limit, nil
+}
+
+// deleteFailures reports failed deletes as one error, or nil when none
+// failed.
+func deleteFailures(total DeleteResult, failures []error) error {
+	if total.Failed == 0 && len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("weaviate: %d of %d deletes failed: %w", total.Failed, total.Matched, errors.Join(failures...))
+}
+
+// add accumulates other into r.
+func (r *DeleteResult) add(other DeleteResult) {
+	r.Matched += other.Matched
+	r.Deleted += other.Deleted
+	r.Failed += other.Failed
+}
+
+// deleteBatchSize returns the configured batch delete size.
+func (p *Provider) deleteBatchSize() int {
+	if p.config.DeleteBatchSize > 0 {
+		return p.config.DeleteBatchSize
+	}
+	return defaultDeleteBatchSize
+}
@@ -0,0 +1,195 @@
+package weaviate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/weaviate/weaviate-go-client/v5/weaviate"
+)
+
+// batchDeleteRequest is the body of a DELETE /v1/batch/objects call.
+type batchDeleteRequest struct {
+	DryRun bool `json:"dryRun"`
+	Match  struct {
+		Class string `json:"class"`
+		Where struct {
+			Operator  string   `json:"operator"`
+			Path      []string `json:"path"`
+			ValueText []string `json:"valueTextArray"`
+		} `json:"where"`
+	} `json:"match"`
+}
+
+// fakeBatchDelete serves batch deletes with respond and records each
+// request body.
+type fakeBatchDelete struct {
+	mu       sync.Mutex
+	requests []batchDeleteRequest
+	respond  func(n int, req batchDeleteRequest) map[string]any
+}
+
+func newFakeBatchDelete(t *testing.T, respond func(n int, req batchDeleteRequest) map[string]any) (*Provider, *fakeBatchDelete) {
+	t.Helper()
+	fake := &fakeBatchDelete{respond: respond}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/meta":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"1.29.0"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/v1/batch/objects":
+			var req batchDeleteRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fake.mu.Lock()
+			n := len(fake.requests)
+			fake.requests = append(fake.requests, req)
+			fake.mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(fake.respond(n, req))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := weaviate.NewClient(weaviate.Config{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	return New(client, Config{Class: "TestClass", DeleteBatchSize: 2}), fake
+}
+
+// deleted reports every ID in req as matched and deleted.
+func deleted(req batchDeleteRequest) map[string]any {
+	n := len(req.Match.Where.ValueText)
+	return map[string]any{"results": map[string]any{"matches": n, "successful": n, "limit": 10000}}
+}
+
+func TestDeleteIDs_Chunks(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	p, fake := newFakeBatchDelete(t, func(_ int, req batchDeleteRequest) map[string]any {
+		return deleted(req)
+	})
+
+	res, err := p.DeleteIDs(context.Background(), ids, false)
+	if err != nil {
+		t.Fatalf("DeleteIDs failed: %v", err)
+	}
+	if res.Deleted != 5 || res.Matched != 5 {
+		t.Errorf("expected 5 matched and deleted, got %+v", res)
+	}
+	var sizes []int
+	for _, req := range fake.requests {
+		sizes = append(sizes, len(req.Match.Where.ValueText))
+		if req.Match.Class != "TestClass" || req.Match.Where.Operator != "ContainsAny" || req.Match.Where.Path[0] != "id" {
+			t.Errorf("unexpected match %+v", req.Match)
+		}
+	}
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Errorf("expected chunks of [2 2 1], got %v", sizes)
+	}
+	if fake.requests[2].Match.Where.ValueText[0] != ids[4].String() {
+		t.Error("expected the last chunk to carry the last ID")
+	}
+}
+
+func TestDeleteIDs_Failures(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	p, fake := newFakeBatchDelete(t, func(_ int, req batchDeleteRequest) map[string]any {
+		failed := ids[1].String()
+		if req.Match.Where.ValueText[1] != failed {
+			return deleted(req)
+		}
+		return map[string]any{"results": map[string]any{
+			"matches": 2, "successful": 1, "failed": 1, "limit": 10000,
+			"objects": []map[string]any{
+				{"id": req.Match.Where.ValueText[0], "status": "SUCCESS"},
+				{"id": failed, "status": "FAILED", "errors": map[string]any{"error": []map[string]any{{"message": "shard read-only"}}}},
+			},
+		}}
+	})
+
+	res, err := p.DeleteIDs(context.Background(), ids, false)
+	if err == nil || !strings.Contains(err.Error(), ids[1].String()) || !strings.Contains(err.Error(), "shard read-only") {
+		t.Fatalf("expected an error naming the failed ID, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "1 of 4 deletes failed") {
+		t.Errorf("expected the failure count in the error, got %v", err)
+	}
+	if res.Deleted != 3 || res.Failed != 1 || len(fake.requests) != 2 {
+		t.Errorf("expected later chunks to still run, got %+v over %d requests", res, len(fake.requests))
+	}
+	if err := p.DeleteBatch(context.Background(), ids[:2]); err == nil || len(fake.requests) != 3 {
+		t.Error("expected DeleteBatch to return the failure")
+	}
+}
+
+func TestDeleteIDs_DryRun(t *testing.T) {
+	p, fake := newFakeBatchDelete(t, func(_ int, req batchDeleteRequest) map[string]any {
+		return map[string]any{"results": map[string]any{"matches": 1, "limit": 10000}}
+	})
+	res, err := p.DeleteIDs(context.Background(), []uuid.UUID{uuid.New(), uuid.New()}, true)
+	if err != nil || res.Matched != 1 || res.Deleted != 0 {
+		t.Errorf("expected 1 match and nothing deleted, got %+v, %v", res, err)
+	}
+	if !fake.requests[0].DryRun {
+		t.Error("expected dryRun in the request")
+	}
+}
+
+func TestDeleteIDs_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p, fake := newFakeBatchDelete(t, func(_ int, req batchDeleteRequest) map[string]any {
+		cancel() // caller gives up after the first chunk
+		return deleted(req)
+	})
+
+	err := p.DeleteBatch(ctx, []uuid.UUID{uuid.New(), uuid.New(), uuid.New()})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if n := len(fake.requests); n != 1 {
+		t.Errorf("expected 1 batch delete before cancellation, got %d", n)
+	}
+}
+
+func TestDeleteWhere(t *testing.T) {
+	t.Run("repeats past the server cap", func(t *testing.T) {
+		p, fake := newFakeBatchDelete(t, func(n int, _ batchDeleteRequest) map[string]any {
+			if n == 0 {
+				return map[string]any{"results": map[string]any{"matches": 3, "successful": 2, "limit": 2}}
+			}
+			return map[string]any{"results": map[string]any{"matches": 1, "successful": 1, "limit": 2}}
+		})
+		res, err := p.DeleteWhere(context.Background(), mustBuilder(t).Where("Status").Eq("stale"), false)
+		if err != nil || res.Deleted != 3 {
+			t.Errorf("expected 3 deleted, got %+v, %v", res, err)
+		}
+		if len(fake.requests) != 2 {
+			t.Errorf("expected 2 requests, got %d", len(fake.requests))
+		}
+	})
+
+	t.Run("nil filter", func(t *testing.T) {
+		p, fake := newFakeBatchDelete(t, nil)
+		if _, err := p.DeleteWhere(context.Background(), nil, false); err == nil {
+			t.Error("expected a nil filter to be rejected")
+		}
+		if len(fake.requests) != 0 {
+			t.Error("expected no request for a nil filter")
+		}
+	})
+}
@@ -4,26 +4,29 @@ package weaviate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"net/http"
+	"strconv"
 
 	"github.com/go-openapi/strfmt"
 	"github.com/google/uuid"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
+	"github.com/weaviate/weaviate-go-client/v5/weaviate/fault"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/filters"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/graphql"
 	"github.com/weaviate/weaviate/entities/models"
 	"github.com/zoobzio/grub"
+	"github.com/zoobzio/grub/internal/shared"
 	"github.com/zoobzio/vecna"
 )
 
-// isNotFoundError checks if the error indicates a not found condition.
+// isNotFoundError reports whether err is the client's error for a 404
+// response. Errors that merely mention "not found" in their text, such as a
+// missing class, are not treated as a missing object.
 func isNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	errStr := err.Error()
-	return strings.Contains(errStr, "404") || strings.Contains(errStr, "not found")
+	var clientErr *fault.WeaviateClientError
+	return errors.As(err, &clientErr) && clientErr.StatusCode == http.StatusNotFound
 }
 
 // Config holds configuration for the Weaviate provider.
@@ -35,12 +38,31 @@ type Config struct {
 	// These must match the property names defined in your Weaviate schema.
 	// If empty, no metadata properties will be returned (only ID, vector, and score).
 	Properties []string
+
+	// TextProperty is the text property HybridSearch matches keywords
+	// against with BM25. It must be indexed as searchable in the schema. If
+	// empty, Weaviate searches every searchable text property of the class.
+	TextProperty string
+
+	// Metric is the distance the class's vector index was created with,
+	// used by EnsureCollection and reported by ScoreKind. Defaults to
+	// cosine. Weaviate returns distances for every metric: 1 - similarity
+	// for cosine, the negated dot product, and the squared Euclidean
+	// distance for l2-squared.
+	Metric grub.DistanceMetric
+
+	// DeleteBatchSize is the most IDs DeleteBatch and DeleteIDs send in one
+	// batch delete. Defaults to 10000, Weaviate's default
+	// QUERY_MAXIMUM_RESULTS; lower it to match a server configured with a
+	// smaller cap.
+	DeleteBatchSize int
 }
 
 // Provider implements grub.VectorProvider for Weaviate.
 type Provider struct {
-	client *weaviate.Client
-	config Config
+	client    *weaviate.Client
+	config    Config
+	orderByID bool
 }
 
 // New creates a Weaviate provider with the given client and config.
@@ -72,6 +94,27 @@ func (p *Provider) buildSearchFields() []graphql.Field {
 	return fields
 }
 
+// buildHybridFields constructs the GraphQL field list for hybrid queries,
+// which report a fused score in place of a distance.
+func (p *Provider) buildHybridFields() []graphql.Field {
+	fields := make([]graphql.Field, 0, len(p.config.Properties)+1)
+
+	for _, prop := range p.config.Properties {
+		fields = append(fields, graphql.Field{Name: prop})
+	}
+
+	fields = append(fields, graphql.Field{
+		Name: "_additional",
+		Fields: []graphql.Field{
+			{Name: "id"},
+			{Name: "vector"},
+			{Name: "score"},
+		},
+	})
+
+	return fields
+}
+
 // buildGetFields constructs the GraphQL field list for non-vector queries.
 // Omits distance which is only valid for near* queries.
 func (p *Provider) buildGetFields() []graphql.Field {
@@ -92,6 +135,45 @@ func (p *Provider) buildGetFields() []graphql.Field {
 	return fields
 }
 
+// Project returns a provider whose searches, Recommend, and Filter request
+// only the named properties instead of Config.Properties. It implements
+// grub.VectorProjector; the receiver is not modified.
+func (p *Provider) Project(fields []string) grub.VectorProvider {
+	config := p.config
+	config.Properties = fields
+	return &Provider{client: p.client, config: config, orderByID: p.orderByID}
+}
+
+// OrderByID returns a provider whose List and Filter sort by ascending ID,
+// so limits keep the lowest IDs and Filter's offset pages stay stable. It
+// implements grub.VectorIDOrderer; the receiver is not modified.
+func (p *Provider) OrderByID() grub.VectorProvider {
+	return &Provider{client: p.client, config: p.config, orderByID: true}
+}
+
+// idSort sorts Get queries by ascending object ID.
+var idSort = graphql.Sort{Path: []string{"_id"}, Order: graphql.Asc}
+
+// ScoreKind reports how near-vector search scores are measured for
+// Config.Metric. It implements grub.VectorScorer.
+func (p *Provider) ScoreKind() grub.ScoreKind {
+	switch metric := p.metric(); metric {
+	case grub.DistanceCosine, grub.DistanceInnerProduct:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreDistance}
+	case grub.DistanceL2:
+		return grub.ScoreKind{Metric: metric, Measure: grub.ScoreSquaredDistance}
+	}
+	return grub.ScoreKind{}
+}
+
+// metric returns Config.Metric, defaulting to cosine.
+func (p *Provider) metric() grub.DistanceMetric {
+	if p.config.Metric == "" {
+		return grub.DistanceCosine
+	}
+	return p.config.Metric
+}
+
 // Upsert stores or updates a vector with associated metadata.
 func (p *Provider) Upsert(ctx context.Context, id uuid.UUID, vector []float32, metadata []byte) error {
 	props, err := bytesToProperties(metadata)
@@ -162,7 +244,7 @@ func (p *Provider) Get(ctx context.Context, id uuid.UUID) ([]float32, *grub.Vect
 		if isNotFoundError(err) {
 			return nil, nil, grub.ErrNotFound
 		}
-		return nil, nil, err
+		return nil, nil, shared.CtxErr(ctx, err)
 	}
 
 	if len(objs) == 0 {
@@ -187,41 +269,31 @@ func (p *Provider) Get(ctx context.Context, id uuid.UUID) ([]float32, *grub.Vect
 
 // Delete removes a vector by ID.
 func (p *Provider) Delete(ctx context.Context, id uuid.UUID) error {
-	exists, err := p.Exists(ctx, id)
-	if err != nil {
-		return err
-	}
-	if !exists {
-		return grub.ErrNotFound
-	}
-
-	return p.client.Data().Deleter().
+	err := p.client.Data().Deleter().
 		WithClassName(p.config.Class).
 		WithID(id.String()).
 		Do(ctx)
+	if isNotFoundError(err) {
+		return grub.ErrNotFound
+	}
+	return err
 }
 
-// DeleteBatch removes multiple vectors by ID.
-// Non-existent IDs are silently ignored, but other errors are returned.
+// DeleteBatch removes multiple vectors by ID using chunked batch deletes;
+// see DeleteIDs. Non-existent IDs are silently ignored, but other errors,
+// including objects that fail to delete, are returned.
 func (p *Provider) DeleteBatch(ctx context.Context, ids []uuid.UUID) error {
-	if len(ids) == 0 {
-		return nil
-	}
-
-	for _, id := range ids {
-		err := p.client.Data().Deleter().
-			WithClassName(p.config.Class).
-			WithID(id.String()).
-			Do(ctx)
-		if err != nil && !isNotFoundError(err) {
-			return err
-		}
-	}
-	return nil
+	_, err := p.DeleteIDs(ctx, ids, false)
+	return err
 }
 
 // Search performs similarity search and returns the k nearest neighbors.
 func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter map[string]any) ([]grub.VectorResult, error) {
+	return p.SearchPage(ctx, vector, k, 0, filter)
+}
+
+// SearchPage performs similarity search, skipping the first offset results.
+func (p *Provider) SearchPage(ctx context.Context, vector []float32, k, offset int, filter map[string]any) ([]grub.VectorResult, error) {
 	nearVector := p.client.GraphQL().NearVectorArgBuilder().
 		WithVector(vector)
 
@@ -230,6 +302,9 @@ func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter m
 		WithNearVector(nearVector).
 		WithLimit(k).
 		WithFields(p.buildSearchFields()...)
+	if offset > 0 {
+		query = query.WithOffset(offset)
+	}
 
 	if len(filter) > 0 {
 		where := buildWhereFilter(filter)
@@ -238,7 +313,7 @@ func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter m
 
 	resp, err := query.Do(ctx)
 	if err != nil {
-		return nil, err
+		return nil, shared.CtxErr(ctx, err)
 	}
 
 	return parseSearchResults(resp, p.config.Class)
@@ -246,6 +321,12 @@ func (p *Provider) Search(ctx context.Context, vector []float32, k int, filter m
 
 // Query performs similarity search with vecna filter support.
 func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	return p.QueryPage(ctx, vector, k, 0, filter)
+}
+
+// QueryPage performs similarity search with vecna filter support, skipping
+// the first offset results.
+func (p *Provider) QueryPage(ctx context.Context, vector []float32, k, offset int, filter *vecna.Filter) ([]grub.VectorResult, error) {
 	nearVector := p.client.GraphQL().NearVectorArgBuilder().
 		WithVector(vector)
 
@@ -254,6 +335,9 @@ func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *v
 		WithNearVector(nearVector).
 		WithLimit(k).
 		WithFields(p.buildSearchFields()...)
+	if offset > 0 {
+		query = query.WithOffset(offset)
+	}
 
 	if filter != nil {
 		where, err := translateFilter(filter)
@@ -267,12 +351,94 @@ func (p *Provider) Query(ctx context.Context, vector []float32, k int, filter *v
 
 	resp, err := query.Do(ctx)
 	if err != nil {
-		return nil, err
+		return nil, shared.CtxErr(ctx, err)
 	}
 
 	return parseSearchResults(resp, p.config.Class)
 }
 
+// HybridSearch blends BM25 keyword relevance over Config.TextProperty with
+// vector similarity, weighted by alpha (1 is pure vector, 0 pure BM25).
+// Results carry Weaviate's fused score, highest first. A nil vector lets the
+// class vectorizer embed text; without one, use alpha 0.
+func (p *Provider) HybridSearch(ctx context.Context, text string, vector []float32, k int, alpha float32, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	hybrid := p.client.GraphQL().HybridArgumentBuilder().
+		WithQuery(text).
+		WithAlpha(alpha)
+	if vector != nil {
+		hybrid = hybrid.WithVector(vector)
+	}
+	if p.config.TextProperty != "" {
+		hybrid = hybrid.WithProperties([]string{p.config.TextProperty})
+	}
+
+	query := p.client.GraphQL().Get().
+		WithClassName(p.config.Class).
+		WithHybrid(hybrid).
+		WithLimit(k).
+		WithFields(p.buildHybridFields()...)
+
+	if filter != nil {
+		where, err := translateFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		if where != nil {
+			query = query.WithWhere(where)
+		}
+	}
+
+	resp, err := query.Do(ctx)
+	if err != nil {
+		return nil, shared.CtxErr(ctx, err)
+	}
+
+	return parseSearchResults(resp, p.config.Class)
+}
+
+// Recommend returns up to k vectors closest to the stored vector at ids[0],
+// using a nearObject search. Weaviate has no multi-object seed, so more than
+// one ID returns grub.ErrUnsupported and the Index falls back to averaging.
+// nearObject matches the seed itself, so one extra result is requested and
+// the seed dropped.
+func (p *Provider) Recommend(ctx context.Context, ids []uuid.UUID, k int, filter *vecna.Filter) ([]grub.VectorResult, error) {
+	if len(ids) != 1 {
+		return nil, grub.ErrUnsupported
+	}
+	nearObject := p.client.GraphQL().NearObjectArgBuilder().
+		WithID(ids[0].String())
+
+	query := p.client.GraphQL().Get().
+		WithClassName(p.config.Class).
+		WithNearObject(nearObject).
+		WithLimit(k + 1).
+		WithFields(p.buildSearchFields()...)
+
+	if filter != nil {
+		where, err := translateFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		if where != nil {
+			query = query.WithWhere(where)
+		}
+	}
+
+	resp, err := query.Do(ctx)
+	if err == nil {
+		var results []grub.VectorResult
+		if results, err = parseSearchResults(resp, p.config.Class); err == nil {
+			return results, nil
+		}
+	}
+	// A missing seed surfaces as a GraphQL error; report it as ErrNotFound
+	// when the seed is in fact absent.
+	if exists, existsErr := p.Exists(ctx, ids[0]); existsErr == nil && !exists {
+		return nil, grub.ErrNotFound
+	}
+	return nil, shared.CtxErr(ctx, err)
+}
+
 // Filter returns vectors matching the metadata filter without similarity search.
 // Uses GraphQL Get with Where filter. Paginates when limit=0.
 func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int) ([]grub.VectorResult, error) {
@@ -290,6 +456,9 @@ func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int)
 	offset := 0
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		fetchLimit := pageSize
 		if limit > 0 {
 			remaining := limit - len(allResults)
@@ -310,10 +479,13 @@ func (p *Provider) Filter(ctx context.Context, filter *vecna.Filter, limit int)
 		if where != nil {
 			query = query.WithWhere(where)
 		}
+		if p.orderByID {
+			query = query.WithSort(idSort)
+		}
 
 		resp, err := query.Do(ctx)
 		if err != nil {
-			return nil, err
+			return nil, shared.CtxErr(ctx, err)
 		}
 
 		batch, err := parseSearchResults(resp, p.config.Class)
@@ -356,10 +528,13 @@ func (p *Provider) List(ctx context.Context, limit int) ([]uuid.UUID, error) {
 				{Name: "id"},
 			},
 		})
+	if p.orderByID {
+		query = query.WithSort(idSort)
+	}
 
 	resp, err := query.Do(ctx)
 	if err != nil {
-		return nil, err
+		return nil, shared.CtxErr(ctx, err)
 	}
 
 	return parseIDs(resp, p.config.Class, limit)
@@ -437,7 +612,10 @@ func parseSearchResults(resp *models.GraphQLResponse, class string) ([]grub.Vect
 		if err != nil {
 			return nil, err
 		}
-		distance, _ := additional["distance"].(float64)
+		score, err := additionalScore(additional)
+		if err != nil {
+			return nil, err
+		}
 
 		var vector []float32
 		if vec, ok := additional["vector"].([]any); ok {
@@ -465,13 +643,31 @@ func parseSearchResults(resp *models.GraphQLResponse, class string) ([]grub.Vect
 			ID:       id,
 			Vector:   vector,
 			Metadata: metadata,
-			Score:    float32(distance),
+			Score:    score,
 		})
 	}
 
 	return results, nil
 }
 
+// additionalScore reads a result's score from its _additional fields: the
+// distance for near* queries, or the fused score for hybrid queries, which
+// Weaviate reports as a string.
+func additionalScore(additional map[string]any) (float32, error) {
+	switch score := additional["score"].(type) {
+	case string:
+		f, err := strconv.ParseFloat(score, 32)
+		if err != nil {
+			return 0, fmt.Errorf("weaviate: invalid score %q: %w", score, err)
+		}
+		return float32(f), nil
+	case float64:
+		return float32(score), nil
+	}
+	distance, _ := additional["distance"].(float64)
+	return float32(distance), nil
+}
+
 // parseIDs parses GraphQL response to ID slice.
 func parseIDs(resp *models.GraphQLResponse, class string, limit int) ([]uuid.UUID, error) {
 	if resp.Errors != nil && len(resp.Errors) > 0 {
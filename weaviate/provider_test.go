@@ -1,7 +1,19 @@
 package weaviate
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+
+	"github.com/google/uuid"
+	"github.com/weaviate/weaviate-go-client/v5/weaviate"
+	"github.com/zoobzio/grub"
 )
 
 func TestNew(t *testing.T) {
@@ -13,3 +25,266 @@ func TestNew(t *testing.T) {
 		t.Errorf("expected class 'TestClass', got %q", p.config.Class)
 	}
 }
+
+func TestProject(t *testing.T) {
+	p := New(nil, Config{Class: "TestClass", Properties: []string{"title", "body", "category"}})
+	projected, ok := p.Project([]string{"title"}).(*Provider)
+	if !ok {
+		t.Fatal("Project did not return a *Provider")
+	}
+	if got := projected.config.Properties; len(got) != 1 || got[0] != "title" {
+		t.Errorf("expected projected properties [title], got %v", got)
+	}
+	if projected.config.Class != "TestClass" {
+		t.Errorf("expected class preserved, got %q", projected.config.Class)
+	}
+	if len(p.config.Properties) != 3 {
+		t.Errorf("original provider modified: %v", p.config.Properties)
+	}
+	var names []string
+	for _, f := range projected.buildSearchFields() {
+		names = append(names, f.Name)
+	}
+	if strings.Join(names, ",") != "title,_additional" {
+		t.Errorf("expected search fields [title _additional], got %v", names)
+	}
+}
+
+func TestOrderByID(t *testing.T) {
+	id := uuid.New()
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/graphql":
+			var body struct {
+				Query string `json:"query"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			queries = append(queries, body.Query)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data":{"Get":{"Doc":[{"_additional":{"id":%q}}]}}}`, id)
+		case "/v1/meta":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"1.29.0"}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := weaviate.NewClient(weaviate.Config{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	p := New(client, Config{Class: "Doc"})
+	ordered, ok := p.OrderByID().(*Provider)
+	if !ok {
+		t.Fatal("OrderByID did not return a *Provider")
+	}
+	if p.orderByID {
+		t.Error("original provider modified")
+	}
+	if projected := ordered.Project([]string{"title"}).(*Provider); !projected.orderByID {
+		t.Error("Project dropped ID ordering")
+	}
+
+	ctx := context.Background()
+	if _, err := p.List(ctx, 10); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if _, err := ordered.List(ctx, 10); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if _, err := ordered.Filter(ctx, nil, 1); err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 queries, got %d", len(queries))
+	}
+	if strings.Contains(queries[0], "sort:") {
+		t.Errorf("expected unordered List without sort, got %s", queries[0])
+	}
+	for _, q := range queries[1:] {
+		if !strings.Contains(q, `sort:[{path:["_id"] order:asc}]`) {
+			t.Errorf("expected ascending _id sort, got %s", q)
+		}
+	}
+}
+
+func TestNotFoundMapping(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/meta":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"1.29.0"}`))
+		case strings.Contains(r.URL.Path, "/MissingClass/"):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"error":[{"message":"class MissingClass not found"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := weaviate.NewClient(weaviate.Config{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	p := New(client, Config{Class: "TestClass"})
+	if _, _, err := p.Get(ctx, uuid.New()); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("Get: expected ErrNotFound for a 404, got %v", err)
+	}
+	if err := p.Delete(ctx, uuid.New()); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("Delete: expected ErrNotFound for a 404, got %v", err)
+	}
+
+	missing := New(client, Config{Class: "MissingClass"})
+	if err := missing.Delete(ctx, uuid.New()); err == nil || errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("Delete: expected a missing class to surface as an error, got %v", err)
+	}
+}
+
+func TestHybridSearch(t *testing.T) {
+	id := uuid.New()
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/graphql":
+			var body struct {
+				Query string `json:"query"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			query = body.Query
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data":{"Get":{"Doc":[{"title":"grub","_additional":{"id":%q,"vector":[1,0],"score":"0.75"}}]}}}`, id)
+		case "/v1/meta":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"1.29.0"}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := weaviate.NewClient(weaviate.Config{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	p := New(client, Config{Class: "Doc", Properties: []string{"title"}, TextProperty: "body"})
+
+	results, err := p.HybridSearch(context.Background(), "storage", []float32{1, 0}, 5, 0.25, nil)
+	if err != nil {
+		t.Fatalf("HybridSearch failed: %v", err)
+	}
+	for _, want := range []string{`hybrid:{query: "storage"`, `alpha: 0.25`, `properties: ["body"]`, `score`} {
+		if !strings.Contains(query, want) {
+			t.Errorf("expected query to contain %q, got %s", want, query)
+		}
+	}
+	if len(results) != 1 || results[0].ID != id {
+		t.Fatalf("expected result %s, got %v", id, results)
+	}
+	if results[0].Score != 0.75 {
+		t.Errorf("expected score 0.75, got %v", results[0].Score)
+	}
+	if string(results[0].Metadata) != `{"title":"grub"}` {
+		t.Errorf("expected metadata, got %s", results[0].Metadata)
+	}
+}
+
+func TestAdditionalScore(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		additional map[string]any
+		want       float32
+		wantErr    bool
+	}{
+		{"distance", map[string]any{"distance": 0.5}, 0.5, false},
+		{"hybrid string score", map[string]any{"score": "0.8"}, 0.8, false},
+		{"numeric score", map[string]any{"score": 0.3}, 0.3, false},
+		{"invalid score", map[string]any{"score": "high"}, 0, true},
+		{"none", map[string]any{}, 0, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := additionalScore(tc.additional)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestScoreKind(t *testing.T) {
+	for metric, want := range map[grub.DistanceMetric]grub.ScoreKind{
+		"":                        {Metric: grub.DistanceCosine, Measure: grub.ScoreDistance},
+		grub.DistanceInnerProduct: {Metric: grub.DistanceInnerProduct, Measure: grub.ScoreDistance},
+		grub.DistanceL2:           {Metric: grub.DistanceL2, Measure: grub.ScoreSquaredDistance},
+		"hamming":                 {},
+	} {
+		if got := New(nil, Config{Class: "TestClass", Metric: metric}).ScoreKind(); got != want {
+			t.Errorf("%q: expected %+v, got %+v", metric, want, got)
+		}
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/graphql":
+			requests.Add(1)
+			objects := make([]string, 100)
+			for i := range objects {
+				objects[i] = fmt.Sprintf(`{"_additional":{"id":%q}}`, uuid.New())
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data":{"Get":{"Doc":[%s]}}}`, strings.Join(objects, ","))
+			// the caller gives up once the first page is served
+			cancel()
+		case "/v1/meta":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"1.29.0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := weaviate.NewClient(weaviate.Config{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	p := New(client, Config{Class: "Doc"})
+
+	if _, err := p.Filter(ctx, nil, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Filter: expected context.Canceled, got %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected Filter to stop after the first page, got %d requests", n)
+	}
+	if _, err := p.Search(ctx, []float32{1, 0}, 5, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Search: expected context.Canceled, got %v", err)
+	}
+	if _, _, err := p.Get(ctx, uuid.New()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Get: expected context.Canceled, got %v", err)
+	}
+}
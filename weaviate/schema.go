@@ -0,0 +1,111 @@
+package weaviate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/zoobzio/grub"
+)
+
+// dataTypes maps grub metadata kinds to Weaviate property data types.
+var dataTypes = map[grub.MetadataKind]string{
+	grub.MetadataString:  "text",
+	grub.MetadataInteger: "int",
+	grub.MetadataNumber:  "number",
+	grub.MetadataBool:    "boolean",
+	grub.MetadataTime:    "date",
+}
+
+// Properties returns the Weaviate class properties for schema, as derived
+// by grub.Index.Schema. Scalars and arrays of scalars map to text, int,
+// number, boolean, and date (with a [] suffix for arrays). Object and
+// custom-encoded fields are skipped: Weaviate needs their nested
+// properties spelled out.
+func Properties(schema grub.MetadataSchema) []*models.Property {
+	props := make([]*models.Property, 0, len(schema.Fields))
+	for _, f := range schema.Fields {
+		kind, suffix := f.Kind, ""
+		if kind == grub.MetadataArray {
+			kind, suffix = f.Elem, "[]"
+		}
+		dataType, ok := dataTypes[kind]
+		if !ok {
+			continue
+		}
+		props = append(props, &models.Property{Name: f.Name, DataType: []string{dataType + suffix}})
+	}
+	return props
+}
+
+// vectorDistances maps grub metrics to Weaviate vector index distances.
+var vectorDistances = map[grub.DistanceMetric]string{
+	grub.DistanceCosine:       "cosine",
+	grub.DistanceInnerProduct: "dot",
+	grub.DistanceL2:           "l2-squared",
+}
+
+// EnsureCollection creates the class if it does not exist, with no
+// vectorizer and an HNSW vector index using Config.Metric. Weaviate takes
+// the vector dimension from the first object stored, so none is given.
+// An existing class is left as is, even if its index differs; add
+// properties with EnsureProperties.
+func (p *Provider) EnsureCollection(ctx context.Context) error {
+	distance, ok := vectorDistances[p.metric()]
+	if !ok {
+		return fmt.Errorf("weaviate: unsupported metric %q", p.config.Metric)
+	}
+	exists, err := p.classExists(ctx)
+	if err != nil || exists {
+		return err
+	}
+	err = p.client.Schema().ClassCreator().WithClass(&models.Class{
+		Class:             p.config.Class,
+		Vectorizer:        "none",
+		VectorIndexType:   "hnsw",
+		VectorIndexConfig: map[string]any{"distance": distance},
+	}).Do(ctx)
+	if err != nil {
+		// Another caller may have created the class since the check.
+		if exists, _ := p.classExists(ctx); exists {
+			return nil
+		}
+		return fmt.Errorf("weaviate: creating class %q: %w", p.config.Class, err)
+	}
+	return nil
+}
+
+// classExists reports whether Config.Class is defined.
+func (p *Provider) classExists(ctx context.Context) (bool, error) {
+	return p.client.Schema().ClassExistenceChecker().WithClassName(p.config.Class).Do(ctx)
+}
+
+// EnsureProperties adds each property Properties derives from schema that
+// the class does not define yet. Properties already present are left as
+// they are, even when their data type differs. The class must exist.
+func (p *Provider) EnsureProperties(ctx context.Context, schema grub.MetadataSchema) error {
+	class, err := p.client.Schema().ClassGetter().WithClassName(p.config.Class).Do(ctx)
+	if err != nil {
+		if isNotFoundError(err) {
+			return fmt.Errorf("weaviate: class %q: %w", p.config.Class, grub.ErrNotFound)
+		}
+		return err
+	}
+	existing := make(map[string]bool, len(class.Properties))
+	for _, prop := range class.Properties {
+		existing[prop.Name] = true
+	}
+	for _, prop := range Properties(schema) {
+		if existing[prop.Name] {
+			continue
+		}
+		err := p.client.Schema().PropertyCreator().
+			WithClassName(p.config.Class).
+			WithProperty(prop).
+			Do(ctx)
+		if err != nil {
+			return fmt.Errorf("weaviate: adding property %q: %w", prop.Name, err)
+		}
+	}
+	return nil
+}
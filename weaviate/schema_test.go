@@ -0,0 +1,156 @@
+package weaviate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/weaviate/weaviate-go-client/v5/weaviate"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/zoobzio/grub"
+)
+
+// article is the metadata type whose derived schema feeds the class.
+type article struct {
+	Title     string            `json:"title"`
+	Views     int               `json:"views"`
+	Rating    float64           `json:"rating"`
+	Draft     bool              `json:"draft"`
+	Published time.Time         `json:"published"`
+	Tags      []string          `json:"tags"`
+	Extra     map[string]string `json:"extra"`
+}
+
+func TestProperties(t *testing.T) {
+	schema := grub.NewIndex[article](nil).Schema()
+	got := make(map[string]string)
+	for _, prop := range Properties(schema) {
+		got[prop.Name] = strings.Join(prop.DataType, ",")
+	}
+	want := map[string]string{
+		"title":     "text",
+		"views":     "int",
+		"rating":    "number",
+		"draft":     "boolean",
+		"published": "date",
+		"tags":      "text[]",
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	for name, dataType := range want {
+		if got[name] != dataType {
+			t.Errorf("%s: expected %s, got %q", name, dataType, got[name])
+		}
+	}
+}
+
+func TestEnsureProperties(t *testing.T) {
+	var created []models.Property
+	classExists := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/meta":
+			_, _ = w.Write([]byte(`{"version":"1.29.0"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/schema/Article":
+			if !classExists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"class":"Article","properties":[{"name":"title","dataType":["text"]}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/schema/Article/properties":
+			var prop models.Property
+			_ = json.NewDecoder(r.Body).Decode(&prop)
+			created = append(created, prop)
+			_ = json.NewEncoder(w).Encode(prop)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := weaviate.NewClient(weaviate.Config{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	p := New(client, Config{Class: "Article"})
+	index := grub.NewIndex[article](p, grub.WithMetadataSchema())
+
+	ctx := context.Background()
+	if err := p.EnsureProperties(ctx, index.Schema()); err != nil {
+		t.Fatalf("EnsureProperties failed: %v", err)
+	}
+	var names []string
+	for _, prop := range created {
+		names = append(names, prop.Name+":"+strings.Join(prop.DataType, ","))
+	}
+	if strings.Join(names, " ") != "views:int rating:number draft:boolean published:date tags:text[]" {
+		t.Errorf("unexpected properties created: %v", names)
+	}
+
+	classExists = false
+	if err := p.EnsureProperties(ctx, index.Schema()); !errors.Is(err, grub.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing class, got %v", err)
+	}
+}
+
+func TestEnsureCollection(t *testing.T) {
+	var classes []models.Class
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/meta":
+			_, _ = w.Write([]byte(`{"version":"1.29.0"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/schema/Article":
+			if len(classes) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(classes[0])
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/schema":
+			var class models.Class
+			_ = json.NewDecoder(r.Body).Decode(&class)
+			classes = append(classes, class)
+			_ = json.NewEncoder(w).Encode(class)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := weaviate.NewClient(weaviate.Config{
+		Host:   strings.TrimPrefix(srv.URL, "http://"),
+		Scheme: "http",
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	p := New(client, Config{Class: "Article", Metric: grub.DistanceInnerProduct})
+
+	ctx := context.Background()
+	for range 2 {
+		if err := p.EnsureCollection(ctx); err != nil {
+			t.Fatalf("EnsureCollection failed: %v", err)
+		}
+	}
+	if len(classes) != 1 {
+		t.Fatalf("expected the class to be created once, got %d", len(classes))
+	}
+	config, _ := classes[0].VectorIndexConfig.(map[string]any)
+	if classes[0].Vectorizer != "none" || config["distance"] != "dot" {
+		t.Errorf("expected no vectorizer and dot distance, got %q %v", classes[0].Vectorizer, classes[0].VectorIndexConfig)
+	}
+
+	if err := New(client, Config{Class: "Article", Metric: "manhattan"}).EnsureCollection(ctx); err == nil {
+		t.Error("expected an error for an unsupported metric")
+	}
+}